@@ -56,6 +56,7 @@ class AuthMiddleware {
       req.user = {
         id: decoded.sub,
         email: decoded.email,
+        walletId: decoded.wallet_id,
         roles: decoded.roles || [],
         permissions: decoded.permissions || []
       };
@@ -48,10 +48,20 @@ class ProxyMiddleware {
         // Add correlation ID for tracing
         proxyReq.setHeader('X-Correlation-ID', req.id);
         
-        // Add user context
+        // Add user context. Identity headers are only trusted when set here, so any copies
+        // sent by the client are dropped.
+        proxyReq.removeHeader('X-User-ID');
+        proxyReq.removeHeader('X-Wallet-ID');
+        proxyReq.removeHeader('X-User-Issuer');
         if (req.user) {
           proxyReq.setHeader('X-User-ID', req.user.id);
           proxyReq.setHeader('X-User-Roles', JSON.stringify(req.user.roles));
+          if (req.user.walletId) {
+            proxyReq.setHeader('X-Wallet-ID', req.user.walletId);
+          }
+          if (req.user.issuer) {
+            proxyReq.setHeader('X-User-Issuer', req.user.issuer);
+          }
         }
 
         // Log request
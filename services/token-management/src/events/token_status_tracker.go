@@ -0,0 +1,150 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/logging"
+	"echopay/token-management/src/models"
+)
+
+// TokenEventType identifies a change to a token that its owner is notified of
+type TokenEventType string
+
+const (
+	TokenEventFrozen      TokenEventType = "token_frozen"
+	TokenEventUnfrozen    TokenEventType = "token_unfrozen"
+	TokenEventTransferred TokenEventType = "token_transferred"
+	TokenEventDestroyed   TokenEventType = "token_destroyed"
+)
+
+// TokenEvent represents a real-time token ownership or status change. Transfers carry the
+// previous owner, so both sides of the transfer are notified.
+type TokenEvent struct {
+	Type            TokenEventType     `json:"type"`
+	TokenID         uuid.UUID          `json:"token_id"`
+	OwnerID         uuid.UUID          `json:"owner_id"`
+	PreviousOwnerID *uuid.UUID         `json:"previous_owner_id,omitempty"`
+	Status          models.TokenStatus `json:"status"`
+	Timestamp       time.Time          `json:"timestamp"`
+	Message         string             `json:"message,omitempty"`
+}
+
+// TokenSubscriber represents a client subscribed to token events
+type TokenSubscriber struct {
+	ID      uuid.UUID
+	Channel chan TokenEvent
+	Filter  TokenFilter
+}
+
+// TokenFilter defines criteria for filtering token events
+type TokenFilter struct {
+	OwnerIDs []uuid.UUID `json:"owner_ids,omitempty"`
+	TokenIDs []uuid.UUID `json:"token_ids,omitempty"`
+}
+
+// TokenStatusTracker manages real-time token event notifications
+type TokenStatusTracker struct {
+	subscribers map[uuid.UUID]*TokenSubscriber
+	mutex       sync.RWMutex
+	logger      *logging.Logger
+}
+
+// NewTokenStatusTracker creates a new token status tracker
+func NewTokenStatusTracker() *TokenStatusTracker {
+	return &TokenStatusTracker{
+		subscribers: make(map[uuid.UUID]*TokenSubscriber),
+		logger:      logging.NewLogger("token-status-tracker"),
+	}
+}
+
+// Subscribe subscribes to token events
+func (t *TokenStatusTracker) Subscribe(filter TokenFilter) *TokenSubscriber {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	subscriber := &TokenSubscriber{
+		ID:      uuid.New(),
+		Channel: make(chan TokenEvent, 100), // Buffered channel
+		Filter:  filter,
+	}
+
+	t.subscribers[subscriber.ID] = subscriber
+	t.logger.Debug("New token subscriber added", "subscriber_id", subscriber.ID)
+
+	return subscriber
+}
+
+// Unsubscribe removes a subscriber
+func (t *TokenStatusTracker) Unsubscribe(subscriberID uuid.UUID) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if subscriber, exists := t.subscribers[subscriberID]; exists {
+		close(subscriber.Channel)
+		delete(t.subscribers, subscriberID)
+		t.logger.Debug("Token subscriber removed", "subscriber_id", subscriberID)
+	}
+}
+
+// Publish sends a token event to all matching subscribers. A nil tracker publishes nothing.
+func (t *TokenStatusTracker) Publish(event TokenEvent) {
+	if t == nil {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, subscriber := range t.subscribers {
+		if matchesTokenFilter(event, subscriber.Filter) {
+			select {
+			case subscriber.Channel <- event:
+				// Successfully sent
+			default:
+				// Channel is full, skip this subscriber
+				t.logger.Warn("Token subscriber channel full, dropping event", "subscriber_id", subscriber.ID)
+			}
+		}
+	}
+
+	t.logger.Debug("Token event published", "token_id", event.TokenID, "type", event.Type)
+}
+
+// matchesTokenFilter checks if an event matches the subscriber's filter. An owner filter
+// matches both the new and previous owner of a transferred token.
+func matchesTokenFilter(event TokenEvent, filter TokenFilter) bool {
+	if len(filter.TokenIDs) > 0 && !containsID(filter.TokenIDs, event.TokenID) {
+		return false
+	}
+
+	if len(filter.OwnerIDs) > 0 {
+		previousOwnerMatches := event.PreviousOwnerID != nil && containsID(filter.OwnerIDs, *event.PreviousOwnerID)
+		if !containsID(filter.OwnerIDs, event.OwnerID) && !previousOwnerMatches {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSubscriberCount returns the number of active subscribers
+func (t *TokenStatusTracker) GetSubscriberCount() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return len(t.subscribers)
+}
@@ -0,0 +1,355 @@
+// Package grpcserver exposes the core token operations over gRPC for internal services. It
+// delegates to the same TokenService as the REST handlers.
+package grpcserver
+
+//go:generate protoc --proto_path=../../proto --go_out=tokenpb --go_opt=paths=source_relative --go-grpc_out=tokenpb --go-grpc_opt=paths=source_relative token_management.proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/grpcserver/tokenpb"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+	"echopay/token-management/src/service"
+)
+
+// Server implements the TokenManagement gRPC service
+type Server struct {
+	tokenpb.UnimplementedTokenManagementServer
+	tokenService *service.TokenService
+}
+
+// NewServer creates a gRPC server implementation backed by the token service
+func NewServer(tokenService *service.TokenService) *Server {
+	return &Server{tokenService: tokenService}
+}
+
+// Register creates a gRPC server with the TokenManagement service registered
+func Register(tokenService *service.TokenService, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
+	tokenpb.RegisterTokenManagementServer(grpcServer, NewServer(tokenService))
+	return grpcServer
+}
+
+// IssueTokens issues new tokens
+func (s *Server) IssueTokens(ctx context.Context, req *tokenpb.IssueTokensRequest) (*tokenpb.IssueTokensResponse, error) {
+	owner, err := parseUUID("owner", req.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.tokenService.IssueTokens(ctx, service.IssueTokenRequest{
+		CBDCType:     models.CBDCType(req.GetCbdcType()),
+		Denomination: req.GetDenomination(),
+		Owner:        owner,
+		Issuer:       req.GetIssuer(),
+		Series:       req.GetSeries(),
+		Quantity:     int(req.GetQuantity()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	tokens := make([]*tokenpb.Token, len(response.Tokens))
+	for i := range response.Tokens {
+		tokens[i] = toProtoToken(&response.Tokens[i])
+	}
+
+	return &tokenpb.IssueTokensResponse{
+		Tokens:   tokens,
+		Count:    int32(response.Count),
+		IssuedAt: timestamppb.New(response.IssuedAt),
+	}, nil
+}
+
+// GetToken retrieves a token by ID
+func (s *Server) GetToken(ctx context.Context, req *tokenpb.GetTokenRequest) (*tokenpb.Token, error) {
+	tokenID, err := parseUUID("token_id", req.GetTokenId())
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokenService.GetToken(ctx, tokenID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProtoToken(token), nil
+}
+
+// TransferToken transfers a token to a new owner
+func (s *Server) TransferToken(ctx context.Context, req *tokenpb.TransferTokenRequest) (*tokenpb.TransferTokenResponse, error) {
+	tokenID, err := parseUUID("token_id", req.GetTokenId())
+	if err != nil {
+		return nil, err
+	}
+	newOwner, err := parseUUID("new_owner", req.GetNewOwner())
+	if err != nil {
+		return nil, err
+	}
+	transactionID, err := parseUUID("transaction_id", req.GetTransactionId())
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing timestamp stays zero rather than becoming the Unix epoch
+	var timestamp time.Time
+	if req.GetTimestamp() != nil {
+		timestamp = req.GetTimestamp().AsTime()
+	}
+
+	response, err := s.tokenService.TransferToken(ctx, service.TransferTokenRequest{
+		TokenID:       tokenID,
+		NewOwner:      newOwner,
+		TransactionID: transactionID,
+		Nonce:         req.GetNonce(),
+		Timestamp:     timestamp,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	result := &tokenpb.TransferTokenResponse{
+		Token:           toProtoToken(&response.Token),
+		PreviousOwner:   response.PreviousOwner.String(),
+		TransferredAt:   timestamppb.New(response.TransferredAt),
+		RequiredSigners: int32(response.RequiredSigners),
+	}
+	if response.PendingTransferID != nil {
+		result.PendingTransferId = response.PendingTransferID.String()
+	}
+	return result, nil
+}
+
+// FreezeToken freezes a token
+func (s *Server) FreezeToken(ctx context.Context, req *tokenpb.FreezeTokenRequest) (*tokenpb.FreezeTokenResponse, error) {
+	tokenID, err := parseUUID("token_id", req.GetTokenId())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.tokenService.FreezeToken(ctx, service.FreezeTokenRequest{
+		TokenID:  tokenID,
+		Reason:   service.FreezeReason(req.GetReason()),
+		Note:     req.GetNote(),
+		Duration: req.GetDuration(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &tokenpb.FreezeTokenResponse{
+		Token:       toProtoToken(&response.Token),
+		FrozenAt:    timestamppb.New(response.FrozenAt),
+		FrozenUntil: toProtoTimestamp(response.FrozenUntil),
+		Reason:      string(response.Reason),
+		Note:        response.Note,
+	}, nil
+}
+
+// UnfreezeToken unfreezes a token
+func (s *Server) UnfreezeToken(ctx context.Context, req *tokenpb.UnfreezeTokenRequest) (*tokenpb.UnfreezeTokenResponse, error) {
+	tokenID, err := parseUUID("token_id", req.GetTokenId())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.tokenService.UnfreezeToken(ctx, service.UnfreezeTokenRequest{
+		TokenID: tokenID,
+		Reason:  service.FreezeReason(req.GetReason()),
+		Note:    req.GetNote(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &tokenpb.UnfreezeTokenResponse{
+		Token:      toProtoToken(&response.Token),
+		UnfrozenAt: timestamppb.New(response.UnfrozenAt),
+		Reason:     string(response.Reason),
+		Note:       response.Note,
+	}, nil
+}
+
+// BulkUpdateStatus updates the status of multiple tokens
+func (s *Server) BulkUpdateStatus(ctx context.Context, req *tokenpb.BulkUpdateStatusRequest) (*tokenpb.BulkUpdateStatusResponse, error) {
+	tokenIDs := make([]uuid.UUID, len(req.GetTokenIds()))
+	for i, id := range req.GetTokenIds() {
+		tokenID, err := parseUUID("token_ids", id)
+		if err != nil {
+			return nil, err
+		}
+		tokenIDs[i] = tokenID
+	}
+
+	response, err := s.tokenService.BulkUpdateTokenStatus(ctx, service.BulkStatusUpdateRequest{
+		TokenIDs:  tokenIDs,
+		NewStatus: models.TokenStatus(req.GetNewStatus()),
+		Reason:    service.FreezeReason(req.GetReason()),
+		Note:      req.GetNote(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &tokenpb.BulkUpdateStatusResponse{
+		UpdatedCount: int32(response.UpdatedCount),
+		NewStatus:    string(response.NewStatus),
+		UpdatedAt:    timestamppb.New(response.UpdatedAt),
+		Reason:       string(response.Reason),
+	}, nil
+}
+
+// GetAuditTrail retrieves a token's audit trail
+func (s *Server) GetAuditTrail(ctx context.Context, req *tokenpb.GetAuditTrailRequest) (*tokenpb.GetAuditTrailResponse, error) {
+	tokenID, err := parseUUID("token_id", req.GetTokenId())
+	if err != nil {
+		return nil, err
+	}
+
+	auditTrail, err := s.tokenService.GetTokenAuditTrail(ctx, tokenID, false)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	entries := make([]*tokenpb.AuditEntry, len(auditTrail))
+	for i, entry := range auditTrail {
+		converted, err := toProtoAuditEntry(entry)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode audit entry %s: %v", entry.ID, err)
+		}
+		entries[i] = converted
+	}
+
+	return &tokenpb.GetAuditTrailResponse{Entries: entries}, nil
+}
+
+// grpcCodes maps EchoPay error codes to gRPC status codes. Unlisted codes map to Internal.
+var grpcCodes = map[string]codes.Code{
+	errors.ErrTokenNotFound:          codes.NotFound,
+	errors.ErrValidation:             codes.InvalidArgument,
+	errors.ErrInvalidTokenState:      codes.FailedPrecondition,
+	errors.ErrTokenFrozen:            codes.FailedPrecondition,
+	errors.ErrNotYetFinal:            codes.FailedPrecondition,
+	errors.ErrQuotaExceeded:          codes.ResourceExhausted,
+	errors.ErrVelocityExceeded:       codes.ResourceExhausted,
+	errors.ErrConcurrentModification: codes.Aborted,
+	errors.ErrReplayDetected:         codes.AlreadyExists,
+	errors.ErrDuplicateToken:         codes.AlreadyExists,
+	errors.ErrAuthenticationFailed:   codes.Unauthenticated,
+	errors.ErrAuthorizationFailed:    codes.PermissionDenied,
+	errors.ErrSanctionsBlocked:       codes.PermissionDenied,
+	errors.ErrRateLimitExceeded:      codes.ResourceExhausted,
+	errors.ErrServiceUnavailable:     codes.Unavailable,
+	errors.ErrDatabaseConnection:     codes.Unavailable,
+}
+
+// toStatus converts a service error to a gRPC status. EchoPay errors keep their code in an
+// ErrorInfo detail so clients can branch on it as they do on the REST "code" field.
+func toStatus(err error) error {
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code, ok := grpcCodes[echoPayErr.Code]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st, detailErr := status.New(code, echoPayErr.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: echoPayErr.Code,
+		Domain: echoPayErr.Service,
+	})
+	if detailErr != nil {
+		return status.Error(code, echoPayErr.Message)
+	}
+	return st.Err()
+}
+
+// EchoPayCode returns the EchoPay error code carried by a gRPC error, if any
+func EchoPayCode(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %q is not a UUID", field, value)
+	}
+	return id, nil
+}
+
+func toProtoTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toProtoToken(token *models.Token) *tokenpb.Token {
+	history := make([]string, len(token.TransactionHistory))
+	for i, id := range token.TransactionHistory {
+		history[i] = id.String()
+	}
+
+	return &tokenpb.Token{
+		TokenId:            token.TokenID.String(),
+		CbdcType:           string(token.CBDCType),
+		Denomination:       token.Denomination,
+		CurrentOwner:       token.CurrentOwner.String(),
+		Status:             string(token.Status),
+		IssueTimestamp:     timestamppb.New(token.IssueTimestamp),
+		TransactionHistory: history,
+		Issuer:             token.Metadata.Issuer,
+		Series:             token.Metadata.Series,
+		CreatedAt:          timestamppb.New(token.CreatedAt),
+		UpdatedAt:          timestamppb.New(token.UpdatedAt),
+		Version:            token.Version,
+	}
+}
+
+func toProtoAuditEntry(entry repository.TokenAuditEntry) (*tokenpb.AuditEntry, error) {
+	result := &tokenpb.AuditEntry{
+		Id:           entry.ID.String(),
+		TokenId:      entry.TokenID.String(),
+		Operation:    entry.Operation,
+		OldStatus:    string(entry.OldStatus),
+		NewStatus:    string(entry.NewStatus),
+		Sequence:     entry.Sequence,
+		PreviousHash: entry.PreviousHash,
+		EntryHash:    entry.EntryHash,
+	}
+	if entry.OldOwner != uuid.Nil {
+		result.OldOwner = entry.OldOwner.String()
+	}
+	if entry.NewOwner != uuid.Nil {
+		result.NewOwner = entry.NewOwner.String()
+	}
+	if entry.Timestamp.Valid {
+		result.Timestamp = timestamppb.New(entry.Timestamp.Time)
+	}
+	if len(entry.Metadata) > 0 {
+		metadata, err := structpb.NewStruct(entry.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		result.Metadata = metadata
+	}
+	return result, nil
+}
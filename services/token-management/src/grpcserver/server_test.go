@@ -0,0 +1,224 @@
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/grpcserver/tokenpb"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+	"echopay/token-management/src/service"
+)
+
+// memoryTokenRepository keeps tokens in memory for the repository calls made by issuance,
+// lookup and transfer. Any other call panics through the nil embedded interface.
+type memoryTokenRepository struct {
+	repository.TokenRepository
+	mu     sync.Mutex
+	tokens map[uuid.UUID]models.Token
+	nonces map[string]bool
+}
+
+func newMemoryTokenRepository() *memoryTokenRepository {
+	return &memoryTokenRepository{tokens: make(map[uuid.UUID]models.Token), nonces: make(map[string]bool)}
+}
+
+func (r *memoryTokenRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.TokenID] = *token
+	return nil
+}
+
+func (r *memoryTokenRepository) GetByID(ctx context.Context, tokenID uuid.UUID) (*models.Token, error) {
+	return r.GetByIDWithTx(ctx, nil, tokenID)
+}
+
+func (r *memoryTokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*models.Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenID]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (r *memoryTokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	return r.CreateWithTx(ctx, tx, token)
+}
+
+func (r *memoryTokenRepository) GetIssuerQuotaForUpdateWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType) (*repository.IssuerQuota, error) {
+	return nil, nil
+}
+
+func (r *memoryTokenRepository) SaveMerkleBatchWithTx(ctx context.Context, tx *sql.Tx, batchID uuid.UUID, root string, proofs []repository.TokenMerkleProof) error {
+	return nil
+}
+
+func (r *memoryTokenRepository) GetRequiredSignersWithTx(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryTokenRepository) RecordTransferNonceWithTx(ctx context.Context, tx *sql.Tx, ownerID uuid.UUID, nonce string, now, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := ownerID.String() + ":" + nonce
+	if r.nonces[key] {
+		return false, nil
+	}
+	r.nonces[key] = true
+	return true, nil
+}
+
+// passthroughDB runs transaction bodies without a database
+type passthroughDB struct{}
+
+func (passthroughDB) Transaction(fn func(*sql.Tx) error) error {
+	return fn(nil)
+}
+
+// newTestClient starts the gRPC server on an in-memory listener and returns a connected client
+func newTestClient(t *testing.T) tokenpb.TokenManagementClient {
+	return newTestClientFor(t, service.NewTokenServiceWithDeps(newMemoryTokenRepository(), passthroughDB{}))
+}
+
+// newTestClientFor serves tokenService on an in-memory listener and returns a connected client
+func newTestClientFor(t *testing.T, tokenService *service.TokenService) tokenpb.TokenManagementClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := Register(tokenService)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return tokenpb.NewTokenManagementClient(conn)
+}
+
+func TestServer_IssueAndTransferRoundTrip(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	owner := uuid.New()
+	newOwner := uuid.New()
+
+	issued, err := client.IssueTokens(ctx, &tokenpb.IssueTokensRequest{
+		CbdcType:     string(models.CBDCTypeUSD),
+		Denomination: 50.0,
+		Owner:        owner.String(),
+		Issuer:       "FED",
+		Series:       "2025-A",
+		Quantity:     2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), issued.Count)
+	require.Len(t, issued.Tokens, 2)
+	assert.Equal(t, owner.String(), issued.Tokens[0].CurrentOwner)
+	assert.Equal(t, "FED", issued.Tokens[0].Issuer)
+
+	transferred, err := client.TransferToken(ctx, &tokenpb.TransferTokenRequest{
+		TokenId:       issued.Tokens[0].TokenId,
+		NewOwner:      newOwner.String(),
+		TransactionId: uuid.New().String(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, owner.String(), transferred.PreviousOwner)
+	assert.Equal(t, newOwner.String(), transferred.Token.CurrentOwner)
+	assert.Empty(t, transferred.PendingTransferId)
+
+	token, err := client.GetToken(ctx, &tokenpb.GetTokenRequest{TokenId: issued.Tokens[0].TokenId})
+	require.NoError(t, err)
+	assert.Equal(t, newOwner.String(), token.CurrentOwner)
+}
+
+func TestServer_TransferTokenWithRequiredNonce(t *testing.T) {
+	tokenService := service.NewTokenServiceWithDeps(newMemoryTokenRepository(), passthroughDB{})
+	tokenService.SetReplayGuard(service.NewReplayGuard(config.ReplayProtectionConfig{RequireNonce: true}))
+	client := newTestClientFor(t, tokenService)
+	ctx := context.Background()
+
+	issued, err := client.IssueTokens(ctx, &tokenpb.IssueTokensRequest{
+		CbdcType:     string(models.CBDCTypeUSD),
+		Denomination: 50.0,
+		Owner:        uuid.New().String(),
+		Issuer:       "FED",
+		Series:       "2025-A",
+		Quantity:     1,
+	})
+	require.NoError(t, err)
+
+	request := &tokenpb.TransferTokenRequest{
+		TokenId:       issued.Tokens[0].TokenId,
+		NewOwner:      uuid.New().String(),
+		TransactionId: uuid.New().String(),
+	}
+
+	// Without a nonce the transfer is rejected
+	_, err = client.TransferToken(ctx, request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	request.Nonce = uuid.NewString()
+	request.Timestamp = timestamppb.Now()
+	transferred, err := client.TransferToken(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, request.NewOwner, transferred.Token.CurrentOwner)
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	t.Run("unknown token maps to NotFound", func(t *testing.T) {
+		_, err := client.GetToken(ctx, &tokenpb.GetTokenRequest{TokenId: uuid.New().String()})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, errors.ErrTokenNotFound, EchoPayCode(err))
+	})
+
+	t.Run("invalid issuance maps to InvalidArgument", func(t *testing.T) {
+		_, err := client.IssueTokens(ctx, &tokenpb.IssueTokensRequest{
+			CbdcType:     "XYZ-CBDC",
+			Denomination: 50.0,
+			Owner:        uuid.New().String(),
+			Issuer:       "FED",
+			Series:       "2025-A",
+			Quantity:     1,
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, errors.ErrValidation, EchoPayCode(err))
+	})
+
+	t.Run("malformed UUID maps to InvalidArgument", func(t *testing.T) {
+		_, err := client.TransferToken(ctx, &tokenpb.TransferTokenRequest{
+			TokenId:       "not-a-uuid",
+			NewOwner:      uuid.New().String(),
+			TransactionId: uuid.New().String(),
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Empty(t, EchoPayCode(err))
+	})
+}
@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: token_management.proto
+
+package tokenpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TokenManagement_IssueTokens_FullMethodName      = "/echopay.tokenmanagement.v1.TokenManagement/IssueTokens"
+	TokenManagement_GetToken_FullMethodName         = "/echopay.tokenmanagement.v1.TokenManagement/GetToken"
+	TokenManagement_TransferToken_FullMethodName    = "/echopay.tokenmanagement.v1.TokenManagement/TransferToken"
+	TokenManagement_FreezeToken_FullMethodName      = "/echopay.tokenmanagement.v1.TokenManagement/FreezeToken"
+	TokenManagement_UnfreezeToken_FullMethodName    = "/echopay.tokenmanagement.v1.TokenManagement/UnfreezeToken"
+	TokenManagement_BulkUpdateStatus_FullMethodName = "/echopay.tokenmanagement.v1.TokenManagement/BulkUpdateStatus"
+	TokenManagement_GetAuditTrail_FullMethodName    = "/echopay.tokenmanagement.v1.TokenManagement/GetAuditTrail"
+)
+
+// TokenManagementClient is the client API for TokenManagement service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TokenManagementClient interface {
+	IssueTokens(ctx context.Context, in *IssueTokensRequest, opts ...grpc.CallOption) (*IssueTokensResponse, error)
+	GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*Token, error)
+	TransferToken(ctx context.Context, in *TransferTokenRequest, opts ...grpc.CallOption) (*TransferTokenResponse, error)
+	FreezeToken(ctx context.Context, in *FreezeTokenRequest, opts ...grpc.CallOption) (*FreezeTokenResponse, error)
+	UnfreezeToken(ctx context.Context, in *UnfreezeTokenRequest, opts ...grpc.CallOption) (*UnfreezeTokenResponse, error)
+	BulkUpdateStatus(ctx context.Context, in *BulkUpdateStatusRequest, opts ...grpc.CallOption) (*BulkUpdateStatusResponse, error)
+	GetAuditTrail(ctx context.Context, in *GetAuditTrailRequest, opts ...grpc.CallOption) (*GetAuditTrailResponse, error)
+}
+
+type tokenManagementClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTokenManagementClient(cc grpc.ClientConnInterface) TokenManagementClient {
+	return &tokenManagementClient{cc}
+}
+
+func (c *tokenManagementClient) IssueTokens(ctx context.Context, in *IssueTokensRequest, opts ...grpc.CallOption) (*IssueTokensResponse, error) {
+	out := new(IssueTokensResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_IssueTokens_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*Token, error) {
+	out := new(Token)
+	err := c.cc.Invoke(ctx, TokenManagement_GetToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) TransferToken(ctx context.Context, in *TransferTokenRequest, opts ...grpc.CallOption) (*TransferTokenResponse, error) {
+	out := new(TransferTokenResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_TransferToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) FreezeToken(ctx context.Context, in *FreezeTokenRequest, opts ...grpc.CallOption) (*FreezeTokenResponse, error) {
+	out := new(FreezeTokenResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_FreezeToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) UnfreezeToken(ctx context.Context, in *UnfreezeTokenRequest, opts ...grpc.CallOption) (*UnfreezeTokenResponse, error) {
+	out := new(UnfreezeTokenResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_UnfreezeToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) BulkUpdateStatus(ctx context.Context, in *BulkUpdateStatusRequest, opts ...grpc.CallOption) (*BulkUpdateStatusResponse, error) {
+	out := new(BulkUpdateStatusResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_BulkUpdateStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenManagementClient) GetAuditTrail(ctx context.Context, in *GetAuditTrailRequest, opts ...grpc.CallOption) (*GetAuditTrailResponse, error) {
+	out := new(GetAuditTrailResponse)
+	err := c.cc.Invoke(ctx, TokenManagement_GetAuditTrail_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenManagementServer is the server API for TokenManagement service.
+// All implementations must embed UnimplementedTokenManagementServer
+// for forward compatibility
+type TokenManagementServer interface {
+	IssueTokens(context.Context, *IssueTokensRequest) (*IssueTokensResponse, error)
+	GetToken(context.Context, *GetTokenRequest) (*Token, error)
+	TransferToken(context.Context, *TransferTokenRequest) (*TransferTokenResponse, error)
+	FreezeToken(context.Context, *FreezeTokenRequest) (*FreezeTokenResponse, error)
+	UnfreezeToken(context.Context, *UnfreezeTokenRequest) (*UnfreezeTokenResponse, error)
+	BulkUpdateStatus(context.Context, *BulkUpdateStatusRequest) (*BulkUpdateStatusResponse, error)
+	GetAuditTrail(context.Context, *GetAuditTrailRequest) (*GetAuditTrailResponse, error)
+	mustEmbedUnimplementedTokenManagementServer()
+}
+
+// UnimplementedTokenManagementServer must be embedded to have forward compatible implementations.
+type UnimplementedTokenManagementServer struct {
+}
+
+func (UnimplementedTokenManagementServer) IssueTokens(context.Context, *IssueTokensRequest) (*IssueTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueTokens not implemented")
+}
+func (UnimplementedTokenManagementServer) GetToken(context.Context, *GetTokenRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetToken not implemented")
+}
+func (UnimplementedTokenManagementServer) TransferToken(context.Context, *TransferTokenRequest) (*TransferTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferToken not implemented")
+}
+func (UnimplementedTokenManagementServer) FreezeToken(context.Context, *FreezeTokenRequest) (*FreezeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeToken not implemented")
+}
+func (UnimplementedTokenManagementServer) UnfreezeToken(context.Context, *UnfreezeTokenRequest) (*UnfreezeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeToken not implemented")
+}
+func (UnimplementedTokenManagementServer) BulkUpdateStatus(context.Context, *BulkUpdateStatusRequest) (*BulkUpdateStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpdateStatus not implemented")
+}
+func (UnimplementedTokenManagementServer) GetAuditTrail(context.Context, *GetAuditTrailRequest) (*GetAuditTrailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditTrail not implemented")
+}
+func (UnimplementedTokenManagementServer) mustEmbedUnimplementedTokenManagementServer() {}
+
+// UnsafeTokenManagementServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TokenManagementServer will
+// result in compilation errors.
+type UnsafeTokenManagementServer interface {
+	mustEmbedUnimplementedTokenManagementServer()
+}
+
+func RegisterTokenManagementServer(s grpc.ServiceRegistrar, srv TokenManagementServer) {
+	s.RegisterService(&TokenManagement_ServiceDesc, srv)
+}
+
+func _TokenManagement_IssueTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).IssueTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_IssueTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).IssueTokens(ctx, req.(*IssueTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_GetToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).GetToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_GetToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).GetToken(ctx, req.(*GetTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_TransferToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).TransferToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_TransferToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).TransferToken(ctx, req.(*TransferTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_FreezeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).FreezeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_FreezeToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).FreezeToken(ctx, req.(*FreezeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_UnfreezeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).UnfreezeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_UnfreezeToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).UnfreezeToken(ctx, req.(*UnfreezeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_BulkUpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkUpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).BulkUpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_BulkUpdateStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).BulkUpdateStatus(ctx, req.(*BulkUpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenManagement_GetAuditTrail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuditTrailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenManagementServer).GetAuditTrail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenManagement_GetAuditTrail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenManagementServer).GetAuditTrail(ctx, req.(*GetAuditTrailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TokenManagement_ServiceDesc is the grpc.ServiceDesc for TokenManagement service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TokenManagement_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "echopay.tokenmanagement.v1.TokenManagement",
+	HandlerType: (*TokenManagementServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IssueTokens",
+			Handler:    _TokenManagement_IssueTokens_Handler,
+		},
+		{
+			MethodName: "GetToken",
+			Handler:    _TokenManagement_GetToken_Handler,
+		},
+		{
+			MethodName: "TransferToken",
+			Handler:    _TokenManagement_TransferToken_Handler,
+		},
+		{
+			MethodName: "FreezeToken",
+			Handler:    _TokenManagement_FreezeToken_Handler,
+		},
+		{
+			MethodName: "UnfreezeToken",
+			Handler:    _TokenManagement_UnfreezeToken_Handler,
+		},
+		{
+			MethodName: "BulkUpdateStatus",
+			Handler:    _TokenManagement_BulkUpdateStatus_Handler,
+		},
+		{
+			MethodName: "GetAuditTrail",
+			Handler:    _TokenManagement_GetAuditTrail_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "token_management.proto",
+}
@@ -1,13 +1,21 @@
 package handler
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	
+	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	sharedhttp "echopay/shared/libraries/http"
 	"echopay/shared/libraries/logging"
 	"echopay/token-management/src/models"
 	"echopay/token-management/src/service"
@@ -27,13 +35,61 @@ func NewTokenHandler(tokenService *service.TokenService, logger *logging.Logger)
 	}
 }
 
+// log returns the handler's logger carrying the request's correlation ID
+func (h *TokenHandler) log(c *gin.Context) *logging.Logger {
+	return h.logger.WithContext(c.Request.Context())
+}
+
 
 
 // IssueTokens handles token issuance requests
 func (h *TokenHandler) IssueTokens(c *gin.Context) {
+	var req service.IssueTokenRequest
+	if !sharedhttp.BindJSON(c, &req, h.cbdcTypeCheck(&req.CBDCType)) {
+		return
+	}
+
+	response, err := h.tokenService.IssueTokens(c.Request.Context(), req)
+	if err != nil {
+		h.log(c).Error("Failed to issue tokens", "error", err, "request", req)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrQuotaExceeded {
+				statusCode = http.StatusUnprocessableEntity
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+				"details": tokenErr.Details,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to issue tokens")
+		return
+	}
+
+	h.log(c).Info("Tokens issued successfully", "count", response.Count, "owner", req.Owner)
+	c.JSON(http.StatusCreated, response)
+}
+
+// cbdcTypeCheck rejects a bound CBDC type the service does not support
+func (h *TokenHandler) cbdcTypeCheck(cbdcType *models.CBDCType) sharedhttp.FieldCheck {
+	return func() []sharedhttp.FieldError {
+		if !h.tokenService.SupportsCBDCType(*cbdcType) {
+			return []sharedhttp.FieldError{{Field: "cbdc_type", Message: fmt.Sprintf("invalid CBDC type: %s", *cbdcType)}}
+		}
+		return nil
+	}
+}
+
+// PreviewIssue validates an issuance and returns the tokens it would mint without minting them
+func (h *TokenHandler) PreviewIssue(c *gin.Context) {
 	var req service.IssueTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid issue tokens request", "error", err)
+		h.log(c).Error("Invalid issuance preview request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -41,25 +97,64 @@ func (h *TokenHandler) IssueTokens(c *gin.Context) {
 		return
 	}
 
-	response, err := h.tokenService.IssueTokens(c.Request.Context(), req)
+	preview, err := h.tokenService.PreviewIssue(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to issue tokens", "error", err, "request", req)
-		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			c.JSON(http.StatusBadRequest, gin.H{
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrQuotaExceeded {
+				statusCode = http.StatusUnprocessableEntity
+			}
+			
+			c.JSON(statusCode, gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
+				"details": tokenErr.Details,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to issue tokens",
+		internalError(c, err, "Failed to preview issuance")
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// IssueBatch handles issuance of mixed-denomination token batches
+func (h *TokenHandler) IssueBatch(c *gin.Context) {
+	var req service.BatchIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid batch issue request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Tokens issued successfully", "count", response.Count, "owner", req.Owner)
+	response, err := h.tokenService.IssueBatch(c.Request.Context(), req)
+	if err != nil {
+		h.log(c).Error("Failed to issue token batch", "error", err, "issuer", req.Issuer, "series", req.Series)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrQuotaExceeded {
+				statusCode = http.StatusUnprocessableEntity
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+				"details": tokenErr.Details,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to issue token batch")
+		return
+	}
+
+	h.log(c).Info("Token batch issued successfully", "count", response.Count, "lines", len(req.Lines), "owner", req.Owner)
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -76,7 +171,7 @@ func (h *TokenHandler) GetToken(c *gin.Context) {
 
 	token, err := h.tokenService.GetToken(c.Request.Context(), tokenID)
 	if err != nil {
-		h.logger.Error("Failed to get token", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to get token", "error", err, "token_id", tokenID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
 			if tokenErr.Code == errors.ErrTokenNotFound {
@@ -86,16 +181,14 @@ func (h *TokenHandler) GetToken(c *gin.Context) {
 				return
 			}
 			
-			c.JSON(http.StatusBadRequest, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve token",
-		})
+		internalError(c, err, "Failed to retrieve token")
 		return
 	}
 
@@ -115,7 +208,7 @@ func (h *TokenHandler) TransferToken(c *gin.Context) {
 
 	var req service.TransferTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid transfer token request", "error", err)
+		h.log(c).Error("Invalid transfer token request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -128,14 +221,18 @@ func (h *TokenHandler) TransferToken(c *gin.Context) {
 
 	response, err := h.tokenService.TransferToken(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to transfer token", "error", err, "request", req)
+		h.log(c).Error("Failed to transfer token", "error", err, "request", req)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			statusCode := http.StatusBadRequest
+			statusCode := errorStatus(tokenErr)
 			if tokenErr.Code == errors.ErrTokenNotFound {
 				statusCode = http.StatusNotFound
-			} else if tokenErr.Code == errors.ErrTokenFrozen {
+			} else if tokenErr.Code == errors.ErrTokenFrozen || tokenErr.Code == errors.ErrConcurrentModification || tokenErr.Code == errors.ErrReplayDetected {
 				statusCode = http.StatusConflict
+			} else if tokenErr.Code == errors.ErrSanctionsBlocked {
+				statusCode = http.StatusUnavailableForLegalReasons
+			} else if tokenErr.Code == errors.ErrVelocityExceeded {
+				statusCode = http.StatusTooManyRequests
 			}
 			
 			c.JSON(statusCode, gin.H{
@@ -145,17 +242,139 @@ func (h *TokenHandler) TransferToken(c *gin.Context) {
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to transfer token",
+		internalError(c, err, "Failed to transfer token")
+		return
+	}
+
+	if response.PendingTransferID != nil {
+		h.log(c).Info("Token transfer awaiting co-signer approval", "token_id", tokenID, "pending_transfer_id", *response.PendingTransferID)
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	h.log(c).Info("Token transferred successfully", "token_id", tokenID, "new_owner", req.NewOwner)
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveTransfer handles co-signer approval of pending transfers. The signer is the
+// authenticated caller; a signer_id in the body must match it.
+func (h *TokenHandler) ApproveTransfer(c *gin.Context) {
+	pendingIDStr := c.Param("id")
+	pendingID, err := uuid.Parse(pendingIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid pending transfer ID format",
+		})
+		return
+	}
+
+	var req struct {
+		SignerID uuid.UUID `json:"signer_id"`
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.log(c).Error("Invalid approve transfer request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request format",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	caller, ok := callerID(c)
+	if !ok || (req.SignerID != uuid.Nil && req.SignerID != caller) {
+		h.log(c).Warn("Rejected transfer approval for another signer", "pending_transfer_id", pendingID, "signer_id", req.SignerID)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Approvals must be made by the authenticated signer",
+			"code": errors.ErrAuthorizationFailed,
 		})
 		return
 	}
+	req.SignerID = caller
+
+	response, err := h.tokenService.ApproveTransfer(c.Request.Context(), pendingID, req.SignerID)
+	if err != nil {
+		h.log(c).Error("Failed to approve transfer", "error", err, "pending_transfer_id", pendingID, "signer_id", req.SignerID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				statusCode = http.StatusNotFound
+			} else if tokenErr.Code == errors.ErrTokenFrozen || tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
+			} else if tokenErr.Code == errors.ErrSanctionsBlocked {
+				statusCode = http.StatusUnavailableForLegalReasons
+			} else if tokenErr.Code == errors.ErrVelocityExceeded {
+				statusCode = http.StatusTooManyRequests
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to approve transfer")
+		return
+	}
 
-	h.logger.Info("Token transferred successfully", "token_id", tokenID, "new_owner", req.NewOwner)
+	h.log(c).Info("Transfer approval recorded", "pending_transfer_id", pendingID, "signer_id", req.SignerID, "completed", response.Completed)
 	c.JSON(http.StatusOK, response)
 }
 
-// DestroyToken handles token destruction requests
+// SetWalletSigningPolicy handles updates to a wallet's required co-signer count and the
+// co-signers authorized to approve its transfers
+func (h *TokenHandler) SetWalletSigningPolicy(c *gin.Context) {
+	walletIDStr := c.Param("id")
+	walletID, err := uuid.Parse(walletIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		RequiredSigners int         `json:"required_signers" binding:"min=0"`
+		CoSigners       []uuid.UUID `json:"co_signers"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid signing policy request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.tokenService.SetWalletRequiredSigners(c.Request.Context(), walletID, req.RequiredSigners, req.CoSigners); err != nil {
+		h.log(c).Error("Failed to set wallet signing policy", "error", err, "wallet_id", walletID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to set wallet signing policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wallet_id": walletID,
+		"required_signers": req.RequiredSigners,
+		"co_signers": req.CoSigners,
+	})
+}
+
+// DestroyToken handles token destruction requests. Administrators can pass force=true with a
+// note to destroy a token that is disputed or has a pending transfer.
 func (h *TokenHandler) DestroyToken(c *gin.Context) {
 	tokenIDStr := c.Param("id")
 	tokenID, err := uuid.Parse(tokenIDStr)
@@ -166,14 +385,34 @@ func (h *TokenHandler) DestroyToken(c *gin.Context) {
 		return
 	}
 
-	err = h.tokenService.DestroyToken(c.Request.Context(), tokenID)
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid force flag",
+		})
+		return
+	}
+
+	if force {
+		if !sharedhttp.HasRole(c, "admin") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Forced destruction requires the admin role",
+			})
+			return
+		}
+		err = h.tokenService.ForceDestroyToken(c.Request.Context(), tokenID, c.Query("note"))
+	} else {
+		err = h.tokenService.DestroyToken(c.Request.Context(), tokenID)
+	}
 	if err != nil {
-		h.logger.Error("Failed to destroy token", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to destroy token", "error", err, "token_id", tokenID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			statusCode := http.StatusBadRequest
+			statusCode := errorStatus(tokenErr)
 			if tokenErr.Code == errors.ErrTokenNotFound {
 				statusCode = http.StatusNotFound
+			} else if tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
 			}
 			
 			c.JSON(statusCode, gin.H{
@@ -183,13 +422,11 @@ func (h *TokenHandler) DestroyToken(c *gin.Context) {
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to destroy token",
-		})
+		internalError(c, err, "Failed to destroy token")
 		return
 	}
 
-	h.logger.Info("Token destroyed successfully", "token_id", tokenID)
+	h.log(c).Info("Token destroyed successfully", "token_id", tokenID, "forced", force)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Token destroyed successfully",
 		"token_id": tokenID,
@@ -209,7 +446,7 @@ func (h *TokenHandler) GetTokenHistory(c *gin.Context) {
 
 	history, err := h.tokenService.GetTokenHistory(c.Request.Context(), tokenID)
 	if err != nil {
-		h.logger.Error("Failed to get token history", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to get token history", "error", err, "token_id", tokenID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
 			if tokenErr.Code == errors.ErrTokenNotFound {
@@ -220,9 +457,7 @@ func (h *TokenHandler) GetTokenHistory(c *gin.Context) {
 			}
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve token history",
-		})
+		internalError(c, err, "Failed to retrieve token history")
 		return
 	}
 
@@ -259,49 +494,62 @@ func (h *TokenHandler) GetWalletTokens(c *gin.Context) {
 		offset = 0
 	}
 
-	tokens, err := h.tokenService.GetTokensByOwner(c.Request.Context(), walletID)
-	if err != nil {
-		h.logger.Error("Failed to get wallet tokens", "error", err, "wallet_id", walletID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve wallet tokens",
-		})
-		return
-	}
+	var filteredTokens []models.Token
+	var total int
+
+	if statusFilter == "" && cbdcTypeFilter == "" {
+		// Unfiltered listings are paginated by the repository
+		page, err := h.tokenService.GetTokensByOwner(c.Request.Context(), walletID, limit, offset)
+		if err != nil {
+			h.log(c).Error("Failed to get wallet tokens", "error", err, "wallet_id", walletID)
+			internalError(c, err, "Failed to retrieve wallet tokens")
+			return
+		}
+		filteredTokens = page.Tokens
+		total = page.Total
+	} else {
+		tokens, err := h.tokenService.GetAllTokensByOwner(c.Request.Context(), walletID)
+		if err != nil {
+			h.log(c).Error("Failed to get wallet tokens", "error", err, "wallet_id", walletID)
+			internalError(c, err, "Failed to retrieve wallet tokens")
+			return
+		}
 
-	// Apply filters
-	filteredTokens := tokens
-	if statusFilter != "" {
-		var filtered []models.Token
-		for _, token := range filteredTokens {
-			if string(token.Status) == statusFilter {
-				filtered = append(filtered, token)
+		// Apply filters
+		filteredTokens = tokens
+		if statusFilter != "" {
+			var filtered []models.Token
+			for _, token := range filteredTokens {
+				if string(token.Status) == statusFilter {
+					filtered = append(filtered, token)
+				}
 			}
+			filteredTokens = filtered
 		}
-		filteredTokens = filtered
-	}
 
-	if cbdcTypeFilter != "" {
-		var filtered []models.Token
-		for _, token := range filteredTokens {
-			if string(token.CBDCType) == cbdcTypeFilter {
-				filtered = append(filtered, token)
+		if cbdcTypeFilter != "" {
+			var filtered []models.Token
+			for _, token := range filteredTokens {
+				if string(token.CBDCType) == cbdcTypeFilter {
+					filtered = append(filtered, token)
+				}
 			}
+			filteredTokens = filtered
 		}
-		filteredTokens = filtered
-	}
 
-	// Apply pagination
-	total := len(filteredTokens)
-	start := offset
-	end := offset + limit
+		// Apply pagination
+		total = len(filteredTokens)
+		start := offset
+		end := offset + limit
 
-	if start >= total {
-		filteredTokens = []models.Token{}
-	} else {
-		if end > total {
-			end = total
+		if start >= total {
+			filteredTokens = []models.Token{}
+		} else {
+			if end > total {
+				end = total
+			}
+			filteredTokens = filteredTokens[start:end]
 		}
-		filteredTokens = filteredTokens[start:end]
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -316,117 +564,95 @@ func (h *TokenHandler) GetWalletTokens(c *gin.Context) {
 	})
 }
 
-// VerifyOwnership handles ownership verification requests
-func (h *TokenHandler) VerifyOwnership(c *gin.Context) {
-	tokenIDStr := c.Param("id")
-	tokenID, err := uuid.Parse(tokenIDStr)
+// GetWalletHoldings handles aggregate wallet holdings requests
+func (h *TokenHandler) GetWalletHoldings(c *gin.Context) {
+	walletIDStr := c.Param("id")
+	walletID, err := uuid.Parse(walletIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid token ID format",
+			"error": "Invalid wallet ID format",
 		})
 		return
 	}
 
-	ownerIDStr := c.Param("owner")
-	ownerID, err := uuid.Parse(ownerIDStr)
+	includeFrozen, err := strconv.ParseBool(c.DefaultQuery("include_frozen", "false"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid owner ID format",
-		})
-		return
+		includeFrozen = false
 	}
 
-	isOwner, err := h.tokenService.VerifyOwnership(c.Request.Context(), tokenID, ownerID)
+	holdings, err := h.tokenService.GetHoldings(c.Request.Context(), walletID, includeFrozen)
 	if err != nil {
-		h.logger.Error("Failed to verify ownership", "error", err, "token_id", tokenID, "owner_id", ownerID)
+		h.log(c).Error("Failed to get wallet holdings", "error", err, "wallet_id", walletID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			if tokenErr.Code == errors.ErrTokenNotFound {
-				c.JSON(http.StatusNotFound, gin.H{
-					"error": "Token not found",
-				})
-				return
-			}
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to verify ownership",
-		})
+		internalError(c, err, "Failed to retrieve wallet holdings")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token_id": tokenID,
-		"owner_id": ownerID,
-		"is_owner": isOwner,
-	})
+	c.JSON(http.StatusOK, holdings)
 }
 
-// FreezeToken handles token freezing requests
-func (h *TokenHandler) FreezeToken(c *gin.Context) {
-	tokenIDStr := c.Param("id")
-	tokenID, err := uuid.Parse(tokenIDStr)
+// SelectTokens handles requests to choose wallet tokens that pay an amount exactly
+func (h *TokenHandler) SelectTokens(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid token ID format",
+			"error": "Invalid wallet ID format",
 		})
 		return
 	}
 
-	var req service.FreezeTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid freeze token request", "error", err)
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
+			"error": "Invalid amount",
 		})
 		return
 	}
 
-	// Set token ID from URL parameter
-	req.TokenID = tokenID
+	cbdcType := models.CBDCType(c.Query("cbdc_type"))
 
-	response, err := h.tokenService.FreezeToken(c.Request.Context(), req)
+	selection, err := h.tokenService.SelectTokensForAmount(c.Request.Context(), walletID, cbdcType, amount)
 	if err != nil {
-		h.logger.Error("Failed to freeze token", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to select wallet tokens", "error", err, "wallet_id", walletID, "amount", amount)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			statusCode := http.StatusBadRequest
-			if tokenErr.Code == errors.ErrTokenNotFound {
-				statusCode = http.StatusNotFound
-			}
-			
-			c.JSON(statusCode, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to freeze token",
-		})
+		internalError(c, err, "Failed to select wallet tokens")
 		return
 	}
 
-	h.logger.Info("Token frozen successfully", "token_id", tokenID, "reason", req.Reason)
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, selection)
 }
 
-// UnfreezeToken handles token unfreezing requests
-func (h *TokenHandler) UnfreezeToken(c *gin.Context) {
-	tokenIDStr := c.Param("id")
-	tokenID, err := uuid.Parse(tokenIDStr)
+// ConsolidateWalletTokens handles requests to merge a wallet's small tokens into larger ones
+func (h *TokenHandler) ConsolidateWalletTokens(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid token ID format",
+			"error": "Invalid wallet ID format",
 		})
 		return
 	}
 
-	var req service.UnfreezeTokenRequest
+	var req struct {
+		CBDCType        models.CBDCType `json:"cbdc_type" binding:"required"`
+		MaxDenomination float64         `json:"max_denomination" binding:"required,gt=0"`
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid unfreeze token request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -434,41 +660,40 @@ func (h *TokenHandler) UnfreezeToken(c *gin.Context) {
 		return
 	}
 
-	// Set token ID from URL parameter
-	req.TokenID = tokenID
-
-	response, err := h.tokenService.UnfreezeToken(c.Request.Context(), req)
+	response, err := h.tokenService.ConsolidateDust(c.Request.Context(), walletID, req.CBDCType, req.MaxDenomination)
 	if err != nil {
-		h.logger.Error("Failed to unfreeze token", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to consolidate wallet tokens", "error", err, "wallet_id", walletID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			statusCode := http.StatusBadRequest
-			if tokenErr.Code == errors.ErrTokenNotFound {
-				statusCode = http.StatusNotFound
-			}
-			
-			c.JSON(statusCode, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to unfreeze token",
-		})
+		internalError(c, err, "Failed to consolidate wallet tokens")
 		return
 	}
 
-	h.logger.Info("Token unfrozen successfully", "token_id", tokenID, "reason", req.Reason)
+	h.log(c).Info("Wallet tokens consolidated", "wallet_id", walletID, "consolidated_count", response.ConsolidatedCount, "minted_count", len(response.Tokens))
 	c.JSON(http.StatusOK, response)
 }
 
-// BulkUpdateStatus handles bulk status update requests (for reversibility service)
-func (h *TokenHandler) BulkUpdateStatus(c *gin.Context) {
-	var req service.BulkStatusUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid bulk update status request", "error", err)
+// FreezeWalletTokens handles wallet token freeze requests
+func (h *TokenHandler) FreezeWalletTokens(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Note string `json:"note,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -476,37 +701,637 @@ func (h *TokenHandler) BulkUpdateStatus(c *gin.Context) {
 		return
 	}
 
-	response, err := h.tokenService.BulkUpdateTokenStatus(c.Request.Context(), req)
+	response, err := h.tokenService.FreezeWalletTokens(c.Request.Context(), walletID, req.Note)
 	if err != nil {
-		h.logger.Error("Failed to bulk update token status", "error", err, "token_count", len(req.TokenIDs))
+		h.log(c).Error("Failed to freeze wallet tokens", "error", err, "wallet_id", walletID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			c.JSON(http.StatusBadRequest, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to bulk update token status",
-		})
+		internalError(c, err, "Failed to freeze wallet tokens")
 		return
 	}
 
-	h.logger.Info("Bulk status update completed", "updated_count", response.UpdatedCount, "status", response.NewStatus)
+	h.log(c).Info("Wallet tokens frozen", "wallet_id", walletID, "updated_count", response.UpdatedCount)
 	c.JSON(http.StatusOK, response)
 }
 
-// BulkFreezeTokens handles bulk token freezing requests
-func (h *TokenHandler) BulkFreezeTokens(c *gin.Context) {
+// UnfreezeWalletTokens handles wallet token unfreeze requests
+func (h *TokenHandler) UnfreezeWalletTokens(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
 	var req struct {
-		TokenIDs []uuid.UUID `json:"token_ids" binding:"required"`
-		Reason   string      `json:"reason,omitempty"`
+		Note string `json:"note,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.UnfreezeWalletTokens(c.Request.Context(), walletID, req.Note)
+	if err != nil {
+		h.log(c).Error("Failed to unfreeze wallet tokens", "error", err, "wallet_id", walletID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to unfreeze wallet tokens")
+		return
+	}
+
+	h.log(c).Info("Wallet tokens unfrozen", "wallet_id", walletID, "updated_count", response.UpdatedCount)
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyOwnership handles ownership verification requests
+func (h *TokenHandler) VerifyOwnership(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	ownerIDStr := c.Param("owner")
+	ownerID, err := uuid.Parse(ownerIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid owner ID format",
+		})
+		return
+	}
+
+	isOwner, err := h.tokenService.VerifyOwnership(c.Request.Context(), tokenID, ownerID)
+	if err != nil {
+		h.log(c).Error("Failed to verify ownership", "error", err, "token_id", tokenID, "owner_id", ownerID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Token not found",
+				})
+				return
+			}
+		}
+		
+		internalError(c, err, "Failed to verify ownership")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token_id": tokenID,
+		"owner_id": ownerID,
+		"is_owner": isOwner,
+	})
+}
+
+// FreezeToken handles token freezing requests
+func (h *TokenHandler) FreezeToken(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	var req service.FreezeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid freeze token request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Set token ID from URL parameter
+	req.TokenID = tokenID
+
+	response, err := h.tokenService.FreezeToken(c.Request.Context(), req)
+	if err != nil {
+		h.log(c).Error("Failed to freeze token", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				statusCode = http.StatusNotFound
+			} else if tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to freeze token")
+		return
+	}
+
+	h.log(c).Info("Token frozen successfully", "token_id", tokenID, "reason", req.Reason)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateComplianceFlags handles compliance flag updates after a re-screen
+func (h *TokenHandler) UpdateComplianceFlags(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	var flags models.ComplianceFlags
+	if err := c.ShouldBindJSON(&flags); err != nil {
+		h.log(c).Error("Invalid compliance update request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.UpdateComplianceFlags(c.Request.Context(), tokenID, flags)
+	if err != nil {
+		h.log(c).Error("Failed to update compliance flags", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				statusCode = http.StatusNotFound
+			} else if tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to update compliance flags")
+		return
+	}
+
+	h.log(c).Info("Compliance flags updated", "token_id", tokenID, "auto_frozen", response.AutoFrozen)
+	c.JSON(http.StatusOK, response)
+}
+
+// UnfreezeToken handles token unfreezing requests
+func (h *TokenHandler) UnfreezeToken(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	var req service.UnfreezeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid unfreeze token request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Set token ID from URL parameter
+	req.TokenID = tokenID
+
+	response, err := h.tokenService.UnfreezeToken(c.Request.Context(), req)
+	if err != nil {
+		h.log(c).Error("Failed to unfreeze token", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				statusCode = http.StatusNotFound
+			} else if tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to unfreeze token")
+		return
+	}
+
+	h.log(c).Info("Token unfrozen successfully", "token_id", tokenID, "reason", req.Reason)
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkUpdateStatus handles bulk status update requests (for reversibility service)
+func (h *TokenHandler) BulkUpdateStatus(c *gin.Context) {
+	var req service.BulkStatusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid bulk update status request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.BulkUpdateTokenStatus(c.Request.Context(), req)
+	if err != nil {
+		h.log(c).Error("Failed to bulk update token status", "error", err, "token_count", len(req.TokenIDs))
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to bulk update token status")
+		return
+	}
+
+	h.log(c).Info("Bulk status update completed", "updated_count", response.UpdatedCount, "status", response.NewStatus)
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkTransferOwnership handles bulk ownership transfer requests (for custody migration)
+func (h *TokenHandler) BulkTransferOwnership(c *gin.Context) {
+	var req service.BulkTransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid bulk transfer request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.BulkTransferOwnership(c.Request.Context(), req.TokenIDs, req.NewOwner, req.TransactionID)
+	if err != nil {
+		h.log(c).Error("Failed to bulk transfer tokens", "error", err, "token_count", len(req.TokenIDs))
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrInvalidTokenState || tokenErr.Code == errors.ErrConcurrentModification {
+				statusCode = http.StatusConflict
+			} else if tokenErr.Code == errors.ErrSanctionsBlocked {
+				statusCode = http.StatusUnavailableForLegalReasons
+			}
+			
+			c.JSON(statusCode, gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+				"details": tokenErr.Details,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to bulk transfer tokens")
+		return
+	}
+
+	h.log(c).Info("Bulk ownership transfer completed", "transferred_count", response.TransferredCount, "new_owner", req.NewOwner)
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkFreezeTokens handles bulk token freezing requests
+func (h *TokenHandler) BulkFreezeTokens(c *gin.Context) {
+	var req struct {
+		TokenIDs []uuid.UUID          `json:"token_ids" binding:"required"`
+		Reason   service.FreezeReason `json:"reason,omitempty"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid bulk freeze request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.BulkFreezeTokens(c.Request.Context(), req.TokenIDs, req.Reason)
+	if err != nil {
+		h.log(c).Error("Failed to bulk freeze tokens", "error", err, "token_count", len(req.TokenIDs))
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to bulk freeze tokens")
+		return
+	}
+
+	h.log(c).Info("Bulk freeze completed", "frozen_count", response.UpdatedCount, "reason", req.Reason)
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkUnfreezeTokens handles bulk token unfreezing requests
+func (h *TokenHandler) BulkUnfreezeTokens(c *gin.Context) {
+	var req struct {
+		TokenIDs []uuid.UUID          `json:"token_ids" binding:"required"`
+		Reason   service.FreezeReason `json:"reason,omitempty"`
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid bulk unfreeze request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.BulkUnfreezeTokens(c.Request.Context(), req.TokenIDs, req.Reason)
+	if err != nil {
+		h.log(c).Error("Failed to bulk unfreeze tokens", "error", err, "token_count", len(req.TokenIDs))
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to bulk unfreeze tokens")
+		return
+	}
+
+	h.log(c).Info("Bulk unfreeze completed", "unfrozen_count", response.UpdatedCount, "reason", req.Reason)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTokensByStatus handles requests to get tokens by status
+func (h *TokenHandler) GetTokensByStatus(c *gin.Context) {
+	statusStr := c.Param("status")
+	status := models.TokenStatus(statusStr)
+
+	limitStr := c.DefaultQuery("limit", "100")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	page, err := h.tokenService.GetTokensByStatus(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.log(c).Error("Failed to get tokens by status", "error", err, "status", status)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to retrieve tokens by status")
+		return
+	}
+
+	h.log(c).Info("Retrieved tokens by status", "status", status, "count", len(page.Tokens))
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"tokens": page.Tokens,
+		"count": len(page.Tokens),
+		"pagination": gin.H{
+			"total": page.Total,
+			"limit": page.Limit,
+			"offset": page.Offset,
+			"count": len(page.Tokens),
+		},
+	})
+}
+
+// GetTokensByCBDCType handles requests to get tokens by CBDC type
+func (h *TokenHandler) GetTokensByCBDCType(c *gin.Context) {
+	cbdcTypeStr := c.Param("type")
+	cbdcType := models.CBDCType(cbdcTypeStr)
+
+	// Validate CBDC type
+	if !h.tokenService.SupportsCBDCType(cbdcType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid CBDC type",
+			"valid_types": h.tokenService.SupportedCBDCTypes(),
+		})
+		return
+	}
+
+	// This would typically be implemented in the service layer
+	h.log(c).Info("Get tokens by CBDC type requested", "cbdc_type", cbdcType)
+	
+	c.JSON(http.StatusOK, gin.H{
+		"cbdc_type": cbdcType,
+		"tokens": []models.Token{}, // Placeholder
+		"count": 0,
+	})
+}
+
+// ExportAuditLog handles requests to stream the token audit entries of a period as
+// newline-delimited JSON with a signed checksum trailer
+func (h *TokenHandler) ExportAuditLog(c *gin.Context) {
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid from timestamp, expected RFC 3339",
+		})
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid to timestamp, expected RFC 3339",
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	trailer, err := h.tokenService.ExportAuditLog(c.Request.Context(), from, to, c.Writer)
+	if err != nil {
+		h.log(c).Error("Failed to export audit log", "error", err, "from", from, "to", to)
+		
+		// Once streaming has started the export ends without its trailer, which the
+		// recipient detects as truncation
+		if c.Writer.Written() {
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to export audit log")
+		return
+	}
+
+	h.log(c).Info("Exported audit log", "from", from, "to", to, "entries", trailer.Entries)
+}
+
+// GetFreezeReport handles requests for the freezes recorded in a reporting period
+func (h *TokenHandler) GetFreezeReport(c *gin.Context) {
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid from timestamp, expected RFC 3339",
+		})
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid to timestamp, expected RFC 3339",
+		})
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "100")
+	offsetStr := c.DefaultQuery("offset", "0")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	report, err := h.tokenService.GetFreezeReport(c.Request.Context(), from, to, limit, offset)
+	if err != nil {
+		h.log(c).Error("Failed to get freeze report", "error", err, "from", from, "to", to)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to retrieve freeze report")
+		return
+	}
+
+	h.log(c).Info("Retrieved freeze report", "from", from, "to", to, "count", len(report.Events))
+	c.JSON(http.StatusOK, gin.H{
+		"from": report.From,
+		"to": report.To,
+		"events": report.Events,
+		"count": len(report.Events),
+		"pagination": gin.H{
+			"total": report.Total,
+			"limit": report.Limit,
+			"offset": report.Offset,
+			"count": len(report.Events),
+		},
+	})
+}
+
+// GetTokenAuditTrail handles audit trail retrieval requests
+func (h *TokenHandler) GetTokenAuditTrail(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	includeArchived, err := strconv.ParseBool(c.DefaultQuery("include_archived", "false"))
+	if err != nil {
+		includeArchived = false
+	}
+
+	auditTrail, err := h.tokenService.GetTokenAuditTrail(c.Request.Context(), tokenID, includeArchived)
+	if err != nil {
+		h.log(c).Error("Failed to get token audit trail", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Token not found",
+				})
+				return
+			}
+			
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to retrieve token audit trail")
+		return
 	}
-	
+
+	h.log(c).Info("Retrieved token audit trail", "token_id", tokenID, "entries", len(auditTrail))
+	c.JSON(http.StatusOK, gin.H{
+		"token_id": tokenID,
+		"audit_trail": auditTrail,
+		"count": len(auditTrail),
+	})
+}
+
+// GetTokenAuditTrails handles requests for the audit trails of several tokens at once
+func (h *TokenHandler) GetTokenAuditTrails(c *gin.Context) {
+	var req service.AuditTrailBatchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid bulk freeze request", "error", err)
+		h.log(c).Error("Invalid audit trail batch request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -514,37 +1339,186 @@ func (h *TokenHandler) BulkFreezeTokens(c *gin.Context) {
 		return
 	}
 
-	response, err := h.tokenService.BulkFreezeTokens(c.Request.Context(), req.TokenIDs, req.Reason)
+	auditTrails, err := h.tokenService.GetTokenAuditTrails(c.Request.Context(), req.TokenIDs)
 	if err != nil {
-		h.logger.Error("Failed to bulk freeze tokens", "error", err, "token_count", len(req.TokenIDs))
+		h.log(c).Error("Failed to get token audit trails", "error", err, "token_count", len(req.TokenIDs))
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			c.JSON(http.StatusBadRequest, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to bulk freeze tokens",
+		internalError(c, err, "Failed to retrieve token audit trails")
+		return
+	}
+
+	h.log(c).Info("Retrieved token audit trails", "token_count", len(auditTrails))
+	c.JSON(http.StatusOK, gin.H{
+		"audit_trails": auditTrails,
+		"count": len(auditTrails),
+	})
+}
+
+// GetTokenProvenance handles token provenance requests, returning the token's lineage with
+// its linked transactions
+func (h *TokenHandler) GetTokenProvenance(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
 		})
 		return
 	}
 
-	h.logger.Info("Bulk freeze completed", "frozen_count", response.UpdatedCount, "reason", req.Reason)
-	c.JSON(http.StatusOK, response)
+	provenance, err := h.tokenService.GetTokenProvenance(c.Request.Context(), tokenID)
+	if err != nil {
+		h.log(c).Error("Failed to get token provenance", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Token not found",
+				})
+				return
+			}
+			
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to retrieve token provenance")
+		return
+	}
+
+	h.log(c).Info("Retrieved token provenance", "token_id", tokenID, "entries", len(provenance.Entries))
+	c.JSON(http.StatusOK, provenance)
 }
 
-// BulkUnfreezeTokens handles bulk token unfreezing requests
-func (h *TokenHandler) BulkUnfreezeTokens(c *gin.Context) {
-	var req struct {
-		TokenIDs []uuid.UUID `json:"token_ids" binding:"required"`
-		Reason   string      `json:"reason,omitempty"`
+// GetTokenHolds handles requests for a token's current and past holds
+func (h *TokenHandler) GetTokenHolds(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
 	}
-	
+
+	holds, err := h.tokenService.GetTokenHolds(c.Request.Context(), tokenID)
+	if err != nil {
+		h.log(c).Error("Failed to get token holds", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Token not found",
+				})
+				return
+			}
+			
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to retrieve token holds")
+		return
+	}
+
+	h.log(c).Info("Retrieved token holds", "token_id", tokenID, "holds", len(holds.Holds))
+	c.JSON(http.StatusOK, holds)
+}
+
+// VerifyAuditTrail handles token audit trail integrity verification requests
+func (h *TokenHandler) VerifyAuditTrail(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	verification, err := h.tokenService.VerifyAuditTrail(c.Request.Context(), tokenID)
+	if err != nil {
+		h.log(c).Error("Failed to verify token audit trail", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to verify token audit trail")
+		return
+	}
+
+	if !verification.Valid {
+		h.log(c).Warn("Token audit trail integrity check failed", "token_id", tokenID, "broken_sequence", verification.BrokenSequence, "reason", verification.Reason)
+	}
+	c.JSON(http.StatusOK, verification)
+}
+
+// GetTokenStateAt handles requests for a token's status and owner at a point in time,
+// replayed from its audit trail
+func (h *TokenHandler) GetTokenStateAt(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, c.Query("t"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid t timestamp, expected RFC 3339",
+		})
+		return
+	}
+
+	state, err := h.tokenService.ReconstructTokenAt(c.Request.Context(), tokenID, at)
+	if err != nil {
+		h.log(c).Error("Failed to reconstruct token state", "error", err, "token_id", tokenID, "at", at)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to reconstruct token state")
+		return
+	}
+
+	h.log(c).Info("Reconstructed token state", "token_id", tokenID, "at", at, "entries_applied", state.EntriesApplied)
+	c.JSON(http.StatusOK, state)
+}
+
+// RecallSeries handles issuer-initiated series recall requests. An issuer may only recall
+// its own series, identified by the issuer the gateway authenticated the caller as; admins
+// may recall any issuer's series.
+func (h *TokenHandler) RecallSeries(c *gin.Context) {
+	var req service.RecallSeriesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid bulk unfreeze request", "error", err)
+		h.log(c).Error("Invalid recall series request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 			"details": err.Error(),
@@ -552,91 +1526,181 @@ func (h *TokenHandler) BulkUnfreezeTokens(c *gin.Context) {
 		return
 	}
 
-	response, err := h.tokenService.BulkUnfreezeTokens(c.Request.Context(), req.TokenIDs, req.Reason)
+	scope := c.GetHeader(sharedhttp.IssuerHeader)
+	if sharedhttp.HasRole(c, "admin") {
+		scope = req.Issuer
+	}
+	ctx := service.WithIssuerScope(c.Request.Context(), scope)
+
+	response, err := h.tokenService.RecallSeries(ctx, req.Issuer, req.Series, req.Reason, req.Continuation)
 	if err != nil {
-		h.logger.Error("Failed to bulk unfreeze tokens", "error", err, "token_count", len(req.TokenIDs))
+		h.log(c).Error("Failed to recall token series", "error", err, "issuer", req.Issuer, "series", req.Series)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			c.JSON(http.StatusBadRequest, gin.H{
+			statusCode := errorStatus(tokenErr)
+			if tokenErr.Code == errors.ErrAuthorizationFailed {
+				statusCode = http.StatusForbidden
+			}
+			
+			c.JSON(statusCode, gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to bulk unfreeze tokens",
-		})
+		internalError(c, err, "Failed to recall token series")
 		return
 	}
 
-	h.logger.Info("Bulk unfreeze completed", "unfrozen_count", response.UpdatedCount, "reason", req.Reason)
+	h.log(c).Info("Token series recalled", "issuer", req.Issuer, "series", req.Series, "recalled_count", response.RecalledCount, "reason", req.Reason)
 	c.JSON(http.StatusOK, response)
 }
 
-// GetTokensByStatus handles requests to get tokens by status
-func (h *TokenHandler) GetTokensByStatus(c *gin.Context) {
-	statusStr := c.Param("status")
-	status := models.TokenStatus(statusStr)
+// GetIssuerQuota handles retrieval of an issuer's issuance quotas
+func (h *TokenHandler) GetIssuerQuota(c *gin.Context) {
+	issuer := c.Param("issuer")
 
-	tokens, err := h.tokenService.GetTokensByStatus(c.Request.Context(), status)
+	quotas, err := h.tokenService.GetIssuerQuotas(c.Request.Context(), issuer)
 	if err != nil {
-		h.logger.Error("Failed to get tokens by status", "error", err, "status", status)
+		h.log(c).Error("Failed to get issuer quotas", "error", err, "issuer", issuer)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
-			c.JSON(http.StatusBadRequest, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve tokens by status",
-		})
+		internalError(c, err, "Failed to get issuer quotas")
 		return
 	}
 
-	h.logger.Info("Retrieved tokens by status", "status", status, "count", len(tokens))
 	c.JSON(http.StatusOK, gin.H{
-		"status": status,
-		"tokens": tokens,
-		"count": len(tokens),
+		"issuer": issuer,
+		"quotas": quotas,
 	})
 }
 
-// GetTokensByCBDCType handles requests to get tokens by CBDC type
-func (h *TokenHandler) GetTokensByCBDCType(c *gin.Context) {
-	cbdcTypeStr := c.Param("type")
-	cbdcType := models.CBDCType(cbdcTypeStr)
+// SetIssuerQuota handles admin updates to an issuer's quota for a series and CBDC type
+func (h *TokenHandler) SetIssuerQuota(c *gin.Context) {
+	issuer := c.Param("issuer")
 
-	// Validate CBDC type
-	validTypes := map[models.CBDCType]bool{
-		models.CBDCTypeUSD: true,
-		models.CBDCTypeEUR: true,
-		models.CBDCTypeGBP: true,
+	var req struct {
+		Series   string          `json:"series" binding:"required"`
+		CBDCType models.CBDCType `json:"cbdc_type" binding:"required"`
+		Quota    float64         `json:"quota" binding:"min=0"`
 	}
 
-	if !validTypes[cbdcType] {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid issuer quota request", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid CBDC type",
-			"valid_types": []string{"USD-CBDC", "EUR-CBDC", "GBP-CBDC"},
+			"error": "Invalid request format",
+			"details": err.Error(),
 		})
 		return
 	}
 
-	// This would typically be implemented in the service layer
-	h.logger.Info("Get tokens by CBDC type requested", "cbdc_type", cbdcType)
-	
+	quota, err := h.tokenService.SetIssuerQuota(c.Request.Context(), issuer, req.Series, req.CBDCType, req.Quota)
+	if err != nil {
+		h.log(c).Error("Failed to set issuer quota", "error", err, "issuer", issuer, "series", req.Series)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to set issuer quota")
+		return
+	}
+
+	h.log(c).Info("Issuer quota updated", "issuer", issuer, "series", req.Series, "cbdc_type", req.CBDCType, "quota", req.Quota)
+	c.JSON(http.StatusOK, quota)
+}
+
+// RotateIssuerKey handles requests to replace an issuer's active signing key
+func (h *TokenHandler) RotateIssuerKey(c *gin.Context) {
+	issuer := c.Param("issuer")
+
+	var req service.RotateIssuerKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log(c).Error("Invalid issuer key rotation request", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.tokenService.RotateIssuerKey(c.Request.Context(), issuer, req)
+	if err != nil {
+		h.log(c).Error("Failed to rotate issuer signing key", "error", err, "issuer", issuer, "key_id", req.KeyID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to rotate issuer signing key")
+		return
+	}
+
+	h.log(c).Info("Issuer signing key rotated", "issuer", issuer, "key_id", response.Key.KeyID, "retired_key_id", response.RetiredKeyID)
+	c.JSON(http.StatusCreated, response)
+}
+
+// VerifyTokenProof handles Merkle issuance proof verification requests
+func (h *TokenHandler) VerifyTokenProof(c *gin.Context) {
+	tokenIDStr := c.Param("id")
+	tokenID, err := uuid.Parse(tokenIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token ID format",
+		})
+		return
+	}
+
+	valid, root, err := h.tokenService.VerifyTokenProof(c.Request.Context(), tokenID)
+	if err != nil {
+		h.log(c).Error("Failed to verify token proof", "error", err, "token_id", tokenID)
+		
+		if tokenErr, ok := err.(*errors.EchoPayError); ok {
+			if tokenErr.Code == errors.ErrTokenNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Token not found",
+				})
+				return
+			}
+			
+			c.JSON(errorStatus(tokenErr), gin.H{
+				"error": tokenErr.Message,
+				"code": tokenErr.Code,
+			})
+			return
+		}
+		
+		internalError(c, err, "Failed to verify token proof")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"cbdc_type": cbdcType,
-		"tokens": []models.Token{}, // Placeholder
-		"count": 0,
+		"token_id": tokenID,
+		"valid": valid,
+		"root": root,
 	})
 }
 
-// GetTokenAuditTrail handles audit trail retrieval requests
-func (h *TokenHandler) GetTokenAuditTrail(c *gin.Context) {
+// VerifyTokenSignature handles issuance signature verification requests. An issuer public
+// key may be supplied in the public_key query parameter, base64-encoded with the URL-safe
+// alphabet and optional padding; otherwise the key is resolved from the configured key source.
+func (h *TokenHandler) VerifyTokenSignature(c *gin.Context) {
 	tokenIDStr := c.Param("id")
 	tokenID, err := uuid.Parse(tokenIDStr)
 	if err != nil {
@@ -646,9 +1710,21 @@ func (h *TokenHandler) GetTokenAuditTrail(c *gin.Context) {
 		return
 	}
 
-	auditTrail, err := h.tokenService.GetTokenAuditTrail(c.Request.Context(), tokenID)
+	var publicKey ed25519.PublicKey
+	if encoded := c.Query("public_key"); encoded != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid public key encoding",
+			})
+			return
+		}
+		publicKey = ed25519.PublicKey(raw)
+	}
+
+	valid, err := h.tokenService.VerifyTokenSignature(c.Request.Context(), tokenID, publicKey)
 	if err != nil {
-		h.logger.Error("Failed to get token audit trail", "error", err, "token_id", tokenID)
+		h.log(c).Error("Failed to verify token signature", "error", err, "token_id", tokenID)
 		
 		if tokenErr, ok := err.(*errors.EchoPayError); ok {
 			if tokenErr.Code == errors.ErrTokenNotFound {
@@ -658,23 +1734,62 @@ func (h *TokenHandler) GetTokenAuditTrail(c *gin.Context) {
 				return
 			}
 			
-			c.JSON(http.StatusBadRequest, gin.H{
+			c.JSON(errorStatus(tokenErr), gin.H{
 				"error": tokenErr.Message,
 				"code": tokenErr.Code,
 			})
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve token audit trail",
-		})
+		internalError(c, err, "Failed to verify token signature")
 		return
 	}
 
-	h.logger.Info("Retrieved token audit trail", "token_id", tokenID, "entries", len(auditTrail))
 	c.JSON(http.StatusOK, gin.H{
 		"token_id": tokenID,
-		"audit_trail": auditTrail,
-		"count": len(auditTrail),
+		"valid": valid,
+	})
+}
+
+// internalError responds to a failure that no endpoint-specific rule matched. A database
+// statement timeout is reported as the service being unavailable, so clients retry later.
+func internalError(c *gin.Context, err error, message string) {
+	if database.IsStatementTimeout(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Database statement timed out",
+			"code": errors.ErrServiceUnavailable,
+		})
+		return
+	}
+	
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": message,
 	})
-}
\ No newline at end of file
+}
+
+// callerID returns the authenticated caller set by the API gateway: the wallet it acts for,
+// or failing that its user ID
+func callerID(c *gin.Context) (uuid.UUID, bool) {
+	header := c.GetHeader(sharedhttp.WalletHeader)
+	if header == "" {
+		header = c.GetHeader(sharedhttp.UserHeader)
+	}
+	id, err := uuid.Parse(header)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// errorStatus returns the status for a token error that no endpoint-specific rule matched.
+// Token state violations, tokens not yet final and duplicate tokens are conflicts; request
+// validation failures are bad requests.
+func errorStatus(tokenErr *errors.EchoPayError) int {
+	switch tokenErr.Code {
+	case errors.ErrInvalidTokenState, errors.ErrNotYetFinal, errors.ErrDuplicateToken:
+		return http.StatusConflict
+	case errors.ErrAuthorizationFailed:
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	sharedhttp "echopay/shared/libraries/http"
+	"echopay/shared/libraries/logging"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+	"echopay/token-management/src/service"
+)
+
+// slowRepository blocks reads until their context is done, like a database that has stopped
+// responding, and reports why each read ended
+type slowRepository struct {
+	repository.TokenRepository
+	cancelled chan error
+}
+
+func (r *slowRepository) GetByID(ctx context.Context, tokenID uuid.UUID) (*models.Token, error) {
+	select {
+	case <-ctx.Done():
+		r.cancelled <- ctx.Err()
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		r.cancelled <- nil
+		return nil, nil
+	}
+}
+
+func TestTokenHandler_RequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &slowRepository{cancelled: make(chan error, 1)}
+	tokenHandler := NewTokenHandler(service.NewTokenServiceWithDeps(repo, inlineTransactions{}), logging.NewLoggerWithWriter("token-management", io.Discard))
+
+	router := gin.New()
+	router.GET("/api/v1/tokens/:id", sharedhttp.RequestTimeout(50*time.Millisecond, "token-management"), tokenHandler.GetToken)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tokens/"+uuid.New().String(), nil))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, context.DeadlineExceeded, <-repo.cancelled, "the repository read should be cancelled")
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, errors.ErrRequestTimeout, body["error"])
+	assert.Equal(t, "token-management", body["service"])
+}
+
+// duplicateTokenRepository rejects every token insert as if the token ID already existed
+type duplicateTokenRepository struct {
+	repository.TokenRepository
+}
+
+func (r *duplicateTokenRepository) GetIssuerQuotaForUpdateWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType) (*repository.IssuerQuota, error) {
+	return nil, nil
+}
+
+func (r *duplicateTokenRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	return errors.NewTokenManagementError(errors.ErrDuplicateToken, "token "+token.TokenID.String()+" already exists")
+}
+
+func TestTokenHandler_IssueTokens_DuplicateTokenConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenHandler := NewTokenHandler(service.NewTokenServiceWithDeps(&duplicateTokenRepository{}, inlineTransactions{}), logging.NewLoggerWithWriter("token-management", io.Discard))
+
+	router := gin.New()
+	router.POST("/api/v1/tokens", tokenHandler.IssueTokens)
+
+	body, err := json.Marshal(service.IssueTokenRequest{
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		Owner:        uuid.New(),
+		Issuer:       "Federal Reserve",
+		Series:       "2025-A",
+		Quantity:     1,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tokens", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, errors.ErrDuplicateToken, response["code"])
+}
+
+func TestTokenHandler_IssueTokens_ReportsAllFieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenHandler := NewTokenHandler(service.NewTokenServiceWithDeps(&duplicateTokenRepository{}, inlineTransactions{}), logging.NewLoggerWithWriter("token-management", io.Discard))
+
+	router := gin.New()
+	router.POST("/api/v1/tokens", tokenHandler.IssueTokens)
+
+	body := `{"cbdc_type": "XYZ-CBDC", "denomination": -5, "owner": "not-a-wallet", "series": "2025-A", "quantity": 5000}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/tokens", bytes.NewReader([]byte(body))))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error  string                  `json:"error"`
+		Errors []sharedhttp.FieldError `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Invalid request format", response.Error)
+	assert.Equal(t, []sharedhttp.FieldError{
+		{Field: "owner", Message: "must be a valid UUID"},
+		{Field: "denomination", Message: "must be greater than 0"},
+		{Field: "issuer", Message: "is required"},
+		{Field: "quantity", Message: "must be at most 1000"},
+		{Field: "cbdc_type", Message: "invalid CBDC type: XYZ-CBDC"},
+	}, response.Errors)
+}
+
+// emptySeriesRepository holds no tokens in any series, so a recall that is authorized
+// recalls nothing
+type emptySeriesRepository struct {
+	repository.TokenRepository
+}
+
+func (r *emptySeriesRepository) GetActiveBySeries(ctx context.Context, issuer, series string, afterID uuid.UUID, limit int) ([]models.Token, error) {
+	return nil, nil
+}
+
+func (r *emptySeriesRepository) RecallWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, reason string) (int, error) {
+	return len(tokenIDs), nil
+}
+
+func TestTokenHandler_RecallSeries_IssuerScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenHandler := NewTokenHandler(service.NewTokenServiceWithDeps(&emptySeriesRepository{}, inlineTransactions{}), logging.NewLoggerWithWriter("token-management", io.Discard))
+
+	router := gin.New()
+	router.POST("/api/v1/tokens/recall", sharedhttp.RequireAnyRole("issuer", "admin"), tokenHandler.RecallSeries)
+
+	recall := func(roles string, headers map[string]string) int {
+		body := `{"issuer": "Federal Reserve", "series": "2025-A", "reason": "counterfeit batch"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tokens/recall", bytes.NewReader([]byte(body)))
+		if roles != "" {
+			req.Header.Set(sharedhttp.RolesHeader, roles)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// A client-chosen issuer header grants nothing
+	assert.Equal(t, http.StatusForbidden, recall("", map[string]string{"X-Issuer-ID": "Federal Reserve"}))
+	assert.Equal(t, http.StatusForbidden, recall(`["issuer"]`, map[string]string{
+		"X-Issuer-ID":           "Federal Reserve",
+		sharedhttp.IssuerHeader: "Bank of England",
+	}))
+
+	assert.Equal(t, http.StatusOK, recall(`["issuer"]`, map[string]string{sharedhttp.IssuerHeader: "Federal Reserve"}))
+	assert.Equal(t, http.StatusOK, recall(`["admin"]`, nil))
+}
+
+// coSignedRepository holds one pending transfer from sender that needs two approvals
+type coSignedRepository struct {
+	repository.TokenRepository
+	pending   repository.PendingTransfer
+	coSigner  uuid.UUID
+	approvals []uuid.UUID
+}
+
+func (r *coSignedRepository) GetPendingTransferWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID) (*repository.PendingTransfer, error) {
+	pending := r.pending
+	return &pending, nil
+}
+
+func (r *coSignedRepository) IsCoSignerWithTx(ctx context.Context, tx *sql.Tx, walletID, signerID uuid.UUID) (bool, error) {
+	return signerID == r.coSigner, nil
+}
+
+func (r *coSignedRepository) AddTransferApprovalWithTx(ctx context.Context, tx *sql.Tx, pendingID, signerID uuid.UUID) error {
+	r.approvals = append(r.approvals, signerID)
+	return nil
+}
+
+func TestTokenHandler_ApproveTransfer_SignerFromCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sender, coSigner := uuid.New(), uuid.New()
+	repo := &coSignedRepository{
+		pending: repository.PendingTransfer{
+			ID:              uuid.New(),
+			TokenID:         uuid.New(),
+			FromOwner:       sender,
+			NewOwner:        uuid.New(),
+			RequiredSigners: 2,
+			Status:          repository.PendingTransferStatusPending,
+		},
+		coSigner: coSigner,
+	}
+	tokenHandler := NewTokenHandler(service.NewTokenServiceWithDeps(repo, inlineTransactions{}), logging.NewLoggerWithWriter("token-management", io.Discard))
+
+	router := gin.New()
+	router.POST("/api/v1/transfers/:id/approve", tokenHandler.ApproveTransfer)
+
+	approve := func(caller string, signer uuid.UUID) int {
+		body := []byte(`{"signer_id": "` + signer.String() + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/transfers/"+repo.pending.ID.String()+"/approve", bytes.NewReader(body))
+		if caller != "" {
+			req.Header.Set(sharedhttp.WalletHeader, caller)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The sender can't approve by naming a co-signer, nor can an unauthenticated caller
+	assert.Equal(t, http.StatusForbidden, approve(sender.String(), coSigner))
+	assert.Equal(t, http.StatusForbidden, approve("", coSigner))
+	assert.Empty(t, repo.approvals)
+
+	assert.Equal(t, http.StatusOK, approve(coSigner.String(), coSigner))
+	assert.Equal(t, []uuid.UUID{coSigner}, repo.approvals)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"echopay/shared/libraries/logging"
+	"echopay/token-management/src/events"
+)
+
+// WebSocketHandler handles WebSocket connections for real-time token events
+type WebSocketHandler struct {
+	statusTracker *events.TokenStatusTracker
+	upgrader      websocket.Upgrader
+	logger        *logging.Logger
+}
+
+// WebSocketMessage represents a message sent over WebSocket
+type WebSocketMessage struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// SubscriptionRequest represents a subscription request from client. It follows the
+// transaction service's subscription protocol, filtering by owner and token instead.
+type SubscriptionRequest struct {
+	Type     string      `json:"type"`
+	OwnerIDs []uuid.UUID `json:"owner_ids,omitempty"`
+	TokenIDs []uuid.UUID `json:"token_ids,omitempty"`
+}
+
+// NewWebSocketHandler creates a new WebSocket handler
+func NewWebSocketHandler(statusTracker *events.TokenStatusTracker) *WebSocketHandler {
+	return &WebSocketHandler{
+		statusTracker: statusTracker,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				// In production, implement proper origin checking
+				return true
+			},
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		logger: logging.NewLogger("token-websocket-handler"),
+	}
+}
+
+// HandleWebSocket handles WebSocket connections for real-time token events
+func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.Error("Failed to upgrade WebSocket connection", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	clientID := uuid.New()
+	h.logger.Info("WebSocket client connected", "client_id", clientID)
+
+	// Set up ping/pong handlers for connection health
+	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go h.pingRoutine(ctx, conn)
+
+	// Handle client messages and subscriptions
+	for {
+		var req SubscriptionRequest
+		err := conn.ReadJSON(&req)
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				h.logger.Error("WebSocket read error", "error", err, "client_id", clientID)
+			}
+			break
+		}
+
+		switch req.Type {
+		case "subscribe":
+			h.handleSubscription(ctx, conn, req)
+		default:
+			h.sendMessage(conn, WebSocketMessage{
+				Type:      "error",
+				Timestamp: time.Now(),
+				Data:      map[string]string{"message": "unknown message type"},
+			})
+		}
+	}
+
+	h.logger.Info("WebSocket client disconnected", "client_id", clientID)
+}
+
+// handleSubscription streams token events matching the request until the connection closes
+func (h *WebSocketHandler) handleSubscription(ctx context.Context, conn *websocket.Conn, req SubscriptionRequest) {
+	filter := events.TokenFilter{
+		OwnerIDs: req.OwnerIDs,
+		TokenIDs: req.TokenIDs,
+	}
+
+	subscriber := h.statusTracker.Subscribe(filter)
+	defer h.statusTracker.Unsubscribe(subscriber.ID)
+
+	h.sendMessage(conn, WebSocketMessage{
+		Type:      "subscribed",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"subscriber_id": subscriber.ID,
+			"filter":        filter,
+		},
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-subscriber.Channel:
+			if !ok {
+				return // Channel closed
+			}
+
+			h.sendMessage(conn, WebSocketMessage{
+				Type:      "token_event",
+				Timestamp: time.Now(),
+				Data:      event,
+			})
+		}
+	}
+}
+
+// sendMessage sends a message to the WebSocket client
+func (h *WebSocketHandler) sendMessage(conn *websocket.Conn, message WebSocketMessage) {
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(message); err != nil {
+		h.logger.Error("Failed to send WebSocket message", "error", err)
+	}
+}
+
+// pingRoutine sends periodic ping messages to keep connection alive
+func (h *WebSocketHandler) pingRoutine(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				h.logger.Error("Failed to send ping", "error", err)
+				return
+			}
+		}
+	}
+}
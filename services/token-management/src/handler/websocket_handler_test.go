@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+	"echopay/token-management/src/service"
+)
+
+// freezeRepository serves a single token to FreezeToken. Other repository methods are not
+// expected to be called and panic through the nil embedded interface.
+type freezeRepository struct {
+	repository.TokenRepository
+	token *models.Token
+}
+
+func (r *freezeRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*models.Token, error) {
+	if tokenID != r.token.TokenID {
+		return nil, nil
+	}
+	token := *r.token
+	return &token, nil
+}
+
+func (r *freezeRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	*r.token = *token
+	return nil
+}
+
+// inlineTransactions runs transactions without a database
+type inlineTransactions struct{}
+
+func (inlineTransactions) Transaction(fn func(*sql.Tx) error) error {
+	return fn(nil)
+}
+
+func TestWebSocketHandler_FreezeNotifiesSubscribedOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	owner := uuid.New()
+	token := &models.Token{
+		TokenID:      uuid.New(),
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: owner,
+		Status:       models.TokenStatusActive,
+	}
+
+	tracker := events.NewTokenStatusTracker()
+	tokenService := service.NewTokenServiceWithDeps(&freezeRepository{token: token}, inlineTransactions{})
+	tokenService.SetStatusTracker(tracker)
+
+	router := gin.New()
+	router.GET("/ws/tokens", NewWebSocketHandler(tracker).HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	subscribe := func(ownerID uuid.UUID) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/tokens", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.WriteJSON(SubscriptionRequest{Type: "subscribe", OwnerIDs: []uuid.UUID{ownerID}}))
+
+		var subscribed WebSocketMessage
+		require.NoError(t, conn.ReadJSON(&subscribed))
+		require.Equal(t, "subscribed", subscribed.Type)
+		return conn
+	}
+
+	ownerConn := subscribe(owner)
+	defer ownerConn.Close()
+	otherConn := subscribe(uuid.New())
+	defer otherConn.Close()
+
+	_, err := tokenService.FreezeToken(context.Background(), service.FreezeTokenRequest{TokenID: token.TokenID})
+	require.NoError(t, err)
+
+	var message struct {
+		Type string            `json:"type"`
+		Data events.TokenEvent `json:"data"`
+	}
+	ownerConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	require.NoError(t, ownerConn.ReadJSON(&message))
+	assert.Equal(t, "token_event", message.Type)
+	assert.Equal(t, events.TokenEventFrozen, message.Data.Type)
+	assert.Equal(t, token.TokenID, message.Data.TokenID)
+	assert.Equal(t, owner, message.Data.OwnerID)
+	assert.Equal(t, models.TokenStatusFrozen, message.Data.Status)
+
+	// Another owner's subscription does not see the freeze
+	otherConn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	assert.Error(t, otherConn.ReadJSON(&message))
+}
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	
 	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/http"
 	"echopay/shared/libraries/logging"
 	"echopay/shared/libraries/monitoring"
+	"echopay/shared/libraries/webhooks"
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/grpcserver"
 	"echopay/token-management/src/handler"
 	"echopay/token-management/src/migrations"
 	"echopay/token-management/src/service"
@@ -23,21 +29,23 @@ func main() {
 	
 	// Initialize logger
 	logger := logging.NewLogger("token-management")
+	logging.SetDefault(logger) // Request-scoped loggers carry the correlation ID
 	
 	// Initialize metrics
 	_ = monitoring.NewMetrics("token-management")
 	
 	// Initialize database
 	dbConfig := database.DatabaseConfig{
-		Host:            "localhost",
-		Port:            5432,
-		Database:        "echopay_tokens",
-		User:            "echopay",
-		Password:        "echopay_dev",
-		SSLMode:         "disable",
-		MaxOpenConns:    25,
-		MaxIdleConns:    5,
-		ConnMaxLifetime: 5 * time.Minute,
+		Host:             "localhost",
+		Port:             5432,
+		Database:         "echopay_tokens",
+		User:             "echopay",
+		Password:         "echopay_dev",
+		SSLMode:          "disable",
+		MaxOpenConns:     25,
+		MaxIdleConns:     5,
+		ConnMaxLifetime:  5 * time.Minute,
+		StatementTimeout: config.GetDatabaseConfig().StatementTimeout,
 	}
 	
 	db, err := database.NewPostgresDB(dbConfig)
@@ -46,18 +54,178 @@ func main() {
 	}
 	defer db.Close()
 	
+	// Export connection pool statistics to /metrics and log slow queries
+	dbMetricsConfig := config.GetDatabaseMetricsConfig()
+	db.SetSlowQueryLog(dbMetricsConfig.SlowQueryThreshold, logger)
+	go db.ReportStats(context.Background(), database.NewPoolMetrics("token-management"), dbMetricsConfig.StatsInterval)
+	
+	// Shed low-priority routes while the database is saturated or unreachable
+	loadSheddingConfig := config.GetLoadSheddingConfig()
+	loadState := http.NewLoadState(loadSheddingConfig.RetryAfter)
+	go loadState.Watch(context.Background(), "database", loadSheddingConfig.CheckInterval, db.LoadReason)
+	
+	// Track startup so /readyz only reports ready once migrations have run and the
+	// schema matches the version this binary expects
+	readiness := http.NewReadinessTracker("migrations", "schema")
+	
 	// Run database migrations
-	if err := db.Migrate(migrations.GetTokenMigrations()); err != nil {
+	tokenMigrations := migrations.GetTokenMigrations()
+	if err := db.Migrate(tokenMigrations); err != nil {
 		log.Fatal("Failed to run database migrations:", err)
 	}
+	readiness.MarkReady("migrations")
+	
+	// Keep checking the schema version so a rollback or another binary's migrations take this instance out of rotation
+	go readiness.Watch(context.Background(), "schema", config.GetSchemaCheckConfig().Interval, func() error {
+		return db.HealthCheckDeep(len(tokenMigrations))
+	})
 	
 	logger.Info("Database connected and migrations applied")
 	
 	// Initialize services
 	tokenService := service.NewTokenService(db)
 	
+	// Sign issuance with each issuer's rotated key, or the configured signing key for issuers
+	// without one. Keys retired by rotation are loaded so their signatures still verify.
+	keySource, err := service.NewKeySourceFromConfig(config.GetSigningConfig())
+	if err != nil {
+		log.Fatal("Failed to load signing keys:", err)
+	}
+	keyRegistry := service.NewKeyRegistry(nil)
+	if keySource != nil {
+		keyRegistry = service.NewKeyRegistry(keySource)
+	}
+	tokenService.SetKeyRegistry(keyRegistry)
+	if err := tokenService.LoadIssuerKeys(context.Background()); err != nil {
+		log.Fatal("Failed to load issuer signing keys:", err)
+	}
+	
+	// Legacy clients may still send free-text freeze reasons while they migrate to reason codes
+	tokenService.SetAllowFreeTextReasons(config.GetReasonCodeConfig().AllowFreeText)
+	
+	// Tokens whose stored checksum does not match are logged, or rejected in strict mode
+	tokenService.SetStrictChecksums(config.GetTokenIntegrityConfig().StrictChecksums)
+	
+	// Large bulk status updates run in batches within a single transaction
+	bulkUpdateConfig := config.GetBulkUpdateConfig()
+	tokenService.SetBulkUpdateOptions(bulkUpdateConfig.BatchSize, bulkUpdateConfig.AuditWorkers)
+	
+	// Investigators may fetch the audit trails of a bounded number of tokens per request
+	auditBatchConfig := config.GetAuditBatchConfig()
+	tokenService.SetAuditBatchLimit(auditBatchConfig.MaxTokens)
+	
+	// Tokens changing hands too often in a short window are held back as a laundering signal
+	velocityConfig := config.GetTransferVelocityConfig()
+	tokenService.SetTransferVelocityLimit(velocityConfig.MaxTransfers, velocityConfig.Window)
+	
+	// Freshly transferred tokens cannot be destroyed until their transfer is final
+	tokenService.SetFinalityWindow(config.GetSettlementFinalityConfig().FinalityWindow)
+	
+	// Hot token reads are cached in memory when a cache size is configured
+	tokenCacheConfig := config.GetTokenCacheConfig()
+	tokenService.SetTokenCache(tokenCacheConfig.Size, tokenCacheConfig.TTL)
+	
+	// Accept the CBDC types configured for this deployment
+	currencyConfig := config.GetCurrencyConfig()
+	currencyRegistry := currency.NewRegistry(currencyConfig.Supported...)
+	for code, decimals := range currencyConfig.Decimals {
+		if err := currencyRegistry.SetDecimals(code, decimals); err != nil {
+			log.Fatal("Invalid currency configuration:", err)
+		}
+	}
+	tokenService.SetCurrencyRegistry(currencyRegistry)
+	
+	// Fetch transactions referenced by token provenance from the transaction service
+	transactionServiceConfig := config.GetTransactionServiceConfig()
+	tokenService.SetTransactionLookup(service.NewHTTPTransactionLookup(transactionServiceConfig.URL, transactionServiceConfig.Timeout))
+	
+	// Enforce the configured per-CBDC denomination rules at issuance
+	denominationRules, err := service.NewDenominationRulesFromConfig(config.GetIssuanceConfig())
+	if err != nil {
+		log.Fatal("Failed to load denomination rules:", err)
+	}
+	tokenService.SetDenominationRules(denominationRules)
+	
+	// Reject replayed or stale transfer requests
+	tokenService.SetReplayGuard(service.NewReplayGuard(config.GetReplayProtectionConfig()))
+	
+	// Notify registered webhook endpoints of freeze and unfreeze operations
+	webhookDispatcher := webhooks.NewDispatcher(config.GetWebhookConfig())
+	tokenService.SetWebhookDispatcher(webhookDispatcher)
+	
+	// Notify subscribed owners of freezes, transfers and destructions of their tokens
+	tokenStatusTracker := events.NewTokenStatusTracker()
+	tokenService.SetStatusTracker(tokenStatusTracker)
+	
+	// Lift time-limited freezes once they expire
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			result, err := tokenService.AutoUnfreeze(context.Background(), now)
+			if err != nil {
+				logger.Error("Failed to sweep expired freezes", "error", err)
+				continue
+			}
+			if len(result.UnfrozenTokenIDs) > 0 || len(result.FailedTokenIDs) > 0 {
+				logger.Info("Swept expired freezes", "unfrozen", len(result.UnfrozenTokenIDs), "failed", len(result.FailedTokenIDs))
+			}
+		}
+	}()
+	
+	// Move audit entries past the retention window into the audit archive
+	auditRetention := config.GetAuditRetentionConfig()
+	if auditRetention.Retention > 0 {
+		go func() {
+			ticker := time.NewTicker(auditRetention.Interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				archived, err := tokenService.ArchiveAuditTrail(context.Background(), now.Add(-auditRetention.Retention))
+				if err != nil {
+					logger.Error("Failed to archive audit entries", "error", err)
+					continue
+				}
+				if archived > 0 {
+					logger.Info("Archived audit entries", "archived", archived)
+				}
+			}
+		}()
+	}
+	
+	// Serve the gRPC API for internal callers on its own port
+	grpcConfig := config.GetGRPCConfig(9003)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcConfig.Port))
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	grpcServer := grpcserver.Register(tokenService)
+	defer grpcServer.GracefulStop()
+	go func() {
+		logger.Info("Token Management gRPC server starting", "port", grpcConfig.Port)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	
+	// Serve runtime profiles on an internal address when explicitly enabled
+	profilingConfig := config.GetProfilingConfig()
+	profilingServer, err := http.NewProfilingServer(profilingConfig)
+	if err != nil {
+		log.Fatal("Invalid profiling configuration:", err)
+	}
+	if profilingServer != nil {
+		defer profilingServer.Close()
+		go func() {
+			logger.Warn("Token Management profiling server starting", "address", profilingConfig.Address)
+			if err := profilingServer.ListenAndServe(); err != nil {
+				logger.Error("Profiling server stopped", "error", err)
+			}
+		}()
+	}
+	
 	// Initialize handlers
 	tokenHandler := handler.NewTokenHandler(tokenService, logger)
+	websocketHandler := handler.NewWebSocketHandler(tokenStatusTracker)
 	
 	// Set Gin mode based on environment
 	if cfg.Environment == "production" {
@@ -69,56 +237,82 @@ func main() {
 	
 	// Add middleware
 	r.Use(http.RequestIDMiddleware())
-	r.Use(http.CORSMiddleware())
-	r.Use(http.MetricsMiddleware("token-management"))
+	r.Use(http.CORSMiddleware(http.CORSOptionsFromConfig(config.GetCORSConfig())))
+	r.Use(http.MetricsMiddleware("token-management", http.MetricsOptionsFromConfig(config.GetHTTPMetricsConfig())))
 	r.Use(http.ErrorHandler())
 	r.Use(http.RateLimitMiddleware(500)) // 500 requests per minute
+	r.Use(http.MaxBodyBytes(http.DefaultMaxBodyBytes))
 	
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		// Check database health
-		if err := db.HealthCheck(); err != nil {
-			c.JSON(503, gin.H{
-				"status": "unhealthy",
-				"service": "token-management",
-				"database": "unhealthy",
-				"error": err.Error(),
-			})
-			return
-		}
-		
-		c.JSON(200, gin.H{
-			"status": "healthy",
-			"service": "token-management",
-			"database": "healthy",
-			"timestamp": time.Now().UTC(),
-		})
-	})
+	// Liveness and readiness endpoints
+	r.GET("/health", http.HealthCheckHandler("token-management"))
+	r.GET("/readyz", http.ReadinessHandler("token-management", readiness))
 	
 	// Metrics endpoint
 	r.GET("/metrics", http.MetricsHandler())
 	
-	// API routes
-	v1 := r.Group("/api/v1")
+	// WebSocket endpoint for real-time token events
+	r.GET("/ws/tokens", websocketHandler.HandleWebSocket)
+	
+	// API routes. Routes declared PriorityLow are shed while the service is overloaded, and
+	// requests that outlive their timeout are cancelled and answered with 504.
+	requestTimeouts := config.GetRequestTimeoutConfig()
+	api := r.Group("/api/v1")
+	v1 := api.Group("", http.RequestTimeout(requestTimeouts.Default, "token-management"))
+	v1Long := api.Group("", http.RequestTimeout(requestTimeouts.Long, "token-management"))
 	{
 		// Token management endpoints
 		v1.POST("/tokens", tokenHandler.IssueTokens)
+		v1Long.POST("/tokens/batch", http.MaxBodyBytes(http.BulkMaxBodyBytes), tokenHandler.IssueBatch)
+		v1.POST("/tokens/preview", tokenHandler.PreviewIssue)
 		v1.GET("/tokens/:id", tokenHandler.GetToken)
 		v1.POST("/tokens/:id/transfer", tokenHandler.TransferToken)
 		v1.DELETE("/tokens/:id", tokenHandler.DestroyToken)
-		v1.GET("/tokens/:id/history", tokenHandler.GetTokenHistory)
-		v1.GET("/tokens/:id/audit", tokenHandler.GetTokenAuditTrail)
+		v1.GET("/tokens/:id/history", loadState.Priority(http.PriorityLow), tokenHandler.GetTokenHistory)
+		v1.GET("/tokens/:id/audit", loadState.Priority(http.PriorityLow), tokenHandler.GetTokenAuditTrail)
+		v1.GET("/tokens/:id/audit/verify", tokenHandler.VerifyAuditTrail)
+		v1.POST("/tokens/audit/batch", http.RateLimitMiddleware(auditBatchConfig.RequestsPerMinute), loadState.Priority(http.PriorityLow), tokenHandler.GetTokenAuditTrails)
+		v1.GET("/tokens/:id/provenance", loadState.Priority(http.PriorityLow), tokenHandler.GetTokenProvenance)
+		v1.GET("/tokens/:id/holds", tokenHandler.GetTokenHolds)
+		v1.GET("/tokens/:id/state-at", http.RequireRole("admin"), loadState.Priority(http.PriorityLow), tokenHandler.GetTokenStateAt)
+		v1.PATCH("/tokens/:id/compliance", http.RequireAnyRole("compliance", "admin"), tokenHandler.UpdateComplianceFlags)
 		
 		// Wallet endpoints
 		v1.GET("/wallets/:id/tokens", tokenHandler.GetWalletTokens)
+		v1.GET("/wallets/:id/holdings", tokenHandler.GetWalletHoldings)
+		v1.GET("/wallets/:id/select", tokenHandler.SelectTokens)
+		v1.PUT("/wallets/:id/signing-policy", http.RequireRole("admin"), tokenHandler.SetWalletSigningPolicy)
+		v1Long.POST("/wallets/:id/freeze", tokenHandler.FreezeWalletTokens)
+		v1Long.POST("/wallets/:id/unfreeze", tokenHandler.UnfreezeWalletTokens)
+		v1Long.POST("/wallets/:id/consolidate", tokenHandler.ConsolidateWalletTokens)
+		
+		// Multi-sig transfer approvals
+		v1.POST("/transfers/:id/approve", tokenHandler.ApproveTransfer)
 		
 		// Ownership verification
 		v1.GET("/tokens/:id/verify/:owner", tokenHandler.VerifyOwnership)
+		v1.GET("/tokens/:id/verify-proof", tokenHandler.VerifyTokenProof)
+		v1.GET("/tokens/:id/verify-signature", tokenHandler.VerifyTokenSignature)
+		
+		// Bulk operations (for reversibility service), which touch many tokens
+		v1Long.POST("/tokens/bulk/status", loadState.Priority(http.PriorityCritical), http.MaxBodyBytes(http.BulkMaxBodyBytes), tokenHandler.BulkUpdateStatus)
+		v1Long.POST("/tokens/bulk/transfer", http.MaxBodyBytes(http.BulkMaxBodyBytes), tokenHandler.BulkTransferOwnership)
+		v1.GET("/tokens/status/:status", loadState.Priority(http.PriorityLow), tokenHandler.GetTokensByStatus)
+		v1.GET("/tokens/cbdc/:type", loadState.Priority(http.PriorityLow), tokenHandler.GetTokensByCBDCType)
+		
+		// Issuer operations
+		v1Long.POST("/tokens/recall", http.RequireAnyRole("issuer", "admin"), tokenHandler.RecallSeries)
+		v1.GET("/issuers/:issuer/quota", tokenHandler.GetIssuerQuota)
+		v1.PUT("/issuers/:issuer/quota", http.RequireRole("admin"), tokenHandler.SetIssuerQuota)
+		v1.POST("/issuers/:issuer/keys", http.RequireRole("admin"), tokenHandler.RotateIssuerKey)
+		
+		// Compliance reporting
+		v1Long.GET("/reports/freezes", loadState.Priority(http.PriorityLow), tokenHandler.GetFreezeReport)
+		// The export streams its response as it is read, so it has no request timeout
+		api.GET("/reports/audit-export", loadState.Priority(http.PriorityLow), tokenHandler.ExportAuditLog)
 		
-		// Bulk operations (for reversibility service)
-		v1.POST("/tokens/bulk/status", tokenHandler.BulkUpdateStatus)
-		v1.GET("/tokens/status/:status", tokenHandler.GetTokensByStatus)
-		v1.GET("/tokens/cbdc/:type", tokenHandler.GetTokensByCBDCType)
+		// Webhook endpoints
+		v1.POST("/webhooks", http.RequireRole("admin"), webhooks.RegisterHandler(webhookDispatcher))
+		v1.GET("/webhooks/deliveries", http.RequireRole("admin"), webhooks.DeliveriesHandler(webhookDispatcher))
 	}
 	
 	logger.Info("Token Management Service starting", "port", cfg.Port, "environment", cfg.Environment)
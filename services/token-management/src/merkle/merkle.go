@@ -0,0 +1,142 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Domain separation prefixes prevent a leaf from being reinterpreted as an internal node
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// ProofStep is a single sibling hash on the path from a leaf to the root
+type ProofStep struct {
+	Hash string `json:"hash"` // Hex-encoded sibling hash
+	Left bool   `json:"left"` // Whether the sibling sits to the left of the running hash
+}
+
+// Tree is a binary Merkle tree built over a batch of leaves
+type Tree struct {
+	levels [][][]byte // levels[0] holds the leaf hashes, the last level holds the root
+}
+
+// NewTree builds a Merkle tree over the given leaf data. When a level has an odd
+// number of nodes, the last node is paired with itself.
+func NewTree(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("merkle tree requires at least one leaf")
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = HashLeaf(leaf)
+	}
+
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, hashNode(level[i], right))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &Tree{levels: levels}, nil
+}
+
+// Root returns the root hash of the tree
+func (t *Tree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// RootHex returns the hex-encoded root hash of the tree
+func (t *Tree) RootHex() string {
+	return hex.EncodeToString(t.Root())
+}
+
+// Proof returns the sibling path for the leaf at index
+func (t *Tree) Proof(index int) ([]ProofStep, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, fmt.Errorf("leaf index %d out of range", index)
+	}
+
+	var proof []ProofStep
+	for _, level := range t.levels[:len(t.levels)-1] {
+		var sibling []byte
+		left := index%2 == 1
+		if left {
+			sibling = level[index-1]
+		} else if index+1 < len(level) {
+			sibling = level[index+1]
+		} else {
+			sibling = level[index]
+		}
+
+		proof = append(proof, ProofStep{
+			Hash: hex.EncodeToString(sibling),
+			Left: left,
+		})
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// ComputeRoot recomputes the root hash from leaf data and its proof path
+func ComputeRoot(leaf []byte, proof []ProofStep) ([]byte, error) {
+	current := HashLeaf(leaf)
+	for i, step := range proof {
+		sibling, err := hex.DecodeString(step.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash at proof step %d: %w", i, err)
+		}
+
+		if step.Left {
+			current = hashNode(sibling, current)
+		} else {
+			current = hashNode(current, sibling)
+		}
+	}
+
+	return current, nil
+}
+
+// Verify reports whether the leaf data and proof path produce the expected hex-encoded root
+func Verify(leaf []byte, proof []ProofStep, rootHex string) (bool, error) {
+	expected, err := hex.DecodeString(rootHex)
+	if err != nil {
+		return false, fmt.Errorf("invalid root hash: %w", err)
+	}
+
+	computed, err := ComputeRoot(leaf, proof)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(computed, expected), nil
+}
+
+// HashLeaf hashes leaf data with the leaf domain prefix
+func HashLeaf(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func hashNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,60 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_ProofsVerifyAgainstRoot(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 5, 8, 13} {
+		t.Run(fmt.Sprintf("%d leaves", size), func(t *testing.T) {
+			leaves := make([][]byte, size)
+			for i := range leaves {
+				leaves[i] = []byte(fmt.Sprintf("leaf-%d", i))
+			}
+
+			tree, err := NewTree(leaves)
+			require.NoError(t, err)
+
+			for i, leaf := range leaves {
+				proof, err := tree.Proof(i)
+				require.NoError(t, err)
+
+				valid, err := Verify(leaf, proof, tree.RootHex())
+				require.NoError(t, err)
+				assert.True(t, valid, "proof for leaf %d should verify", i)
+			}
+		})
+	}
+}
+
+func TestTree_TamperedLeafFailsVerification(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	tree, err := NewTree(leaves)
+	require.NoError(t, err)
+
+	proof, err := tree.Proof(1)
+	require.NoError(t, err)
+
+	valid, err := Verify([]byte("tampered"), proof, tree.RootHex())
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestTree_Errors(t *testing.T) {
+	_, err := NewTree(nil)
+	assert.Error(t, err)
+
+	tree, err := NewTree([][]byte{[]byte("a")})
+	require.NoError(t, err)
+
+	_, err = tree.Proof(1)
+	assert.Error(t, err)
+
+	_, err = Verify([]byte("a"), []ProofStep{{Hash: "zz"}}, tree.RootHex())
+	assert.Error(t, err)
+}
@@ -6,6 +6,22 @@ func GetTokenMigrations() []string {
 		createTokensTable,
 		createTokenAuditTrailTable,
 		createTokenIndexes,
+		createTokenSeriesIndex,
+		createTokenMerkleTables,
+		createTokenSignaturesTable,
+		createMultiSigTransferTables,
+		createIssuerQuotasTable,
+		addAuditTrailHashChain,
+		addTokenVersionColumn,
+		addTokenFrozenUntilColumn,
+		addFreezeReportIndex,
+		createTokenHistoryTable,
+		createTokenAuditArchiveTable,
+		addTokenChecksumColumn,
+		createIssuerSigningKeysTable,
+		addSignatureIssuedToColumn,
+		createWalletCoSignersTable,
+		createTransferNoncesTable,
 	}
 }
 
@@ -106,4 +122,270 @@ CREATE INDEX IF NOT EXISTS idx_tokens_metadata ON tokens USING GIN(metadata);
 
 -- GIN index for compliance flags JSON queries
 CREATE INDEX IF NOT EXISTS idx_tokens_compliance_flags ON tokens USING GIN(compliance_flags);
-`
\ No newline at end of file
+`
+// createTokenSeriesIndex supports issuer-initiated series recalls
+const createTokenSeriesIndex = `
+-- Expression index for active tokens by issuer and series (recalls)
+CREATE INDEX IF NOT EXISTS idx_tokens_issuer_series ON tokens ((metadata->>'issuer'), (metadata->>'series'), token_id)
+    WHERE status = 'active';
+`
+
+// createTokenMerkleTables creates the tables holding issuance batch Merkle roots and proofs
+const createTokenMerkleTables = `
+CREATE TABLE IF NOT EXISTS token_merkle_batches (
+    batch_id UUID PRIMARY KEY,
+    root VARCHAR(64) NOT NULL,
+    token_count INTEGER NOT NULL CHECK (token_count > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS token_merkle_proofs (
+    token_id UUID PRIMARY KEY,
+    batch_id UUID NOT NULL,
+    leaf_index INTEGER NOT NULL,
+    path JSONB NOT NULL DEFAULT '[]'::jsonb,
+    
+    CONSTRAINT fk_token_merkle_proofs_token_id
+        FOREIGN KEY (token_id)
+        REFERENCES tokens(token_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_token_merkle_proofs_batch_id
+        FOREIGN KEY (batch_id)
+        REFERENCES token_merkle_batches(batch_id)
+);
+
+COMMENT ON TABLE token_merkle_batches IS 'Merkle roots for batches of issued tokens';
+COMMENT ON TABLE token_merkle_proofs IS 'Per-token inclusion proof paths against the issuance batch root';
+
+CREATE INDEX IF NOT EXISTS idx_token_merkle_proofs_batch_id ON token_merkle_proofs(batch_id);
+`
+
+// createTokenSignaturesTable creates the table holding issuer signatures over issued tokens
+const createTokenSignaturesTable = `
+CREATE TABLE IF NOT EXISTS token_signatures (
+    token_id UUID PRIMARY KEY,
+    key_id VARCHAR(100) NOT NULL,
+    algorithm VARCHAR(20) NOT NULL,
+    signature BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    CONSTRAINT fk_token_signatures_token_id
+        FOREIGN KEY (token_id)
+        REFERENCES tokens(token_id)
+        ON DELETE CASCADE
+);
+
+COMMENT ON TABLE token_signatures IS 'Issuer signatures over tokens at issuance';
+COMMENT ON COLUMN token_signatures.key_id IS 'Identifier of the issuer key used, so signatures survive key rotation';
+
+CREATE INDEX IF NOT EXISTS idx_token_signatures_key_id ON token_signatures(key_id);
+`
+
+// createMultiSigTransferTables creates the tables for co-signer approval of wallet transfers
+const createMultiSigTransferTables = `
+CREATE TABLE IF NOT EXISTS wallet_signing_policies (
+    wallet_id UUID PRIMARY KEY,
+    required_signers INTEGER NOT NULL CHECK (required_signers > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS pending_transfers (
+    id UUID PRIMARY KEY,
+    token_id UUID NOT NULL,
+    from_owner UUID NOT NULL,
+    new_owner UUID NOT NULL,
+    transaction_id UUID NOT NULL,
+    required_signers INTEGER NOT NULL CHECK (required_signers > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'completed', 'rejected')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    CONSTRAINT fk_pending_transfers_token_id
+        FOREIGN KEY (token_id)
+        REFERENCES tokens(token_id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pending_transfer_approvals (
+    pending_transfer_id UUID NOT NULL,
+    signer_id UUID NOT NULL,
+    approved_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    PRIMARY KEY (pending_transfer_id, signer_id),
+    CONSTRAINT fk_pending_transfer_approvals_transfer_id
+        FOREIGN KEY (pending_transfer_id)
+        REFERENCES pending_transfers(id)
+        ON DELETE CASCADE
+);
+
+COMMENT ON TABLE wallet_signing_policies IS 'Wallets requiring co-signer approval for outgoing token transfers';
+COMMENT ON TABLE pending_transfers IS 'Token transfers awaiting co-signer approval';
+COMMENT ON TABLE pending_transfer_approvals IS 'Distinct co-signer approvals for pending transfers';
+
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_token_id ON pending_transfers(token_id);
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_status ON pending_transfers(status);
+`
+
+// createIssuerQuotasTable creates the per-issuer issuance quota table
+const createIssuerQuotasTable = `
+CREATE TABLE IF NOT EXISTS issuer_quotas (
+    issuer VARCHAR(255) NOT NULL,
+    series VARCHAR(100) NOT NULL,
+    cbdc_type VARCHAR(50) NOT NULL,
+    minted_total DECIMAL(20,2) NOT NULL DEFAULT 0 CHECK (minted_total >= 0),
+    quota DECIMAL(20,2) NOT NULL CHECK (quota >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    PRIMARY KEY (issuer, series, cbdc_type)
+);
+
+COMMENT ON TABLE issuer_quotas IS 'Maximum value each issuer may mint per series and CBDC type';
+COMMENT ON COLUMN issuer_quotas.minted_total IS 'Running total of value minted against the quota';
+`
+
+// addAuditTrailHashChain adds hash chain columns to the token audit trail. Rows written
+// before this migration keep NULL hashes and are reported as unsigned during verification.
+const addAuditTrailHashChain = `
+ALTER TABLE token_audit_trail ADD COLUMN IF NOT EXISTS sequence BIGINT;
+ALTER TABLE token_audit_trail ADD COLUMN IF NOT EXISTS previous_hash VARCHAR(64);
+ALTER TABLE token_audit_trail ADD COLUMN IF NOT EXISTS entry_hash VARCHAR(64);
+
+COMMENT ON COLUMN token_audit_trail.sequence IS 'Position of the entry in the token''s audit hash chain';
+COMMENT ON COLUMN token_audit_trail.previous_hash IS 'Hash of the preceding entry in the chain, empty for the first entry';
+COMMENT ON COLUMN token_audit_trail.entry_hash IS 'SHA-256 over the previous hash and this entry''s operation fields';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_token_audit_trail_chain ON token_audit_trail(token_id, sequence);
+`
+
+// addTokenVersionColumn adds the optimistic concurrency version to tokens. Existing rows
+// start at version 1.
+const addTokenVersionColumn = `
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN tokens.version IS 'Incremented on every update; writes must match the version they read';
+`
+
+// addTokenFrozenUntilColumn adds the expiry for time-limited freezes. Tokens frozen without a
+// duration keep a NULL expiry and stay frozen until unfrozen manually.
+const addTokenFrozenUntilColumn = `
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS frozen_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN tokens.frozen_until IS 'When a frozen token is automatically unfrozen, NULL for indefinite freezes';
+
+CREATE INDEX IF NOT EXISTS idx_tokens_frozen_until ON tokens(frozen_until) WHERE frozen_until IS NOT NULL;
+`
+
+// addFreezeReportIndex supports range scans over freezes in a reporting period
+const addFreezeReportIndex = `
+CREATE INDEX IF NOT EXISTS idx_token_audit_freezes ON token_audit_trail(timestamp) WHERE new_status = 'frozen';
+`
+
+// createTokenHistoryTable creates the archive for transaction history entries trimmed from a
+// token row once its in-row history exceeds the configured limit
+const createTokenHistoryTable = `
+CREATE TABLE IF NOT EXISTS token_history (
+    token_id UUID NOT NULL REFERENCES tokens(token_id) ON DELETE CASCADE,
+    position BIGINT NOT NULL,
+    transaction_id UUID NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    PRIMARY KEY (token_id, position)
+);
+
+COMMENT ON TABLE token_history IS 'Older transaction history entries moved out of tokens.transaction_history';
+COMMENT ON COLUMN token_history.position IS 'Position of the entry in the token''s full transaction history';
+`
+
+// createTokenAuditArchiveTable creates the archive for audit entries past the retention
+// window. Archived entries keep their chain columns, so a token's chain can be verified
+// across the archive boundary.
+const createTokenAuditArchiveTable = `
+CREATE TABLE IF NOT EXISTS token_audit_trail_archive (
+    id UUID PRIMARY KEY,
+    token_id UUID NOT NULL REFERENCES tokens(token_id) ON DELETE CASCADE,
+    operation VARCHAR(50) NOT NULL,
+    old_status VARCHAR(20),
+    new_status VARCHAR(20),
+    old_owner UUID,
+    new_owner UUID,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    metadata JSONB DEFAULT '{}'::jsonb,
+    sequence BIGINT,
+    previous_hash VARCHAR(64),
+    entry_hash VARCHAR(64),
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE token_audit_trail_archive IS 'Audit entries moved out of token_audit_trail once past the retention window';
+COMMENT ON COLUMN token_audit_trail_archive.archived_at IS 'When the entry was moved to the archive';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_token_audit_archive_chain ON token_audit_trail_archive(token_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_token_audit_archive_token_timestamp ON token_audit_trail_archive(token_id, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_token_audit_archive_timestamp ON token_audit_trail_archive(timestamp);
+`
+
+// addTokenChecksumColumn stores the checksum of each token's canonical form, recomputed on
+// every write and verified on read. Tokens written before it was added have a NULL checksum
+// until their next update.
+const addTokenChecksumColumn = `
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS checksum VARCHAR(64);
+
+COMMENT ON COLUMN tokens.checksum IS 'SHA-256 of the token''s canonical form, checked when the token is read';
+`
+
+// createIssuerSigningKeysTable records the public half of each issuer signing key added
+// through rotation, so signatures made with it stay verifiable after the key is retired.
+// Private keys are never stored.
+const createIssuerSigningKeysTable = `
+CREATE TABLE IF NOT EXISTS issuer_signing_keys (
+    key_id VARCHAR(100) PRIMARY KEY,
+    issuer VARCHAR(255) NOT NULL,
+    algorithm VARCHAR(20) NOT NULL,
+    public_key BYTEA NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'retired')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE issuer_signing_keys IS 'Issuer signing keys added by rotation; retired keys are kept for verification';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_issuer_signing_keys_active ON issuer_signing_keys(issuer) WHERE status = 'active';
+`
+
+// addSignatureIssuedToColumn records the owner a token was issued to alongside its signature,
+// since the signature covers the issuance owner and the token's current owner changes with
+// every transfer. Signatures stored before it was added have a NULL owner and are checked
+// against the current owner.
+const addSignatureIssuedToColumn = `
+ALTER TABLE token_signatures ADD COLUMN IF NOT EXISTS issued_to UUID;
+
+COMMENT ON COLUMN token_signatures.issued_to IS 'Owner the token was issued to, as covered by the signature';
+`
+
+// createWalletCoSignersTable lists the co-signers authorized to approve each multi-sig
+// wallet's transfers. They are removed with the wallet's signing policy.
+const createWalletCoSignersTable = `
+CREATE TABLE IF NOT EXISTS wallet_co_signers (
+    wallet_id UUID NOT NULL REFERENCES wallet_signing_policies(wallet_id) ON DELETE CASCADE,
+    signer_id UUID NOT NULL,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    
+    PRIMARY KEY (wallet_id, signer_id)
+);
+
+COMMENT ON TABLE wallet_co_signers IS 'Co-signers authorized to approve a multi-sig wallet''s transfers';
+`
+
+// createTransferNoncesTable records the transfer nonces each owner has used, so replays are
+// rejected by every service instance
+const createTransferNoncesTable = `
+CREATE TABLE IF NOT EXISTS transfer_nonces (
+    owner_id UUID NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    
+    PRIMARY KEY (owner_id, nonce)
+);
+
+COMMENT ON TABLE transfer_nonces IS 'Transfer nonces used by each owner, kept until a replay would be rejected as stale';
+`
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// auditArchiveBatchSize is the number of audit entries moved per archival transaction
+const auditArchiveBatchSize = 500
+
+// ArchiveAuditTrail moves audit entries recorded before the cutoff into
+// token_audit_trail_archive and returns how many were moved. Only a contiguous prefix of each
+// token's hash chain is archived: an entry moves only if every earlier entry of its token is
+// also older than the cutoff, and each token's latest entry always stays, so new entries keep
+// chaining from it. Each batch is moved in its own database transaction.
+func (r *tokenRepository) ArchiveAuditTrail(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+	for {
+		archived, err := r.archiveAuditBatch(ctx, before, auditArchiveBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += archived
+		if archived < auditArchiveBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// archiveAuditBatch archives up to limit audit entries in a single database transaction.
+// Entries are taken in chain order, so a token's prefix split across batches stays contiguous.
+func (r *tokenRepository) archiveAuditBatch(ctx context.Context, before time.Time, limit int) (int, error) {
+	var archived int
+	err := r.db.TransactionContext(ctx, func(tx *sql.Tx) error {
+		// Entries written before hash chaining have no sequence and are archived on age alone
+		rows, err := tx.QueryContext(ctx, `
+			SELECT e.id FROM token_audit_trail e
+			WHERE e.timestamp < $1
+			  AND (e.sequence IS NULL OR (
+				e.sequence < (SELECT MAX(head.sequence) FROM token_audit_trail head WHERE head.token_id = e.token_id)
+				AND NOT EXISTS (
+					SELECT 1 FROM token_audit_trail newer
+					WHERE newer.token_id = e.token_id AND newer.sequence < e.sequence AND newer.timestamp >= $1
+				)
+			  ))
+			ORDER BY e.token_id, e.sequence ASC NULLS FIRST
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		`, before, limit)
+		if err != nil {
+			return err
+		}
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		batch := pq.Array(ids)
+		statements := []string{
+			`INSERT INTO token_audit_trail_archive (
+				id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				sequence, previous_hash, entry_hash, archived_at
+			)
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   sequence, previous_hash, entry_hash, NOW()
+			FROM token_audit_trail WHERE id = ANY($1)`,
+			`DELETE FROM token_audit_trail WHERE id = ANY($1)`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement, batch); err != nil {
+				return err
+			}
+		}
+
+		archived = len(ids)
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive audit entries: %w", err)
+	}
+	return archived, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+)
+
+func TestTokenRepository_AuditReadsAfterArchive(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tokenID := uuid.New()
+	err := repo.Create(ctx, &models.Token{
+		TokenID:            tokenID,
+		CBDCType:           models.CBDCTypeUSD,
+		Denomination:       100.0,
+		CurrentOwner:       uuid.New(),
+		Status:             models.TokenStatusActive,
+		IssueTimestamp:     start,
+		TransactionHistory: make(models.UUIDArray, 0),
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	})
+	require.NoError(t, err)
+	defer func() {
+		db.Exec(`DELETE FROM token_audit_trail WHERE token_id = $1`, tokenID)
+		db.Exec(`DELETE FROM token_audit_trail_archive WHERE token_id = $1`, tokenID)
+		db.Exec(`DELETE FROM tokens WHERE token_id = $1`, tokenID)
+	}()
+
+	// Three entries fall before the cutoff and are archived; the fourth stays live
+	for i := 1; i <= 4; i++ {
+		offset := time.Duration(i) * time.Hour
+		if i == 4 {
+			offset = 72 * time.Hour
+		}
+		_, err := db.Exec(`
+			INSERT INTO token_audit_trail (id, token_id, operation, timestamp, metadata)
+			VALUES ($1, $2, 'STATUS_CHANGE', $3, '{}'::jsonb)`,
+			uuid.New(), tokenID, start.Add(offset))
+		require.NoError(t, err)
+	}
+
+	archived, err := repo.ArchiveAuditTrail(ctx, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, archived, 3)
+
+	countArchived := func(entries []TokenAuditEntry) (total, archived int) {
+		for _, entry := range entries {
+			if entry.TokenID != tokenID {
+				continue
+			}
+			total++
+			if entry.Archived {
+				archived++
+			}
+		}
+		return total, archived
+	}
+
+	t.Run("export includes archived entries", func(t *testing.T) {
+		entries, err := repo.GetAuditEntriesBetween(ctx, start, start.Add(96*time.Hour), start, uuid.Nil, 1000)
+		require.NoError(t, err)
+
+		total, archived := countArchived(entries)
+		assert.Equal(t, 4, total)
+		assert.Equal(t, 3, archived)
+		for i := 1; i < len(entries); i++ {
+			assert.False(t, entries[i].Timestamp.Time.Before(entries[i-1].Timestamp.Time))
+		}
+	})
+
+	t.Run("audit trail includes archived entries after live ones", func(t *testing.T) {
+		trail, err := repo.GetAuditTrail(ctx, tokenID)
+		require.NoError(t, err)
+
+		_, archived := countArchived(trail)
+		assert.Equal(t, 3, archived)
+		require.NotEmpty(t, trail)
+		assert.False(t, trail[0].Archived)
+		assert.True(t, trail[len(trail)-1].Archived)
+	})
+
+	t.Run("batch audit trails include archived entries", func(t *testing.T) {
+		trails, err := repo.GetAuditTrails(ctx, []uuid.UUID{tokenID})
+		require.NoError(t, err)
+
+		_, archived := countArchived(trails[tokenID])
+		assert.Equal(t, 3, archived)
+	})
+}
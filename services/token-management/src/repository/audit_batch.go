@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetAuditTrails retrieves the audit trails of several tokens in one query, keyed by token ID.
+// Each trail is newest first and includes archived entries, as from GetAuditTrail. Tokens with
+// no entries are absent from the map.
+func (r *tokenRepository) GetAuditTrails(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID][]TokenAuditEntry, error) {
+	trails := make(map[uuid.UUID][]TokenAuditEntry, len(tokenIDs))
+	if len(tokenIDs) == 0 {
+		return trails, nil
+	}
+
+	query := `
+		SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata, archived
+		FROM (
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   FALSE AS archived
+			FROM token_audit_trail
+			WHERE token_id = ANY($1)
+			UNION ALL
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   TRUE AS archived
+			FROM token_audit_trail_archive
+			WHERE token_id = ANY($1)
+		) trails
+		ORDER BY token_id, timestamp DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(tokenIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit trails: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry TokenAuditEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TokenID,
+			&entry.Operation,
+			&entry.OldStatus,
+			&entry.NewStatus,
+			&entry.OldOwner,
+			&entry.NewOwner,
+			&entry.Timestamp,
+			&entry.Metadata,
+			&entry.Archived,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		trails[entry.TokenID] = append(trails[entry.TokenID], entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit trail rows: %w", err)
+	}
+
+	return trails, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+)
+
+func TestTokenRepository_GetAuditTrails(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tokenIDs := make([]uuid.UUID, 4)
+	for i := range tokenIDs {
+		tokenIDs[i] = uuid.New()
+		err := repo.Create(ctx, &models.Token{
+			TokenID:            tokenIDs[i],
+			CBDCType:           models.CBDCTypeUSD,
+			Denomination:       100.0,
+			CurrentOwner:       uuid.New(),
+			Status:             models.TokenStatusActive,
+			IssueTimestamp:     start,
+			TransactionHistory: make(models.UUIDArray, 0),
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	defer func() {
+		for _, tokenID := range tokenIDs {
+			db.Exec(`DELETE FROM token_audit_trail WHERE token_id = $1`, tokenID)
+			db.Exec(`DELETE FROM tokens WHERE token_id = $1`, tokenID)
+		}
+	}()
+
+	// Each of the first three tokens gets a different number of entries, seeded out of order;
+	// the last token has none
+	for i, tokenID := range tokenIDs[:3] {
+		for j := i + 2; j > 0; j-- {
+			_, err := db.Exec(`
+				INSERT INTO token_audit_trail (id, token_id, operation, timestamp, metadata)
+				VALUES ($1, $2, 'STATUS_CHANGE', $3, '{}'::jsonb)`,
+				uuid.New(), tokenID, start.Add(time.Duration(j)*time.Hour))
+			require.NoError(t, err)
+		}
+	}
+
+	trails, err := repo.GetAuditTrails(ctx, tokenIDs)
+	require.NoError(t, err)
+
+	assert.Len(t, trails, 3)
+	assert.NotContains(t, trails, tokenIDs[3])
+	for i, tokenID := range tokenIDs[:3] {
+		trail := trails[tokenID]
+		require.Len(t, trail, i+2)
+		for j, entry := range trail {
+			assert.Equal(t, tokenID, entry.TokenID)
+			if j > 0 {
+				assert.True(t, trail[j-1].Timestamp.Time.After(entry.Timestamp.Time), "trail should be newest first")
+			}
+		}
+	}
+
+	empty, err := repo.GetAuditTrails(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// auditHashInput is the canonical form of an audit entry that is hashed into the chain
+type auditHashInput struct {
+	PreviousHash string      `json:"previous_hash"`
+	Sequence     int64       `json:"sequence"`
+	ID           string      `json:"id"`
+	TokenID      string      `json:"token_id"`
+	Operation    string      `json:"operation"`
+	OldStatus    string      `json:"old_status"`
+	NewStatus    string      `json:"new_status"`
+	OldOwner     string      `json:"old_owner"`
+	NewOwner     string      `json:"new_owner"`
+	Timestamp    string      `json:"timestamp"`
+	Metadata     interface{} `json:"metadata"`
+}
+
+// AuditEntryHash computes the hex-encoded SHA-256 hash of an audit entry over the previous
+// entry's hash and the entry's operation fields. Metadata is canonicalized through a JSON
+// round trip so the hash is stable after the value has been stored as JSONB and read back.
+func AuditEntryHash(entry TokenAuditEntry) (string, error) {
+	metadata, err := canonicalAuditMetadata(entry.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	input, err := json.Marshal(auditHashInput{
+		PreviousHash: entry.PreviousHash,
+		Sequence:     entry.Sequence,
+		ID:           entry.ID.String(),
+		TokenID:      entry.TokenID.String(),
+		Operation:    entry.Operation,
+		OldStatus:    string(entry.OldStatus),
+		NewStatus:    string(entry.NewStatus),
+		OldOwner:     entry.OldOwner.String(),
+		NewOwner:     entry.NewOwner.String(),
+		Timestamp:    entry.Timestamp.Time.UTC().Format(time.RFC3339Nano),
+		Metadata:     metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	sum := sha256.Sum256(input)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalAuditMetadata converts metadata to generic JSON values so struct values and maps
+// that encode identically hash identically. Empty metadata hashes as null.
+func canonicalAuditMetadata(metadata map[string]interface{}) (interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	var canonical interface{}
+	if err := json.Unmarshal(encoded, &canonical); err != nil {
+		return nil, fmt.Errorf("failed to decode audit metadata: %w", err)
+	}
+	return canonical, nil
+}
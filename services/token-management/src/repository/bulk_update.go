@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/token-management/src/models"
+)
+
+const (
+	// DefaultBulkBatchSize is the number of token IDs BulkUpdateStatus updates per statement
+	DefaultBulkBatchSize = 500
+	// MaxBulkBatchSize keeps a batch's audit insert under Postgres' limit of 65535 bind
+	// parameters, at 12 per entry
+	MaxBulkBatchSize = 5000
+	// DefaultBulkAuditWorkers is the number of workers preparing a batch's audit entries
+	DefaultBulkAuditWorkers = 4
+)
+
+// auditChainHead is the latest chained audit entry of a token
+type auditChainHead struct {
+	sequence  int64
+	entryHash string
+}
+
+// SetBulkUpdateOptions sets how many token IDs BulkUpdateStatus updates per statement and
+// how many workers prepare each batch's audit entries. Values out of range fall back to the
+// defaults, and batch sizes above MaxBulkBatchSize are capped.
+func (r *tokenRepository) SetBulkUpdateOptions(batchSize, auditWorkers int) {
+	if batchSize <= 0 {
+		batchSize = DefaultBulkBatchSize
+	}
+	if batchSize > MaxBulkBatchSize {
+		batchSize = MaxBulkBatchSize
+	}
+	if auditWorkers <= 0 {
+		auditWorkers = DefaultBulkAuditWorkers
+	}
+
+	r.bulkBatchSize = batchSize
+	r.bulkAuditWorkers = auditWorkers
+}
+
+// bulkUpdateBatchWithTx updates the status of one batch of tokens and appends a chained audit
+// entry for each of them
+func (r *tokenRepository) bulkUpdateBatchWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, status models.TokenStatus, auditMetadata map[string]interface{}, timestamp time.Time) error {
+	// Build placeholders for IN clause
+	placeholders := make([]string, len(tokenIDs))
+	args := make([]interface{}, len(tokenIDs)+1)
+
+	for i, tokenID := range tokenIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = tokenID
+	}
+	args[len(tokenIDs)] = status
+
+	// Bulk status changes are indefinite, so any freeze expiry is cleared
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET status = $%d, frozen_until = NULL, updated_at = NOW(), version = version + 1
+		WHERE token_id IN (%s)`,
+		len(tokenIDs)+1,
+		strings.Join(placeholders, ","),
+	)
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to bulk update token status: %w", err)
+	}
+
+	if err := r.refreshChecksums(ctx, tx, tokenIDs); err != nil {
+		return err
+	}
+
+	heads, err := r.auditChainHeadsWithTx(ctx, tx, tokenIDs)
+	if err != nil {
+		return err
+	}
+
+	template := TokenAuditEntry{
+		Operation: "BULK_STATUS_UPDATE",
+		NewStatus: status,
+		Timestamp: sql.NullTime{Time: timestamp, Valid: true},
+		Metadata:  auditMetadata,
+	}
+	entries, err := prepareAuditEntries(tokenIDs, heads, template, r.bulkAuditWorkers)
+	if err != nil {
+		return err
+	}
+
+	return insertAuditEntriesWithTx(ctx, tx, entries)
+}
+
+// auditChainHeadsWithTx locks and returns the latest chained audit entry of each token, as
+// createAuditEntry does for a single token. Tokens with no chained entries are left out.
+func (r *tokenRepository) auditChainHeadsWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID) (map[uuid.UUID]auditChainHead, error) {
+	ids := make([]string, len(tokenIDs))
+	for i, tokenID := range tokenIDs {
+		ids[i] = tokenID.String()
+	}
+
+	query := `
+		SELECT a.token_id, a.sequence, a.entry_hash
+		FROM token_audit_trail a
+		WHERE a.token_id = ANY($1) AND a.entry_hash IS NOT NULL
+			AND a.sequence = (
+				SELECT MAX(b.sequence)
+				FROM token_audit_trail b
+				WHERE b.token_id = a.token_id AND b.entry_hash IS NOT NULL
+			)
+		FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	heads := make(map[uuid.UUID]auditChainHead, len(tokenIDs))
+	for rows.Next() {
+		var tokenID uuid.UUID
+		var head auditChainHead
+		if err := rows.Scan(&tokenID, &head.sequence, &head.entryHash); err != nil {
+			return nil, fmt.Errorf("failed to scan previous audit entry: %w", err)
+		}
+		heads[tokenID] = head
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating previous audit entries: %w", err)
+	}
+
+	return heads, nil
+}
+
+// prepareAuditEntries builds a chained audit entry for each token from template, hashing them
+// on a bounded pool of workers. Statements on a transaction share its single connection, so
+// only this preparation runs concurrently; the entries are then written in one insert.
+func prepareAuditEntries(tokenIDs []uuid.UUID, heads map[uuid.UUID]auditChainHead, template TokenAuditEntry, workers int) ([]TokenAuditEntry, error) {
+	if workers <= 0 {
+		workers = DefaultBulkAuditWorkers
+	}
+	if workers > len(tokenIDs) {
+		workers = len(tokenIDs)
+	}
+
+	entries := make([]TokenAuditEntry, len(tokenIDs))
+	errs := make([]error, len(tokenIDs))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				head := heads[tokenIDs[i]]
+
+				entry := template
+				entry.ID = uuid.New()
+				entry.TokenID = tokenIDs[i]
+				entry.Sequence = head.sequence + 1
+				entry.PreviousHash = head.entryHash
+
+				entry.EntryHash, errs[i] = AuditEntryHash(entry)
+				entries[i] = entry
+			}
+		}()
+	}
+
+	for i := range tokenIDs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash audit entry for token %s: %w", tokenIDs[i], err)
+		}
+	}
+
+	return entries, nil
+}
+
+// insertAuditEntriesWithTx writes audit entries in a single multi-row insert
+func insertAuditEntriesWithTx(ctx context.Context, tx *sql.Tx, entries []TokenAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	const columns = 12
+	rows := make([]string, len(entries))
+	args := make([]interface{}, 0, len(entries)*columns)
+
+	for i, entry := range entries {
+		metadata, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit metadata: %w", err)
+		}
+
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		rows[i] = "(" + strings.Join(placeholders, ", ") + ")"
+
+		args = append(args,
+			entry.ID,
+			entry.TokenID,
+			entry.Operation,
+			entry.OldStatus,
+			entry.NewStatus,
+			entry.OldOwner,
+			entry.NewOwner,
+			entry.Timestamp.Time,
+			metadata,
+			entry.Sequence,
+			entry.PreviousHash,
+			entry.EntryHash,
+		)
+	}
+
+	query := `
+		INSERT INTO token_audit_trail (
+			id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+			sequence, previous_hash, entry_hash
+		) VALUES ` + strings.Join(rows, ",\n\t\t\t")
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create bulk update audit entries: %w", err)
+	}
+
+	return nil
+}
+
+// chunkTokenIDs splits token IDs into consecutive batches of at most size IDs
+func chunkTokenIDs(tokenIDs []uuid.UUID, size int) [][]uuid.UUID {
+	if size <= 0 {
+		size = DefaultBulkBatchSize
+	}
+
+	chunks := make([][]uuid.UUID, 0, (len(tokenIDs)+size-1)/size)
+	for start := 0; start < len(tokenIDs); start += size {
+		end := start + size
+		if end > len(tokenIDs) {
+			end = len(tokenIDs)
+		}
+		chunks = append(chunks, tokenIDs[start:end])
+	}
+	return chunks
+}
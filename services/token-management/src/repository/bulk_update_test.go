@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+)
+
+func TestChunkTokenIDs(t *testing.T) {
+	tokenIDs := make([]uuid.UUID, 7)
+	for i := range tokenIDs {
+		tokenIDs[i] = uuid.New()
+	}
+
+	chunks := chunkTokenIDs(tokenIDs, 3)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, tokenIDs[0:3], chunks[0])
+	assert.Equal(t, tokenIDs[3:6], chunks[1])
+	assert.Equal(t, tokenIDs[6:], chunks[2])
+
+	assert.Len(t, chunkTokenIDs(tokenIDs, 0), 1)
+	assert.Empty(t, chunkTokenIDs(nil, 3))
+}
+
+func TestPrepareAuditEntries(t *testing.T) {
+	chained := uuid.New()
+	fresh := uuid.New()
+	heads := map[uuid.UUID]auditChainHead{
+		chained: {sequence: 4, entryHash: "previous"},
+	}
+	template := TokenAuditEntry{
+		Operation: "BULK_STATUS_UPDATE",
+		NewStatus: models.TokenStatusFrozen,
+		Timestamp: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		Metadata:  map[string]interface{}{"bulk_operation": true},
+	}
+
+	// More workers than entries
+	entries, err := prepareAuditEntries([]uuid.UUID{chained, fresh}, heads, template, 8)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, chained, entries[0].TokenID)
+	assert.Equal(t, int64(5), entries[0].Sequence)
+	assert.Equal(t, "previous", entries[0].PreviousHash)
+
+	assert.Equal(t, fresh, entries[1].TokenID)
+	assert.Equal(t, int64(1), entries[1].Sequence)
+	assert.Empty(t, entries[1].PreviousHash)
+
+	assert.NotEqual(t, entries[0].ID, entries[1].ID)
+	for _, entry := range entries {
+		computed, err := AuditEntryHash(entry)
+		require.NoError(t, err)
+		assert.Equal(t, computed, entry.EntryHash)
+	}
+}
+
+// createBulkTestTokens creates active tokens for bulk update tests, returning their IDs and a
+// function that deletes them
+func createBulkTestTokens(tb testing.TB, repo TokenRepository, db *sql.DB, count int) ([]uuid.UUID, func()) {
+	ctx := context.Background()
+
+	tokenIDs := make([]uuid.UUID, count)
+	for i := range tokenIDs {
+		tokenIDs[i] = uuid.New()
+		err := repo.Create(ctx, &models.Token{
+			TokenID:            tokenIDs[i],
+			CBDCType:           models.CBDCTypeUSD,
+			Denomination:       100.0,
+			CurrentOwner:       uuid.New(),
+			Status:             models.TokenStatusActive,
+			IssueTimestamp:     time.Now(),
+			TransactionHistory: make(models.UUIDArray, 0),
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+		if err != nil {
+			tb.Fatalf("Failed to create token: %v", err)
+		}
+	}
+
+	return tokenIDs, func() {
+		for _, tokenID := range tokenIDs {
+			db.Exec(`DELETE FROM token_audit_trail WHERE token_id = $1`, tokenID)
+			db.Exec(`DELETE FROM tokens WHERE token_id = $1`, tokenID)
+		}
+	}
+}
+
+func TestTokenRepository_BulkUpdateStatusBatches(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	repo.SetBulkUpdateOptions(3, 2)
+	ctx := context.Background()
+
+	tokenIDs, cleanup := createBulkTestTokens(t, repo, db.DB, 7)
+	defer cleanup()
+
+	var err error
+
+	// One token already has a chained entry, which the bulk entry must extend
+	previous := TokenAuditEntry{
+		ID:        uuid.New(),
+		TokenID:   tokenIDs[4],
+		Operation: "NOTE",
+		Timestamp: sql.NullTime{Time: time.Now().UTC().Truncate(time.Microsecond), Valid: true},
+		Sequence:  1,
+	}
+	previous.EntryHash, err = AuditEntryHash(previous)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO token_audit_trail (id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, sequence, previous_hash, entry_hash)
+		VALUES ($1, $2, $3, '', '', $4, $4, $5, $6, '', $7)`,
+		previous.ID, previous.TokenID, previous.Operation, uuid.Nil, previous.Timestamp.Time, previous.Sequence, previous.EntryHash)
+	require.NoError(t, err)
+
+	// Duplicates are updated and audited once
+	err = repo.BulkUpdateStatus(ctx, append(tokenIDs, tokenIDs[0]), models.TokenStatusFrozen, map[string]interface{}{
+		"reason_code": "FRAUD_SUSPECTED",
+	})
+	require.NoError(t, err)
+
+	for _, tokenID := range tokenIDs {
+		token, err := repo.GetByID(ctx, tokenID)
+		require.NoError(t, err)
+		require.NotNil(t, token)
+		assert.Equal(t, models.TokenStatusFrozen, token.Status)
+
+		chain, err := repo.GetAuditChain(ctx, tokenID)
+		require.NoError(t, err)
+
+		var bulkEntries []TokenAuditEntry
+		previousHash := ""
+		for i, entry := range chain {
+			assert.Equal(t, int64(i+1), entry.Sequence)
+			assert.Equal(t, previousHash, entry.PreviousHash)
+			computed, err := AuditEntryHash(entry)
+			require.NoError(t, err)
+			assert.Equal(t, computed, entry.EntryHash)
+			previousHash = entry.EntryHash
+
+			if entry.Operation == "BULK_STATUS_UPDATE" {
+				bulkEntries = append(bulkEntries, entry)
+			}
+		}
+
+		require.Len(t, bulkEntries, 1, "token %s", tokenID)
+		assert.Equal(t, models.TokenStatusFrozen, bulkEntries[0].NewStatus)
+		assert.Equal(t, "FRAUD_SUSPECTED", bulkEntries[0].Metadata["reason_code"])
+		assert.EqualValues(t, len(tokenIDs), bulkEntries[0].Metadata["token_count"])
+	}
+
+	chain, err := repo.GetAuditChain(ctx, tokenIDs[4])
+	require.NoError(t, err)
+	assert.Len(t, chain, 2)
+}
+
+func TestTokenRepository_BulkUpdateStatusIsAtomic(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	repo.SetBulkUpdateOptions(2, 2)
+	ctx := context.Background()
+
+	tokenIDs, cleanup := createBulkTestTokens(t, repo, db.DB, 4)
+	defer cleanup()
+
+	// The unknown token in the last batch cannot be audited, so no batch is applied
+	err := repo.BulkUpdateStatus(ctx, append(tokenIDs, uuid.New()), models.TokenStatusFrozen, nil)
+	require.Error(t, err)
+
+	for _, tokenID := range tokenIDs {
+		token, err := repo.GetByID(ctx, tokenID)
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusActive, token.Status)
+
+		trail, err := repo.GetAuditTrail(ctx, tokenID)
+		require.NoError(t, err)
+		for _, entry := range trail {
+			assert.NotEqual(t, "BULK_STATUS_UPDATE", entry.Operation)
+		}
+	}
+}
+
+// BenchmarkBulkUpdateStatus compares updating 1000 tokens in one statement with sequential
+// audit entries, as before batching, against the default batches and audit workers
+func BenchmarkBulkUpdateStatus(b *testing.B) {
+	db := setupFreezeReportDB(b)
+	defer db.Close()
+
+	benchmarks := []struct {
+		name         string
+		batchSize    int
+		auditWorkers int
+	}{
+		{"single batch, sequential audit", 1000, 1},
+		{"default batches", DefaultBulkBatchSize, DefaultBulkAuditWorkers},
+		{"small batches", 100, DefaultBulkAuditWorkers},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			repo := NewTokenRepository(db)
+			repo.SetBulkUpdateOptions(bm.batchSize, bm.auditWorkers)
+			ctx := context.Background()
+
+			tokenIDs, cleanup := createBulkTestTokens(b, repo, db.DB, 1000)
+			defer cleanup()
+
+			statuses := []models.TokenStatus{models.TokenStatusFrozen, models.TokenStatusActive}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.BulkUpdateStatus(ctx, tokenIDs, statuses[i%2], nil); err != nil {
+					b.Fatalf("Bulk update failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Signing key statuses. An issuer has at most one active key; retired keys only verify.
+const (
+	SigningKeyActive  = "active"
+	SigningKeyRetired = "retired"
+)
+
+// IssuerSigningKey is the public half of an issuer signing key added through rotation
+type IssuerSigningKey struct {
+	KeyID     string     `json:"key_id" db:"key_id"`
+	Issuer    string     `json:"issuer" db:"issuer"`
+	Algorithm string     `json:"algorithm" db:"algorithm"`
+	PublicKey []byte     `json:"public_key" db:"public_key"`
+	Status    string     `json:"status" db:"status"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty" db:"retired_at"`
+}
+
+// RotateSigningKey records a new active key for its issuer and retires the issuer's previous
+// active key, in one database transaction. It returns the ID of the retired key, or an empty
+// string if the issuer had no active key.
+func (r *tokenRepository) RotateSigningKey(ctx context.Context, key *IssuerSigningKey) (string, error) {
+	var retiredKeyID string
+	err := r.db.TransactionContext(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE issuer_signing_keys SET status = $2, retired_at = NOW()
+			WHERE issuer = $1 AND status = $3
+			RETURNING key_id`,
+			key.Issuer, SigningKeyRetired, SigningKeyActive,
+		).Scan(&retiredKeyID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to retire signing key: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO issuer_signing_keys (key_id, issuer, algorithm, public_key, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())
+			RETURNING created_at`,
+			key.KeyID, key.Issuer, key.Algorithm, key.PublicKey, SigningKeyActive,
+		).Scan(&key.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store signing key: %w", err)
+		}
+		key.Status = SigningKeyActive
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return retiredKeyID, nil
+}
+
+// GetSigningKeys retrieves every issuer signing key added through rotation, oldest first
+func (r *tokenRepository) GetSigningKeys(ctx context.Context) ([]IssuerSigningKey, error) {
+	query := `
+		SELECT key_id, issuer, algorithm, public_key, status, created_at, retired_at
+		FROM issuer_signing_keys
+		ORDER BY created_at, key_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []IssuerSigningKey
+	for rows.Next() {
+		var key IssuerSigningKey
+		var retiredAt sql.NullTime
+		err := rows.Scan(
+			&key.KeyID,
+			&key.Issuer,
+			&key.Algorithm,
+			&key.PublicKey,
+			&key.Status,
+			&key.CreatedAt,
+			&retiredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating signing key rows: %w", err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRepository_RotateSigningKey(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	issuer := "issuer-" + uuid.New().String()
+	defer db.Exec(`DELETE FROM issuer_signing_keys WHERE issuer = $1`, issuer)
+
+	first := &IssuerSigningKey{KeyID: uuid.New().String(), Issuer: issuer, Algorithm: "ed25519", PublicKey: []byte("first")}
+	retired, err := repo.RotateSigningKey(ctx, first)
+	require.NoError(t, err)
+	assert.Empty(t, retired)
+	assert.Equal(t, SigningKeyActive, first.Status)
+
+	second := &IssuerSigningKey{KeyID: uuid.New().String(), Issuer: issuer, Algorithm: "ed25519", PublicKey: []byte("second")}
+	retired, err = repo.RotateSigningKey(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, first.KeyID, retired)
+
+	// Reusing a key ID fails without retiring the active key
+	_, err = repo.RotateSigningKey(ctx, &IssuerSigningKey{KeyID: first.KeyID, Issuer: issuer, Algorithm: "ed25519", PublicKey: []byte("reused")})
+	assert.Error(t, err)
+
+	keys, err := repo.GetSigningKeys(ctx)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	for _, key := range keys {
+		if key.Issuer == issuer {
+			statuses[key.KeyID] = key.Status
+			if key.KeyID == first.KeyID {
+				assert.NotNil(t, key.RetiredAt)
+				assert.Equal(t, []byte("first"), key.PublicKey)
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{first.KeyID: SigningKeyRetired, second.KeyID: SigningKeyActive}, statuses)
+}
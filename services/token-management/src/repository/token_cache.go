@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/token-management/src/models"
+)
+
+// cachedToken is a token read held by the cache
+type cachedToken struct {
+	token     *models.Token
+	expiresAt time.Time
+}
+
+// CachingTokenRepository serves GetByID from a bounded least-recently-used cache in front of
+// another repository, for hot tokens such as those of busy merchant wallets that are verified
+// repeatedly. Every write through it evicts the tokens it touches. Tokens written inside a
+// transaction are neither served from nor added to the cache until ReleaseTx reports that the
+// transaction has finished, so a read in this process never sees a token older than a write
+// made here. Writes by other processes are seen once the TTL expires.
+type CachingTokenRepository struct {
+	TokenRepository
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	entries     map[uuid.UUID]*list.Element
+	lru         *list.List              // Most recently used at the front
+	pending     map[uuid.UUID]int       // Writes in progress per token
+	txWrites    map[*sql.Tx][]uuid.UUID // Tokens written by each open transaction
+	ownerWrites int                     // Writes in progress by owner, whose tokens are only known once they finish
+	generation  uint64                  // Advanced whenever a write starts or finishes
+}
+
+// NewCachingTokenRepository wraps a repository with a cache of up to maxEntries tokens, each
+// served for at most ttl
+func NewCachingTokenRepository(next TokenRepository, maxEntries int, ttl time.Duration) *CachingTokenRepository {
+	return &CachingTokenRepository{
+		TokenRepository: next,
+		maxEntries:      maxEntries,
+		ttl:             ttl,
+		now:             time.Now,
+		entries:         make(map[uuid.UUID]*list.Element),
+		lru:             list.New(),
+		pending:         make(map[uuid.UUID]int),
+		txWrites:        make(map[*sql.Tx][]uuid.UUID),
+	}
+}
+
+// GetByID returns a cached copy of the token while it is fresh, otherwise reads it and caches
+// it. A read that overlaps a write is not cached, since it may have seen the state before it.
+func (r *CachingTokenRepository) GetByID(ctx context.Context, tokenID uuid.UUID) (*models.Token, error) {
+	r.mu.Lock()
+	if elem, ok := r.entries[tokenID]; ok {
+		entry := elem.Value.(*cachedToken)
+		if r.now().Before(entry.expiresAt) {
+			r.lru.MoveToFront(elem)
+			token := cloneToken(entry.token)
+			r.mu.Unlock()
+			return token, nil
+		}
+		r.evictLocked(tokenID)
+	}
+	generation := r.generation
+	r.mu.Unlock()
+
+	token, err := r.TokenRepository.GetByID(ctx, tokenID)
+	if err != nil || token == nil {
+		return token, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation == generation && r.pending[tokenID] == 0 && r.ownerWrites == 0 {
+		r.storeLocked(cloneToken(token))
+	}
+
+	return token, nil
+}
+
+// Len returns the number of cached tokens
+func (r *CachingTokenRepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lru.Len()
+}
+
+// ReleaseTx ends the writes made in a transaction once it has committed or rolled back,
+// evicting the tokens it wrote and allowing them to be cached again
+func (r *CachingTokenRepository) ReleaseTx(tx *sql.Tx) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokenIDs, ok := r.txWrites[tx]
+	if !ok {
+		return
+	}
+	delete(r.txWrites, tx)
+	r.endWriteLocked(tokenIDs)
+}
+
+// write evicts tokens about to be written and keeps them out of the cache until the returned
+// function is called. Writes in a transaction last until ReleaseTx, so for them the returned
+// function does nothing.
+func (r *CachingTokenRepository) write(tx *sql.Tx, tokenIDs ...uuid.UUID) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	for _, tokenID := range tokenIDs {
+		r.evictLocked(tokenID)
+		r.pending[tokenID]++
+	}
+
+	if tx != nil {
+		r.txWrites[tx] = append(r.txWrites[tx], tokenIDs...)
+		return func() {}
+	}
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.endWriteLocked(tokenIDs)
+	}
+}
+
+// writeOwner evicts an owner's cached tokens and keeps every token out of the cache while a
+// write by owner is in progress. The returned function ends the write, evicting the tokens it
+// reports having written.
+func (r *CachingTokenRepository) writeOwner(ownerID uuid.UUID) func(written []uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.ownerWrites++
+	for elem := r.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if token := elem.Value.(*cachedToken).token; token.CurrentOwner == ownerID {
+			r.evictLocked(token.TokenID)
+		}
+		elem = next
+	}
+
+	return func(written []uuid.UUID) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.generation++
+		r.ownerWrites--
+		for _, tokenID := range written {
+			r.evictLocked(tokenID)
+		}
+	}
+}
+
+func (r *CachingTokenRepository) endWriteLocked(tokenIDs []uuid.UUID) {
+	r.generation++
+	for _, tokenID := range tokenIDs {
+		r.evictLocked(tokenID)
+		if r.pending[tokenID]--; r.pending[tokenID] <= 0 {
+			delete(r.pending, tokenID)
+		}
+	}
+}
+
+func (r *CachingTokenRepository) storeLocked(token *models.Token) {
+	if elem, ok := r.entries[token.TokenID]; ok {
+		r.lru.MoveToFront(elem)
+		elem.Value = &cachedToken{token: token, expiresAt: r.now().Add(r.ttl)}
+		return
+	}
+
+	r.entries[token.TokenID] = r.lru.PushFront(&cachedToken{token: token, expiresAt: r.now().Add(r.ttl)})
+	for r.lru.Len() > r.maxEntries {
+		r.evictLocked(r.lru.Back().Value.(*cachedToken).token.TokenID)
+	}
+}
+
+func (r *CachingTokenRepository) evictLocked(tokenID uuid.UUID) {
+	if elem, ok := r.entries[tokenID]; ok {
+		r.lru.Remove(elem)
+		delete(r.entries, tokenID)
+	}
+}
+
+// cloneToken copies a token so callers cannot change a cached token, including its
+// transaction history, which transfers append to
+func cloneToken(token *models.Token) *models.Token {
+	clone := *token
+	clone.TransactionHistory = append(models.UUIDArray(nil), token.TransactionHistory...)
+	return &clone
+}
+
+// Update evicts the token and writes it
+func (r *CachingTokenRepository) Update(ctx context.Context, token *models.Token) error {
+	defer r.write(nil, token.TokenID)()
+	return r.TokenRepository.Update(ctx, token)
+}
+
+// UpdateWithTx evicts the token and writes it in tx
+func (r *CachingTokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	defer r.write(tx, token.TokenID)()
+	return r.TokenRepository.UpdateWithTx(ctx, tx, token)
+}
+
+// BulkUpdateStatus evicts the tokens and updates their status
+func (r *CachingTokenRepository) BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus, metadata map[string]interface{}) error {
+	defer r.write(nil, tokenIDs...)()
+	return r.TokenRepository.BulkUpdateStatus(ctx, tokenIDs, status, metadata)
+}
+
+// RecallWithTx evicts the tokens and recalls them in tx
+func (r *CachingTokenRepository) RecallWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, reason string) (int, error) {
+	defer r.write(tx, tokenIDs...)()
+	return r.TokenRepository.RecallWithTx(ctx, tx, tokenIDs, reason)
+}
+
+// UpdateComplianceFlagsWithTx evicts the token and updates its compliance flags in tx
+func (r *CachingTokenRepository) UpdateComplianceFlagsWithTx(ctx context.Context, tx *sql.Tx, token *models.Token, previousFlags models.ComplianceFlags, previousStatus models.TokenStatus) error {
+	defer r.write(tx, token.TokenID)()
+	return r.TokenRepository.UpdateComplianceFlagsWithTx(ctx, tx, token, previousFlags, previousStatus)
+}
+
+// SetFrozenUntilWithTx evicts the token and sets its freeze expiry in tx
+func (r *CachingTokenRepository) SetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, frozenUntil *time.Time) error {
+	defer r.write(tx, tokenID)()
+	return r.TokenRepository.SetFrozenUntilWithTx(ctx, tx, tokenID, frozenUntil)
+}
+
+// ArchiveTransactionHistoryWithTx evicts the token and archives its transaction history in tx
+func (r *CachingTokenRepository) ArchiveTransactionHistoryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, transactionIDs []uuid.UUID) error {
+	defer r.write(tx, tokenID)()
+	return r.TokenRepository.ArchiveTransactionHistoryWithTx(ctx, tx, tokenID, transactionIDs)
+}
+
+// FreezeOwnerTokens evicts the owner's tokens and freezes them
+func (r *CachingTokenRepository) FreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	done := r.writeOwner(ownerID)
+	tokenIDs, err := r.TokenRepository.FreezeOwnerTokens(ctx, ownerID, metadata)
+	done(tokenIDs)
+	return tokenIDs, err
+}
+
+// UnfreezeOwnerTokens evicts the owner's tokens and unfreezes them
+func (r *CachingTokenRepository) UnfreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, reasonCode string, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	done := r.writeOwner(ownerID)
+	tokenIDs, err := r.TokenRepository.UnfreezeOwnerTokens(ctx, ownerID, reasonCode, metadata)
+	done(tokenIDs)
+	return tokenIDs, err
+}
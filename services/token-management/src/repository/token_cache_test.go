@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+)
+
+// countingRepository keeps tokens in memory and counts the reads that reach it
+type countingRepository struct {
+	TokenRepository
+	tokens map[uuid.UUID]models.Token
+	reads  int
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, tokenID uuid.UUID) (*models.Token, error) {
+	r.reads++
+	token, ok := r.tokens[tokenID]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (r *countingRepository) Update(ctx context.Context, token *models.Token) error {
+	r.tokens[token.TokenID] = *token
+	return nil
+}
+
+func (r *countingRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	r.tokens[token.TokenID] = *token
+	return nil
+}
+
+func (r *countingRepository) FreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	var frozen []uuid.UUID
+	for tokenID, token := range r.tokens {
+		if token.CurrentOwner == ownerID {
+			token.Status = models.TokenStatusFrozen
+			r.tokens[tokenID] = token
+			frozen = append(frozen, tokenID)
+		}
+	}
+	return frozen, nil
+}
+
+func TestCachingTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+
+	newCache := func(size int, tokens ...models.Token) (*CachingTokenRepository, *countingRepository) {
+		next := &countingRepository{tokens: make(map[uuid.UUID]models.Token)}
+		for _, token := range tokens {
+			next.tokens[token.TokenID] = token
+		}
+		return NewCachingTokenRepository(next, size, time.Minute), next
+	}
+
+	newToken := func() models.Token {
+		return models.Token{TokenID: uuid.New(), CurrentOwner: owner, Status: models.TokenStatusActive}
+	}
+
+	t.Run("repeated reads are served from the cache", func(t *testing.T) {
+		token := newToken()
+		cache, next := newCache(10, token)
+
+		for i := 0; i < 3; i++ {
+			got, err := cache.GetByID(ctx, token.TokenID)
+			require.NoError(t, err)
+			assert.Equal(t, token.TokenID, got.TokenID)
+		}
+		assert.Equal(t, 1, next.reads)
+
+		// Changing a returned token does not change the cached one
+		got, _ := cache.GetByID(ctx, token.TokenID)
+		got.Status = models.TokenStatusInvalid
+		got, _ = cache.GetByID(ctx, token.TokenID)
+		assert.Equal(t, models.TokenStatusActive, got.Status)
+	})
+
+	t.Run("a write invalidates the cached token", func(t *testing.T) {
+		token := newToken()
+		cache, _ := newCache(10, token)
+
+		_, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+
+		updated := token
+		updated.Status = models.TokenStatusFrozen
+		require.NoError(t, cache.Update(ctx, &updated))
+
+		got, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusFrozen, got.Status)
+	})
+
+	t.Run("a token written in a transaction is not cached until it finishes", func(t *testing.T) {
+		token := newToken()
+		cache, next := newCache(10, token)
+		tx := &sql.Tx{}
+
+		_, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+
+		updated := token
+		updated.CurrentOwner = uuid.New()
+		require.NoError(t, cache.UpdateWithTx(ctx, tx, &updated))
+
+		reads := next.reads
+		_, err = cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+		_, err = cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, reads+2, next.reads, "reads during the transaction must reach the repository")
+		assert.Equal(t, 0, cache.Len())
+
+		cache.ReleaseTx(tx)
+		got, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, updated.CurrentOwner, got.CurrentOwner)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("freezing an owner's tokens invalidates them", func(t *testing.T) {
+		token := newToken()
+		cache, _ := newCache(10, token)
+
+		_, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+
+		frozen, err := cache.FreezeOwnerTokens(ctx, owner, nil)
+		require.NoError(t, err)
+		require.Contains(t, frozen, token.TokenID)
+
+		got, err := cache.GetByID(ctx, token.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusFrozen, got.Status)
+	})
+
+	t.Run("expired and least recently used tokens are read again", func(t *testing.T) {
+		first, second, third := newToken(), newToken(), newToken()
+		cache, next := newCache(2, first, second, third)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		for _, token := range []models.Token{first, second, third} {
+			_, err := cache.GetByID(ctx, token.TokenID)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, cache.Len())
+
+		_, err := cache.GetByID(ctx, first.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, next.reads, "the least recently used token is evicted")
+
+		now = now.Add(2 * time.Minute)
+		_, err = cache.GetByID(ctx, first.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, 5, next.reads, "an expired token is read again")
+	})
+
+	t.Run("missing tokens are not cached", func(t *testing.T) {
+		cache, next := newCache(10)
+		tokenID := uuid.New()
+
+		for i := 0; i < 2; i++ {
+			token, err := cache.GetByID(ctx, tokenID)
+			require.NoError(t, err)
+			assert.Nil(t, token)
+		}
+		assert.Equal(t, 2, next.reads)
+	})
+}
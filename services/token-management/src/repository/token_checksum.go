@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/logging"
+	"echopay/token-management/src/models"
+)
+
+// canonicalToken is the canonical form of a token's content. Fields are declared in key order,
+// and nested objects are canonicalized to generic JSON values, whose keys encode sorted, so the
+// encoding is the same wherever the token is serialized. Bookkeeping fields (created and updated
+// times and the version) are left out, so equal tokens held by different services compare equal.
+type canonicalToken struct {
+	CBDCType           string      `json:"cbdc_type"`
+	ComplianceFlags    interface{} `json:"compliance_flags"`
+	CurrentOwner       string      `json:"current_owner"`
+	Denomination       string      `json:"denomination"`
+	IssueTimestamp     string      `json:"issue_timestamp"`
+	Metadata           interface{} `json:"metadata"`
+	Status             string      `json:"status"`
+	TokenID            string      `json:"token_id"`
+	TransactionHistory []string    `json:"transaction_history"`
+}
+
+// TokenCanonical returns the deterministic byte representation of a token. Values are
+// normalized the way they are stored, so a token encodes identically after a database round
+// trip: timestamps in UTC to the microsecond and the denomination in its shortest form.
+func TokenCanonical(token *models.Token) ([]byte, error) {
+	metadata, err := canonicalJSONValue(token.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize token metadata: %w", err)
+	}
+
+	flags, err := canonicalJSONValue(token.ComplianceFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize compliance flags: %w", err)
+	}
+
+	history := make([]string, len(token.TransactionHistory))
+	for i, transactionID := range token.TransactionHistory {
+		history[i] = transactionID.String()
+	}
+
+	canonical, err := json.Marshal(canonicalToken{
+		CBDCType:           string(token.CBDCType),
+		ComplianceFlags:    flags,
+		CurrentOwner:       token.CurrentOwner.String(),
+		Denomination:       strconv.FormatFloat(token.Denomination, 'f', -1, 64),
+		IssueTimestamp:     token.IssueTimestamp.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		Metadata:           metadata,
+		Status:             string(token.Status),
+		TokenID:            token.TokenID.String(),
+		TransactionHistory: history,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode token: %w", err)
+	}
+	return canonical, nil
+}
+
+// TokenChecksum returns the hex-encoded SHA-256 hash of a token's canonical representation
+func TokenChecksum(token *models.Token) (string, error) {
+	canonical, err := TokenCanonical(token)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSONValue converts a value to generic JSON values, whose object keys encode in
+// sorted order
+func canonicalJSONValue(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var canonical interface{}
+	if err := json.Unmarshal(encoded, &canonical); err != nil {
+		return nil, err
+	}
+	return canonical, nil
+}
+
+// SetStrictChecksums sets whether reading a token whose stored checksum does not match its
+// content fails. By default the mismatch is only logged.
+func (r *tokenRepository) SetStrictChecksums(strict bool) {
+	r.strictChecksums = strict
+}
+
+// verifyChecksum compares a token read from the database with its stored checksum, to detect
+// rows corrupted or altered at rest. Tokens written before checksums were introduced have none
+// and are not checked until they are next updated.
+func (r *tokenRepository) verifyChecksum(ctx context.Context, token *models.Token, stored sql.NullString) error {
+	if !stored.Valid {
+		return nil
+	}
+
+	computed, err := TokenChecksum(token)
+	if err != nil {
+		return err
+	}
+	if computed == stored.String {
+		return nil
+	}
+
+	if r.strictChecksums {
+		return fmt.Errorf("token %s failed checksum verification", token.TokenID)
+	}
+	logging.WithContext(ctx).Warn("Token checksum mismatch",
+		"token_id", token.TokenID,
+		"stored_checksum", stored.String,
+		"computed_checksum", computed,
+	)
+	return nil
+}
+
+// refreshChecksums recomputes and stores the checksums of tokens changed by a statement that
+// updated their rows directly, within the same transaction
+func (r *tokenRepository) refreshChecksums(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID) error {
+	if len(tokenIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(tokenIDs))
+	for i, tokenID := range tokenIDs {
+		ids[i] = tokenID.String()
+	}
+
+	query := r.db.QueryContext
+	exec := r.db.ExecContext
+	if tx != nil {
+		query = tx.QueryContext
+		exec = tx.ExecContext
+	}
+
+	rows, err := query(ctx, `
+		SELECT token_id, cbdc_type, denomination, current_owner, status,
+			   issue_timestamp, transaction_history, metadata, compliance_flags
+		FROM tokens
+		WHERE token_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load tokens for checksums: %w", err)
+	}
+
+	var tokens []models.Token
+	for rows.Next() {
+		var token models.Token
+		err := rows.Scan(
+			&token.TokenID,
+			&token.CBDCType,
+			&token.Denomination,
+			&token.CurrentOwner,
+			&token.Status,
+			&token.IssueTimestamp,
+			&token.TransactionHistory,
+			&token.Metadata,
+			&token.ComplianceFlags,
+		)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating token rows: %w", err)
+	}
+
+	for i := range tokens {
+		checksum, err := TokenChecksum(&tokens[i])
+		if err != nil {
+			return err
+		}
+		if _, err := exec(ctx, `UPDATE tokens SET checksum = $2 WHERE token_id = $1`, tokens[i].TokenID, checksum); err != nil {
+			return fmt.Errorf("failed to update token checksum: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/merkle"
 	"echopay/token-management/src/models"
 )
 
@@ -19,37 +23,198 @@ type TokenRepository interface {
 	CreateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error
 	GetByID(ctx context.Context, tokenID uuid.UUID) (*models.Token, error)
 	GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*models.Token, error)
+	GetByIDs(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID]*models.Token, error)
 	Update(ctx context.Context, token *models.Token) error
 	UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error
-	GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Token, error)
-	GetByStatus(ctx context.Context, status models.TokenStatus) ([]models.Token, error)
+	GetByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]models.Token, error)
+	CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error)
+	GetByStatus(ctx context.Context, status models.TokenStatus, limit, offset int) ([]models.Token, error)
+	CountByStatus(ctx context.Context, status models.TokenStatus) (int, error)
 	GetByCBDCType(ctx context.Context, cbdcType models.CBDCType) ([]models.Token, error)
-	BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus) error
+	BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus, metadata map[string]interface{}) error
 	GetAuditTrail(ctx context.Context, tokenID uuid.UUID) ([]TokenAuditEntry, error)
+	GetAuditChain(ctx context.Context, tokenID uuid.UUID) ([]TokenAuditEntry, error)
+	GetAuditTrails(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID][]TokenAuditEntry, error)
+	ArchiveAuditTrail(ctx context.Context, before time.Time) (int, error)
+	CreateAuditEntryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, operation string, metadata map[string]interface{}) error
+	GetFreezeEventsBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]FreezeEvent, error)
+	CountFreezeEventsBetween(ctx context.Context, from, to time.Time) (int, error)
+	GetAuditEntriesBetween(ctx context.Context, from, to, afterTimestamp time.Time, afterID uuid.UUID, limit int) ([]TokenAuditEntry, error)
+	GetActiveBySeries(ctx context.Context, issuer, series string, afterID uuid.UUID, limit int) ([]models.Token, error)
+	RecallWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, reason string) (int, error)
+	SaveMerkleBatchWithTx(ctx context.Context, tx *sql.Tx, batchID uuid.UUID, root string, proofs []TokenMerkleProof) error
+	GetMerkleProof(ctx context.Context, tokenID uuid.UUID) (*TokenMerkleProof, error)
+	SaveSignatureWithTx(ctx context.Context, tx *sql.Tx, signature *TokenSignature) error
+	GetSignature(ctx context.Context, tokenID uuid.UUID) (*TokenSignature, error)
+	RotateSigningKey(ctx context.Context, key *IssuerSigningKey) (string, error)
+	GetSigningKeys(ctx context.Context) ([]IssuerSigningKey, error)
+	SumByOwner(ctx context.Context, ownerID uuid.UUID) ([]TokenHolding, error)
+	GetRequiredSignersWithTx(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) (int, error)
+	SetRequiredSigners(ctx context.Context, walletID uuid.UUID, requiredSigners int, coSigners []uuid.UUID) error
+	IsCoSignerWithTx(ctx context.Context, tx *sql.Tx, walletID, signerID uuid.UUID) (bool, error)
+	CreatePendingTransferWithTx(ctx context.Context, tx *sql.Tx, transfer *PendingTransfer) error
+	GetPendingTransferWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID) (*PendingTransfer, error)
+	AddTransferApprovalWithTx(ctx context.Context, tx *sql.Tx, pendingID, signerID uuid.UUID) error
+	UpdatePendingTransferStatusWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID, status PendingTransferStatus) error
+	HasPendingTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (bool, error)
+	CountOwnershipTransfersSinceWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, since time.Time) (int, error)
+	GetLastOwnershipTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error)
+	RecordTransferNonceWithTx(ctx context.Context, tx *sql.Tx, ownerID uuid.UUID, nonce string, now, expiresAt time.Time) (bool, error)
+	GetPendingTransfersByToken(ctx context.Context, tokenID uuid.UUID) ([]PendingTransfer, error)
+	RecordSanctionsBlockWithTx(ctx context.Context, tx *sql.Tx, block *SanctionsBlock) error
+	UpdateComplianceFlagsWithTx(ctx context.Context, tx *sql.Tx, token *models.Token, previousFlags models.ComplianceFlags, previousStatus models.TokenStatus) error
+	GetIssuerQuotaForUpdateWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType) (*IssuerQuota, error)
+	AddMintedTotalWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType, amount float64) error
+	GetIssuerQuotas(ctx context.Context, issuer string) ([]IssuerQuota, error)
+	SetIssuerQuota(ctx context.Context, issuer, series string, cbdcType models.CBDCType, quota float64) (*IssuerQuota, error)
+	SetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, frozenUntil *time.Time) error
+	GetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error)
+	GetExpiredFreezes(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	ArchiveTransactionHistoryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, transactionIDs []uuid.UUID) error
+	GetArchivedTransactionHistory(ctx context.Context, tokenID uuid.UUID) ([]uuid.UUID, error)
+	FreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, metadata map[string]interface{}) ([]uuid.UUID, error)
+	UnfreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, reasonCode string, metadata map[string]interface{}) ([]uuid.UUID, error)
+	SetStrictChecksums(strict bool)
+	SetBulkUpdateOptions(batchSize, auditWorkers int)
 }
 
 // tokenRepository implements TokenRepository
 type tokenRepository struct {
-	db *database.PostgresDB
+	db              *database.PostgresDB
+	retryPolicy     database.RetryPolicy
+	strictChecksums  bool // Fail reads of tokens whose stored checksum does not match
+	bulkBatchSize    int  // Token IDs BulkUpdateStatus updates per statement
+	bulkAuditWorkers int  // Workers preparing audit entries for each bulk batch
 }
 
 // TokenAuditEntry represents an audit trail entry for token operations
 type TokenAuditEntry struct {
-	ID          uuid.UUID           `json:"id" db:"id"`
-	TokenID     uuid.UUID           `json:"token_id" db:"token_id"`
-	Operation   string              `json:"operation" db:"operation"`
-	OldStatus   models.TokenStatus  `json:"old_status" db:"old_status"`
-	NewStatus   models.TokenStatus  `json:"new_status" db:"new_status"`
-	OldOwner    uuid.UUID           `json:"old_owner" db:"old_owner"`
-	NewOwner    uuid.UUID           `json:"new_owner" db:"new_owner"`
-	Timestamp   sql.NullTime        `json:"timestamp" db:"timestamp"`
-	Metadata    map[string]interface{} `json:"metadata" db:"metadata"`
+	ID           uuid.UUID              `json:"id" db:"id"`
+	TokenID      uuid.UUID              `json:"token_id" db:"token_id"`
+	Operation    string                 `json:"operation" db:"operation"`
+	OldStatus    models.TokenStatus     `json:"old_status" db:"old_status"`
+	NewStatus    models.TokenStatus     `json:"new_status" db:"new_status"`
+	OldOwner     uuid.UUID              `json:"old_owner" db:"old_owner"`
+	NewOwner     uuid.UUID              `json:"new_owner" db:"new_owner"`
+	Timestamp    sql.NullTime           `json:"timestamp" db:"timestamp"`
+	Metadata     map[string]interface{} `json:"metadata" db:"metadata"`
+	Sequence     int64                  `json:"sequence,omitempty" db:"sequence"`
+	PreviousHash string                 `json:"previous_hash,omitempty" db:"previous_hash"`
+	EntryHash    string                 `json:"entry_hash,omitempty" db:"entry_hash"`
+	Archived     bool                   `json:"archived,omitempty" db:"-"`
+}
+
+// FreezeEvent represents a token being frozen, individually or in bulk, as recorded in the
+// audit trail. Metadata carries the reason code and note recorded with the freeze, if any.
+type FreezeEvent struct {
+	AuditID    uuid.UUID              `json:"audit_id" db:"id"`
+	TokenID    uuid.UUID              `json:"token_id" db:"token_id"`
+	Operation  string                 `json:"operation" db:"operation"`
+	FrozenAt   time.Time              `json:"frozen_at" db:"timestamp"`
+	ReasonCode string                 `json:"reason_code,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+}
+
+// TokenMerkleProof represents a token's inclusion proof in its issuance batch Merkle tree
+type TokenMerkleProof struct {
+	TokenID   uuid.UUID          `json:"token_id" db:"token_id"`
+	BatchID   uuid.UUID          `json:"batch_id" db:"batch_id"`
+	LeafIndex int                `json:"leaf_index" db:"leaf_index"`
+	Path      []merkle.ProofStep `json:"path" db:"path"`
+	BatchRoot string             `json:"batch_root" db:"root"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+}
+
+// TokenSignature represents an issuer's signature over a token at issuance. IssuedTo is the
+// owner the token was issued to, which the signature covers; it is uuid.Nil for signatures
+// stored before the issuance owner was recorded.
+type TokenSignature struct {
+	TokenID   uuid.UUID `json:"token_id" db:"token_id"`
+	KeyID     string    `json:"key_id" db:"key_id"`
+	Algorithm string    `json:"algorithm" db:"algorithm"`
+	Signature []byte    `json:"signature" db:"signature"`
+	IssuedTo  uuid.UUID `json:"issued_to" db:"issued_to"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TokenHolding represents aggregate holdings of a single CBDC type and status for an owner
+type TokenHolding struct {
+	CBDCType models.CBDCType    `json:"cbdc_type" db:"cbdc_type"`
+	Status   models.TokenStatus `json:"status" db:"status"`
+	Count    int                `json:"count" db:"count"`
+	Total    float64            `json:"total" db:"total"`
+}
+
+// PendingTransferStatus represents the state of a transfer awaiting co-signer approval
+type PendingTransferStatus string
+
+const (
+	PendingTransferStatusPending   PendingTransferStatus = "pending"
+	PendingTransferStatusCompleted PendingTransferStatus = "completed"
+	PendingTransferStatusRejected  PendingTransferStatus = "rejected"
+)
+
+// PendingTransfer represents a token transfer from a multi-sig wallet awaiting approvals
+type PendingTransfer struct {
+	ID              uuid.UUID             `json:"id" db:"id"`
+	TokenID         uuid.UUID             `json:"token_id" db:"token_id"`
+	FromOwner       uuid.UUID             `json:"from_owner" db:"from_owner"`
+	NewOwner        uuid.UUID             `json:"new_owner" db:"new_owner"`
+	TransactionID   uuid.UUID             `json:"transaction_id" db:"transaction_id"`
+	RequiredSigners int                   `json:"required_signers" db:"required_signers"`
+	Status          PendingTransferStatus `json:"status" db:"status"`
+	Approvals       []uuid.UUID           `json:"approvals"`
+	CreatedAt       time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// SanctionsBlock describes a transfer stopped because one of the parties failed screening
+type SanctionsBlock struct {
+	TokenID      uuid.UUID `json:"token_id"`
+	FromOwner    uuid.UUID `json:"from_owner"`
+	ToOwner      uuid.UUID `json:"to_owner"`
+	BlockedParty uuid.UUID `json:"blocked_party"`
+	ListRef      string    `json:"list_ref"`
+}
+
+// IssuerQuota caps the total value an issuer may mint for a series and CBDC type
+type IssuerQuota struct {
+	Issuer      string          `json:"issuer" db:"issuer"`
+	Series      string          `json:"series" db:"series"`
+	CBDCType    models.CBDCType `json:"cbdc_type" db:"cbdc_type"`
+	MintedTotal float64         `json:"minted_total" db:"minted_total"`
+	Quota       float64         `json:"quota" db:"quota"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Page size bounds for paginated token queries
+const (
+	DefaultTokenPageSize = 100
+	MaxTokenPageSize     = 1000
+)
+
+// NormalizePagination applies the default page size to a non-positive limit, caps it at
+// MaxTokenPageSize and clamps negative offsets to zero
+func NormalizePagination(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultTokenPageSize
+	}
+	if limit > MaxTokenPageSize {
+		limit = MaxTokenPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
 }
 
 // NewTokenRepository creates a new token repository
 func NewTokenRepository(db *database.PostgresDB) TokenRepository {
 	return &tokenRepository{
-		db: db,
+		db:               db,
+		retryPolicy:      database.DefaultRetryPolicy(),
+		bulkBatchSize:    DefaultBulkBatchSize,
+		bulkAuditWorkers: DefaultBulkAuditWorkers,
 	}
 }
 
@@ -64,12 +229,19 @@ func (r *tokenRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, token *m
 		INSERT INTO tokens (
 			token_id, cbdc_type, denomination, current_owner, status,
 			issue_timestamp, transaction_history, metadata, compliance_flags,
-			created_at, updated_at
+			created_at, updated_at, version, checksum
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 		)`
 
-	var err error
+	// New tokens start at version 1; every successful update increments it
+	token.Version = 1
+
+	checksum, err := TokenChecksum(token)
+	if err != nil {
+		return err
+	}
+
 	if tx != nil {
 		_, err = tx.ExecContext(ctx, query,
 			token.TokenID,
@@ -83,6 +255,8 @@ func (r *tokenRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, token *m
 			token.ComplianceFlags,
 			token.CreatedAt,
 			token.UpdatedAt,
+			token.Version,
+			checksum,
 		)
 	} else {
 		_, err = r.db.ExecContext(ctx, query,
@@ -97,11 +271,13 @@ func (r *tokenRepository) CreateWithTx(ctx context.Context, tx *sql.Tx, token *m
 			token.ComplianceFlags,
 			token.CreatedAt,
 			token.UpdatedAt,
+			token.Version,
+			checksum,
 		)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to create token: %w", err)
+		return tokenInsertError(token.TokenID, err)
 	}
 
 	// Create audit trail entry
@@ -119,16 +295,19 @@ func (r *tokenRepository) GetByID(ctx context.Context, tokenID uuid.UUID) (*mode
 	return r.GetByIDWithTx(ctx, nil, tokenID)
 }
 
-// GetByIDWithTx retrieves a token by its ID using an existing transaction
+// GetByIDWithTx retrieves a token by its ID using an existing transaction. The token's
+// checksum is recomputed and compared with the one stored when it was last written; see
+// verifyChecksum.
 func (r *tokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*models.Token, error) {
 	query := `
 		SELECT token_id, cbdc_type, denomination, current_owner, status,
 			   issue_timestamp, transaction_history, metadata, compliance_flags,
-			   created_at, updated_at
+			   created_at, updated_at, version, checksum
 		FROM tokens
 		WHERE token_id = $1`
 
 	var token models.Token
+	var checksum sql.NullString
 	var err error
 
 	if tx != nil {
@@ -144,6 +323,8 @@ func (r *tokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID
 			&token.ComplianceFlags,
 			&token.CreatedAt,
 			&token.UpdatedAt,
+			&token.Version,
+			&checksum,
 		)
 	} else {
 		err = r.db.QueryRowContext(ctx, query, tokenID).Scan(
@@ -158,6 +339,8 @@ func (r *tokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID
 			&token.ComplianceFlags,
 			&token.CreatedAt,
 			&token.UpdatedAt,
+			&token.Version,
+			&checksum,
 		)
 	}
 
@@ -168,15 +351,83 @@ func (r *tokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tokenID
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
 
+	if err := r.verifyChecksum(ctx, &token, checksum); err != nil {
+		return nil, err
+	}
+
 	return &token, nil
 }
 
+// MaxBatchFetchSize limits the number of tokens GetByIDs loads in one query
+const MaxBatchFetchSize = 1000
+
+// GetByIDs retrieves many tokens in a single query, keyed by token ID. IDs with no matching
+// token are left out of the result.
+func (r *tokenRepository) GetByIDs(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID]*models.Token, error) {
+	if len(tokenIDs) > MaxBatchFetchSize {
+		return nil, fmt.Errorf("cannot fetch more than %d tokens at once", MaxBatchFetchSize)
+	}
+
+	tokens := make(map[uuid.UUID]*models.Token, len(tokenIDs))
+	if len(tokenIDs) == 0 {
+		return tokens, nil
+	}
+
+	ids := make([]string, len(tokenIDs))
+	for i, tokenID := range tokenIDs {
+		ids[i] = tokenID.String()
+	}
+
+	query := `
+		SELECT token_id, cbdc_type, denomination, current_owner, status,
+			   issue_timestamp, transaction_history, metadata, compliance_flags,
+			   created_at, updated_at, version
+		FROM tokens
+		WHERE token_id = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tokens by ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token models.Token
+		err := rows.Scan(
+			&token.TokenID,
+			&token.CBDCType,
+			&token.Denomination,
+			&token.CurrentOwner,
+			&token.Status,
+			&token.IssueTimestamp,
+			&token.TransactionHistory,
+			&token.Metadata,
+			&token.ComplianceFlags,
+			&token.CreatedAt,
+			&token.UpdatedAt,
+			&token.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+		tokens[token.TokenID] = &token
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating token rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
 // Update updates an existing token in the database
 func (r *tokenRepository) Update(ctx context.Context, token *models.Token) error {
 	return r.UpdateWithTx(ctx, nil, token)
 }
 
-// UpdateWithTx updates an existing token using an existing transaction
+// UpdateWithTx updates an existing token using an existing transaction. The write only
+// applies if the stored version still matches token.Version; a stale write returns
+// ErrConcurrentModification. On success token.Version is advanced to the stored version.
 func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
 	// Get current token for audit trail
 	currentToken, err := r.GetByIDWithTx(ctx, tx, token.TokenID)
@@ -201,12 +452,20 @@ func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *m
 			transaction_history = $7,
 			metadata = $8,
 			compliance_flags = $9,
-			updated_at = $10
-		WHERE token_id = $1`
+			updated_at = $10,
+			version = version + 1,
+			checksum = $12
+		WHERE token_id = $1 AND version = $11`
 
+	checksum, err := TokenChecksum(token)
+	if err != nil {
+		return err
+	}
+
+	var result sql.Result
 	var execErr error
 	if tx != nil {
-		_, execErr = tx.ExecContext(ctx, query,
+		result, execErr = tx.ExecContext(ctx, query,
 			token.TokenID,
 			token.CBDCType,
 			token.Denomination,
@@ -217,9 +476,11 @@ func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *m
 			token.Metadata,
 			token.ComplianceFlags,
 			token.UpdatedAt,
+			token.Version,
+			checksum,
 		)
 	} else {
-		_, execErr = r.db.ExecContext(ctx, query,
+		result, execErr = r.db.ExecContext(ctx, query,
 			token.TokenID,
 			token.CBDCType,
 			token.Denomination,
@@ -230,6 +491,8 @@ func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *m
 			token.Metadata,
 			token.ComplianceFlags,
 			token.UpdatedAt,
+			token.Version,
+			checksum,
 		)
 	}
 
@@ -237,6 +500,21 @@ func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *m
 		return fmt.Errorf("failed to update token: %w", execErr)
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check token update result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewTokenManagementError(
+			errors.ErrConcurrentModification,
+			"token was modified concurrently",
+		).WithDetails(map[string]interface{}{
+			"token_id":         token.TokenID,
+			"expected_version": token.Version,
+		})
+	}
+	token.Version++
+
 	// Create audit trail entry for status change
 	if currentToken.Status != token.Status {
 		if err := r.createAuditEntry(ctx, tx, token.TokenID, "STATUS_CHANGE", currentToken.Status, token.Status, uuid.Nil, uuid.Nil, nil); err != nil {
@@ -254,17 +532,20 @@ func (r *tokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, token *m
 	return nil
 }
 
-// GetByOwner retrieves all tokens owned by a specific owner
-func (r *tokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Token, error) {
+// GetByOwner retrieves a page of tokens owned by a specific owner
+func (r *tokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]models.Token, error) {
+	limit, offset = NormalizePagination(limit, offset)
+
 	query := `
 		SELECT token_id, cbdc_type, denomination, current_owner, status,
 			   issue_timestamp, transaction_history, metadata, compliance_flags,
-			   created_at, updated_at
+			   created_at, updated_at, version
 		FROM tokens
 		WHERE current_owner = $1
-		ORDER BY created_at DESC`
+		ORDER BY created_at DESC, token_id
+		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	rows, err := r.db.QueryContext(ctx, query, ownerID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tokens by owner: %w", err)
 	}
@@ -285,6 +566,7 @@ func (r *tokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]
 			&token.ComplianceFlags,
 			&token.CreatedAt,
 			&token.UpdatedAt,
+			&token.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan token: %w", err)
@@ -299,17 +581,20 @@ func (r *tokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]
 	return tokens, nil
 }
 
-// GetByStatus retrieves all tokens with a specific status
-func (r *tokenRepository) GetByStatus(ctx context.Context, status models.TokenStatus) ([]models.Token, error) {
+// GetByStatus retrieves a page of tokens with a specific status
+func (r *tokenRepository) GetByStatus(ctx context.Context, status models.TokenStatus, limit, offset int) ([]models.Token, error) {
+	limit, offset = NormalizePagination(limit, offset)
+
 	query := `
 		SELECT token_id, cbdc_type, denomination, current_owner, status,
 			   issue_timestamp, transaction_history, metadata, compliance_flags,
-			   created_at, updated_at
+			   created_at, updated_at, version
 		FROM tokens
 		WHERE status = $1
-		ORDER BY created_at DESC`
+		ORDER BY created_at DESC, token_id
+		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryContext(ctx, query, status)
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tokens by status: %w", err)
 	}
@@ -330,6 +615,7 @@ func (r *tokenRepository) GetByStatus(ctx context.Context, status models.TokenSt
 			&token.ComplianceFlags,
 			&token.CreatedAt,
 			&token.UpdatedAt,
+			&token.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan token: %w", err)
@@ -344,12 +630,32 @@ func (r *tokenRepository) GetByStatus(ctx context.Context, status models.TokenSt
 	return tokens, nil
 }
 
+// CountByOwner returns the total number of tokens owned by a specific owner
+func (r *tokenRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tokens WHERE current_owner = $1", ownerID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens by owner: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatus returns the total number of tokens with a specific status
+func (r *tokenRepository) CountByStatus(ctx context.Context, status models.TokenStatus) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tokens WHERE status = $1", status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens by status: %w", err)
+	}
+	return count, nil
+}
+
 // GetByCBDCType retrieves all tokens of a specific CBDC type
 func (r *tokenRepository) GetByCBDCType(ctx context.Context, cbdcType models.CBDCType) ([]models.Token, error) {
 	query := `
 		SELECT token_id, cbdc_type, denomination, current_owner, status,
 			   issue_timestamp, transaction_history, metadata, compliance_flags,
-			   created_at, updated_at
+			   created_at, updated_at, version
 		FROM tokens
 		WHERE cbdc_type = $1
 		ORDER BY created_at DESC`
@@ -375,6 +681,7 @@ func (r *tokenRepository) GetByCBDCType(ctx context.Context, cbdcType models.CBD
 			&token.ComplianceFlags,
 			&token.CreatedAt,
 			&token.UpdatedAt,
+			&token.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan token: %w", err)
@@ -389,57 +696,359 @@ func (r *tokenRepository) GetByCBDCType(ctx context.Context, cbdcType models.CBD
 	return tokens, nil
 }
 
-// BulkUpdateStatus updates the status of multiple tokens atomically
-func (r *tokenRepository) BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus) error {
+// SumByOwner aggregates an owner's active and frozen tokens by CBDC type and status.
+// Disputed and invalid tokens are excluded.
+func (r *tokenRepository) SumByOwner(ctx context.Context, ownerID uuid.UUID) ([]TokenHolding, error) {
+	query := `
+		SELECT cbdc_type, status, COUNT(*), COALESCE(SUM(denomination), 0)
+		FROM tokens
+		WHERE current_owner = $1 AND status IN ($2, $3)
+		GROUP BY cbdc_type, status
+		ORDER BY cbdc_type, status`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID, models.TokenStatusActive, models.TokenStatusFrozen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holdings by owner: %w", err)
+	}
+	defer rows.Close()
+
+	var holdings []TokenHolding
+	for rows.Next() {
+		var holding TokenHolding
+		if err := rows.Scan(&holding.CBDCType, &holding.Status, &holding.Count, &holding.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan holding: %w", err)
+		}
+		holdings = append(holdings, holding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating holding rows: %w", err)
+	}
+
+	return holdings, nil
+}
+
+// BulkUpdateStatus updates the status of multiple tokens atomically, adding metadata to each
+// token's audit entry. Tokens are updated in batches of the configured size, all within one
+// transaction, so the update still succeeds or fails as a whole. Contended updates that fail
+// with a serialization failure or deadlock are retried.
+func (r *tokenRepository) BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus, metadata map[string]interface{}) error {
 	if len(tokenIDs) == 0 {
 		return nil
 	}
 
-	// Use transaction for atomicity
-	return r.db.Transaction(func(tx *sql.Tx) error {
-		// Build placeholders for IN clause
+	// Each token is updated and audited once, however often it is listed
+	seen := make(map[uuid.UUID]bool, len(tokenIDs))
+	unique := make([]uuid.UUID, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		if !seen[tokenID] {
+			seen[tokenID] = true
+			unique = append(unique, tokenID)
+		}
+	}
+
+	auditMetadata := map[string]interface{}{
+		"bulk_operation": true,
+		"token_count":    len(unique),
+	}
+	for key, value := range metadata {
+		auditMetadata[key] = value
+	}
+
+	bulkUpdate := func(tx *sql.Tx) error {
+		timestamp := time.Now().UTC().Truncate(time.Microsecond) // Postgres precision
+		for _, batch := range chunkTokenIDs(unique, r.bulkBatchSize) {
+			if err := r.bulkUpdateBatchWithTx(ctx, tx, batch, status, auditMetadata, timestamp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Use transaction for atomicity, retrying serialization failures and deadlocks
+	return database.WithRetry(func() error {
+		return r.db.Transaction(bulkUpdate)
+	}, r.retryPolicy)
+}
+
+// FreezeOwnerTokens freezes every active token held by an owner in one transaction and
+// returns the frozen token IDs
+func (r *tokenRepository) FreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	query := `
+		SELECT token_id FROM tokens
+		WHERE current_owner = $1 AND status = $2
+		ORDER BY token_id
+		FOR UPDATE`
+
+	return r.updateOwnerTokens(ctx, query, []interface{}{ownerID, models.TokenStatusActive}, models.TokenStatusFrozen, metadata)
+}
+
+// UnfreezeOwnerTokens unfreezes an owner's frozen tokens whose freeze was recorded with
+// reasonCode, leaving tokens frozen for any other reason untouched, and returns the
+// unfrozen token IDs
+func (r *tokenRepository) UnfreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, reasonCode string, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	// The latest entry that set the token's current status records why it was frozen
+	query := `
+		SELECT t.token_id FROM tokens t
+		WHERE t.current_owner = $1 AND t.status = $2
+			AND (
+				SELECT a.metadata->>'reason_code'
+				FROM token_audit_trail a
+				WHERE a.token_id = t.token_id AND a.new_status = t.status
+				ORDER BY a.sequence DESC
+				LIMIT 1
+			) = $3
+		ORDER BY t.token_id
+		FOR UPDATE OF t`
+
+	return r.updateOwnerTokens(ctx, query, []interface{}{ownerID, models.TokenStatusFrozen, reasonCode}, models.TokenStatusActive, metadata)
+}
+
+// updateOwnerTokens locks the tokens selected by query and moves them to status, auditing
+// each one as a bulk status update. Serialization failures and deadlocks are retried.
+func (r *tokenRepository) updateOwnerTokens(ctx context.Context, query string, args []interface{}, status models.TokenStatus, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	var tokenIDs []uuid.UUID
+
+	update := func(tx *sql.Tx) error {
+		tokenIDs = nil
+
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to lock owner tokens: %w", err)
+		}
+		for rows.Next() {
+			var tokenID uuid.UUID
+			if err := rows.Scan(&tokenID); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan token ID: %w", err)
+			}
+			tokenIDs = append(tokenIDs, tokenID)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("error iterating owner tokens: %w", err)
+		}
+		rows.Close()
+
+		if len(tokenIDs) == 0 {
+			return nil
+		}
+
 		placeholders := make([]string, len(tokenIDs))
-		args := make([]interface{}, len(tokenIDs)+1)
-		
+		updateArgs := make([]interface{}, len(tokenIDs)+1)
 		for i, tokenID := range tokenIDs {
 			placeholders[i] = fmt.Sprintf("$%d", i+1)
-			args[i] = tokenID
+			updateArgs[i] = tokenID
 		}
-		args[len(tokenIDs)] = status
+		updateArgs[len(tokenIDs)] = status
 
-		query := fmt.Sprintf(`
-			UPDATE tokens 
-			SET status = $%d, updated_at = NOW()
+		updateQuery := fmt.Sprintf(`
+			UPDATE tokens
+			SET status = $%d, frozen_until = NULL, updated_at = NOW(), version = version + 1
 			WHERE token_id IN (%s)`,
 			len(tokenIDs)+1,
 			strings.Join(placeholders, ","),
 		)
+		if _, err := tx.ExecContext(ctx, updateQuery, updateArgs...); err != nil {
+			return fmt.Errorf("failed to update owner token status: %w", err)
+		}
 
-		_, err := tx.ExecContext(ctx, query, args...)
-		if err != nil {
-			return fmt.Errorf("failed to bulk update token status: %w", err)
+		if err := r.refreshChecksums(ctx, tx, tokenIDs); err != nil {
+			return err
+		}
+
+		auditMetadata := map[string]interface{}{
+			"bulk_operation": true,
+			"token_count":    len(tokenIDs),
+		}
+		for key, value := range metadata {
+			auditMetadata[key] = value
 		}
 
-		// Create audit entries for each token
 		for _, tokenID := range tokenIDs {
-			if err := r.createAuditEntry(ctx, tx, tokenID, "BULK_STATUS_UPDATE", "", status, uuid.Nil, uuid.Nil, map[string]interface{}{
-				"bulk_operation": true,
-				"token_count":    len(tokenIDs),
-			}); err != nil {
+			if err := r.createAuditEntry(ctx, tx, tokenID, "BULK_STATUS_UPDATE", "", status, uuid.Nil, uuid.Nil, auditMetadata); err != nil {
 				fmt.Printf("Warning: failed to create bulk update audit entry for token %s: %v\n", tokenID, err)
 			}
 		}
 
 		return nil
-	})
+	}
+
+	err := database.WithRetry(func() error {
+		return r.db.Transaction(update)
+	}, r.retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenIDs, nil
+}
+
+// CreateAuditEntryWithTx records an operation on a token that carries metadata, such as a
+// reason code, but no status or ownership change of its own
+func (r *tokenRepository) CreateAuditEntryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, operation string, metadata map[string]interface{}) error {
+	if err := r.createAuditEntry(ctx, tx, tokenID, operation, "", "", uuid.Nil, uuid.Nil, metadata); err != nil {
+		return fmt.Errorf("failed to create %s audit entry: %w", operation, err)
+	}
+	return nil
+}
+
+// GetFreezeEventsBetween returns a page of freezes recorded at or after from and before to,
+// oldest first. A single-token freeze records its reason in a FREEZE entry directly after
+// the status change, so that entry's metadata is reported in place of the status change's.
+// Freezes after a failed sanctions re-screen are COMPLIANCE_UPDATE entries marked auto_frozen.
+func (r *tokenRepository) GetFreezeEventsBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]FreezeEvent, error) {
+	query := `
+		SELECT a.id, a.token_id, a.operation, a.timestamp, COALESCE(reason.metadata, a.metadata)
+		FROM token_audit_trail a
+		LEFT JOIN token_audit_trail reason
+			ON reason.token_id = a.token_id
+			AND reason.sequence = a.sequence + 1
+			AND reason.operation = 'FREEZE'
+		WHERE (a.operation IN ('STATUS_CHANGE', 'BULK_STATUS_UPDATE')
+				OR (a.operation = 'COMPLIANCE_UPDATE' AND a.metadata->>'auto_frozen' = 'true'))
+			AND a.new_status = $1
+			AND a.timestamp >= $2 AND a.timestamp < $3
+		ORDER BY a.timestamp ASC, a.id ASC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.QueryContext(ctx, query, models.TokenStatusFrozen, from, to, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query freeze events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []FreezeEvent
+	for rows.Next() {
+		var event FreezeEvent
+		var metadata []byte
+		if err := rows.Scan(&event.AuditID, &event.TokenID, &event.Operation, &event.FrozenAt, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan freeze event: %w", err)
+		}
+
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode freeze event metadata: %w", err)
+			}
+		}
+		if reasonCode, ok := event.Metadata["reason_code"].(string); ok {
+			event.ReasonCode = reasonCode
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating freeze event rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountFreezeEventsBetween counts freezes recorded at or after from and before to
+func (r *tokenRepository) CountFreezeEventsBetween(ctx context.Context, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM token_audit_trail
+		WHERE (operation IN ('STATUS_CHANGE', 'BULK_STATUS_UPDATE')
+				OR (operation = 'COMPLIANCE_UPDATE' AND metadata->>'auto_frozen' = 'true'))
+			AND new_status = $1
+			AND timestamp >= $2 AND timestamp < $3`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, models.TokenStatusFrozen, from, to).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count freeze events: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetAuditEntriesBetween returns up to limit audit entries for all tokens recorded at or after
+// from and before to, ordered by timestamp and then ID, including entries moved to the
+// archive. Only entries after the (afterTimestamp, afterID) cursor are returned, so a period
+// can be read a page at a time; pass from and uuid.Nil for the first page.
+func (r *tokenRepository) GetAuditEntriesBetween(ctx context.Context, from, to, afterTimestamp time.Time, afterID uuid.UUID, limit int) ([]TokenAuditEntry, error) {
+	query := `
+		SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+			   sequence, previous_hash, entry_hash, archived
+		FROM (
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   sequence, previous_hash, entry_hash, FALSE AS archived
+			FROM token_audit_trail
+			UNION ALL
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   sequence, previous_hash, entry_hash, TRUE AS archived
+			FROM token_audit_trail_archive
+		) entries
+		WHERE timestamp >= $1 AND timestamp < $2
+			AND (timestamp, id) > ($3, $4)
+		ORDER BY timestamp ASC, id ASC
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, afterTimestamp, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []TokenAuditEntry
+	for rows.Next() {
+		var entry TokenAuditEntry
+		var metadata []byte
+		var sequence sql.NullInt64
+		var previousHash, entryHash sql.NullString
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TokenID,
+			&entry.Operation,
+			&entry.OldStatus,
+			&entry.NewStatus,
+			&entry.OldOwner,
+			&entry.NewOwner,
+			&entry.Timestamp,
+			&metadata,
+			&sequence,
+			&previousHash,
+			&entryHash,
+			&entry.Archived,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode audit entry metadata: %w", err)
+			}
+		}
+		entry.Sequence = sequence.Int64
+		entry.PreviousHash = previousHash.String
+		entry.EntryHash = entryHash.String
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entry rows: %w", err)
+	}
+
+	return entries, nil
 }
 
-// GetAuditTrail retrieves the audit trail for a specific token
+// GetAuditTrail retrieves the audit trail for a specific token, newest first. Entries moved
+// to the archive are included and marked Archived; they are older than every live entry.
 func (r *tokenRepository) GetAuditTrail(ctx context.Context, tokenID uuid.UUID) ([]TokenAuditEntry, error) {
 	query := `
-		SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata
-		FROM token_audit_trail
-		WHERE token_id = $1
+		SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata, archived
+		FROM (
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   FALSE AS archived
+			FROM token_audit_trail
+			WHERE token_id = $1
+			UNION ALL
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   TRUE AS archived
+			FROM token_audit_trail_archive
+			WHERE token_id = $1
+		) trail
 		ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, tokenID)
@@ -461,6 +1070,7 @@ func (r *tokenRepository) GetAuditTrail(ctx context.Context, tokenID uuid.UUID)
 			&entry.NewOwner,
 			&entry.Timestamp,
 			&entry.Metadata,
+			&entry.Archived,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
@@ -475,41 +1085,1040 @@ func (r *tokenRepository) GetAuditTrail(ctx context.Context, tokenID uuid.UUID)
 	return entries, nil
 }
 
-// createAuditEntry creates an audit trail entry
-func (r *tokenRepository) createAuditEntry(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, operation string, oldStatus, newStatus models.TokenStatus, oldOwner, newOwner uuid.UUID, metadata map[string]interface{}) error {
+// GetAuditChain retrieves a token's audit entries in chain order, including archived entries
+// so the chain can be verified from its start. Entries written before hash chaining was
+// introduced have no sequence and are returned first.
+func (r *tokenRepository) GetAuditChain(ctx context.Context, tokenID uuid.UUID) ([]TokenAuditEntry, error) {
 	query := `
-		INSERT INTO token_audit_trail (
-			id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW(), $8
-		)`
+		SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+			   sequence, previous_hash, entry_hash
+		FROM (
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   sequence, previous_hash, entry_hash
+			FROM token_audit_trail_archive
+			WHERE token_id = $1
+			UNION ALL
+			SELECT id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+				   sequence, previous_hash, entry_hash
+			FROM token_audit_trail
+			WHERE token_id = $1
+		) chain
+		ORDER BY sequence ASC NULLS FIRST, timestamp ASC`
 
-	auditID := uuid.New()
-	var err error
+	rows, err := r.db.QueryContext(ctx, query, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit chain: %w", err)
+	}
+	defer rows.Close()
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query,
-			auditID,
-			tokenID,
-			operation,
-			oldStatus,
-			newStatus,
-			oldOwner,
-			newOwner,
-			metadata,
-		)
-	} else {
-		_, err = r.db.ExecContext(ctx, query,
-			auditID,
-			tokenID,
-			operation,
-			oldStatus,
-			newStatus,
-			oldOwner,
-			newOwner,
-			metadata,
+	var entries []TokenAuditEntry
+	for rows.Next() {
+		var entry TokenAuditEntry
+		var sequence sql.NullInt64
+		var previousHash, entryHash sql.NullString
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TokenID,
+			&entry.Operation,
+			&entry.OldStatus,
+			&entry.NewStatus,
+			&entry.OldOwner,
+			&entry.NewOwner,
+			&entry.Timestamp,
+			&entry.Metadata,
+			&sequence,
+			&previousHash,
+			&entryHash,
 		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Sequence = sequence.Int64
+		entry.PreviousHash = previousHash.String
+		entry.EntryHash = entryHash.String
+		entries = append(entries, entry)
 	}
 
-	return err
-}
\ No newline at end of file
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit chain rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetActiveBySeries retrieves active tokens for an issuer and series, ordered by token ID
+// and starting after afterID so large result sets can be paged
+func (r *tokenRepository) GetActiveBySeries(ctx context.Context, issuer, series string, afterID uuid.UUID, limit int) ([]models.Token, error) {
+	query := `
+		SELECT token_id, cbdc_type, denomination, current_owner, status,
+			   issue_timestamp, transaction_history, metadata, compliance_flags,
+			   created_at, updated_at, version
+		FROM tokens
+		WHERE status = $1
+		  AND metadata->>'issuer' = $2
+		  AND metadata->>'series' = $3
+		  AND token_id > $4
+		ORDER BY token_id
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, query, models.TokenStatusActive, issuer, series, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tokens by series: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []models.Token
+	for rows.Next() {
+		var token models.Token
+		err := rows.Scan(
+			&token.TokenID,
+			&token.CBDCType,
+			&token.Denomination,
+			&token.CurrentOwner,
+			&token.Status,
+			&token.IssueTimestamp,
+			&token.TransactionHistory,
+			&token.Metadata,
+			&token.ComplianceFlags,
+			&token.CreatedAt,
+			&token.UpdatedAt,
+			&token.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating token rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RecallWithTx invalidates the given active tokens and records a RECALL audit entry
+// with the recall reason for each one. Tokens that are no longer active are skipped.
+func (r *tokenRepository) RecallWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, reason string) (int, error) {
+	if len(tokenIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(tokenIDs))
+	args := make([]interface{}, len(tokenIDs)+2)
+
+	for i, tokenID := range tokenIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args[i+2] = tokenID
+	}
+	args[0] = models.TokenStatusInvalid
+	args[1] = models.TokenStatusActive
+
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET status = $1, updated_at = NOW(), version = version + 1
+		WHERE status = $2 AND token_id IN (%s)
+		RETURNING token_id`,
+		strings.Join(placeholders, ","),
+	)
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to recall tokens: %w", err)
+	}
+
+	var recalled []uuid.UUID
+	for rows.Next() {
+		var tokenID uuid.UUID
+		if err := rows.Scan(&tokenID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan recalled token: %w", err)
+		}
+		recalled = append(recalled, tokenID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("error iterating recalled token rows: %w", err)
+	}
+	rows.Close()
+
+	if err := r.refreshChecksums(ctx, tx, recalled); err != nil {
+		return 0, err
+	}
+
+	// Audit entries are required for recalls, so failures abort the operation
+	for _, tokenID := range recalled {
+		if err := r.createAuditEntry(ctx, tx, tokenID, "RECALL", models.TokenStatusActive, models.TokenStatusInvalid, uuid.Nil, uuid.Nil, map[string]interface{}{
+			"reason": reason,
+		}); err != nil {
+			return 0, fmt.Errorf("failed to create recall audit entry for token %s: %w", tokenID, err)
+		}
+	}
+
+	return len(recalled), nil
+}
+
+// SaveMerkleBatchWithTx persists an issuance batch root along with each token's proof path
+func (r *tokenRepository) SaveMerkleBatchWithTx(ctx context.Context, tx *sql.Tx, batchID uuid.UUID, root string, proofs []TokenMerkleProof) error {
+	batchQuery := `
+		INSERT INTO token_merkle_batches (batch_id, root, token_count, created_at)
+		VALUES ($1, $2, $3, NOW())`
+
+	proofQuery := `
+		INSERT INTO token_merkle_proofs (token_id, batch_id, leaf_index, path)
+		VALUES ($1, $2, $3, $4)`
+
+	exec := r.db.ExecContext
+	if tx != nil {
+		exec = tx.ExecContext
+	}
+
+	if _, err := exec(ctx, batchQuery, batchID, root, len(proofs)); err != nil {
+		return fmt.Errorf("failed to create merkle batch: %w", err)
+	}
+
+	for _, proof := range proofs {
+		path, err := json.Marshal(proof.Path)
+		if err != nil {
+			return fmt.Errorf("failed to marshal merkle proof for token %s: %w", proof.TokenID, err)
+		}
+
+		if _, err := exec(ctx, proofQuery, proof.TokenID, batchID, proof.LeafIndex, path); err != nil {
+			return fmt.Errorf("failed to store merkle proof for token %s: %w", proof.TokenID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetMerkleProof retrieves a token's stored proof path and the root of its issuance batch
+func (r *tokenRepository) GetMerkleProof(ctx context.Context, tokenID uuid.UUID) (*TokenMerkleProof, error) {
+	query := `
+		SELECT p.token_id, p.batch_id, p.leaf_index, p.path, b.root, b.created_at
+		FROM token_merkle_proofs p
+		JOIN token_merkle_batches b ON b.batch_id = p.batch_id
+		WHERE p.token_id = $1`
+
+	var proof TokenMerkleProof
+	var path []byte
+	err := r.db.QueryRowContext(ctx, query, tokenID).Scan(
+		&proof.TokenID,
+		&proof.BatchID,
+		&proof.LeafIndex,
+		&path,
+		&proof.BatchRoot,
+		&proof.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No proof recorded
+		}
+		return nil, fmt.Errorf("failed to get merkle proof: %w", err)
+	}
+
+	if err := json.Unmarshal(path, &proof.Path); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merkle proof: %w", err)
+	}
+
+	return &proof, nil
+}
+
+// SaveSignatureWithTx stores a token's issuance signature
+func (r *tokenRepository) SaveSignatureWithTx(ctx context.Context, tx *sql.Tx, signature *TokenSignature) error {
+	query := `
+		INSERT INTO token_signatures (token_id, key_id, algorithm, signature, issued_to, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, signature.TokenID, signature.KeyID, signature.Algorithm, signature.Signature, signature.IssuedTo)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, signature.TokenID, signature.KeyID, signature.Algorithm, signature.Signature, signature.IssuedTo)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to store token signature: %w", err)
+	}
+
+	return nil
+}
+
+// GetSignature retrieves a token's issuance signature
+func (r *tokenRepository) GetSignature(ctx context.Context, tokenID uuid.UUID) (*TokenSignature, error) {
+	query := `
+		SELECT token_id, key_id, algorithm, signature, issued_to, created_at
+		FROM token_signatures
+		WHERE token_id = $1`
+
+	var signature TokenSignature
+	var issuedTo uuid.NullUUID
+	err := r.db.QueryRowContext(ctx, query, tokenID).Scan(
+		&signature.TokenID,
+		&signature.KeyID,
+		&signature.Algorithm,
+		&signature.Signature,
+		&issuedTo,
+		&signature.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No signature recorded
+		}
+		return nil, fmt.Errorf("failed to get token signature: %w", err)
+	}
+	signature.IssuedTo = issuedTo.UUID
+
+	return &signature, nil
+}
+
+// GetRequiredSignersWithTx returns the number of co-signer approvals a wallet requires for
+// outgoing transfers, or zero when the wallet has no multi-sig policy
+func (r *tokenRepository) GetRequiredSignersWithTx(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) (int, error) {
+	query := `SELECT required_signers FROM wallet_signing_policies WHERE wallet_id = $1`
+
+	var requiredSigners int
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, walletID).Scan(&requiredSigners)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, walletID).Scan(&requiredSigners)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil // No multi-sig policy
+		}
+		return 0, fmt.Errorf("failed to get wallet signing policy: %w", err)
+	}
+
+	return requiredSigners, nil
+}
+
+// SetRequiredSigners sets the number of co-signer approvals a wallet requires and replaces the
+// co-signers authorized to give them; zero removes the policy along with its co-signers
+func (r *tokenRepository) SetRequiredSigners(ctx context.Context, walletID uuid.UUID, requiredSigners int, coSigners []uuid.UUID) error {
+	if requiredSigners == 0 {
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM wallet_signing_policies WHERE wallet_id = $1`, walletID); err != nil {
+			return fmt.Errorf("failed to remove wallet signing policy: %w", err)
+		}
+		return nil
+	}
+
+	return r.db.TransactionContext(ctx, func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO wallet_signing_policies (wallet_id, required_signers, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (wallet_id) DO UPDATE SET required_signers = $2, updated_at = NOW()`
+
+		if _, err := tx.ExecContext(ctx, query, walletID, requiredSigners); err != nil {
+			return fmt.Errorf("failed to set wallet signing policy: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM wallet_co_signers WHERE wallet_id = $1`, walletID); err != nil {
+			return fmt.Errorf("failed to clear wallet co-signers: %w", err)
+		}
+		for _, signerID := range coSigners {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO wallet_co_signers (wallet_id, signer_id) VALUES ($1, $2)`, walletID, signerID); err != nil {
+				return fmt.Errorf("failed to add wallet co-signer: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// IsCoSignerWithTx reports whether a signer is authorized to approve a wallet's transfers
+func (r *tokenRepository) IsCoSignerWithTx(ctx context.Context, tx *sql.Tx, walletID, signerID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM wallet_co_signers WHERE wallet_id = $1 AND signer_id = $2)`
+
+	var authorized bool
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, walletID, signerID).Scan(&authorized)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, walletID, signerID).Scan(&authorized)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check wallet co-signer: %w", err)
+	}
+
+	return authorized, nil
+}
+
+// CreatePendingTransferWithTx stores a transfer awaiting co-signer approval
+func (r *tokenRepository) CreatePendingTransferWithTx(ctx context.Context, tx *sql.Tx, transfer *PendingTransfer) error {
+	query := `
+		INSERT INTO pending_transfers (
+			id, token_id, from_owner, new_owner, transaction_id,
+			required_signers, status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	args := []interface{}{
+		transfer.ID,
+		transfer.TokenID,
+		transfer.FromOwner,
+		transfer.NewOwner,
+		transfer.TransactionID,
+		transfer.RequiredSigners,
+		transfer.Status,
+		transfer.CreatedAt,
+		transfer.UpdatedAt,
+	}
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, args...)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create pending transfer: %w", err)
+	}
+
+	if err := r.createAuditEntry(ctx, tx, transfer.TokenID, "TRANSFER_PENDING", "", "", transfer.FromOwner, transfer.NewOwner, map[string]interface{}{
+		"pending_transfer_id": transfer.ID,
+		"required_signers":    transfer.RequiredSigners,
+	}); err != nil {
+		fmt.Printf("Warning: failed to create pending transfer audit entry: %v\n", err)
+	}
+
+	return nil
+}
+
+// GetPendingTransferWithTx retrieves a pending transfer and its approvals. When called within
+// a transaction the pending transfer row is locked until commit.
+func (r *tokenRepository) GetPendingTransferWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID) (*PendingTransfer, error) {
+	query := `
+		SELECT id, token_id, from_owner, new_owner, transaction_id,
+			   required_signers, status, created_at, updated_at
+		FROM pending_transfers
+		WHERE id = $1`
+
+	approvalsQuery := `
+		SELECT signer_id FROM pending_transfer_approvals
+		WHERE pending_transfer_id = $1
+		ORDER BY approved_at`
+
+	var transfer PendingTransfer
+	var err error
+	var rows *sql.Rows
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query+" FOR UPDATE", pendingID).Scan(
+			&transfer.ID,
+			&transfer.TokenID,
+			&transfer.FromOwner,
+			&transfer.NewOwner,
+			&transfer.TransactionID,
+			&transfer.RequiredSigners,
+			&transfer.Status,
+			&transfer.CreatedAt,
+			&transfer.UpdatedAt,
+		)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, pendingID).Scan(
+			&transfer.ID,
+			&transfer.TokenID,
+			&transfer.FromOwner,
+			&transfer.NewOwner,
+			&transfer.TransactionID,
+			&transfer.RequiredSigners,
+			&transfer.Status,
+			&transfer.CreatedAt,
+			&transfer.UpdatedAt,
+		)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Pending transfer not found
+		}
+		return nil, fmt.Errorf("failed to get pending transfer: %w", err)
+	}
+
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, approvalsQuery, pendingID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, approvalsQuery, pendingID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer approvals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var signerID uuid.UUID
+		if err := rows.Scan(&signerID); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer approval: %w", err)
+		}
+		transfer.Approvals = append(transfer.Approvals, signerID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfer approval rows: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// AddTransferApprovalWithTx records a co-signer's approval of a pending transfer
+func (r *tokenRepository) AddTransferApprovalWithTx(ctx context.Context, tx *sql.Tx, pendingID, signerID uuid.UUID) error {
+	query := `
+		INSERT INTO pending_transfer_approvals (pending_transfer_id, signer_id, approved_at)
+		VALUES ($1, $2, NOW())`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, pendingID, signerID)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, pendingID, signerID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to record transfer approval: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePendingTransferStatusWithTx updates the status of a pending transfer
+func (r *tokenRepository) UpdatePendingTransferStatusWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID, status PendingTransferStatus) error {
+	query := `UPDATE pending_transfers SET status = $2, updated_at = NOW() WHERE id = $1`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, pendingID, status)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, pendingID, status)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update pending transfer status: %w", err)
+	}
+
+	return nil
+}
+
+// HasPendingTransferWithTx reports whether a token has a transfer still awaiting co-signer
+// approval
+func (r *tokenRepository) HasPendingTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM pending_transfers
+			WHERE token_id = $1 AND status = $2
+		)`
+
+	var pending bool
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, tokenID, PendingTransferStatusPending).Scan(&pending)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, tokenID, PendingTransferStatusPending).Scan(&pending)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check pending transfers: %w", err)
+	}
+
+	return pending, nil
+}
+
+// GetPendingTransfersByToken returns every multi-sig transfer of a token, whatever its status,
+// newest first. Approvals are not loaded.
+func (r *tokenRepository) GetPendingTransfersByToken(ctx context.Context, tokenID uuid.UUID) ([]PendingTransfer, error) {
+	query := `
+		SELECT id, token_id, from_owner, new_owner, transaction_id,
+			   required_signers, status, created_at, updated_at
+		FROM pending_transfers
+		WHERE token_id = $1
+		ORDER BY created_at DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []PendingTransfer
+	for rows.Next() {
+		var transfer PendingTransfer
+		err := rows.Scan(
+			&transfer.ID,
+			&transfer.TokenID,
+			&transfer.FromOwner,
+			&transfer.NewOwner,
+			&transfer.TransactionID,
+			&transfer.RequiredSigners,
+			&transfer.Status,
+			&transfer.CreatedAt,
+			&transfer.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pending transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending transfer rows: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// RecordSanctionsBlockWithTx records a SANCTIONS_BLOCK audit entry for a transfer that was
+// stopped by sanctions screening, including the matching list reference
+func (r *tokenRepository) RecordSanctionsBlockWithTx(ctx context.Context, tx *sql.Tx, block *SanctionsBlock) error {
+	if err := r.createAuditEntry(ctx, tx, block.TokenID, "SANCTIONS_BLOCK", "", "", block.FromOwner, block.ToOwner, map[string]interface{}{
+		"blocked_party": block.BlockedParty,
+		"list_ref":      block.ListRef,
+	}); err != nil {
+		return fmt.Errorf("failed to create sanctions block audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateComplianceFlagsWithTx persists a token's compliance flags and status and records
+// a COMPLIANCE_UPDATE audit entry with the previous and new flag values. Like UpdateWithTx
+// it is guarded by the token version.
+func (r *tokenRepository) UpdateComplianceFlagsWithTx(ctx context.Context, tx *sql.Tx, token *models.Token, previousFlags models.ComplianceFlags, previousStatus models.TokenStatus) error {
+	query := `
+		UPDATE tokens SET
+			compliance_flags = $2,
+			status = $3,
+			updated_at = $4,
+			version = version + 1,
+			checksum = $6
+		WHERE token_id = $1 AND version = $5`
+
+	checksum, err := TokenChecksum(token)
+	if err != nil {
+		return err
+	}
+
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, token.TokenID, token.ComplianceFlags, token.Status, token.UpdatedAt, token.Version, checksum)
+	} else {
+		result, err = r.db.ExecContext(ctx, query, token.TokenID, token.ComplianceFlags, token.Status, token.UpdatedAt, token.Version, checksum)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update compliance flags: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check compliance update result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewTokenManagementError(
+			errors.ErrConcurrentModification,
+			"token was modified concurrently",
+		).WithDetails(map[string]interface{}{
+			"token_id":         token.TokenID,
+			"expected_version": token.Version,
+		})
+	}
+	token.Version++
+
+	// Audit entries are required for compliance changes, so failures abort the update
+	if err := r.createAuditEntry(ctx, tx, token.TokenID, "COMPLIANCE_UPDATE", previousStatus, token.Status, uuid.Nil, uuid.Nil, map[string]interface{}{
+		"old_flags":   previousFlags,
+		"new_flags":   token.ComplianceFlags,
+		"auto_frozen": previousStatus != token.Status && token.Status == models.TokenStatusFrozen,
+	}); err != nil {
+		return fmt.Errorf("failed to create compliance audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetIssuerQuotaForUpdateWithTx returns the issuance quota for an issuer's series and CBDC type,
+// locking the row until the transaction ends so concurrent issuances are serialized. It returns
+// nil when no quota is configured.
+func (r *tokenRepository) GetIssuerQuotaForUpdateWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType) (*IssuerQuota, error) {
+	query := `
+		SELECT issuer, series, cbdc_type, minted_total, quota, updated_at
+		FROM issuer_quotas
+		WHERE issuer = $1 AND series = $2 AND cbdc_type = $3`
+
+	var quota IssuerQuota
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query+" FOR UPDATE", issuer, series, cbdcType).Scan(
+			&quota.Issuer,
+			&quota.Series,
+			&quota.CBDCType,
+			&quota.MintedTotal,
+			&quota.Quota,
+			&quota.UpdatedAt,
+		)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, issuer, series, cbdcType).Scan(
+			&quota.Issuer,
+			&quota.Series,
+			&quota.CBDCType,
+			&quota.MintedTotal,
+			&quota.Quota,
+			&quota.UpdatedAt,
+		)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No quota configured
+		}
+		return nil, fmt.Errorf("failed to get issuer quota: %w", err)
+	}
+
+	return &quota, nil
+}
+
+// AddMintedTotalWithTx adds newly minted value to an issuer's running mint total
+func (r *tokenRepository) AddMintedTotalWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType, amount float64) error {
+	query := `
+		UPDATE issuer_quotas
+		SET minted_total = minted_total + $4, updated_at = NOW()
+		WHERE issuer = $1 AND series = $2 AND cbdc_type = $3`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, issuer, series, cbdcType, amount)
+	} else {
+		result, err = r.db.ExecContext(ctx, query, issuer, series, cbdcType, amount)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update minted total: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check minted total update result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("issuer quota not found for %s/%s/%s", issuer, series, cbdcType)
+	}
+
+	return nil
+}
+
+// GetIssuerQuotas returns every issuance quota configured for an issuer
+func (r *tokenRepository) GetIssuerQuotas(ctx context.Context, issuer string) ([]IssuerQuota, error) {
+	query := `
+		SELECT issuer, series, cbdc_type, minted_total, quota, updated_at
+		FROM issuer_quotas
+		WHERE issuer = $1
+		ORDER BY series, cbdc_type`
+
+	rows, err := r.db.QueryContext(ctx, query, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issuer quotas: %w", err)
+	}
+	defer rows.Close()
+
+	var quotas []IssuerQuota
+	for rows.Next() {
+		var quota IssuerQuota
+		if err := rows.Scan(
+			&quota.Issuer,
+			&quota.Series,
+			&quota.CBDCType,
+			&quota.MintedTotal,
+			&quota.Quota,
+			&quota.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan issuer quota: %w", err)
+		}
+		quotas = append(quotas, quota)
+	}
+
+	return quotas, rows.Err()
+}
+
+// SetIssuerQuota creates or replaces the quota for an issuer's series and CBDC type. The running
+// mint total is preserved when an existing quota is changed.
+func (r *tokenRepository) SetIssuerQuota(ctx context.Context, issuer, series string, cbdcType models.CBDCType, quota float64) (*IssuerQuota, error) {
+	query := `
+		INSERT INTO issuer_quotas (issuer, series, cbdc_type, minted_total, quota, updated_at)
+		VALUES ($1, $2, $3, 0, $4, NOW())
+		ON CONFLICT (issuer, series, cbdc_type) DO UPDATE SET quota = $4, updated_at = NOW()
+		RETURNING issuer, series, cbdc_type, minted_total, quota, updated_at`
+
+	var result IssuerQuota
+	err := r.db.QueryRowContext(ctx, query, issuer, series, cbdcType, quota).Scan(
+		&result.Issuer,
+		&result.Series,
+		&result.CBDCType,
+		&result.MintedTotal,
+		&result.Quota,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set issuer quota: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SetFrozenUntilWithTx sets when a frozen token is automatically unfrozen; nil clears the
+// expiry so the token stays frozen until it is unfrozen manually
+func (r *tokenRepository) SetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, frozenUntil *time.Time) error {
+	query := `UPDATE tokens SET frozen_until = $2 WHERE token_id = $1`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, tokenID, frozenUntil)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, tokenID, frozenUntil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to set token freeze expiry: %w", err)
+	}
+
+	return nil
+}
+
+// GetFrozenUntilWithTx returns a token's freeze expiry, or nil if it has none
+func (r *tokenRepository) GetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error) {
+	query := `SELECT frozen_until FROM tokens WHERE token_id = $1`
+
+	var frozenUntil sql.NullTime
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, tokenID).Scan(&frozenUntil)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, tokenID).Scan(&frozenUntil)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get token freeze expiry: %w", err)
+	}
+
+	if !frozenUntil.Valid {
+		return nil, nil
+	}
+	return &frozenUntil.Time, nil
+}
+
+// GetExpiredFreezes returns up to limit frozen tokens whose freeze expiry is at or before now,
+// oldest expiry first
+func (r *tokenRepository) GetExpiredFreezes(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT token_id FROM tokens
+		WHERE status = $1 AND frozen_until IS NOT NULL AND frozen_until <= $2
+		ORDER BY frozen_until
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, models.TokenStatusFrozen, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired freezes: %w", err)
+	}
+	defer rows.Close()
+
+	var tokenIDs []uuid.UUID
+	for rows.Next() {
+		var tokenID uuid.UUID
+		if err := rows.Scan(&tokenID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired freeze: %w", err)
+		}
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate expired freezes: %w", err)
+	}
+
+	return tokenIDs, nil
+}
+
+// ArchiveTransactionHistoryWithTx appends transaction IDs trimmed from a token's in-row
+// history to the token_history archive, after any entries archived earlier
+func (r *tokenRepository) ArchiveTransactionHistoryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, transactionIDs []uuid.UUID) error {
+	if len(transactionIDs) == 0 {
+		return nil
+	}
+
+	positionQuery := `SELECT COALESCE(MAX(position) + 1, 0) FROM token_history WHERE token_id = $1`
+	insertQuery := `
+		INSERT INTO token_history (token_id, position, transaction_id)
+		VALUES ($1, $2, $3)`
+
+	queryRow := r.db.QueryRowContext
+	exec := r.db.ExecContext
+	if tx != nil {
+		queryRow = tx.QueryRowContext
+		exec = tx.ExecContext
+	}
+
+	var position int64
+	if err := queryRow(ctx, positionQuery, tokenID).Scan(&position); err != nil {
+		return fmt.Errorf("failed to get token history position: %w", err)
+	}
+
+	for i, transactionID := range transactionIDs {
+		if _, err := exec(ctx, insertQuery, tokenID, position+int64(i), transactionID); err != nil {
+			return fmt.Errorf("failed to archive token history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetArchivedTransactionHistory returns a token's archived transaction IDs, oldest first
+func (r *tokenRepository) GetArchivedTransactionHistory(ctx context.Context, tokenID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT transaction_id FROM token_history WHERE token_id = $1 ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token history: %w", err)
+	}
+	defer rows.Close()
+
+	var transactionIDs []uuid.UUID
+	for rows.Next() {
+		var transactionID uuid.UUID
+		if err := rows.Scan(&transactionID); err != nil {
+			return nil, fmt.Errorf("failed to scan token history: %w", err)
+		}
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate token history: %w", err)
+	}
+
+	return transactionIDs, nil
+}
+
+// createAuditEntry creates an audit trail entry chained to the token's previous entry.
+// Each entry stores the previous entry's hash and a hash over its own fields, so any later
+// modification or deletion of a row breaks the chain.
+func (r *tokenRepository) createAuditEntry(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, operation string, oldStatus, newStatus models.TokenStatus, oldOwner, newOwner uuid.UUID, metadata map[string]interface{}) error {
+	// Lock the latest chained entry so concurrent writers cannot fork the chain; the unique
+	// (token_id, sequence) index rejects the loser when the token has no entries yet
+	previousQuery := `
+		SELECT sequence, entry_hash
+		FROM token_audit_trail
+		WHERE token_id = $1 AND entry_hash IS NOT NULL
+		ORDER BY sequence DESC
+		LIMIT 1
+		FOR UPDATE`
+
+	var previousSequence int64
+	var previousHash string
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, previousQuery, tokenID).Scan(&previousSequence, &previousHash)
+	} else {
+		err = r.db.QueryRowContext(ctx, previousQuery, tokenID).Scan(&previousSequence, &previousHash)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get previous audit entry: %w", err)
+	}
+
+	entry := TokenAuditEntry{
+		ID:           uuid.New(),
+		TokenID:      tokenID,
+		Operation:    operation,
+		OldStatus:    oldStatus,
+		NewStatus:    newStatus,
+		OldOwner:     oldOwner,
+		NewOwner:     newOwner,
+		Timestamp:    sql.NullTime{Time: time.Now().UTC().Truncate(time.Microsecond), Valid: true}, // Postgres precision
+		Metadata:     metadata,
+		Sequence:     previousSequence + 1,
+		PreviousHash: previousHash,
+	}
+	entry.EntryHash, err = AuditEntryHash(entry)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO token_audit_trail (
+			id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+			sequence, previous_hash, entry_hash
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query,
+			entry.ID,
+			entry.TokenID,
+			entry.Operation,
+			entry.OldStatus,
+			entry.NewStatus,
+			entry.OldOwner,
+			entry.NewOwner,
+			entry.Timestamp.Time,
+			entry.Metadata,
+			entry.Sequence,
+			entry.PreviousHash,
+			entry.EntryHash,
+		)
+	} else {
+		_, err = r.db.ExecContext(ctx, query,
+			entry.ID,
+			entry.TokenID,
+			entry.Operation,
+			entry.OldStatus,
+			entry.NewStatus,
+			entry.OldOwner,
+			entry.NewOwner,
+			entry.Timestamp.Time,
+			entry.Metadata,
+			entry.Sequence,
+			entry.PreviousHash,
+			entry.EntryHash,
+		)
+	}
+
+	if err != nil {
+		return auditInsertError(tokenID, err)
+	}
+	return nil
+}
+
+// tokenInsertError converts a failed token insert into an error for the caller. A token ID
+// that already exists is reported as a duplicate token rather than a database failure.
+func tokenInsertError(tokenID uuid.UUID, err error) error {
+	if database.IsUniqueViolation(err) {
+		return errors.NewTokenManagementError(
+			errors.ErrDuplicateToken,
+			fmt.Sprintf("token %s already exists", tokenID),
+		).WithDetails(map[string]interface{}{
+			"token_id": tokenID,
+		})
+	}
+	return fmt.Errorf("failed to create token: %w", err)
+}
+
+// auditInsertError converts a failed audit entry insert into an error for the caller. A
+// duplicate sequence means another writer appended to the token's chain first, which is
+// reported as a concurrent modification so the operation can be retried.
+func auditInsertError(tokenID uuid.UUID, err error) error {
+	if database.IsUniqueViolation(err) {
+		return errors.NewTokenManagementError(
+			errors.ErrConcurrentModification,
+			fmt.Sprintf("audit trail of token %s was appended to concurrently", tokenID),
+		).WithDetails(map[string]interface{}{
+			"token_id": tokenID,
+		})
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+)
+
+func TestTokenInsertError(t *testing.T) {
+	tokenID := uuid.New()
+
+	t.Run("unique violation is a duplicate token", func(t *testing.T) {
+		err := tokenInsertError(tokenID, &pq.Error{Code: "23505", Constraint: "tokens_pkey"})
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError, got %v", err)
+		assert.Equal(t, errors.ErrDuplicateToken, tokenErr.Code)
+		assert.Equal(t, 409, tokenErr.GetHTTPStatus())
+		assert.Equal(t, tokenID, tokenErr.Details["token_id"])
+	})
+
+	t.Run("other failures stay wrapped database errors", func(t *testing.T) {
+		cause := &pq.Error{Code: "23502"}
+		err := tokenInsertError(tokenID, cause)
+
+		_, ok := err.(*errors.EchoPayError)
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, cause)
+	})
+}
+
+func TestAuditInsertError(t *testing.T) {
+	tokenID := uuid.New()
+
+	err := auditInsertError(tokenID, fmt.Errorf("insert failed: %w", &pq.Error{Code: "23505"}))
+
+	tokenErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok, "Expected EchoPayError, got %v", err)
+	assert.Equal(t, errors.ErrConcurrentModification, tokenErr.Code)
+	assert.Equal(t, 409, tokenErr.GetHTTPStatus())
+
+	cause := fmt.Errorf("connection reset")
+	assert.Equal(t, cause, auditInsertError(tokenID, cause))
+}
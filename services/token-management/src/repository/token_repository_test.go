@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	
+	"echopay/shared/libraries/database"
+	"echopay/token-management/src/migrations"
 	"echopay/token-management/src/models"
 )
 
@@ -119,21 +121,22 @@ func TestTokenRepository_Create(t *testing.T) {
 		INSERT INTO tokens (
 			token_id, cbdc_type, denomination, current_owner, status,
 			issue_timestamp, transaction_history, metadata, compliance_flags,
-			created_at, updated_at
+			created_at, updated_at, version
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 		)`
-				}), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+				}), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 				
 				// Mock the audit trail insert
 				db.On("ExecContext", mock.Anything, mock.MatchedBy(func(query string) bool {
 					return query == `
 		INSERT INTO token_audit_trail (
-			id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata
+			id, token_id, operation, old_status, new_status, old_owner, new_owner, timestamp, metadata,
+			sequence, previous_hash, entry_hash
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW(), $8
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 		)`
-				}), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+				}), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 			},
 			expectError: false,
 		},
@@ -188,8 +191,9 @@ func TestTokenRepository_GetByOwner(t *testing.T) {
 			   created_at, updated_at
 		FROM tokens
 		WHERE current_owner = $1
-		ORDER BY created_at DESC`
-				}), ownerID).Return((*sql.Rows)(nil), sql.ErrNoRows) // Simplified for testing
+		ORDER BY created_at DESC, token_id
+		LIMIT $2 OFFSET $3`
+				}), ownerID, DefaultTokenPageSize, 0).Return((*sql.Rows)(nil), sql.ErrNoRows) // Simplified for testing
 			},
 			expectTokens: 0,
 			expectError:  false,
@@ -206,7 +210,7 @@ func TestTokenRepository_GetByOwner(t *testing.T) {
 
 			tt.setupMocks(mockDB)
 
-			tokens, err := repo.GetByOwner(context.Background(), tt.ownerID)
+			tokens, err := repo.GetByOwner(context.Background(), tt.ownerID, 0, 0)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -267,7 +271,7 @@ func TestTokenRepository_BulkUpdateStatus(t *testing.T) {
 
 			tt.setupMocks(mockDB)
 
-			err := repo.BulkUpdateStatus(context.Background(), tt.tokenIDs, tt.status)
+			err := repo.BulkUpdateStatus(context.Background(), tt.tokenIDs, tt.status, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -367,7 +371,7 @@ func TestTokenRepository_ConcurrentBulkOperations(t *testing.T) {
 			// Execute operations sequentially (simulating concurrent access)
 			var errors []error
 			for _, op := range tt.operations {
-				err := repo.BulkUpdateStatus(context.Background(), op.tokenIDs, op.status)
+				err := repo.BulkUpdateStatus(context.Background(), op.tokenIDs, op.status, nil)
 				if err != nil {
 					errors = append(errors, err)
 				}
@@ -402,7 +406,7 @@ func TestTokenRepository_BulkOperationAuditTrail(t *testing.T) {
 		// Mock transaction
 		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 
-		err := repo.BulkUpdateStatus(context.Background(), tokenIDs, newStatus)
+		err := repo.BulkUpdateStatus(context.Background(), tokenIDs, newStatus, nil)
 
 		assert.NoError(t, err)
 		mockDB.AssertExpectations(t)
@@ -479,8 +483,9 @@ func TestTokenRepository_StatusFiltering(t *testing.T) {
 			   created_at, updated_at
 		FROM tokens
 		WHERE status = $1
-		ORDER BY created_at DESC`
-				}), status).Return((*sql.Rows)(nil), sql.ErrNoRows) // Simplified for testing
+		ORDER BY created_at DESC, token_id
+		LIMIT $2 OFFSET $3`
+				}), status, 50, 100).Return((*sql.Rows)(nil), sql.ErrNoRows) // Simplified for testing
 			},
 			expectError: false, // We expect an error due to our simplified mock, but the query structure is correct
 		},
@@ -496,7 +501,7 @@ func TestTokenRepository_StatusFiltering(t *testing.T) {
 
 			tt.setupMocks(mockDB)
 
-			tokens, err := repo.GetByStatus(context.Background(), tt.status)
+			tokens, err := repo.GetByStatus(context.Background(), tt.status, 50, 100)
 
 			// Note: This test is simplified due to the complexity of mocking sql.Rows
 			assert.Error(t, err) // We expect an error due to our simplified mock
@@ -557,7 +562,7 @@ func TestTokenRepository_BulkOperationTransactionSafety(t *testing.T) {
 
 			tt.setupMocks(mockDB)
 
-			err := repo.BulkUpdateStatus(context.Background(), tt.tokenIDs, tt.status)
+			err := repo.BulkUpdateStatus(context.Background(), tt.tokenIDs, tt.status, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -568,4 +573,182 @@ func TestTokenRepository_BulkOperationTransactionSafety(t *testing.T) {
 			mockDB.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+// setupFreezeReportDB connects to the test database, skipping the test when it is unavailable
+func setupFreezeReportDB(t testing.TB) *database.PostgresDB {
+	db, err := database.NewPostgresDB(database.DatabaseConfig{
+		Host:            "localhost",
+		Port:            5432,
+		Database:        "echopay_tokens_test",
+		User:            "echopay",
+		Password:        "echopay_dev",
+		SSLMode:         "disable",
+		MaxOpenConns:    5,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: 5 * time.Minute,
+	})
+	if err != nil {
+		t.Skipf("Skipping database tests: %v", err)
+	}
+
+	if err := db.Migrate(migrations.GetTokenMigrations()); err != nil {
+		db.Close()
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return db
+}
+
+func TestTokenRepository_GetFreezeEventsBetween(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tokenIDs := make([]uuid.UUID, 7)
+	for i := range tokenIDs {
+		tokenIDs[i] = uuid.New()
+		err := repo.Create(ctx, &models.Token{
+			TokenID:            tokenIDs[i],
+			CBDCType:           models.CBDCTypeUSD,
+			Denomination:       100.0,
+			CurrentOwner:       uuid.New(),
+			Status:             models.TokenStatusActive,
+			IssueTimestamp:     from.Add(-time.Hour),
+			TransactionHistory: make(models.UUIDArray, 0),
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+	}
+	defer func() {
+		for _, tokenID := range tokenIDs {
+			db.Exec(`DELETE FROM tokens WHERE token_id = $1`, tokenID)
+		}
+	}()
+
+	seed := func(tokenID uuid.UUID, sequence int64, operation string, newStatus models.TokenStatus, at time.Time, metadata string) {
+		_, err := db.Exec(`
+			INSERT INTO token_audit_trail (id, token_id, operation, new_status, timestamp, metadata, sequence)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6::jsonb, $7)`,
+			uuid.New(), tokenID, operation, newStatus, at, metadata, 100+sequence)
+		if err != nil {
+			t.Fatalf("Failed to seed audit entry: %v", err)
+		}
+	}
+
+	// Just before the period: excluded
+	seed(tokenIDs[0], 1, "STATUS_CHANGE", models.TokenStatusFrozen, from.Add(-time.Microsecond), `{}`)
+	// At the start of the period, with its reason in the following FREEZE entry: included
+	seed(tokenIDs[1], 1, "STATUS_CHANGE", models.TokenStatusFrozen, from, `{}`)
+	seed(tokenIDs[1], 2, "FREEZE", "", from, `{"reason_code": "sanctions_hit", "note": "list update"}`)
+	// Bulk freeze inside the period: included
+	seed(tokenIDs[2], 1, "BULK_STATUS_UPDATE", models.TokenStatusFrozen, from.Add(24*time.Hour), `{"bulk_operation": true, "reason_code": "dispute"}`)
+	// Unfreeze inside the period: excluded
+	seed(tokenIDs[3], 1, "STATUS_CHANGE", models.TokenStatusActive, from.Add(48*time.Hour), `{}`)
+	// At the end of the period: excluded
+	seed(tokenIDs[4], 1, "STATUS_CHANGE", models.TokenStatusFrozen, to, `{}`)
+	// Frozen by a failed sanctions re-screen: included
+	seed(tokenIDs[5], 1, "COMPLIANCE_UPDATE", models.TokenStatusFrozen, from.Add(72*time.Hour), `{"auto_frozen": true}`)
+	// Flags updated on an already frozen token: excluded
+	seed(tokenIDs[6], 1, "COMPLIANCE_UPDATE", models.TokenStatusFrozen, from.Add(96*time.Hour), `{"auto_frozen": false}`)
+
+	events, err := repo.GetFreezeEventsBetween(ctx, from, to, 10, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var found []FreezeEvent
+	for _, event := range events {
+		for _, tokenID := range tokenIDs {
+			if event.TokenID == tokenID {
+				found = append(found, event)
+			}
+		}
+	}
+
+	if assert.Len(t, found, 3) {
+		assert.Equal(t, tokenIDs[1], found[0].TokenID)
+		assert.Equal(t, "STATUS_CHANGE", found[0].Operation)
+		assert.Equal(t, "sanctions_hit", found[0].ReasonCode)
+		assert.Equal(t, "list update", found[0].Metadata["note"])
+
+		assert.Equal(t, tokenIDs[2], found[1].TokenID)
+		assert.Equal(t, "BULK_STATUS_UPDATE", found[1].Operation)
+		assert.Equal(t, "dispute", found[1].ReasonCode)
+
+		assert.Equal(t, tokenIDs[5], found[2].TokenID)
+		assert.Equal(t, "COMPLIANCE_UPDATE", found[2].Operation)
+	}
+
+	count, err := repo.CountFreezeEventsBetween(ctx, from, to)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 3)
+
+	page, err := repo.GetFreezeEventsBetween(ctx, from, to, 1, 0)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+}
+
+func TestTokenRepository_GetByIDs(t *testing.T) {
+	db := setupFreezeReportDB(t)
+	defer db.Close()
+
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+
+	present := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, tokenID := range present {
+		err := repo.Create(ctx, &models.Token{
+			TokenID:            tokenID,
+			CBDCType:           models.CBDCTypeUSD,
+			Denomination:       100.0,
+			CurrentOwner:       uuid.New(),
+			Status:             models.TokenStatusActive,
+			IssueTimestamp:     time.Now(),
+			TransactionHistory: make(models.UUIDArray, 0),
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+	}
+	defer func() {
+		for _, tokenID := range present {
+			db.Exec(`DELETE FROM tokens WHERE token_id = $1`, tokenID)
+		}
+	}()
+
+	absent := []uuid.UUID{uuid.New(), uuid.New()}
+	ids := []uuid.UUID{absent[0], present[0], present[1], absent[1], present[2]}
+
+	tokens, err := repo.GetByIDs(ctx, ids)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, tokens, len(present))
+	for _, tokenID := range present {
+		if assert.Contains(t, tokens, tokenID) {
+			assert.Equal(t, tokenID, tokens[tokenID].TokenID)
+			assert.Equal(t, 100.0, tokens[tokenID].Denomination)
+		}
+	}
+	for _, tokenID := range absent {
+		assert.NotContains(t, tokens, tokenID)
+	}
+
+	empty, err := repo.GetByIDs(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = repo.GetByIDs(ctx, make([]uuid.UUID, MaxBatchFetchSize+1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecordTransferNonceWithTx marks a transfer nonce as used by owner until expiresAt and reports
+// whether it was free. A nonce still unexpired at now is not recorded again. The owner's expired
+// nonces are removed first, so the table only holds nonces that could still be replayed. Within
+// a transaction the nonce is released again if the transaction rolls back.
+func (r *tokenRepository) RecordTransferNonceWithTx(ctx context.Context, tx *sql.Tx, ownerID uuid.UUID, nonce string, now, expiresAt time.Time) (bool, error) {
+	exec := r.db.ExecContext
+	if tx != nil {
+		exec = tx.ExecContext
+	}
+
+	if _, err := exec(ctx, `DELETE FROM transfer_nonces WHERE owner_id = $1 AND expires_at < $2`, ownerID, now); err != nil {
+		return false, fmt.Errorf("failed to remove expired transfer nonces: %w", err)
+	}
+
+	result, err := exec(ctx, `
+		INSERT INTO transfer_nonces (owner_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (owner_id, nonce) DO NOTHING`,
+		ownerID, nonce, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record transfer nonce: %w", err)
+	}
+
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record transfer nonce: %w", err)
+	}
+
+	return recorded == 1, nil
+}
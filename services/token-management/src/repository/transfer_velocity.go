@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CountOwnershipTransfersSinceWithTx counts the ownership transfers recorded in a token's audit
+// trail at or after since. Archived entries are not counted, so the window should stay shorter
+// than the audit retention period.
+func (r *tokenRepository) CountOwnershipTransfersSinceWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM token_audit_trail
+		WHERE token_id = $1 AND operation = 'OWNERSHIP_TRANSFER' AND timestamp >= $2`
+
+	var count int
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, tokenID, since).Scan(&count)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, tokenID, since).Scan(&count)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count ownership transfers: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLastOwnershipTransferWithTx returns when a token last changed hands according to its
+// audit trail, or nil if it never has
+func (r *tokenRepository) GetLastOwnershipTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error) {
+	query := `
+		SELECT MAX(timestamp) FROM token_audit_trail
+		WHERE token_id = $1 AND operation = 'OWNERSHIP_TRANSFER'`
+
+	var last sql.NullTime
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, tokenID).Scan(&last)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, tokenID).Scan(&last)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last ownership transfer: %w", err)
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+
+	return &last.Time, nil
+}
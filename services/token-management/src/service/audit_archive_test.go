@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_AuditTrailAcrossArchiveBoundary(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The chain is linked the way the repository writes it, then split where archival would
+	// split it: the oldest entries move to the archive and the head stays live
+	chain := []repository.TokenAuditEntry{
+		{Operation: "CREATE", NewStatus: models.TokenStatusActive, NewOwner: owner},
+		{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusActive, NewStatus: models.TokenStatusFrozen},
+		{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusFrozen, NewStatus: models.TokenStatusActive},
+		{Operation: "OWNERSHIP_TRANSFER", OldOwner: owner, NewOwner: uuid.New()},
+	}
+	previousHash := ""
+	for i := range chain {
+		chain[i].ID = uuid.New()
+		chain[i].TokenID = tokenID
+		chain[i].Sequence = int64(i + 1)
+		chain[i].PreviousHash = previousHash
+		chain[i].Timestamp = sql.NullTime{Time: start.Add(time.Duration(i) * 30 * 24 * time.Hour), Valid: true}
+		hash, err := repository.AuditEntryHash(chain[i])
+		require.NoError(t, err)
+		chain[i].EntryHash = hash
+		previousHash = hash
+	}
+	archived := []repository.TokenAuditEntry{chain[1], chain[0]}
+	for i := range archived {
+		archived[i].Archived = true
+	}
+	live := []repository.TokenAuditEntry{chain[3], chain[2]}
+
+	newService := func() (*TokenService, *MockTokenRepository) {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetAuditTrail", mock.Anything, tokenID).Return(append(append([]repository.TokenAuditEntry{}, live...), archived...), nil)
+		mockRepo.On("GetAuditChain", mock.Anything, tokenID).Return(chain, nil)
+		return NewTokenServiceWithDeps(mockRepo, nil), mockRepo
+	}
+
+	t.Run("chain verifies across the archive boundary", func(t *testing.T) {
+		service, _ := newService()
+
+		assert.Equal(t, archived[0].EntryHash, live[len(live)-1].PreviousHash)
+
+		result, err := service.VerifyAuditTrail(context.Background(), tokenID)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Reason)
+		assert.Equal(t, len(chain), result.EntriesVerified)
+	})
+
+	t.Run("archived entries follow live entries when included", func(t *testing.T) {
+		service, _ := newService()
+
+		auditTrail, err := service.GetTokenAuditTrail(context.Background(), tokenID, true)
+		require.NoError(t, err)
+		require.Len(t, auditTrail, len(chain))
+		for i, entry := range auditTrail {
+			assert.Equal(t, int64(len(chain)-i), entry.Sequence)
+		}
+	})
+
+	t.Run("archived entries are excluded by default", func(t *testing.T) {
+		service, _ := newService()
+
+		auditTrail, err := service.GetTokenAuditTrail(context.Background(), tokenID, false)
+		require.NoError(t, err)
+		assert.Equal(t, live, auditTrail)
+	})
+}
+
+func TestTokenService_ArchiveAuditTrail(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("archives entries before the cutoff", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+		service.SetClock(clock.NewFake(now))
+
+		cutoff := now.Add(-90 * 24 * time.Hour)
+		mockRepo.On("ArchiveAuditTrail", mock.Anything, cutoff).Return(42, nil)
+
+		archived, err := service.ArchiveAuditTrail(context.Background(), cutoff)
+		require.NoError(t, err)
+		assert.Equal(t, 42, archived)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a future cutoff", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+		service.SetClock(clock.NewFake(now))
+
+		_, err := service.ArchiveAuditTrail(context.Background(), now.Add(time.Hour))
+		require.Error(t, err)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "ArchiveAuditTrail", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/repository"
+)
+
+// DefaultAuditBatchLimit caps the tokens whose audit trails one batch fetch returns
+const DefaultAuditBatchLimit = 100
+
+// AuditTrailBatchRequest represents a request for the audit trails of several tokens
+type AuditTrailBatchRequest struct {
+	TokenIDs []uuid.UUID `json:"token_ids" binding:"required,min=1"`
+}
+
+// SetAuditBatchLimit sets how many tokens a batch audit trail fetch may request. A limit of
+// zero or less restores the default.
+func (s *TokenService) SetAuditBatchLimit(limit int) {
+	if limit <= 0 {
+		limit = DefaultAuditBatchLimit
+	}
+	s.auditBatchLimit = limit
+}
+
+// GetTokenAuditTrails retrieves the audit trails of several tokens with a single query, keyed
+// by token ID. Each trail is newest first and holds live entries only. Every requested token
+// has a key, with an empty trail if it has no entries; duplicate IDs are fetched once.
+func (s *TokenService) GetTokenAuditTrails(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID][]repository.TokenAuditEntry, error) {
+	if len(tokenIDs) == 0 {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token IDs list cannot be empty",
+		)
+	}
+
+	unique := make([]uuid.UUID, 0, len(tokenIDs))
+	seen := make(map[uuid.UUID]bool, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		if tokenID == uuid.Nil {
+			return nil, errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"token ID cannot be nil",
+			)
+		}
+		if !seen[tokenID] {
+			seen[tokenID] = true
+			unique = append(unique, tokenID)
+		}
+	}
+
+	if len(unique) > s.auditBatchLimit {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("cannot fetch audit trails for more than %d tokens at once", s.auditBatchLimit),
+		)
+	}
+
+	trails, err := s.repo.GetAuditTrails(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token audit trails: %w", err)
+	}
+
+	for _, tokenID := range unique {
+		if trails[tokenID] == nil {
+			trails[tokenID] = []repository.TokenAuditEntry{}
+		}
+	}
+
+	return trails, nil
+}
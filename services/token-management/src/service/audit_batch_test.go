@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_GetTokenAuditTrails(t *testing.T) {
+	first, second, quiet := uuid.New(), uuid.New(), uuid.New()
+	trails := map[uuid.UUID][]repository.TokenAuditEntry{
+		first: {
+			{ID: uuid.New(), TokenID: first, Operation: "STATUS_CHANGE"},
+			{ID: uuid.New(), TokenID: first, Operation: "CREATE"},
+		},
+		second: {
+			{ID: uuid.New(), TokenID: second, Operation: "OWNERSHIP_TRANSFER"},
+			{ID: uuid.New(), TokenID: second, Operation: "FREEZE"},
+			{ID: uuid.New(), TokenID: second, Operation: "CREATE"},
+		},
+	}
+
+	t.Run("trails are returned per token and duplicates fetched once", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetAuditTrails", mock.Anything, []uuid.UUID{first, second, quiet}).Return(trails, nil).Once()
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		result, err := service.GetTokenAuditTrails(context.Background(), []uuid.UUID{first, second, first, quiet})
+		require.NoError(t, err)
+
+		assert.Len(t, result, 3)
+		assert.Equal(t, trails[first], result[first])
+		assert.Equal(t, trails[second], result[second])
+		assert.NotNil(t, result[quiet])
+		assert.Empty(t, result[quiet])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("requests over the limit are rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+		service.SetAuditBatchLimit(2)
+
+		_, err := service.GetTokenAuditTrails(context.Background(), []uuid.UUID{first, second, quiet})
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrValidation, err.(*errors.EchoPayError).Code)
+		mockRepo.AssertNotCalled(t, "GetAuditTrails", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty and nil token IDs are rejected", func(t *testing.T) {
+		service := NewTokenServiceWithDeps(new(MockTokenRepository), nil)
+
+		_, err := service.GetTokenAuditTrails(context.Background(), nil)
+		assert.Error(t, err)
+
+		_, err = service.GetTokenAuditTrails(context.Background(), []uuid.UUID{first, uuid.Nil})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+// auditExportPageSize is the number of audit entries read per query while exporting, which
+// bounds the memory an export uses however long the period is
+const auditExportPageSize = 500
+
+// auditExportSigner is the issuer whose key signs audit exports
+const auditExportSigner = "token-management"
+
+// Record types in an audit export
+const (
+	AuditExportTypeTokenAudit = "token_audit"
+	AuditExportTypeChecksum   = "checksum"
+)
+
+// AuditExportRecord is one token state change in an audit export
+type AuditExportRecord struct {
+	Type         string                 `json:"type"`
+	ID           uuid.UUID              `json:"id"`
+	TokenID      uuid.UUID              `json:"token_id"`
+	Operation    string                 `json:"operation"`
+	OldStatus    models.TokenStatus     `json:"old_status,omitempty"`
+	NewStatus    models.TokenStatus     `json:"new_status,omitempty"`
+	OldOwner     uuid.UUID              `json:"old_owner"`
+	NewOwner     uuid.UUID              `json:"new_owner"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Sequence     int64                  `json:"sequence,omitempty"`
+	PreviousHash string                 `json:"previous_hash,omitempty"`
+	EntryHash    string                 `json:"entry_hash,omitempty"`
+}
+
+// AuditExportTrailer is the last line of an audit export. SHA256 covers every byte exported
+// before the trailer, and the signature covers the period, entry count and checksum, so a
+// recipient can detect an export that was truncated or altered.
+type AuditExportTrailer struct {
+	Type      string    `json:"type"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Entries   int       `json:"entries"`
+	SHA256    string    `json:"sha256"`
+	KeyID     string    `json:"key_id"`
+	Algorithm string    `json:"algorithm"`
+	Signature []byte    `json:"signature"`
+}
+
+// signingPayload serializes the signed fields of a trailer in a canonical order
+func (t *AuditExportTrailer) signingPayload() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%d|%s",
+		t.From.UTC().Format(time.RFC3339Nano),
+		t.To.UTC().Format(time.RFC3339Nano),
+		t.Entries,
+		t.SHA256,
+	))
+}
+
+// ExportAuditLog writes every token audit entry recorded at or after from and before to as
+// newline-delimited JSON, oldest first, followed by a signed checksum trailer. Entries are
+// read a page at a time so memory use does not grow with the period. If writing fails part
+// way the trailer is never written, which the recipient detects as a truncated export.
+func (s *TokenService) ExportAuditLog(ctx context.Context, from, to time.Time, w io.Writer) (*AuditExportTrailer, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"export period requires both from and to",
+		)
+	}
+
+	if !from.Before(to) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"export period start must be before its end",
+		)
+	}
+
+	if s.keySource == nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrServiceUnavailable,
+			"audit export signing is not configured",
+		)
+	}
+
+	keyID, privateKey, err := s.keySource.SigningKey(auditExportSigner)
+	if err == ErrNoSigningKey {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrServiceUnavailable,
+			"audit export signing is not configured",
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit export signing key: %w", err)
+	}
+
+	checksum := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(w, checksum))
+	entries := 0
+
+	afterTimestamp, afterID := from, uuid.Nil
+	for {
+		page, err := s.repo.GetAuditEntriesBetween(ctx, from, to, afterTimestamp, afterID, auditExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audit entries: %w", err)
+		}
+
+		for _, entry := range page {
+			if err := encoder.Encode(auditExportRecord(entry)); err != nil {
+				return nil, fmt.Errorf("failed to write audit export: %w", err)
+			}
+			entries++
+		}
+
+		if len(page) < auditExportPageSize {
+			break
+		}
+		last := page[len(page)-1]
+		afterTimestamp, afterID = last.Timestamp.Time, last.ID
+	}
+
+	trailer := &AuditExportTrailer{
+		Type:      AuditExportTypeChecksum,
+		From:      from,
+		To:        to,
+		Entries:   entries,
+		SHA256:    hex.EncodeToString(checksum.Sum(nil)),
+		KeyID:     keyID,
+		Algorithm: SignatureAlgorithm,
+	}
+	trailer.Signature = ed25519.Sign(privateKey, trailer.signingPayload())
+
+	if err := json.NewEncoder(w).Encode(trailer); err != nil {
+		return nil, fmt.Errorf("failed to write audit export checksum: %w", err)
+	}
+
+	return trailer, nil
+}
+
+// auditExportRecord converts an audit entry to its exported form
+func auditExportRecord(entry repository.TokenAuditEntry) AuditExportRecord {
+	return AuditExportRecord{
+		Type:         AuditExportTypeTokenAudit,
+		ID:           entry.ID,
+		TokenID:      entry.TokenID,
+		Operation:    entry.Operation,
+		OldStatus:    entry.OldStatus,
+		NewStatus:    entry.NewStatus,
+		OldOwner:     entry.OldOwner,
+		NewOwner:     entry.NewOwner,
+		Timestamp:    entry.Timestamp.Time,
+		Metadata:     entry.Metadata,
+		Sequence:     entry.Sequence,
+		PreviousHash: entry.PreviousHash,
+		EntryHash:    entry.EntryHash,
+	}
+}
+
+// VerifyAuditExport reads an audit export and checks it against its trailer: the entry count,
+// the checksum over the bytes before the trailer and the trailer's signature. It returns the
+// trailer of an intact export and an error for one that was truncated or altered.
+func VerifyAuditExport(r io.Reader, keys SigningKeySource) (*AuditExportTrailer, error) {
+	reader := bufio.NewReader(r)
+	checksum := sha256.New()
+	entries := 0
+
+	// Each line is hashed once the next one shows it is not the trailer
+	var pending []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if pending != nil {
+				checksum.Write(pending)
+				entries++
+			}
+			pending = line
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit export: %w", err)
+		}
+	}
+
+	var trailer AuditExportTrailer
+	if pending == nil || json.Unmarshal(bytes.TrimSpace(pending), &trailer) != nil || trailer.Type != AuditExportTypeChecksum {
+		return nil, fmt.Errorf("audit export has no checksum trailer; it may be truncated")
+	}
+
+	if trailer.Entries != entries {
+		return nil, fmt.Errorf("audit export has %d entries, its trailer records %d", entries, trailer.Entries)
+	}
+
+	if hex.EncodeToString(checksum.Sum(nil)) != trailer.SHA256 {
+		return nil, fmt.Errorf("audit export checksum does not match its trailer")
+	}
+
+	if trailer.Algorithm != SignatureAlgorithm {
+		return nil, fmt.Errorf("unsupported audit export signature algorithm: %s", trailer.Algorithm)
+	}
+
+	publicKey, err := keys.PublicKey(trailer.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ed25519.Verify(publicKey, trailer.signingPayload(), trailer.Signature) {
+		return nil, fmt.Errorf("audit export signature is invalid")
+	}
+
+	return &trailer, nil
+}
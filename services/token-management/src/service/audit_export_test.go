@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func newAuditEntries(start time.Time, count int) []repository.TokenAuditEntry {
+	entries := make([]repository.TokenAuditEntry, count)
+	for i := range entries {
+		entries[i] = repository.TokenAuditEntry{
+			ID:        uuid.New(),
+			TokenID:   uuid.New(),
+			Operation: "STATUS_CHANGE",
+			OldStatus: models.TokenStatusActive,
+			NewStatus: models.TokenStatusFrozen,
+			Timestamp: sql.NullTime{Time: start.Add(time.Duration(i) * time.Second), Valid: true},
+			Metadata:  map[string]interface{}{"reason_code": "fraud_investigation"},
+		}
+	}
+	return entries
+}
+
+func TestTokenService_ExportAuditLog(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	keys := NewStaticKeySource("audit-key-1", ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize)))
+
+	// A full first page makes the export read on from the last entry exported
+	entries := newAuditEntries(from, auditExportPageSize+2)
+	firstPage, secondPage := entries[:auditExportPageSize], entries[auditExportPageSize:]
+	last := firstPage[len(firstPage)-1]
+
+	mockRepo := new(MockTokenRepository)
+	service := NewTokenServiceWithDeps(mockRepo, nil)
+	service.SetSigningKeySource(keys)
+
+	mockRepo.On("GetAuditEntriesBetween", mock.Anything, from, to, from, uuid.Nil, auditExportPageSize).Return(firstPage, nil).Once()
+	mockRepo.On("GetAuditEntriesBetween", mock.Anything, from, to, last.Timestamp.Time, last.ID, auditExportPageSize).Return(secondPage, nil).Once()
+
+	var out bytes.Buffer
+	trailer, err := service.ExportAuditLog(context.Background(), from, to, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, len(entries), trailer.Entries)
+	assert.Equal(t, "audit-key-1", trailer.KeyID)
+	mockRepo.AssertExpectations(t)
+
+	// Entries are exported in timestamp order, followed by the trailer
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, len(entries)+1)
+	for i, entry := range entries {
+		var record AuditExportRecord
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &record))
+		assert.Equal(t, AuditExportTypeTokenAudit, record.Type)
+		assert.Equal(t, entry.ID, record.ID)
+		assert.True(t, record.Timestamp.Equal(entry.Timestamp.Time))
+	}
+
+	verified, err := VerifyAuditExport(bytes.NewReader(out.Bytes()), keys)
+	require.NoError(t, err)
+	assert.Equal(t, trailer.SHA256, verified.SHA256)
+	assert.Equal(t, len(entries), verified.Entries)
+
+	t.Run("truncated export fails verification", func(t *testing.T) {
+		withoutTrailer := strings.Join(lines[:len(lines)-1], "\n") + "\n"
+		_, err := VerifyAuditExport(strings.NewReader(withoutTrailer), keys)
+		assert.Error(t, err)
+
+		withoutEntry := strings.Join(append(append([]string{}, lines[:3]...), lines[4:]...), "\n") + "\n"
+		_, err = VerifyAuditExport(strings.NewReader(withoutEntry), keys)
+		assert.Error(t, err)
+
+		cutShort := out.String()[:out.Len()/2]
+		_, err = VerifyAuditExport(strings.NewReader(cutShort), keys)
+		assert.Error(t, err)
+	})
+
+	t.Run("altered export fails verification", func(t *testing.T) {
+		altered := strings.Replace(out.String(), "fraud_investigation", "customer_request", 1)
+		_, err := VerifyAuditExport(strings.NewReader(altered), keys)
+		assert.Error(t, err)
+	})
+
+	t.Run("export signed with another key fails verification", func(t *testing.T) {
+		otherKeys := NewStaticKeySource("audit-key-1", ed25519.NewKeyFromSeed(bytes.Repeat([]byte{9}, ed25519.SeedSize)))
+		_, err := VerifyAuditExport(bytes.NewReader(out.Bytes()), otherKeys)
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenService_ExportAuditLog_EmptyPeriod(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	keys := NewStaticKeySource("audit-key-1", ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize)))
+
+	mockRepo := new(MockTokenRepository)
+	service := NewTokenServiceWithDeps(mockRepo, nil)
+	service.SetSigningKeySource(keys)
+
+	mockRepo.On("GetAuditEntriesBetween", mock.Anything, from, to, from, uuid.Nil, auditExportPageSize).Return(nil, nil).Once()
+
+	var out bytes.Buffer
+	_, err := service.ExportAuditLog(context.Background(), from, to, &out)
+	require.NoError(t, err)
+
+	verified, err := VerifyAuditExport(&out, keys)
+	require.NoError(t, err)
+	assert.Equal(t, 0, verified.Entries)
+}
+
+func TestTokenService_ExportAuditLog_Validation(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := NewStaticKeySource("audit-key-1", ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize)))
+
+	testCases := []struct {
+		name     string
+		from, to time.Time
+		keys     SigningKeySource
+		code     string
+	}{
+		{"missing from", time.Time{}, from, keys, errors.ErrValidation},
+		{"end before start", from, from.Add(-time.Hour), keys, errors.ErrValidation},
+		{"signing not configured", from, from.Add(time.Hour), nil, errors.ErrServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+			if tc.keys != nil {
+				service.SetSigningKeySource(tc.keys)
+			}
+
+			var out bytes.Buffer
+			_, err := service.ExportAuditLog(context.Background(), tc.from, tc.to, &out)
+
+			tokenErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, tc.code, tokenErr.Code)
+			assert.Zero(t, out.Len())
+			mockRepo.AssertNotCalled(t, "GetAuditEntriesBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
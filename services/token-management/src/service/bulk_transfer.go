@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/models"
+)
+
+// MaxBulkTransferTokens is the most tokens that can change owner in one bulk transfer
+const MaxBulkTransferTokens = 1000
+
+// BulkTransferOwnershipRequest represents a request to move many tokens to one new owner,
+// as when a custodian changes
+type BulkTransferOwnershipRequest struct {
+	TokenIDs      []uuid.UUID `json:"token_ids" binding:"required,min=1,max=1000"`
+	NewOwner      uuid.UUID   `json:"new_owner" binding:"required"`
+	TransactionID uuid.UUID   `json:"transaction_id" binding:"required"`
+}
+
+// BulkTransferOwnershipResponse represents the response from a bulk ownership transfer
+type BulkTransferOwnershipResponse struct {
+	TransferredCount int       `json:"transferred_count"`
+	NewOwner         uuid.UUID `json:"new_owner"`
+	TransactionID    uuid.UUID `json:"transaction_id"`
+	TransferredAt    time.Time `json:"transferred_at"`
+}
+
+// IneligibleToken is a token that blocked a bulk transfer, with the reason it cannot move
+type IneligibleToken struct {
+	TokenID uuid.UUID `json:"token_id"`
+	Reason  string    `json:"reason"`
+}
+
+// BulkTransferOwnership moves every token to newOwner in a single transaction, recording
+// transactionID in each token's history and an ownership transfer in its audit trail. If
+// any token is ineligible nothing is transferred, and the error's details list the
+// ineligible tokens under "ineligible_tokens".
+func (s *TokenService) BulkTransferOwnership(ctx context.Context, tokenIDs []uuid.UUID, newOwner, transactionID uuid.UUID) (*BulkTransferOwnershipResponse, error) {
+	if err := validateBulkTransferOwnership(tokenIDs, newOwner, transactionID); err != nil {
+		return nil, err
+	}
+
+	if clear, listRef := s.screener.Screen(newOwner); !clear {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrSanctionsBlocked,
+			fmt.Sprintf("transfer blocked by sanctions screening of %s", newOwner),
+		).WithDetails(map[string]interface{}{
+			"blocked_party": newOwner,
+			"list_ref":      listRef,
+		})
+	}
+
+	tokenIDs = uniqueTokenIDs(tokenIDs)
+	transferredAt := s.clock.Now()
+	var previousOwners map[uuid.UUID]uuid.UUID
+
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
+		tokens := make([]*models.Token, 0, len(tokenIDs))
+		previousOwners = make(map[uuid.UUID]uuid.UUID, len(tokenIDs))
+		var ineligible []IneligibleToken
+		requiredSigners := make(map[uuid.UUID]int)
+
+		for _, tokenID := range tokenIDs {
+			token, err := s.repo.GetByIDWithTx(ctx, tx, tokenID)
+			if err != nil {
+				return fmt.Errorf("failed to get token: %w", err)
+			}
+
+			reason, err := s.bulkTransferIneligibility(ctx, tx, token, newOwner, transferredAt, requiredSigners)
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				ineligible = append(ineligible, IneligibleToken{TokenID: tokenID, Reason: reason})
+				continue
+			}
+			tokens = append(tokens, token)
+		}
+
+		if len(ineligible) > 0 {
+			return errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				fmt.Sprintf("%d of %d tokens cannot be transferred", len(ineligible), len(tokenIDs)),
+			).WithDetails(map[string]interface{}{
+				"ineligible_tokens": ineligible,
+			})
+		}
+
+		for _, token := range tokens {
+			previousOwners[token.TokenID] = token.CurrentOwner
+			if err := token.TransferOwnership(newOwner, transactionID); err != nil {
+				return err
+			}
+			if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTokenTransferFailed,
+			fmt.Sprintf("failed to bulk transfer tokens: %v", err),
+		)
+	}
+
+	logBulkTokenTransition(ctx, "BULK_OWNERSHIP_TRANSFER", models.TokenStatusActive, len(tokenIDs),
+		"new_owner", newOwner.String(),
+		"transaction_id", transactionID.String(),
+	)
+	for _, tokenID := range tokenIDs {
+		previousOwner := previousOwners[tokenID]
+		s.publishTokenEvent(events.TokenEventTransferred, tokenID, newOwner, &previousOwner, models.TokenStatusActive, "Token transferred in a custody migration")
+	}
+
+	return &BulkTransferOwnershipResponse{
+		TransferredCount: len(tokenIDs),
+		NewOwner:         newOwner,
+		TransactionID:    transactionID,
+		TransferredAt:    transferredAt,
+	}, nil
+}
+
+// bulkTransferIneligibility returns why a token cannot be moved to newOwner in a bulk
+// transfer, or an empty string if it can. requiredSigners caches each owner's signing policy.
+func (s *TokenService) bulkTransferIneligibility(ctx context.Context, tx *sql.Tx, token *models.Token, newOwner uuid.UUID, transferredAt time.Time, requiredSigners map[uuid.UUID]int) (string, error) {
+	if token == nil {
+		return "token not found", nil
+	}
+
+	if token.Status != models.TokenStatusActive {
+		return fmt.Sprintf("token is %s", token.Status), nil
+	}
+
+	if token.CurrentOwner == newOwner {
+		return "token is already owned by the new owner", nil
+	}
+
+	if clear, _ := s.screener.Screen(token.CurrentOwner); !clear {
+		return "current owner is blocked by sanctions screening", nil
+	}
+
+	pending, err := s.repo.HasPendingTransferWithTx(ctx, tx, token.TokenID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check pending transfers: %w", err)
+	}
+	if pending {
+		return "token has a pending multi-signature transfer", nil
+	}
+
+	// A token over its transfer velocity limit is ineligible like any other, so it fails the
+	// whole batch and is listed with the limit it exceeded
+	if err := s.checkTransferVelocity(ctx, tx, token.TokenID, transferredAt); err != nil {
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return echoPayErr.Message, nil
+		}
+		return "", err
+	}
+
+	// Multi-sig wallets only release tokens with co-signer approval
+	signers, ok := requiredSigners[token.CurrentOwner]
+	if !ok {
+		signers, err = s.repo.GetRequiredSignersWithTx(ctx, tx, token.CurrentOwner)
+		if err != nil {
+			return "", fmt.Errorf("failed to get wallet signing policy: %w", err)
+		}
+		requiredSigners[token.CurrentOwner] = signers
+	}
+	if signers > 0 {
+		return "current owner requires co-signer approval", nil
+	}
+
+	return "", nil
+}
+
+func validateBulkTransferOwnership(tokenIDs []uuid.UUID, newOwner, transactionID uuid.UUID) error {
+	if len(tokenIDs) == 0 {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token IDs list cannot be empty",
+		)
+	}
+
+	if len(tokenIDs) > MaxBulkTransferTokens {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("cannot transfer more than %d tokens at once", MaxBulkTransferTokens),
+		)
+	}
+
+	for _, tokenID := range tokenIDs {
+		if tokenID == uuid.Nil {
+			return errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"token ID cannot be nil",
+			)
+		}
+	}
+
+	if newOwner == uuid.Nil {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"new owner cannot be nil",
+		)
+	}
+
+	if transactionID == uuid.Nil {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"transaction ID cannot be nil",
+		)
+	}
+
+	return nil
+}
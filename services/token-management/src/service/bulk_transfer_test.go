@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestTokenService_BulkTransferOwnership(t *testing.T) {
+	oldCustodian := uuid.New()
+	newCustodian := uuid.New()
+	transactionID := uuid.New()
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	newToken := func(status models.TokenStatus) *models.Token {
+		return &models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: oldCustodian,
+			Status:       status,
+		}
+	}
+
+	t.Run("all eligible tokens move owner", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		tokens := []*models.Token{newToken(models.TokenStatusActive), newToken(models.TokenStatusActive)}
+		tokenIDs := []uuid.UUID{tokens[0].TokenID, tokens[1].TokenID, tokens[0].TokenID}
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		for _, token := range tokens {
+			mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(token, nil)
+			mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(false, nil)
+		}
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, oldCustodian).Return(0, nil).Once()
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+			return token.CurrentOwner == newCustodian &&
+				len(token.TransactionHistory) > 0 &&
+				token.TransactionHistory[len(token.TransactionHistory)-1] == transactionID
+		})).Return(nil).Times(2)
+
+		response, err := service.BulkTransferOwnership(context.Background(), tokenIDs, newCustodian, transactionID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.TransferredCount)
+		assert.Equal(t, newCustodian, response.NewOwner)
+		assert.Equal(t, transactionID, response.TransactionID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("mixed eligibility fails the whole batch", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		active := newToken(models.TokenStatusActive)
+		frozen := newToken(models.TokenStatusFrozen)
+		pending := newToken(models.TokenStatusActive)
+		missing := uuid.New()
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, active.TokenID).Return(active, nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, frozen.TokenID).Return(frozen, nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, pending.TokenID).Return(pending, nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, missing).Return(nil, nil)
+		mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, active.TokenID).Return(false, nil)
+		mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, pending.TokenID).Return(true, nil)
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, oldCustodian).Return(0, nil)
+
+		tokenIDs := []uuid.UUID{active.TokenID, frozen.TokenID, pending.TokenID, missing}
+		_, err := service.BulkTransferOwnership(context.Background(), tokenIDs, newCustodian, transactionID)
+		require.Error(t, err)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+		assert.Equal(t, []IneligibleToken{
+			{TokenID: frozen.TokenID, Reason: "token is frozen"},
+			{TokenID: pending.TokenID, Reason: "token has a pending multi-signature transfer"},
+			{TokenID: missing, Reason: "token not found"},
+		}, tokenErr.Details["ineligible_tokens"])
+
+		// No token changes owner, including the eligible one
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, oldCustodian, active.CurrentOwner)
+	})
+
+	t.Run("token over its velocity limit fails the whole batch", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetClock(clock.NewFake(now))
+		service.SetTransferVelocityLimit(3, 24*time.Hour)
+
+		settled := newToken(models.TokenStatusActive)
+		churned := newToken(models.TokenStatusActive)
+		since := now.Add(-24 * time.Hour)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		for _, token := range []*models.Token{settled, churned} {
+			mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(token, nil)
+			mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(false, nil)
+		}
+		mockRepo.On("CountOwnershipTransfersSinceWithTx", mock.Anything, mock.Anything, settled.TokenID, since).Return(1, nil)
+		mockRepo.On("CountOwnershipTransfersSinceWithTx", mock.Anything, mock.Anything, churned.TokenID, since).Return(3, nil)
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, oldCustodian).Return(0, nil)
+
+		tokenIDs := []uuid.UUID{settled.TokenID, churned.TokenID}
+		_, err := service.BulkTransferOwnership(context.Background(), tokenIDs, newCustodian, transactionID)
+		require.Error(t, err)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+		assert.Equal(t, []IneligibleToken{
+			{TokenID: churned.TokenID, Reason: "token has changed hands 3 times in the last 24h0m0s, the maximum allowed"},
+		}, tokenErr.Details["ineligible_tokens"])
+
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, oldCustodian, settled.CurrentOwner)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		tooMany := make([]uuid.UUID, MaxBulkTransferTokens+1)
+		for i := range tooMany {
+			tooMany[i] = uuid.New()
+		}
+
+		for _, tokenIDs := range [][]uuid.UUID{nil, tooMany, {uuid.Nil}} {
+			_, err := service.BulkTransferOwnership(context.Background(), tokenIDs, newCustodian, transactionID)
+			tokenErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		}
+
+		_, err := service.BulkTransferOwnership(context.Background(), []uuid.UUID{uuid.New()}, uuid.Nil, transactionID)
+		assert.Error(t, err)
+		_, err = service.BulkTransferOwnership(context.Background(), []uuid.UUID{uuid.New()}, newCustodian, uuid.Nil)
+		assert.Error(t, err)
+
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+}
@@ -23,7 +23,7 @@ func TestConcurrentBulkOperations(t *testing.T) {
 		concurrency := 3
 
 		// Each bulk operation will call BulkUpdateStatus
-		mockRepo.On("BulkUpdateStatus", mock.Anything, mock.AnythingOfType("[]uuid.UUID"), models.TokenStatusFrozen).Return(nil).Times(concurrency)
+		mockRepo.On("BulkUpdateStatus", mock.Anything, mock.AnythingOfType("[]uuid.UUID"), models.TokenStatusFrozen, mock.Anything).Return(nil).Times(concurrency)
 
 		// Run concurrent bulk operations
 		var wg sync.WaitGroup
@@ -35,7 +35,7 @@ func TestConcurrentBulkOperations(t *testing.T) {
 				defer wg.Done()
 				// Use different token sets for each operation
 				uniqueTokenIDs := []uuid.UUID{uuid.New(), uuid.New()}
-				_, err := service.BulkFreezeTokens(context.Background(), uniqueTokenIDs, "Concurrent bulk test")
+				_, err := service.BulkFreezeTokens(context.Background(), uniqueTokenIDs, FreezeReasonFraudInvestigation)
 				errors <- err
 			}(i)
 		}
@@ -84,7 +84,7 @@ func TestTokenStateTransitionValidation(t *testing.T) {
 
 		_, err := service.UnfreezeToken(context.Background(), UnfreezeTokenRequest{
 			TokenID: tokenID,
-			Reason:  "Invalid state transition test",
+			Reason:  FreezeReasonInvestigationClosed,
 		})
 
 		assert.Error(t, err, "Should not be able to unfreeze an active token")
@@ -104,9 +104,9 @@ func TestBulkOperationAtomicity(t *testing.T) {
 		service := NewTokenServiceWithDeps(mockRepo, nil)
 
 		// Mock successful bulk update
-		mockRepo.On("BulkUpdateStatus", mock.Anything, tokenIDs, models.TokenStatusFrozen).Return(nil)
+		mockRepo.On("BulkUpdateStatus", mock.Anything, tokenIDs, models.TokenStatusFrozen, mock.Anything).Return(nil)
 
-		response, err := service.BulkFreezeTokens(context.Background(), tokenIDs, "Atomicity test")
+		response, err := service.BulkFreezeTokens(context.Background(), tokenIDs, FreezeReasonFraudInvestigation)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -122,9 +122,9 @@ func TestBulkOperationAtomicity(t *testing.T) {
 		service := NewTokenServiceWithDeps(mockRepo, nil)
 
 		// Mock successful bulk update
-		mockRepo.On("BulkUpdateStatus", mock.Anything, tokenIDs, models.TokenStatusActive).Return(nil)
+		mockRepo.On("BulkUpdateStatus", mock.Anything, tokenIDs, models.TokenStatusActive, mock.Anything).Return(nil)
 
-		response, err := service.BulkUnfreezeTokens(context.Background(), tokenIDs, "Atomicity test")
+		response, err := service.BulkUnfreezeTokens(context.Background(), tokenIDs, FreezeReasonInvestigationClosed)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -164,10 +164,11 @@ func TestTimestampConsistency(t *testing.T) {
 		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(t *models.Token) bool {
 			return t.UpdatedAt.After(originalTime) && t.Status == models.TokenStatusFrozen
 		})).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil)
 
 		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
 			TokenID: tokenID,
-			Reason:  "Timestamp test",
+			Reason:  FreezeReasonFraudInvestigation,
 		})
 
 		assert.NoError(t, err)
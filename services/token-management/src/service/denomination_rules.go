@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/token-management/src/models"
+)
+
+// Issuance denomination bounds used when no rule or configuration overrides them
+const (
+	MinDenomination        = 0.01
+	DefaultMaxDenomination = 1000000.0
+)
+
+// DenominationRule limits the denominations a CBDC type can be issued in. A zero Min or Max
+// falls back to the default bound. When Allowed is set, only those fixed denominations can be
+// issued, as for cash-like tokens.
+type DenominationRule struct {
+	Min     float64   `json:"min,omitempty"`
+	Max     float64   `json:"max,omitempty"`
+	Allowed []float64 `json:"allowed,omitempty"`
+}
+
+// DenominationRules holds the per-CBDC-type issuance rules and the default maximum
+type DenominationRules struct {
+	maxDenomination float64
+	rules           map[models.CBDCType]DenominationRule
+}
+
+// NewDenominationRules creates rules with the given default maximum and per-type overrides
+func NewDenominationRules(maxDenomination float64, rules map[models.CBDCType]DenominationRule) *DenominationRules {
+	copied := make(map[models.CBDCType]DenominationRule, len(rules))
+	for cbdcType, rule := range rules {
+		copied[cbdcType] = rule
+	}
+	return &DenominationRules{maxDenomination: maxDenomination, rules: copied}
+}
+
+// DefaultDenominationRules allows any denomination between MinDenomination and
+// DefaultMaxDenomination
+func DefaultDenominationRules() *DenominationRules {
+	return NewDenominationRules(DefaultMaxDenomination, nil)
+}
+
+// NewDenominationRulesFromConfig builds rules from issuance configuration
+func NewDenominationRulesFromConfig(cfg config.IssuanceConfig) (*DenominationRules, error) {
+	maxDenomination := cfg.MaxDenomination
+	if maxDenomination <= 0 {
+		maxDenomination = DefaultMaxDenomination
+	}
+
+	var rules map[models.CBDCType]DenominationRule
+	if strings.TrimSpace(cfg.DenominationRules) != "" {
+		if err := json.Unmarshal([]byte(cfg.DenominationRules), &rules); err != nil {
+			return nil, fmt.Errorf("invalid denomination rules: %w", err)
+		}
+	}
+
+	for cbdcType, rule := range rules {
+		if rule.Min < 0 || rule.Max < 0 || (rule.Max > 0 && rule.Min > rule.Max) {
+			return nil, fmt.Errorf("invalid denomination bounds for %s", cbdcType)
+		}
+		for _, denomination := range rule.Allowed {
+			if denomination < MinDenomination {
+				return nil, fmt.Errorf("invalid allowed denomination %v for %s", denomination, cbdcType)
+			}
+		}
+	}
+
+	return NewDenominationRules(maxDenomination, rules), nil
+}
+
+// Validate checks a denomination against the rule for its CBDC type, naming the violated rule
+func (r *DenominationRules) Validate(cbdcType models.CBDCType, denomination float64) error {
+	rule := r.rules[cbdcType]
+
+	min := rule.Min
+	if min < MinDenomination {
+		min = MinDenomination
+	}
+	max := rule.Max
+	if max <= 0 {
+		max = r.maxDenomination
+	}
+
+	// Compare in the CBDC's minor units, so rules apply at the precision denominations are stored
+	currency := string(cbdcType)
+	if money.ToMinor(denomination, currency) < money.ToMinor(min, currency) {
+		return denominationRuleError("min", cbdcType, denomination,
+			fmt.Sprintf("denomination %v is below the %s minimum of %v", denomination, cbdcType, min))
+	}
+
+	if money.ToMinor(denomination, currency) > money.ToMinor(max, currency) {
+		return denominationRuleError("max", cbdcType, denomination,
+			fmt.Sprintf("denomination %v exceeds the %s maximum of %v", denomination, cbdcType, max))
+	}
+
+	if len(rule.Allowed) > 0 {
+		for _, allowed := range rule.Allowed {
+			if money.ToMinor(denomination, currency) == money.ToMinor(allowed, currency) {
+				return nil
+			}
+		}
+
+		allowed := append([]float64(nil), rule.Allowed...)
+		sort.Float64s(allowed)
+		err := denominationRuleError("allowed", cbdcType, denomination,
+			fmt.Sprintf("denomination %v is not an allowed %s denomination %v", denomination, cbdcType, allowed))
+		err.Details["allowed"] = allowed
+		return err
+	}
+
+	return nil
+}
+
+// Allowed returns the fixed denominations a CBDC type can be issued in, smallest first, or nil
+// if it can be issued in any denomination within its bounds
+func (r *DenominationRules) Allowed(cbdcType models.CBDCType) []float64 {
+	rule := r.rules[cbdcType]
+	if len(rule.Allowed) == 0 {
+		return nil
+	}
+	allowed := append([]float64(nil), rule.Allowed...)
+	sort.Float64s(allowed)
+	return allowed
+}
+
+// denominationRuleError reports a denomination rejected by the named rule
+func denominationRuleError(rule string, cbdcType models.CBDCType, denomination float64, message string) *errors.EchoPayError {
+	return errors.NewTokenManagementError(errors.ErrValidation, message).WithDetails(map[string]interface{}{
+		"rule":         rule,
+		"cbdc_type":    cbdcType,
+		"denomination": denomination,
+	})
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestNewDenominationRulesFromConfig(t *testing.T) {
+	t.Run("defaults without configuration", func(t *testing.T) {
+		rules, err := NewDenominationRulesFromConfig(config.IssuanceConfig{})
+		require.NoError(t, err)
+		assert.NoError(t, rules.Validate(models.CBDCTypeUSD, DefaultMaxDenomination))
+		assert.Error(t, rules.Validate(models.CBDCTypeUSD, DefaultMaxDenomination+1))
+	})
+
+	t.Run("per-type rules", func(t *testing.T) {
+		rules, err := NewDenominationRulesFromConfig(config.IssuanceConfig{
+			MaxDenomination:   5000,
+			DenominationRules: `{"USD-CBDC":{"allowed":[1,5,10,20,50,100]},"EUR-CBDC":{"min":0.5,"max":200}}`,
+		})
+		require.NoError(t, err)
+
+		assert.NoError(t, rules.Validate(models.CBDCTypeUSD, 20))
+		assert.Error(t, rules.Validate(models.CBDCTypeUSD, 25))
+		assert.NoError(t, rules.Validate(models.CBDCTypeEUR, 12.34))
+		assert.Error(t, rules.Validate(models.CBDCTypeEUR, 0.25))
+		assert.Error(t, rules.Validate(models.CBDCTypeEUR, 250))
+		assert.NoError(t, rules.Validate(models.CBDCTypeGBP, 5000))
+		assert.Error(t, rules.Validate(models.CBDCTypeGBP, 5000.01))
+	})
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		for _, raw := range []string{
+			`not json`,
+			`{"USD-CBDC":{"min":100,"max":10}}`,
+			`{"USD-CBDC":{"allowed":[0]}}`,
+		} {
+			_, err := NewDenominationRulesFromConfig(config.IssuanceConfig{DenominationRules: raw})
+			assert.Error(t, err, "expected %s to be rejected", raw)
+		}
+	})
+}
+
+func TestTokenService_IssueTokens_DenominationRules(t *testing.T) {
+	rules := NewDenominationRules(DefaultMaxDenomination, map[models.CBDCType]DenominationRule{
+		models.CBDCTypeUSD: {Allowed: []float64{1, 5, 10, 20, 50, 100}},
+	})
+
+	issue := func(cbdcType models.CBDCType, denomination float64) (*MockDatabase, error) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetDenominationRules(rules)
+
+		_, err := service.IssueTokens(context.Background(), IssueTokenRequest{
+			CBDCType:     cbdcType,
+			Denomination: denomination,
+			Owner:        uuid.New(),
+			Issuer:       "Federal Reserve",
+			Series:       "2025-A",
+			Quantity:     1,
+		})
+		return mockDB, err
+	}
+
+	t.Run("over-max denomination", func(t *testing.T) {
+		mockDB, err := issue(models.CBDCTypeEUR, 1e12)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		assert.Equal(t, "max", tokenErr.Details["rule"])
+		assert.Contains(t, tokenErr.Message, "maximum")
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+
+	t.Run("disallowed fixed denomination", func(t *testing.T) {
+		mockDB, err := issue(models.CBDCTypeUSD, 25)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		assert.Equal(t, "allowed", tokenErr.Details["rule"])
+		assert.Equal(t, []float64{1, 5, 10, 20, 50, 100}, tokenErr.Details["allowed"])
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+}
+
+func TestDenominationRules_ComparesInCurrencyMinorUnits(t *testing.T) {
+	jpy := models.CBDCType("JPY-CBDC")
+	rules := NewDenominationRules(DefaultMaxDenomination, map[models.CBDCType]DenominationRule{
+		jpy: {Allowed: []float64{1000, 5000, 10000}},
+	})
+
+	// Yen have no minor unit, so a denomination is compared in whole yen
+	assert.NoError(t, rules.Validate(jpy, 1000.4))
+	assert.Error(t, rules.Validate(jpy, 1000.6))
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/models"
+)
+
+// MaxDustConsolidationTokens caps the number of tokens merged by one consolidation; wallets
+// holding more dust are consolidated over several calls
+const MaxDustConsolidationTokens = 500
+
+// DustConsolidationResponse reports the small tokens merged by a consolidation and the
+// larger tokens that replaced them. Frozen tokens, tokens that could not be destroyed, such as
+// those held by a pending transfer or received too recently to be final, and groups whose
+// total cannot be reissued in denominations the CBDC type allows are left untouched and
+// counted as skipped.
+type DustConsolidationResponse struct {
+	OwnerID           uuid.UUID       `json:"owner_id"`
+	CBDCType          models.CBDCType `json:"cbdc_type"`
+	MaxDenomination   float64         `json:"max_denomination"`
+	ConsolidatedCount int             `json:"consolidated_count"`
+	ConsolidatedIDs   []uuid.UUID     `json:"consolidated_token_ids"`
+	Tokens            []models.Token  `json:"tokens"`
+	SkippedCount      int             `json:"skipped_count"`
+	ConsolidatedAt    time.Time       `json:"consolidated_at"`
+}
+
+// dustGroup holds dust tokens that can be merged together: tokens of one issuer and series
+type dustGroup struct {
+	issuer string
+	series string
+	tokens []models.Token
+}
+
+// ConsolidateDust merges an owner's active cbdcType tokens worth less than maxDenom into as
+// few tokens as possible, each worth at most maxDenom, so wallets left with many small
+// tokens after split payments can pay with fewer. Tokens are only merged with others of the
+// same issuer and series, the merged tokens are invalidated, and the replacements share an
+// issuance Merkle root. Replacements are only minted in denominations the CBDC type's rules
+// allow. At most MaxDustConsolidationTokens are merged per call.
+func (s *TokenService) ConsolidateDust(ctx context.Context, ownerID uuid.UUID, cbdcType models.CBDCType, maxDenom float64) (*DustConsolidationResponse, error) {
+	if ownerID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"owner ID cannot be nil",
+		)
+	}
+	if !s.SupportsCBDCType(cbdcType) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("invalid CBDC type: %s", cbdcType),
+		)
+	}
+	currency := string(cbdcType)
+	maxMinor := money.ToMinor(maxDenom, currency)
+	if maxMinor <= 0 {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"maximum denomination must be positive",
+		)
+	}
+
+	tokens, err := s.GetAllTokensByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DustConsolidationResponse{
+		OwnerID:         ownerID,
+		CBDCType:        cbdcType,
+		MaxDenomination: money.FromMinor(maxMinor, currency),
+		ConsolidatedIDs: []uuid.UUID{},
+		Tokens:          []models.Token{},
+	}
+
+	var dust []models.Token
+	for _, token := range tokens {
+		if token.CBDCType != cbdcType || money.ToMinor(token.Denomination, currency) >= maxMinor {
+			continue
+		}
+		switch {
+		case token.IsActive():
+			dust = append(dust, token)
+		case token.IsFrozen():
+			response.SkippedCount++
+		}
+	}
+
+	// Merge the smallest tokens first, so a bounded call removes as many tokens as possible
+	sort.SliceStable(dust, func(i, j int) bool {
+		return dust[i].Denomination < dust[j].Denomination
+	})
+	if len(dust) > MaxDustConsolidationTokens {
+		dust = dust[:MaxDustConsolidationTokens]
+	}
+
+	var allowedMinor []int64
+	for _, allowed := range s.denominations.Allowed(cbdcType) {
+		allowedMinor = append(allowedMinor, money.ToMinor(allowed, currency))
+	}
+
+	var destroyed []models.Token
+	skipped := 0
+	err = s.transactionWithRetry(func(tx *sql.Tx) error {
+		// Reset results, since a retried transaction starts over from fresh state
+		destroyed = nil
+		response.Tokens = []models.Token{}
+		skipped = 0
+
+		for _, group := range groupDust(dust) {
+			var merged []models.Token
+			for _, candidate := range group.tokens {
+				token, ok, err := s.reloadDustToken(ctx, tx, candidate, ownerID)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					skipped++
+					continue
+				}
+				merged = append(merged, *token)
+			}
+
+			// A single token gains nothing from consolidation
+			if len(merged) < 2 {
+				continue
+			}
+
+			var total int64
+			for _, token := range merged {
+				total += money.ToMinor(token.Denomination, currency)
+			}
+			denominations := consolidatedDenominations(total, maxMinor, allowedMinor)
+			if !s.issuableDenominations(cbdcType, denominations) {
+				skipped += len(merged)
+				continue
+			}
+			minted, err := s.mintConsolidatedTokens(ctx, tx, ownerID, cbdcType, group, denominations)
+			if err != nil {
+				return err
+			}
+
+			mintedIDs := make([]uuid.UUID, len(minted))
+			for i, token := range minted {
+				mintedIDs[i] = token.TokenID
+			}
+			mergedIDs := make([]uuid.UUID, len(merged))
+			for i, token := range merged {
+				mergedIDs[i] = token.TokenID
+			}
+
+			for i := range merged {
+				if err := merged[i].Invalidate(); err != nil {
+					return err
+				}
+				if err := s.updateTokenWithTx(ctx, tx, &merged[i]); err != nil {
+					return err
+				}
+				if err := s.repo.CreateAuditEntryWithTx(ctx, tx, merged[i].TokenID, "CONSOLIDATE", map[string]interface{}{
+					"consolidated_into": mintedIDs,
+				}); err != nil {
+					return err
+				}
+			}
+			for _, token := range minted {
+				if err := s.repo.CreateAuditEntryWithTx(ctx, tx, token.TokenID, "CONSOLIDATE", map[string]interface{}{
+					"consolidated_from": mergedIDs,
+				}); err != nil {
+					return err
+				}
+			}
+
+			destroyed = append(destroyed, merged...)
+			response.Tokens = append(response.Tokens, minted...)
+		}
+
+		// Replacement tokens across all groups share one issuance Merkle root
+		if len(response.Tokens) > 0 {
+			if err := s.storeIssuanceProofs(ctx, tx, response.Tokens); err != nil {
+				return fmt.Errorf("failed to store issuance proofs: %w", err)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to consolidate tokens: %v", err),
+		)
+	}
+
+	for _, token := range destroyed {
+		response.ConsolidatedIDs = append(response.ConsolidatedIDs, token.TokenID)
+	}
+	response.ConsolidatedCount = len(destroyed)
+	response.SkippedCount += skipped
+	response.ConsolidatedAt = s.clock.Now()
+
+	if len(destroyed) > 0 {
+		logBulkTokenTransition(ctx, "CONSOLIDATE", models.TokenStatusInvalid, len(destroyed), "owner", ownerID.String(), "minted", len(response.Tokens))
+	}
+	for _, token := range destroyed {
+		s.publishTokenEvent(events.TokenEventDestroyed, token.TokenID, ownerID, nil, models.TokenStatusInvalid, "Token consolidated into larger tokens")
+	}
+
+	return response, nil
+}
+
+// reloadDustToken re-reads a dust token within the consolidation transaction and reports
+// whether it can still be merged: it must be active, still held by the owner and pass the
+// checks a destruction would, since merging destroys it
+func (s *TokenService) reloadDustToken(ctx context.Context, tx *sql.Tx, candidate models.Token, ownerID uuid.UUID) (*models.Token, bool, error) {
+	token, err := s.repo.GetByIDWithTx(ctx, tx, candidate.TokenID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil || !token.IsActive() || token.CurrentOwner != ownerID {
+		return nil, false, nil
+	}
+
+	blockers, _, err := s.validateTokenDestruction(ctx, tx, token)
+	if err != nil {
+		return nil, false, err
+	}
+	return token, len(blockers) == 0, nil
+}
+
+// issuableDenominations reports whether a consolidation can mint every denomination, in minor
+// units, under the CBDC type's denomination rules
+func (s *TokenService) issuableDenominations(cbdcType models.CBDCType, denominations []int64) bool {
+	if len(denominations) == 0 {
+		return false
+	}
+	for _, minor := range denominations {
+		if err := s.denominations.Validate(cbdcType, money.FromMinor(minor, string(cbdcType))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// mintConsolidatedTokens mints one token for each denomination, in minor units, carrying the
+// group's issuer and series. Each denomination must be one the CBDC type can be issued in.
+func (s *TokenService) mintConsolidatedTokens(ctx context.Context, tx *sql.Tx, ownerID uuid.UUID, cbdcType models.CBDCType, group dustGroup, denominations []int64) ([]models.Token, error) {
+	var minted []models.Token
+	for _, minor := range denominations {
+		denomination := money.FromMinor(minor, string(cbdcType))
+		if err := s.denominations.Validate(cbdcType, denomination); err != nil {
+			return nil, err
+		}
+
+		tokens, err := s.mintTokensWithTx(ctx, tx, IssueTokenRequest{
+			CBDCType:     cbdcType,
+			Denomination: denomination,
+			Owner:        ownerID,
+			Issuer:       group.issuer,
+			Series:       group.series,
+			Quantity:     1,
+		}, len(minted))
+		if err != nil {
+			return nil, err
+		}
+		minted = append(minted, tokens...)
+	}
+	return minted, nil
+}
+
+// groupDust splits dust tokens by issuer and series, keeping each group's tokens in order
+// and ordering groups by their first token
+func groupDust(tokens []models.Token) []dustGroup {
+	var groups []dustGroup
+	index := make(map[[2]string]int)
+	for _, token := range tokens {
+		key := [2]string{token.Metadata.Issuer, token.Metadata.Series}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, dustGroup{issuer: key[0], series: key[1]})
+		}
+		groups[i].tokens = append(groups[i].tokens, token)
+	}
+	return groups
+}
+
+// consolidatedDenominations splits a total, in minor units, into tokens worth at most max.
+// Without allowed denominations it takes as many tokens of max as the total holds plus one
+// token for any remainder. Otherwise it takes the largest allowed denominations up to max
+// first, and returns nil if the total cannot be split into them exactly.
+func consolidatedDenominations(total, max int64, allowed []int64) []int64 {
+	if len(allowed) == 0 {
+		denominations := make([]int64, 0, total/max+1)
+		for ; total >= max; total -= max {
+			denominations = append(denominations, max)
+		}
+		if total > 0 {
+			denominations = append(denominations, total)
+		}
+		return denominations
+	}
+
+	sizes := make([]int64, 0, len(allowed))
+	for _, size := range allowed {
+		if size > 0 && size <= max {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+
+	var denominations []int64
+	for _, size := range sizes {
+		for ; total >= size; total -= size {
+			denominations = append(denominations, size)
+		}
+	}
+	if total > 0 {
+		return nil
+	}
+	return denominations
+}
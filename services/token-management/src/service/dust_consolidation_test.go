@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_ConsolidateDust(t *testing.T) {
+	owner := uuid.New()
+	newToken := func(denomination float64, status models.TokenStatus) models.Token {
+		return models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: denomination,
+			CurrentOwner: owner,
+			Status:       status,
+			Metadata:     models.TokenMetadata{Issuer: "Federal Reserve", Series: "2025A"},
+		}
+	}
+
+	// Eleven quarters and a dime left over from split payments
+	var dust []models.Token
+	for i := 0; i < 11; i++ {
+		dust = append(dust, newToken(0.25, models.TokenStatusActive))
+	}
+	dust = append(dust, newToken(0.10, models.TokenStatusActive))
+	frozen := newToken(0.25, models.TokenStatusFrozen)
+	held := newToken(0.50, models.TokenStatusActive)
+	large := newToken(20, models.TokenStatusActive)
+	tokens := append([]models.Token{large, frozen, held}, dust...)
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, 0).Return(tokens, nil)
+	for _, token := range append([]models.Token{held}, dust...) {
+		token := token
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&token, nil)
+		mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(token.TokenID == held.TokenID, nil)
+	}
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, mock.Anything, "CONSOLIDATE", mock.Anything).Return(nil)
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	response, err := service.ConsolidateDust(context.Background(), owner, models.CBDCTypeUSD, 1.00)
+	require.NoError(t, err)
+
+	// 2.85 in dust collapses into two whole units and the 0.85 remainder
+	assert.Equal(t, len(dust), response.ConsolidatedCount)
+	var dustIDs []uuid.UUID
+	for _, token := range dust {
+		dustIDs = append(dustIDs, token.TokenID)
+	}
+	assert.ElementsMatch(t, dustIDs, response.ConsolidatedIDs)
+	require.Len(t, response.Tokens, 3)
+	var denominations []float64
+	for _, token := range response.Tokens {
+		denominations = append(denominations, token.Denomination)
+		assert.Equal(t, owner, token.CurrentOwner)
+		assert.Equal(t, "Federal Reserve", token.Metadata.Issuer)
+		assert.Equal(t, "2025A", token.Metadata.Series)
+	}
+	assert.Equal(t, []float64{1.00, 1.00, 0.85}, denominations)
+
+	// The frozen and held tokens are skipped, and the large token is never considered
+	assert.Equal(t, 2, response.SkippedCount)
+	mockRepo.AssertNumberOfCalls(t, "UpdateWithTx", len(dust))
+	mockRepo.AssertNotCalled(t, "GetByIDWithTx", mock.Anything, mock.Anything, large.TokenID)
+	mockRepo.AssertNotCalled(t, "GetByIDWithTx", mock.Anything, mock.Anything, frozen.TokenID)
+	for _, call := range mockRepo.Calls {
+		if call.Method == "UpdateWithTx" {
+			updated := call.Arguments.Get(2).(*models.Token)
+			assert.Equal(t, models.TokenStatusInvalid, updated.Status)
+			assert.NotEqual(t, held.TokenID, updated.TokenID)
+		}
+	}
+}
+
+func TestTokenService_ConsolidateDust_AllowedDenominations(t *testing.T) {
+	owner := uuid.New()
+	newToken := func(denomination float64) models.Token {
+		return models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: denomination,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusActive,
+			Metadata:     models.TokenMetadata{Issuer: "Federal Reserve", Series: "2025A"},
+		}
+	}
+
+	newService := func(allowed []float64, tokens []models.Token) (*TokenService, *MockTokenRepository) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetDenominationRules(NewDenominationRules(DefaultMaxDenomination, map[models.CBDCType]DenominationRule{
+			models.CBDCTypeUSD: {Allowed: allowed},
+		}))
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, 0).Return(tokens, nil)
+		for _, token := range tokens {
+			token := token
+			mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&token, nil)
+			mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(false, nil)
+		}
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, mock.Anything, "CONSOLIDATE", mock.Anything).Return(nil)
+		mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		return service, mockRepo
+	}
+
+	t.Run("total is split into allowed denominations", func(t *testing.T) {
+		var tokens []models.Token
+		for i := 0; i < 7; i++ {
+			tokens = append(tokens, newToken(1))
+		}
+		service, _ := newService([]float64{1, 5, 10}, tokens)
+
+		response, err := service.ConsolidateDust(context.Background(), owner, models.CBDCTypeUSD, 10)
+		require.NoError(t, err)
+
+		assert.Equal(t, 7, response.ConsolidatedCount)
+		var denominations []float64
+		for _, token := range response.Tokens {
+			denominations = append(denominations, token.Denomination)
+		}
+		assert.Equal(t, []float64{5, 1, 1}, denominations)
+	})
+
+	t.Run("group that cannot be split is left untouched", func(t *testing.T) {
+		// Tokens issued before the rule allowed only fives and tens
+		tokens := []models.Token{newToken(2), newToken(2), newToken(2)}
+		service, mockRepo := newService([]float64{5, 10}, tokens)
+
+		response, err := service.ConsolidateDust(context.Background(), owner, models.CBDCTypeUSD, 10)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, response.ConsolidatedCount)
+		assert.Empty(t, response.Tokens)
+		assert.Equal(t, 3, response.SkippedCount)
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConsolidatedDenominations(t *testing.T) {
+	assert.Equal(t, []int64{100, 100, 85}, consolidatedDenominations(285, 100, nil))
+	assert.Equal(t, []int64{100, 100}, consolidatedDenominations(200, 100, nil))
+	assert.Equal(t, []int64{40}, consolidatedDenominations(40, 100, nil))
+
+	// Allowed denominations above the consolidation maximum are not used
+	assert.Equal(t, []int64{500, 100, 100}, consolidatedDenominations(700, 1000, []int64{100, 500, 1000}))
+	assert.Equal(t, []int64{500, 500, 100}, consolidatedDenominations(1100, 500, []int64{1000, 100, 500}))
+	assert.Nil(t, consolidatedDenominations(600, 1000, []int64{500, 1000}))
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestFreezeReason_Valid(t *testing.T) {
+	for _, reason := range []FreezeReason{
+		FreezeReasonFraudInvestigation,
+		FreezeReasonSanctionsHit,
+		FreezeReasonDispute,
+		FreezeReasonLegalHold,
+		FreezeReasonCustomerRequest,
+		FreezeReasonInvestigationClosed,
+		FreezeReasonDisputeResolved,
+		FreezeReasonHoldReleased,
+		FreezeReasonExpired,
+	} {
+		assert.True(t, reason.Valid(), "expected %q to be valid", reason)
+	}
+
+	for _, reason := range []FreezeReason{"", "Fraud investigation", "FRAUD_INVESTIGATION"} {
+		assert.False(t, reason.Valid(), "expected %q to be invalid", reason)
+	}
+}
+
+func TestFreezeReasonMetadata(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"reason_code": "legal_hold",
+		"note":        "court order 2025-114",
+	}, freezeReasonMetadata(FreezeReasonLegalHold, "court order 2025-114"))
+
+	assert.Equal(t, map[string]interface{}{
+		"reason": "Suspicious activity",
+	}, freezeReasonMetadata("Suspicious activity", ""))
+
+	assert.Empty(t, freezeReasonMetadata("", ""))
+}
+
+func TestTokenService_ReasonCodeValidation(t *testing.T) {
+	tokenID := uuid.New()
+
+	t.Run("unknown freeze reason is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  "Suspicious activity",
+		})
+
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+
+	t.Run("unknown unfreeze reason is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		response, err := service.UnfreezeToken(context.Background(), UnfreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  "All clear",
+		})
+
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+
+	t.Run("unknown dispute reason is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		response, err := service.BulkUpdateTokenStatus(context.Background(), BulkStatusUpdateRequest{
+			TokenIDs:  []uuid.UUID{tokenID},
+			NewStatus: models.TokenStatusDisputed,
+			Reason:    "Chargeback",
+		})
+
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "BulkUpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dispute reason code and note are recorded", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockRepo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID}, models.TokenStatusDisputed, map[string]interface{}{
+			"reason_code": "dispute",
+			"note":        "case 8812",
+		}).Return(nil)
+
+		response, err := service.BulkUpdateTokenStatus(context.Background(), BulkStatusUpdateRequest{
+			TokenIDs:  []uuid.UUID{tokenID},
+			NewStatus: models.TokenStatusDisputed,
+			Reason:    FreezeReasonDispute,
+			Note:      "case 8812",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, FreezeReasonDispute, response.Reason)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("free-text reason is accepted when legacy reasons are allowed", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetAllowFreeTextReasons(true)
+
+		token := &models.Token{
+			TokenID:      tokenID,
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: uuid.New(),
+			Status:       models.TokenStatusActive,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", map[string]interface{}{
+			"reason": "Suspicious activity",
+			"note":   "flagged by branch",
+		}).Return(nil)
+
+		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  "Suspicious activity",
+			Note:    "flagged by branch",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, FreezeReason("Suspicious activity"), response.Reason)
+		assert.Equal(t, "flagged by branch", response.Note)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("over-long free-text reason is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetAllowFreeTextReasons(true)
+
+		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  FreezeReason(strings.Repeat("x", MaxFreezeReasonLength+1)),
+		})
+
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+}
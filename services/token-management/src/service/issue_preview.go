@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/token-management/src/models"
+)
+
+// IssuePreview shows the tokens an issuance would mint, with their compliance defaults and
+// security features, without minting them. The token IDs are generated for the preview only;
+// issuing the request mints tokens with new IDs.
+type IssuePreview struct {
+	Tokens      []models.Token     `json:"tokens"`
+	Count       int                `json:"count"`
+	Amount      float64            `json:"amount"`                 // Total value the issuance would mint
+	QuotaImpact *IssuerQuotaImpact `json:"quota_impact,omitempty"` // Nil when no quota restricts the issuance
+	PreviewedAt time.Time          `json:"previewed_at"`
+}
+
+// IssuerQuotaImpact reports the quota an issuance counts against, the value it has left now
+// and the value it would have left after the issuance
+type IssuerQuotaImpact struct {
+	IssuerQuotaStatus
+	RemainingAfter float64 `json:"remaining_after"`
+}
+
+// PreviewIssue validates an issuance exactly as IssueTokens does and returns the tokens it
+// would mint along with its effect on the issuer's quota. Nothing is written: no tokens,
+// signatures, issuance proofs or quota updates are stored, and the quota is read without
+// being reserved, so a later issuance can still be refused if the quota is used up first.
+func (s *TokenService) PreviewIssue(ctx context.Context, req IssueTokenRequest) (*IssuePreview, error) {
+	req = normalizeIssueRequest(req)
+
+	if err := s.validateIssueRequest(req); err != nil {
+		return nil, err
+	}
+
+	amount := float64(req.Quantity) * req.Denomination
+	impact, err := s.previewQuotaImpact(ctx, req, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]models.Token, 0, req.Quantity)
+	for i := 0; i < req.Quantity; i++ {
+		token, err := newIssuedToken(req, i)
+		if err != nil {
+			return nil, errors.NewTokenManagementError(
+				errors.ErrTransactionFailed,
+				fmt.Sprintf("failed to issue tokens: %v", err),
+			)
+		}
+		tokens = append(tokens, *token)
+	}
+
+	return &IssuePreview{
+		Tokens:      tokens,
+		Count:       len(tokens),
+		Amount:      money.Round(amount, string(req.CBDCType)),
+		QuotaImpact: impact,
+		PreviewedAt: s.clock.Now(),
+	}, nil
+}
+
+// previewQuotaImpact checks an issuance against the issuer's quota for its series and CBDC
+// type, returning the same error IssueTokens would if the quota would be exceeded
+func (s *TokenService) previewQuotaImpact(ctx context.Context, req IssueTokenRequest, amount float64) (*IssuerQuotaImpact, error) {
+	quotas, err := s.repo.GetIssuerQuotas(ctx, req.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issuer quotas: %w", err)
+	}
+
+	for _, quota := range quotas {
+		if quota.Series != req.Series || quota.CBDCType != req.CBDCType {
+			continue
+		}
+
+		if err := checkIssuanceQuota(quota, req.Issuer, req.Series, req.CBDCType, amount); err != nil {
+			return nil, err
+		}
+
+		currency := string(req.CBDCType)
+		before := newIssuerQuotaStatus(quota)
+		after := quota
+		after.MintedTotal = money.FromMinor(money.ToMinor(quota.MintedTotal, currency)+money.ToMinor(amount, currency), currency)
+		return &IssuerQuotaImpact{
+			IssuerQuotaStatus: before,
+			RemainingAfter:    newIssuerQuotaStatus(after).Remaining,
+		}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_PreviewIssue(t *testing.T) {
+	request := IssueTokenRequest{
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		Owner:        uuid.New(),
+		Issuer:       "Federal Reserve",
+		Series:       "2025-A",
+		Quantity:     3,
+	}
+
+	assertNothingWritten := func(t *testing.T, mockRepo *MockTokenRepository, mockDB *MockDatabase) {
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+		mockRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "AddMintedTotalWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+
+	// Errors carry their own timestamp and stack trace, so only what callers see is compared
+	assertSameError := func(t *testing.T, expected, actual error) {
+		expectedErr, ok := expected.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		actualErr, ok := actual.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, expectedErr.Code, actualErr.Code)
+		assert.Equal(t, expectedErr.Message, actualErr.Message)
+		assert.Equal(t, expectedErr.Details, actualErr.Details)
+	}
+
+	t.Run("builds tokens and quota impact without persisting", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockRepo.On("GetIssuerQuotas", mock.Anything, request.Issuer).Return([]repository.IssuerQuota{
+			{Issuer: request.Issuer, Series: "2024-B", CBDCType: request.CBDCType, MintedTotal: 0, Quota: 50},
+			{Issuer: request.Issuer, Series: request.Series, CBDCType: request.CBDCType, MintedTotal: 200, Quota: 1000},
+		}, nil)
+
+		preview, err := service.PreviewIssue(context.Background(), request)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, preview.Count)
+		require.Len(t, preview.Tokens, 3)
+		assert.Equal(t, 300.0, preview.Amount)
+		for _, token := range preview.Tokens {
+			assert.NotEqual(t, uuid.Nil, token.TokenID)
+			assert.Equal(t, request.Owner, token.CurrentOwner)
+			assert.Equal(t, models.TokenStatusActive, token.Status)
+			assert.Equal(t, request.Series, token.Metadata.Series)
+		}
+		assert.NotEqual(t, preview.Tokens[0].TokenID, preview.Tokens[1].TokenID)
+
+		require.NotNil(t, preview.QuotaImpact)
+		assert.Equal(t, 800.0, preview.QuotaImpact.Remaining)
+		assert.Equal(t, 500.0, preview.QuotaImpact.RemainingAfter)
+
+		assertNothingWritten(t, mockRepo, mockDB)
+	})
+
+	t.Run("unrestricted issuance reports no quota impact", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockRepo.On("GetIssuerQuotas", mock.Anything, request.Issuer).Return([]repository.IssuerQuota{}, nil)
+
+		preview, err := service.PreviewIssue(context.Background(), request)
+		require.NoError(t, err)
+		assert.Nil(t, preview.QuotaImpact)
+		assertNothingWritten(t, mockRepo, mockDB)
+	})
+
+	t.Run("validation errors match issuance", func(t *testing.T) {
+		invalid := map[string]IssueTokenRequest{
+			"unsupported CBDC type": {CBDCType: "XYZ-CBDC", Denomination: 100, Owner: request.Owner, Issuer: request.Issuer, Series: request.Series, Quantity: 1},
+			"nil owner":             {CBDCType: request.CBDCType, Denomination: 100, Owner: uuid.Nil, Issuer: request.Issuer, Series: request.Series, Quantity: 1},
+			"zero quantity":         {CBDCType: request.CBDCType, Denomination: 100, Owner: request.Owner, Issuer: request.Issuer, Series: request.Series, Quantity: 0},
+			"missing issuer":        {CBDCType: request.CBDCType, Denomination: 100, Owner: request.Owner, Series: request.Series, Quantity: 1},
+		}
+
+		for name, req := range invalid {
+			t.Run(name, func(t *testing.T) {
+				mockRepo := new(MockTokenRepository)
+				mockDB := new(MockDatabase)
+				service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+				_, issueErr := service.IssueTokens(context.Background(), req)
+				_, previewErr := service.PreviewIssue(context.Background(), req)
+
+				require.Error(t, issueErr)
+				assertSameError(t, issueErr, previewErr)
+				assertNothingWritten(t, mockRepo, mockDB)
+			})
+		}
+	})
+
+	t.Run("quota exceeded error matches issuance", func(t *testing.T) {
+		quota := repository.IssuerQuota{Issuer: request.Issuer, Series: request.Series, CBDCType: request.CBDCType, MintedTotal: 900, Quota: 1000}
+
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
+		mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, request.Issuer, request.Series, request.CBDCType).Return(&quota, nil).Once()
+		_, issueErr := service.IssueTokens(context.Background(), request)
+		require.Error(t, issueErr)
+
+		mockRepo.On("GetIssuerQuotas", mock.Anything, request.Issuer).Return([]repository.IssuerQuota{quota}, nil)
+		_, previewErr := service.PreviewIssue(context.Background(), request)
+
+		assertSameError(t, issueErr, previewErr)
+		mockDB.AssertNumberOfCalls(t, "Transaction", 1)
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/repository"
+)
+
+// maxSigningKeyIDLength matches the key ID columns of token_signatures and issuer_signing_keys
+const maxSigningKeyIDLength = 100
+
+// RotateIssuerKeyRequest represents a request to replace an issuer's active signing key
+type RotateIssuerKeyRequest struct {
+	KeyID      string `json:"key_id" binding:"required"`
+	PrivateKey string `json:"private_key" binding:"required"` // Base64-encoded Ed25519 seed or private key
+}
+
+// RotateIssuerKeyResponse represents the result of an issuer key rotation
+type RotateIssuerKeyResponse struct {
+	Key          repository.IssuerSigningKey `json:"key"`
+	RetiredKeyID string                      `json:"retired_key_id,omitempty"`
+}
+
+// SetKeyRegistry signs issuance with keys from the registry and enables issuer key rotation
+func (s *TokenService) SetKeyRegistry(registry *KeyRegistry) {
+	s.keyRegistry = registry
+	s.keySource = registry
+}
+
+// LoadIssuerKeys registers the public keys of every key previously rotated in, so tokens they
+// signed still verify after a restart
+func (s *TokenService) LoadIssuerKeys(ctx context.Context) error {
+	if s.keyRegistry == nil {
+		return nil
+	}
+
+	keys, err := s.repo.GetSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load issuer signing keys: %w", err)
+	}
+
+	for _, key := range keys {
+		if len(key.PublicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid public key for signing key %s", key.KeyID)
+		}
+		s.keyRegistry.AddVerificationKey(key.KeyID, ed25519.PublicKey(key.PublicKey))
+	}
+
+	return nil
+}
+
+// RotateIssuerKey makes a new key the issuer's active signing key. Tokens issued afterwards
+// are signed with it, while the retired key stays registered so tokens it signed still
+// verify. Key IDs identify a key for good and cannot be reused.
+func (s *TokenService) RotateIssuerKey(ctx context.Context, issuer string, req RotateIssuerKeyRequest) (*RotateIssuerKeyResponse, error) {
+	if s.keyRegistry == nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrServiceUnavailable,
+			"issuer key rotation is not configured",
+		)
+	}
+
+	issuer = strings.TrimSpace(issuer)
+	keyID := strings.TrimSpace(req.KeyID)
+	if issuer == "" || keyID == "" {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"issuer and key ID are required",
+		)
+	}
+	if len(keyID) > maxSigningKeyIDLength {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("key ID cannot be longer than %d characters", maxSigningKeyIDLength),
+		)
+	}
+
+	privateKey, err := parseSigningKey(req.PrivateKey)
+	if err != nil {
+		return nil, errors.NewTokenManagementError(errors.ErrValidation, err.Error())
+	}
+
+	if s.keyRegistry.HasKey(keyID) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("signing key %s is already registered", keyID),
+		)
+	}
+
+	key := repository.IssuerSigningKey{
+		KeyID:     keyID,
+		Issuer:    issuer,
+		Algorithm: SignatureAlgorithm,
+		PublicKey: privateKey.Public().(ed25519.PublicKey),
+	}
+	retiredKeyID, err := s.repo.RotateSigningKey(ctx, &key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate issuer signing key: %w", err)
+	}
+
+	if err := s.keyRegistry.Activate(issuer, keyID, privateKey); err != nil {
+		return nil, errors.NewTokenManagementError(errors.ErrValidation, err.Error())
+	}
+
+	return &RotateIssuerKeyResponse{Key: key, RetiredKeyID: retiredKeyID}, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_RotateIssuerKey(t *testing.T) {
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	_, keyA, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, keyB, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, keyC, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	service.SetKeyRegistry(NewKeyRegistry(NewStaticKeySource("fed-2025-01", keyA)))
+
+	// Tokens and signatures are kept so verification reads back what issuance stored
+	signatures := map[uuid.UUID]*repository.TokenSignature{}
+	tokens := map[uuid.UUID]*models.Token{}
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SaveSignatureWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.TokenSignature")).
+		Run(func(args mock.Arguments) {
+			signature := args.Get(2).(*repository.TokenSignature)
+			signatures[signature.TokenID] = signature
+		}).Return(nil)
+
+	issue := func(issuer string) models.Token {
+		response, err := service.IssueTokens(context.Background(), IssueTokenRequest{
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 10.0,
+			Owner:        uuid.New(),
+			Issuer:       issuer,
+			Series:       "2025-A",
+			Quantity:     1,
+		})
+		require.NoError(t, err)
+		token := response.Tokens[0]
+		tokens[token.TokenID] = &token
+		return token
+	}
+	rotate := func(keyID string, privateKey ed25519.PrivateKey) (*RotateIssuerKeyResponse, error) {
+		return service.RotateIssuerKey(context.Background(), "Federal Reserve", RotateIssuerKeyRequest{
+			KeyID:      keyID,
+			PrivateKey: base64.StdEncoding.EncodeToString(privateKey.Seed()),
+		})
+	}
+	assertVerifies := func(token models.Token, keyID string) {
+		assert.Equal(t, keyID, signatures[token.TokenID].KeyID)
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(tokens[token.TokenID], nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signatures[token.TokenID], nil).Once()
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, nil)
+		assert.NoError(t, err)
+		assert.True(t, valid, "token signed with %s should verify", keyID)
+	}
+
+	tokenA := issue("Federal Reserve")
+
+	mockRepo.On("RotateSigningKey", mock.Anything, mock.MatchedBy(func(key *repository.IssuerSigningKey) bool {
+		return key.KeyID == "fed-2025-02"
+	})).Return("", nil).Once()
+	response, err := rotate("fed-2025-02", keyB)
+	require.NoError(t, err)
+	assert.Equal(t, "Federal Reserve", response.Key.Issuer)
+	assert.Equal(t, []byte(keyB.Public().(ed25519.PublicKey)), response.Key.PublicKey)
+
+	tokenB := issue("Federal Reserve")
+	otherIssuer := issue("European Central Bank")
+
+	assertVerifies(tokenA, "fed-2025-01")
+	assertVerifies(tokenB, "fed-2025-02")
+	assertVerifies(otherIssuer, "fed-2025-01")
+
+	t.Run("a second rotation retires the first rotated key", func(t *testing.T) {
+		mockRepo.On("RotateSigningKey", mock.Anything, mock.Anything).Return("fed-2025-02", nil).Once()
+		response, err := rotate("fed-2025-03", keyC)
+		require.NoError(t, err)
+		assert.Equal(t, "fed-2025-02", response.RetiredKeyID)
+
+		tokenC := issue("Federal Reserve")
+		assertVerifies(tokenA, "fed-2025-01")
+		assertVerifies(tokenB, "fed-2025-02")
+		assertVerifies(tokenC, "fed-2025-03")
+	})
+
+	t.Run("key IDs cannot be reused", func(t *testing.T) {
+		_, err := rotate("fed-2025-01", keyC)
+		assert.Error(t, err)
+		_, err = rotate("fed-2025-02", keyC)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid keys are rejected", func(t *testing.T) {
+		_, err := service.RotateIssuerKey(context.Background(), "Federal Reserve", RotateIssuerKeyRequest{
+			KeyID:      "fed-2025-04",
+			PrivateKey: base64.StdEncoding.EncodeToString([]byte("short")),
+		})
+		assert.Error(t, err)
+	})
+
+	mockRepo.AssertNumberOfCalls(t, "RotateSigningKey", 2)
+}
+
+func TestTokenService_LoadIssuerKeys(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	mockRepo := new(MockTokenRepository)
+	mockRepo.On("GetSigningKeys", mock.Anything).Return([]repository.IssuerSigningKey{
+		{KeyID: "fed-2024-07", Issuer: "Federal Reserve", PublicKey: publicKey, Status: repository.SigningKeyRetired},
+	}, nil)
+
+	service := NewTokenServiceWithDeps(mockRepo, nil)
+	registry := NewKeyRegistry(nil)
+	service.SetKeyRegistry(registry)
+	require.NoError(t, service.LoadIssuerKeys(context.Background()))
+
+	loaded, err := registry.PublicKey("fed-2024-07")
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, loaded)
+
+	// Loaded keys only verify; without a default key issuers have nothing to sign with
+	_, _, err = registry.SigningKey("Federal Reserve")
+	assert.Equal(t, ErrNoSigningKey, err)
+	assert.True(t, ed25519.Verify(loaded, []byte("payload"), ed25519.Sign(privateKey, []byte("payload"))))
+}
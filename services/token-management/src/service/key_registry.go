@@ -0,0 +1,97 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoSigningKey is returned by KeyRegistry.SigningKey when an issuer has no active key of
+// its own and no default key is configured
+var ErrNoSigningKey = errors.New("no signing key configured")
+
+// KeyRegistry is a SigningKeySource holding keys by key ID, with an active signing key per
+// issuer. Issuers without a key of their own sign with the default source, typically the
+// configured key. Retired keys keep their public half so older signatures stay verifiable.
+// Private keys are held in memory only: after a restart an issuer signs with the default key
+// until a new key is rotated in.
+type KeyRegistry struct {
+	mu          sync.RWMutex
+	defaults    SigningKeySource
+	active      map[string]string // Issuer to its active key ID
+	privateKeys map[string]ed25519.PrivateKey
+	publicKeys  map[string]ed25519.PublicKey
+}
+
+// NewKeyRegistry creates a key registry falling back to defaults, which may be nil when no
+// key is configured
+func NewKeyRegistry(defaults SigningKeySource) *KeyRegistry {
+	return &KeyRegistry{
+		defaults:    defaults,
+		active:      make(map[string]string),
+		privateKeys: make(map[string]ed25519.PrivateKey),
+		publicKeys:  make(map[string]ed25519.PublicKey),
+	}
+}
+
+// SigningKey returns the issuer's active key, or the default key if the issuer has none
+func (r *KeyRegistry) SigningKey(issuer string) (string, ed25519.PrivateKey, error) {
+	r.mu.RLock()
+	keyID, ok := r.active[issuer]
+	privateKey := r.privateKeys[keyID]
+	r.mu.RUnlock()
+
+	if ok {
+		return keyID, privateKey, nil
+	}
+	if r.defaults != nil {
+		return r.defaults.SigningKey(issuer)
+	}
+	return "", nil, ErrNoSigningKey
+}
+
+// PublicKey returns the public key for a key ID, whether active, retired or a default key
+func (r *KeyRegistry) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	r.mu.RLock()
+	publicKey, ok := r.publicKeys[keyID]
+	r.mu.RUnlock()
+
+	if ok {
+		return publicKey, nil
+	}
+	if r.defaults != nil {
+		return r.defaults.PublicKey(keyID)
+	}
+	return nil, fmt.Errorf("unknown signing key: %s", keyID)
+}
+
+// HasKey reports whether a key ID is already registered, so key IDs are never reused
+func (r *KeyRegistry) HasKey(keyID string) bool {
+	_, err := r.PublicKey(keyID)
+	return err == nil
+}
+
+// Activate makes privateKey the issuer's active signing key. The key it replaces stays
+// registered for verification.
+func (r *KeyRegistry) Activate(issuer, keyID string, privateKey ed25519.PrivateKey) error {
+	if r.HasKey(keyID) {
+		return fmt.Errorf("signing key %s is already registered", keyID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active[issuer] = keyID
+	r.privateKeys[keyID] = privateKey
+	r.publicKeys[keyID] = privateKey.Public().(ed25519.PublicKey)
+	return nil
+}
+
+// AddVerificationKey registers a public key that verifies signatures but never signs
+func (r *KeyRegistry) AddVerificationKey(keyID string, publicKey ed25519.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.publicKeys[keyID] = publicKey
+}
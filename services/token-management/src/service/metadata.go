@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"echopay/shared/libraries/errors"
+)
+
+// Limits on the free-text token metadata stored with every minted token
+const (
+	MaxIssuerLength = 256
+	MaxSeriesLength = 64
+)
+
+// sanitizeMetadataText strips control characters from a free-text metadata value
+func sanitizeMetadataText(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// validateMetadataLength rejects a metadata value longer than max characters
+func validateMetadataLength(field, value string, max int) error {
+	if utf8.RuneCountInString(value) > max {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("%s cannot be longer than %d characters", field, max),
+		)
+	}
+	return nil
+}
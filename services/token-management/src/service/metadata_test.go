@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestTokenService_IssueTokens_MetadataLimits(t *testing.T) {
+	t.Run("over-long series is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		_, err := service.IssueTokens(context.Background(), IssueTokenRequest{
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			Owner:        uuid.New(),
+			Issuer:       "Federal Reserve",
+			Series:       strings.Repeat("A", MaxSeriesLength+1),
+			Quantity:     1,
+		})
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		assert.Contains(t, tokenErr.Message, "series")
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+
+	t.Run("control characters are stripped", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, "Federal Reserve", "2025-A", models.CBDCTypeUSD).Return(nil, nil).Once()
+		mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+			return token.Metadata.Issuer == "Federal Reserve" && token.Metadata.Series == "2025-A"
+		})).Return(nil).Once()
+		mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := service.IssueTokens(context.Background(), IssueTokenRequest{
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			Owner:        uuid.New(),
+			Issuer:       "Federal\x00 Reserve\x1b",
+			Series:       "2025-\u0085A\n",
+			Quantity:     1,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Count)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+// Reasons a referenced transaction could not be included in a token's provenance
+const (
+	TransactionUnavailableNotFound      = "not_found"
+	TransactionUnavailableLookupFailed  = "lookup_failed"
+	TransactionUnavailableNotConfigured = "lookup_not_configured"
+)
+
+// ProvenanceOperationTransaction marks a provenance entry for a transaction in the token's
+// history that has no matching ownership transfer in the audit trail
+const ProvenanceOperationTransaction = "TRANSACTION"
+
+// ProvenanceEntry is one event in a token's lineage. Ownership transfers carry the linked
+// transaction, or the reason it is unavailable.
+type ProvenanceEntry struct {
+	Timestamp              time.Time              `json:"timestamp"`
+	Operation              string                 `json:"operation"`
+	OldStatus              models.TokenStatus     `json:"old_status,omitempty"`
+	NewStatus              models.TokenStatus     `json:"new_status,omitempty"`
+	OldOwner               *uuid.UUID             `json:"old_owner,omitempty"`
+	NewOwner               *uuid.UUID             `json:"new_owner,omitempty"`
+	Metadata               map[string]interface{} `json:"metadata,omitempty"`
+	TransactionID          *uuid.UUID             `json:"transaction_id,omitempty"`
+	Transaction            *TransactionSummary    `json:"transaction,omitempty"`
+	TransactionUnavailable string                 `json:"transaction_unavailable,omitempty"`
+}
+
+// TokenProvenance is the time-ordered lineage of a token from issuance onwards
+type TokenProvenance struct {
+	Token   models.Token      `json:"token"`
+	Entries []ProvenanceEntry `json:"entries"`
+}
+
+// SetTransactionLookup sets where transactions referenced by token provenance are fetched from
+func (s *TokenService) SetTransactionLookup(lookup TransactionLookup) {
+	s.transactions = lookup
+}
+
+// GetTokenProvenance merges a token's audit trail with the transactions in its history. The
+// audit trail does not record transaction IDs, so ownership transfers are paired with the
+// token's transaction history in order; any history left over is listed on its own. A
+// transaction that cannot be fetched is marked unavailable instead of failing the request.
+func (s *TokenService) GetTokenProvenance(ctx context.Context, tokenID uuid.UUID) (*TokenProvenance, error) {
+	if tokenID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token ID cannot be nil",
+		)
+	}
+
+	token, err := s.GetToken(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditTrail, err := s.repo.GetAuditTrail(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token audit trail: %w", err)
+	}
+
+	// The audit trail is returned newest first
+	sort.SliceStable(auditTrail, func(i, j int) bool {
+		a, b := auditTrail[i], auditTrail[j]
+		if !a.Timestamp.Time.Equal(b.Timestamp.Time) {
+			return a.Timestamp.Time.Before(b.Timestamp.Time)
+		}
+		return a.Sequence < b.Sequence
+	})
+
+	history, err := s.fullTransactionHistory(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	next := 0
+	entries := make([]ProvenanceEntry, 0, len(auditTrail)+len(history))
+
+	for _, auditEntry := range auditTrail {
+		entry := newProvenanceEntry(auditEntry)
+		if auditEntry.Operation == "OWNERSHIP_TRANSFER" && next < len(history) {
+			s.attachTransaction(ctx, &entry, history[next])
+			next++
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, transactionID := range history[next:] {
+		entry := ProvenanceEntry{Operation: ProvenanceOperationTransaction}
+		s.attachTransaction(ctx, &entry, transactionID)
+		if entry.Transaction != nil {
+			entry.Timestamp = entry.Transaction.CreatedAt
+		}
+		entries = append(entries, entry)
+	}
+
+	// Entries without a known time sort last
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Timestamp, entries[j].Timestamp
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+
+	return &TokenProvenance{
+		Token:   *token,
+		Entries: entries,
+	}, nil
+}
+
+// attachTransaction links a transaction to a provenance entry, recording why it is
+// unavailable if it cannot be fetched
+func (s *TokenService) attachTransaction(ctx context.Context, entry *ProvenanceEntry, transactionID uuid.UUID) {
+	id := transactionID
+	entry.TransactionID = &id
+
+	if s.transactions == nil {
+		entry.TransactionUnavailable = TransactionUnavailableNotConfigured
+		return
+	}
+
+	transaction, err := s.transactions.GetTransaction(ctx, transactionID)
+	if err != nil {
+		entry.TransactionUnavailable = TransactionUnavailableLookupFailed
+		return
+	}
+	if transaction == nil {
+		entry.TransactionUnavailable = TransactionUnavailableNotFound
+		return
+	}
+	entry.Transaction = transaction
+}
+
+// newProvenanceEntry converts an audit trail entry to a provenance entry
+func newProvenanceEntry(auditEntry repository.TokenAuditEntry) ProvenanceEntry {
+	entry := ProvenanceEntry{
+		Timestamp: auditEntry.Timestamp.Time,
+		Operation: auditEntry.Operation,
+		OldStatus: auditEntry.OldStatus,
+		NewStatus: auditEntry.NewStatus,
+		Metadata:  auditEntry.Metadata,
+	}
+	if auditEntry.OldOwner != uuid.Nil {
+		oldOwner := auditEntry.OldOwner
+		entry.OldOwner = &oldOwner
+	}
+	if auditEntry.NewOwner != uuid.Nil {
+		newOwner := auditEntry.NewOwner
+		entry.NewOwner = &newOwner
+	}
+	return entry
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+// mapTransactionLookup serves transactions from a map and fails for IDs in failing
+type mapTransactionLookup struct {
+	transactions map[uuid.UUID]*TransactionSummary
+	failing      map[uuid.UUID]bool
+}
+
+func (l *mapTransactionLookup) GetTransaction(ctx context.Context, transactionID uuid.UUID) (*TransactionSummary, error) {
+	if l.failing[transactionID] {
+		return nil, fmt.Errorf("transaction service unavailable")
+	}
+	return l.transactions[transactionID], nil
+}
+
+func TestTokenService_GetTokenProvenance(t *testing.T) {
+	tokenID := uuid.New()
+	issuer := uuid.New()
+	firstOwner := uuid.New()
+	secondOwner := uuid.New()
+	settledTx, archivedTx, orphanTx := uuid.New(), uuid.New(), uuid.New()
+	issuedAt := time.Now().Add(-3 * time.Hour).UTC()
+
+	token := &models.Token{
+		TokenID:            tokenID,
+		CBDCType:           models.CBDCTypeUSD,
+		Denomination:       100.0,
+		CurrentOwner:       secondOwner,
+		Status:             models.TokenStatusActive,
+		TransactionHistory: []uuid.UUID{archivedTx, orphanTx},
+	}
+
+	at := func(offset time.Duration) sql.NullTime {
+		return sql.NullTime{Time: issuedAt.Add(offset), Valid: true}
+	}
+
+	// The repository returns the audit trail newest first
+	auditTrail := []repository.TokenAuditEntry{
+		{Operation: "OWNERSHIP_TRANSFER", OldOwner: firstOwner, NewOwner: secondOwner, Timestamp: at(2 * time.Hour), Sequence: 3},
+		{Operation: "OWNERSHIP_TRANSFER", OldOwner: issuer, NewOwner: firstOwner, Timestamp: at(time.Hour), Sequence: 2},
+		{Operation: "CREATE", NewStatus: models.TokenStatusActive, NewOwner: issuer, Timestamp: at(0), Sequence: 1},
+	}
+
+	newService := func() (*TokenService, *MockTokenRepository) {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetByID", context.Background(), tokenID).Return(token, nil)
+		mockRepo.On("GetAuditTrail", context.Background(), tokenID).Return(append([]repository.TokenAuditEntry(nil), auditTrail...), nil)
+		// The oldest history entry has been moved out of the token row
+		mockRepo.On("GetArchivedTransactionHistory", context.Background(), tokenID).Return([]uuid.UUID{settledTx}, nil)
+		return NewTokenServiceWithDeps(mockRepo, nil), mockRepo
+	}
+
+	t.Run("transfers are linked to transactions and unavailable ones are marked", func(t *testing.T) {
+		service, mockRepo := newService()
+		service.SetTransactionLookup(&mapTransactionLookup{
+			transactions: map[uuid.UUID]*TransactionSummary{
+				settledTx: {ID: settledTx, Amount: 100.0, Currency: "USD-CBDC", Status: "completed", CreatedAt: issuedAt.Add(time.Hour)},
+			},
+			failing: map[uuid.UUID]bool{orphanTx: true},
+		})
+
+		provenance, err := service.GetTokenProvenance(context.Background(), tokenID)
+
+		require.NoError(t, err)
+		assert.Equal(t, tokenID, provenance.Token.TokenID)
+		require.Len(t, provenance.Entries, 4)
+
+		issuance := provenance.Entries[0]
+		assert.Equal(t, "CREATE", issuance.Operation)
+		assert.Nil(t, issuance.TransactionID)
+
+		first := provenance.Entries[1]
+		assert.Equal(t, "OWNERSHIP_TRANSFER", first.Operation)
+		assert.Equal(t, &firstOwner, first.NewOwner)
+		assert.Equal(t, &settledTx, first.TransactionID)
+		require.NotNil(t, first.Transaction)
+		assert.Equal(t, "completed", first.Transaction.Status)
+		assert.Empty(t, first.TransactionUnavailable)
+
+		second := provenance.Entries[2]
+		assert.Equal(t, &archivedTx, second.TransactionID)
+		assert.Nil(t, second.Transaction)
+		assert.Equal(t, TransactionUnavailableNotFound, second.TransactionUnavailable)
+
+		// History without a matching transfer is listed last when its time is unknown
+		orphan := provenance.Entries[3]
+		assert.Equal(t, ProvenanceOperationTransaction, orphan.Operation)
+		assert.Equal(t, &orphanTx, orphan.TransactionID)
+		assert.Equal(t, TransactionUnavailableLookupFailed, orphan.TransactionUnavailable)
+		assert.True(t, orphan.Timestamp.IsZero())
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("transactions are unavailable without a lookup", func(t *testing.T) {
+		service, _ := newService()
+
+		provenance, err := service.GetTokenProvenance(context.Background(), tokenID)
+
+		require.NoError(t, err)
+		for _, entry := range provenance.Entries {
+			if entry.TransactionID != nil {
+				assert.Equal(t, TransactionUnavailableNotConfigured, entry.TransactionUnavailable)
+			}
+		}
+	})
+}
+
+func TestHTTPTransactionLookup_GetTransaction(t *testing.T) {
+	found, missing := uuid.New(), uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/transactions/" + found.String():
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":         found,
+				"amount":     25.5,
+				"currency":   "EUR-CBDC",
+				"status":     "completed",
+				"created_at": time.Now().UTC(),
+			})
+		case "/api/v1/transactions/" + missing.String():
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	lookup := NewHTTPTransactionLookup(server.URL+"/", time.Second)
+
+	transaction, err := lookup.GetTransaction(context.Background(), found)
+	require.NoError(t, err)
+	assert.Equal(t, found, transaction.ID)
+	assert.Equal(t, 25.5, transaction.Amount)
+
+	transaction, err = lookup.GetTransaction(context.Background(), missing)
+	assert.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	_, err = lookup.GetTransaction(context.Background(), uuid.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+)
+
+// DefaultMaxClockSkew is how far a transfer request's timestamp may differ from the server
+// clock unless configured otherwise
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// MaxNonceLength caps the length of a transfer request nonce
+const MaxNonceLength = 128
+
+// ReplayGuard validates the nonce and timestamp of transfer requests. A request's timestamp must
+// be within the allowed skew of the server clock; its nonce is then recorded in the database
+// with the transfer, so a nonce is used up only by a transfer that succeeds and replays are
+// rejected across every service instance. A nonce is remembered only while a request carrying
+// it could still pass the timestamp check; after that a replay is rejected as stale.
+type ReplayGuard struct {
+	requireNonce bool
+	maxSkew      time.Duration
+}
+
+// NewReplayGuard creates a replay guard from configuration
+func NewReplayGuard(cfg config.ReplayProtectionConfig) *ReplayGuard {
+	maxSkew := cfg.MaxClockSkew
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxClockSkew
+	}
+	return &ReplayGuard{
+		requireNonce: cfg.RequireNonce,
+		maxSkew:      maxSkew,
+	}
+}
+
+// Check validates a request's nonce and timestamp at now. Requests without a nonce pass
+// unless nonces are required. Whether the nonce was already used is checked when it is
+// recorded; see NonceExpiry.
+func (g *ReplayGuard) Check(nonce string, timestamp, now time.Time) error {
+	if nonce == "" {
+		if g.requireNonce {
+			return errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"transfer nonce is required",
+			)
+		}
+		return nil
+	}
+
+	if len(nonce) > MaxNonceLength {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("transfer nonce cannot exceed %d characters", MaxNonceLength),
+		)
+	}
+
+	if timestamp.IsZero() {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"transfer timestamp is required with a nonce",
+		)
+	}
+
+	skew := now.Sub(timestamp)
+	if skew > g.maxSkew || skew < -g.maxSkew {
+		return errors.NewTokenManagementError(
+			errors.ErrReplayDetected,
+			"transfer timestamp is outside the allowed clock skew",
+		).WithDetails(map[string]interface{}{
+			"max_skew_seconds": g.maxSkew.Seconds(),
+		})
+	}
+
+	return nil
+}
+
+// NonceExpiry returns when a nonce sent with timestamp can be forgotten, because any replay
+// after that fails the timestamp check
+func (g *ReplayGuard) NonceExpiry(timestamp time.Time) time.Time {
+	return timestamp.Add(g.maxSkew)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	tokenErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok, "Expected EchoPayError, got %v", err)
+	assert.Equal(t, code, tokenErr.Code)
+}
+
+func TestReplayGuard_Check(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("accepts a fresh request", func(t *testing.T) {
+		guard := NewReplayGuard(config.ReplayProtectionConfig{MaxClockSkew: time.Minute})
+
+		assert.NoError(t, guard.Check("nonce-1", now.Add(-30*time.Second), now))
+		assert.NoError(t, guard.Check("nonce-2", now.Add(30*time.Second), now))
+	})
+
+	t.Run("rejects a timestamp outside the allowed skew", func(t *testing.T) {
+		guard := NewReplayGuard(config.ReplayProtectionConfig{MaxClockSkew: time.Minute})
+
+		assertErrorCode(t, guard.Check("stale", now.Add(-2*time.Minute), now), errors.ErrReplayDetected)
+		assertErrorCode(t, guard.Check("future", now.Add(2*time.Minute), now), errors.ErrReplayDetected)
+	})
+
+	t.Run("a nonce is remembered until a replay would be stale", func(t *testing.T) {
+		guard := NewReplayGuard(config.ReplayProtectionConfig{MaxClockSkew: time.Minute})
+
+		expiresAt := guard.NonceExpiry(now)
+		assert.Equal(t, now.Add(time.Minute), expiresAt)
+		assertErrorCode(t, guard.Check("nonce-1", now, expiresAt.Add(time.Second)), errors.ErrReplayDetected)
+	})
+
+	t.Run("nonces are optional unless required", func(t *testing.T) {
+		optional := NewReplayGuard(config.ReplayProtectionConfig{})
+		assert.NoError(t, optional.Check("", time.Time{}, now))
+
+		required := NewReplayGuard(config.ReplayProtectionConfig{RequireNonce: true})
+		assertErrorCode(t, required.Check("", time.Time{}, now), errors.ErrValidation)
+	})
+
+	t.Run("a nonce requires a timestamp", func(t *testing.T) {
+		guard := NewReplayGuard(config.ReplayProtectionConfig{})
+		assertErrorCode(t, guard.Check("nonce-1", time.Time{}, now), errors.ErrValidation)
+	})
+}
+
+func TestTokenService_TransferToken_RejectsReplayedNonce(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	tokenID := uuid.New()
+	owner := uuid.New()
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	service.SetClock(clock.NewFake(now))
+
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: owner,
+		Status:       models.TokenStatusActive,
+	}
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, owner).Return(0, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+
+	request := TransferTokenRequest{
+		TokenID:       tokenID,
+		NewOwner:      uuid.New(),
+		TransactionID: uuid.New(),
+		Nonce:         "c5f0e0d2-transfer-1",
+		Timestamp:     now.Add(-time.Second),
+	}
+	expiresAt := request.Timestamp.Add(DefaultMaxClockSkew)
+	mockRepo.On("RecordTransferNonceWithTx", mock.Anything, mock.Anything, owner, request.Nonce, now, expiresAt).Return(true, nil).Once()
+	mockRepo.On("RecordTransferNonceWithTx", mock.Anything, mock.Anything, owner, request.Nonce, now, expiresAt).Return(false, nil).Once()
+
+	_, err := service.TransferToken(context.Background(), request)
+	require.NoError(t, err)
+
+	// The transfer updated the mocked token; restore its owner and send the captured request again
+	token.CurrentOwner = owner
+	_, err = service.TransferToken(context.Background(), request)
+	assertErrorCode(t, err, errors.ErrReplayDetected)
+	mockRepo.AssertNumberOfCalls(t, "UpdateWithTx", 1)
+}
+
+func TestTokenService_TransferToken_FailedTransferKeepsNonce(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	tokenID := uuid.New()
+	owner := uuid.New()
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	service.SetClock(clock.NewFake(now))
+
+	// A frozen token fails validation, so the nonce must still be usable for a later attempt
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: owner,
+		Status:       models.TokenStatusFrozen,
+	}
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+
+	_, err := service.TransferToken(context.Background(), TransferTokenRequest{
+		TokenID:       tokenID,
+		NewOwner:      uuid.New(),
+		TransactionID: uuid.New(),
+		Nonce:         "c5f0e0d2-transfer-2",
+		Timestamp:     now,
+	})
+
+	assertErrorCode(t, err, errors.ErrTokenFrozen)
+	mockRepo.AssertNotCalled(t, "RecordTransferNonceWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
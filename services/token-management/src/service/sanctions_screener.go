@@ -0,0 +1,103 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsScreener checks wallet owners against sanctions lists. When an owner is not
+// clear, listRef identifies the list entry that matched.
+type SanctionsScreener interface {
+	Screen(ownerID uuid.UUID) (clear bool, listRef string)
+}
+
+// AllowAllScreener clears every owner. It is the default when no provider is configured.
+type AllowAllScreener struct{}
+
+// Screen always reports the owner as clear
+func (AllowAllScreener) Screen(ownerID uuid.UUID) (bool, string) {
+	return true, ""
+}
+
+// MapSanctionsScreener blocks owners present in a fixed map of owner ID to list reference
+type MapSanctionsScreener struct {
+	mu     sync.RWMutex
+	listed map[uuid.UUID]string
+}
+
+// NewMapSanctionsScreener creates a screener backed by the given listed owners
+func NewMapSanctionsScreener(listed map[uuid.UUID]string) *MapSanctionsScreener {
+	entries := make(map[uuid.UUID]string, len(listed))
+	for ownerID, listRef := range listed {
+		entries[ownerID] = listRef
+	}
+	return &MapSanctionsScreener{listed: entries}
+}
+
+// Add lists an owner under the given reference
+func (m *MapSanctionsScreener) Add(ownerID uuid.UUID, listRef string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listed[ownerID] = listRef
+}
+
+// Screen reports whether the owner is absent from the map
+func (m *MapSanctionsScreener) Screen(ownerID uuid.UUID) (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if listRef, ok := m.listed[ownerID]; ok {
+		return false, listRef
+	}
+	return true, ""
+}
+
+// screeningResult is a cached screening outcome
+type screeningResult struct {
+	clear     bool
+	listRef   string
+	expiresAt time.Time
+}
+
+// CachingScreener caches results from another screener for a fixed TTL so that bulk
+// transfers involving the same owners do not repeatedly call the provider
+type CachingScreener struct {
+	next    SanctionsScreener
+	ttl     time.Duration
+	mu      sync.Mutex
+	results map[uuid.UUID]screeningResult
+	now     func() time.Time
+}
+
+// NewCachingScreener wraps a screener with a result cache
+func NewCachingScreener(next SanctionsScreener, ttl time.Duration) *CachingScreener {
+	return &CachingScreener{
+		next:    next,
+		ttl:     ttl,
+		results: make(map[uuid.UUID]screeningResult),
+		now:     time.Now,
+	}
+}
+
+// Screen returns a cached result when one is still fresh, otherwise screens and caches
+func (c *CachingScreener) Screen(ownerID uuid.UUID) (bool, string) {
+	c.mu.Lock()
+	if result, ok := c.results[ownerID]; ok && c.now().Before(result.expiresAt) {
+		c.mu.Unlock()
+		return result.clear, result.listRef
+	}
+	c.mu.Unlock()
+
+	clear, listRef := c.next.Screen(ownerID)
+
+	c.mu.Lock()
+	c.results[ownerID] = screeningResult{
+		clear:     clear,
+		listRef:   listRef,
+		expiresAt: c.now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	return clear, listRef
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingScreener records how many times the provider is called
+type countingScreener struct {
+	calls int
+	next  SanctionsScreener
+}
+
+func (c *countingScreener) Screen(ownerID uuid.UUID) (bool, string) {
+	c.calls++
+	return c.next.Screen(ownerID)
+}
+
+func TestAllowAllScreener(t *testing.T) {
+	clear, listRef := AllowAllScreener{}.Screen(uuid.New())
+
+	assert.True(t, clear)
+	assert.Empty(t, listRef)
+}
+
+func TestMapSanctionsScreener(t *testing.T) {
+	listed := uuid.New()
+	screener := NewMapSanctionsScreener(map[uuid.UUID]string{listed: "OFAC-SDN-1234"})
+
+	clear, listRef := screener.Screen(listed)
+	assert.False(t, clear)
+	assert.Equal(t, "OFAC-SDN-1234", listRef)
+
+	clear, listRef = screener.Screen(uuid.New())
+	assert.True(t, clear)
+	assert.Empty(t, listRef)
+
+	added := uuid.New()
+	screener.Add(added, "UN-1267")
+	clear, listRef = screener.Screen(added)
+	assert.False(t, clear)
+	assert.Equal(t, "UN-1267", listRef)
+}
+
+func TestCachingScreener(t *testing.T) {
+	listed := uuid.New()
+	provider := &countingScreener{next: NewMapSanctionsScreener(map[uuid.UUID]string{listed: "EU-CFSP-77"})}
+
+	now := time.Now()
+	cache := NewCachingScreener(provider, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		clear, listRef := cache.Screen(listed)
+		assert.False(t, clear)
+		assert.Equal(t, "EU-CFSP-77", listRef)
+	}
+	assert.Equal(t, 1, provider.calls, "Expected repeated screens to be served from cache")
+
+	cache.Screen(uuid.New())
+	assert.Equal(t, 2, provider.calls, "Expected a different owner to be screened")
+
+	// Expired entries are screened again
+	now = now.Add(2 * time.Minute)
+	cache.Screen(listed)
+	assert.Equal(t, 3, provider.calls)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetFinalityWindow sets how long after a token last changed hands its transfer becomes
+// final. Until then the token cannot be destroyed without a forced, audited override, nor
+// merged away by dust consolidation. A window of zero or less makes transfers final at once.
+func (s *TokenService) SetFinalityWindow(window time.Duration) {
+	if window < 0 {
+		window = 0
+	}
+	s.finalityWindow = window
+}
+
+// transferFinalAt returns when the token's latest transfer becomes final, or nil if it is
+// already final at now or the token never changed hands. Transfers are read from the token's
+// audit trail.
+func (s *TokenService) transferFinalAt(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, now time.Time) (*time.Time, error) {
+	if s.finalityWindow <= 0 {
+		return nil, nil
+	}
+
+	lastTransfer, err := s.repo.GetLastOwnershipTransferWithTx(ctx, tx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check settlement finality: %w", err)
+	}
+	if lastTransfer == nil {
+		return nil, nil
+	}
+
+	finalAt := lastTransfer.Add(s.finalityWindow)
+	if !now.Before(finalAt) {
+		return nil, nil
+	}
+	return &finalAt, nil
+}
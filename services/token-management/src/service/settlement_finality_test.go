@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_DestroyToken_SettlementFinality(t *testing.T) {
+	const window = 30 * time.Minute
+	transferredAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	setup := func(now time.Time, lastTransfer *time.Time) (*TokenService, *MockTokenRepository, *models.Token) {
+		token := &models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: uuid.New(),
+			Status:       models.TokenStatusActive,
+		}
+
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetClock(clock.NewFake(now))
+		service.SetFinalityWindow(window)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(token, nil)
+		mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(false, nil)
+		mockRepo.On("GetLastOwnershipTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(lastTransfer, nil)
+		return service, mockRepo, token
+	}
+
+	t.Run("within the window", func(t *testing.T) {
+		service, mockRepo, token := setup(transferredAt.Add(window - time.Second), &transferredAt)
+
+		err := service.DestroyToken(context.Background(), token.TokenID)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError, got %v", err)
+		assert.Equal(t, errors.ErrNotYetFinal, tokenErr.Code)
+		assert.Equal(t, 409, tokenErr.GetHTTPStatus())
+		assert.Equal(t, transferredAt.Add(window), tokenErr.Details["final_at"])
+		assert.Equal(t, models.TokenStatusActive, token.Status)
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("at the window", func(t *testing.T) {
+		service, mockRepo, token := setup(transferredAt.Add(window), &transferredAt)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+
+		require.NoError(t, service.DestroyToken(context.Background(), token.TokenID))
+		assert.Equal(t, models.TokenStatusInvalid, token.Status)
+	})
+
+	t.Run("past the window", func(t *testing.T) {
+		service, mockRepo, token := setup(transferredAt.Add(window + time.Hour), &transferredAt)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+
+		require.NoError(t, service.DestroyToken(context.Background(), token.TokenID))
+		assert.Equal(t, models.TokenStatusInvalid, token.Status)
+	})
+
+	t.Run("forced destruction overrides finality", func(t *testing.T) {
+		service, mockRepo, token := setup(transferredAt.Add(time.Minute), &transferredAt)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, token.TokenID, "FORCED_DESTROY", map[string]interface{}{
+			"note":       "issuer ordered destruction",
+			"overridden": []string{"token transfer is not final until 2025-03-01T09:30:00Z"},
+		}).Return(nil).Once()
+
+		require.NoError(t, service.ForceDestroyToken(context.Background(), token.TokenID, "issuer ordered destruction"))
+		assert.Equal(t, models.TokenStatusInvalid, token.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("never transferred tokens are final", func(t *testing.T) {
+		service, mockRepo, token := setup(transferredAt, nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+
+		require.NoError(t, service.DestroyToken(context.Background(), token.TokenID))
+	})
+}
+
+func TestTokenService_ConsolidateDust_SettlementFinality(t *testing.T) {
+	const window = 30 * time.Minute
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	owner := uuid.New()
+
+	newDust := func() models.Token {
+		return models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 0.25,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusActive,
+			Metadata:     models.TokenMetadata{Issuer: "Federal Reserve", Series: "2025A"},
+		}
+	}
+	settled := []models.Token{newDust(), newDust()}
+	recent := newDust()
+	tokens := append([]models.Token{recent}, settled...)
+
+	settledAt := now.Add(-2 * window)
+	receivedAt := now.Add(-time.Minute)
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	service.SetClock(clock.NewFake(now))
+	service.SetFinalityWindow(window)
+
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, 0).Return(tokens, nil)
+	for _, token := range tokens {
+		token := token
+		lastTransfer := &settledAt
+		if token.TokenID == recent.TokenID {
+			lastTransfer = &receivedAt
+		}
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&token, nil)
+		mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(false, nil)
+		mockRepo.On("GetLastOwnershipTransferWithTx", mock.Anything, mock.Anything, token.TokenID).Return(lastTransfer, nil)
+	}
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, mock.Anything, "CONSOLIDATE", mock.Anything).Return(nil)
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	response, err := service.ConsolidateDust(context.Background(), owner, models.CBDCTypeUSD, 1.00)
+	require.NoError(t, err)
+
+	// The token received within the finality window is skipped; the settled ones are merged
+	assert.ElementsMatch(t, []uuid.UUID{settled[0].TokenID, settled[1].TokenID}, response.ConsolidatedIDs)
+	assert.Equal(t, 1, response.SkippedCount)
+	require.Len(t, response.Tokens, 1)
+	assert.Equal(t, 0.50, response.Tokens[0].Denomination)
+	for _, call := range mockRepo.Calls {
+		if call.Method == "UpdateWithTx" {
+			assert.NotEqual(t, recent.TokenID, call.Arguments.Get(2).(*models.Token).TokenID)
+		}
+	}
+}
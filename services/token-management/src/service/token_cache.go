@@ -0,0 +1,43 @@
+package service
+
+import (
+	"database/sql"
+	"time"
+
+	"echopay/token-management/src/repository"
+)
+
+// SetTokenCache caches up to size token reads for ttl in front of the repository. A size or
+// TTL of zero or less leaves reads uncached.
+func (s *TokenService) SetTokenCache(size int, ttl time.Duration) {
+	if size <= 0 || ttl <= 0 {
+		return
+	}
+
+	cache := repository.NewCachingTokenRepository(s.repo, size, ttl)
+	s.repo = cache
+	s.db = &cacheReleasingTransactions{next: s.db, cache: cache}
+}
+
+// cacheReleasingTransactions tells the token cache when each transaction has finished, so
+// the tokens written in it can be cached again once the write is visible
+type cacheReleasingTransactions struct {
+	next  TransactionManager
+	cache *repository.CachingTokenRepository
+}
+
+// Transaction runs fn in a transaction and releases its writes from the cache afterwards,
+// whether it committed or rolled back
+func (t *cacheReleasingTransactions) Transaction(fn func(*sql.Tx) error) error {
+	var started *sql.Tx
+	defer func() {
+		if started != nil {
+			t.cache.ReleaseTx(started)
+		}
+	}()
+
+	return t.next.Transaction(func(tx *sql.Tx) error {
+		started = tx
+		return fn(tx)
+	})
+}
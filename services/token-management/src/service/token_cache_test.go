@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+)
+
+// fakeTxTransactions runs transactions with a placeholder tx, so writes in them are tracked
+// by the token cache as they would be against a database
+type fakeTxTransactions struct{}
+
+func (fakeTxTransactions) Transaction(fn func(*sql.Tx) error) error {
+	return fn(&sql.Tx{})
+}
+
+func TestTokenService_SetTokenCache(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func() (*TokenService, *MockTokenRepository, *models.Token) {
+		token := &models.Token{TokenID: uuid.New(), CurrentOwner: uuid.New(), Status: models.TokenStatusActive}
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(token, nil)
+		return NewTokenServiceWithDeps(mockRepo, fakeTxTransactions{}), mockRepo, token
+	}
+
+	t.Run("disabled cache always reads the repository", func(t *testing.T) {
+		service, mockRepo, token := newService()
+		service.SetTokenCache(0, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := service.GetToken(ctx, token.TokenID)
+			require.NoError(t, err)
+		}
+		mockRepo.AssertNumberOfCalls(t, "GetByID", 3)
+	})
+
+	t.Run("enabled cache serves repeated reads", func(t *testing.T) {
+		service, mockRepo, token := newService()
+		service.SetTokenCache(100, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := service.GetToken(ctx, token.TokenID)
+			require.NoError(t, err)
+		}
+		mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+	})
+
+	t.Run("a write in a transaction invalidates the cached token", func(t *testing.T) {
+		service, mockRepo, token := newService()
+		service.SetTokenCache(100, time.Minute)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		_, err := service.GetToken(ctx, token.TokenID)
+		require.NoError(t, err)
+
+		require.NoError(t, service.db.Transaction(func(tx *sql.Tx) error {
+			return service.repo.UpdateWithTx(ctx, tx, token)
+		}))
+
+		_, err = service.GetToken(ctx, token.TokenID)
+		require.NoError(t, err)
+		_, err = service.GetToken(ctx, token.TokenID)
+		require.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "GetByID", 2)
+	})
+}
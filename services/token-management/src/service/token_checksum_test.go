@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenChecksum(t *testing.T) {
+	issued := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	newToken := func() *models.Token {
+		return &models.Token{
+			TokenID:        uuid.MustParse("6f1c2f4e-8a0b-4c3d-9e5f-1a2b3c4d5e6f"),
+			CBDCType:       models.CBDCTypeUSD,
+			Denomination:   100.0,
+			CurrentOwner:   uuid.MustParse("0d9e8f7a-6b5c-4d3e-8f2a-1b0c9d8e7f6a"),
+			Status:         models.TokenStatusActive,
+			IssueTimestamp: issued,
+			Metadata: models.TokenMetadata{
+				Issuer: "Federal Reserve",
+				Series: "2025-A",
+			},
+			ComplianceFlags: models.ComplianceFlags{
+				KYCVerified: true,
+				AMLCleared:  true,
+			},
+		}
+	}
+
+	checksum, err := repository.TokenChecksum(newToken())
+	require.NoError(t, err)
+	assert.Len(t, checksum, 64)
+
+	t.Run("equal tokens have equal checksums", func(t *testing.T) {
+		token := newToken()
+		token.IssueTimestamp = issued.In(time.FixedZone("UTC+5", 5*60*60)).Truncate(time.Microsecond)
+		token.TransactionHistory = models.UUIDArray{}
+		token.CreatedAt = time.Now()
+		token.Version = 7
+
+		other, err := repository.TokenChecksum(token)
+		require.NoError(t, err)
+		assert.Equal(t, checksum, other)
+	})
+
+	t.Run("changed content changes the checksum", func(t *testing.T) {
+		mutations := map[string]func(*models.Token){
+			"owner":        func(token *models.Token) { token.CurrentOwner = uuid.New() },
+			"denomination": func(token *models.Token) { token.Denomination = 100.01 },
+			"status":       func(token *models.Token) { token.Status = models.TokenStatusFrozen },
+			"history":      func(token *models.Token) { token.TransactionHistory = models.UUIDArray{uuid.New()} },
+			"metadata":     func(token *models.Token) { token.Metadata.Series = "2025-B" },
+			"compliance":   func(token *models.Token) { token.ComplianceFlags.AMLCleared = false },
+		}
+
+		for name, mutate := range mutations {
+			token := newToken()
+			mutate(token)
+
+			other, err := repository.TokenChecksum(token)
+			require.NoError(t, err)
+			assert.NotEqual(t, checksum, other, name)
+		}
+	})
+}
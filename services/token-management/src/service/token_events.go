@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/google/uuid"
+
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/models"
+)
+
+// SetStatusTracker publishes token freezes, unfreezes, transfers and destructions to the
+// tracker's subscribers, so owners are notified of changes to their tokens
+func (s *TokenService) SetStatusTracker(tracker *events.TokenStatusTracker) {
+	s.statusTracker = tracker
+}
+
+// publishTokenEvent notifies subscribers following a token or its owner. For transfers,
+// previousOwner is notified too.
+func (s *TokenService) publishTokenEvent(eventType events.TokenEventType, tokenID, owner uuid.UUID, previousOwner *uuid.UUID, status models.TokenStatus, message string) {
+	s.statusTracker.Publish(events.TokenEvent{
+		Type:            eventType,
+		TokenID:         tokenID,
+		OwnerID:         owner,
+		PreviousOwnerID: previousOwner,
+		Status:          status,
+		Timestamp:       s.clock.Now().UTC(),
+		Message:         message,
+	})
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+// TokenHoldType identifies what is holding a token
+type TokenHoldType string
+
+const (
+	// TokenHoldFreeze is a freeze, optionally time-limited, that stops the token moving
+	TokenHoldFreeze TokenHoldType = "freeze"
+	// TokenHoldPendingTransfer reserves the token for a multi-sig transfer awaiting co-signers
+	TokenHoldPendingTransfer TokenHoldType = "pending_transfer"
+)
+
+// TokenHoldStatus is the state of a hold at the time it is reported
+type TokenHoldStatus string
+
+const (
+	TokenHoldActive   TokenHoldStatus = "active"
+	TokenHoldExpired  TokenHoldStatus = "expired" // Past its expiry but not yet lifted by the sweeper
+	TokenHoldReleased TokenHoldStatus = "released"
+)
+
+// TokenHold is a current or past hold on a token. HolderID is the party a pending transfer
+// reserves the token for; freezes are placed by operators and record no holder. The reason of
+// a released pending transfer is whether it completed or was rejected.
+type TokenHold struct {
+	Type        TokenHoldType   `json:"type"`
+	Status      TokenHoldStatus `json:"status"`
+	HolderID    *uuid.UUID      `json:"holder_id,omitempty"`
+	ReferenceID *uuid.UUID      `json:"reference_id,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   *time.Time      `json:"expires_at,omitempty"`
+	ReleasedAt  *time.Time      `json:"released_at,omitempty"`
+}
+
+// TokenHolds lists a token's holds, newest first
+type TokenHolds struct {
+	TokenID uuid.UUID   `json:"token_id"`
+	Holds   []TokenHold `json:"holds"`
+}
+
+// GetTokenHolds returns the active and historical holds on a token: its freezes, rebuilt from
+// the audit trail, and its multi-sig transfers. A token that was never held has an empty list.
+func (s *TokenService) GetTokenHolds(ctx context.Context, tokenID uuid.UUID) (*TokenHolds, error) {
+	if tokenID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token ID cannot be nil",
+		)
+	}
+
+	token, err := s.GetToken(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditChain, err := s.repo.GetAuditChain(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token audit trail: %w", err)
+	}
+
+	holds := freezeHolds(auditChain)
+
+	// Only the current freeze can have an expiry; lifted freezes clear it
+	if token.Status == models.TokenStatusFrozen && len(holds) > 0 && holds[len(holds)-1].Status == TokenHoldActive {
+		frozenUntil, err := s.repo.GetFrozenUntilWithTx(ctx, nil, tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token freeze expiry: %w", err)
+		}
+		current := &holds[len(holds)-1]
+		current.ExpiresAt = frozenUntil
+		if frozenUntil != nil && !s.clock.Now().Before(*frozenUntil) {
+			current.Status = TokenHoldExpired
+		}
+	}
+
+	transfers, err := s.repo.GetPendingTransfersByToken(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending transfers: %w", err)
+	}
+	for _, transfer := range transfers {
+		holds = append(holds, pendingTransferHold(transfer))
+	}
+
+	sort.SliceStable(holds, func(i, j int) bool {
+		return holds[i].CreatedAt.After(holds[j].CreatedAt)
+	})
+
+	return &TokenHolds{TokenID: tokenID, Holds: holds}, nil
+}
+
+// freezeHolds pairs each change of a token's status to frozen with the change that lifted
+// it, oldest first. auditChain must be in chain order. The reason comes from the FREEZE entry
+// recorded with a single-token freeze, or from a bulk freeze's metadata.
+func freezeHolds(auditChain []repository.TokenAuditEntry) []TokenHold {
+	holds := []TokenHold{}
+	var open *TokenHold
+
+	for _, entry := range auditChain {
+		switch {
+		case entry.Operation == "FREEZE" && open != nil && open.Reason == "":
+			open.Reason = freezeHoldReason(entry.Metadata)
+		case entry.NewStatus == "":
+			continue
+		case entry.NewStatus == models.TokenStatusFrozen && open == nil:
+			holds = append(holds, TokenHold{
+				Type:      TokenHoldFreeze,
+				Status:    TokenHoldActive,
+				Reason:    freezeHoldReason(entry.Metadata),
+				CreatedAt: entry.Timestamp.Time,
+			})
+			open = &holds[len(holds)-1]
+		case entry.NewStatus != models.TokenStatusFrozen && open != nil:
+			releasedAt := entry.Timestamp.Time
+			open.Status = TokenHoldReleased
+			open.ReleasedAt = &releasedAt
+			open = nil
+		}
+	}
+
+	return holds
+}
+
+// freezeHoldReason returns the reason code or free-text reason recorded with a freeze
+func freezeHoldReason(metadata map[string]interface{}) string {
+	for _, key := range []string{"reason_code", "reason"} {
+		if reason, ok := metadata[key].(string); ok && reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// pendingTransferHold reports a multi-sig transfer as a hold reserving the token for its new
+// owner until the transfer completes or is rejected
+func pendingTransferHold(transfer repository.PendingTransfer) TokenHold {
+	holderID := transfer.NewOwner
+	referenceID := transfer.ID
+	hold := TokenHold{
+		Type:        TokenHoldPendingTransfer,
+		Status:      TokenHoldActive,
+		HolderID:    &holderID,
+		ReferenceID: &referenceID,
+		CreatedAt:   transfer.CreatedAt,
+	}
+
+	if transfer.Status != repository.PendingTransferStatusPending {
+		releasedAt := transfer.UpdatedAt
+		hold.Status = TokenHoldReleased
+		hold.Reason = string(transfer.Status)
+		hold.ReleasedAt = &releasedAt
+	}
+
+	return hold
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_GetTokenHolds(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	owner := uuid.New()
+
+	at := func(offset time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(offset), Valid: true}
+	}
+
+	newService := func(token *models.Token, auditChain []repository.TokenAuditEntry, transfers []repository.PendingTransfer) (*TokenService, *MockTokenRepository) {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(token, nil)
+		mockRepo.On("GetAuditChain", mock.Anything, token.TokenID).Return(auditChain, nil)
+		mockRepo.On("GetPendingTransfersByToken", mock.Anything, token.TokenID).Return(transfers, nil)
+
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+		service.SetClock(clock.NewFake(now))
+		return service, mockRepo
+	}
+
+	newToken := func(status models.TokenStatus) *models.Token {
+		return &models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: owner,
+			Status:       status,
+		}
+	}
+
+	t.Run("token without holds returns an empty list", func(t *testing.T) {
+		token := newToken(models.TokenStatusActive)
+		service, _ := newService(token, []repository.TokenAuditEntry{
+			{Operation: "CREATE", NewStatus: models.TokenStatusActive, Timestamp: at(-time.Hour), Sequence: 1},
+		}, nil)
+
+		holds, err := service.GetTokenHolds(context.Background(), token.TokenID)
+		require.NoError(t, err)
+		assert.Equal(t, token.TokenID, holds.TokenID)
+		assert.NotNil(t, holds.Holds)
+		assert.Empty(t, holds.Holds)
+	})
+
+	t.Run("active and historical holds are reported newest first", func(t *testing.T) {
+		token := newToken(models.TokenStatusFrozen)
+		newOwner := uuid.New()
+		completedID, pendingID := uuid.New(), uuid.New()
+
+		auditChain := []repository.TokenAuditEntry{
+			{Operation: "CREATE", NewStatus: models.TokenStatusActive, Timestamp: at(-48 * time.Hour), Sequence: 1},
+			{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusActive, NewStatus: models.TokenStatusFrozen, Timestamp: at(-40 * time.Hour), Sequence: 2},
+			{Operation: "FREEZE", Metadata: map[string]interface{}{"reason_code": "dispute"}, Timestamp: at(-40 * time.Hour), Sequence: 3},
+			{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusFrozen, NewStatus: models.TokenStatusActive, Timestamp: at(-30 * time.Hour), Sequence: 4},
+			{Operation: "BULK_STATUS_UPDATE", NewStatus: models.TokenStatusFrozen, Metadata: map[string]interface{}{"reason": "legal_hold"}, Timestamp: at(-2 * time.Hour), Sequence: 5},
+		}
+		transfers := []repository.PendingTransfer{
+			{ID: pendingID, TokenID: token.TokenID, FromOwner: owner, NewOwner: newOwner, Status: repository.PendingTransferStatusPending, CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour)},
+			{ID: completedID, TokenID: token.TokenID, FromOwner: owner, NewOwner: newOwner, Status: repository.PendingTransferStatusRejected, CreatedAt: now.Add(-20 * time.Hour), UpdatedAt: now.Add(-10 * time.Hour)},
+		}
+
+		service, mockRepo := newService(token, auditChain, transfers)
+		frozenUntil := now.Add(24 * time.Hour)
+		mockRepo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&frozenUntil, nil)
+
+		holds, err := service.GetTokenHolds(context.Background(), token.TokenID)
+		require.NoError(t, err)
+		require.Len(t, holds.Holds, 4)
+
+		pending := holds.Holds[0]
+		assert.Equal(t, TokenHoldPendingTransfer, pending.Type)
+		assert.Equal(t, TokenHoldActive, pending.Status)
+		assert.Equal(t, newOwner, *pending.HolderID)
+		assert.Equal(t, pendingID, *pending.ReferenceID)
+		assert.Nil(t, pending.ReleasedAt)
+
+		freeze := holds.Holds[1]
+		assert.Equal(t, TokenHoldFreeze, freeze.Type)
+		assert.Equal(t, TokenHoldActive, freeze.Status)
+		assert.Equal(t, "legal_hold", freeze.Reason)
+		assert.Equal(t, now.Add(-2*time.Hour), freeze.CreatedAt)
+		assert.Equal(t, frozenUntil, *freeze.ExpiresAt)
+		assert.Nil(t, freeze.HolderID)
+
+		rejected := holds.Holds[2]
+		assert.Equal(t, TokenHoldPendingTransfer, rejected.Type)
+		assert.Equal(t, TokenHoldReleased, rejected.Status)
+		assert.Equal(t, "rejected", rejected.Reason)
+		assert.Equal(t, now.Add(-10*time.Hour), *rejected.ReleasedAt)
+
+		liftedFreeze := holds.Holds[3]
+		assert.Equal(t, TokenHoldFreeze, liftedFreeze.Type)
+		assert.Equal(t, TokenHoldReleased, liftedFreeze.Status)
+		assert.Equal(t, "dispute", liftedFreeze.Reason)
+		assert.Equal(t, now.Add(-30*time.Hour), *liftedFreeze.ReleasedAt)
+		assert.Nil(t, liftedFreeze.ExpiresAt)
+	})
+
+	t.Run("freeze past its expiry but not yet lifted is reported as expired", func(t *testing.T) {
+		token := newToken(models.TokenStatusFrozen)
+		service, mockRepo := newService(token, []repository.TokenAuditEntry{
+			{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusActive, NewStatus: models.TokenStatusFrozen, Timestamp: at(-72 * time.Hour), Sequence: 1},
+		}, nil)
+		frozenUntil := now.Add(-time.Minute)
+		mockRepo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&frozenUntil, nil)
+
+		holds, err := service.GetTokenHolds(context.Background(), token.TokenID)
+		require.NoError(t, err)
+		require.Len(t, holds.Holds, 1)
+		assert.Equal(t, TokenHoldExpired, holds.Holds[0].Status)
+		assert.Equal(t, frozenUntil, *holds.Holds[0].ExpiresAt)
+		assert.Nil(t, holds.Holds[0].ReleasedAt)
+	})
+
+	t.Run("unknown token is not found", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		tokenID := uuid.New()
+		mockRepo.On("GetByID", mock.Anything, tokenID).Return(nil, nil)
+
+		_, err := NewTokenServiceWithDeps(mockRepo, nil).GetTokenHolds(context.Background(), tokenID)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrTokenNotFound, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "GetPendingTransfersByToken", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/token-management/src/models"
+)
+
+// maxSelectionSearchSteps bounds the exact-subset search run when the greedy pass cannot make
+// exact change, so a wallet with many tokens cannot make a selection request expensive
+const maxSelectionSearchSteps = 100000
+
+// TokenSelectionResponse lists the tokens chosen to pay an amount exactly
+type TokenSelectionResponse struct {
+	OwnerID  uuid.UUID       `json:"owner_id"`
+	CBDCType models.CBDCType `json:"cbdc_type"`
+	Amount   float64         `json:"amount"`
+	TokenIDs []uuid.UUID     `json:"token_ids"`
+	Count    int             `json:"count"`
+}
+
+// denominationGroup holds an owner's eligible tokens of one denomination, in minor units
+type denominationGroup struct {
+	value    int64
+	tokenIDs []uuid.UUID
+}
+
+// SelectTokensForAmount chooses active tokens of cbdcType held by ownerID whose denominations
+// sum exactly to amount. The largest denominations are taken first; when that leaves a
+// remainder, a bounded search over denomination counts looks for another exact combination.
+// Frozen tokens are never selected.
+func (s *TokenService) SelectTokensForAmount(ctx context.Context, ownerID uuid.UUID, cbdcType models.CBDCType, amount float64) (*TokenSelectionResponse, error) {
+	if ownerID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"owner ID cannot be nil",
+		)
+	}
+	if !s.SupportsCBDCType(cbdcType) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("invalid CBDC type: %s", cbdcType),
+		)
+	}
+	if amount <= 0 {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"amount must be positive",
+		)
+	}
+	currency := string(cbdcType)
+	if !money.IsExact(amount, currency) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("amount %v is more precise than the smallest unit of %s", amount, cbdcType),
+		)
+	}
+
+	tokens, err := s.GetAllTokensByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, available := groupEligibleTokens(tokens, cbdcType)
+	target := money.ToMinor(amount, currency)
+	if available < target {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrInsufficientFunds,
+			fmt.Sprintf("wallet holds %s in active %s tokens, less than the %s requested", money.Format(money.FromMinor(available, currency), currency), cbdcType, money.Format(amount, currency)),
+		)
+	}
+
+	counts := greedyDenominationCounts(groups, target)
+	if counts == nil {
+		counts = searchDenominationCounts(groups, target, maxSelectionSearchSteps)
+	}
+	if counts == nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			fmt.Sprintf("no combination of active %s tokens sums exactly to %s", cbdcType, money.Format(amount, currency)),
+		)
+	}
+
+	tokenIDs := []uuid.UUID{}
+	for i, count := range counts {
+		tokenIDs = append(tokenIDs, groups[i].tokenIDs[:count]...)
+	}
+
+	return &TokenSelectionResponse{
+		OwnerID:  ownerID,
+		CBDCType: cbdcType,
+		Amount:   amount,
+		TokenIDs: tokenIDs,
+		Count:    len(tokenIDs),
+	}, nil
+}
+
+// groupEligibleTokens groups the active tokens of cbdcType by denomination, largest first, and
+// returns their total value in minor units
+func groupEligibleTokens(tokens []models.Token, cbdcType models.CBDCType) ([]denominationGroup, int64) {
+	index := make(map[int64]int)
+	var groups []denominationGroup
+	var total int64
+
+	for _, token := range tokens {
+		if token.Status != models.TokenStatusActive || token.CBDCType != cbdcType {
+			continue
+		}
+		value := money.ToMinor(token.Denomination, string(cbdcType))
+		if value <= 0 {
+			continue
+		}
+
+		i, ok := index[value]
+		if !ok {
+			i = len(groups)
+			index[value] = i
+			groups = append(groups, denominationGroup{value: value})
+		}
+		groups[i].tokenIDs = append(groups[i].tokenIDs, token.TokenID)
+		total += value
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].value > groups[j].value
+	})
+	return groups, total
+}
+
+// greedyDenominationCounts takes as many of each denomination as fit, largest first. It
+// returns the number of tokens taken per group, or nil if the greedy choice is not exact.
+func greedyDenominationCounts(groups []denominationGroup, target int64) []int {
+	counts := make([]int, len(groups))
+	remaining := target
+
+	for i, group := range groups {
+		count := int(remaining / group.value)
+		if count > len(group.tokenIDs) {
+			count = len(group.tokenIDs)
+		}
+		counts[i] = count
+		remaining -= int64(count) * group.value
+	}
+
+	if remaining != 0 {
+		return nil
+	}
+	return counts
+}
+
+// searchDenominationCounts looks for any exact combination of denomination counts, trying
+// larger counts of larger denominations first. It gives up after maxSteps choices and
+// returns nil if no exact combination was found.
+func searchDenominationCounts(groups []denominationGroup, target int64, maxSteps int) []int {
+	// remainingValue[i] is the total value of groups i and later, for pruning
+	remainingValue := make([]int64, len(groups)+1)
+	for i := len(groups) - 1; i >= 0; i-- {
+		remainingValue[i] = remainingValue[i+1] + groups[i].total()
+	}
+
+	counts := make([]int, len(groups))
+	steps := 0
+
+	var search func(i int, remaining int64) bool
+	search = func(i int, remaining int64) bool {
+		if remaining == 0 {
+			for j := i; j < len(counts); j++ {
+				counts[j] = 0
+			}
+			return true
+		}
+		if i == len(groups) || remainingValue[i] < remaining || steps >= maxSteps {
+			return false
+		}
+
+		group := groups[i]
+		most := int(remaining / group.value)
+		if most > len(group.tokenIDs) {
+			most = len(group.tokenIDs)
+		}
+		for count := most; count >= 0; count-- {
+			steps++
+			counts[i] = count
+			if search(i+1, remaining-int64(count)*group.value) {
+				return true
+			}
+			if steps >= maxSteps {
+				return false
+			}
+		}
+		return false
+	}
+
+	if !search(0, target) {
+		return nil
+	}
+	return counts
+}
+
+// total returns the combined value of the group's tokens in minor units
+func (g denominationGroup) total() int64 {
+	return g.value * int64(len(g.tokenIDs))
+}
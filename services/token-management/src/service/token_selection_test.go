@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_SelectTokensForAmount(t *testing.T) {
+	owner := uuid.New()
+	newToken := func(denomination float64, status models.TokenStatus) models.Token {
+		return models.Token{
+			TokenID:      uuid.New(),
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: denomination,
+			CurrentOwner: owner,
+			Status:       status,
+		}
+	}
+	newService := func(tokens []models.Token) *TokenService {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, 0).Return(tokens, nil)
+		return NewTokenServiceWithDeps(mockRepo, nil)
+	}
+
+	t.Run("greedy exact match", func(t *testing.T) {
+		hundred := newToken(100, models.TokenStatusActive)
+		fifty := newToken(50, models.TokenStatusActive)
+		ten := newToken(10, models.TokenStatusActive)
+		service := newService([]models.Token{ten, hundred, newToken(10, models.TokenStatusActive), fifty})
+
+		selection, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 160)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{hundred.TokenID, fifty.TokenID, ten.TokenID}, selection.TokenIDs)
+		assert.Equal(t, 3, selection.Count)
+	})
+
+	t.Run("exact match the greedy pass misses", func(t *testing.T) {
+		first := newToken(20, models.TokenStatusActive)
+		second := newToken(20, models.TokenStatusActive)
+		third := newToken(20, models.TokenStatusActive)
+		service := newService([]models.Token{newToken(50, models.TokenStatusActive), first, second, third})
+
+		selection, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 60)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first.TokenID, second.TokenID, third.TokenID}, selection.TokenIDs)
+	})
+
+	t.Run("no exact combination", func(t *testing.T) {
+		service := newService([]models.Token{newToken(50, models.TokenStatusActive), newToken(20, models.TokenStatusActive)})
+
+		_, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 30)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+	})
+
+	t.Run("amount exceeds active holdings", func(t *testing.T) {
+		service := newService([]models.Token{newToken(100, models.TokenStatusActive), newToken(50, models.TokenStatusActive)})
+
+		_, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 1000)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrInsufficientFunds, tokenErr.Code)
+	})
+
+	t.Run("frozen tokens are not selected", func(t *testing.T) {
+		active := newToken(50, models.TokenStatusActive)
+		service := newService([]models.Token{newToken(100, models.TokenStatusFrozen), active})
+
+		_, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 100)
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrInsufficientFunds, tokenErr.Code)
+
+		selection, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, 50)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{active.TokenID}, selection.TokenIDs)
+	})
+
+	t.Run("invalid amounts are rejected", func(t *testing.T) {
+		service := newService(nil)
+
+		for _, amount := range []float64{0, -10, 10.001} {
+			_, err := service.SelectTokensForAmount(context.Background(), owner, models.CBDCTypeUSD, amount)
+
+			tokenErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		}
+	})
+}
@@ -2,24 +2,54 @@ package service
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/shared/libraries/webhooks"
+	"echopay/token-management/src/merkle"
+	"echopay/token-management/src/events"
 	"echopay/token-management/src/models"
 	"echopay/token-management/src/repository"
 )
 
 // TokenService handles token lifecycle management
 type TokenService struct {
-	repo   repository.TokenRepository
-	db     TransactionManager
+	repo          repository.TokenRepository
+	db            TransactionManager
+	keySource     SigningKeySource
+	keyRegistry   *KeyRegistry
+	screener      SanctionsScreener
+	webhooks      *webhooks.Dispatcher
+	statusTracker *events.TokenStatusTracker
+	currencies    *currency.Registry
+	denominations *DenominationRules
+	transactions  TransactionLookup
+	replayGuard   *ReplayGuard
+	historyLimit  int
+	clock         clock.Clock
+
+	auditBatchLimit      int
+	allowFreeTextReasons bool
+	velocityLimit        int
+	velocityWindow       time.Duration
+	finalityWindow       time.Duration
 }
 
+// DefaultTransactionHistoryLimit is how many of a token's most recent transaction IDs are kept
+// in the token row; older entries are moved to the history archive
+const DefaultTransactionHistoryLimit = 100
+
 // TransactionManager interface for database transactions
 type TransactionManager interface {
 	Transaction(fn func(*sql.Tx) error) error
@@ -28,17 +58,143 @@ type TransactionManager interface {
 // NewTokenService creates a new token service instance
 func NewTokenService(db *database.PostgresDB) *TokenService {
 	return &TokenService{
-		repo: repository.NewTokenRepository(db),
-		db:   db,
+		repo:          repository.NewTokenRepository(db),
+		db:            db,
+		screener:      AllowAllScreener{},
+		currencies:    currency.NewDefaultRegistry(),
+		denominations: DefaultDenominationRules(),
+		replayGuard:   NewReplayGuard(config.ReplayProtectionConfig{MaxClockSkew: DefaultMaxClockSkew}),
+		historyLimit:  DefaultTransactionHistoryLimit,
+		clock:         clock.Real(),
+
+		auditBatchLimit: DefaultAuditBatchLimit,
 	}
 }
 
 // NewTokenServiceWithDeps creates a new token service with injected dependencies (for testing)
 func NewTokenServiceWithDeps(repo repository.TokenRepository, db TransactionManager) *TokenService {
 	return &TokenService{
-		repo: repo,
-		db:   db,
+		repo:          repo,
+		db:            db,
+		screener:      AllowAllScreener{},
+		currencies:    currency.NewDefaultRegistry(),
+		denominations: DefaultDenominationRules(),
+		replayGuard:   NewReplayGuard(config.ReplayProtectionConfig{MaxClockSkew: DefaultMaxClockSkew}),
+		historyLimit:  DefaultTransactionHistoryLimit,
+		clock:         clock.Real(),
+
+		auditBatchLimit: DefaultAuditBatchLimit,
+	}
+}
+
+// SetSigningKeySource enables issuance signatures using keys from the given source
+func (s *TokenService) SetSigningKeySource(source SigningKeySource) {
+	s.keySource = source
+}
+
+// SetSanctionsScreener sets the screener consulted before ownership transfers. A positive
+// cacheTTL caches screening results per owner for that duration.
+func (s *TokenService) SetSanctionsScreener(screener SanctionsScreener, cacheTTL time.Duration) {
+	if cacheTTL > 0 {
+		screener = NewCachingScreener(screener, cacheTTL)
+	}
+	s.screener = screener
+}
+
+// SetWebhookDispatcher enables webhook notifications for freeze and unfreeze operations
+func (s *TokenService) SetWebhookDispatcher(dispatcher *webhooks.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// SetCurrencyRegistry sets the CBDC types accepted for issuance and quotas
+func (s *TokenService) SetCurrencyRegistry(registry *currency.Registry) {
+	s.currencies = registry
+}
+
+// SetDenominationRules sets the per-CBDC-type denomination rules enforced at issuance
+func (s *TokenService) SetDenominationRules(rules *DenominationRules) {
+	s.denominations = rules
+}
+
+// SetTransactionHistoryLimit sets how many recent transaction IDs are kept in a token row.
+// A limit of zero or less keeps the full history in the row.
+func (s *TokenService) SetTransactionHistoryLimit(limit int) {
+	s.historyLimit = limit
+}
+
+// SetReplayGuard sets the nonce and timestamp checks applied to transfer requests
+func (s *TokenService) SetReplayGuard(guard *ReplayGuard) {
+	s.replayGuard = guard
+}
+
+// SetClock sets the clock used for issuance, transfer and freeze timestamps
+func (s *TokenService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SupportsCBDCType reports whether a CBDC type is registered as supported
+func (s *TokenService) SupportsCBDCType(cbdcType models.CBDCType) bool {
+	return s.currencies.Supported(string(cbdcType))
+}
+
+// SupportedCBDCTypes returns the supported CBDC types
+func (s *TokenService) SupportedCBDCTypes() []string {
+	return s.currencies.Codes()
+}
+
+// SetAllowFreeTextReasons controls whether freeze, unfreeze and bulk status reasons that are
+// not known reason codes are accepted as legacy free text
+func (s *TokenService) SetAllowFreeTextReasons(allow bool) {
+	s.allowFreeTextReasons = allow
+}
+
+// SetStrictChecksums controls whether reading a token whose content does not match its stored
+// checksum fails instead of only being logged
+func (s *TokenService) SetStrictChecksums(strict bool) {
+	s.repo.SetStrictChecksums(strict)
+}
+
+// SetBulkUpdateOptions sets the batch size and audit worker count for bulk status updates
+func (s *TokenService) SetBulkUpdateOptions(batchSize, auditWorkers int) {
+	s.repo.SetBulkUpdateOptions(batchSize, auditWorkers)
+}
+
+// transactionWithRetry runs fn in a database transaction and, if a token update inside it
+// lost an optimistic concurrency race, runs it once more against freshly read state
+func (s *TokenService) transactionWithRetry(fn func(*sql.Tx) error) error {
+	err := s.db.Transaction(fn)
+	if isConcurrentModification(err) {
+		err = s.db.Transaction(fn)
 	}
+	return err
+}
+
+// isConcurrentModification reports whether err is a stale-version write rejection
+func isConcurrentModification(err error) bool {
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	return ok && echoPayErr.Code == errors.ErrConcurrentModification
+}
+
+// updateTokenWithTx persists a token, passing version conflicts through unwrapped so
+// callers can retry them. Transaction history beyond the limit is archived in the same
+// database transaction, so a rejected update also discards the archived entries.
+func (s *TokenService) updateTokenWithTx(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	if s.historyLimit > 0 && len(token.TransactionHistory) > s.historyLimit {
+		overflow := len(token.TransactionHistory) - s.historyLimit
+		archived := append([]uuid.UUID(nil), token.TransactionHistory[:overflow]...)
+		if err := s.repo.ArchiveTransactionHistoryWithTx(ctx, tx, token.TokenID, archived); err != nil {
+			return fmt.Errorf("failed to archive token history: %w", err)
+		}
+		token.TransactionHistory = append(token.TransactionHistory[:0:0], token.TransactionHistory[overflow:]...)
+	}
+
+	if err := s.repo.UpdateWithTx(ctx, tx, token); err != nil {
+		if isConcurrentModification(err) {
+			return err
+		}
+		return fmt.Errorf("failed to update token: %w", err)
+	}
+	return nil
 }
 
 // IssueTokenRequest represents a token issuance request
@@ -63,57 +219,66 @@ type TransferTokenRequest struct {
 	TokenID       uuid.UUID `json:"token_id" binding:"required"`
 	NewOwner      uuid.UUID `json:"new_owner" binding:"required"`
 	TransactionID uuid.UUID `json:"transaction_id" binding:"required"`
+	Nonce         string    `json:"nonce,omitempty"`     // Single-use per owner; replays are rejected
+	Timestamp     time.Time `json:"timestamp,omitempty"` // When the request was made; required with a nonce
 }
 
-// TransferTokenResponse represents the response from token transfer
+// TransferTokenResponse represents the response from token transfer. When the source wallet
+// requires co-signers, the transfer is not completed and PendingTransferID is set instead.
 type TransferTokenResponse struct {
-	Token         models.Token `json:"token"`
-	PreviousOwner uuid.UUID    `json:"previous_owner"`
-	TransferredAt time.Time    `json:"transferred_at"`
+	Token             models.Token `json:"token"`
+	PreviousOwner     uuid.UUID    `json:"previous_owner"`
+	TransferredAt     time.Time    `json:"transferred_at"`
+	PendingTransferID *uuid.UUID   `json:"pending_transfer_id,omitempty"`
+	RequiredSigners   int          `json:"required_signers,omitempty"`
 }
 
 // IssueTokens creates new tokens and stores them in the distributed ledger
 func (s *TokenService) IssueTokens(ctx context.Context, req IssueTokenRequest) (*IssueTokenResponse, error) {
+	req = normalizeIssueRequest(req)
+
 	// Validate request first (before database operations)
 	if err := s.validateIssueRequest(req); err != nil {
 		return nil, err
 	}
 
 	var tokens []models.Token
-	issuedAt := time.Now()
+	issuedAt := s.clock.Now()
 
 	// Use transaction to ensure atomicity
 	err := s.db.Transaction(func(tx *sql.Tx) error {
-		for i := 0; i < req.Quantity; i++ {
-			// Create new token
-			token, err := models.NewToken(
-				req.CBDCType,
-				req.Denomination,
-				req.Owner,
-				req.Issuer,
-				req.Series,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create token %d: %w", i+1, err)
-			}
+		amount := float64(req.Quantity) * req.Denomination
+		if err := s.reserveIssuanceQuota(ctx, tx, req.Issuer, req.Series, req.CBDCType, amount); err != nil {
+			return err
+		}
 
-			// Store token in repository
-			if err := s.repo.CreateWithTx(ctx, tx, token); err != nil {
-				return fmt.Errorf("failed to store token %d: %w", i+1, err)
-			}
+		minted, err := s.mintTokensWithTx(ctx, tx, req, 0)
+		if err != nil {
+			return err
+		}
+		tokens = minted
 
-			tokens = append(tokens, *token)
+		// Commit the batch to a Merkle root so each token can later prove its issuance
+		if err := s.storeIssuanceProofs(ctx, tx, tokens); err != nil {
+			return fmt.Errorf("failed to store issuance proofs: %w", err)
 		}
 		return nil
 	})
 
 	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
 		return nil, errors.NewTokenManagementError(
 			errors.ErrTransactionFailed,
 			fmt.Sprintf("failed to issue tokens: %v", err),
 		)
 	}
 
+	logBulkTokenTransition(ctx, "ISSUE", models.TokenStatusActive, len(tokens), "owner", req.Owner.String(), "issuer", req.Issuer, "series", req.Series)
+
 	return &IssueTokenResponse{
 		Tokens:   tokens,
 		Count:    len(tokens),
@@ -121,6 +286,166 @@ func (s *TokenService) IssueTokens(ctx context.Context, req IssueTokenRequest) (
 	}, nil
 }
 
+// normalizeIssueRequest sanitizes an issuance's metadata and rounds its denomination to the
+// CBDC's minor unit, e.g. whole units for a zero-decimal currency, as it will be stored
+func normalizeIssueRequest(req IssueTokenRequest) IssueTokenRequest {
+	req.Issuer = sanitizeMetadataText(req.Issuer)
+	req.Series = sanitizeMetadataText(req.Series)
+	req.Denomination = money.Round(req.Denomination, string(req.CBDCType))
+	return req
+}
+
+// BatchIssueLine is one denomination within a batch issuance
+type BatchIssueLine struct {
+	Denomination float64 `json:"denomination" binding:"required,gt=0"`
+	Quantity     int     `json:"quantity" binding:"required,gt=0,lte=1000"`
+}
+
+// BatchIssueRequest represents an issuance of mixed denominations sharing one CBDC type,
+// owner, issuer and series
+type BatchIssueRequest struct {
+	CBDCType models.CBDCType  `json:"cbdc_type" binding:"required"`
+	Owner    uuid.UUID        `json:"owner" binding:"required"`
+	Issuer   string           `json:"issuer" binding:"required"`
+	Series   string           `json:"series" binding:"required"`
+	Lines    []BatchIssueLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// DenominationCount reports how many tokens of a denomination were issued
+type DenominationCount struct {
+	Denomination float64 `json:"denomination"`
+	Count        int     `json:"count"`
+}
+
+// BatchIssueResponse represents the response from a batch issuance
+type BatchIssueResponse struct {
+	Tokens             []models.Token      `json:"tokens"`
+	Count              int                 `json:"count"`
+	DenominationCounts []DenominationCount `json:"denomination_counts"`
+	IssuedAt           time.Time           `json:"issued_at"`
+}
+
+// MaxBatchIssueTokens caps the total number of tokens minted by one batch issuance
+const MaxBatchIssueTokens = 1000
+
+// IssueBatch mints tokens of several denominations in a single transaction. Every line is
+// validated like a single issuance and the whole batch shares one issuance Merkle root.
+func (s *TokenService) IssueBatch(ctx context.Context, req BatchIssueRequest) (*BatchIssueResponse, error) {
+	req.Issuer = sanitizeMetadataText(req.Issuer)
+	req.Series = sanitizeMetadataText(req.Series)
+
+	lineRequests, err := s.validateBatchIssueRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []models.Token
+	issuedAt := s.clock.Now()
+
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		var amount float64
+		for _, line := range lineRequests {
+			amount += float64(line.Quantity) * line.Denomination
+		}
+		if err := s.reserveIssuanceQuota(ctx, tx, req.Issuer, req.Series, req.CBDCType, amount); err != nil {
+			return err
+		}
+
+		for _, line := range lineRequests {
+			minted, err := s.mintTokensWithTx(ctx, tx, line, len(tokens))
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, minted...)
+		}
+
+		if err := s.storeIssuanceProofs(ctx, tx, tokens); err != nil {
+			return fmt.Errorf("failed to store issuance proofs: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to issue token batch: %v", err),
+		)
+	}
+
+	// Lines may repeat a denomination, so counts are aggregated in first-seen order
+	var counts []DenominationCount
+	index := make(map[float64]int)
+	for _, line := range lineRequests {
+		i, ok := index[line.Denomination]
+		if !ok {
+			i = len(counts)
+			index[line.Denomination] = i
+			counts = append(counts, DenominationCount{Denomination: line.Denomination})
+		}
+		counts[i].Count += line.Quantity
+	}
+
+	logBulkTokenTransition(ctx, "ISSUE", models.TokenStatusActive, len(tokens), "owner", req.Owner.String(), "issuer", req.Issuer, "series", req.Series)
+
+	return &BatchIssueResponse{
+		Tokens:             tokens,
+		Count:              len(tokens),
+		DenominationCounts: counts,
+		IssuedAt:           issuedAt,
+	}, nil
+}
+
+// mintTokensWithTx creates, stores and optionally signs req.Quantity tokens. Offset is the
+// number of tokens already minted in the same transaction and only affects error messages.
+func (s *TokenService) mintTokensWithTx(ctx context.Context, tx *sql.Tx, req IssueTokenRequest, offset int) ([]models.Token, error) {
+	tokens := make([]models.Token, 0, req.Quantity)
+	for i := offset; i < offset+req.Quantity; i++ {
+		token, err := newIssuedToken(req, i)
+		if err != nil {
+			return nil, err
+		}
+
+		// Store token in repository
+		if err := s.repo.CreateWithTx(ctx, tx, token); err != nil {
+			// A colliding token ID keeps its typed error so it is reported as a conflict
+			if echoPayErr, ok := err.(*errors.EchoPayError); ok && echoPayErr.Code == errors.ErrDuplicateToken {
+				return nil, echoPayErr
+			}
+			return nil, fmt.Errorf("failed to store token %d: %w", i+1, err)
+		}
+
+		if s.keySource != nil {
+			if err := s.signToken(ctx, tx, token); err != nil {
+				return nil, fmt.Errorf("failed to sign token %d: %w", i+1, err)
+			}
+		}
+
+		tokens = append(tokens, *token)
+	}
+
+	return tokens, nil
+}
+
+// newIssuedToken creates the i-th token of an issuance, counting from zero
+func newIssuedToken(req IssueTokenRequest, i int) (*models.Token, error) {
+	token, err := models.NewToken(
+		req.CBDCType,
+		req.Denomination,
+		req.Owner,
+		req.Issuer,
+		req.Series,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token %d: %w", i+1, err)
+	}
+	return token, nil
+}
+
 // TransferToken transfers ownership of a token to a new owner
 func (s *TokenService) TransferToken(ctx context.Context, req TransferTokenRequest) (*TransferTokenResponse, error) {
 	// Validate request
@@ -130,10 +455,16 @@ func (s *TokenService) TransferToken(ctx context.Context, req TransferTokenReque
 
 	var transferredToken models.Token
 	var previousOwner uuid.UUID
-	transferredAt := time.Now()
+	var pendingTransfer *repository.PendingTransfer
+	var blockedErr error
+	transferredAt := s.clock.Now()
+
+	if err := s.replayGuard.Check(req.Nonce, req.Timestamp, transferredAt); err != nil {
+		return nil, err
+	}
 
 	// Use transaction to ensure atomicity
-	err := s.db.Transaction(func(tx *sql.Tx) error {
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
 		// Get current token
 		token, err := s.repo.GetByIDWithTx(ctx, tx, req.TokenID)
 		if err != nil {
@@ -152,53 +483,521 @@ func (s *TokenService) TransferToken(ctx context.Context, req TransferTokenReque
 
 		// Verify ownership transfer is valid
 		if err := s.validateOwnershipTransfer(token, req.NewOwner); err != nil {
+			// Sanctions blocks are committed to the audit trail before being returned
+			if block, ok := sanctionsBlockFromError(err, token.TokenID, token.CurrentOwner, req.NewOwner); ok {
+				if err := s.repo.RecordSanctionsBlockWithTx(ctx, tx, block); err != nil {
+					return err
+				}
+				blockedErr = err
+				return nil
+			}
+			return err
+		}
+
+		if err := s.checkTransferVelocity(ctx, tx, token.TokenID, transferredAt); err != nil {
+			return err
+		}
+
+		// The nonce is recorded with the transfer, so it is released if the transfer fails
+		if err := s.recordTransferNonce(ctx, tx, token.CurrentOwner, req, transferredAt); err != nil {
+			return err
+		}
+
+		// Multi-sig wallets hold the transfer until enough co-signers approve
+		requiredSigners, err := s.repo.GetRequiredSignersWithTx(ctx, tx, token.CurrentOwner)
+		if err != nil {
+			return fmt.Errorf("failed to get wallet signing policy: %w", err)
+		}
+
+		if requiredSigners > 0 {
+			pending := &repository.PendingTransfer{
+				ID:              uuid.New(),
+				TokenID:         token.TokenID,
+				FromOwner:       token.CurrentOwner,
+				NewOwner:        req.NewOwner,
+				TransactionID:   req.TransactionID,
+				RequiredSigners: requiredSigners,
+				Status:          repository.PendingTransferStatusPending,
+				CreatedAt:       transferredAt,
+				UpdatedAt:       transferredAt,
+			}
+
+			if err := s.repo.CreatePendingTransferWithTx(ctx, tx, pending); err != nil {
+				return fmt.Errorf("failed to create pending transfer: %w", err)
+			}
+
+			pendingTransfer = pending
+			transferredToken = *token
+			return nil
+		}
+
+		// Transfer ownership
+		if err := token.TransferOwnership(req.NewOwner, req.TransactionID); err != nil {
+			return err // Preserve the original error from the model
+		}
+
+		// Update token in repository
+		if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
 			return err
 		}
 
-		// Transfer ownership
-		if err := token.TransferOwnership(req.NewOwner, req.TransactionID); err != nil {
-			return err // Preserve the original error from the model
-		}
+		transferredToken = *token
+		return nil
+	})
+
+	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+		
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTokenTransferFailed,
+			fmt.Sprintf("failed to transfer token: %v", err),
+		)
+	}
+
+	if blockedErr != nil {
+		return nil, blockedErr
+	}
+
+	if pendingTransfer != nil {
+		logTokenTransition(ctx, req.TokenID, "TRANSFER_PENDING", transferredToken.Status,
+			"pending_transfer_id", pendingTransfer.ID.String(),
+			"transaction_id", req.TransactionID.String(),
+		)
+		return &TransferTokenResponse{
+			Token:             transferredToken,
+			PreviousOwner:     previousOwner,
+			PendingTransferID: &pendingTransfer.ID,
+			RequiredSigners:   pendingTransfer.RequiredSigners,
+		}, nil
+	}
+
+	logTokenTransition(ctx, req.TokenID, "OWNERSHIP_TRANSFER", transferredToken.Status,
+		"old_owner", previousOwner.String(),
+		"new_owner", req.NewOwner.String(),
+		"transaction_id", req.TransactionID.String(),
+	)
+	s.publishTokenEvent(events.TokenEventTransferred, req.TokenID, req.NewOwner, &previousOwner, transferredToken.Status, "Token transferred")
+
+	return &TransferTokenResponse{
+		Token:         transferredToken,
+		PreviousOwner: previousOwner,
+		TransferredAt: transferredAt,
+	}, nil
+}
+
+// recordTransferNonce uses up a transfer request's nonce for owner, rejecting the request if
+// the nonce was already used. Requests without a nonce are not recorded.
+func (s *TokenService) recordTransferNonce(ctx context.Context, tx *sql.Tx, owner uuid.UUID, req TransferTokenRequest, now time.Time) error {
+	if req.Nonce == "" {
+		return nil
+	}
+
+	recorded, err := s.repo.RecordTransferNonceWithTx(ctx, tx, owner, req.Nonce, now, s.replayGuard.NonceExpiry(req.Timestamp))
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return errors.NewTokenManagementError(
+			errors.ErrReplayDetected,
+			"transfer nonce has already been used",
+		)
+	}
+	return nil
+}
+
+// ApproveTransferResponse represents the response from approving a pending transfer
+type ApproveTransferResponse struct {
+	PendingTransfer repository.PendingTransfer `json:"pending_transfer"`
+	Completed       bool                       `json:"completed"`
+	Token           *models.Token              `json:"token,omitempty"`
+}
+
+// ApproveTransfer records a co-signer's approval of a pending transfer and completes the
+// transfer once the required number of distinct approvals is reached. Token state is
+// re-checked at completion time.
+func (s *TokenService) ApproveTransfer(ctx context.Context, pendingID, signerID uuid.UUID) (*ApproveTransferResponse, error) {
+	if pendingID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"pending transfer ID cannot be nil",
+		)
+	}
+
+	if signerID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"signer ID cannot be nil",
+		)
+	}
+
+	var response ApproveTransferResponse
+	var blockedErr error
+
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
+		response = ApproveTransferResponse{}
+
+		pending, err := s.repo.GetPendingTransferWithTx(ctx, tx, pendingID)
+		if err != nil {
+			return fmt.Errorf("failed to get pending transfer: %w", err)
+		}
+
+		if pending == nil {
+			return errors.NewTokenManagementError(
+				errors.ErrTokenNotFound,
+				"pending transfer not found",
+			)
+		}
+
+		if pending.Status != repository.PendingTransferStatusPending {
+			return errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				fmt.Sprintf("pending transfer is already %s", pending.Status),
+			)
+		}
+
+		for _, approver := range pending.Approvals {
+			if approver == signerID {
+				return errors.NewTokenManagementError(
+					errors.ErrInvalidTokenState,
+					"signer has already approved this transfer",
+				)
+			}
+		}
+
+		// Only the wallet's authorized co-signers count toward the threshold, and the
+		// sender cannot approve their own transfer
+		if signerID == pending.FromOwner {
+			return errors.NewTokenManagementError(
+				errors.ErrAuthorizationFailed,
+				"the sending wallet cannot co-sign its own transfer",
+			)
+		}
+		authorized, err := s.repo.IsCoSignerWithTx(ctx, tx, pending.FromOwner, signerID)
+		if err != nil {
+			return err
+		}
+		if !authorized {
+			return errors.NewTokenManagementError(
+				errors.ErrAuthorizationFailed,
+				fmt.Sprintf("signer %s is not an authorized co-signer for wallet %s", signerID, pending.FromOwner),
+			)
+		}
+
+		if err := s.repo.AddTransferApprovalWithTx(ctx, tx, pendingID, signerID); err != nil {
+			return fmt.Errorf("failed to record approval: %w", err)
+		}
+		pending.Approvals = append(pending.Approvals, signerID)
+
+		if len(pending.Approvals) >= pending.RequiredSigners {
+			token, err := s.completePendingTransfer(ctx, tx, pending)
+			if err != nil {
+				// A sanctions hit rejects the pending transfer rather than leaving it open
+				if block, ok := sanctionsBlockFromError(err, pending.TokenID, pending.FromOwner, pending.NewOwner); ok {
+					if err := s.repo.RecordSanctionsBlockWithTx(ctx, tx, block); err != nil {
+						return err
+					}
+					if err := s.repo.UpdatePendingTransferStatusWithTx(ctx, tx, pending.ID, repository.PendingTransferStatusRejected); err != nil {
+						return fmt.Errorf("failed to reject pending transfer: %w", err)
+					}
+					blockedErr = err
+					return nil
+				}
+				return err
+			}
+			pending.Status = repository.PendingTransferStatusCompleted
+			response.Completed = true
+			response.Token = token
+		}
+
+		response.PendingTransfer = *pending
+		return nil
+	})
+
+	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTokenTransferFailed,
+			fmt.Sprintf("failed to approve transfer: %v", err),
+		)
+	}
+
+	if blockedErr != nil {
+		return nil, blockedErr
+	}
+
+	if response.Completed {
+		logTokenTransition(ctx, response.Token.TokenID, "OWNERSHIP_TRANSFER", response.Token.Status,
+			"old_owner", response.PendingTransfer.FromOwner.String(),
+			"new_owner", response.PendingTransfer.NewOwner.String(),
+			"pending_transfer_id", pendingID.String(),
+		)
+		previousOwner := response.PendingTransfer.FromOwner
+		s.publishTokenEvent(events.TokenEventTransferred, response.Token.TokenID, response.Token.CurrentOwner, &previousOwner, response.Token.Status, "Token transferred after co-signer approval")
+	}
+
+	return &response, nil
+}
+
+// SetWalletRequiredSigners configures how many co-signer approvals a wallet's outgoing
+// transfers require and which co-signers may give them; zero disables multi-sig for the
+// wallet. There must be at least as many co-signers as required approvals, and the wallet
+// cannot co-sign for itself.
+func (s *TokenService) SetWalletRequiredSigners(ctx context.Context, walletID uuid.UUID, requiredSigners int, coSigners []uuid.UUID) error {
+	if walletID == uuid.Nil {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"wallet ID cannot be nil",
+		)
+	}
+
+	if requiredSigners < 0 || requiredSigners > 10 {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"required signers must be between 0 and 10",
+		)
+	}
+
+	if requiredSigners == 0 && len(coSigners) > 0 {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"co-signers require a signing policy with at least one required signer",
+		)
+	}
+
+	if len(coSigners) < requiredSigners {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("%d required signers need at least as many co-signers, got %d", requiredSigners, len(coSigners)),
+		)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(coSigners))
+	for _, signerID := range coSigners {
+		if signerID == uuid.Nil || signerID == walletID {
+			return errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"co-signers must be valid IDs other than the wallet itself",
+			)
+		}
+		if seen[signerID] {
+			return errors.NewTokenManagementError(
+				errors.ErrValidation,
+				fmt.Sprintf("duplicate co-signer %s", signerID),
+			)
+		}
+		seen[signerID] = true
+	}
+
+	if err := s.repo.SetRequiredSigners(ctx, walletID, requiredSigners, coSigners); err != nil {
+		return fmt.Errorf("failed to set wallet signing policy: %w", err)
+	}
+
+	return nil
+}
+
+// IssuerQuotaStatus reports an issuance quota along with the value still available to mint
+type IssuerQuotaStatus struct {
+	repository.IssuerQuota
+	Remaining float64 `json:"remaining"`
+}
+
+// GetIssuerQuotas returns the issuance quotas configured for an issuer
+func (s *TokenService) GetIssuerQuotas(ctx context.Context, issuer string) ([]IssuerQuotaStatus, error) {
+	if issuer == "" {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"issuer is required",
+		)
+	}
+
+	quotas, err := s.repo.GetIssuerQuotas(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issuer quotas: %w", err)
+	}
+
+	statuses := make([]IssuerQuotaStatus, len(quotas))
+	for i, quota := range quotas {
+		statuses[i] = newIssuerQuotaStatus(quota)
+	}
+
+	return statuses, nil
+}
+
+// SetIssuerQuota sets the maximum value an issuer may mint for a series and CBDC type.
+// Lowering a quota below the amount already minted blocks further issuance but does not
+// affect existing tokens.
+func (s *TokenService) SetIssuerQuota(ctx context.Context, issuer, series string, cbdcType models.CBDCType, quota float64) (*IssuerQuotaStatus, error) {
+	if issuer == "" || series == "" {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"issuer and series are required",
+		)
+	}
+
+	if !s.SupportsCBDCType(cbdcType) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("invalid CBDC type: %s", cbdcType),
+		)
+	}
+
+	if quota < 0 {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"quota cannot be negative",
+		)
+	}
+
+	updated, err := s.repo.SetIssuerQuota(ctx, issuer, series, cbdcType, quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set issuer quota: %w", err)
+	}
+
+	status := newIssuerQuotaStatus(*updated)
+	return &status, nil
+}
+
+// reserveIssuanceQuota checks that minting amount stays within the issuer's quota and adds it to
+// the running mint total. The quota row stays locked until tx ends so concurrent issuances cannot
+// both pass the check. Issuance is unrestricted when no quota is configured.
+func (s *TokenService) reserveIssuanceQuota(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType, amount float64) error {
+	quota, err := s.repo.GetIssuerQuotaForUpdateWithTx(ctx, tx, issuer, series, cbdcType)
+	if err != nil {
+		return fmt.Errorf("failed to get issuer quota: %w", err)
+	}
+	if quota == nil {
+		return nil
+	}
+
+	if err := checkIssuanceQuota(*quota, issuer, series, cbdcType, amount); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddMintedTotalWithTx(ctx, tx, issuer, series, cbdcType, amount); err != nil {
+		return fmt.Errorf("failed to update minted total: %w", err)
+	}
+
+	return nil
+}
+
+// checkIssuanceQuota returns a quota exceeded error if minting amount for an issuer's series
+// would take the quota's mint total past it
+func checkIssuanceQuota(quota repository.IssuerQuota, issuer, series string, cbdcType models.CBDCType, amount float64) error {
+	// Compare in minor units so float rounding cannot let issuance slip past the quota
+	currency := string(cbdcType)
+	if money.ToMinor(quota.MintedTotal, currency)+money.ToMinor(amount, currency) > money.ToMinor(quota.Quota, currency) {
+		return errors.NewTokenManagementError(
+			errors.ErrQuotaExceeded,
+			fmt.Sprintf("issuance of %s exceeds remaining quota for %s series %s", money.Format(amount, currency), issuer, series),
+		).WithDetails(map[string]interface{}{
+			"issuer":       issuer,
+			"series":       series,
+			"cbdc_type":    cbdcType,
+			"requested":    amount,
+			"minted_total": quota.MintedTotal,
+			"quota":        quota.Quota,
+		})
+	}
+
+	return nil
+}
+
+// newIssuerQuotaStatus computes the remaining mintable value of a quota, never below zero
+func newIssuerQuotaStatus(quota repository.IssuerQuota) IssuerQuotaStatus {
+	currency := string(quota.CBDCType)
+	remaining := money.FromMinor(money.ToMinor(quota.Quota, currency)-money.ToMinor(quota.MintedTotal, currency), currency)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return IssuerQuotaStatus{IssuerQuota: quota, Remaining: remaining}
+}
+
+// completePendingTransfer re-validates the token and applies a fully approved transfer
+func (s *TokenService) completePendingTransfer(ctx context.Context, tx *sql.Tx, pending *repository.PendingTransfer) (*models.Token, error) {
+	token, err := s.repo.GetByIDWithTx(ctx, tx, pending.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	if token == nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTokenNotFound,
+			"token not found",
+		)
+	}
+
+	if token.CurrentOwner != pending.FromOwner {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			"token ownership changed since the transfer was requested",
+		)
+	}
+
+	if err := s.validateOwnershipTransfer(token, pending.NewOwner); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTransferVelocity(ctx, tx, token.TokenID, s.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := token.TransferOwnership(pending.NewOwner, pending.TransactionID); err != nil {
+		return nil, err // Preserve the original error from the model
+	}
+
+	if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePendingTransferStatusWithTx(ctx, tx, pending.ID, repository.PendingTransferStatusCompleted); err != nil {
+		return nil, fmt.Errorf("failed to complete pending transfer: %w", err)
+	}
 
-		// Update token in repository
-		if err := s.repo.UpdateWithTx(ctx, tx, token); err != nil {
-			return fmt.Errorf("failed to update token: %w", err)
-		}
+	return token, nil
+}
 
-		transferredToken = *token
-		return nil
-	})
+// DestroyToken marks a token as invalid (irreversible destruction). Disputed tokens and tokens
+// with a transfer awaiting co-signer approval are not destroyed, since that would orphan the
+// dispute or transfer.
+func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) error {
+	return s.destroyToken(ctx, tokenID, false, "")
+}
 
-	if err != nil {
-		// Check if it's already an EchoPayError and return it directly
-		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
-			return nil, echoPayErr
-		}
-		
-		return nil, errors.NewTokenManagementError(
-			errors.ErrTokenTransferFailed,
-			fmt.Sprintf("failed to transfer token: %v", err),
+// ForceDestroyToken destroys a token even when it is disputed or has a pending transfer. It is
+// reserved for administrators; the override and their note are recorded in the token's audit
+// trail.
+func (s *TokenService) ForceDestroyToken(ctx context.Context, tokenID uuid.UUID, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"a note is required to force token destruction",
 		)
 	}
 
-	return &TransferTokenResponse{
-		Token:         transferredToken,
-		PreviousOwner: previousOwner,
-		TransferredAt: transferredAt,
-	}, nil
+	return s.destroyToken(ctx, tokenID, true, note)
 }
 
-// DestroyToken marks a token as invalid (irreversible destruction)
-func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) error {
+// destroyToken invalidates a token, overriding destruction blockers when forced
+func (s *TokenService) destroyToken(ctx context.Context, tokenID uuid.UUID, force bool, note string) error {
 	if tokenID == uuid.Nil {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID cannot be nil",
 		)
 	}
 
+	var owner uuid.UUID
+
 	// Use transaction to ensure atomicity
-	err := s.db.Transaction(func(tx *sql.Tx) error {
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
 		// Get current token
 		token, err := s.repo.GetByIDWithTx(ctx, tx, tokenID)
 		if err != nil {
@@ -211,11 +1010,29 @@ func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) erro
 				"token not found",
 			)
 		}
+		owner = token.CurrentOwner
 
 		// Verify token can be destroyed
-		if err := s.validateTokenDestruction(token); err != nil {
+		blockers, finalAt, err := s.validateTokenDestruction(ctx, tx, token)
+		if err != nil {
 			return err
 		}
+		if len(blockers) > 0 && !force {
+			// A token blocked only by settlement finality can be destroyed once it is final
+			if finalAt != nil && len(blockers) == 1 {
+				return errors.NewTokenManagementError(
+					errors.ErrNotYetFinal,
+					fmt.Sprintf("cannot destroy token: %s", blockers[0]),
+				).WithDetails(map[string]interface{}{
+					"token_id": token.TokenID,
+					"final_at": *finalAt,
+				})
+			}
+			return errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				fmt.Sprintf("cannot destroy token: %s", strings.Join(blockers, "; ")),
+			)
+		}
 
 		// Mark token as invalid
 		if err := token.Invalidate(); err != nil {
@@ -223,8 +1040,18 @@ func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) erro
 		}
 
 		// Update token in repository
-		if err := s.repo.UpdateWithTx(ctx, tx, token); err != nil {
-			return fmt.Errorf("failed to update token: %w", err)
+		if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
+			return err
+		}
+
+		if force {
+			metadata := map[string]interface{}{"note": note}
+			if len(blockers) > 0 {
+				metadata["overridden"] = blockers
+			}
+			if err := s.repo.CreateAuditEntryWithTx(ctx, tx, token.TokenID, "FORCED_DESTROY", metadata); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -242,6 +1069,8 @@ func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) erro
 		)
 	}
 
+	logTokenTransition(ctx, tokenID, "DESTROY", models.TokenStatusInvalid)
+	s.publishTokenEvent(events.TokenEventDestroyed, tokenID, owner, nil, models.TokenStatusInvalid, "Token destroyed")
 	return nil
 }
 
@@ -249,7 +1078,7 @@ func (s *TokenService) DestroyToken(ctx context.Context, tokenID uuid.UUID) erro
 func (s *TokenService) GetToken(ctx context.Context, tokenID uuid.UUID) (*models.Token, error) {
 	if tokenID == uuid.Nil {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID cannot be nil",
 		)
 	}
@@ -269,21 +1098,106 @@ func (s *TokenService) GetToken(ctx context.Context, tokenID uuid.UUID) (*models
 	return token, nil
 }
 
-// GetTokensByOwner retrieves all tokens owned by a specific owner
-func (s *TokenService) GetTokensByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Token, error) {
+// TokenPage is one page of a token listing along with the total number of matches
+type TokenPage struct {
+	Tokens []models.Token `json:"tokens"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// GetTokensByOwner retrieves a page of tokens owned by a specific owner. A non-positive
+// limit uses the default page size and limits above the maximum are capped.
+func (s *TokenService) GetTokensByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) (*TokenPage, error) {
 	if ownerID == uuid.Nil {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"owner ID cannot be nil",
 		)
 	}
 
-	tokens, err := s.repo.GetByOwner(ctx, ownerID)
+	limit, offset = repository.NormalizePagination(limit, offset)
+
+	tokens, err := s.repo.GetByOwner(ctx, ownerID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tokens by owner: %w", err)
 	}
 
-	return tokens, nil
+	total, err := s.repo.CountByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tokens by owner: %w", err)
+	}
+
+	return &TokenPage{
+		Tokens: tokens,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// HoldingsResponse represents an owner's aggregate holdings by CBDC type
+type HoldingsResponse struct {
+	Owner    uuid.UUID                 `json:"owner"`
+	Holdings []repository.TokenHolding `json:"holdings"`
+	Frozen   []repository.TokenHolding `json:"frozen,omitempty"`
+}
+
+// GetAllTokensByOwner retrieves every token owned by a specific owner, reading one
+// maximum-size page at a time. Prefer GetTokensByOwner for user-facing listings.
+func (s *TokenService) GetAllTokensByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Token, error) {
+	if ownerID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"owner ID cannot be nil",
+		)
+	}
+
+	var tokens []models.Token
+	for offset := 0; ; offset += repository.MaxTokenPageSize {
+		page, err := s.repo.GetByOwner(ctx, ownerID, repository.MaxTokenPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tokens by owner: %w", err)
+		}
+		tokens = append(tokens, page...)
+		if len(page) < repository.MaxTokenPageSize {
+			return tokens, nil
+		}
+	}
+}
+
+// GetHoldings returns an owner's active holdings grouped by CBDC type. Frozen holdings are
+// never counted in the totals but are reported separately when includeFrozen is set.
+func (s *TokenService) GetHoldings(ctx context.Context, ownerID uuid.UUID, includeFrozen bool) (*HoldingsResponse, error) {
+	if ownerID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"owner ID cannot be nil",
+		)
+	}
+
+	holdings, err := s.repo.SumByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holdings by owner: %w", err)
+	}
+
+	response := &HoldingsResponse{
+		Owner:    ownerID,
+		Holdings: []repository.TokenHolding{},
+	}
+
+	for _, holding := range holdings {
+		switch holding.Status {
+		case models.TokenStatusActive:
+			response.Holdings = append(response.Holdings, holding)
+		case models.TokenStatusFrozen:
+			if includeFrozen {
+				response.Frozen = append(response.Frozen, holding)
+			}
+		}
+	}
+
+	return response, nil
 }
 
 // VerifyOwnership verifies that a token is owned by a specific owner
@@ -296,47 +1210,83 @@ func (s *TokenService) VerifyOwnership(ctx context.Context, tokenID, ownerID uui
 	return token.CurrentOwner == ownerID, nil
 }
 
-// GetTokenHistory retrieves the transaction history for a token
+// GetTokenHistory retrieves the full transaction history for a token, oldest first,
+// including entries archived out of the token row
 func (s *TokenService) GetTokenHistory(ctx context.Context, tokenID uuid.UUID) ([]uuid.UUID, error) {
 	token, err := s.GetToken(ctx, tokenID)
 	if err != nil {
 		return nil, err
 	}
 
-	return []uuid.UUID(token.TransactionHistory), nil
+	return s.fullTransactionHistory(ctx, token)
+}
+
+// fullTransactionHistory prepends a token's archived transaction history to its in-row history
+func (s *TokenService) fullTransactionHistory(ctx context.Context, token *models.Token) ([]uuid.UUID, error) {
+	archived, err := s.repo.GetArchivedTransactionHistory(ctx, token.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived token history: %w", err)
+	}
+
+	history := make([]uuid.UUID, 0, len(archived)+len(token.TransactionHistory))
+	history = append(history, archived...)
+	return append(history, token.TransactionHistory...), nil
 }
 
-// FreezeTokenRequest represents a token freezing request
+// FreezeTokenRequest represents a token freezing request. Duration, such as "72h", limits the
+// freeze; without it the token stays frozen until unfrozen manually.
 type FreezeTokenRequest struct {
-	TokenID uuid.UUID `json:"token_id" binding:"required"`
-	Reason  string    `json:"reason,omitempty"`
+	TokenID  uuid.UUID    `json:"token_id" binding:"required"`
+	Reason   FreezeReason `json:"reason,omitempty"`
+	Note     string       `json:"note,omitempty"`
+	Duration string       `json:"duration,omitempty"`
 }
 
 // FreezeTokenResponse represents the response from token freezing
 type FreezeTokenResponse struct {
-	Token     models.Token `json:"token"`
-	FrozenAt  time.Time    `json:"frozen_at"`
-	Reason    string       `json:"reason,omitempty"`
+	Token       models.Token `json:"token"`
+	FrozenAt    time.Time    `json:"frozen_at"`
+	FrozenUntil *time.Time   `json:"frozen_until,omitempty"`
+	Reason      FreezeReason `json:"reason,omitempty"`
+	Note        string       `json:"note,omitempty"`
 }
 
 // UnfreezeTokenRequest represents a token unfreezing request
 type UnfreezeTokenRequest struct {
-	TokenID uuid.UUID `json:"token_id" binding:"required"`
-	Reason  string    `json:"reason,omitempty"`
+	TokenID uuid.UUID    `json:"token_id" binding:"required"`
+	Reason  FreezeReason `json:"reason,omitempty"`
+	Note    string       `json:"note,omitempty"`
 }
 
 // UnfreezeTokenResponse represents the response from token unfreezing
 type UnfreezeTokenResponse struct {
 	Token      models.Token `json:"token"`
 	UnfrozenAt time.Time    `json:"unfrozen_at"`
-	Reason     string       `json:"reason,omitempty"`
+	Reason     FreezeReason `json:"reason,omitempty"`
+	Note       string       `json:"note,omitempty"`
+}
+
+// AutoUnfreezeResponse represents the result of an expired freeze sweep
+type AutoUnfreezeResponse struct {
+	UnfrozenTokenIDs []uuid.UUID `json:"unfrozen_token_ids"`
+	FailedTokenIDs   []uuid.UUID `json:"failed_token_ids,omitempty"`
+	SweptAt          time.Time   `json:"swept_at"`
+}
+
+// UpdateComplianceFlagsResponse represents the response from a compliance flag update
+type UpdateComplianceFlagsResponse struct {
+	Token         models.Token           `json:"token"`
+	PreviousFlags models.ComplianceFlags `json:"previous_flags"`
+	AutoFrozen    bool                   `json:"auto_frozen"`
+	UpdatedAt     time.Time              `json:"updated_at"`
 }
 
 // BulkStatusUpdateRequest represents a bulk status update request
 type BulkStatusUpdateRequest struct {
 	TokenIDs  []uuid.UUID        `json:"token_ids" binding:"required,min=1,max=1000"`
 	NewStatus models.TokenStatus `json:"new_status" binding:"required"`
-	Reason    string             `json:"reason,omitempty"`
+	Reason    FreezeReason       `json:"reason,omitempty"`
+	Note      string             `json:"note,omitempty"`
 }
 
 // BulkStatusUpdateResponse represents the response from bulk status update
@@ -344,23 +1294,40 @@ type BulkStatusUpdateResponse struct {
 	UpdatedCount int                `json:"updated_count"`
 	NewStatus    models.TokenStatus `json:"new_status"`
 	UpdatedAt    time.Time          `json:"updated_at"`
-	Reason       string             `json:"reason,omitempty"`
+	Reason       FreezeReason       `json:"reason,omitempty"`
 }
 
 // FreezeToken freezes a token with atomic database operations
 func (s *TokenService) FreezeToken(ctx context.Context, req FreezeTokenRequest) (*FreezeTokenResponse, error) {
 	if req.TokenID == uuid.Nil {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID cannot be nil",
 		)
 	}
 
+	if err := s.validateFreezeReason(req.Reason); err != nil {
+		return nil, err
+	}
+
 	var frozenToken models.Token
-	frozenAt := time.Now()
+	frozenAt := s.clock.Now()
+
+	var frozenUntil *time.Time
+	if req.Duration != "" {
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			return nil, errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"freeze duration must be a positive duration such as 72h",
+			)
+		}
+		until := frozenAt.Add(duration)
+		frozenUntil = &until
+	}
 
 	// Use transaction to ensure atomicity
-	err := s.db.Transaction(func(tx *sql.Tx) error {
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
 		// Get current token
 		token, err := s.repo.GetByIDWithTx(ctx, tx, req.TokenID)
 		if err != nil {
@@ -385,8 +1352,20 @@ func (s *TokenService) FreezeToken(ctx context.Context, req FreezeTokenRequest)
 		}
 
 		// Update token in repository with timestamp logging
-		if err := s.repo.UpdateWithTx(ctx, tx, token); err != nil {
-			return fmt.Errorf("failed to update token: %w", err)
+		if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
+			return err
+		}
+
+		if frozenUntil != nil {
+			if err := s.repo.SetFrozenUntilWithTx(ctx, tx, token.TokenID, frozenUntil); err != nil {
+				return err
+			}
+		}
+
+		if metadata := freezeReasonMetadata(req.Reason, req.Note); len(metadata) > 0 {
+			if err := s.repo.CreateAuditEntryWithTx(ctx, tx, token.TokenID, "FREEZE", metadata); err != nil {
+				return err
+			}
 		}
 
 		frozenToken = *token
@@ -405,10 +1384,23 @@ func (s *TokenService) FreezeToken(ctx context.Context, req FreezeTokenRequest)
 		)
 	}
 
+	s.webhooks.Dispatch(webhooks.EventTokenFrozen, map[string]interface{}{
+		"token_id":     frozenToken.TokenID,
+		"owner":        frozenToken.CurrentOwner,
+		"reason":       req.Reason,
+		"frozen_at":    frozenAt,
+		"frozen_until": frozenUntil,
+	})
+
+	logTokenTransition(ctx, frozenToken.TokenID, "FREEZE", frozenToken.Status, "reason", req.Reason)
+	s.publishTokenEvent(events.TokenEventFrozen, frozenToken.TokenID, frozenToken.CurrentOwner, nil, frozenToken.Status, "Token frozen")
+
 	return &FreezeTokenResponse{
-		Token:    frozenToken,
-		FrozenAt: frozenAt,
-		Reason:   req.Reason,
+		Token:       frozenToken,
+		FrozenAt:    frozenAt,
+		FrozenUntil: frozenUntil,
+		Reason:      req.Reason,
+		Note:        req.Note,
 	}, nil
 }
 
@@ -416,16 +1408,74 @@ func (s *TokenService) FreezeToken(ctx context.Context, req FreezeTokenRequest)
 func (s *TokenService) UnfreezeToken(ctx context.Context, req UnfreezeTokenRequest) (*UnfreezeTokenResponse, error) {
 	if req.TokenID == uuid.Nil {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID cannot be nil",
 		)
 	}
 
+	if err := s.validateFreezeReason(req.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.unfreezeToken(ctx, req, nil)
+}
+
+// autoUnfreezeBatchSize is the maximum number of expired freezes lifted by one sweep
+const autoUnfreezeBatchSize = 500
+
+// AutoUnfreeze unfreezes tokens whose freeze expiry is at or before now, through the same path
+// as a manual unfreeze. Tokens that fail to unfreeze are reported and picked up again by the
+// next sweep.
+func (s *TokenService) AutoUnfreeze(ctx context.Context, now time.Time) (*AutoUnfreezeResponse, error) {
+	tokenIDs, err := s.repo.GetExpiredFreezes(ctx, now, autoUnfreezeBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired freezes: %w", err)
+	}
+
+	response := &AutoUnfreezeResponse{
+		UnfrozenTokenIDs: []uuid.UUID{},
+		SweptAt:          now,
+	}
+	for _, tokenID := range tokenIDs {
+		unfrozen, err := s.unfreezeToken(ctx, UnfreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  FreezeReasonExpired,
+		}, &now)
+		if err != nil {
+			response.FailedTokenIDs = append(response.FailedTokenIDs, tokenID)
+			continue
+		}
+		if unfrozen != nil {
+			response.UnfrozenTokenIDs = append(response.UnfrozenTokenIDs, tokenID)
+		}
+	}
+
+	return response, nil
+}
+
+// unfreezeToken unfreezes a token and clears its freeze expiry. When expiredBy is set the token
+// is only unfrozen if its expiry is still at or before that time, and a nil response is returned
+// for tokens that were unfrozen or refrozen since they were found to be expired.
+func (s *TokenService) unfreezeToken(ctx context.Context, req UnfreezeTokenRequest, expiredBy *time.Time) (*UnfreezeTokenResponse, error) {
 	var unfrozenToken models.Token
-	unfrozenAt := time.Now()
+	var skipped bool
+	unfrozenAt := s.clock.Now()
 
 	// Use transaction to ensure atomicity
-	err := s.db.Transaction(func(tx *sql.Tx) error {
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
+		skipped = false
+
+		if expiredBy != nil {
+			frozenUntil, err := s.repo.GetFrozenUntilWithTx(ctx, tx, req.TokenID)
+			if err != nil {
+				return err
+			}
+			if frozenUntil == nil || frozenUntil.After(*expiredBy) {
+				skipped = true
+				return nil
+			}
+		}
+
 		// Get current token
 		token, err := s.repo.GetByIDWithTx(ctx, tx, req.TokenID)
 		if err != nil {
@@ -450,8 +1500,18 @@ func (s *TokenService) UnfreezeToken(ctx context.Context, req UnfreezeTokenReque
 		}
 
 		// Update token in repository with timestamp logging
-		if err := s.repo.UpdateWithTx(ctx, tx, token); err != nil {
-			return fmt.Errorf("failed to update token: %w", err)
+		if err := s.updateTokenWithTx(ctx, tx, token); err != nil {
+			return err
+		}
+
+		if err := s.repo.SetFrozenUntilWithTx(ctx, tx, token.TokenID, nil); err != nil {
+			return err
+		}
+
+		if metadata := freezeReasonMetadata(req.Reason, req.Note); len(metadata) > 0 {
+			if err := s.repo.CreateAuditEntryWithTx(ctx, tx, token.TokenID, "UNFREEZE", metadata); err != nil {
+				return err
+			}
 		}
 
 		unfrozenToken = *token
@@ -470,13 +1530,113 @@ func (s *TokenService) UnfreezeToken(ctx context.Context, req UnfreezeTokenReque
 		)
 	}
 
+	if skipped {
+		return nil, nil
+	}
+
+	s.webhooks.Dispatch(webhooks.EventTokenUnfrozen, map[string]interface{}{
+		"token_id":    unfrozenToken.TokenID,
+		"owner":       unfrozenToken.CurrentOwner,
+		"reason":      req.Reason,
+		"unfrozen_at": unfrozenAt,
+	})
+
+	logTokenTransition(ctx, unfrozenToken.TokenID, "UNFREEZE", unfrozenToken.Status, "reason", req.Reason, "expired", expiredBy != nil)
+	s.publishTokenEvent(events.TokenEventUnfrozen, unfrozenToken.TokenID, unfrozenToken.CurrentOwner, nil, unfrozenToken.Status, "Token unfrozen")
+
 	return &UnfreezeTokenResponse{
 		Token:      unfrozenToken,
 		UnfrozenAt: unfrozenAt,
 		Reason:     req.Reason,
+		Note:       req.Note,
 	}, nil
 }
 
+// UpdateComplianceFlags replaces a token's compliance flags after a re-screen. If sanctions
+// screening changes from passed to failed, an active token is frozen in the same transaction
+// and the freeze is announced like a manual one.
+func (s *TokenService) UpdateComplianceFlags(ctx context.Context, tokenID uuid.UUID, flags models.ComplianceFlags) (*UpdateComplianceFlagsResponse, error) {
+	if tokenID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token ID cannot be nil",
+		)
+	}
+
+	var response UpdateComplianceFlagsResponse
+
+	err := s.transactionWithRetry(func(tx *sql.Tx) error {
+		response = UpdateComplianceFlagsResponse{}
+
+		token, err := s.repo.GetByIDWithTx(ctx, tx, tokenID)
+		if err != nil {
+			return fmt.Errorf("failed to get token: %w", err)
+		}
+
+		if token == nil {
+			return errors.NewTokenManagementError(
+				errors.ErrTokenNotFound,
+				"token not found",
+			)
+		}
+
+		if token.IsInvalid() {
+			return errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				"cannot update compliance flags of an invalid token",
+			)
+		}
+
+		previousFlags := token.ComplianceFlags
+		previousStatus := token.Status
+
+		token.ComplianceFlags = flags
+		token.UpdatedAt = s.clock.Now()
+
+		sanctionsFailed := previousFlags.SanctionsChecked && !flags.SanctionsChecked
+		if sanctionsFailed && token.IsActive() {
+			if err := token.Freeze(); err != nil {
+				return err // Preserve the original error from the model
+			}
+			response.AutoFrozen = true
+		}
+
+		if err := s.repo.UpdateComplianceFlagsWithTx(ctx, tx, token, previousFlags, previousStatus); err != nil {
+			return err
+		}
+
+		response.Token = *token
+		response.PreviousFlags = previousFlags
+		response.UpdatedAt = token.UpdatedAt
+		return nil
+	})
+
+	if err != nil {
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to update compliance flags: %v", err),
+		)
+	}
+
+	if response.AutoFrozen {
+		s.webhooks.Dispatch(webhooks.EventTokenFrozen, map[string]interface{}{
+			"token_id":  response.Token.TokenID,
+			"owner":     response.Token.CurrentOwner,
+			"reason":    FreezeReasonSanctionsHit,
+			"frozen_at": response.UpdatedAt,
+		})
+
+		logTokenTransition(ctx, tokenID, "COMPLIANCE_FREEZE", response.Token.Status)
+		s.publishTokenEvent(events.TokenEventFrozen, response.Token.TokenID, response.Token.CurrentOwner, nil, response.Token.Status, "Token frozen after failed sanctions screening")
+	}
+
+	return &response, nil
+}
+
 // BulkUpdateTokenStatus updates the status of multiple tokens atomically for efficient reversibility processing
 func (s *TokenService) BulkUpdateTokenStatus(ctx context.Context, req BulkStatusUpdateRequest) (*BulkStatusUpdateResponse, error) {
 	// Validate request
@@ -484,10 +1644,11 @@ func (s *TokenService) BulkUpdateTokenStatus(ctx context.Context, req BulkStatus
 		return nil, err
 	}
 
-	updatedAt := time.Now()
+	updatedAt := s.clock.Now()
+	tokenIDs := uniqueTokenIDs(req.TokenIDs)
 
 	// Use repository's bulk update method which handles transactions internally
-	err := s.repo.BulkUpdateStatus(ctx, req.TokenIDs, req.NewStatus)
+	err := s.repo.BulkUpdateStatus(ctx, tokenIDs, req.NewStatus, freezeReasonMetadata(req.Reason, req.Note))
 	if err != nil {
 		return nil, errors.NewTokenManagementError(
 			errors.ErrTransactionFailed,
@@ -495,16 +1656,18 @@ func (s *TokenService) BulkUpdateTokenStatus(ctx context.Context, req BulkStatus
 		)
 	}
 
+	logBulkTokenTransition(ctx, "BULK_STATUS_UPDATE", req.NewStatus, len(tokenIDs), "reason", req.Reason)
+
 	return &BulkStatusUpdateResponse{
-		UpdatedCount: len(req.TokenIDs),
+		UpdatedCount: len(tokenIDs),
 		NewStatus:    req.NewStatus,
 		UpdatedAt:    updatedAt,
 		Reason:       req.Reason,
 	}, nil
 }
 
-// GetTokensByStatus retrieves all tokens with a specific status
-func (s *TokenService) GetTokensByStatus(ctx context.Context, status models.TokenStatus) ([]models.Token, error) {
+// GetTokensByStatus retrieves a page of tokens with a specific status
+func (s *TokenService) GetTokensByStatus(ctx context.Context, status models.TokenStatus, limit, offset int) (*TokenPage, error) {
 	// Validate status
 	validStatuses := map[models.TokenStatus]bool{
 		models.TokenStatusActive:   true,
@@ -513,50 +1676,220 @@ func (s *TokenService) GetTokensByStatus(ctx context.Context, status models.Toke
 		models.TokenStatusInvalid:  true,
 	}
 
-	if !validStatuses[status] {
-		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
-			fmt.Sprintf("invalid token status: %s", status),
+	if !validStatuses[status] {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("invalid token status: %s", status),
+		)
+	}
+
+	limit, offset = repository.NormalizePagination(limit, offset)
+
+	tokens, err := s.repo.GetByStatus(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens by status: %w", err)
+	}
+
+	total, err := s.repo.CountByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tokens by status: %w", err)
+	}
+
+	return &TokenPage{
+		Tokens: tokens,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// FreezeReport is a page of token freezes recorded in a reporting period
+type FreezeReport struct {
+	From   time.Time                `json:"from"`
+	To     time.Time                `json:"to"`
+	Events []repository.FreezeEvent `json:"events"`
+	Total  int                      `json:"total"`
+	Limit  int                      `json:"limit"`
+	Offset int                      `json:"offset"`
+}
+
+// GetFreezeReport returns a page of the freezes recorded at or after from and before to,
+// with the reason recorded for each freeze
+func (s *TokenService) GetFreezeReport(ctx context.Context, from, to time.Time, limit, offset int) (*FreezeReport, error) {
+	if from.IsZero() || to.IsZero() {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"reporting period requires both from and to",
+		)
+	}
+
+	if !from.Before(to) {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"reporting period start must be before its end",
+		)
+	}
+
+	limit, offset = repository.NormalizePagination(limit, offset)
+
+	events, err := s.repo.GetFreezeEventsBetween(ctx, from, to, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get freeze events: %w", err)
+	}
+	if events == nil {
+		events = []repository.FreezeEvent{}
+	}
+
+	total, err := s.repo.CountFreezeEventsBetween(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count freeze events: %w", err)
+	}
+
+	return &FreezeReport{
+		From:   from,
+		To:     to,
+		Events: events,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// GetTokenAuditTrail retrieves a token's audit trail, newest first. Entries past the audit
+// retention window are only included when includeArchived is set, after the live entries.
+func (s *TokenService) GetTokenAuditTrail(ctx context.Context, tokenID uuid.UUID, includeArchived bool) ([]repository.TokenAuditEntry, error) {
+	if tokenID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"token ID cannot be nil",
+		)
+	}
+
+	auditTrail, err := s.repo.GetAuditTrail(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token audit trail: %w", err)
+	}
+
+	if !includeArchived {
+		live := auditTrail[:0]
+		for _, entry := range auditTrail {
+			if !entry.Archived {
+				live = append(live, entry)
+			}
+		}
+		auditTrail = live
+	}
+
+	return auditTrail, nil
+}
+
+// auditArchiveTimeout bounds an audit archival run
+const auditArchiveTimeout = 30 * time.Minute
+
+// ArchiveAuditTrail moves audit entries recorded before the cutoff out of the live audit trail
+// and returns how many were moved. Each token's chain is archived from its start, so the
+// chain still verifies across the archive boundary.
+func (s *TokenService) ArchiveAuditTrail(ctx context.Context, before time.Time) (int, error) {
+	if before.After(s.clock.Now()) {
+		return 0, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"archive cutoff cannot be in the future",
 		)
 	}
 
-	tokens, err := s.repo.GetByStatus(ctx, status)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tokens by status: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(ctx, auditArchiveTimeout)
+	defer cancel()
 
-	return tokens, nil
+	return s.repo.ArchiveAuditTrail(ctx, before)
+}
+
+// AuditTrailVerification reports the result of walking a token's audit hash chain. When the
+// chain is broken, BrokenEntryID and BrokenSequence identify the first entry that fails.
+type AuditTrailVerification struct {
+	TokenID         uuid.UUID  `json:"token_id"`
+	Valid           bool       `json:"valid"`
+	EntriesVerified int        `json:"entries_verified"`
+	UnsignedEntries int        `json:"unsigned_entries"`
+	BrokenEntryID   *uuid.UUID `json:"broken_entry_id,omitempty"`
+	BrokenSequence  int64      `json:"broken_sequence,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	VerifiedAt      time.Time  `json:"verified_at"`
 }
 
-// GetTokenAuditTrail retrieves the complete audit trail for a token
-func (s *TokenService) GetTokenAuditTrail(ctx context.Context, tokenID uuid.UUID) ([]repository.TokenAuditEntry, error) {
+// VerifyAuditTrail walks a token's audit hash chain and reports the first broken link.
+// Entries written before hash chaining was introduced are counted as unsigned and skipped.
+func (s *TokenService) VerifyAuditTrail(ctx context.Context, tokenID uuid.UUID) (*AuditTrailVerification, error) {
 	if tokenID == uuid.Nil {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID cannot be nil",
 		)
 	}
 
-	auditTrail, err := s.repo.GetAuditTrail(ctx, tokenID)
+	entries, err := s.repo.GetAuditChain(ctx, tokenID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get token audit trail: %w", err)
+		return nil, fmt.Errorf("failed to get token audit chain: %w", err)
 	}
 
-	return auditTrail, nil
+	result := &AuditTrailVerification{
+		TokenID:    tokenID,
+		Valid:      true,
+		VerifiedAt: s.clock.Now(),
+	}
+
+	previousHash := ""
+	expectedSequence := int64(1)
+	for _, entry := range entries {
+		if entry.EntryHash == "" {
+			result.UnsignedEntries++
+			continue
+		}
+
+		reason := ""
+		switch {
+		case entry.Sequence != expectedSequence:
+			reason = fmt.Sprintf("expected sequence %d, found %d", expectedSequence, entry.Sequence)
+		case entry.PreviousHash != previousHash:
+			reason = "previous hash does not match preceding entry"
+		default:
+			computed, err := repository.AuditEntryHash(entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash audit entry %s: %w", entry.ID, err)
+			}
+			if computed != entry.EntryHash {
+				reason = "entry hash does not match entry contents"
+			}
+		}
+
+		if reason != "" {
+			brokenID := entry.ID
+			result.Valid = false
+			result.BrokenEntryID = &brokenID
+			result.BrokenSequence = entry.Sequence
+			result.Reason = reason
+			return result, nil
+		}
+
+		result.EntriesVerified++
+		previousHash = entry.EntryHash
+		expectedSequence++
+	}
+
+	return result, nil
 }
 
 // BulkFreezeTokens freezes multiple tokens atomically for efficient fraud response
-func (s *TokenService) BulkFreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, reason string) (*BulkStatusUpdateResponse, error) {
+func (s *TokenService) BulkFreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, reason FreezeReason) (*BulkStatusUpdateResponse, error) {
 	if len(tokenIDs) == 0 {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token IDs list cannot be empty",
 		)
 	}
 
 	if len(tokenIDs) > 1000 {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"cannot freeze more than 1000 tokens at once",
 		)
 	}
@@ -567,21 +1900,33 @@ func (s *TokenService) BulkFreezeTokens(ctx context.Context, tokenIDs []uuid.UUI
 		Reason:    reason,
 	}
 
-	return s.BulkUpdateTokenStatus(ctx, req)
+	response, err := s.BulkUpdateTokenStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.webhooks.Dispatch(webhooks.EventTokensBulkFrozen, map[string]interface{}{
+		"token_ids":     tokenIDs,
+		"updated_count": response.UpdatedCount,
+		"reason":        reason,
+		"frozen_at":     response.UpdatedAt,
+	})
+
+	return response, nil
 }
 
 // BulkUnfreezeTokens unfreezes multiple tokens atomically for efficient fraud resolution
-func (s *TokenService) BulkUnfreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, reason string) (*BulkStatusUpdateResponse, error) {
+func (s *TokenService) BulkUnfreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, reason FreezeReason) (*BulkStatusUpdateResponse, error) {
 	if len(tokenIDs) == 0 {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token IDs list cannot be empty",
 		)
 	}
 
 	if len(tokenIDs) > 1000 {
 		return nil, errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"cannot unfreeze more than 1000 tokens at once",
 		)
 	}
@@ -595,68 +1940,337 @@ func (s *TokenService) BulkUnfreezeTokens(ctx context.Context, tokenIDs []uuid.U
 	return s.BulkUpdateTokenStatus(ctx, req)
 }
 
+// VerifyTokenProof recomputes a token's issuance Merkle root from its current fields and
+// stored proof path, and reports whether it matches the persisted batch root
+func (s *TokenService) VerifyTokenProof(ctx context.Context, tokenID uuid.UUID) (bool, string, error) {
+	token, err := s.GetToken(ctx, tokenID)
+	if err != nil {
+		return false, "", err
+	}
+
+	proof, err := s.repo.GetMerkleProof(ctx, tokenID)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get merkle proof: %w", err)
+	}
+
+	if proof == nil {
+		return false, "", errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			"no merkle proof recorded for token",
+		)
+	}
+
+	valid, err := merkle.Verify(tokenProofLeaf(token), proof.Path, proof.BatchRoot)
+	if err != nil {
+		return false, proof.BatchRoot, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			fmt.Sprintf("malformed merkle proof: %v", err),
+		)
+	}
+
+	return valid, proof.BatchRoot, nil
+}
+
+// VerifyTokenSignature reports whether a token's stored issuance signature validates. When
+// issuerPubKey is nil, the public key is resolved from the configured key source using the
+// key ID recorded with the signature.
+func (s *TokenService) VerifyTokenSignature(ctx context.Context, tokenID uuid.UUID, issuerPubKey ed25519.PublicKey) (bool, error) {
+	token, err := s.GetToken(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+
+	signature, err := s.repo.GetSignature(ctx, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get token signature: %w", err)
+	}
+
+	if signature == nil {
+		return false, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			"no signature recorded for token",
+		)
+	}
+
+	if signature.Algorithm != SignatureAlgorithm {
+		return false, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			fmt.Sprintf("unsupported signature algorithm: %s", signature.Algorithm),
+		)
+	}
+
+	if issuerPubKey == nil {
+		if s.keySource == nil {
+			return false, errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				"no issuer public key available for verification",
+			)
+		}
+
+		issuerPubKey, err = s.keySource.PublicKey(signature.KeyID)
+		if err != nil {
+			return false, errors.NewTokenManagementError(
+				errors.ErrInvalidTokenState,
+				err.Error(),
+			)
+		}
+	}
+
+	if len(issuerPubKey) != ed25519.PublicKeySize {
+		return false, errors.NewTokenManagementError(
+			errors.ErrInvalidTokenState,
+			"invalid issuer public key",
+		)
+	}
+
+	// Signatures stored before the issuance owner was recorded are checked against the current
+	// owner, which only matches tokens that have not been transferred since
+	issuedTo := signature.IssuedTo
+	if issuedTo == uuid.Nil {
+		issuedTo = token.CurrentOwner
+	}
+
+	return ed25519.Verify(issuerPubKey, tokenSigningPayload(token, issuedTo), signature.Signature), nil
+}
+
+// signToken signs a newly issued token with the issuer's active key and stores the signature
+func (s *TokenService) signToken(ctx context.Context, tx *sql.Tx, token *models.Token) error {
+	keyID, privateKey, err := s.keySource.SigningKey(token.Metadata.Issuer)
+	if err == ErrNoSigningKey {
+		// Issuers without a key issue unsigned tokens, as when signing is not configured
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SaveSignatureWithTx(ctx, tx, &repository.TokenSignature{
+		TokenID:   token.TokenID,
+		KeyID:     keyID,
+		Algorithm: SignatureAlgorithm,
+		Signature: ed25519.Sign(privateKey, tokenSigningPayload(token, token.CurrentOwner)),
+		IssuedTo:  token.CurrentOwner,
+	})
+}
+
+// storeIssuanceProofs builds a Merkle tree over an issuance batch and persists the root and proofs
+func (s *TokenService) storeIssuanceProofs(ctx context.Context, tx *sql.Tx, tokens []models.Token) error {
+	leaves := make([][]byte, len(tokens))
+	for i := range tokens {
+		leaves[i] = tokenProofLeaf(&tokens[i])
+	}
+
+	tree, err := merkle.NewTree(leaves)
+	if err != nil {
+		return err
+	}
+
+	batchID := uuid.New()
+	proofs := make([]repository.TokenMerkleProof, len(tokens))
+	for i, token := range tokens {
+		path, err := tree.Proof(i)
+		if err != nil {
+			return err
+		}
+
+		proofs[i] = repository.TokenMerkleProof{
+			TokenID:   token.TokenID,
+			BatchID:   batchID,
+			LeafIndex: i,
+			Path:      path,
+		}
+	}
+
+	return s.repo.SaveMerkleBatchWithTx(ctx, tx, batchID, tree.RootHex(), proofs)
+}
+
+// tokenProofLeaf serializes the immutable issuance fields of a token as a Merkle leaf.
+// Owner and status are excluded because they legitimately change after issuance.
+func tokenProofLeaf(token *models.Token) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%.2f|%s|%s",
+		token.TokenID,
+		token.CBDCType,
+		token.Denomination,
+		token.Metadata.Issuer,
+		token.Metadata.Series,
+	))
+}
+
+// MaxRecallBatchSize caps the number of tokens recalled per call; larger recalls are paged
+// using the continuation token returned in the response
+const MaxRecallBatchSize = 500
+
+// issuerScopeKey is the context key for the issuer the caller is authorized to act for
+type issuerScopeKey struct{}
+
+// WithIssuerScope returns a context authorizing the caller to act on behalf of an issuer
+func WithIssuerScope(ctx context.Context, issuer string) context.Context {
+	return context.WithValue(ctx, issuerScopeKey{}, issuer)
+}
+
+// IssuerScopeFromContext returns the issuer the caller is authorized to act for, if any
+func IssuerScopeFromContext(ctx context.Context) (string, bool) {
+	issuer, ok := ctx.Value(issuerScopeKey{}).(string)
+	return issuer, ok && issuer != ""
+}
+
+// RecallSeriesRequest represents an issuer-initiated series recall request
+type RecallSeriesRequest struct {
+	Issuer       string `json:"issuer" binding:"required"`
+	Series       string `json:"series" binding:"required"`
+	Reason       string `json:"reason" binding:"required"`
+	Continuation string `json:"continuation,omitempty"`
+}
+
+// RecallSeriesResponse represents the response from a series recall
+type RecallSeriesResponse struct {
+	Issuer        string    `json:"issuer"`
+	Series        string    `json:"series"`
+	RecalledCount int       `json:"recalled_count"`
+	Continuation  string    `json:"continuation,omitempty"`
+	RecalledAt    time.Time `json:"recalled_at"`
+}
+
+// RecallSeries invalidates active tokens of an issuer's series. At most MaxRecallBatchSize
+// tokens are recalled per call; when more remain, the response carries a continuation token
+// to pass back on the next call.
+func (s *TokenService) RecallSeries(ctx context.Context, issuer, series, reason, continuation string) (*RecallSeriesResponse, error) {
+	if err := s.validateRecallRequest(ctx, issuer, series, reason); err != nil {
+		return nil, err
+	}
+
+	afterID := uuid.Nil
+	if continuation != "" {
+		parsed, err := uuid.Parse(continuation)
+		if err != nil {
+			return nil, errors.NewTokenManagementError(
+				errors.ErrValidation,
+				"invalid continuation token",
+			)
+		}
+		afterID = parsed
+	}
+
+	recalledAt := s.clock.Now()
+
+	// Fetch one extra token to detect whether another page remains
+	tokens, err := s.repo.GetActiveBySeries(ctx, issuer, series, afterID, MaxRecallBatchSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens by series: %w", err)
+	}
+
+	nextContinuation := ""
+	if len(tokens) > MaxRecallBatchSize {
+		tokens = tokens[:MaxRecallBatchSize]
+		nextContinuation = tokens[len(tokens)-1].TokenID.String()
+	}
+
+	tokenIDs := make([]uuid.UUID, len(tokens))
+	for i, token := range tokens {
+		tokenIDs[i] = token.TokenID
+	}
+
+	var recalledCount int
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		count, err := s.repo.RecallWithTx(ctx, tx, tokenIDs, reason)
+		if err != nil {
+			return err
+		}
+		recalledCount = count
+		return nil
+	})
+
+	if err != nil {
+		// Check if it's already an EchoPayError and return it directly
+		if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+			return nil, echoPayErr
+		}
+
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to recall token series: %v", err),
+		)
+	}
+
+	logBulkTokenTransition(ctx, "RECALL", models.TokenStatusInvalid, recalledCount, "issuer", issuer, "series", series)
+
+	return &RecallSeriesResponse{
+		Issuer:        issuer,
+		Series:        series,
+		RecalledCount: recalledCount,
+		Continuation:  nextContinuation,
+		RecalledAt:    recalledAt,
+	}, nil
+}
+
 // Validation helper methods
 
 func (s *TokenService) validateIssueRequest(req IssueTokenRequest) error {
 	if req.CBDCType == "" {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"CBDC type is required",
 		)
 	}
 
 	// Validate CBDC type
-	validTypes := map[models.CBDCType]bool{
-		models.CBDCTypeUSD: true,
-		models.CBDCTypeEUR: true,
-		models.CBDCTypeGBP: true,
-	}
-	
-	if !validTypes[req.CBDCType] {
+	if !s.SupportsCBDCType(req.CBDCType) {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			fmt.Sprintf("invalid CBDC type: %s", req.CBDCType),
 		)
 	}
 
 	if req.Denomination <= 0 {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"denomination must be greater than 0",
 		)
 	}
 
 	if req.Denomination < 0.01 {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"denomination must be at least 0.01",
 		)
 	}
 
+	if err := s.denominations.Validate(req.CBDCType, req.Denomination); err != nil {
+		return err
+	}
+
 	if req.Owner == uuid.Nil {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"owner is required",
 		)
 	}
 
 	if req.Issuer == "" {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"issuer is required",
 		)
 	}
 
 	if req.Series == "" {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"series is required",
 		)
 	}
 
+	if err := validateMetadataLength("issuer", req.Issuer, MaxIssuerLength); err != nil {
+		return err
+	}
+
+	if err := validateMetadataLength("series", req.Series, MaxSeriesLength); err != nil {
+		return err
+	}
+
 	if req.Quantity <= 0 || req.Quantity > 1000 {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"quantity must be between 1 and 1000",
 		)
 	}
@@ -664,24 +2278,67 @@ func (s *TokenService) validateIssueRequest(req IssueTokenRequest) error {
 	return nil
 }
 
+// validateBatchIssueRequest validates each line as a single issuance and returns the
+// per-line requests to mint
+func (s *TokenService) validateBatchIssueRequest(req BatchIssueRequest) ([]IssueTokenRequest, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"at least one issuance line is required",
+		)
+	}
+
+	lineRequests := make([]IssueTokenRequest, len(req.Lines))
+	total := 0
+	for i, line := range req.Lines {
+		lineRequests[i] = IssueTokenRequest{
+			CBDCType:     req.CBDCType,
+			Denomination: money.Round(line.Denomination, string(req.CBDCType)),
+			Owner:        req.Owner,
+			Issuer:       req.Issuer,
+			Series:       req.Series,
+			Quantity:     line.Quantity,
+		}
+		if err := s.validateIssueRequest(lineRequests[i]); err != nil {
+			if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+				return nil, errors.NewTokenManagementError(
+					echoPayErr.Code,
+					fmt.Sprintf("line %d: %s", i+1, echoPayErr.Message),
+				)
+			}
+			return nil, err
+		}
+		total += line.Quantity
+	}
+
+	if total > MaxBatchIssueTokens {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			fmt.Sprintf("batch cannot issue more than %d tokens, got %d", MaxBatchIssueTokens, total),
+		)
+	}
+
+	return lineRequests, nil
+}
+
 func (s *TokenService) validateTransferRequest(req TransferTokenRequest) error {
 	if req.TokenID == uuid.Nil {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token ID is required",
 		)
 	}
 
 	if req.NewOwner == uuid.Nil {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"new owner is required",
 		)
 	}
 
 	if req.TransactionID == uuid.Nil {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"transaction ID is required",
 		)
 	}
@@ -706,19 +2363,77 @@ func (s *TokenService) validateOwnershipTransfer(token *models.Token, newOwner u
 		)
 	}
 
+	// Block the transfer if either party is sanctioned
+	for _, party := range []uuid.UUID{newOwner, token.CurrentOwner} {
+		if clear, listRef := s.screener.Screen(party); !clear {
+			return errors.NewTokenManagementError(
+				errors.ErrSanctionsBlocked,
+				fmt.Sprintf("transfer blocked by sanctions screening of %s", party),
+			).WithDetails(map[string]interface{}{
+				"blocked_party": party,
+				"list_ref":      listRef,
+			})
+		}
+	}
+
 	return nil
 }
 
-func (s *TokenService) validateTokenDestruction(token *models.Token) error {
-	// Tokens can be destroyed from any state except invalid
+// sanctionsBlockFromError builds the audit record for an ErrSanctionsBlocked validation error
+func sanctionsBlockFromError(err error, tokenID, fromOwner, toOwner uuid.UUID) (*repository.SanctionsBlock, bool) {
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	if !ok || echoPayErr.Code != errors.ErrSanctionsBlocked {
+		return nil, false
+	}
+
+	block := &repository.SanctionsBlock{
+		TokenID:   tokenID,
+		FromOwner: fromOwner,
+		ToOwner:   toOwner,
+	}
+	if party, ok := echoPayErr.Details["blocked_party"].(uuid.UUID); ok {
+		block.BlockedParty = party
+	}
+	if listRef, ok := echoPayErr.Details["list_ref"].(string); ok {
+		block.ListRef = listRef
+	}
+
+	return block, true
+}
+
+// validateTokenDestruction rejects destroying an invalid token and returns what else blocks
+// destroying it, which a forced destruction overrides. When the token's latest transfer is
+// not yet final, the time it becomes final is also returned.
+func (s *TokenService) validateTokenDestruction(ctx context.Context, tx *sql.Tx, token *models.Token) ([]string, *time.Time, error) {
 	if token.IsInvalid() {
-		return errors.NewTokenManagementError(
+		return nil, nil, errors.NewTokenManagementError(
 			errors.ErrInvalidTokenState,
 			"token is already invalid",
 		)
 	}
 
-	return nil
+	var blockers []string
+	if token.Status == models.TokenStatusDisputed {
+		blockers = append(blockers, "token is disputed")
+	}
+
+	pending, err := s.repo.HasPendingTransferWithTx(ctx, tx, token.TokenID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pending {
+		blockers = append(blockers, "token has a transfer awaiting co-signer approval")
+	}
+
+	finalAt, err := s.transferFinalAt(ctx, tx, token.TokenID, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if finalAt != nil {
+		blockers = append(blockers, fmt.Sprintf("token transfer is not final until %s", finalAt.UTC().Format(time.RFC3339)))
+	}
+
+	return blockers, finalAt, nil
 }
 
 func (s *TokenService) validateTokenFreeze(token *models.Token) error {
@@ -753,17 +2468,51 @@ func (s *TokenService) validateTokenUnfreeze(token *models.Token) error {
 	return nil
 }
 
+func (s *TokenService) validateRecallRequest(ctx context.Context, issuer, series, reason string) error {
+	if issuer == "" {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"issuer is required",
+		)
+	}
+
+	if series == "" {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"series is required",
+		)
+	}
+
+	if reason == "" {
+		return errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"recall reason is required",
+		)
+	}
+
+	// Only the issuer itself may recall its series
+	scope, ok := IssuerScopeFromContext(ctx)
+	if !ok || scope != issuer {
+		return errors.NewTokenManagementError(
+			errors.ErrAuthorizationFailed,
+			fmt.Sprintf("caller is not authorized to recall tokens for issuer %s", issuer),
+		)
+	}
+
+	return nil
+}
+
 func (s *TokenService) validateBulkStatusUpdateRequest(req BulkStatusUpdateRequest) error {
 	if len(req.TokenIDs) == 0 {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"token IDs list cannot be empty",
 		)
 	}
 
 	if len(req.TokenIDs) > 1000 {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			"cannot update more than 1000 tokens at once",
 		)
 	}
@@ -778,29 +2527,32 @@ func (s *TokenService) validateBulkStatusUpdateRequest(req BulkStatusUpdateReque
 
 	if !validStatuses[req.NewStatus] {
 		return errors.NewTokenManagementError(
-			errors.ErrInvalidTokenState,
+			errors.ErrValidation,
 			fmt.Sprintf("invalid token status: %s", req.NewStatus),
 		)
 	}
 
-	// Check for duplicate token IDs
-	seen := make(map[uuid.UUID]bool)
 	for _, tokenID := range req.TokenIDs {
 		if tokenID == uuid.Nil {
 			return errors.NewTokenManagementError(
-				errors.ErrInvalidTokenState,
+				errors.ErrValidation,
 				"token ID cannot be nil",
 			)
 		}
-		
-		if seen[tokenID] {
-			return errors.NewTokenManagementError(
-				errors.ErrInvalidTokenState,
-				fmt.Sprintf("duplicate token ID found: %s", tokenID),
-			)
-		}
-		seen[tokenID] = true
 	}
 
-	return nil
+	return s.validateFreezeReason(req.Reason)
+}
+
+// uniqueTokenIDs returns tokenIDs with repeats removed, keeping first-occurrence order
+func uniqueTokenIDs(tokenIDs []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(tokenIDs))
+	unique := make([]uuid.UUID, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		if !seen[tokenID] {
+			seen[tokenID] = true
+			unique = append(unique, tokenID)
+		}
+	}
+	return unique
 }
\ No newline at end of file
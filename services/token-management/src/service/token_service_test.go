@@ -2,15 +2,22 @@ package service
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/events"
 	"echopay/token-management/src/models"
 	"echopay/token-management/src/repository"
 )
@@ -46,6 +53,14 @@ func (m *MockTokenRepository) GetByIDWithTx(ctx context.Context, tx *sql.Tx, tok
 	return args.Get(0).(*models.Token), args.Error(1)
 }
 
+func (m *MockTokenRepository) GetByIDs(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID]*models.Token, error) {
+	args := m.Called(ctx, tokenIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*models.Token), args.Error(1)
+}
+
 func (m *MockTokenRepository) Update(ctx context.Context, token *models.Token) error {
 	args := m.Called(ctx, token)
 	return args.Error(0)
@@ -56,22 +71,32 @@ func (m *MockTokenRepository) UpdateWithTx(ctx context.Context, tx *sql.Tx, toke
 	return args.Error(0)
 }
 
-func (m *MockTokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Token, error) {
-	args := m.Called(ctx, ownerID)
+func (m *MockTokenRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]models.Token, error) {
+	args := m.Called(ctx, ownerID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Token), args.Error(1)
 }
 
-func (m *MockTokenRepository) GetByStatus(ctx context.Context, status models.TokenStatus) ([]models.Token, error) {
-	args := m.Called(ctx, status)
+func (m *MockTokenRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetByStatus(ctx context.Context, status models.TokenStatus, limit, offset int) ([]models.Token, error) {
+	args := m.Called(ctx, status, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Token), args.Error(1)
 }
 
+func (m *MockTokenRepository) CountByStatus(ctx context.Context, status models.TokenStatus) (int, error) {
+	args := m.Called(ctx, status)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTokenRepository) GetByCBDCType(ctx context.Context, cbdcType models.CBDCType) ([]models.Token, error) {
 	args := m.Called(ctx, cbdcType)
 	if args.Get(0) == nil {
@@ -80,8 +105,8 @@ func (m *MockTokenRepository) GetByCBDCType(ctx context.Context, cbdcType models
 	return args.Get(0).([]models.Token), args.Error(1)
 }
 
-func (m *MockTokenRepository) BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus) error {
-	args := m.Called(ctx, tokenIDs, status)
+func (m *MockTokenRepository) BulkUpdateStatus(ctx context.Context, tokenIDs []uuid.UUID, status models.TokenStatus, metadata map[string]interface{}) error {
+	args := m.Called(ctx, tokenIDs, status, metadata)
 	return args.Error(0)
 }
 
@@ -93,6 +118,273 @@ func (m *MockTokenRepository) GetAuditTrail(ctx context.Context, tokenID uuid.UU
 	return args.Get(0).([]repository.TokenAuditEntry), args.Error(1)
 }
 
+func (m *MockTokenRepository) GetAuditChain(ctx context.Context, tokenID uuid.UUID) ([]repository.TokenAuditEntry, error) {
+	args := m.Called(ctx, tokenID)
+	return args.Get(0).([]repository.TokenAuditEntry), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetAuditTrails(ctx context.Context, tokenIDs []uuid.UUID) (map[uuid.UUID][]repository.TokenAuditEntry, error) {
+	args := m.Called(ctx, tokenIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID][]repository.TokenAuditEntry), args.Error(1)
+}
+
+func (m *MockTokenRepository) ArchiveAuditTrail(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) CreateAuditEntryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, operation string, metadata map[string]interface{}) error {
+	args := m.Called(ctx, tx, tokenID, operation, metadata)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetFreezeEventsBetween(ctx context.Context, from, to time.Time, limit, offset int) ([]repository.FreezeEvent, error) {
+	args := m.Called(ctx, from, to, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.FreezeEvent), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetAuditEntriesBetween(ctx context.Context, from, to, afterTimestamp time.Time, afterID uuid.UUID, limit int) ([]repository.TokenAuditEntry, error) {
+	args := m.Called(ctx, from, to, afterTimestamp, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.TokenAuditEntry), args.Error(1)
+}
+
+func (m *MockTokenRepository) CountFreezeEventsBetween(ctx context.Context, from, to time.Time) (int, error) {
+	args := m.Called(ctx, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetActiveBySeries(ctx context.Context, issuer, series string, afterID uuid.UUID, limit int) ([]models.Token, error) {
+	args := m.Called(ctx, issuer, series, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Token), args.Error(1)
+}
+
+func (m *MockTokenRepository) RecallWithTx(ctx context.Context, tx *sql.Tx, tokenIDs []uuid.UUID, reason string) (int, error) {
+	args := m.Called(ctx, tx, tokenIDs, reason)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) SaveMerkleBatchWithTx(ctx context.Context, tx *sql.Tx, batchID uuid.UUID, root string, proofs []repository.TokenMerkleProof) error {
+	args := m.Called(ctx, tx, batchID, root, proofs)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetMerkleProof(ctx context.Context, tokenID uuid.UUID) (*repository.TokenMerkleProof, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TokenMerkleProof), args.Error(1)
+}
+
+func (m *MockTokenRepository) SaveSignatureWithTx(ctx context.Context, tx *sql.Tx, signature *repository.TokenSignature) error {
+	args := m.Called(ctx, tx, signature)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetSignature(ctx context.Context, tokenID uuid.UUID) (*repository.TokenSignature, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TokenSignature), args.Error(1)
+}
+
+func (m *MockTokenRepository) RotateSigningKey(ctx context.Context, key *repository.IssuerSigningKey) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetSigningKeys(ctx context.Context) ([]repository.IssuerSigningKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.IssuerSigningKey), args.Error(1)
+}
+
+func (m *MockTokenRepository) SumByOwner(ctx context.Context, ownerID uuid.UUID) ([]repository.TokenHolding, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.TokenHolding), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetRequiredSignersWithTx(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) (int, error) {
+	args := m.Called(ctx, tx, walletID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) SetRequiredSigners(ctx context.Context, walletID uuid.UUID, requiredSigners int, coSigners []uuid.UUID) error {
+	args := m.Called(ctx, walletID, requiredSigners, coSigners)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) IsCoSignerWithTx(ctx context.Context, tx *sql.Tx, walletID, signerID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, walletID, signerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) CreatePendingTransferWithTx(ctx context.Context, tx *sql.Tx, transfer *repository.PendingTransfer) error {
+	args := m.Called(ctx, tx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetPendingTransferWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID) (*repository.PendingTransfer, error) {
+	args := m.Called(ctx, tx, pendingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.PendingTransfer), args.Error(1)
+}
+
+func (m *MockTokenRepository) AddTransferApprovalWithTx(ctx context.Context, tx *sql.Tx, pendingID, signerID uuid.UUID) error {
+	args := m.Called(ctx, tx, pendingID, signerID)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) UpdatePendingTransferStatusWithTx(ctx context.Context, tx *sql.Tx, pendingID uuid.UUID, status repository.PendingTransferStatus) error {
+	args := m.Called(ctx, tx, pendingID, status)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) HasPendingTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, tokenID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) CountOwnershipTransfersSinceWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, since time.Time) (int, error) {
+	args := m.Called(ctx, tx, tokenID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RecordTransferNonceWithTx(ctx context.Context, tx *sql.Tx, ownerID uuid.UUID, nonce string, now, expiresAt time.Time) (bool, error) {
+	args := m.Called(ctx, tx, ownerID, nonce, now, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetLastOwnershipTransferWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, tx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetPendingTransfersByToken(ctx context.Context, tokenID uuid.UUID) ([]repository.PendingTransfer, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.PendingTransfer), args.Error(1)
+}
+
+func (m *MockTokenRepository) RecordSanctionsBlockWithTx(ctx context.Context, tx *sql.Tx, block *repository.SanctionsBlock) error {
+	args := m.Called(ctx, tx, block)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) UpdateComplianceFlagsWithTx(ctx context.Context, tx *sql.Tx, token *models.Token, previousFlags models.ComplianceFlags, previousStatus models.TokenStatus) error {
+	args := m.Called(ctx, tx, token, previousFlags, previousStatus)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetIssuerQuotaForUpdateWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType) (*repository.IssuerQuota, error) {
+	args := m.Called(ctx, tx, issuer, series, cbdcType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.IssuerQuota), args.Error(1)
+}
+
+func (m *MockTokenRepository) AddMintedTotalWithTx(ctx context.Context, tx *sql.Tx, issuer, series string, cbdcType models.CBDCType, amount float64) error {
+	args := m.Called(ctx, tx, issuer, series, cbdcType, amount)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetIssuerQuotas(ctx context.Context, issuer string) ([]repository.IssuerQuota, error) {
+	args := m.Called(ctx, issuer)
+	return args.Get(0).([]repository.IssuerQuota), args.Error(1)
+}
+
+func (m *MockTokenRepository) SetIssuerQuota(ctx context.Context, issuer, series string, cbdcType models.CBDCType, quota float64) (*repository.IssuerQuota, error) {
+	args := m.Called(ctx, issuer, series, cbdcType, quota)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.IssuerQuota), args.Error(1)
+}
+
+func (m *MockTokenRepository) SetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, frozenUntil *time.Time) error {
+	args := m.Called(ctx, tx, tokenID, frozenUntil)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetFrozenUntilWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, tx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockTokenRepository) GetExpiredFreezes(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockTokenRepository) ArchiveTransactionHistoryWithTx(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, transactionIDs []uuid.UUID) error {
+	args := m.Called(ctx, tx, tokenID, transactionIDs)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetArchivedTransactionHistory(ctx context.Context, tokenID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockTokenRepository) FreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	args := m.Called(ctx, ownerID, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockTokenRepository) UnfreezeOwnerTokens(ctx context.Context, ownerID uuid.UUID, reasonCode string, metadata map[string]interface{}) ([]uuid.UUID, error) {
+	args := m.Called(ctx, ownerID, reasonCode, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockTokenRepository) SetStrictChecksums(strict bool) {
+	m.Called(strict)
+}
+
+func (m *MockTokenRepository) SetBulkUpdateOptions(batchSize, auditWorkers int) {
+	m.Called(batchSize, auditWorkers)
+}
+
 // MockDatabase is a mock implementation of database transaction functionality
 type MockDatabase struct {
 	mock.Mock
@@ -126,7 +418,11 @@ func TestTokenService_IssueTokens(t *testing.T) {
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, "Federal Reserve", "2025-A", models.CBDCTypeUSD).Return(nil, nil).Once()
 				repo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Times(5)
+				repo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.MatchedBy(func(proofs []repository.TokenMerkleProof) bool {
+					return len(proofs) == 5
+				})).Return(nil).Once()
 			},
 			expectError: false,
 		},
@@ -144,7 +440,7 @@ func TestTokenService_IssueTokens(t *testing.T) {
 				// No database calls expected for validation errors
 			},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "zero denomination",
@@ -158,7 +454,7 @@ func TestTokenService_IssueTokens(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "nil owner",
@@ -172,7 +468,7 @@ func TestTokenService_IssueTokens(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "empty issuer",
@@ -186,7 +482,7 @@ func TestTokenService_IssueTokens(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "quantity too high",
@@ -200,7 +496,7 @@ func TestTokenService_IssueTokens(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -248,6 +544,46 @@ func TestTokenService_IssueTokens(t *testing.T) {
 	}
 }
 
+func TestTokenService_RegisteredCBDCType(t *testing.T) {
+	jpy := models.CBDCType("JPY-CBDC")
+	request := IssueTokenRequest{
+		CBDCType:     jpy,
+		Denomination: 1000.0,
+		Owner:        uuid.New(),
+		Issuer:       "Bank of Japan",
+		Series:       "2025-A",
+		Quantity:     1,
+	}
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	// Unregistered CBDC types are rejected
+	_, err := service.IssueTokens(context.Background(), request)
+	tokenErr, ok := err.(*errors.EchoPayError)
+	assert.True(t, ok, "Expected EchoPayError")
+	assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+	assert.False(t, service.SupportsCBDCType(jpy))
+
+	registry := currency.NewDefaultRegistry()
+	registry.Register(string(jpy))
+	service.SetCurrencyRegistry(registry)
+
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, "Bank of Japan", "2025-A", jpy).Return(nil, nil).Once()
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.Anything).Return(nil).Once()
+
+	response, err := service.IssueTokens(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Tokens, 1)
+	assert.Equal(t, jpy, response.Tokens[0].CBDCType)
+	assert.Contains(t, service.SupportedCBDCTypes(), "JPY-CBDC")
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTokenService_TransferToken(t *testing.T) {
 	tokenID := uuid.New()
 	currentOwner := uuid.New()
@@ -281,6 +617,7 @@ func TestTokenService_TransferToken(t *testing.T) {
 				
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+				repo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, currentOwner).Return(0, nil)
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
 			},
 			expectError: false,
@@ -356,7 +693,7 @@ func TestTokenService_TransferToken(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "nil new owner",
@@ -367,7 +704,7 @@ func TestTokenService_TransferToken(t *testing.T) {
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -405,6 +742,61 @@ func TestTokenService_TransferToken(t *testing.T) {
 	}
 }
 
+func TestTokenService_TransferToken_ArchivesHistoryOverLimit(t *testing.T) {
+	const limit = 3
+	tokenID := uuid.New()
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: uuid.New(),
+		Status:       models.TokenStatusActive,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	service.SetTransactionHistoryLimit(limit)
+
+	var archived []uuid.UUID
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	mockRepo.On("ArchiveTransactionHistoryWithTx", mock.Anything, mock.Anything, tokenID, mock.Anything).Run(func(args mock.Arguments) {
+		archived = append(archived, args.Get(3).([]uuid.UUID)...)
+	}).Return(nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+		return len(token.TransactionHistory) <= limit
+	})).Return(nil)
+
+	var transactionIDs []uuid.UUID
+	for i := 0; i < 2*limit+1; i++ {
+		transactionID := uuid.New()
+		transactionIDs = append(transactionIDs, transactionID)
+
+		_, err := service.TransferToken(context.Background(), TransferTokenRequest{
+			TokenID:       tokenID,
+			NewOwner:      uuid.New(),
+			TransactionID: transactionID,
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, transactionIDs[:len(transactionIDs)-limit], archived)
+	assert.Equal(t, []uuid.UUID(transactionIDs[len(transactionIDs)-limit:]), []uuid.UUID(token.TransactionHistory))
+
+	mockRepo.On("GetByID", mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("GetArchivedTransactionHistory", mock.Anything, tokenID).Return(archived, nil)
+
+	history, err := service.GetTokenHistory(context.Background(), tokenID)
+
+	require.NoError(t, err)
+	assert.Equal(t, transactionIDs, history)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestTokenService_DestroyToken(t *testing.T) {
 	tokenID := uuid.New()
 	owner := uuid.New()
@@ -432,10 +824,53 @@ func TestTokenService_DestroyToken(t *testing.T) {
 				
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+				repo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, tokenID).Return(false, nil)
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
 			},
 			expectError: false,
 		},
+		{
+			name:    "disputed token",
+			tokenID: tokenID,
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				token := &models.Token{
+					TokenID:      tokenID,
+					CBDCType:     models.CBDCTypeUSD,
+					Denomination: 100.0,
+					CurrentOwner: owner,
+					Status:       models.TokenStatusDisputed,
+					CreatedAt:    time.Now(),
+					UpdatedAt:    time.Now(),
+				}
+				
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+				repo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, tokenID).Return(false, nil)
+			},
+			expectError: true,
+			errorType:   errors.ErrInvalidTokenState,
+		},
+		{
+			name:    "token with pending co-signer transfer",
+			tokenID: tokenID,
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				token := &models.Token{
+					TokenID:      tokenID,
+					CBDCType:     models.CBDCTypeUSD,
+					Denomination: 100.0,
+					CurrentOwner: owner,
+					Status:       models.TokenStatusActive,
+					CreatedAt:    time.Now(),
+					UpdatedAt:    time.Now(),
+				}
+				
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+				repo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, tokenID).Return(true, nil)
+			},
+			expectError: true,
+			errorType:   errors.ErrInvalidTokenState,
+		},
 		{
 			name:    "token not found",
 			tokenID: tokenID,
@@ -471,7 +906,7 @@ func TestTokenService_DestroyToken(t *testing.T) {
 			tokenID:     uuid.Nil,
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -504,6 +939,59 @@ func TestTokenService_DestroyToken(t *testing.T) {
 	}
 }
 
+func TestTokenService_ForceDestroyToken(t *testing.T) {
+	tokenID := uuid.New()
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: uuid.New(),
+		Status:       models.TokenStatusDisputed,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("HasPendingTransferWithTx", mock.Anything, mock.Anything, tokenID).Return(true, nil)
+
+	// Without force the error names every blocker
+	err := service.DestroyToken(context.Background(), tokenID)
+	tokenErr, ok := err.(*errors.EchoPayError)
+	assert.True(t, ok, "Expected EchoPayError")
+	assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+	assert.Contains(t, tokenErr.Message, "disputed")
+	assert.Contains(t, tokenErr.Message, "co-signer")
+	mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+
+	// Forcing records the override and the note
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+	mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FORCED_DESTROY", map[string]interface{}{
+		"note":       "counterfeit confirmed by issuer",
+		"overridden": []string{"token is disputed", "token has a transfer awaiting co-signer approval"},
+	}).Return(nil).Once()
+
+	err = service.ForceDestroyToken(context.Background(), tokenID, "  counterfeit confirmed by issuer ")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TokenStatusInvalid, token.Status)
+	mockRepo.AssertExpectations(t)
+
+	t.Run("note is required", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, new(MockDatabase))
+
+		err := service.ForceDestroyToken(context.Background(), tokenID, "  ")
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "GetByIDWithTx", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestTokenService_GetToken(t *testing.T) {
 	tokenID := uuid.New()
 	owner := uuid.New()
@@ -547,7 +1035,7 @@ func TestTokenService_GetToken(t *testing.T) {
 			tokenID:     uuid.Nil,
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -673,7 +1161,7 @@ func TestTokenService_FreezeToken(t *testing.T) {
 			name: "successful token freeze",
 			request: FreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Fraud investigation",
+				Reason:  FreezeReasonFraudInvestigation,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				token := &models.Token{
@@ -689,6 +1177,7 @@ func TestTokenService_FreezeToken(t *testing.T) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", map[string]interface{}{"reason_code": string(FreezeReasonFraudInvestigation)}).Return(nil)
 			},
 			expectError: false,
 		},
@@ -696,7 +1185,7 @@ func TestTokenService_FreezeToken(t *testing.T) {
 			name: "freeze already frozen token",
 			request: FreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Additional investigation",
+				Reason:  FreezeReasonFraudInvestigation,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				token := &models.Token{
@@ -719,7 +1208,7 @@ func TestTokenService_FreezeToken(t *testing.T) {
 			name: "freeze invalid token",
 			request: FreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Investigation",
+				Reason:  FreezeReasonFraudInvestigation,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				token := &models.Token{
@@ -742,7 +1231,7 @@ func TestTokenService_FreezeToken(t *testing.T) {
 			name: "token not found",
 			request: FreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Investigation",
+				Reason:  FreezeReasonFraudInvestigation,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
@@ -755,11 +1244,11 @@ func TestTokenService_FreezeToken(t *testing.T) {
 			name: "nil token ID",
 			request: FreezeTokenRequest{
 				TokenID: uuid.Nil,
-				Reason:  "Investigation",
+				Reason:  FreezeReasonFraudInvestigation,
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -812,7 +1301,7 @@ func TestTokenService_UnfreezeToken(t *testing.T) {
 			name: "successful token unfreeze",
 			request: UnfreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Investigation completed",
+				Reason:  FreezeReasonInvestigationClosed,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				token := &models.Token{
@@ -828,6 +1317,8 @@ func TestTokenService_UnfreezeToken(t *testing.T) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "UNFREEZE", map[string]interface{}{"reason_code": string(FreezeReasonInvestigationClosed)}).Return(nil)
+				repo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, (*time.Time)(nil)).Return(nil)
 			},
 			expectError: false,
 		},
@@ -835,7 +1326,7 @@ func TestTokenService_UnfreezeToken(t *testing.T) {
 			name: "unfreeze active token",
 			request: UnfreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Not needed",
+				Reason:  FreezeReasonCustomerRequest,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				token := &models.Token{
@@ -858,7 +1349,7 @@ func TestTokenService_UnfreezeToken(t *testing.T) {
 			name: "token not found",
 			request: UnfreezeTokenRequest{
 				TokenID: tokenID,
-				Reason:  "Investigation completed",
+				Reason:  FreezeReasonInvestigationClosed,
 			},
 			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
@@ -871,11 +1362,11 @@ func TestTokenService_UnfreezeToken(t *testing.T) {
 			name: "nil token ID",
 			request: UnfreezeTokenRequest{
 				TokenID: uuid.Nil,
-				Reason:  "Investigation completed",
+				Reason:  FreezeReasonInvestigationClosed,
 			},
 			setupMocks:  func(repo *MockTokenRepository, db *MockDatabase) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -930,10 +1421,10 @@ func TestTokenService_BulkUpdateTokenStatus(t *testing.T) {
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1, tokenID2, tokenID3},
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Fraud investigation",
+				Reason:    FreezeReasonFraudInvestigation,
 			},
 			setupMocks: func(repo *MockTokenRepository) {
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
@@ -942,10 +1433,10 @@ func TestTokenService_BulkUpdateTokenStatus(t *testing.T) {
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1, tokenID2},
 				NewStatus: models.TokenStatusActive,
-				Reason:    "Investigation completed",
+				Reason:    FreezeReasonInvestigationClosed,
 			},
 			setupMocks: func(repo *MockTokenRepository) {
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusActive).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusActive, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
@@ -954,55 +1445,55 @@ func TestTokenService_BulkUpdateTokenStatus(t *testing.T) {
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{},
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Investigation",
+				Reason:    FreezeReasonFraudInvestigation,
 			},
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "too many tokens",
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  make([]uuid.UUID, 1001),
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Investigation",
+				Reason:    FreezeReasonFraudInvestigation,
 			},
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "invalid status",
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1},
 				NewStatus: "invalid-status",
-				Reason:    "Investigation",
+				Reason:    FreezeReasonFraudInvestigation,
 			},
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name: "nil token ID",
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1, uuid.Nil, tokenID2},
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Investigation",
+				Reason:    FreezeReasonFraudInvestigation,
 			},
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
-			name: "duplicate token ID",
+			name: "reason too long",
 			request: BulkStatusUpdateRequest{
-				TokenIDs:  []uuid.UUID{tokenID1, tokenID2, tokenID1},
+				TokenIDs:  []uuid.UUID{tokenID1},
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Investigation",
+				Reason:    FreezeReason(strings.Repeat("x", MaxFreezeReasonLength+1)),
 			},
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -1039,15 +1530,36 @@ func TestTokenService_BulkUpdateTokenStatus(t *testing.T) {
 	}
 }
 
-func TestTokenService_BulkFreezeTokens(t *testing.T) {
+func TestTokenService_BulkUpdateTokenStatus_DeduplicatesTokenIDs(t *testing.T) {
 	tokenID1 := uuid.New()
 	tokenID2 := uuid.New()
-	tokenID3 := uuid.New()
+
+	mockRepo := new(MockTokenRepository)
+	service := NewTokenServiceWithDeps(mockRepo, nil)
+
+	// Repeated IDs are collapsed before the update reaches the database
+	mockRepo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusFrozen, mock.Anything).Return(nil)
+
+	response, err := service.BulkUpdateTokenStatus(context.Background(), BulkStatusUpdateRequest{
+		TokenIDs:  []uuid.UUID{tokenID1, tokenID2, tokenID1, tokenID2},
+		NewStatus: models.TokenStatusFrozen,
+		Reason:    FreezeReasonFraudInvestigation,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.UpdatedCount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenService_BulkFreezeTokens(t *testing.T) {
+	tokenID1 := uuid.New()
+	tokenID2 := uuid.New()
+	tokenID3 := uuid.New()
 
 	tests := []struct {
 		name        string
 		tokenIDs    []uuid.UUID
-		reason      string
+		reason      FreezeReason
 		setupMocks  func(*MockTokenRepository)
 		expectError bool
 		errorType   string
@@ -1055,27 +1567,27 @@ func TestTokenService_BulkFreezeTokens(t *testing.T) {
 		{
 			name:     "successful bulk freeze",
 			tokenIDs: []uuid.UUID{tokenID1, tokenID2, tokenID3},
-			reason:   "Fraud investigation",
+			reason:   FreezeReasonFraudInvestigation,
 			setupMocks: func(repo *MockTokenRepository) {
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
 		{
 			name:        "empty token list",
 			tokenIDs:    []uuid.UUID{},
-			reason:      "Investigation",
+			reason:      FreezeReasonFraudInvestigation,
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 		{
 			name:        "too many tokens",
 			tokenIDs:    make([]uuid.UUID, 1001),
-			reason:      "Investigation",
+			reason:      FreezeReasonFraudInvestigation,
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -1118,7 +1630,7 @@ func TestTokenService_BulkUnfreezeTokens(t *testing.T) {
 	tests := []struct {
 		name        string
 		tokenIDs    []uuid.UUID
-		reason      string
+		reason      FreezeReason
 		setupMocks  func(*MockTokenRepository)
 		expectError bool
 		errorType   string
@@ -1126,19 +1638,19 @@ func TestTokenService_BulkUnfreezeTokens(t *testing.T) {
 		{
 			name:     "successful bulk unfreeze",
 			tokenIDs: []uuid.UUID{tokenID1, tokenID2},
-			reason:   "Investigation completed",
+			reason:   FreezeReasonInvestigationClosed,
 			setupMocks: func(repo *MockTokenRepository) {
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusActive).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusActive, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
 		{
 			name:        "empty token list",
 			tokenIDs:    []uuid.UUID{},
-			reason:      "Investigation completed",
+			reason:      FreezeReasonInvestigationClosed,
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -1202,7 +1714,8 @@ func TestTokenService_GetTokensByStatus(t *testing.T) {
 						Status:       models.TokenStatusFrozen,
 					},
 				}
-				repo.On("GetByStatus", mock.Anything, models.TokenStatusFrozen).Return(tokens, nil)
+				repo.On("GetByStatus", mock.Anything, models.TokenStatusFrozen, repository.DefaultTokenPageSize, 0).Return(tokens, nil)
+				repo.On("CountByStatus", mock.Anything, models.TokenStatusFrozen).Return(len(tokens), nil)
 			},
 			expectError: false,
 		},
@@ -1211,7 +1724,7 @@ func TestTokenService_GetTokensByStatus(t *testing.T) {
 			status:      "invalid-status",
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -1223,11 +1736,11 @@ func TestTokenService_GetTokensByStatus(t *testing.T) {
 
 			tt.setupMocks(mockRepo)
 
-			tokens, err := service.GetTokensByStatus(context.Background(), tt.status)
+			page, err := service.GetTokensByStatus(context.Background(), tt.status, 0, 0)
 
 			if tt.expectError {
 				assert.Error(t, err)
-				assert.Nil(t, tokens)
+				assert.Nil(t, page)
 				
 				if tt.errorType != "" {
 					tokenErr, ok := err.(*errors.EchoPayError)
@@ -1236,10 +1749,11 @@ func TestTokenService_GetTokensByStatus(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, tokens)
+				assert.NotNil(t, page)
+				assert.Equal(t, len(page.Tokens), page.Total)
 				
 				// Verify all tokens have the expected status
-				for _, token := range tokens {
+				for _, token := range page.Tokens {
 					assert.Equal(t, tt.status, token.Status)
 				}
 			}
@@ -1287,7 +1801,7 @@ func TestTokenService_GetTokenAuditTrail(t *testing.T) {
 			tokenID:     uuid.Nil,
 			setupMocks:  func(repo *MockTokenRepository) {},
 			expectError: true,
-			errorType:   errors.ErrInvalidTokenState,
+			errorType:   errors.ErrValidation,
 		},
 	}
 
@@ -1299,7 +1813,7 @@ func TestTokenService_GetTokenAuditTrail(t *testing.T) {
 
 			tt.setupMocks(mockRepo)
 
-			auditTrail, err := service.GetTokenAuditTrail(context.Background(), tt.tokenID)
+			auditTrail, err := service.GetTokenAuditTrail(context.Background(), tt.tokenID, false)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -1353,6 +1867,7 @@ func TestTokenService_ConcurrentTokenStateTransitions(t *testing.T) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil).Once()
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil).Once()
 				
 				// Second operation finds already frozen token
 				frozenToken := &models.Token{
@@ -1371,14 +1886,14 @@ func TestTokenService_ConcurrentTokenStateTransitions(t *testing.T) {
 				func(s *TokenService) error {
 					_, err := s.FreezeToken(context.Background(), FreezeTokenRequest{
 						TokenID: tokenID,
-						Reason:  "First freeze",
+						Reason:  FreezeReasonFraudInvestigation,
 					})
 					return err
 				},
 				func(s *TokenService) error {
 					_, err := s.FreezeToken(context.Background(), FreezeTokenRequest{
 						TokenID: tokenID,
-						Reason:  "Second freeze",
+						Reason:  FreezeReasonLegalHold,
 					})
 					return err
 				},
@@ -1412,24 +1927,27 @@ func TestTokenService_ConcurrentTokenStateTransitions(t *testing.T) {
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken, nil).Once()
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil).Once()
 				
 				// Unfreeze operation
 				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
 				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(frozenToken, nil).Once()
 				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "UNFREEZE", mock.Anything).Return(nil).Once()
+				repo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, (*time.Time)(nil)).Return(nil).Once()
 			},
 			operations: []func(*TokenService) error{
 				func(s *TokenService) error {
 					_, err := s.FreezeToken(context.Background(), FreezeTokenRequest{
 						TokenID: tokenID,
-						Reason:  "Freeze operation",
+						Reason:  FreezeReasonFraudInvestigation,
 					})
 					return err
 				},
 				func(s *TokenService) error {
 					_, err := s.UnfreezeToken(context.Background(), UnfreezeTokenRequest{
 						TokenID: tokenID,
-						Reason:  "Unfreeze operation",
+						Reason:  FreezeReasonInvestigationClosed,
 					})
 					return err
 				},
@@ -1486,11 +2004,11 @@ func TestTokenService_BulkOperationsWithMixedStates(t *testing.T) {
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1, tokenID2, tokenID3},
 				NewStatus: models.TokenStatusFrozen,
-				Reason:    "Emergency freeze",
+				Reason:    FreezeReasonSanctionsHit,
 			},
 			setupMocks: func(repo *MockTokenRepository) {
 				// Repository should handle the bulk update regardless of initial states
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2, tokenID3}, models.TokenStatusFrozen, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
@@ -1499,10 +2017,10 @@ func TestTokenService_BulkOperationsWithMixedStates(t *testing.T) {
 			request: BulkStatusUpdateRequest{
 				TokenIDs:  []uuid.UUID{tokenID1, tokenID2},
 				NewStatus: models.TokenStatusInvalid,
-				Reason:    "Security breach",
+				Reason:    FreezeReasonLegalHold,
 			},
 			setupMocks: func(repo *MockTokenRepository) {
-				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusInvalid).Return(nil)
+				repo.On("BulkUpdateStatus", mock.Anything, []uuid.UUID{tokenID1, tokenID2}, models.TokenStatusInvalid, mock.Anything).Return(nil)
 			},
 			expectError: false,
 		},
@@ -1563,10 +2081,11 @@ func TestTokenService_TimestampLogging(t *testing.T) {
 		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
 		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
 		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil)
 
 		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
 			TokenID: tokenID,
-			Reason:  "Test freeze",
+			Reason:  FreezeReasonCustomerRequest,
 		})
 
 		assert.NoError(t, err)
@@ -1576,4 +2095,1835 @@ func TestTokenService_TimestampLogging(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 		mockDB.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+func TestTokenService_RecallSeries(t *testing.T) {
+	issuer := "Federal Reserve"
+	series := "2025-A"
+
+	makeTokens := func(n int) []models.Token {
+		tokens := make([]models.Token, n)
+		for i := range tokens {
+			tokens[i] = models.Token{
+				TokenID:      uuid.New(),
+				CBDCType:     models.CBDCTypeUSD,
+				Denomination: 1.0,
+				CurrentOwner: uuid.New(),
+				Status:       models.TokenStatusActive,
+			}
+		}
+		return tokens
+	}
+
+	t.Run("recalls all tokens in a single page", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		tokens := makeTokens(3)
+		ids := []uuid.UUID{tokens[0].TokenID, tokens[1].TokenID, tokens[2].TokenID}
+
+		mockRepo.On("GetActiveBySeries", mock.Anything, issuer, series, uuid.Nil, MaxRecallBatchSize+1).Return(tokens, nil)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("RecallWithTx", mock.Anything, mock.Anything, ids, "security flaw").Return(3, nil)
+
+		ctx := WithIssuerScope(context.Background(), issuer)
+		response, err := service.RecallSeries(ctx, issuer, series, "security flaw", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, response.RecalledCount)
+		assert.Empty(t, response.Continuation)
+
+		mockRepo.AssertExpectations(t)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("large recall returns continuation", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		tokens := makeTokens(MaxRecallBatchSize + 1)
+		lastRecalled := tokens[MaxRecallBatchSize-1].TokenID
+
+		mockRepo.On("GetActiveBySeries", mock.Anything, issuer, series, uuid.Nil, MaxRecallBatchSize+1).Return(tokens, nil)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("RecallWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(ids []uuid.UUID) bool {
+			return len(ids) == MaxRecallBatchSize
+		}), "security flaw").Return(MaxRecallBatchSize, nil)
+
+		ctx := WithIssuerScope(context.Background(), issuer)
+		response, err := service.RecallSeries(ctx, issuer, series, "security flaw", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, MaxRecallBatchSize, response.RecalledCount)
+		assert.Equal(t, lastRecalled.String(), response.Continuation)
+
+		// Next page resumes after the continuation token
+		mockRepo.On("GetActiveBySeries", mock.Anything, issuer, series, lastRecalled, MaxRecallBatchSize+1).Return([]models.Token{}, nil)
+		mockRepo.On("RecallWithTx", mock.Anything, mock.Anything, []uuid.UUID{}, "security flaw").Return(0, nil)
+
+		response, err = service.RecallSeries(ctx, issuer, series, "security flaw", response.Continuation)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, response.RecalledCount)
+		assert.Empty(t, response.Continuation)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		issuer       string
+		reason       string
+		continuation string
+		errorType    string
+	}{
+		{
+			name:      "missing issuer scope",
+			ctx:       context.Background(),
+			issuer:    issuer,
+			reason:    "security flaw",
+			errorType: errors.ErrAuthorizationFailed,
+		},
+		{
+			name:      "issuer scope mismatch",
+			ctx:       WithIssuerScope(context.Background(), "Bank of England"),
+			issuer:    issuer,
+			reason:    "security flaw",
+			errorType: errors.ErrAuthorizationFailed,
+		},
+		{
+			name:      "missing reason",
+			ctx:       WithIssuerScope(context.Background(), issuer),
+			issuer:    issuer,
+			reason:    "",
+			errorType: errors.ErrValidation,
+		},
+		{
+			name:         "invalid continuation token",
+			ctx:          WithIssuerScope(context.Background(), issuer),
+			issuer:       issuer,
+			reason:       "security flaw",
+			continuation: "not-a-uuid",
+			errorType:    errors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+
+			response, err := service.RecallSeries(tt.ctx, tt.issuer, series, tt.reason, tt.continuation)
+
+			assert.Error(t, err)
+			assert.Nil(t, response)
+
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, tt.errorType, tokenErr.Code)
+
+			mockRepo.AssertNotCalled(t, "GetActiveBySeries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTokenService_VerifyTokenProof(t *testing.T) {
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	req := IssueTokenRequest{
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 10.0,
+		Owner:        uuid.New(),
+		Issuer:       "Federal Reserve",
+		Series:       "2025-A",
+		Quantity:     3,
+	}
+
+	// Capture the batch root and proofs persisted at issuance
+	var batchID uuid.UUID
+	var root string
+	var proofs []repository.TokenMerkleProof
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("string"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			batchID = args.Get(2).(uuid.UUID)
+			root = args.Get(3).(string)
+			proofs = args.Get(4).([]repository.TokenMerkleProof)
+		}).Return(nil)
+
+	response, err := service.IssueTokens(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Len(t, proofs, 3)
+
+	token := response.Tokens[1]
+	storedProof := &repository.TokenMerkleProof{
+		TokenID:   token.TokenID,
+		BatchID:   batchID,
+		LeafIndex: proofs[1].LeafIndex,
+		Path:      proofs[1].Path,
+		BatchRoot: root,
+	}
+
+	t.Run("untampered token verifies", func(t *testing.T) {
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetMerkleProof", mock.Anything, token.TokenID).Return(storedProof, nil).Once()
+
+		valid, gotRoot, err := service.VerifyTokenProof(context.Background(), token.TokenID)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.Equal(t, root, gotRoot)
+	})
+
+	t.Run("tampered denomination fails verification", func(t *testing.T) {
+		tampered := token
+		tampered.Denomination = 1000.0
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&tampered, nil).Once()
+		mockRepo.On("GetMerkleProof", mock.Anything, token.TokenID).Return(storedProof, nil).Once()
+
+		valid, gotRoot, err := service.VerifyTokenProof(context.Background(), token.TokenID)
+
+		assert.NoError(t, err)
+		assert.False(t, valid)
+		assert.Equal(t, root, gotRoot)
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetMerkleProof", mock.Anything, token.TokenID).Return(nil, nil).Once()
+
+		valid, _, err := service.VerifyTokenProof(context.Background(), token.TokenID)
+
+		assert.Error(t, err)
+		assert.False(t, valid)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenService_VerifyTokenSignature(t *testing.T) {
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+	oldPublicKey, oldPrivateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	_, newPrivateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	service.SetSigningKeySource(NewStaticKeySource("fed-2025-01", oldPrivateKey))
+
+	req := IssueTokenRequest{
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 10.0,
+		Owner:        uuid.New(),
+		Issuer:       "Federal Reserve",
+		Series:       "2025-A",
+		Quantity:     1,
+	}
+
+	// Capture the signature persisted at issuance
+	var signature *repository.TokenSignature
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SaveSignatureWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*repository.TokenSignature")).
+		Run(func(args mock.Arguments) {
+			signature = args.Get(2).(*repository.TokenSignature)
+		}).Return(nil)
+
+	response, err := service.IssueTokens(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotNil(t, signature)
+	assert.Equal(t, "fed-2025-01", signature.KeyID)
+	assert.Equal(t, SignatureAlgorithm, signature.Algorithm)
+
+	token := response.Tokens[0]
+
+	t.Run("valid signature with supplied public key", func(t *testing.T) {
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signature, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, oldPublicKey)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("tampered denomination fails verification", func(t *testing.T) {
+		tampered := token
+		tampered.Denomination = 1000.0
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&tampered, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signature, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, oldPublicKey)
+
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("tampered issuance owner fails verification", func(t *testing.T) {
+		tampered := *signature
+		tampered.IssuedTo = uuid.New()
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(&tampered, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, oldPublicKey)
+
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("legacy signature without issuance owner", func(t *testing.T) {
+		legacy := *signature
+		legacy.IssuedTo = uuid.Nil
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(&legacy, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, oldPublicKey)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("transferred token still verifies", func(t *testing.T) {
+		held := token
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, token.TokenID).Return(&held, nil).Once()
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, req.Owner).Return(0, nil).Once()
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Once()
+
+		newOwner := uuid.New()
+		_, err := service.TransferToken(context.Background(), TransferTokenRequest{
+			TokenID:       token.TokenID,
+			NewOwner:      newOwner,
+			TransactionID: uuid.New(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, newOwner, held.CurrentOwner)
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&held, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signature, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, oldPublicKey)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("rotated key source still verifies old signatures", func(t *testing.T) {
+		rotated := NewStaticKeySource("fed-2025-02", newPrivateKey)
+		rotated.AddVerificationKey("fed-2025-01", oldPublicKey)
+		service.SetSigningKeySource(rotated)
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signature, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, nil)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("unknown key ID", func(t *testing.T) {
+		service.SetSigningKeySource(NewStaticKeySource("fed-2025-02", newPrivateKey))
+
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(&token, nil).Once()
+		mockRepo.On("GetSignature", mock.Anything, token.TokenID).Return(signature, nil).Once()
+
+		valid, err := service.VerifyTokenSignature(context.Background(), token.TokenID, nil)
+
+		assert.Error(t, err)
+		assert.False(t, valid)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenService_GetHoldings(t *testing.T) {
+	owner := uuid.New()
+	holdings := []repository.TokenHolding{
+		{CBDCType: models.CBDCTypeEUR, Status: models.TokenStatusActive, Count: 2, Total: 20.0},
+		{CBDCType: models.CBDCTypeUSD, Status: models.TokenStatusActive, Count: 3, Total: 150.0},
+		{CBDCType: models.CBDCTypeUSD, Status: models.TokenStatusFrozen, Count: 1, Total: 50.0},
+	}
+
+	tests := []struct {
+		name           string
+		ownerID        uuid.UUID
+		includeFrozen  bool
+		setupMocks     func(*MockTokenRepository)
+		expectError    bool
+		expectedActive int
+		expectedFrozen int
+	}{
+		{
+			name:    "active holdings only",
+			ownerID: owner,
+			setupMocks: func(repo *MockTokenRepository) {
+				repo.On("SumByOwner", mock.Anything, owner).Return(holdings, nil)
+			},
+			expectedActive: 2,
+			expectedFrozen: 0,
+		},
+		{
+			name:          "frozen holdings reported separately",
+			ownerID:       owner,
+			includeFrozen: true,
+			setupMocks: func(repo *MockTokenRepository) {
+				repo.On("SumByOwner", mock.Anything, owner).Return(holdings, nil)
+			},
+			expectedActive: 2,
+			expectedFrozen: 1,
+		},
+		{
+			name:    "no holdings",
+			ownerID: owner,
+			setupMocks: func(repo *MockTokenRepository) {
+				repo.On("SumByOwner", mock.Anything, owner).Return([]repository.TokenHolding{}, nil)
+			},
+			expectedActive: 0,
+			expectedFrozen: 0,
+		},
+		{
+			name:        "nil owner",
+			ownerID:     uuid.Nil,
+			setupMocks:  func(repo *MockTokenRepository) {},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+
+			tt.setupMocks(mockRepo)
+
+			response, err := service.GetHoldings(context.Background(), tt.ownerID, tt.includeFrozen)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, response)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, response.Holdings, tt.expectedActive)
+				assert.Len(t, response.Frozen, tt.expectedFrozen)
+
+				// Frozen tokens never contribute to active totals
+				for _, holding := range response.Holdings {
+					assert.Equal(t, models.TokenStatusActive, holding.Status)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenService_MultiSigTransfer(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	newOwner := uuid.New()
+	transactionID := uuid.New()
+	signer1 := uuid.New()
+	signer2 := uuid.New()
+
+	newActiveToken := func() *models.Token {
+		return &models.Token{
+			TokenID:      tokenID,
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusActive,
+		}
+	}
+
+	t.Run("transfer from multi-sig wallet is held pending", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(newActiveToken(), nil)
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, owner).Return(2, nil)
+		mockRepo.On("CreatePendingTransferWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p *repository.PendingTransfer) bool {
+			return p.TokenID == tokenID && p.FromOwner == owner && p.NewOwner == newOwner && p.RequiredSigners == 2
+		})).Return(nil)
+
+		response, err := service.TransferToken(context.Background(), TransferTokenRequest{
+			TokenID:       tokenID,
+			NewOwner:      newOwner,
+			TransactionID: transactionID,
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response.PendingTransferID)
+		assert.Equal(t, 2, response.RequiredSigners)
+		assert.Equal(t, owner, response.Token.CurrentOwner)
+
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	pendingWith := func(approvals ...uuid.UUID) *repository.PendingTransfer {
+		return &repository.PendingTransfer{
+			ID:              uuid.New(),
+			TokenID:         tokenID,
+			FromOwner:       owner,
+			NewOwner:        newOwner,
+			TransactionID:   transactionID,
+			RequiredSigners: 2,
+			Status:          repository.PendingTransferStatusPending,
+			Approvals:       approvals,
+		}
+	}
+
+	t.Run("first approval does not complete transfer", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		pending := pendingWith()
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+		mockRepo.On("IsCoSignerWithTx", mock.Anything, mock.Anything, owner, signer1).Return(true, nil)
+		mockRepo.On("AddTransferApprovalWithTx", mock.Anything, mock.Anything, pending.ID, signer1).Return(nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, signer1)
+
+		assert.NoError(t, err)
+		assert.False(t, response.Completed)
+		assert.Len(t, response.PendingTransfer.Approvals, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("final distinct approval completes transfer", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		pending := pendingWith(signer1)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+		mockRepo.On("IsCoSignerWithTx", mock.Anything, mock.Anything, owner, signer2).Return(true, nil)
+		mockRepo.On("AddTransferApprovalWithTx", mock.Anything, mock.Anything, pending.ID, signer2).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(newActiveToken(), nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("UpdatePendingTransferStatusWithTx", mock.Anything, mock.Anything, pending.ID, repository.PendingTransferStatusCompleted).Return(nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, signer2)
+
+		assert.NoError(t, err)
+		assert.True(t, response.Completed)
+		assert.Equal(t, newOwner, response.Token.CurrentOwner)
+		assert.Equal(t, repository.PendingTransferStatusCompleted, response.PendingTransfer.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate approval rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		pending := pendingWith(signer1)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, signer1)
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrInvalidTokenState, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "AddTransferApprovalWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("signer outside the wallet's co-signers rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		outsider := uuid.New()
+		pending := pendingWith(signer1)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+		mockRepo.On("IsCoSignerWithTx", mock.Anything, mock.Anything, owner, outsider).Return(false, nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, outsider)
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrAuthorizationFailed, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "AddTransferApprovalWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sending wallet cannot approve its own transfer", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		pending := pendingWith(signer1)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, owner)
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrAuthorizationFailed, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "IsCoSignerWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "AddTransferApprovalWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("token frozen before final approval", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		frozen := newActiveToken()
+		frozen.Status = models.TokenStatusFrozen
+
+		pending := pendingWith(signer1)
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+		mockRepo.On("IsCoSignerWithTx", mock.Anything, mock.Anything, owner, signer2).Return(true, nil)
+		mockRepo.On("AddTransferApprovalWithTx", mock.Anything, mock.Anything, pending.ID, signer2).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(frozen, nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, signer2)
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrTokenFrozen, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pending transfer not found", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		pendingID := uuid.New()
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pendingID).Return(nil, nil)
+
+		_, err := service.ApproveTransfer(context.Background(), pendingID, signer1)
+
+		assert.Error(t, err)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrTokenNotFound, tokenErr.Code)
+	})
+}
+
+func TestTokenService_SetWalletRequiredSigners(t *testing.T) {
+	walletID := uuid.New()
+	signer1 := uuid.New()
+	signer2 := uuid.New()
+
+	t.Run("policy stored with its co-signers", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, new(MockDatabase))
+
+		coSigners := []uuid.UUID{signer1, signer2}
+		mockRepo.On("SetRequiredSigners", mock.Anything, walletID, 2, coSigners).Return(nil)
+
+		assert.NoError(t, service.SetWalletRequiredSigners(context.Background(), walletID, 2, coSigners))
+		mockRepo.AssertExpectations(t)
+	})
+
+	invalid := map[string]struct {
+		required  int
+		coSigners []uuid.UUID
+	}{
+		"fewer co-signers than required": {2, []uuid.UUID{signer1}},
+		"duplicate co-signer":            {2, []uuid.UUID{signer1, signer1}},
+		"wallet co-signs for itself":     {1, []uuid.UUID{walletID}},
+		"co-signers without a policy":    {0, []uuid.UUID{signer1}},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, new(MockDatabase))
+
+			err := service.SetWalletRequiredSigners(context.Background(), walletID, tc.required, tc.coSigners)
+
+			assert.Error(t, err)
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+			mockRepo.AssertNotCalled(t, "SetRequiredSigners", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTokenService_UpdateComplianceFlags(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	cleared := models.ComplianceFlags{KYCVerified: true, AMLCleared: true, SanctionsChecked: true}
+
+	tests := []struct {
+		name           string
+		tokenID        uuid.UUID
+		currentStatus  models.TokenStatus
+		currentFlags   models.ComplianceFlags
+		newFlags       models.ComplianceFlags
+		tokenMissing   bool
+		expectError    bool
+		errorType      string
+		expectedStatus models.TokenStatus
+		autoFrozen     bool
+	}{
+		{
+			name:           "KYC verified after re-screen",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusActive,
+			currentFlags:   models.ComplianceFlags{AMLCleared: true, SanctionsChecked: true},
+			newFlags:       cleared,
+			expectedStatus: models.TokenStatusActive,
+		},
+		{
+			name:           "KYC verification revoked",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusActive,
+			currentFlags:   cleared,
+			newFlags:       models.ComplianceFlags{AMLCleared: true, SanctionsChecked: true},
+			expectedStatus: models.TokenStatusActive,
+		},
+		{
+			name:           "AML clearance revoked",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusActive,
+			currentFlags:   cleared,
+			newFlags:       models.ComplianceFlags{KYCVerified: true, SanctionsChecked: true},
+			expectedStatus: models.TokenStatusActive,
+		},
+		{
+			name:           "sanctions screening fails on active token",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusActive,
+			currentFlags:   cleared,
+			newFlags:       models.ComplianceFlags{KYCVerified: true, AMLCleared: true},
+			expectedStatus: models.TokenStatusFrozen,
+			autoFrozen:     true,
+		},
+		{
+			name:           "sanctions screening fails on already frozen token",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusFrozen,
+			currentFlags:   cleared,
+			newFlags:       models.ComplianceFlags{KYCVerified: true, AMLCleared: true},
+			expectedStatus: models.TokenStatusFrozen,
+		},
+		{
+			name:           "sanctions screening passes does not unfreeze",
+			tokenID:        tokenID,
+			currentStatus:  models.TokenStatusFrozen,
+			currentFlags:   models.ComplianceFlags{KYCVerified: true, AMLCleared: true},
+			newFlags:       cleared,
+			expectedStatus: models.TokenStatusFrozen,
+		},
+		{
+			name:          "invalid token",
+			tokenID:       tokenID,
+			currentStatus: models.TokenStatusInvalid,
+			currentFlags:  cleared,
+			newFlags:      models.ComplianceFlags{},
+			expectError:   true,
+			errorType:     errors.ErrInvalidTokenState,
+		},
+		{
+			name:         "token not found",
+			tokenID:      tokenID,
+			tokenMissing: true,
+			newFlags:     cleared,
+			expectError:  true,
+			errorType:    errors.ErrTokenNotFound,
+		},
+		{
+			name:        "nil token ID",
+			tokenID:     uuid.Nil,
+			newFlags:    cleared,
+			expectError: true,
+			errorType:   errors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+			tracker := events.NewTokenStatusTracker()
+			service.SetStatusTracker(tracker)
+			subscriber := tracker.Subscribe(events.TokenFilter{TokenIDs: []uuid.UUID{tokenID}})
+
+			if tt.tokenID != uuid.Nil {
+				mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				if tt.tokenMissing {
+					mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tt.tokenID).Return(nil, nil)
+				} else {
+					token := &models.Token{
+						TokenID:         tt.tokenID,
+						CBDCType:        models.CBDCTypeUSD,
+						Denomination:    100.0,
+						CurrentOwner:    owner,
+						Status:          tt.currentStatus,
+						ComplianceFlags: tt.currentFlags,
+						CreatedAt:       time.Now(),
+						UpdatedAt:       time.Now(),
+					}
+					mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tt.tokenID).Return(token, nil)
+				}
+				if !tt.expectError {
+					mockRepo.On("UpdateComplianceFlagsWithTx", mock.Anything, mock.Anything,
+						mock.MatchedBy(func(token *models.Token) bool {
+							return token.ComplianceFlags == tt.newFlags && token.Status == tt.expectedStatus
+						}),
+						tt.currentFlags, tt.currentStatus,
+					).Return(nil)
+				}
+			}
+
+			response, err := service.UpdateComplianceFlags(context.Background(), tt.tokenID, tt.newFlags)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, response)
+
+				tokenErr, ok := err.(*errors.EchoPayError)
+				assert.True(t, ok, "Expected EchoPayError")
+				assert.Equal(t, tt.errorType, tokenErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, response)
+				assert.Equal(t, tt.newFlags, response.Token.ComplianceFlags)
+				assert.Equal(t, tt.currentFlags, response.PreviousFlags)
+				assert.Equal(t, tt.expectedStatus, response.Token.Status)
+				assert.Equal(t, tt.autoFrozen, response.AutoFrozen)
+			}
+
+			// Only an automatic freeze is announced
+			if tt.autoFrozen {
+				require.Len(t, subscriber.Channel, 1)
+				event := <-subscriber.Channel
+				assert.Equal(t, events.TokenEventFrozen, event.Type)
+				assert.Equal(t, owner, event.OwnerID)
+			} else {
+				assert.Empty(t, subscriber.Channel)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenService_SanctionsScreening(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	newOwner := uuid.New()
+	transactionID := uuid.New()
+
+	newActiveToken := func() *models.Token {
+		return &models.Token{
+			TokenID:      tokenID,
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusActive,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		listed       map[uuid.UUID]string
+		blockedParty uuid.UUID
+		listRef      string
+	}{
+		{
+			name:         "sanctioned new owner",
+			listed:       map[uuid.UUID]string{newOwner: "OFAC-SDN-1234"},
+			blockedParty: newOwner,
+			listRef:      "OFAC-SDN-1234",
+		},
+		{
+			name:         "sanctioned current owner",
+			listed:       map[uuid.UUID]string{owner: "EU-CFSP-77"},
+			blockedParty: owner,
+			listRef:      "EU-CFSP-77",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+			service.SetSanctionsScreener(NewMapSanctionsScreener(tt.listed), 0)
+
+			mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+			mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(newActiveToken(), nil)
+			mockRepo.On("RecordSanctionsBlockWithTx", mock.Anything, mock.Anything, &repository.SanctionsBlock{
+				TokenID:      tokenID,
+				FromOwner:    owner,
+				ToOwner:      newOwner,
+				BlockedParty: tt.blockedParty,
+				ListRef:      tt.listRef,
+			}).Return(nil)
+
+			response, err := service.TransferToken(context.Background(), TransferTokenRequest{
+				TokenID:       tokenID,
+				NewOwner:      newOwner,
+				TransactionID: transactionID,
+			})
+
+			assert.Error(t, err)
+			assert.Nil(t, response)
+
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrSanctionsBlocked, tokenErr.Code)
+
+			mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("clear parties transfer normally", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetSanctionsScreener(NewMapSanctionsScreener(map[uuid.UUID]string{uuid.New(): "OFAC-SDN-1"}), time.Minute)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(newActiveToken(), nil)
+		mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, owner).Return(0, nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+
+		response, err := service.TransferToken(context.Background(), TransferTokenRequest{
+			TokenID:       tokenID,
+			NewOwner:      newOwner,
+			TransactionID: transactionID,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newOwner, response.Token.CurrentOwner)
+		mockRepo.AssertNotCalled(t, "RecordSanctionsBlockWithTx", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("pending transfer is rejected when new owner is listed before approval", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+		service.SetSanctionsScreener(NewMapSanctionsScreener(map[uuid.UUID]string{newOwner: "UN-1267"}), 0)
+
+		signer := uuid.New()
+		pending := &repository.PendingTransfer{
+			ID:              uuid.New(),
+			TokenID:         tokenID,
+			FromOwner:       owner,
+			NewOwner:        newOwner,
+			TransactionID:   transactionID,
+			RequiredSigners: 1,
+			Status:          repository.PendingTransferStatusPending,
+		}
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetPendingTransferWithTx", mock.Anything, mock.Anything, pending.ID).Return(pending, nil)
+		mockRepo.On("IsCoSignerWithTx", mock.Anything, mock.Anything, owner, signer).Return(true, nil)
+		mockRepo.On("AddTransferApprovalWithTx", mock.Anything, mock.Anything, pending.ID, signer).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(newActiveToken(), nil)
+		mockRepo.On("RecordSanctionsBlockWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(block *repository.SanctionsBlock) bool {
+			return block.BlockedParty == newOwner && block.ListRef == "UN-1267"
+		})).Return(nil)
+		mockRepo.On("UpdatePendingTransferStatusWithTx", mock.Anything, mock.Anything, pending.ID, repository.PendingTransferStatusRejected).Return(nil)
+
+		response, err := service.ApproveTransfer(context.Background(), pending.ID, signer)
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrSanctionsBlocked, tokenErr.Code)
+
+		mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTokenService_TokenPagination(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name           string
+		limit          int
+		offset         int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{
+			name:           "explicit limit and offset",
+			limit:          25,
+			offset:         50,
+			expectedLimit:  25,
+			expectedOffset: 50,
+		},
+		{
+			name:           "default page size",
+			limit:          0,
+			offset:         0,
+			expectedLimit:  repository.DefaultTokenPageSize,
+			expectedOffset: 0,
+		},
+		{
+			name:           "limit capped at maximum",
+			limit:          50000,
+			offset:         10,
+			expectedLimit:  repository.MaxTokenPageSize,
+			expectedOffset: 10,
+		},
+		{
+			name:           "negative offset clamped",
+			limit:          10,
+			offset:         -5,
+			expectedLimit:  10,
+			expectedOffset: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("by status "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+
+			tokens := []models.Token{{TokenID: uuid.New(), Status: models.TokenStatusActive}}
+			mockRepo.On("GetByStatus", mock.Anything, models.TokenStatusActive, tt.expectedLimit, tt.expectedOffset).Return(tokens, nil)
+			mockRepo.On("CountByStatus", mock.Anything, models.TokenStatusActive).Return(5000, nil)
+
+			page, err := service.GetTokensByStatus(context.Background(), models.TokenStatusActive, tt.limit, tt.offset)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tokens, page.Tokens)
+			assert.Equal(t, 5000, page.Total)
+			assert.Equal(t, tt.expectedLimit, page.Limit)
+			assert.Equal(t, tt.expectedOffset, page.Offset)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("by owner "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+
+			tokens := []models.Token{{TokenID: uuid.New(), CurrentOwner: owner}}
+			mockRepo.On("GetByOwner", mock.Anything, owner, tt.expectedLimit, tt.expectedOffset).Return(tokens, nil)
+			mockRepo.On("CountByOwner", mock.Anything, owner).Return(42, nil)
+
+			page, err := service.GetTokensByOwner(context.Background(), owner, tt.limit, tt.offset)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tokens, page.Tokens)
+			assert.Equal(t, 42, page.Total)
+			assert.Equal(t, tt.expectedLimit, page.Limit)
+			assert.Equal(t, tt.expectedOffset, page.Offset)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("all tokens by owner reads every page", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		fullPage := make([]models.Token, repository.MaxTokenPageSize)
+		lastPage := []models.Token{{TokenID: uuid.New(), CurrentOwner: owner}}
+		mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, 0).Return(fullPage, nil)
+		mockRepo.On("GetByOwner", mock.Anything, owner, repository.MaxTokenPageSize, repository.MaxTokenPageSize).Return(lastPage, nil)
+
+		tokens, err := service.GetAllTokensByOwner(context.Background(), owner)
+
+		assert.NoError(t, err)
+		assert.Len(t, tokens, repository.MaxTokenPageSize+1)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTokenService_IssuanceQuota(t *testing.T) {
+	request := IssueTokenRequest{
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		Owner:        uuid.New(),
+		Issuer:       "Federal Reserve",
+		Series:       "2025-A",
+		Quantity:     5,
+	}
+
+	quotaWith := func(minted, quota float64) *repository.IssuerQuota {
+		return &repository.IssuerQuota{
+			Issuer:      request.Issuer,
+			Series:      request.Series,
+			CBDCType:    request.CBDCType,
+			MintedTotal: minted,
+			Quota:       quota,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		quota       *repository.IssuerQuota
+		expectMint  bool
+		expectError string
+	}{
+		{
+			name:       "issuance within quota updates minted total",
+			quota:      quotaWith(500, 1000),
+			expectMint: true,
+		},
+		{
+			name:       "issuance exactly reaching quota",
+			quota:      quotaWith(499.9, 999.9),
+			expectMint: true,
+		},
+		{
+			name:        "issuance exceeding remaining quota is rejected",
+			quota:       quotaWith(600, 1000),
+			expectError: errors.ErrQuotaExceeded,
+		},
+		{
+			name:       "no quota configured",
+			quota:      nil,
+			expectMint: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+			mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+			if tt.quota != nil {
+				mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, request.Issuer, request.Series, request.CBDCType).Return(tt.quota, nil).Once()
+			} else {
+				mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, request.Issuer, request.Series, request.CBDCType).Return(nil, nil).Once()
+			}
+			if tt.expectMint {
+				if tt.quota != nil {
+					mockRepo.On("AddMintedTotalWithTx", mock.Anything, mock.Anything, request.Issuer, request.Series, request.CBDCType, 500.0).Return(nil).Once()
+				}
+				mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Times(5)
+				mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			response, err := service.IssueTokens(context.Background(), request)
+
+			if tt.expectError != "" {
+				assert.Nil(t, response)
+				tokenErr, ok := err.(*errors.EchoPayError)
+				assert.True(t, ok, "Expected EchoPayError")
+				assert.Equal(t, tt.expectError, tokenErr.Code)
+				mockRepo.AssertNotCalled(t, "AddMintedTotalWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				mockRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 5, response.Count)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockDB.AssertExpectations(t)
+		})
+	}
+
+	t.Run("quota status reports remaining value", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		mockRepo.On("GetIssuerQuotas", mock.Anything, request.Issuer).Return([]repository.IssuerQuota{
+			*quotaWith(250.5, 1000),
+			*quotaWith(1200, 1000),
+		}, nil)
+
+		quotas, err := service.GetIssuerQuotas(context.Background(), request.Issuer)
+
+		assert.NoError(t, err)
+		assert.Len(t, quotas, 2)
+		assert.Equal(t, 749.5, quotas[0].Remaining)
+		assert.Equal(t, 0.0, quotas[1].Remaining)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("set quota rejects negative values", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		quota, err := service.SetIssuerQuota(context.Background(), request.Issuer, request.Series, request.CBDCType, -1)
+
+		assert.Nil(t, quota)
+		tokenErr, ok := err.(*errors.EchoPayError)
+		assert.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "SetIssuerQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTokenService_IssueBatch(t *testing.T) {
+	owner := uuid.New()
+	baseRequest := func(lines ...BatchIssueLine) BatchIssueRequest {
+		return BatchIssueRequest{
+			CBDCType: models.CBDCTypeUSD,
+			Owner:    owner,
+			Issuer:   "Federal Reserve",
+			Series:   "2025-A",
+			Lines:    lines,
+		}
+	}
+
+	t.Run("mixed denominations minted in one transaction", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
+		mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, "Federal Reserve", "2025-A", models.CBDCTypeUSD).
+			Return(&repository.IssuerQuota{Quota: 10000}, nil).Once()
+		mockRepo.On("AddMintedTotalWithTx", mock.Anything, mock.Anything, "Federal Reserve", "2025-A", models.CBDCTypeUSD, 1450.0).Return(nil).Once()
+		mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Times(35)
+		mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(proofs []repository.TokenMerkleProof) bool {
+			return len(proofs) == 35
+		})).Return(nil).Once()
+
+		response, err := service.IssueBatch(context.Background(), baseRequest(
+			BatchIssueLine{Denomination: 100, Quantity: 10},
+			BatchIssueLine{Denomination: 50, Quantity: 5},
+			BatchIssueLine{Denomination: 10, Quantity: 20},
+		))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 35, response.Count)
+		assert.Len(t, response.Tokens, 35)
+		assert.Equal(t, []DenominationCount{
+			{Denomination: 100, Count: 10},
+			{Denomination: 50, Count: 5},
+			{Denomination: 10, Count: 20},
+		}, response.DenominationCounts)
+		for _, token := range response.Tokens {
+			assert.Equal(t, owner, token.CurrentOwner)
+			assert.Equal(t, "2025-A", token.Metadata.Series)
+		}
+
+		mockRepo.AssertExpectations(t)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("repeated denominations are aggregated", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil).Once()
+		mockRepo.On("GetIssuerQuotaForUpdateWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mockRepo.On("CreateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil).Times(5)
+		mockRepo.On("SaveMerkleBatchWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		response, err := service.IssueBatch(context.Background(), baseRequest(
+			BatchIssueLine{Denomination: 20, Quantity: 2},
+			BatchIssueLine{Denomination: 20, Quantity: 3},
+		))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DenominationCount{{Denomination: 20, Count: 5}}, response.DenominationCounts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name    string
+		request BatchIssueRequest
+	}{
+		{
+			name:    "no lines",
+			request: baseRequest(),
+		},
+		{
+			name:    "invalid line denomination",
+			request: baseRequest(BatchIssueLine{Denomination: 100, Quantity: 1}, BatchIssueLine{Denomination: 0, Quantity: 1}),
+		},
+		{
+			name:    "total exceeds cap across lines",
+			request: baseRequest(BatchIssueLine{Denomination: 100, Quantity: 600}, BatchIssueLine{Denomination: 50, Quantity: 401}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+			response, err := service.IssueBatch(context.Background(), tt.request)
+
+			assert.Nil(t, response)
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+			mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+		})
+	}
+}
+
+func TestTokenService_VerifyAuditTrail(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	newOwner := uuid.New()
+
+	// buildChain links entries the same way the repository does when writing them
+	buildChain := func(entries []repository.TokenAuditEntry) []repository.TokenAuditEntry {
+		previousHash := ""
+		for i := range entries {
+			entries[i].ID = uuid.New()
+			entries[i].TokenID = tokenID
+			entries[i].Sequence = int64(i + 1)
+			entries[i].PreviousHash = previousHash
+			entries[i].Timestamp = sql.NullTime{Time: time.Now().UTC().Add(time.Duration(i) * time.Second), Valid: true}
+			hash, err := repository.AuditEntryHash(entries[i])
+			assert.NoError(t, err)
+			entries[i].EntryHash = hash
+			previousHash = hash
+		}
+		return entries
+	}
+
+	newChain := func() []repository.TokenAuditEntry {
+		return buildChain([]repository.TokenAuditEntry{
+			{Operation: "CREATE", NewStatus: models.TokenStatusActive, NewOwner: owner},
+			{Operation: "OWNERSHIP_TRANSFER", OldOwner: owner, NewOwner: newOwner},
+			{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusActive, NewStatus: models.TokenStatusFrozen, Metadata: map[string]interface{}{
+				"reason": "fraud review",
+			}},
+		})
+	}
+
+	tests := []struct {
+		name           string
+		chain          func() []repository.TokenAuditEntry
+		expectValid    bool
+		expectVerified int
+		expectUnsigned int
+		expectBrokenAt int64
+	}{
+		{
+			name:           "intact chain",
+			chain:          newChain,
+			expectValid:    true,
+			expectVerified: 3,
+		},
+		{
+			name: "tampered middle entry",
+			chain: func() []repository.TokenAuditEntry {
+				chain := newChain()
+				chain[1].NewOwner = uuid.New()
+				return chain
+			},
+			expectValid:    false,
+			expectVerified: 1,
+			expectBrokenAt: 2,
+		},
+		{
+			name: "tampered middle entry with recomputed hash",
+			chain: func() []repository.TokenAuditEntry {
+				chain := newChain()
+				chain[1].NewOwner = uuid.New()
+				chain[1].EntryHash, _ = repository.AuditEntryHash(chain[1])
+				return chain
+			},
+			expectValid:    false,
+			expectVerified: 2,
+			expectBrokenAt: 3,
+		},
+		{
+			name: "deleted middle entry",
+			chain: func() []repository.TokenAuditEntry {
+				chain := newChain()
+				return append(chain[:1], chain[2:]...)
+			},
+			expectValid:    false,
+			expectVerified: 1,
+			expectBrokenAt: 3,
+		},
+		{
+			name: "unsigned legacy entries are skipped",
+			chain: func() []repository.TokenAuditEntry {
+				legacy := repository.TokenAuditEntry{ID: uuid.New(), TokenID: tokenID, Operation: "CREATE"}
+				return append([]repository.TokenAuditEntry{legacy}, newChain()...)
+			},
+			expectValid:    true,
+			expectVerified: 3,
+			expectUnsigned: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			service := NewTokenServiceWithDeps(mockRepo, nil)
+
+			mockRepo.On("GetAuditChain", mock.Anything, tokenID).Return(tt.chain(), nil)
+
+			result, err := service.VerifyAuditTrail(context.Background(), tokenID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectValid, result.Valid)
+			assert.Equal(t, tt.expectVerified, result.EntriesVerified)
+			assert.Equal(t, tt.expectUnsigned, result.UnsignedEntries)
+			if tt.expectValid {
+				assert.Nil(t, result.BrokenEntryID)
+			} else {
+				assert.NotNil(t, result.BrokenEntryID)
+				assert.Equal(t, tt.expectBrokenAt, result.BrokenSequence)
+				assert.NotEmpty(t, result.Reason)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("metadata hash survives JSON round trip", func(t *testing.T) {
+		entry := repository.TokenAuditEntry{
+			ID:        uuid.New(),
+			TokenID:   tokenID,
+			Operation: "COMPLIANCE_UPDATE",
+			Timestamp: sql.NullTime{Time: time.Now(), Valid: true},
+			Metadata: map[string]interface{}{
+				"new_flags":   models.ComplianceFlags{KYCVerified: true},
+				"auto_frozen": false,
+			},
+		}
+		original, err := repository.AuditEntryHash(entry)
+		assert.NoError(t, err)
+
+		// Simulate reading the metadata back from JSONB
+		encoded, err := json.Marshal(entry.Metadata)
+		assert.NoError(t, err)
+		var decoded map[string]interface{}
+		assert.NoError(t, json.Unmarshal(encoded, &decoded))
+		entry.Metadata = decoded
+
+		roundTripped, err := repository.AuditEntryHash(entry)
+		assert.NoError(t, err)
+		assert.Equal(t, original, roundTripped)
+	})
+}
+
+func TestTokenService_OptimisticConcurrency(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+
+	// Each read returns a fresh copy, as a real repository would
+	activeToken := func(version int64) *models.Token {
+		return &models.Token{
+			TokenID:      tokenID,
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusActive,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+			Version:      version,
+		}
+	}
+	staleWrite := errors.NewTokenManagementError(errors.ErrConcurrentModification, "token was modified concurrently")
+
+	tests := []struct {
+		name         string
+		setupMocks   func(*MockTokenRepository, *MockDatabase)
+		expectError  bool
+		errorType    string
+		transactions int
+	}{
+		{
+			name: "stale write is retried against fresh state",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken(1), nil).Once()
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken(2), nil).Once()
+				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+					return token.Version == 1
+				})).Return(staleWrite).Once()
+				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+					return token.Version == 2
+				})).Return(nil).Once()
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil).Once()
+			},
+			expectError:  false,
+			transactions: 2,
+		},
+		{
+			name: "stale write is rejected after one retry",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken(1), nil).Once()
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken(1), nil).Once()
+				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(staleWrite).Twice()
+			},
+			expectError:  true,
+			errorType:    errors.ErrConcurrentModification,
+			transactions: 2,
+		},
+		{
+			name: "other update failures are not retried",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(activeToken(1), nil).Once()
+				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(sql.ErrConnDone).Once()
+			},
+			expectError:  true,
+			errorType:    errors.ErrTransactionFailed,
+			transactions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+			tt.setupMocks(mockRepo, mockDB)
+
+			response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+				TokenID: tokenID,
+				Reason:  FreezeReasonFraudInvestigation,
+			})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, response)
+
+				tokenErr, ok := err.(*errors.EchoPayError)
+				assert.True(t, ok, "Expected EchoPayError")
+				assert.Equal(t, tt.errorType, tokenErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, response)
+				assert.Equal(t, models.TokenStatusFrozen, response.Token.Status)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockDB.AssertNumberOfCalls(t, "Transaction", tt.transactions)
+		})
+	}
+}
+
+func TestTokenService_FreezeTokenWithDuration(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+
+	activeToken := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: owner,
+		Status:       models.TokenStatusActive,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	t.Run("duration sets freeze expiry", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		token := *activeToken
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(&token, nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil)
+		mockRepo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, mock.MatchedBy(func(until *time.Time) bool {
+			return until != nil && until.Sub(time.Now()) > 71*time.Hour
+		})).Return(nil)
+
+		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+			TokenID:  tokenID,
+			Reason:   FreezeReasonLegalHold,
+			Duration: "72h",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.TokenStatusFrozen, response.Token.Status)
+		if assert.NotNil(t, response.FrozenUntil) {
+			assert.Equal(t, 72*time.Hour, response.FrozenUntil.Sub(response.FrozenAt))
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no duration freezes indefinitely", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		token := *activeToken
+		mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+		mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(&token, nil)
+		mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+		mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "FREEZE", mock.Anything).Return(nil)
+
+		response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+			TokenID: tokenID,
+			Reason:  FreezeReasonFraudInvestigation,
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, response.FrozenUntil)
+		mockRepo.AssertNotCalled(t, "SetFrozenUntilWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid duration is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		for _, duration := range []string{"soon", "-1h", "0s"} {
+			response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+				TokenID:  tokenID,
+				Duration: duration,
+			})
+
+			assert.Error(t, err, "duration %q", duration)
+			assert.Nil(t, response)
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		}
+		mockDB.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+}
+
+func TestTokenService_FreezeToken_FakeClock(t *testing.T) {
+	tokenID := uuid.New()
+	frozenAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	frozenUntil := frozenAt.Add(72 * time.Hour)
+	fakeClock := clock.NewFake(frozenAt)
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	service.SetClock(fakeClock)
+
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: uuid.New(),
+		Status:       models.TokenStatusActive,
+	}
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+	mockRepo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, &frozenUntil).Return(nil).Once()
+
+	response, err := service.FreezeToken(context.Background(), FreezeTokenRequest{
+		TokenID:  tokenID,
+		Reason:   FreezeReasonLegalHold,
+		Duration: "72h",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, frozenAt, response.FrozenAt)
+	assert.Equal(t, &frozenUntil, response.FrozenUntil)
+
+	// One nanosecond before expiry the sweep leaves the token frozen
+	fakeClock.Set(frozenUntil.Add(-time.Nanosecond))
+	mockRepo.On("GetExpiredFreezes", mock.Anything, mock.Anything, autoUnfreezeBatchSize).Return([]uuid.UUID{tokenID}, nil)
+	mockRepo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID).Return(&frozenUntil, nil)
+
+	sweep, err := service.AutoUnfreeze(context.Background(), fakeClock.Now())
+	require.NoError(t, err)
+	assert.Empty(t, sweep.UnfrozenTokenIDs)
+	assert.Equal(t, models.TokenStatusFrozen, token.Status)
+
+	// At the expiry instant it is lifted
+	fakeClock.Advance(time.Nanosecond)
+	mockRepo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, (*time.Time)(nil)).Return(nil)
+
+	sweep, err = service.AutoUnfreeze(context.Background(), fakeClock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{tokenID}, sweep.UnfrozenTokenIDs)
+	assert.Equal(t, frozenUntil, sweep.SweptAt)
+	assert.Equal(t, models.TokenStatusActive, token.Status)
+}
+
+func TestTokenService_AutoUnfreeze(t *testing.T) {
+	tokenID := uuid.New()
+	owner := uuid.New()
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	notExpired := now.Add(time.Hour)
+
+	frozenToken := func() *models.Token {
+		return &models.Token{
+			TokenID:      tokenID,
+			CBDCType:     models.CBDCTypeUSD,
+			Denomination: 100.0,
+			CurrentOwner: owner,
+			Status:       models.TokenStatusFrozen,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*MockTokenRepository, *MockDatabase)
+		expectUnfrozen []uuid.UUID
+		expectFailed   []uuid.UUID
+	}{
+		{
+			name: "expired freeze is lifted",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetExpiredFreezes", mock.Anything, now, autoUnfreezeBatchSize).Return([]uuid.UUID{tokenID}, nil)
+				repo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID).Return(&expired, nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(frozenToken(), nil)
+				repo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.MatchedBy(func(token *models.Token) bool {
+					return token.Status == models.TokenStatusActive
+				})).Return(nil)
+				repo.On("CreateAuditEntryWithTx", mock.Anything, mock.Anything, tokenID, "UNFREEZE", map[string]interface{}{"reason_code": string(FreezeReasonExpired)}).Return(nil)
+				repo.On("SetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID, (*time.Time)(nil)).Return(nil)
+			},
+			expectUnfrozen: []uuid.UUID{tokenID},
+		},
+		{
+			name: "freeze extended since listing is not yet expired",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetExpiredFreezes", mock.Anything, now, autoUnfreezeBatchSize).Return([]uuid.UUID{tokenID}, nil)
+				repo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID).Return(&notExpired, nil)
+			},
+			expectUnfrozen: []uuid.UUID{},
+		},
+		{
+			name: "indefinite freeze stays frozen",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetExpiredFreezes", mock.Anything, now, autoUnfreezeBatchSize).Return([]uuid.UUID{tokenID}, nil)
+				repo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID).Return(nil, nil)
+			},
+			expectUnfrozen: []uuid.UUID{},
+		},
+		{
+			name: "failed unfreeze is reported",
+			setupMocks: func(repo *MockTokenRepository, db *MockDatabase) {
+				db.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				repo.On("GetExpiredFreezes", mock.Anything, now, autoUnfreezeBatchSize).Return([]uuid.UUID{tokenID}, nil)
+				repo.On("GetFrozenUntilWithTx", mock.Anything, mock.Anything, tokenID).Return(&expired, nil)
+				repo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(nil, sql.ErrConnDone)
+			},
+			expectUnfrozen: []uuid.UUID{},
+			expectFailed:   []uuid.UUID{tokenID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTokenRepository)
+			mockDB := new(MockDatabase)
+
+			service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+			tt.setupMocks(mockRepo, mockDB)
+
+			response, err := service.AutoUnfreeze(context.Background(), now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectUnfrozen, response.UnfrozenTokenIDs)
+			assert.Equal(t, tt.expectFailed, response.FailedTokenIDs)
+			assert.Equal(t, now, response.SweptAt)
+
+			mockRepo.AssertExpectations(t)
+			if len(tt.expectUnfrozen) == 0 {
+				mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestTokenService_GetFreezeReport(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns a page of freeze events with the total", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		events := []repository.FreezeEvent{
+			{AuditID: uuid.New(), TokenID: uuid.New(), Operation: "STATUS_CHANGE", FrozenAt: from.Add(time.Hour), ReasonCode: string(FreezeReasonSanctionsHit)},
+			{AuditID: uuid.New(), TokenID: uuid.New(), Operation: "BULK_STATUS_UPDATE", FrozenAt: from.Add(2 * time.Hour)},
+		}
+		mockRepo.On("GetFreezeEventsBetween", mock.Anything, from, to, repository.MaxTokenPageSize, 0).Return(events, nil)
+		mockRepo.On("CountFreezeEventsBetween", mock.Anything, from, to).Return(7, nil)
+
+		report, err := service.GetFreezeReport(context.Background(), from, to, repository.MaxTokenPageSize+1, -5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, events, report.Events)
+		assert.Equal(t, 7, report.Total)
+		assert.Equal(t, repository.MaxTokenPageSize, report.Limit)
+		assert.Equal(t, 0, report.Offset)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty period returns no events", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		mockRepo.On("GetFreezeEventsBetween", mock.Anything, from, to, repository.DefaultTokenPageSize, 0).Return(nil, nil)
+		mockRepo.On("CountFreezeEventsBetween", mock.Anything, from, to).Return(0, nil)
+
+		report, err := service.GetFreezeReport(context.Background(), from, to, 0, 0)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, report.Events)
+		assert.Empty(t, report.Events)
+	})
+
+	t.Run("invalid periods are rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		mockDB := new(MockDatabase)
+		service := NewTokenServiceWithDeps(mockRepo, mockDB)
+
+		for _, period := range [][2]time.Time{{time.Time{}, to}, {from, time.Time{}}, {to, from}, {from, from}} {
+			report, err := service.GetFreezeReport(context.Background(), period[0], period[1], 10, 0)
+
+			assert.Nil(t, report)
+			tokenErr, ok := err.(*errors.EchoPayError)
+			assert.True(t, ok, "Expected EchoPayError")
+			assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		}
+		mockRepo.AssertNotCalled(t, "GetFreezeEventsBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/config"
+	"echopay/token-management/src/models"
+)
+
+// SignatureAlgorithm identifies the algorithm used for token issuance signatures
+const SignatureAlgorithm = "ed25519"
+
+// SigningKeySource supplies issuer keys for signing and verifying tokens. Key IDs are
+// stored alongside each signature so tokens signed with retired keys remain verifiable.
+type SigningKeySource interface {
+	// SigningKey returns the active key ID and private key for an issuer
+	SigningKey(issuer string) (string, ed25519.PrivateKey, error)
+	// PublicKey returns the public key for a key ID, including retired keys
+	PublicKey(keyID string) (ed25519.PublicKey, error)
+}
+
+// StaticKeySource signs with a single active key and verifies against the active key
+// plus any registered retired keys
+type StaticKeySource struct {
+	activeKeyID string
+	privateKey  ed25519.PrivateKey
+	publicKeys  map[string]ed25519.PublicKey
+}
+
+// NewStaticKeySource creates a key source with the given active signing key
+func NewStaticKeySource(keyID string, privateKey ed25519.PrivateKey) *StaticKeySource {
+	return &StaticKeySource{
+		activeKeyID: keyID,
+		privateKey:  privateKey,
+		publicKeys: map[string]ed25519.PublicKey{
+			keyID: privateKey.Public().(ed25519.PublicKey),
+		},
+	}
+}
+
+// NewKeySourceFromConfig builds a key source from signing configuration. It returns nil
+// when no signing key is configured.
+func NewKeySourceFromConfig(cfg config.SigningConfig) (*StaticKeySource, error) {
+	if cfg.KeyID == "" || cfg.PrivateKey == "" {
+		return nil, nil
+	}
+
+	privateKey, err := parseSigningKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	source := NewStaticKeySource(cfg.KeyID, privateKey)
+	for keyID, encoded := range cfg.VerificationKeys {
+		publicKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid verification key %s", keyID)
+		}
+		source.AddVerificationKey(keyID, ed25519.PublicKey(publicKey))
+	}
+
+	return source, nil
+}
+
+// parseSigningKey decodes a base64-encoded Ed25519 seed or private key
+func parseSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key encoding: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid signing key length: %d", len(raw))
+	}
+}
+
+// AddVerificationKey registers a retired public key so older signatures remain verifiable
+func (s *StaticKeySource) AddVerificationKey(keyID string, publicKey ed25519.PublicKey) {
+	s.publicKeys[keyID] = publicKey
+}
+
+// SigningKey returns the active signing key; the same key is used for every issuer
+func (s *StaticKeySource) SigningKey(issuer string) (string, ed25519.PrivateKey, error) {
+	return s.activeKeyID, s.privateKey, nil
+}
+
+// PublicKey returns the public key registered for a key ID
+func (s *StaticKeySource) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	publicKey, ok := s.publicKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", keyID)
+	}
+	return publicKey, nil
+}
+
+// tokenSigningPayload serializes the signed fields of a token in a canonical order. The owner
+// is the one the token was issued to, not its current owner, so the signature stays valid as
+// the token changes hands.
+func tokenSigningPayload(token *models.Token, issuedTo uuid.UUID) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%.2f|%s|%s|%s",
+		token.TokenID,
+		token.CBDCType,
+		token.Denomination,
+		issuedTo,
+		token.Metadata.Issuer,
+		token.Metadata.Series,
+	))
+}
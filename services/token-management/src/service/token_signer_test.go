@@ -0,0 +1,57 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"echopay/shared/libraries/config"
+)
+
+func TestNewKeySourceFromConfig(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	retiredPublicKey, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	t.Run("no key configured", func(t *testing.T) {
+		source, err := NewKeySourceFromConfig(config.SigningConfig{})
+		assert.NoError(t, err)
+		assert.Nil(t, source)
+	})
+
+	t.Run("seed with retired verification key", func(t *testing.T) {
+		source, err := NewKeySourceFromConfig(config.SigningConfig{
+			KeyID:      "fed-2025-02",
+			PrivateKey: base64.StdEncoding.EncodeToString(seed),
+			VerificationKeys: map[string]string{
+				"fed-2025-01": base64.StdEncoding.EncodeToString(retiredPublicKey),
+			},
+		})
+		assert.NoError(t, err)
+
+		keyID, privateKey, err := source.SigningKey("Federal Reserve")
+		assert.NoError(t, err)
+		assert.Equal(t, "fed-2025-02", keyID)
+		assert.Equal(t, ed25519.NewKeyFromSeed(seed), privateKey)
+
+		publicKey, err := source.PublicKey("fed-2025-01")
+		assert.NoError(t, err)
+		assert.Equal(t, retiredPublicKey, publicKey)
+
+		_, err = source.PublicKey("unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key length", func(t *testing.T) {
+		_, err := NewKeySourceFromConfig(config.SigningConfig{
+			KeyID:      "fed-2025-02",
+			PrivateKey: base64.StdEncoding.EncodeToString([]byte("short")),
+		})
+		assert.Error(t, err)
+	})
+}
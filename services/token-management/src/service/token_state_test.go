@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+	"echopay/token-management/src/repository"
+)
+
+func TestTokenService_ReconstructTokenAt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	issuer, buyer := uuid.New(), uuid.New()
+
+	at := func(offset time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(offset), Valid: true}
+	}
+
+	newService := func(token *models.Token, auditChain []repository.TokenAuditEntry) *TokenService {
+		mockRepo := new(MockTokenRepository)
+		mockRepo.On("GetByID", mock.Anything, token.TokenID).Return(token, nil)
+		mockRepo.On("GetAuditChain", mock.Anything, token.TokenID).Return(auditChain, nil)
+		return NewTokenServiceWithDeps(mockRepo, nil)
+	}
+
+	token := &models.Token{
+		TokenID:      uuid.New(),
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: buyer,
+		Status:       models.TokenStatusFrozen,
+	}
+	auditChain := []repository.TokenAuditEntry{
+		{Operation: "CREATE", NewStatus: models.TokenStatusActive, NewOwner: issuer, Timestamp: at(-48 * time.Hour), Sequence: 1},
+		{Operation: "TRANSFER_PENDING", OldOwner: issuer, NewOwner: uuid.New(), Timestamp: at(-40 * time.Hour), Sequence: 2},
+		{Operation: "OWNERSHIP_TRANSFER", OldOwner: issuer, NewOwner: buyer, Timestamp: at(-30 * time.Hour), Sequence: 3},
+		{Operation: "STATUS_CHANGE", OldStatus: models.TokenStatusActive, NewStatus: models.TokenStatusFrozen, Timestamp: at(-10 * time.Hour), Sequence: 4},
+		{Operation: "FREEZE", Metadata: map[string]interface{}{"reason_code": "dispute"}, Timestamp: at(-10 * time.Hour), Sequence: 5},
+	}
+
+	t.Run("state before a freeze", func(t *testing.T) {
+		state, err := newService(token, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, now.Add(-20*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusActive, state.Status)
+		assert.Equal(t, buyer, state.Owner)
+		assert.Equal(t, 3, state.EntriesApplied)
+		assert.Equal(t, now.Add(-30*time.Hour), *state.LastChangeAt)
+		assert.True(t, state.Consistent)
+	})
+
+	t.Run("state after a freeze", func(t *testing.T) {
+		state, err := newService(token, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, now.Add(-10*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusFrozen, state.Status)
+		assert.Equal(t, buyer, state.Owner)
+		assert.Equal(t, 5, state.EntriesApplied)
+		assert.Equal(t, now.Add(-10*time.Hour), *state.LastChangeAt)
+		assert.True(t, state.Consistent)
+	})
+
+	t.Run("pending transfers do not change the owner", func(t *testing.T) {
+		state, err := newService(token, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, now.Add(-35*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, issuer, state.Owner)
+	})
+
+	t.Run("time before creation has no state", func(t *testing.T) {
+		_, err := newService(token, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, now.Add(-72*time.Hour))
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrTokenNotFound, tokenErr.Code)
+	})
+
+	t.Run("trail that does not explain the live token is flagged", func(t *testing.T) {
+		tampered := *token
+		tampered.Status = models.TokenStatusActive
+
+		state, err := newService(&tampered, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, now)
+		require.NoError(t, err)
+		assert.Equal(t, models.TokenStatusFrozen, state.Status)
+		assert.False(t, state.Consistent)
+		assert.Contains(t, state.Mismatch, "status")
+	})
+
+	t.Run("time is required", func(t *testing.T) {
+		_, err := newService(token, auditChain).ReconstructTokenAt(context.Background(), token.TokenID, time.Time{})
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionSummary is the part of a transaction-service transaction shown in token provenance
+type TransactionSummary struct {
+	ID         uuid.UUID  `json:"id"`
+	FromWallet uuid.UUID  `json:"from_wallet"`
+	ToWallet   uuid.UUID  `json:"to_wallet"`
+	Amount     float64    `json:"amount"`
+	Currency   string     `json:"currency"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	SettledAt  *time.Time `json:"settled_at,omitempty"`
+}
+
+// TransactionLookup fetches transactions referenced by a token's history. A transaction that
+// does not exist is reported as nil with no error.
+type TransactionLookup interface {
+	GetTransaction(ctx context.Context, transactionID uuid.UUID) (*TransactionSummary, error)
+}
+
+// HTTPTransactionLookup fetches transactions from the transaction service REST API
+type HTTPTransactionLookup struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPTransactionLookup creates a lookup against the transaction service at baseURL
+func NewHTTPTransactionLookup(baseURL string, timeout time.Duration) *HTTPTransactionLookup {
+	return &HTTPTransactionLookup{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// GetTransaction fetches a transaction by ID. The transaction service also serves archived
+// transactions from this endpoint.
+func (l *HTTPTransactionLookup) GetTransaction(ctx context.Context, transactionID uuid.UUID) (*TransactionSummary, error) {
+	url := fmt.Sprintf("%s/api/v1/transactions/%s", l.baseURL, transactionID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transaction service returned status %d", resp.StatusCode)
+	}
+
+	var transaction TransactionSummary
+	if err := json.NewDecoder(resp.Body).Decode(&transaction); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	return &transaction, nil
+}
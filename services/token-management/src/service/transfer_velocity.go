@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+)
+
+// SetTransferVelocityLimit limits how many times a single token may change hands within a
+// sliding window, since rapid churn through many owners is a laundering signal. A limit of
+// zero or less, or a window of zero or less, leaves transfers unlimited.
+func (s *TokenService) SetTransferVelocityLimit(maxTransfers int, window time.Duration) {
+	if maxTransfers <= 0 || window <= 0 {
+		maxTransfers, window = 0, 0
+	}
+	s.velocityLimit = maxTransfers
+	s.velocityWindow = window
+}
+
+// checkTransferVelocity returns a velocity exceeded error if the token has already changed
+// hands the maximum number of times in the window ending at now. Transfers are counted from
+// the token's audit trail.
+func (s *TokenService) checkTransferVelocity(ctx context.Context, tx *sql.Tx, tokenID uuid.UUID, now time.Time) error {
+	if s.velocityLimit <= 0 {
+		return nil
+	}
+
+	transfers, err := s.repo.CountOwnershipTransfersSinceWithTx(ctx, tx, tokenID, now.Add(-s.velocityWindow))
+	if err != nil {
+		return fmt.Errorf("failed to check transfer velocity: %w", err)
+	}
+
+	if transfers >= s.velocityLimit {
+		return errors.NewTokenManagementError(
+			errors.ErrVelocityExceeded,
+			fmt.Sprintf("token has changed hands %d times in the last %s, the maximum allowed", transfers, s.velocityWindow),
+		).WithDetails(map[string]interface{}{
+			"token_id":      tokenID,
+			"transfers":     transfers,
+			"max_transfers": s.velocityLimit,
+			"window":        s.velocityWindow.String(),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestTokenService_TransferToken_VelocityLimit(t *testing.T) {
+	const maxTransfers = 3
+	const window = 10 * time.Minute
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	tokenID := uuid.New()
+
+	mockRepo := new(MockTokenRepository)
+	mockDB := new(MockDatabase)
+	service := NewTokenServiceWithDeps(mockRepo, mockDB)
+	fakeClock := clock.NewFake(now)
+	service.SetClock(fakeClock)
+	service.SetTransferVelocityLimit(maxTransfers, window)
+
+	token := &models.Token{
+		TokenID:      tokenID,
+		CBDCType:     models.CBDCTypeUSD,
+		Denomination: 100.0,
+		CurrentOwner: uuid.New(),
+		Status:       models.TokenStatusActive,
+	}
+	mockDB.On("Transaction", mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+	mockRepo.On("GetByIDWithTx", mock.Anything, mock.Anything, tokenID).Return(token, nil)
+	mockRepo.On("GetRequiredSignersWithTx", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Token")).Return(nil)
+
+	transfer := func() error {
+		_, err := service.TransferToken(context.Background(), TransferTokenRequest{
+			TokenID:       tokenID,
+			NewOwner:      uuid.New(),
+			TransactionID: uuid.New(),
+		})
+		return err
+	}
+
+	// Each transfer is counted against the ones already in the window
+	since := now.Add(-window)
+	for i := 0; i < maxTransfers; i++ {
+		mockRepo.On("CountOwnershipTransfersSinceWithTx", mock.Anything, mock.Anything, tokenID, since).Return(i, nil).Once()
+		require.NoError(t, transfer(), "transfer %d", i+1)
+	}
+
+	mockRepo.On("CountOwnershipTransfersSinceWithTx", mock.Anything, mock.Anything, tokenID, since).Return(maxTransfers, nil).Once()
+	assertErrorCode(t, transfer(), errors.ErrVelocityExceeded)
+	mockRepo.AssertNumberOfCalls(t, "UpdateWithTx", maxTransfers)
+
+	// Once earlier transfers leave the window the token can move again
+	fakeClock.Advance(window)
+	mockRepo.On("CountOwnershipTransfersSinceWithTx", mock.Anything, mock.Anything, tokenID, now).Return(1, nil).Once()
+	require.NoError(t, transfer())
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/logging"
+	"echopay/token-management/src/models"
+)
+
+// logTokenTransition logs a committed change to a token's status or owner. The line carries
+// the request's correlation ID from ctx along with the token ID.
+func logTokenTransition(ctx context.Context, tokenID uuid.UUID, operation string, status models.TokenStatus, args ...interface{}) {
+	ctx = logging.ContextWithFields(ctx, "token_id", tokenID.String())
+	fields := []interface{}{
+		"operation", operation,
+		"to_status", status,
+	}
+	logging.WithContext(ctx).Info("Token state transition", append(fields, args...)...)
+}
+
+// logBulkTokenTransition logs a committed status change applied to many tokens at once
+func logBulkTokenTransition(ctx context.Context, operation string, status models.TokenStatus, count int, args ...interface{}) {
+	fields := []interface{}{
+		"operation", operation,
+		"to_status", status,
+		"token_count", count,
+	}
+	logging.WithContext(ctx).Info("Token state transition", append(fields, args...)...)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/webhooks"
+	"echopay/token-management/src/events"
+	"echopay/token-management/src/models"
+)
+
+// WalletTokensStatusResponse reports the tokens whose status changed with their wallet
+type WalletTokensStatusResponse struct {
+	WalletID     uuid.UUID          `json:"wallet_id"`
+	TokenIDs     []uuid.UUID        `json:"token_ids"`
+	UpdatedCount int                `json:"updated_count"`
+	NewStatus    models.TokenStatus `json:"new_status"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// FreezeWalletTokens freezes every active token held by a wallet in one transaction, for an
+// emergency wallet freeze. The tokens are frozen with the wallet_frozen reason code so that
+// UnfreezeWalletTokens lifts only this freeze.
+func (s *TokenService) FreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) (*WalletTokensStatusResponse, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"wallet ID cannot be nil",
+		)
+	}
+
+	tokenIDs, err := s.repo.FreezeOwnerTokens(ctx, walletID, freezeReasonMetadata(FreezeReasonWalletFrozen, note))
+	if err != nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to freeze wallet tokens: %v", err),
+		)
+	}
+
+	response := &WalletTokensStatusResponse{
+		WalletID:     walletID,
+		TokenIDs:     tokenIDs,
+		UpdatedCount: len(tokenIDs),
+		NewStatus:    models.TokenStatusFrozen,
+		UpdatedAt:    s.clock.Now(),
+	}
+	logBulkTokenTransition(ctx, "WALLET_FREEZE", models.TokenStatusFrozen, len(tokenIDs), "wallet_id", walletID.String())
+	for _, tokenID := range tokenIDs {
+		s.publishTokenEvent(events.TokenEventFrozen, tokenID, walletID, nil, models.TokenStatusFrozen, "Token frozen with its wallet")
+	}
+
+	if len(tokenIDs) > 0 {
+		s.webhooks.Dispatch(webhooks.EventTokensBulkFrozen, map[string]interface{}{
+			"token_ids":     tokenIDs,
+			"updated_count": len(tokenIDs),
+			"reason":        FreezeReasonWalletFrozen,
+			"wallet_id":     walletID,
+			"frozen_at":     response.UpdatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// UnfreezeWalletTokens unfreezes the wallet's tokens that were frozen by FreezeWalletTokens.
+// Tokens frozen for any other reason stay frozen.
+func (s *TokenService) UnfreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) (*WalletTokensStatusResponse, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrValidation,
+			"wallet ID cannot be nil",
+		)
+	}
+
+	tokenIDs, err := s.repo.UnfreezeOwnerTokens(ctx, walletID, string(FreezeReasonWalletFrozen), freezeReasonMetadata(FreezeReasonWalletRecovered, note))
+	if err != nil {
+		return nil, errors.NewTokenManagementError(
+			errors.ErrTransactionFailed,
+			fmt.Sprintf("failed to unfreeze wallet tokens: %v", err),
+		)
+	}
+	logBulkTokenTransition(ctx, "WALLET_UNFREEZE", models.TokenStatusActive, len(tokenIDs), "wallet_id", walletID.String())
+	for _, tokenID := range tokenIDs {
+		s.publishTokenEvent(events.TokenEventUnfrozen, tokenID, walletID, nil, models.TokenStatusActive, "Token unfrozen with its wallet")
+	}
+
+	return &WalletTokensStatusResponse{
+		WalletID:     walletID,
+		TokenIDs:     tokenIDs,
+		UpdatedCount: len(tokenIDs),
+		NewStatus:    models.TokenStatusActive,
+		UpdatedAt:    s.clock.Now(),
+	}, nil
+}
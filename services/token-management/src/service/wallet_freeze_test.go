@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/token-management/src/models"
+)
+
+func TestTokenService_FreezeWalletTokens(t *testing.T) {
+	walletID := uuid.New()
+	tokenIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	t.Run("active tokens are frozen with the wallet reason", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		mockRepo.On("FreezeOwnerTokens", mock.Anything, walletID, map[string]interface{}{
+			"reason_code": "wallet_frozen",
+			"note":        "device compromised",
+		}).Return(tokenIDs, nil).Once()
+
+		response, err := service.FreezeWalletTokens(context.Background(), walletID, "device compromised")
+
+		require.NoError(t, err)
+		assert.Equal(t, walletID, response.WalletID)
+		assert.Equal(t, tokenIDs, response.TokenIDs)
+		assert.Equal(t, 2, response.UpdatedCount)
+		assert.Equal(t, models.TokenStatusFrozen, response.NewStatus)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("nil wallet is rejected", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		_, err := service.FreezeWalletTokens(context.Background(), uuid.Nil, "")
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrValidation, tokenErr.Code)
+		mockRepo.AssertNotCalled(t, "FreezeOwnerTokens", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failures are reported", func(t *testing.T) {
+		mockRepo := new(MockTokenRepository)
+		service := NewTokenServiceWithDeps(mockRepo, nil)
+
+		mockRepo.On("FreezeOwnerTokens", mock.Anything, walletID, mock.Anything).Return(nil, fmt.Errorf("connection reset")).Once()
+
+		_, err := service.FreezeWalletTokens(context.Background(), walletID, "")
+
+		tokenErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrTransactionFailed, tokenErr.Code)
+	})
+}
+
+func TestTokenService_UnfreezeWalletTokens(t *testing.T) {
+	walletID := uuid.New()
+	tokenIDs := []uuid.UUID{uuid.New()}
+
+	mockRepo := new(MockTokenRepository)
+	service := NewTokenServiceWithDeps(mockRepo, nil)
+
+	// Only tokens frozen by the wallet freeze are lifted
+	mockRepo.On("UnfreezeOwnerTokens", mock.Anything, walletID, "wallet_frozen", map[string]interface{}{
+		"reason_code": "wallet_recovered",
+	}).Return(tokenIDs, nil).Once()
+
+	response, err := service.UnfreezeWalletTokens(context.Background(), walletID, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, tokenIDs, response.TokenIDs)
+	assert.Equal(t, models.TokenStatusActive, response.NewStatus)
+	mockRepo.AssertExpectations(t)
+}
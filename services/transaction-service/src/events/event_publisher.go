@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,8 +38,10 @@ type TransactionEvent struct {
 	Currency      models.Currency        `json:"currency"`
 	Status        models.TransactionStatus `json:"status"`
 	FraudScore    *float64               `json:"fraud_score,omitempty"`
+	BaselineDeviation *float64           `json:"baseline_deviation,omitempty"` // Fraud scoring input: 0 typical, towards 1 far above the sender's baseline
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	Version       int                    `json:"version"`
+	Replay        bool                   `json:"replay,omitempty"` // Re-published by a resync
 }
 
 // BalanceUpdateEvent represents a balance update event
@@ -52,12 +55,30 @@ type BalanceUpdateEvent struct {
 	NewBalance float64        `json:"new_balance"`
 	TransactionID *uuid.UUID  `json:"transaction_id,omitempty"`
 	Version   int             `json:"version"`
+	Replay    bool            `json:"replay,omitempty"` // Re-published by a resync
+}
+
+// BalanceChange is a wallet balance update made by a transaction, as replayed by
+// ReplayTransactionEvents
+type BalanceChange struct {
+	WalletID   uuid.UUID
+	OldBalance float64
+	NewBalance float64
 }
 
 // EventPublisher handles publishing events to Kafka
 type EventPublisher struct {
-	writer *kafka.Writer
-	logger *logging.Logger
+	writer  *kafka.Writer
+	write   func(ctx context.Context, messages ...kafka.Message) error
+	brokers []string
+	logger  *logging.Logger
+
+	mu             sync.RWMutex
+	lastPublishErr error         // Error from the most recent asynchronous write, nil once a write succeeds
+	pending        int           // Messages accepted for asynchronous delivery whose batch has not completed
+	drained        chan struct{} // Closed once pending drops back to zero
+	deliveryErr    error         // First delivery error since the last Flush
+	failedBatches  int           // Batches that failed to deliver since the last Flush
 }
 
 // EventPublisherConfig holds configuration for the event publisher
@@ -79,14 +100,31 @@ func NewEventPublisher(config EventPublisherConfig) *EventPublisher {
 		Async:        true, // Enable async publishing for better performance
 	}
 
-	return &EventPublisher{
-		writer: writer,
-		logger: logging.NewLogger("event-publisher"),
+	publisher := &EventPublisher{
+		writer:  writer,
+		write:   writer.WriteMessages,
+		brokers: config.KafkaBrokers,
+		logger:  logging.NewLogger("event-publisher"),
 	}
+
+	// Async writes never return errors to the caller, so record their outcome for health checks
+	writer.Completion = publisher.recordCompletion
+
+	return publisher
 }
 
 // PublishTransactionEvent publishes a transaction event
 func (p *EventPublisher) PublishTransactionEvent(ctx context.Context, transaction *models.Transaction, eventType EventType) error {
+	return p.publishTransactionEvent(ctx, transaction, eventType, nil)
+}
+
+// PublishTransactionCreatedEvent publishes a transaction created event carrying how far the
+// transfer deviates from the sender's baseline, for fraud detection to score it with
+func (p *EventPublisher) PublishTransactionCreatedEvent(ctx context.Context, transaction *models.Transaction, baselineDeviation *float64) error {
+	return p.publishTransactionEvent(ctx, transaction, EventTransactionCreated, baselineDeviation)
+}
+
+func (p *EventPublisher) publishTransactionEvent(ctx context.Context, transaction *models.Transaction, eventType EventType, baselineDeviation *float64) error {
 	event := TransactionEvent{
 		ID:            uuid.New(),
 		Type:          eventType,
@@ -98,6 +136,7 @@ func (p *EventPublisher) PublishTransactionEvent(ctx context.Context, transactio
 		Currency:      transaction.Currency,
 		Status:        transaction.Status,
 		FraudScore:    transaction.FraudScore,
+		BaselineDeviation: baselineDeviation,
 		Metadata: map[string]interface{}{
 			"description": transaction.Metadata.Description,
 			"category":    transaction.Metadata.Category,
@@ -148,8 +187,69 @@ func (p *EventPublisher) PublishFraudScoreEvent(ctx context.Context, transaction
 	return p.publishEvent(ctx, event.ID.String(), event)
 }
 
-// publishEvent publishes an event to Kafka
-func (p *EventPublisher) publishEvent(ctx context.Context, key string, event interface{}) error {
+// ReplayTransactionEvents re-publishes the canonical events of a completed transaction: created,
+// one balance update per balance change, then completed. Replayed events carry replay=true and
+// IDs derived from the transaction, so replaying the same transaction again yields identical
+// events that consumers can deduplicate.
+func (p *EventPublisher) ReplayTransactionEvents(ctx context.Context, transaction *models.Transaction, balanceChanges []BalanceChange) error {
+	completedAt := transaction.CreatedAt
+	if transaction.SettledAt != nil {
+		completedAt = *transaction.SettledAt
+	}
+
+	replayEvent := func(eventType EventType, status models.TransactionStatus, timestamp time.Time) TransactionEvent {
+		return TransactionEvent{
+			ID:            uuid.NewSHA1(transaction.ID, []byte(eventType)),
+			Type:          eventType,
+			Timestamp:     timestamp.UTC(),
+			TransactionID: transaction.ID,
+			FromWallet:    transaction.FromWallet,
+			ToWallet:      transaction.ToWallet,
+			Amount:        transaction.Amount,
+			Currency:      transaction.Currency,
+			Status:        status,
+			FraudScore:    transaction.FraudScore,
+			Metadata: map[string]interface{}{
+				"description": transaction.Metadata.Description,
+				"category":    transaction.Metadata.Category,
+			},
+			Version: 1,
+			Replay:  true,
+		}
+	}
+
+	replay := kafka.Header{Key: "replay", Value: []byte("true")}
+
+	created := replayEvent(EventTransactionCreated, models.StatusPending, transaction.CreatedAt)
+	if err := p.publishEvent(ctx, created.ID.String(), created, replay); err != nil {
+		return err
+	}
+
+	for _, change := range balanceChanges {
+		transactionID := transaction.ID
+		event := BalanceUpdateEvent{
+			ID:            uuid.NewSHA1(transaction.ID, []byte(string(EventBalanceUpdated)+":"+change.WalletID.String())),
+			Type:          EventBalanceUpdated,
+			Timestamp:     completedAt.UTC(),
+			WalletID:      change.WalletID,
+			Currency:      transaction.Currency,
+			OldBalance:    change.OldBalance,
+			NewBalance:    change.NewBalance,
+			TransactionID: &transactionID,
+			Version:       1,
+			Replay:        true,
+		}
+		if err := p.publishEvent(ctx, event.ID.String(), event, replay); err != nil {
+			return err
+		}
+	}
+
+	completed := replayEvent(EventTransactionCompleted, models.StatusCompleted, completedAt)
+	return p.publishEvent(ctx, completed.ID.String(), completed, replay)
+}
+
+// publishEvent publishes an event to Kafka with any extra headers
+func (p *EventPublisher) publishEvent(ctx context.Context, key string, event interface{}, headers ...kafka.Header) error {
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to marshal event", "event-publisher")
@@ -164,9 +264,13 @@ func (p *EventPublisher) publishEvent(ctx context.Context, key string, event int
 			{Key: "producer", Value: []byte("transaction-service")},
 		},
 	}
+	message.Headers = append(message.Headers, headers...)
 
-	err = p.writer.WriteMessages(ctx, message)
+	p.track(1)
+	err = p.write(ctx, message)
 	if err != nil {
+		// A rejected write never reaches the writer's completion callback
+		p.settle(1)
 		p.logger.Error("Failed to publish event", "error", err, "key", key)
 		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to publish event", "event-publisher")
 	}
@@ -175,6 +279,143 @@ func (p *EventPublisher) publishEvent(ctx context.Context, key string, event int
 	return nil
 }
 
+// CheckConnection verifies that at least one configured Kafka broker is reachable
+func (p *EventPublisher) CheckConnection(ctx context.Context) error {
+	var lastErr error
+	for _, broker := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn.Close()
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no kafka brokers configured")
+	}
+	return errors.WrapError(lastErr, errors.ErrServiceUnavailable, "event publisher cannot reach kafka", "event-publisher")
+}
+
+// Healthy reports whether events can currently be published. The most recent asynchronous
+// write must not have failed, and a broker must be reachable and report partitions for the
+// configured topic.
+func (p *EventPublisher) Healthy(ctx context.Context) error {
+	p.mu.RLock()
+	publishErr := p.lastPublishErr
+	p.mu.RUnlock()
+	if publishErr != nil {
+		return errors.WrapError(publishErr, errors.ErrServiceUnavailable, "most recent event publish failed", "event-publisher")
+	}
+
+	var lastErr error
+	for _, broker := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		partitions, err := conn.ReadPartitions(p.writer.Topic)
+		conn.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(partitions) == 0 {
+			lastErr = fmt.Errorf("topic %s has no partitions", p.writer.Topic)
+			continue
+		}
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no kafka brokers configured")
+	}
+	return errors.WrapError(lastErr, errors.ErrServiceUnavailable, "event publisher cannot reach topic", "event-publisher")
+}
+
+// recordCompletion tracks the outcome of an asynchronous write batch
+func (p *EventPublisher) recordCompletion(messages []kafka.Message, err error) {
+	if err != nil {
+		p.logger.Error("Asynchronous event write failed", "error", err, "messages", len(messages))
+	}
+
+	p.mu.Lock()
+	p.lastPublishErr = err
+	if err != nil {
+		if p.deliveryErr == nil {
+			p.deliveryErr = err
+		}
+		p.failedBatches++
+	}
+	p.mu.Unlock()
+
+	p.settle(len(messages))
+}
+
+// track records messages handed to the writer for asynchronous delivery
+func (p *EventPublisher) track(count int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == 0 {
+		p.drained = make(chan struct{})
+	}
+	p.pending += count
+}
+
+// settle records messages whose delivery has finished, successfully or not, and wakes
+// flushes once none are left in flight
+func (p *EventPublisher) settle(count int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == 0 {
+		return
+	}
+	p.pending -= count
+	if p.pending <= 0 {
+		p.pending = 0
+		close(p.drained)
+		p.drained = nil
+	}
+}
+
+// Flush waits until every event published so far has been delivered or has failed, and
+// returns any delivery failures since the previous flush. Asynchronous batches are sent
+// once BatchSize events are buffered or BatchTimeout passes, so a flush takes at most about
+// BatchTimeout plus the time to write the batch; while events keep being published it may
+// wait until ctx is done. Flush is safe to call concurrently, and each failure is reported
+// by one flush only.
+func (p *EventPublisher) Flush(ctx context.Context) error {
+	p.mu.RLock()
+	drained := p.drained
+	p.mu.RUnlock()
+
+	if drained != nil {
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return errors.WrapError(ctx.Err(), errors.ErrRequestTimeout, "timed out flushing events", "event-publisher")
+		}
+	}
+
+	p.mu.Lock()
+	deliveryErr, failedBatches := p.deliveryErr, p.failedBatches
+	p.deliveryErr, p.failedBatches = nil, 0
+	p.mu.Unlock()
+
+	if deliveryErr != nil {
+		return errors.WrapError(deliveryErr, errors.ErrServiceUnavailable, fmt.Sprintf("%d event batches failed to deliver", failedBatches), "event-publisher")
+	}
+	return nil
+}
+
 // Close closes the event publisher
 func (p *EventPublisher) Close() error {
 	return p.writer.Close()
@@ -1,13 +1,19 @@
 package events
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"echopay/shared/libraries/errors"
 	"echopay/transaction-service/src/models"
 )
 
@@ -128,4 +134,194 @@ func TestEventPublisher_Creation(t *testing.T) {
 	// Clean up
 	err := publisher.Close()
 	assert.NoError(t, err)
-}
\ No newline at end of file
+}
+func TestEventPublisher_HealthyUnreachableBroker(t *testing.T) {
+	publisher := NewEventPublisher(EventPublisherConfig{
+		KafkaBrokers: []string{"127.0.0.1:1"},
+		Topic:        "test.transactions",
+		BatchSize:    10,
+		BatchTimeout: 10 * time.Millisecond,
+	})
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := publisher.Healthy(ctx)
+	require.Error(t, err)
+
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok, "Expected EchoPayError")
+	assert.Equal(t, errors.ErrServiceUnavailable, echoPayErr.Code)
+}
+
+func TestEventPublisher_HealthyReportsFailedWrites(t *testing.T) {
+	publisher := NewEventPublisher(EventPublisherConfig{
+		KafkaBrokers: []string{"127.0.0.1:1"},
+		Topic:        "test.transactions",
+	})
+	defer publisher.Close()
+
+	// A failed asynchronous write is reported without contacting the broker
+	publisher.recordCompletion(nil, fmt.Errorf("leader not available"))
+	err := publisher.Healthy(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "most recent event publish failed")
+
+	// A later successful write clears it, leaving only the broker check
+	publisher.recordCompletion(nil, nil)
+	err = publisher.Healthy(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot reach topic")
+}
+
+func TestEventPublisher_ReplayTransactionEvents(t *testing.T) {
+	publisher := NewEventPublisher(DefaultEventPublisherConfig())
+	defer publisher.Close()
+
+	var messages []kafka.Message
+	publisher.write = func(ctx context.Context, msgs ...kafka.Message) error {
+		messages = append(messages, msgs...)
+		return nil
+	}
+
+	createdAt := time.Now().Add(-time.Hour).UTC()
+	settledAt := createdAt.Add(time.Second)
+	transaction := &models.Transaction{
+		ID:         uuid.New(),
+		FromWallet: uuid.New(),
+		ToWallet:   uuid.New(),
+		Amount:     125.50,
+		Currency:   models.USDCBDC,
+		Status:     models.StatusCompleted,
+		CreatedAt:  createdAt,
+		SettledAt:  &settledAt,
+	}
+	changes := []BalanceChange{
+		{WalletID: transaction.FromWallet, OldBalance: 1000.0, NewBalance: 874.50},
+		{WalletID: transaction.ToWallet, OldBalance: 0.0, NewBalance: 125.50},
+	}
+
+	require.NoError(t, publisher.ReplayTransactionEvents(context.Background(), transaction, changes))
+	require.Len(t, messages, 4)
+
+	for _, message := range messages {
+		assert.Contains(t, message.Headers, kafka.Header{Key: "replay", Value: []byte("true")})
+	}
+
+	var created, completed TransactionEvent
+	require.NoError(t, json.Unmarshal(messages[0].Value, &created))
+	require.NoError(t, json.Unmarshal(messages[3].Value, &completed))
+	assert.Equal(t, EventTransactionCreated, created.Type)
+	assert.Equal(t, models.StatusPending, created.Status)
+	assert.Equal(t, EventTransactionCompleted, completed.Type)
+	assert.Equal(t, settledAt, completed.Timestamp)
+	for _, event := range []TransactionEvent{created, completed} {
+		assert.True(t, event.Replay)
+		assert.Equal(t, transaction.ID, event.TransactionID)
+		assert.Equal(t, 125.50, event.Amount)
+	}
+
+	for i, change := range changes {
+		var balance BalanceUpdateEvent
+		require.NoError(t, json.Unmarshal(messages[i+1].Value, &balance))
+		assert.True(t, balance.Replay)
+		assert.Equal(t, change.WalletID, balance.WalletID)
+		assert.Equal(t, change.OldBalance, balance.OldBalance)
+		assert.Equal(t, change.NewBalance, balance.NewBalance)
+		assert.Equal(t, &transaction.ID, balance.TransactionID)
+	}
+
+	// Replaying again produces the same event IDs so consumers can deduplicate
+	first := messages
+	messages = nil
+	require.NoError(t, publisher.ReplayTransactionEvents(context.Background(), transaction, changes))
+	for i := range first {
+		assert.Equal(t, first[i].Key, messages[i].Key)
+	}
+}
+
+func TestEventPublisher_FlushWaitsForDelivery(t *testing.T) {
+	publisher := NewEventPublisher(DefaultEventPublisherConfig())
+	defer publisher.Close()
+
+	// Deliver each write after a delay and report it through the completion callback, as
+	// the asynchronous Kafka writer does once a batch is sent
+	var mu sync.Mutex
+	var delivered []kafka.Message
+	var deliveryErr error
+	publisher.write = func(ctx context.Context, msgs ...kafka.Message) error {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			err := deliveryErr
+			if err == nil {
+				delivered = append(delivered, msgs...)
+			}
+			mu.Unlock()
+			publisher.recordCompletion(msgs, err)
+		}()
+		return nil
+	}
+
+	transaction := &models.Transaction{
+		ID:         uuid.New(),
+		FromWallet: uuid.New(),
+		ToWallet:   uuid.New(),
+		Amount:     42.0,
+		Currency:   models.USDCBDC,
+		Status:     models.StatusCompleted,
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, publisher.PublishTransactionEvent(context.Background(), transaction, EventTransactionCompleted))
+	}
+
+	// Concurrent flushes all return once every event has been delivered
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, publisher.Flush(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	assert.Len(t, delivered, 5)
+	mu.Unlock()
+
+	// Nothing is in flight, so a further flush returns at once
+	require.NoError(t, publisher.Flush(context.Background()))
+
+	t.Run("returns delivery failures once", func(t *testing.T) {
+		mu.Lock()
+		deliveryErr = fmt.Errorf("leader not available")
+		mu.Unlock()
+
+		require.NoError(t, publisher.PublishTransactionEvent(context.Background(), transaction, EventTransactionFailed))
+		err := publisher.Flush(context.Background())
+		require.Error(t, err)
+		echoPayErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrServiceUnavailable, echoPayErr.Code)
+		assert.Contains(t, err.Error(), "1 event batches failed to deliver")
+
+		assert.NoError(t, publisher.Flush(context.Background()))
+	})
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		publisher.write = func(ctx context.Context, msgs ...kafka.Message) error {
+			return nil // Never completes
+		}
+		require.NoError(t, publisher.PublishTransactionEvent(context.Background(), transaction, EventTransactionCreated))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := publisher.Flush(ctx)
+		require.Error(t, err)
+		echoPayErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, "Expected EchoPayError")
+		assert.Equal(t, errors.ErrRequestTimeout, echoPayErr.Code)
+	})
+}
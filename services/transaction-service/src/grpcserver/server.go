@@ -0,0 +1,121 @@
+// Package grpcserver exposes real-time transaction status updates over gRPC for backend
+// consumers. It is backed by the same StatusTracker as the WebSocket feed.
+package grpcserver
+
+//go:generate protoc --proto_path=../../proto --go_out=statuspb --go_opt=paths=source_relative --go-grpc_out=statuspb --go-grpc_opt=paths=source_relative transaction_status.proto
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/grpcserver/statuspb"
+	"echopay/transaction-service/src/models"
+)
+
+// Server implements the TransactionStatus gRPC service
+type Server struct {
+	statuspb.UnimplementedTransactionStatusServer
+	statusTracker *events.StatusTracker
+	logger        *logging.Logger
+}
+
+// NewServer creates a gRPC server implementation backed by the status tracker
+func NewServer(statusTracker *events.StatusTracker) *Server {
+	return &Server{
+		statusTracker: statusTracker,
+		logger:        logging.NewLogger("grpc-status-server"),
+	}
+}
+
+// Register creates a gRPC server with the TransactionStatus service registered
+func Register(statusTracker *events.StatusTracker, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
+	statuspb.RegisterTransactionStatusServer(grpcServer, NewServer(statusTracker))
+	return grpcServer
+}
+
+// SubscribeStatus streams status updates matching the filter until the client cancels or the
+// subscription is closed
+func (s *Server) SubscribeStatus(req *statuspb.StatusFilter, stream statuspb.TransactionStatus_SubscribeStatusServer) error {
+	filter, err := toStatusFilter(req)
+	if err != nil {
+		return err
+	}
+
+	subscriber := s.statusTracker.Subscribe(filter)
+	defer s.statusTracker.Unsubscribe(subscriber.ID)
+
+	s.logger.Info("gRPC status subscriber connected", "subscriber_id", subscriber.ID)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("gRPC status subscriber disconnected", "subscriber_id", subscriber.ID)
+			return nil
+		case update, ok := <-subscriber.Channel:
+			if !ok {
+				return nil // Subscription closed
+			}
+
+			// Skip liveness probes left in the channel by the tracker's cleanup routine
+			if update.TransactionID == uuid.Nil {
+				continue
+			}
+
+			if err := stream.Send(toProtoUpdate(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toStatusFilter converts a gRPC filter to the tracker's filter
+func toStatusFilter(req *statuspb.StatusFilter) (events.StatusFilter, error) {
+	var filter events.StatusFilter
+
+	for _, id := range req.GetTransactionIds() {
+		transactionID, err := parseUUID("transaction_ids", id)
+		if err != nil {
+			return filter, err
+		}
+		filter.TransactionIDs = append(filter.TransactionIDs, transactionID)
+	}
+
+	for _, id := range req.GetWalletIds() {
+		walletID, err := parseUUID("wallet_ids", id)
+		if err != nil {
+			return filter, err
+		}
+		filter.WalletIDs = append(filter.WalletIDs, walletID)
+	}
+
+	for _, transactionStatus := range req.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, models.TransactionStatus(transactionStatus))
+	}
+
+	return filter, nil
+}
+
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %q is not a UUID", field, value)
+	}
+	return id, nil
+}
+
+func toProtoUpdate(update events.StatusUpdate) *statuspb.StatusUpdate {
+	return &statuspb.StatusUpdate{
+		TransactionId: update.TransactionID.String(),
+		Status:        string(update.Status),
+		Timestamp:     timestamppb.New(update.Timestamp),
+		FraudScore:    update.FraudScore,
+		Message:       update.Message,
+	}
+}
@@ -0,0 +1,102 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/grpcserver/statuspb"
+	"echopay/transaction-service/src/models"
+)
+
+// newTestClient starts the gRPC server on an in-memory listener and returns a connected client
+func newTestClient(t *testing.T, statusTracker *events.StatusTracker) statuspb.TransactionStatusClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := Register(statusTracker)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return statuspb.NewTransactionStatusClient(conn)
+}
+
+func waitForSubscribers(t *testing.T, statusTracker *events.StatusTracker, count int) {
+	require.Eventually(t, func() bool {
+		return statusTracker.GetSubscriberCount() == count
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServer_SubscribeStatus_FiltersByWallet(t *testing.T) {
+	statusTracker := events.NewStatusTracker()
+	client := newTestClient(t, statusTracker)
+	walletID := uuid.New()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.SubscribeStatus(ctx, &statuspb.StatusFilter{WalletIds: []string{walletID.String()}})
+	require.NoError(t, err)
+	waitForSubscribers(t, statusTracker, 1)
+
+	fraudScore := 0.42
+	other := &models.Transaction{ID: uuid.New(), FromWallet: uuid.New(), ToWallet: uuid.New(), Status: models.StatusPending}
+	matching := &models.Transaction{ID: uuid.New(), FromWallet: uuid.New(), ToWallet: walletID, Status: models.StatusCompleted, FraudScore: &fraudScore}
+
+	statusTracker.PublishStatusUpdate(other, "Not for this subscriber")
+	statusTracker.PublishStatusUpdate(matching, "Transaction completed successfully")
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, matching.ID.String(), update.TransactionId)
+	assert.Equal(t, string(models.StatusCompleted), update.Status)
+	assert.Equal(t, "Transaction completed successfully", update.Message)
+	require.NotNil(t, update.FraudScore)
+	assert.Equal(t, 0.42, *update.FraudScore)
+	assert.NotNil(t, update.Timestamp)
+}
+
+func TestServer_SubscribeStatus_UnsubscribesOnCancel(t *testing.T) {
+	statusTracker := events.NewStatusTracker()
+	client := newTestClient(t, statusTracker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := client.SubscribeStatus(ctx, &statuspb.StatusFilter{})
+	require.NoError(t, err)
+	waitForSubscribers(t, statusTracker, 1)
+
+	cancel()
+	waitForSubscribers(t, statusTracker, 0)
+}
+
+func TestServer_SubscribeStatus_InvalidFilter(t *testing.T) {
+	statusTracker := events.NewStatusTracker()
+	client := newTestClient(t, statusTracker)
+
+	stream, err := client.SubscribeStatus(context.Background(), &statuspb.StatusFilter{
+		TransactionIds: []string{"not-a-uuid"},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 0, statusTracker.GetSubscriberCount())
+}
@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction_status.proto
+
+package statuspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TransactionStatus_SubscribeStatus_FullMethodName = "/echopay.transaction.v1.TransactionStatus/SubscribeStatus"
+)
+
+// TransactionStatusClient is the client API for TransactionStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransactionStatusClient interface {
+	// SubscribeStatus streams updates matching the filter until the client cancels. An empty
+	// filter receives every update.
+	SubscribeStatus(ctx context.Context, in *StatusFilter, opts ...grpc.CallOption) (TransactionStatus_SubscribeStatusClient, error)
+}
+
+type transactionStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransactionStatusClient(cc grpc.ClientConnInterface) TransactionStatusClient {
+	return &transactionStatusClient{cc}
+}
+
+func (c *transactionStatusClient) SubscribeStatus(ctx context.Context, in *StatusFilter, opts ...grpc.CallOption) (TransactionStatus_SubscribeStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStatus_ServiceDesc.Streams[0], TransactionStatus_SubscribeStatus_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStatusSubscribeStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStatus_SubscribeStatusClient interface {
+	Recv() (*StatusUpdate, error)
+	grpc.ClientStream
+}
+
+type transactionStatusSubscribeStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStatusSubscribeStatusClient) Recv() (*StatusUpdate, error) {
+	m := new(StatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransactionStatusServer is the server API for TransactionStatus service.
+// All implementations must embed UnimplementedTransactionStatusServer
+// for forward compatibility
+type TransactionStatusServer interface {
+	// SubscribeStatus streams updates matching the filter until the client cancels. An empty
+	// filter receives every update.
+	SubscribeStatus(*StatusFilter, TransactionStatus_SubscribeStatusServer) error
+	mustEmbedUnimplementedTransactionStatusServer()
+}
+
+// UnimplementedTransactionStatusServer must be embedded to have forward compatible implementations.
+type UnimplementedTransactionStatusServer struct {
+}
+
+func (UnimplementedTransactionStatusServer) SubscribeStatus(*StatusFilter, TransactionStatus_SubscribeStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeStatus not implemented")
+}
+func (UnimplementedTransactionStatusServer) mustEmbedUnimplementedTransactionStatusServer() {}
+
+// UnsafeTransactionStatusServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransactionStatusServer will
+// result in compilation errors.
+type UnsafeTransactionStatusServer interface {
+	mustEmbedUnimplementedTransactionStatusServer()
+}
+
+func RegisterTransactionStatusServer(s grpc.ServiceRegistrar, srv TransactionStatusServer) {
+	s.RegisterService(&TransactionStatus_ServiceDesc, srv)
+}
+
+func _TransactionStatus_SubscribeStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatusFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStatusServer).SubscribeStatus(m, &transactionStatusSubscribeStatusServer{stream})
+}
+
+type TransactionStatus_SubscribeStatusServer interface {
+	Send(*StatusUpdate) error
+	grpc.ServerStream
+}
+
+type transactionStatusSubscribeStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStatusSubscribeStatusServer) Send(m *StatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TransactionStatus_ServiceDesc is the grpc.ServiceDesc for TransactionStatus service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransactionStatus_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "echopay.transaction.v1.TransactionStatus",
+	HandlerType: (*TransactionStatusServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeStatus",
+			Handler:       _TransactionStatus_SubscribeStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "transaction_status.proto",
+}
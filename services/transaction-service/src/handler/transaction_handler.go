@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	sharedhttp "echopay/shared/libraries/http"
 	"echopay/transaction-service/src/models"
 	"echopay/transaction-service/src/service"
 )
@@ -25,6 +30,51 @@ func NewTransactionHandler(service *service.TransactionService) *TransactionHand
 // CreateTransaction handles POST /api/v1/transactions
 func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	var req service.TransactionRequest
+	if !sharedhttp.BindJSON(c, &req, h.currencyCheck(&req.Currency)) {
+		return
+	}
+
+	transaction, err := h.service.ProcessTransaction(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Transfers left pending have their funds held until they are settled
+	settlement := "immediate"
+	if transaction.Status == models.StatusPending {
+		settlement = "delayed"
+	}
+
+	response := gin.H{
+		"transaction_id": transaction.ID,
+		"status": transaction.Status,
+		"timestamp": transaction.CreatedAt,
+		"fraud_score": transaction.FraudScore,
+		"estimated_settlement": settlement,
+	}
+	
+	// The transaction is processed, but its events may not reach downstream consumers
+	if h.service.EventStreamingDegraded() {
+		response["degraded"] = []string{"event_streaming"}
+	}
+	
+	c.JSON(http.StatusCreated, response)
+}
+
+// currencyCheck rejects a bound currency the service does not support
+func (h *TransactionHandler) currencyCheck(currency *models.Currency) sharedhttp.FieldCheck {
+	return func() []sharedhttp.FieldError {
+		if !h.service.SupportsCurrency(*currency) {
+			return []sharedhttp.FieldError{{Field: "currency", Message: fmt.Sprintf("unsupported currency: %s", *currency)}}
+		}
+		return nil
+	}
+}
+
+// CreateTokenSettlement handles POST /api/v1/transactions/with-tokens
+func (h *TransactionHandler) CreateTokenSettlement(c *gin.Context) {
+	var req service.TokenSettlementRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
@@ -33,7 +83,7 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	transaction, err := h.service.ProcessTransaction(c.Request.Context(), &req)
+	transaction, err := h.service.SettleTransferWithTokens(c.Request.Context(), &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -43,8 +93,40 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		"transaction_id": transaction.ID,
 		"status": transaction.Status,
 		"timestamp": transaction.CreatedAt,
-		"fraud_score": transaction.FraudScore,
-		"estimated_settlement": "immediate",
+		"token_ids": req.TokenIDs,
+	})
+}
+
+// CreateAtomicMultiTransfer handles POST /api/v1/transactions/atomic-multi
+func (h *TransactionHandler) CreateAtomicMultiTransfer(c *gin.Context) {
+	var req struct {
+		Legs []service.TransferLeg `json:"legs" binding:"required,min=1,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.ProcessAtomicMultiTransfer(c.Request.Context(), req.Legs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	transactionIDs := make([]uuid.UUID, len(result.Transactions))
+	for i, transaction := range result.Transactions {
+		transactionIDs[i] = transaction.ID
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"correlation_id": result.CorrelationID,
+		"transaction_ids": transactionIDs,
+		"count": len(transactionIDs),
+		"status": models.StatusCompleted,
 	})
 }
 
@@ -68,7 +150,69 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, transaction)
 }
 
-// GetTransactionsByWallet handles GET /api/v1/wallets/:wallet_id/transactions
+// GetTransactionReceipt handles GET /api/v1/transactions/:id/receipt
+func (h *TransactionHandler) GetTransactionReceipt(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	receipt, err := h.service.GenerateReceipt(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+// GetTransactionChain handles GET /api/v1/transactions/:id/chain, returning the transactions
+// linked to a transaction by refunds
+func (h *TransactionHandler) GetTransactionChain(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	chain, err := h.service.GetTransactionChain(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
+}
+
+// GetReceiptKeys handles GET /api/v1/receipts/keys, returning the base64-encoded public keys
+// receipts can be verified with
+func (h *TransactionHandler) GetReceiptKeys(c *gin.Context) {
+	publicKeys, err := h.service.ReceiptPublicKeys()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	keys := make(map[string]string, len(publicKeys))
+	for keyID, publicKey := range publicKeys {
+		keys[keyID] = base64.StdEncoding.EncodeToString(publicKey)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"algorithm": service.ReceiptSignatureAlgorithm,
+		"keys":      keys,
+	})
+}
+
+// GetTransactionsByWallet handles GET /api/v1/wallets/:wallet_id/transactions. Transfer notes
+// are only included for the wallet the caller acts for.
 func (h *TransactionHandler) GetTransactionsByWallet(c *gin.Context) {
 	walletIDStr := c.Param("wallet_id")
 	walletID, err := uuid.Parse(walletIDStr)
@@ -79,6 +223,18 @@ func (h *TransactionHandler) GetTransactionsByWallet(c *gin.Context) {
 		return
 	}
 
+	// Without a caller wallet no private notes are shown
+	requester := uuid.Nil
+	if header := c.GetHeader(sharedhttp.WalletHeader); header != "" {
+		requester, err = uuid.Parse(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid caller wallet ID format",
+			})
+			return
+		}
+	}
+
 	// Parse pagination parameters
 	limit := 50
 	offset := 0
@@ -89,13 +245,111 @@ func (h *TransactionHandler) GetTransactionsByWallet(c *gin.Context) {
 		}
 	}
 	
+	// Prefer keyset pagination when a cursor is supplied; an empty cursor requests the first page
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		page, err := h.service.GetTransactionsByWalletCursor(c.Request.Context(), walletID, requester, cursor, limit)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		
+		c.JSON(http.StatusOK, gin.H{
+			"transactions": page.Transactions,
+			"pagination": gin.H{
+				"limit": limit,
+				"count": len(page.Transactions),
+				"next_cursor": page.NextCursor,
+			},
+		})
+		return
+	}
+	
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	transactions, err := h.service.GetTransactionsByWallet(c.Request.Context(), walletID, requester, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"pagination": gin.H{
+			"limit": limit,
+			"offset": offset,
+			"count": len(transactions),
+		},
+	})
+}
+
+// AddTransactionTag handles POST /api/v1/transactions/:id/tags/:tag
+func (h *TransactionHandler) AddTransactionTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	tags, err := h.service.AddTag(c.Request.Context(), id, c.Param("tag"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_id": id,
+		"tags": tags,
+	})
+}
+
+// RemoveTransactionTag handles DELETE /api/v1/transactions/:id/tags/:tag
+func (h *TransactionHandler) RemoveTransactionTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	tags, err := h.service.RemoveTag(c.Request.Context(), id, c.Param("tag"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_id": id,
+		"tags": tags,
+	})
+}
+
+// GetTransactionsByTag handles GET /api/v1/transactions/by-tag/:tag
+func (h *TransactionHandler) GetTransactionsByTag(c *gin.Context) {
+	limit := 50
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
 	if offsetStr := c.Query("offset"); offsetStr != "" {
 		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
 			offset = parsedOffset
 		}
 	}
 
-	transactions, err := h.service.GetTransactionsByWallet(c.Request.Context(), walletID, limit, offset)
+	transactions, err := h.service.GetByTag(c.Request.Context(), c.Param("tag"), limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -182,10 +436,56 @@ func (h *TransactionHandler) SetFraudScore(c *gin.Context) {
 	})
 }
 
-// GetWalletBalance handles GET /api/v1/wallets/:wallet_id/balance
-func (h *TransactionHandler) GetWalletBalance(c *gin.Context) {
-	walletIDStr := c.Param("wallet_id")
-	walletID, err := uuid.Parse(walletIDStr)
+// SetFraudScoresBulk handles PATCH /api/v1/transactions/fraud-scores
+func (h *TransactionHandler) SetFraudScoresBulk(c *gin.Context) {
+	var req struct {
+		Scores  map[uuid.UUID]float64  `json:"scores" binding:"required"`
+		Details map[string]interface{} `json:"details,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	err := h.service.SetFraudScoresBulk(c.Request.Context(), req.Scores, req.Details)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Fraud scores updated successfully",
+		"updated_count": len(req.Scores),
+	})
+}
+
+// CreateWallet handles POST /api/v1/wallets
+func (h *TransactionHandler) CreateWallet(c *gin.Context) {
+	var req service.CreateWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wallet, err := h.service.CreateWallet(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, wallet)
+}
+
+// GetWallet handles GET /api/v1/wallets/:wallet_id
+func (h *TransactionHandler) GetWallet(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid wallet ID format",
@@ -193,73 +493,767 @@ func (h *TransactionHandler) GetWalletBalance(c *gin.Context) {
 		return
 	}
 
-	currency := models.Currency(c.Query("currency"))
-	if currency == "" {
-		currency = models.USDCBDC // Default currency
+	wallet, err := h.service.GetWallet(c.Request.Context(), walletID)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
-	balance, err := h.service.GetWalletBalance(c.Request.Context(), walletID, currency)
+	c.JSON(http.StatusOK, wallet)
+}
+
+// CloseWallet handles POST /api/v1/wallets/:wallet_id/close
+func (h *TransactionHandler) CloseWallet(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	wallet, err := h.service.CloseWallet(c.Request.Context(), walletID)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, balance)
+	c.JSON(http.StatusOK, wallet)
 }
 
-// GetPendingTransactions handles GET /api/v1/transactions/pending
-func (h *TransactionHandler) GetPendingTransactions(c *gin.Context) {
-	limit := 100
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+// GetUserWallets handles GET /api/v1/users/:id/wallets, listing the wallets held by a user
+func (h *TransactionHandler) GetUserWallets(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID format",
+		})
+		return
 	}
 
-	transactions, err := h.service.GetPendingTransactions(c.Request.Context(), limit)
+	wallets, err := h.service.GetWalletsForUser(c.Request.Context(), userID)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"transactions": transactions,
-		"count": len(transactions),
+		"user_id": userID,
+		"wallets": wallets,
+		"count": len(wallets),
 	})
 }
 
-// GetTransactionStats handles GET /api/v1/wallets/:wallet_id/stats
-func (h *TransactionHandler) GetTransactionStats(c *gin.Context) {
-	walletIDStr := c.Param("wallet_id")
-	walletID, err := uuid.Parse(walletIDStr)
+// defaultLayeringWindow is how far back layering detection looks when window is not given
+const defaultLayeringWindow = 24 * time.Hour
+
+// GetUserLayering handles GET /api/v1/admin/users/:id/layering, reporting chains of
+// near-equal transfers through the user's wallets that fraud scoring treats as layering
+func (h *TransactionHandler) GetUserLayering(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid wallet ID format",
+			"error": "Invalid user ID format",
 		})
 		return
 	}
 
-	// Parse since parameter (default to 30 days ago)
-	since := time.Now().AddDate(0, 0, -30)
-	if sinceStr := c.Query("since"); sinceStr != "" {
-		if parsedSince, err := time.Parse(time.RFC3339, sinceStr); err == nil {
-			since = parsedSince
+	window := defaultLayeringWindow
+	if windowStr := c.Query("window"); windowStr != "" {
+		parsed, err := time.ParseDuration(windowStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid window duration, expected a positive duration such as 6h",
+			})
+			return
 		}
+		window = parsed
 	}
 
-	stats, err := h.service.GetTransactionStats(c.Request.Context(), walletID, since)
+	alerts, err := h.service.DetectLayering(c.Request.Context(), userID, window)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": userID,
+		"window": window.String(),
+		"alerts": alerts,
+		"count": len(alerts),
+	})
 }
 
-// GetServiceMetrics handles GET /api/v1/metrics/service
-func (h *TransactionHandler) GetServiceMetrics(c *gin.Context) {
-	metrics := h.service.GetServiceMetrics()
-	
+// LinkWalletAccount handles PUT /api/v1/admin/wallets/:wallet_id/account, making a user the
+// wallet's account holder
+func (h *TransactionHandler) LinkWalletAccount(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		UserID uuid.UUID `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wallet, err := h.service.LinkWalletToUser(c.Request.Context(), walletID, req.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// SetWalletCurrencies handles PUT /api/v1/admin/wallets/:wallet_id/currencies, restricting
+// the currencies a wallet may hold. An empty list allows every supported currency.
+func (h *TransactionHandler) SetWalletCurrencies(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		AllowedCurrencies []models.Currency `json:"allowed_currencies"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wallet, err := h.service.SetWalletAllowedCurrencies(c.Request.Context(), walletID, req.AllowedCurrencies)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// SetWalletMinBalance handles PUT /api/v1/wallets/:wallet_id/reserve
+func (h *TransactionHandler) SetWalletMinBalance(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Currency   models.Currency `json:"currency" binding:"required"`
+		MinBalance *float64        `json:"min_balance" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	balance, err := h.service.SetWalletMinBalance(c.Request.Context(), walletID, req.Currency, *req.MinBalance)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// FundWallet handles POST /api/v1/wallets/:wallet_id/fund
+func (h *TransactionHandler) FundWallet(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Currency    models.Currency `json:"currency" binding:"required"`
+		Amount      float64         `json:"amount" binding:"required"`
+		ExternalRef string          `json:"external_ref" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.FundWallet(c.Request.Context(), walletID, req.Currency, req.Amount, req.ExternalRef)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// EmergencyFreezeWallet handles POST /api/v1/emergency/freeze-wallet
+func (h *TransactionHandler) EmergencyFreezeWallet(c *gin.Context) {
+	var req struct {
+		WalletID uuid.UUID `json:"wallet_id" binding:"required"`
+		Reason   string    `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wallet, err := h.service.EmergencyFreezeWallet(c.Request.Context(), req.WalletID, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// RecoverWallet handles POST /api/v1/emergency/recover-wallet
+func (h *TransactionHandler) RecoverWallet(c *gin.Context) {
+	var req struct {
+		WalletID   uuid.UUID `json:"wallet_id" binding:"required"`
+		VerifiedBy uuid.UUID `json:"verified_by" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	wallet, err := h.service.RecoverWallet(c.Request.Context(), req.WalletID, req.VerifiedBy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// GetWalletBalance handles GET /api/v1/wallets/:wallet_id/balance
+func (h *TransactionHandler) GetWalletBalance(c *gin.Context) {
+	walletIDStr := c.Param("wallet_id")
+	walletID, err := uuid.Parse(walletIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	currency := models.Currency(c.Query("currency"))
+	if currency == "" {
+		currency = models.USDCBDC // Default currency
+	}
+
+	balance, err := h.service.GetWalletBalance(c.Request.Context(), walletID, currency)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetWalletBalances handles POST /api/v1/wallets/balances, fetching the balances of many
+// wallets in one currency
+func (h *TransactionHandler) GetWalletBalances(c *gin.Context) {
+	var req struct {
+		WalletIDs []uuid.UUID     `json:"wallet_ids" binding:"required,min=1"`
+		Currency  models.Currency `json:"currency"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Currency == "" {
+		req.Currency = models.USDCBDC // Default currency
+	}
+
+	balances, err := h.service.GetBalancesForWallets(c.Request.Context(), req.WalletIDs, req.Currency)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"currency": req.Currency,
+		"balances": balances,
+		"count": len(balances),
+	})
+}
+
+// GetWalletBaseline handles GET /api/v1/admin/wallets/:wallet_id/baseline, reporting the
+// moving averages fraud detection scores the wallet's transfers against
+func (h *TransactionHandler) GetWalletBaseline(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	currency := models.Currency(c.Query("currency"))
+	if currency == "" {
+		currency = models.USDCBDC // Default currency
+	}
+
+	baseline, err := h.service.GetWalletBaseline(c.Request.Context(), walletID, currency)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, baseline)
+}
+
+// GetTransferPolicy handles GET /api/v1/admin/wallets/:wallet_id/transfer-policy
+func (h *TransactionHandler) GetTransferPolicy(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	policy, err := h.service.GetTransferPolicy(c.Request.Context(), walletID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// SetTransferPolicy handles PUT /api/v1/admin/wallets/:wallet_id/transfer-policy, replacing
+// the wallet's mode and counterparty list
+func (h *TransactionHandler) SetTransferPolicy(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Mode           string      `json:"mode" binding:"required"`
+		Counterparties []uuid.UUID `json:"counterparties"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	mode, err := service.ParseTransferPolicyMode(req.Mode)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	policy, err := h.service.SetTransferPolicy(c.Request.Context(), walletID, mode, req.Counterparties)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// AddTransferPolicyCounterparties handles POST
+// /api/v1/admin/wallets/:wallet_id/transfer-policy/counterparties
+func (h *TransactionHandler) AddTransferPolicyCounterparties(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Counterparties []uuid.UUID `json:"counterparties" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.service.AddTransferPolicyCounterparties(c.Request.Context(), walletID, req.Counterparties)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// RemoveTransferPolicyCounterparty handles DELETE
+// /api/v1/admin/wallets/:wallet_id/transfer-policy/counterparties/:counterparty_id
+func (h *TransactionHandler) RemoveTransferPolicyCounterparty(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	counterparty, err := uuid.Parse(c.Param("counterparty_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid counterparty ID format",
+		})
+		return
+	}
+
+	policy, err := h.service.RemoveTransferPolicyCounterparty(c.Request.Context(), walletID, counterparty)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetPendingTransactions handles GET /api/v1/transactions/pending
+func (h *TransactionHandler) GetPendingTransactions(c *gin.Context) {
+	limit := 100
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	transactions, err := h.service.GetPendingTransactions(c.Request.Context(), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"count": len(transactions),
+	})
+}
+
+// defaultStuckThreshold is how old a pending transaction must be to be listed as stuck when
+// older_than is not given
+const defaultStuckThreshold = 15 * time.Minute
+
+// GetStuckTransactions handles GET /api/v1/admin/transactions/stuck
+func (h *TransactionHandler) GetStuckTransactions(c *gin.Context) {
+	olderThan := defaultStuckThreshold
+	if olderThanStr := c.Query("older_than"); olderThanStr != "" {
+		parsed, err := time.ParseDuration(olderThanStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid older_than duration, expected a positive duration such as 30m",
+			})
+			return
+		}
+		olderThan = parsed
+	}
+
+	limit := 100
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	transactions, err := h.service.GetStuckTransactions(c.Request.Context(), olderThan, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"count": len(transactions),
+		"older_than": olderThan.String(),
+	})
+}
+
+// ForceFailTransaction handles POST /api/v1/admin/transactions/:id/fail
+func (h *TransactionHandler) ForceFailTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Reason string     `json:"reason" binding:"required"`
+		UserID *uuid.UUID `json:"user_id,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.service.ForceFailTransaction(c.Request.Context(), id, req.Reason, req.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// RefundTransaction handles POST /api/v1/transactions/:id/refund
+func (h *TransactionHandler) RefundTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	var req struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+		Reason string  `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.service.PartialRefund(c.Request.Context(), id, req.Amount, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// Settle handles POST /api/v1/admin/transactions/:id/settle
+func (h *TransactionHandler) Settle(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	transaction, err := h.service.Settle(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ResyncTransaction handles POST /api/v1/admin/transactions/:id/resync
+func (h *TransactionHandler) ResyncTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	if err := h.service.ResyncTransaction(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_id": id,
+		"resynced": true,
+	})
+}
+
+// ResyncTransactions handles POST /api/v1/admin/transactions/resync
+func (h *TransactionHandler) ResyncTransactions(c *gin.Context) {
+	var req struct {
+		Since time.Time `json:"since" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resynced, err := h.service.ResyncSince(c.Request.Context(), req.Since)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resynced": resynced,
+		"since": req.Since,
+	})
+}
+
+// GetTransactionStats handles GET /api/v1/wallets/:wallet_id/stats
+func (h *TransactionHandler) GetTransactionStats(c *gin.Context) {
+	walletIDStr := c.Param("wallet_id")
+	walletID, err := uuid.Parse(walletIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	// Parse since parameter (default to 30 days ago)
+	since := time.Now().AddDate(0, 0, -30)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		if parsedSince, err := time.Parse(time.RFC3339, sinceStr); err == nil {
+			since = parsedSince
+		}
+	}
+
+	stats, err := h.service.GetTransactionStats(c.Request.Context(), walletID, since)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetSpendingByCategory handles GET /api/v1/wallets/:wallet_id/spending. The period defaults
+// to the last 30 days.
+func (h *TransactionHandler) GetSpendingByCategory(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("wallet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet ID format",
+		})
+		return
+	}
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid to time, expected RFC 3339",
+			})
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid from time, expected RFC 3339",
+			})
+			return
+		}
+	}
+
+	summary, err := h.service.GetSpendingByCategory(c.Request.Context(), walletID, from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetDailyVolume handles GET /api/v1/reports/volume, reporting a currency's completed
+// transaction volume for one calendar day in the reporting timezone
+func (h *TransactionHandler) GetDailyVolume(c *gin.Context) {
+	currency := models.Currency(c.Query("currency"))
+	if currency == "" {
+		currency = models.USDCBDC // Default currency
+	}
+
+	location := h.service.ReportingLocation()
+	day := time.Now().In(location)
+	if dayStr := c.Query("day"); dayStr != "" {
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", dayStr, location); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid day, expected YYYY-MM-DD",
+			})
+			return
+		}
+	}
+
+	volume, err := h.service.GetDailyVolume(c.Request.Context(), currency, day)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, volume)
+}
+
+// GetServiceMetrics handles GET /api/v1/metrics/service
+func (h *TransactionHandler) GetServiceMetrics(c *gin.Context) {
+	metrics := h.service.GetServiceMetrics()
+	
 	// Calculate average processing time
 	var avgProcessingTime time.Duration
 	if len(metrics.ProcessingTimes) > 0 {
@@ -270,25 +1264,96 @@ func (h *TransactionHandler) GetServiceMetrics(c *gin.Context) {
 		avgProcessingTime = total / time.Duration(len(metrics.ProcessingTimes))
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"success_count": metrics.SuccessCount,
 		"failure_count": metrics.FailureCount,
 		"total_requests": metrics.SuccessCount + metrics.FailureCount,
 		"success_rate": float64(metrics.SuccessCount) / float64(metrics.SuccessCount + metrics.FailureCount),
 		"avg_processing_time_ms": avgProcessingTime.Milliseconds(),
+		"p50_processing_time_ms": metrics.P50.Milliseconds(),
+		"p95_processing_time_ms": metrics.P95.Milliseconds(),
+		"p99_processing_time_ms": metrics.P99.Milliseconds(),
+		"max_processing_time_ms": metrics.Max.Milliseconds(),
 		"recent_processing_times": len(metrics.ProcessingTimes),
+	}
+	
+	// Percentiles are estimated from the Prometheus latency histogram when enabled
+	if percentiles := h.service.GetLatencyPercentiles(); percentiles != nil {
+		histogramMs := gin.H{}
+		for name, value := range percentiles {
+			histogramMs[name] = float64(value.Microseconds()) / 1000
+		}
+		response["histogram_percentiles_ms"] = histogramMs
+	}
+	
+	c.JSON(http.StatusOK, response)
+}
+
+// ArchiveTransactions handles POST /api/v1/admin/maintenance/archive
+func (h *TransactionHandler) ArchiveTransactions(c *gin.Context) {
+	var req struct {
+		Before time.Time `json:"before" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	archived, err := h.service.ArchiveTransactions(c.Request.Context(), req.Before)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"archived": archived,
+		"before": req.Before,
+	})
+}
+
+// GetEventStreamingHealth handles GET /api/v1/events/health
+func (h *TransactionHandler) GetEventStreamingHealth(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	status := h.service.CheckEventStreaming(ctx)
+	if !status.Healthy {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "degraded",
+			"error": status.Error,
+			"checked_at": status.CheckedAt,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "healthy",
+		"checked_at": status.CheckedAt,
 	})
 }
 
 // handleError handles different types of errors and returns appropriate HTTP responses
 func (h *TransactionHandler) handleError(c *gin.Context, err error) {
+	// A statement that timed out means the database is overloaded, not that the request failed
+	if database.IsStatementTimeout(err) {
+		err = errors.NewTransactionError(errors.ErrServiceUnavailable, "database statement timed out")
+	}
+
 	if echoPayErr, ok := err.(*errors.EchoPayError); ok {
-		c.JSON(echoPayErr.GetHTTPStatus(), gin.H{
+		response := gin.H{
 			"error": echoPayErr.Code,
 			"message": echoPayErr.Message,
 			"service": echoPayErr.Service,
 			"timestamp": echoPayErr.Timestamp,
-		})
+		}
+		if len(echoPayErr.Details) > 0 {
+			response["details"] = echoPayErr.Details
+		}
+		c.JSON(echoPayErr.GetHTTPStatus(), response)
 		return
 	}
 
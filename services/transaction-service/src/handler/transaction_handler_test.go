@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -36,19 +37,17 @@ func setupTestHandler(t *testing.T) (*TransactionHandler, *service.TransactionSe
 	return handler, transactionService
 }
 
-func setupTestWalletsForHandler(t *testing.T, service *service.TransactionService) (uuid.UUID, uuid.UUID) {
-	fromWallet := uuid.New()
-	toWallet := uuid.New()
-	
+func setupTestWalletsForHandler(t *testing.T, transactionService *service.TransactionService) (uuid.UUID, uuid.UUID) {
 	// Create wallets with initial balances
-	err := service.GetBalanceRepo().CreateWallet(fromWallet)
+	from, err := transactionService.CreateWallet(context.Background(), &service.CreateWalletRequest{OwnerID: uuid.New()})
 	require.NoError(t, err)
 	
-	err = service.GetBalanceRepo().CreateWallet(toWallet)
+	to, err := transactionService.CreateWallet(context.Background(), &service.CreateWalletRequest{OwnerID: uuid.New()})
 	require.NoError(t, err)
+	fromWallet, toWallet := from.ID, to.ID
 	
 	// Add funds to sender wallet
-	err = service.GetBalanceRepo().AddFunds(fromWallet, models.USDCBDC, 1000.0)
+	err = transactionService.GetBalanceRepo().AddFunds(fromWallet, models.USDCBDC, 1000.0)
 	require.NoError(t, err)
 	
 	return fromWallet, toWallet
@@ -340,6 +339,44 @@ func TestTransactionHandler_SetFraudScore(t *testing.T) {
 	assert.Equal(t, "Fraud score updated successfully", response["message"])
 }
 
+func TestTransactionHandler_CreateWallet(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+	
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/wallets", handler.CreateWallet)
+	router.GET("/api/v1/wallets/:wallet_id", handler.GetWallet)
+	
+	ownerID := uuid.New()
+	body, err := json.Marshal(map[string]interface{}{"owner_id": ownerID})
+	require.NoError(t, err)
+	
+	req, err := http.NewRequest("POST", "/api/v1/wallets", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusCreated, w.Code)
+	
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	
+	assert.Equal(t, ownerID.String(), response["owner_id"])
+	assert.Equal(t, "active", response["status"])
+	
+	// Unknown wallets are not created on lookup
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/wallets/%s", uuid.New()), nil)
+	require.NoError(t, err)
+	
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestTransactionHandler_GetWalletBalance(t *testing.T) {
 	handler, service := setupTestHandler(t)
 	fromWallet, _ := setupTestWalletsForHandler(t, service)
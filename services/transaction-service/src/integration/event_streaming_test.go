@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"net"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -12,9 +13,14 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 
 	"echopay/shared/libraries/database"
 	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/grpcserver"
+	"echopay/transaction-service/src/grpcserver/statuspb"
 	"echopay/transaction-service/src/handler"
 	"echopay/transaction-service/src/models"
 	"echopay/transaction-service/src/service"
@@ -50,19 +56,17 @@ func setupEventIntegrationTest(t *testing.T) (*service.TransactionService, *even
 	return transactionService, eventPublisher, statusTracker
 }
 
-func setupTestWalletsForEvents(t *testing.T, service *service.TransactionService) (uuid.UUID, uuid.UUID) {
-	fromWallet := uuid.New()
-	toWallet := uuid.New()
-	
+func setupTestWalletsForEvents(t *testing.T, transactionService *service.TransactionService) (uuid.UUID, uuid.UUID) {
 	// Create wallets with initial balances
-	err := service.GetBalanceRepo().CreateWallet(fromWallet)
+	from, err := transactionService.CreateWallet(context.Background(), &service.CreateWalletRequest{OwnerID: uuid.New()})
 	require.NoError(t, err)
 	
-	err = service.GetBalanceRepo().CreateWallet(toWallet)
+	to, err := transactionService.CreateWallet(context.Background(), &service.CreateWalletRequest{OwnerID: uuid.New()})
 	require.NoError(t, err)
+	fromWallet, toWallet := from.ID, to.ID
 	
 	// Add funds to sender wallet
-	err = service.GetBalanceRepo().AddFunds(fromWallet, models.USDCBDC, 1000.0)
+	err = transactionService.GetBalanceRepo().AddFunds(fromWallet, models.USDCBDC, 1000.0)
 	require.NoError(t, err)
 	
 	return fromWallet, toWallet
@@ -99,6 +103,11 @@ func TestEventPublisher_PublishTransactionEvent(t *testing.T) {
 	
 	err = eventPublisher.PublishTransactionEvent(ctx, transaction, events.EventTransactionCompleted)
 	assert.NoError(t, err)
+	
+	// Publishing is asynchronous; flushing confirms both events reached Kafka
+	flushCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	assert.NoError(t, eventPublisher.Flush(flushCtx))
 }
 
 func TestStatusTracker_SubscribeAndPublish(t *testing.T) {
@@ -233,6 +242,73 @@ func TestWebSocketIntegration(t *testing.T) {
 	assert.GreaterOrEqual(t, updateCount, 2, "Should receive at least 2 status updates")
 }
 
+func TestGRPCStatusStreamIntegration(t *testing.T) {
+	transactionService, _, statusTracker := setupEventIntegrationTest(t)
+	fromWallet, toWallet := setupTestWalletsForEvents(t, transactionService)
+	
+	// Serve the status stream over an in-memory listener
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpcserver.Register(statusTracker)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+	
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	
+	// Subscribe to wallet updates
+	stream, err := statuspb.NewTransactionStatusClient(conn).SubscribeStatus(ctx, &statuspb.StatusFilter{
+		WalletIds: []string{fromWallet.String(), toWallet.String()},
+	})
+	require.NoError(t, err)
+	
+	// The subscription is registered once the server handler starts
+	require.Eventually(t, func() bool {
+		return statusTracker.GetSubscriberCount() == 1
+	}, time.Second, 10*time.Millisecond)
+	
+	req := &service.TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+		Metadata: models.TransactionMetadata{
+			Description: "gRPC stream test transaction",
+			Category:    "test",
+		},
+	}
+	
+	transaction, err := transactionService.ProcessTransaction(context.Background(), req)
+	require.NoError(t, err)
+	
+	// Should receive the created and completed updates
+	created, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID.String(), created.TransactionId)
+	assert.Equal(t, string(models.StatusPending), created.Status)
+	assert.Equal(t, "Transaction created and processing", created.Message)
+	
+	completed, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID.String(), completed.TransactionId)
+	assert.Equal(t, string(models.StatusCompleted), completed.Status)
+	assert.Equal(t, "Transaction completed successfully", completed.Message)
+	
+	// Cancelling the stream removes the subscription
+	cancel()
+	assert.Eventually(t, func() bool {
+		return statusTracker.GetSubscriberCount() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestTransactionServiceEventIntegration(t *testing.T) {
 	transactionService, _, statusTracker := setupEventIntegrationTest(t)
 	fromWallet, toWallet := setupTestWalletsForEvents(t, transactionService)
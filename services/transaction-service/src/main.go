@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	
 	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/http"
 	"echopay/shared/libraries/logging"
 	"echopay/shared/libraries/monitoring"
+	"echopay/shared/libraries/webhooks"
+	"echopay/transaction-service/src/grpcserver"
 	"echopay/transaction-service/src/handler"
+	"echopay/transaction-service/src/repository"
 	"echopay/transaction-service/src/service"
 )
 
@@ -21,27 +32,217 @@ func main() {
 	
 	// Initialize logger
 	logger := logging.NewLogger("transaction-service")
+	logging.SetDefault(logger) // Request-scoped loggers carry the correlation ID
 	
 	// Initialize metrics
 	metrics := monitoring.NewMetrics("transaction-service")
-	_ = metrics // TODO: Use metrics in handlers
 	
 	// Initialize database
 	dbConfig := database.DefaultConfig()
 	dbConfig.Database = "echopay_transactions"
+	dbConfig.StatementTimeout = config.GetDatabaseConfig().StatementTimeout
 	db, err := database.NewPostgresDB(dbConfig)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer db.Close()
 	
+	// Export connection pool statistics to /metrics and log slow queries
+	dbMetricsConfig := config.GetDatabaseMetricsConfig()
+	db.SetSlowQueryLog(dbMetricsConfig.SlowQueryThreshold, logger)
+	go db.ReportStats(context.Background(), database.NewPoolMetrics("transaction-service"), dbMetricsConfig.StatsInterval)
+	
+	// Shed low-priority routes while the database is saturated or unreachable
+	loadSheddingConfig := config.GetLoadSheddingConfig()
+	loadState := http.NewLoadState(loadSheddingConfig.RetryAfter)
+	go loadState.Watch(context.Background(), "database", loadSheddingConfig.CheckInterval, db.LoadReason)
+	
+	// Reject writes while operators hold the service in read-only mode
+	readOnly := http.NewReadOnlyMode()
+	if readOnlyConfig := config.GetReadOnlyConfig(); readOnlyConfig.Enabled {
+		readOnly.Enable(readOnlyConfig.Reason)
+		logger.Warn("Transaction Service starting in read-only mode", "reason", readOnlyConfig.Reason)
+	}
+	
 	// Initialize service with event streaming
 	transactionService := service.NewTransactionService(db)
+	transactionService.SetPrometheusMetrics(metrics)
+	
+	// Accept the currencies configured for this deployment
+	currencyConfig := config.GetCurrencyConfig()
+	currencyRegistry := currency.NewRegistry(currencyConfig.Supported...)
+	for code, decimals := range currencyConfig.Decimals {
+		if err := currencyRegistry.SetDecimals(code, decimals); err != nil {
+			log.Fatal("Invalid currency configuration:", err)
+		}
+	}
+	transactionService.SetCurrencyRegistry(currencyRegistry)
+	
+	// Restrict transaction categories to the configured allow-list, if any
+	transactionService.SetAllowedCategories(config.GetCategoryConfig().Allowed)
+	
+	// Report daily volumes over calendar days in the configured timezone
+	reportingLocation, err := time.LoadLocation(config.GetReportingConfig().Timezone)
+	if err != nil {
+		log.Fatal("Invalid reporting timezone:", err)
+	}
+	transactionService.SetReportingLocation(reportingLocation)
+	
+	// Hold transfers until they are settled when delayed settlement is configured
+	settlementMode, err := service.ParseSettlementMode(config.GetSettlementConfig().Mode)
+	if err != nil {
+		log.Fatal("Invalid settlement configuration:", err)
+	}
+	transactionService.SetSettlementMode(settlementMode)
+	
+	// Collect per-transaction fees into the configured wallet when fees are enabled
+	feeConfig, err := service.NewFeeConfigFromConfig(config.GetFeeConfig())
+	if err != nil {
+		log.Fatal("Invalid fee configuration:", err)
+	}
+	transactionService.SetFeeConfig(feeConfig)
+	
+	// Enable metadata encryption when configured
+	encryptor, err := repository.NewEncryptorFromConfig(config.GetEncryptionConfig())
+	if err != nil {
+		log.Fatal("Failed to load metadata encryption key:", err)
+	}
+	if encryptor != nil {
+		transactionService.SetMetadataEncryptor(encryptor)
+	}
+	
+	// Notify registered webhook endpoints of reversals
+	webhookDispatcher := webhooks.NewDispatcher(config.GetWebhookConfig())
+	transactionService.SetWebhookDispatcher(webhookDispatcher)
+	
+	// Freeze a wallet's tokens in the token management service along with the wallet, and move
+	// tokens for token-backed transfers
+	tokenServiceConfig := config.GetTokenServiceConfig()
+	transactionService.SetTokenFreezer(service.NewHTTPTokenFreezer(tokenServiceConfig.URL, tokenServiceConfig.Timeout))
+	transactionService.SetTokenTransferrer(service.NewHTTPTokenTransferrer(tokenServiceConfig.URL, tokenServiceConfig.Timeout))
+	
+	// Optionally freeze the tokens moved by transactions scored as likely fraud, alert
+	// operations to transactions scored above the critical threshold, and let old scores fade
+	// from wallet risk
+	fraudConfig := config.GetFraudConfig()
+	if fraudConfig.AutoFreezeEnabled {
+		transactionService.SetAutoFreezeThreshold(fraudConfig.AutoFreezeThreshold)
+	}
+	transactionService.SetRiskAlerter(service.LoggingAlerter{}, fraudConfig.CriticalThreshold)
+	transactionService.SetFraudScoreHalfLife(fraudConfig.ScoreHalfLife)
+	
+	// Verify long audit trails in part as they are read, and in full in the background
+	auditVerificationConfig := config.GetAuditVerificationConfig()
+	transactionService.SetMaxVerifiedAuditEntries(auditVerificationConfig.MaxEntries)
+	
+	// Sign transaction receipts when a receipt signing key is configured
+	receiptKeys, err := service.NewReceiptKeysFromConfig(config.GetReceiptSigningConfig())
+	if err != nil {
+		log.Fatal("Failed to load receipt signing key:", err)
+	}
+	if receiptKeys != nil {
+		transactionService.SetReceiptKeys(receiptKeys)
+	}
+	
+	// Track startup so /readyz only reports ready once dependencies are available
+	readiness := http.NewReadinessTracker("migrations", "event_publisher")
 	
 	// Run database migrations
 	if err := transactionService.Migrate(); err != nil {
 		log.Fatal("Failed to run database migrations:", err)
 	}
+	readiness.MarkReady("migrations")
+	
+	// Wait for the event publisher to reach Kafka without blocking startup
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := transactionService.CheckEventPublisher(ctx)
+			cancel()
+			if err == nil {
+				readiness.MarkReady("event_publisher")
+				logger.Info("Event publisher connected")
+				break
+			}
+			readiness.MarkNotReady("event_publisher", err.Error())
+			time.Sleep(5 * time.Second)
+		}
+		
+		// Keep checking event streaming; failures degrade the service but it stays ready
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			status := transactionService.CheckEventStreaming(ctx)
+			cancel()
+			readiness.MarkDegraded("event_streaming", status.Error)
+			if !status.Healthy {
+				logger.Warn("Event streaming degraded", "error", status.Error)
+			}
+			time.Sleep(30 * time.Second)
+		}
+	}()
+	
+	// Return pending transactions held by crashed workers to the pool
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			released, err := transactionService.ReleaseExpiredClaims(context.Background(), now)
+			if err != nil {
+				logger.Error("Failed to release expired transaction claims", "error", err)
+				continue
+			}
+			if released > 0 {
+				logger.Info("Released expired transaction claims", "released", released)
+			}
+		}
+	}()
+	
+	// Fully verify the audit trails of transactions flagged while being read
+	go func() {
+		ticker := time.NewTicker(auditVerificationConfig.FullVerifyInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			verified, failed, err := transactionService.VerifyFlaggedTransactions(context.Background(), 100)
+			if err != nil {
+				logger.Error("Failed to verify flagged transactions", "error", err)
+				continue
+			}
+			if verified > 0 || len(failed) > 0 {
+				logger.Info("Verified flagged transactions", "verified", verified, "failed", len(failed))
+			}
+		}
+	}()
+	
+	// Stream status updates to backend consumers over gRPC on its own port
+	grpcConfig := config.GetGRPCConfig(9001)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcConfig.Port))
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	grpcServer := grpcserver.Register(transactionService.GetStatusTracker())
+	defer grpcServer.GracefulStop()
+	go func() {
+		logger.Info("Transaction Service gRPC server starting", "port", grpcConfig.Port)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	
+	// Serve runtime profiles on an internal address when explicitly enabled
+	profilingConfig := config.GetProfilingConfig()
+	profilingServer, err := http.NewProfilingServer(profilingConfig)
+	if err != nil {
+		log.Fatal("Invalid profiling configuration:", err)
+	}
+	if profilingServer != nil {
+		defer profilingServer.Close()
+		go func() {
+			logger.Warn("Transaction Service profiling server starting", "address", profilingConfig.Address)
+			if err := profilingServer.ListenAndServe(); err != nil {
+				logger.Error("Profiling server stopped", "error", err)
+			}
+		}()
+	}
 	
 	// Initialize handlers
 	transactionHandler := handler.NewTransactionHandler(transactionService)
@@ -57,13 +258,15 @@ func main() {
 	
 	// Add middleware
 	r.Use(http.RequestIDMiddleware())
-	r.Use(http.CORSMiddleware())
-	r.Use(http.MetricsMiddleware("transaction-service"))
+	r.Use(http.CORSMiddleware(http.CORSOptionsFromConfig(config.GetCORSConfig())))
+	r.Use(http.MetricsMiddleware("transaction-service", http.MetricsOptionsFromConfig(config.GetHTTPMetricsConfig())))
 	r.Use(http.ErrorHandler())
 	r.Use(http.RateLimitMiddleware(1000)) // 1000 requests per minute
+	r.Use(http.MaxBodyBytes(http.DefaultMaxBodyBytes))
 	
-	// Health check endpoint
+	// Liveness and readiness endpoints
 	r.GET("/health", http.HealthCheckHandler("transaction-service"))
+	r.GET("/readyz", http.ReadinessHandler("transaction-service", readiness))
 	
 	// Metrics endpoint
 	r.GET("/metrics", http.MetricsHandler())
@@ -71,23 +274,87 @@ func main() {
 	// WebSocket endpoint for real-time updates
 	r.GET("/ws/transactions", websocketHandler.HandleWebSocket)
 	
-	// API routes
-	v1 := r.Group("/api/v1")
+	// Read-only mode is toggled outside the v1 group so it can be lifted while writes are rejected
+	readOnlyAdmin := r.Group("/api/v1/admin/read-only", http.RequireRole("admin"))
+	readOnlyAdmin.GET("", http.ReadOnlyStatusHandler(readOnly))
+	readOnlyAdmin.PUT("", http.SetReadOnlyHandler(readOnly))
+	
+	// Emergency freezes are incident response, so they stay available while writes are rejected
+	r.POST("/api/v1/emergency/freeze-wallet", http.RequireAnyRole("compliance", "admin"), transactionHandler.EmergencyFreezeWallet)
+	
+	// Balances for several wallets are read with a POST body, so the route is kept out of the
+	// v1 group where read-only mode would reject it as a write
+	r.POST("/api/v1/wallets/balances", transactionHandler.GetWalletBalances)
+	
+	// API routes. Routes declared PriorityLow are shed while the service is overloaded, and
+	// writes are rejected while the service is read-only.
+	v1 := r.Group("/api/v1", readOnly.RejectWrites())
 	{
 		// Transaction endpoints
 		v1.POST("/transactions", transactionHandler.CreateTransaction)
+		v1.POST("/transactions/with-tokens", transactionHandler.CreateTokenSettlement)
+		v1.POST("/transactions/atomic-multi", http.MaxBodyBytes(http.BulkMaxBodyBytes), transactionHandler.CreateAtomicMultiTransfer)
 		v1.GET("/transactions/:id", transactionHandler.GetTransaction)
-		v1.PATCH("/transactions/:id/status", transactionHandler.UpdateTransactionStatus)
+		v1.GET("/transactions/:id/receipt", transactionHandler.GetTransactionReceipt)
+		v1.GET("/transactions/:id/chain", loadState.Priority(http.PriorityLow), transactionHandler.GetTransactionChain)
+		v1.PATCH("/transactions/:id/status", loadState.Priority(http.PriorityCritical), transactionHandler.UpdateTransactionStatus)
+		v1.PATCH("/transactions/fraud-scores", http.MaxBodyBytes(http.BulkMaxBodyBytes), transactionHandler.SetFraudScoresBulk)
 		v1.PATCH("/transactions/:id/fraud-score", transactionHandler.SetFraudScore)
+		v1.POST("/transactions/:id/refund", transactionHandler.RefundTransaction)
+		v1.POST("/transactions/:id/tags/:tag", transactionHandler.AddTransactionTag)
+		v1.DELETE("/transactions/:id/tags/:tag", transactionHandler.RemoveTransactionTag)
+		v1.GET("/transactions/by-tag/:tag", loadState.Priority(http.PriorityLow), transactionHandler.GetTransactionsByTag)
 		v1.GET("/transactions/pending", transactionHandler.GetPendingTransactions)
 		
 		// Wallet endpoints
-		v1.GET("/wallets/:wallet_id/transactions", transactionHandler.GetTransactionsByWallet)
+		v1.POST("/wallets", transactionHandler.CreateWallet)
+		v1.GET("/wallets/:wallet_id", transactionHandler.GetWallet)
+		v1.POST("/wallets/:wallet_id/close", transactionHandler.CloseWallet)
+		v1.GET("/wallets/:wallet_id/transactions", loadState.Priority(http.PriorityLow), transactionHandler.GetTransactionsByWallet)
 		v1.GET("/wallets/:wallet_id/balance", transactionHandler.GetWalletBalance)
-		v1.GET("/wallets/:wallet_id/stats", transactionHandler.GetTransactionStats)
+		v1.PUT("/wallets/:wallet_id/reserve", http.RequireRole("admin"), transactionHandler.SetWalletMinBalance)
+		v1.POST("/wallets/:wallet_id/fund", http.RequireRole("admin"), transactionHandler.FundWallet)
+		v1.GET("/wallets/:wallet_id/stats", loadState.Priority(http.PriorityLow), transactionHandler.GetTransactionStats)
+		v1.GET("/wallets/:wallet_id/spending", loadState.Priority(http.PriorityLow), transactionHandler.GetSpendingByCategory)
+		v1.GET("/users/:id/wallets", transactionHandler.GetUserWallets)
+		
+		// Emergency wallet recovery requires an administrator who verified the owner
+		v1.POST("/emergency/recover-wallet", http.RequireRole("admin"), transactionHandler.RecoverWallet)
+		
+		// Public keys for verifying transaction receipts offline
+		v1.GET("/receipts/keys", transactionHandler.GetReceiptKeys)
+		
+		// Aggregate reports
+		v1.GET("/reports/volume", loadState.Priority(http.PriorityLow), transactionHandler.GetDailyVolume)
 		
 		// Service metrics
-		v1.GET("/metrics/service", transactionHandler.GetServiceMetrics)
+		v1.GET("/metrics/service", loadState.Priority(http.PriorityLow), transactionHandler.GetServiceMetrics)
+		
+		// Event streaming health
+		v1.GET("/events/health", transactionHandler.GetEventStreamingHealth)
+		
+		// Admin endpoints for resolving stuck pending transactions and unpublished events
+		admin := v1.Group("/admin", http.RequireRole("admin"))
+		admin.GET("/transactions/stuck", transactionHandler.GetStuckTransactions)
+		admin.POST("/transactions/:id/fail", transactionHandler.ForceFailTransaction)
+		admin.POST("/transactions/:id/settle", transactionHandler.Settle)
+		admin.POST("/transactions/:id/resync", transactionHandler.ResyncTransaction)
+		admin.POST("/transactions/resync", transactionHandler.ResyncTransactions)
+		admin.GET("/wallets/:wallet_id/baseline", transactionHandler.GetWalletBaseline)
+		admin.PUT("/wallets/:wallet_id/account", transactionHandler.LinkWalletAccount)
+		admin.PUT("/wallets/:wallet_id/currencies", transactionHandler.SetWalletCurrencies)
+		admin.GET("/users/:id/layering", loadState.Priority(http.PriorityLow), transactionHandler.GetUserLayering)
+		admin.GET("/wallets/:wallet_id/transfer-policy", transactionHandler.GetTransferPolicy)
+		admin.PUT("/wallets/:wallet_id/transfer-policy", transactionHandler.SetTransferPolicy)
+		admin.POST("/wallets/:wallet_id/transfer-policy/counterparties", transactionHandler.AddTransferPolicyCounterparties)
+		admin.DELETE("/wallets/:wallet_id/transfer-policy/counterparties/:counterparty_id", transactionHandler.RemoveTransferPolicyCounterparty)
+		
+		// Maintenance endpoints
+		admin.POST("/maintenance/archive", loadState.Priority(http.PriorityLow), transactionHandler.ArchiveTransactions)
+		
+		// Webhook endpoints
+		v1.POST("/webhooks", http.RequireRole("admin"), webhooks.RegisterHandler(webhookDispatcher))
+		v1.GET("/webhooks/deliveries", http.RequireRole("admin"), webhooks.DeliveriesHandler(webhookDispatcher))
 		
 		// WebSocket connection info
 		v1.GET("/ws/info", func(c *gin.Context) {
@@ -102,7 +369,25 @@ func main() {
 	
 	// Start server
 	addr := fmt.Sprintf(":%d", cfg.Port)
-	if err := r.Run(addr); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &nethttp.Server{Addr: addr, Handler: r}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != nethttp.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+	
+	// On SIGINT or SIGTERM finish in-flight requests, then deliver events still buffered for Kafka
+	shutdown, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-shutdown.Done()
+	logger.Info("Transaction Service shutting down")
+	
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.WriteTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server shutdown failed", "error", err)
+	}
+	if err := transactionService.FlushEvents(shutdownCtx); err != nil {
+		logger.Error("Failed to deliver buffered events", "error", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+
+	"echopay/shared/libraries/money"
+)
+
+// amountIntegerDigits is the number of digits before the decimal point in amount columns,
+// as in the original DECIMAL(15,2) columns
+const amountIntegerDigits = 13
+
+// amountColumns lists every column holding a currency amount or balance
+var amountColumns = []struct {
+	table  string
+	column string
+}{
+	{"transactions", "amount"},
+	{"transaction_fees", "amount"},
+	{"transaction_balance_changes", "old_balance"},
+	{"transaction_balance_changes", "new_balance"},
+	{"transactions_archive", "amount"},
+	{"transaction_fees_archive", "amount"},
+	{"wallet_balances", "balance"},
+	{"wallet_balances", "min_balance"},
+	{"wallet_balances", "held"},
+	{"balance_holds", "amount"},
+	{"balance_holds", "fee"},
+	{"fund_events", "amount"},
+}
+
+// amountColumnType returns the column type that stores amounts with the given number of
+// decimal places exactly
+func amountColumnType(scale int) string {
+	return fmt.Sprintf("DECIMAL(%d,%d)", amountIntegerDigits+scale, scale)
+}
+
+// amountScaleMigration widens a column to the given scale if it stores fewer decimal places.
+// Columns are never narrowed, since that would round amounts already stored, and missing
+// tables are skipped.
+func amountScaleMigration(table, column string, scale int) string {
+	return fmt.Sprintf(`DO $$
+		BEGIN
+			IF (SELECT numeric_scale FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = '%s' AND column_name = '%s') < %d THEN
+				ALTER TABLE %s ALTER COLUMN %s TYPE %s;
+			END IF;
+		END
+		$$`, table, column, scale, table, column, amountColumnType(scale))
+}
+
+// MigrateAmountScale widens the amount and balance columns so they store every configured
+// currency exactly, e.g. to three decimal places once a three-decimal currency is configured.
+// Unlike Migrate it is checked on every startup, since the currency configuration can change.
+func (r *TransactionRepository) MigrateAmountScale() error {
+	scale := money.MaxDecimals()
+	if scale <= money.DefaultDecimals {
+		return nil // The columns were created with the default scale
+	}
+
+	for _, c := range amountColumns {
+		if _, err := r.db.Exec(amountScaleMigration(c.table, c.column, scale)); err != nil {
+			return fmt.Errorf("failed to widen %s.%s to %d decimal places: %w", c.table, c.column, scale, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/models"
+)
+
+func TestAmountColumnType(t *testing.T) {
+	assert.Equal(t, "DECIMAL(15,2)", amountColumnType(2))
+	assert.Equal(t, "DECIMAL(16,3)", amountColumnType(3))
+
+	migration := amountScaleMigration("wallet_balances", "balance", 3)
+	assert.Contains(t, migration, "table_name = 'wallet_balances' AND column_name = 'balance') < 3")
+	assert.Contains(t, migration, "ALTER TABLE wallet_balances ALTER COLUMN balance TYPE DECIMAL(16,3)")
+}
+
+func TestWalletBalanceRepository_CurrencyPrecision(t *testing.T) {
+	repo, db := setupTestBalanceRepo(t)
+	defer db.Close()
+
+	const zeroDecimal = models.Currency("XOF-CBDC")
+	const threeDecimal = models.Currency("BHD-CBDC")
+	require.NoError(t, money.SetDecimals(string(zeroDecimal), 0))
+	require.NoError(t, money.SetDecimals(string(threeDecimal), 3))
+	defer money.SetDecimals(string(zeroDecimal), 2)
+	defer money.SetDecimals(string(threeDecimal), 2)
+
+	// Widen the balance columns so three-decimal amounts are not rounded on write
+	require.NoError(t, NewTransactionRepository(db).MigrateAmountScale())
+
+	walletID := uuid.New()
+
+	t.Run("zero-decimal currency stores whole units", func(t *testing.T) {
+		require.NoError(t, repo.AddFunds(walletID, zeroDecimal, 1500))
+
+		err := repo.AddFunds(walletID, zeroDecimal, 0.5)
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "more than 0 decimal places"))
+
+		balance, err := repo.GetBalance(walletID, zeroDecimal)
+		require.NoError(t, err)
+		assert.Equal(t, 1500.0, balance.Balance)
+	})
+
+	t.Run("three-decimal currency stores fils exactly", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.AddFunds(walletID, threeDecimal, 0.125))
+		}
+
+		assert.Error(t, repo.AddFunds(walletID, threeDecimal, 0.0005))
+
+		balance, err := repo.GetBalance(walletID, threeDecimal)
+		require.NoError(t, err)
+		assert.Equal(t, 0.375, balance.Balance)
+		assert.Equal(t, "0.375", money.Format(balance.Balance, string(threeDecimal)))
+	})
+}
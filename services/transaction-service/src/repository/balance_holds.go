@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// BalanceHold is the part of a sender's balance reserved for a transfer awaiting settlement.
+// The sender's held balance includes Amount and Fee until the transfer settles or fails.
+type BalanceHold struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	WalletID      uuid.UUID       `json:"wallet_id"`
+	Currency      models.Currency `json:"currency"`
+	Amount        float64         `json:"amount"`
+	Fee           float64         `json:"fee"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// CreateHoldInTx records the hold placed on a sender's balance for a transaction
+func (r *WalletBalanceRepository) CreateHoldInTx(tx *sql.Tx, hold *BalanceHold) error {
+	_, err := tx.Exec(`
+		INSERT INTO balance_holds (transaction_id, wallet_id, currency, amount, fee, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, hold.TransactionID, hold.WalletID, hold.Currency, hold.Amount, hold.Fee, hold.CreatedAt)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record balance hold", "transaction-service")
+	}
+	return nil
+}
+
+// GetHold retrieves the hold placed for a transaction, or nil if it has none
+func (r *WalletBalanceRepository) GetHold(transactionID uuid.UUID) (*BalanceHold, error) {
+	return scanHold(r.db.QueryRow(`
+		SELECT transaction_id, wallet_id, currency, amount, fee, created_at
+		FROM balance_holds
+		WHERE transaction_id = $1
+	`, transactionID))
+}
+
+// GetHoldForUpdateInTx retrieves and locks the hold placed for a transaction, or returns nil
+// if it has none, so the hold cannot be released twice
+func (r *WalletBalanceRepository) GetHoldForUpdateInTx(tx *sql.Tx, transactionID uuid.UUID) (*BalanceHold, error) {
+	return scanHold(tx.QueryRow(`
+		SELECT transaction_id, wallet_id, currency, amount, fee, created_at
+		FROM balance_holds
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`, transactionID))
+}
+
+// DeleteHoldInTx removes a transaction's hold record once it has been settled or released
+func (r *WalletBalanceRepository) DeleteHoldInTx(tx *sql.Tx, transactionID uuid.UUID) error {
+	_, err := tx.Exec(`DELETE FROM balance_holds WHERE transaction_id = $1`, transactionID)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to delete balance hold", "transaction-service")
+	}
+	return nil
+}
+
+func scanHold(row *sql.Row) (*BalanceHold, error) {
+	var hold BalanceHold
+	err := row.Scan(&hold.TransactionID, &hold.WalletID, &hold.Currency, &hold.Amount, &hold.Fee, &hold.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get balance hold", "transaction-service")
+	}
+	return &hold, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+)
+
+// IntegrityCheck is a transaction whose audit trail was too long to verify in full as it was
+// read, and is waiting to be verified offline
+type IntegrityCheck struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	AuditEntries  int       `json:"audit_entries"` // Length of the audit trail when it was flagged
+	FlaggedAt     time.Time `json:"flagged_at"`
+}
+
+// FlagForFullVerification records that a transaction's audit trail needs full verification.
+// Flagging a transaction that is already flagged updates its trail length.
+func (r *TransactionRepository) FlagForFullVerification(transactionID uuid.UUID, auditEntries int) error {
+	query := `
+		INSERT INTO transaction_integrity_checks (transaction_id, audit_entries)
+		VALUES ($1, $2)
+		ON CONFLICT (transaction_id) DO UPDATE SET audit_entries = EXCLUDED.audit_entries
+	`
+
+	if _, err := r.db.Exec(query, transactionID, auditEntries); err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to flag transaction for full verification", "transaction-service")
+	}
+
+	return nil
+}
+
+// GetFlaggedForFullVerification returns up to limit flagged transactions, oldest flag first
+func (r *TransactionRepository) GetFlaggedForFullVerification(limit int) ([]IntegrityCheck, error) {
+	query := `
+		SELECT transaction_id, audit_entries, flagged_at
+		FROM transaction_integrity_checks
+		ORDER BY flagged_at, transaction_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transactions flagged for full verification", "transaction-service")
+	}
+	defer rows.Close()
+
+	var checks []IntegrityCheck
+	for rows.Next() {
+		var check IntegrityCheck
+		if err := rows.Scan(&check.TransactionID, &check.AuditEntries, &check.FlaggedAt); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan integrity check", "transaction-service")
+		}
+		checks = append(checks, check)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating integrity checks", "transaction-service")
+	}
+
+	return checks, nil
+}
+
+// ClearFullVerificationFlag removes a transaction's full verification flag once it has been
+// verified
+func (r *TransactionRepository) ClearFullVerificationFlag(transactionID uuid.UUID) error {
+	if _, err := r.db.Exec(`DELETE FROM transaction_integrity_checks WHERE transaction_id = $1`, transactionID); err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to clear full verification flag", "transaction-service")
+	}
+
+	return nil
+}
+
+// integrityCheckMigrations creates the table of transactions awaiting full audit trail
+// verification
+func integrityCheckMigrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS transaction_integrity_checks (
+			transaction_id UUID PRIMARY KEY,
+			audit_entries INTEGER NOT NULL,
+			flagged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaction_integrity_checks_flagged ON transaction_integrity_checks(flagged_at, transaction_id)`,
+	}
+}
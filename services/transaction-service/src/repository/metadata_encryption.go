@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"echopay/shared/libraries/config"
+	"echopay/transaction-service/src/models"
+)
+
+// encryptedValuePrefix marks a metadata value as ciphertext. The full format is
+// enc:<version>:<keyID>:<base64(nonce||ciphertext)>, which lets plaintext rows
+// written before encryption was enabled continue to read as-is.
+const (
+	encryptedValuePrefix  = "enc"
+	encryptedValueVersion = "v1"
+)
+
+// Encryptor encrypts and decrypts individual metadata values
+type Encryptor interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+}
+
+// AESGCMEncryptor encrypts values with AES-256-GCM. New values are encrypted
+// with the active key; older keys are kept for decryption during rotation.
+type AESGCMEncryptor struct {
+	activeKeyID string
+	keys        map[string]cipher.AEAD
+}
+
+// NewAESGCMEncryptor creates an encryptor that encrypts with the given key
+func NewAESGCMEncryptor(keyID string, key []byte) (*AESGCMEncryptor, error) {
+	e := &AESGCMEncryptor{
+		activeKeyID: keyID,
+		keys:        make(map[string]cipher.AEAD),
+	}
+	if err := e.AddKey(keyID, key); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewEncryptorFromConfig builds an encryptor from configuration.
+// It returns nil when encryption is disabled.
+func NewEncryptorFromConfig(cfg config.EncryptionConfig) (Encryptor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	encryptor, err := NewAESGCMEncryptor(cfg.KeyID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for keyID, encoded := range cfg.DecryptionKeys {
+		retired, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decryption key %s: %w", keyID, err)
+		}
+		if err := encryptor.AddKey(keyID, retired); err != nil {
+			return nil, err
+		}
+	}
+
+	return encryptor, nil
+}
+
+// AddKey registers a key that can be used to decrypt existing values
+func (e *AESGCMEncryptor) AddKey(keyID string, key []byte) error {
+	if keyID == "" || strings.Contains(keyID, ":") {
+		return fmt.Errorf("invalid encryption key ID %q", keyID)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("encryption key %s must be 32 bytes, got %d", keyID, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	e.keys[keyID] = aead
+	return nil
+}
+
+// Encrypt encrypts a value with the active key
+func (e *AESGCMEncryptor) Encrypt(plaintext string) (string, error) {
+	aead := e.keys[e.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(e.activeKeyID))
+	return strings.Join([]string{
+		encryptedValuePrefix,
+		encryptedValueVersion,
+		e.activeKeyID,
+		base64.StdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt. Values without the
+// encryption prefix are treated as legacy plaintext and returned unchanged.
+func (e *AESGCMEncryptor) Decrypt(value string) (string, error) {
+	if !isEncryptedValue(value) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(value, ":", 4)
+	if len(parts) != 4 || parts[1] != encryptedValueVersion {
+		return "", fmt.Errorf("unsupported encrypted value format")
+	}
+
+	keyID := parts[2]
+	aead, ok := e.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("unknown encryption key %s", keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// IsReservedValue reports whether a caller-supplied value starts with the encryption prefix
+// and so would be read back as ciphertext
+func IsReservedValue(value string) bool {
+	return isEncryptedValue(value)
+}
+
+// isEncryptedValue reports whether a stored value carries the encryption prefix
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix+":")
+}
+
+// sensitiveMetadataFields returns the metadata fields that are encrypted at rest
+func sensitiveMetadataFields(metadata *models.TransactionMetadata) []*string {
+	return []*string{&metadata.Description}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"echopay/transaction-service/src/models"
+)
+
+func testEncryptionKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestAESGCMEncryptor_RoundTrip(t *testing.T) {
+	encryptor, err := NewAESGCMEncryptor("meta-1", testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	
+	ciphertext, err := encryptor.Encrypt("Rent for March")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	
+	if !strings.HasPrefix(ciphertext, "enc:v1:meta-1:") {
+		t.Errorf("Expected versioned ciphertext with key ID, got %s", ciphertext)
+	}
+	if strings.Contains(ciphertext, "Rent for March") {
+		t.Error("Ciphertext should not contain the plaintext")
+	}
+	
+	plaintext, err := encryptor.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Failed to decrypt: %v", err)
+	}
+	if plaintext != "Rent for March" {
+		t.Errorf("Expected 'Rent for March', got %s", plaintext)
+	}
+}
+
+func TestAESGCMEncryptor_WrongKeyFails(t *testing.T) {
+	encryptor, err := NewAESGCMEncryptor("meta-1", testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	ciphertext, err := encryptor.Encrypt("Rent for March")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	
+	// Same key ID, different key material
+	wrongKey, err := NewAESGCMEncryptor("meta-1", testEncryptionKey(2))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	if _, err := wrongKey.Decrypt(ciphertext); err == nil {
+		t.Error("Expected decryption with the wrong key to fail")
+	}
+	
+	// Key ID not known to the encryptor
+	otherKeyID, err := NewAESGCMEncryptor("meta-2", testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	if _, err := otherKeyID.Decrypt(ciphertext); err == nil {
+		t.Error("Expected decryption with an unknown key ID to fail")
+	}
+}
+
+func TestAESGCMEncryptor_KeyRotation(t *testing.T) {
+	oldEncryptor, err := NewAESGCMEncryptor("meta-1", testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	oldCiphertext, err := oldEncryptor.Encrypt("Invoice 42")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	
+	rotated, err := NewAESGCMEncryptor("meta-2", testEncryptionKey(2))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	if err := rotated.AddKey("meta-1", testEncryptionKey(1)); err != nil {
+		t.Fatalf("Failed to add retired key: %v", err)
+	}
+	
+	plaintext, err := rotated.Decrypt(oldCiphertext)
+	if err != nil {
+		t.Fatalf("Failed to decrypt with retired key: %v", err)
+	}
+	if plaintext != "Invoice 42" {
+		t.Errorf("Expected 'Invoice 42', got %s", plaintext)
+	}
+	
+	newCiphertext, err := rotated.Encrypt("Invoice 43")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	if !strings.HasPrefix(newCiphertext, "enc:v1:meta-2:") {
+		t.Errorf("Expected new values to use the active key, got %s", newCiphertext)
+	}
+}
+
+func TestAESGCMEncryptor_InvalidKey(t *testing.T) {
+	if _, err := NewAESGCMEncryptor("meta-1", []byte("too-short")); err == nil {
+		t.Error("Expected error for a key that is not 32 bytes")
+	}
+	if _, err := NewAESGCMEncryptor("", testEncryptionKey(1)); err == nil {
+		t.Error("Expected error for an empty key ID")
+	}
+}
+
+func TestTransactionRepository_MetadataEncryption(t *testing.T) {
+	encryptor, err := NewAESGCMEncryptor("meta-1", testEncryptionKey(1))
+	if err != nil {
+		t.Fatalf("Failed to create encryptor: %v", err)
+	}
+	
+	repo := &TransactionRepository{}
+	metadata := models.TransactionMetadata{Description: "Payroll", Category: "business"}
+	
+	// Encryption is opt-in: without an encryptor metadata is stored as-is
+	stored, err := repo.encryptMetadata(metadata)
+	if err != nil {
+		t.Fatalf("Failed to encrypt metadata: %v", err)
+	}
+	if stored.Description != "Payroll" {
+		t.Errorf("Expected plaintext description, got %s", stored.Description)
+	}
+	
+	repo.SetEncryptor(encryptor)
+	stored, err = repo.encryptMetadata(metadata)
+	if err != nil {
+		t.Fatalf("Failed to encrypt metadata: %v", err)
+	}
+	if !isEncryptedValue(stored.Description) {
+		t.Errorf("Expected encrypted description, got %s", stored.Description)
+	}
+	if stored.Category != "business" {
+		t.Errorf("Expected category to be left unchanged, got %s", stored.Category)
+	}
+	if metadata.Description != "Payroll" {
+		t.Error("Encrypting should not modify the caller's metadata")
+	}
+	
+	if err := repo.decryptMetadata(&stored); err != nil {
+		t.Fatalf("Failed to decrypt metadata: %v", err)
+	}
+	if stored.Description != "Payroll" {
+		t.Errorf("Expected 'Payroll', got %s", stored.Description)
+	}
+	
+	// Rows written before encryption was enabled still read as plaintext
+	legacy := models.TransactionMetadata{Description: "Legacy row"}
+	if err := repo.decryptMetadata(&legacy); err != nil {
+		t.Fatalf("Failed to read plaintext metadata: %v", err)
+	}
+	if legacy.Description != "Legacy row" {
+		t.Errorf("Expected 'Legacy row', got %s", legacy.Description)
+	}
+	
+	// A value that only looks encrypted is still encrypted and reads back unchanged
+	lookalike := models.TransactionMetadata{Description: "enc:v1:meta-1:AAAA"}
+	stored, err = repo.encryptMetadata(lookalike)
+	if err != nil {
+		t.Fatalf("Failed to encrypt metadata: %v", err)
+	}
+	if stored.Description == lookalike.Description {
+		t.Error("Expected a lookalike value to be encrypted")
+	}
+	if err := repo.decryptMetadata(&stored); err != nil {
+		t.Fatalf("Failed to decrypt metadata: %v", err)
+	}
+	if stored.Description != lookalike.Description {
+		t.Errorf("Expected %s, got %s", lookalike.Description, stored.Description)
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// Hot and cold table names for transactions and their audit entries
+const (
+	transactionsTable            = "transactions"
+	transactionAuditTable        = "transaction_audit"
+	transactionsArchiveTable     = "transactions_archive"
+	transactionAuditArchiveTable = "transaction_audit_archive"
+)
+
+// archiveBatchSize is the number of transactions moved per archival transaction
+const archiveBatchSize = 500
+
+// ArchiveTransactions moves completed and reversed transactions settled before the cutoff,
+// along with their audit entries and fees, into the archive tables. Each batch is moved in
+// its own database transaction so archival never holds long locks on the hot tables.
+// A deadline on ctx longer than the statement timeout extends the timeout for each batch.
+// It returns the total number of transactions archived.
+func (r *TransactionRepository) ArchiveTransactions(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+	for {
+		archived, err := r.archiveBatch(ctx, before, archiveBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += archived
+		if archived < archiveBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// archiveBatch archives up to limit transactions in a single database transaction
+func (r *TransactionRepository) archiveBatch(ctx context.Context, before time.Time, limit int) (int, error) {
+	var archived int
+	err := r.db.TransactionContext(ctx, func(tx *sql.Tx) error {
+		// Skip rows locked by in-flight updates; they are picked up by a later run
+		rows, err := tx.Query(`
+			SELECT id FROM transactions
+			WHERE status IN ($1, $2)
+			  AND COALESCE(settled_at, created_at) < $3
+			ORDER BY created_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		`, models.StatusCompleted, models.StatusReversed, before, limit)
+		if err != nil {
+			return err
+		}
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		batch := pq.Array(ids)
+		statements := []string{
+			`INSERT INTO transactions_archive (
+				id, from_wallet_id, to_wallet_id, amount, currency, status, fraud_score,
+				created_at, settled_at, metadata, correlation_id, archived_at
+			)
+			SELECT id, from_wallet_id, to_wallet_id, amount, currency, status, fraud_score,
+				   created_at, settled_at, metadata, correlation_id, NOW()
+			FROM transactions WHERE id = ANY($1)`,
+			`INSERT INTO transaction_audit_archive (
+				id, transaction_id, action, previous_state, new_state, timestamp,
+				user_id, service_id, details, signature, archived_at
+			)
+			SELECT id, transaction_id, action, previous_state, new_state, timestamp,
+				   user_id, service_id, details, signature, NOW()
+			FROM transaction_audit WHERE transaction_id = ANY($1)`,
+			`INSERT INTO transaction_fees_archive (
+				transaction_id, fee_wallet_id, currency, amount, created_at, archived_at
+			)
+			SELECT transaction_id, fee_wallet_id, currency, amount, created_at, NOW()
+			FROM transaction_fees WHERE transaction_id = ANY($1)`,
+			// Audit entries and fees are removed from the hot tables by ON DELETE CASCADE
+			`DELETE FROM transactions WHERE id = ANY($1)`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, batch); err != nil {
+				return err
+			}
+		}
+
+		archived = len(ids)
+		return nil
+	})
+
+	if err != nil {
+		return 0, errors.WrapError(err, errors.ErrTransactionFailed, "failed to archive transactions", "transaction-service")
+	}
+	return archived, nil
+}
+
+// archiveMigrations creates the cold tables that hold archived transactions
+func archiveMigrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS transactions_archive (
+			id UUID PRIMARY KEY,
+			from_wallet_id UUID NOT NULL,
+			to_wallet_id UUID NOT NULL,
+			amount DECIMAL(15,2) NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			fraud_score DECIMAL(3,2),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			settled_at TIMESTAMP WITH TIME ZONE,
+			metadata JSONB,
+			correlation_id UUID,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS transaction_audit_archive (
+			id UUID PRIMARY KEY,
+			transaction_id UUID NOT NULL REFERENCES transactions_archive(id) ON DELETE CASCADE,
+			action VARCHAR(50) NOT NULL,
+			previous_state VARCHAR(100),
+			new_state VARCHAR(100),
+			timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+			user_id UUID,
+			service_id VARCHAR(50) NOT NULL,
+			details JSONB,
+			signature VARCHAR(64) NOT NULL,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS transaction_fees_archive (
+			transaction_id UUID PRIMARY KEY REFERENCES transactions_archive(id) ON DELETE CASCADE,
+			fee_wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			amount DECIMAL(15,2) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_archive_from_wallet ON transactions_archive(from_wallet_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_archive_to_wallet ON transactions_archive(to_wallet_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_archive_created_at ON transactions_archive(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaction_audit_archive_transaction_id ON transaction_audit_archive(transaction_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaction_fees_archive_fee_wallet ON transaction_fees_archive(fee_wallet_id)`,
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/errors"
+)
+
+// TransferNotes are the private memos a transfer's sender and recipient each keep on it.
+// They are stored under the sender_note and recipient_note keys of the transaction metadata
+// and are encrypted at rest like the description.
+type TransferNotes struct {
+	SenderNote    string
+	RecipientNote string
+}
+
+// IsEmpty reports whether neither party left a note
+func (n TransferNotes) IsEmpty() bool {
+	return n.SenderNote == "" && n.RecipientNote == ""
+}
+
+// fields returns the note fields for encryption
+func (n *TransferNotes) fields() []*string {
+	return []*string{&n.SenderNote, &n.RecipientNote}
+}
+
+// RecordTransferNotesInTx records a transaction's transfer notes in its metadata, within the
+// database transaction that applied it
+func (r *TransactionRepository) RecordTransferNotesInTx(tx *sql.Tx, transactionID uuid.UUID, notes TransferNotes) error {
+	if err := r.encryptFields(notes.fields()); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE transactions
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('sender_note', $2::text, 'recipient_note', $3::text)
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(query, transactionID, notes.SenderNote, notes.RecipientNote)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record transfer notes", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found for transfer notes")
+	}
+
+	return nil
+}
+
+// GetTransferNotes retrieves the transfer notes of the given transactions. Transactions
+// without notes are left out of the result.
+func (r *TransactionRepository) GetTransferNotes(transactionIDs []uuid.UUID) (map[uuid.UUID]TransferNotes, error) {
+	notes := make(map[uuid.UUID]TransferNotes)
+	if len(transactionIDs) == 0 {
+		return notes, nil
+	}
+
+	ids := make([]string, len(transactionIDs))
+	for i, id := range transactionIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT id, COALESCE(metadata->>'sender_note', ''), COALESCE(metadata->>'recipient_note', '')
+		FROM transactions
+		WHERE id = ANY($1) AND (metadata ? 'sender_note' OR metadata ? 'recipient_note')
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transfer notes", "transaction-service")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var note TransferNotes
+		if err := rows.Scan(&id, &note.SenderNote, &note.RecipientNote); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transfer notes", "transaction-service")
+		}
+		if err := r.decryptFields(note.fields()); err != nil {
+			return nil, err
+		}
+		notes[id] = note
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to iterate transfer notes", "transaction-service")
+	}
+
+	return notes, nil
+}
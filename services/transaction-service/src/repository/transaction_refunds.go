@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// GetRefundStateForUpdateInTx locks a transaction for refunding and returns its status and the
+// total already refunded from it, which is kept under the refunded_amount key of its metadata.
+// Locking the original serializes concurrent refunds of the same transaction.
+func (r *TransactionRepository) GetRefundStateForUpdateInTx(tx *sql.Tx, transactionID uuid.UUID) (models.TransactionStatus, float64, error) {
+	query := `
+		SELECT status, COALESCE((metadata->>'refunded_amount')::numeric, 0)
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var status models.TransactionStatus
+	var refunded float64
+	err := tx.QueryRow(query, transactionID).Scan(&status, &refunded)
+	if err == sql.ErrNoRows {
+		return "", 0, errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found or archived")
+	}
+	if err != nil {
+		return "", 0, errors.WrapError(err, errors.ErrTransactionFailed, "failed to lock transaction for refund", "transaction-service")
+	}
+
+	return status, refunded, nil
+}
+
+// RecordRefundInTx links a refund to the transaction it refunds, within the database
+// transaction that applied it. The refund's metadata records the original under refund_of,
+// and the original's metadata lists its refunds and keeps their running total.
+func (r *TransactionRepository) RecordRefundInTx(tx *sql.Tx, refundID, originalID uuid.UUID, amount float64, reason string) error {
+	refundQuery := `
+		UPDATE transactions
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('refund_of', $2::text, 'refund_reason', $3::text)
+		WHERE id = $1
+	`
+	if err := execSingleRow(tx, refundQuery, "refund", refundID, originalID, reason); err != nil {
+		return err
+	}
+
+	originalQuery := `
+		UPDATE transactions
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(
+			'refunded_amount', COALESCE((metadata->>'refunded_amount')::numeric, 0) + $2::numeric,
+			'refunds', COALESCE(metadata->'refunds', '[]'::jsonb) || to_jsonb($3::text)
+		)
+		WHERE id = $1
+	`
+	return execSingleRow(tx, originalQuery, "refunded transaction", originalID, amount, refundID)
+}
+
+// execSingleRow runs an update that must match exactly one transaction
+func execSingleRow(tx *sql.Tx, query, subject string, args ...interface{}) error {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record "+subject, "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, subject+" not found")
+	}
+
+	return nil
+}
+
+// RefundLinks are the refund links recorded in a transaction's metadata
+type RefundLinks struct {
+	RefundOf *uuid.UUID  // Transaction this one refunds, if it is a refund
+	Refunds  []uuid.UUID // Refunds of this transaction
+	Archived bool        // Whether the transaction has been moved to the archive
+}
+
+// GetRefundLinks returns the refund links of a transaction, including an archived one
+func (r *TransactionRepository) GetRefundLinks(transactionID uuid.UUID) (*RefundLinks, error) {
+	query := `
+		SELECT metadata->>'refund_of', COALESCE(metadata->'refunds', '[]'::jsonb), FALSE
+		FROM transactions WHERE id = $1
+		UNION ALL
+		SELECT metadata->>'refund_of', COALESCE(metadata->'refunds', '[]'::jsonb), TRUE
+		FROM transactions_archive WHERE id = $1
+		LIMIT 1
+	`
+
+	var refundOf sql.NullString
+	var refunds []byte
+	var links RefundLinks
+	err := r.db.QueryRow(query, transactionID).Scan(&refundOf, &refunds, &links.Archived)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get refund links", "transaction-service")
+	}
+
+	if refundOf.Valid {
+		originalID, err := uuid.Parse(refundOf.String)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "invalid refund_of link", "transaction-service")
+		}
+		links.RefundOf = &originalID
+	}
+
+	if err := json.Unmarshal(refunds, &links.Refunds); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "invalid refunds link", "transaction-service")
+	}
+
+	return &links, nil
+}
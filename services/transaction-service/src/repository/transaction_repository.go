@@ -2,6 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,7 +15,8 @@ import (
 
 // TransactionRepository handles database operations for transactions
 type TransactionRepository struct {
-	db *database.PostgresDB
+	db        *database.PostgresDB
+	encryptor Encryptor
 }
 
 // NewTransactionRepository creates a new transaction repository
@@ -20,6 +24,12 @@ func NewTransactionRepository(db *database.PostgresDB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// SetEncryptor enables field-level encryption of sensitive metadata.
+// A nil encryptor stores metadata in plaintext.
+func (r *TransactionRepository) SetEncryptor(encryptor Encryptor) {
+	r.encryptor = encryptor
+}
+
 // Create inserts a new transaction and its initial audit entry
 func (r *TransactionRepository) Create(transaction *models.Transaction) error {
 	return r.db.Transaction(func(tx *sql.Tx) error {
@@ -37,7 +47,12 @@ func (r *TransactionRepository) CreateInTx(tx *sql.Tx, transaction *models.Trans
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	
-	_, err := tx.Exec(query,
+	metadata, err := r.encryptMetadata(transaction.Metadata)
+	if err != nil {
+		return err
+	}
+	
+	_, err = tx.Exec(query,
 		transaction.ID,
 		transaction.FromWallet,
 		transaction.ToWallet,
@@ -47,7 +62,7 @@ func (r *TransactionRepository) CreateInTx(tx *sql.Tx, transaction *models.Trans
 		transaction.FraudScore,
 		transaction.CreatedAt,
 		transaction.SettledAt,
-		transaction.Metadata,
+		metadata,
 	)
 	if err != nil {
 		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to insert transaction", "transaction-service")
@@ -64,15 +79,34 @@ func (r *TransactionRepository) CreateInTx(tx *sql.Tx, transaction *models.Trans
 	return nil
 }
 
-// GetByID retrieves a transaction by ID with its audit trail
+// GetByID retrieves a transaction by ID with its audit trail. Transactions moved to
+// the archive by ArchiveTransactions are returned from the archive tables.
 func (r *TransactionRepository) GetByID(id uuid.UUID) (*models.Transaction, error) {
+	transaction, err := r.getByIDFrom(transactionsTable, transactionAuditTable, id)
+	if err == sql.ErrNoRows {
+		transaction, err = r.getByIDFrom(transactionsArchiveTable, transactionAuditArchiveTable, id)
+	}
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found")
+		}
+		return nil, err
+	}
+	
+	return transaction, nil
+}
+
+// getByIDFrom retrieves a transaction and its audit trail from the given tables.
+// It returns sql.ErrNoRows unwrapped when the transaction is not present.
+func (r *TransactionRepository) getByIDFrom(table, auditTable string, id uuid.UUID) (*models.Transaction, error) {
 	// Get transaction
-	query := `
+	query := fmt.Sprintf(`
 		SELECT id, from_wallet_id, to_wallet_id, amount, currency, 
 			   status, fraud_score, created_at, settled_at, metadata
-		FROM transactions 
+		FROM %s 
 		WHERE id = $1
-	`
+	`, table)
 	
 	var transaction models.Transaction
 	var fraudScore sql.NullFloat64
@@ -93,11 +127,15 @@ func (r *TransactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 	
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found")
+			return nil, err
 		}
 		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transaction", "transaction-service")
 	}
 	
+	if err := r.decryptMetadata(&transaction.Metadata); err != nil {
+		return nil, err
+	}
+	
 	// Handle nullable fields
 	if fraudScore.Valid {
 		transaction.FraudScore = &fraudScore.Float64
@@ -107,7 +145,7 @@ func (r *TransactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 	}
 	
 	// Load audit trail
-	auditTrail, err := r.getAuditTrail(id)
+	auditTrail, err := r.getAuditTrailFrom(auditTable, id)
 	if err != nil {
 		return nil, err
 	}
@@ -119,50 +157,66 @@ func (r *TransactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 // Update updates a transaction and adds new audit entries
 func (r *TransactionRepository) Update(transaction *models.Transaction) error {
 	return r.db.Transaction(func(tx *sql.Tx) error {
-		// Update transaction
-		query := `
-			UPDATE transactions 
-			SET status = $2, fraud_score = $3, settled_at = $4, metadata = $5
-			WHERE id = $1
-		`
-		
-		result, err := tx.Exec(query,
-			transaction.ID,
-			transaction.Status,
-			transaction.FraudScore,
-			transaction.SettledAt,
-			transaction.Metadata,
-		)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update transaction", "transaction-service")
-		}
-		
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
-		}
-		
-		if rowsAffected == 0 {
-			return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found for update")
-		}
+		return r.updateInTx(tx, transaction)
+	})
+}
 
-		// Get existing audit entries count to determine which are new
-		var existingCount int
-		err = tx.QueryRow("SELECT COUNT(*) FROM transaction_audit WHERE transaction_id = $1", transaction.ID).Scan(&existingCount)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to count existing audit entries", "transaction-service")
-		}
+// UpdateInTx updates a transaction and adds new audit entries within an existing transaction
+func (r *TransactionRepository) UpdateInTx(tx *sql.Tx, transaction *models.Transaction) error {
+	return r.updateInTx(tx, transaction)
+}
 
-		// Insert new audit entries
-		for i := existingCount; i < len(transaction.AuditTrail); i++ {
-			err = r.insertAuditEntry(tx, transaction.AuditTrail[i])
-			if err != nil {
-				return err
-			}
+// updateInTx updates a transaction and adds new audit entries within an existing transaction.
+// Metadata keys the model does not carry, such as recorded token IDs, are kept.
+func (r *TransactionRepository) updateInTx(tx *sql.Tx, transaction *models.Transaction) error {
+	// Update transaction
+	query := `
+		UPDATE transactions 
+		SET status = $2, fraud_score = $3, settled_at = $4, metadata = COALESCE(metadata, '{}'::jsonb) || $5::jsonb
+		WHERE id = $1
+	`
+	
+	metadata, err := r.encryptMetadata(transaction.Metadata)
+	if err != nil {
+		return err
+	}
+	
+	result, err := tx.Exec(query,
+		transaction.ID,
+		transaction.Status,
+		transaction.FraudScore,
+		transaction.SettledAt,
+		metadata,
+	)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update transaction", "transaction-service")
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+	
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found for update")
+	}
+
+	// Get existing audit entries count to determine which are new
+	var existingCount int
+	err = tx.QueryRow("SELECT COUNT(*) FROM transaction_audit WHERE transaction_id = $1", transaction.ID).Scan(&existingCount)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to count existing audit entries", "transaction-service")
+	}
+
+	// Insert new audit entries
+	for i := existingCount; i < len(transaction.AuditTrail); i++ {
+		err = r.insertAuditEntry(tx, transaction.AuditTrail[i])
+		if err != nil {
+			return err
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
 // GetByWallet retrieves transactions for a specific wallet
@@ -182,6 +236,89 @@ func (r *TransactionRepository) GetByWallet(walletID uuid.UUID, limit, offset in
 	}
 	defer rows.Close()
 	
+	return r.scanTransactionRows(rows)
+}
+
+// WalletCursor marks a position in a wallet's transaction listing, which is ordered
+// newest first by (created_at, id)
+type WalletCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c WalletCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeWalletCursor parses a cursor produced by WalletCursor.Encode
+func DecodeWalletCursor(encoded string) (WalletCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return WalletCursor{}, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return WalletCursor{}, fmt.Errorf("invalid cursor format")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return WalletCursor{}, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return WalletCursor{}, fmt.Errorf("invalid cursor ID: %w", err)
+	}
+
+	return WalletCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// GetByWalletCursor retrieves a page of transactions for a wallet using keyset pagination.
+// Results continue after the (afterCreatedAt, afterID) position in newest-first order, so
+// transactions inserted between page fetches never shift later pages. A zero afterCreatedAt
+// starts from the newest transaction. The returned cursor is nil on the last page.
+func (r *TransactionRepository) GetByWalletCursor(walletID uuid.UUID, afterCreatedAt time.Time, afterID uuid.UUID, limit int) ([]*models.Transaction, *WalletCursor, error) {
+	query := `
+		SELECT id, from_wallet_id, to_wallet_id, amount, currency, 
+			   status, fraud_score, created_at, settled_at, metadata
+		FROM transactions 
+		WHERE (from_wallet_id = $1 OR to_wallet_id = $1)
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2, $3))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+	
+	var after interface{}
+	if !afterCreatedAt.IsZero() {
+		after = afterCreatedAt
+	}
+	
+	// Fetch one extra row to detect whether another page remains
+	rows, err := r.db.Query(query, walletID, after, afterID, limit+1)
+	if err != nil {
+		return nil, nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transactions by wallet", "transaction-service")
+	}
+	defer rows.Close()
+	
+	transactions, err := r.scanTransactionRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	if len(transactions) <= limit {
+		return transactions, nil, nil
+	}
+	
+	transactions = transactions[:limit]
+	last := transactions[len(transactions)-1]
+	return transactions, &WalletCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+// scanTransactionRows reads transaction rows, decrypting metadata and loading audit trails
+func (r *TransactionRepository) scanTransactionRows(rows *sql.Rows) ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
 	
 	for rows.Next() {
@@ -205,6 +342,10 @@ func (r *TransactionRepository) GetByWallet(walletID uuid.UUID, limit, offset in
 			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transaction", "transaction-service")
 		}
 		
+		if err := r.decryptMetadata(&transaction.Metadata); err != nil {
+			return nil, err
+		}
+		
 		// Handle nullable fields
 		if fraudScore.Valid {
 			transaction.FraudScore = &fraudScore.Float64
@@ -216,7 +357,7 @@ func (r *TransactionRepository) GetByWallet(walletID uuid.UUID, limit, offset in
 		transactions = append(transactions, &transaction)
 	}
 	
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating transactions", "transaction-service")
 	}
 	
@@ -272,6 +413,10 @@ func (r *TransactionRepository) GetPendingTransactions(limit int) ([]*models.Tra
 			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan pending transaction", "transaction-service")
 		}
 		
+		if err := r.decryptMetadata(&transaction.Metadata); err != nil {
+			return nil, err
+		}
+		
 		// Handle nullable fields
 		if fraudScore.Valid {
 			transaction.FraudScore = &fraudScore.Float64
@@ -290,6 +435,138 @@ func (r *TransactionRepository) GetPendingTransactions(limit int) ([]*models.Tra
 	return transactions, nil
 }
 
+// GetStuckTransactions retrieves pending transactions created before olderThan, oldest first
+func (r *TransactionRepository) GetStuckTransactions(olderThan time.Time, limit int) ([]*models.Transaction, error) {
+	query := `
+		SELECT id, from_wallet_id, to_wallet_id, amount, currency, 
+			   status, fraud_score, created_at, settled_at, metadata
+		FROM transactions 
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`
+	
+	rows, err := r.db.Query(query, models.StatusPending, olderThan, limit)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get stuck transactions", "transaction-service")
+	}
+	defer rows.Close()
+	
+	return r.scanTransactionRows(rows)
+}
+
+// ClaimPendingTransactions leases up to limit unclaimed pending transactions to workerID until
+// now+leaseDuration, oldest first. Rows locked by a concurrent claim are skipped, so each
+// transaction returned is owned by this worker alone until its lease expires.
+func (r *TransactionRepository) ClaimPendingTransactions(workerID string, limit int, leaseDuration time.Duration, now time.Time) ([]*models.Transaction, error) {
+	query := `
+		WITH claimable AS (
+			SELECT id FROM transactions
+			WHERE status = $1 AND (claimed_until IS NULL OR claimed_until < $4)
+			ORDER BY created_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE transactions t
+		SET claimed_by = $3, claimed_until = $5, processing_started_at = $4
+		FROM claimable
+		WHERE t.id = claimable.id
+		RETURNING t.id, t.from_wallet_id, t.to_wallet_id, t.amount, t.currency,
+			t.status, t.fraud_score, t.created_at, t.settled_at, t.metadata
+	`
+	
+	rows, err := r.db.Query(query, models.StatusPending, limit, workerID, now, now.Add(leaseDuration))
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to claim pending transactions", "transaction-service")
+	}
+	defer rows.Close()
+	
+	return r.scanTransactionRows(rows)
+}
+
+// ReleaseExpiredClaims clears leases on pending transactions that expired before now so they
+// can be claimed again, returning the number released
+func (r *TransactionRepository) ReleaseExpiredClaims(now time.Time) (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE transactions SET claimed_by = NULL, claimed_until = NULL
+		WHERE status = $1 AND claimed_until < $2
+	`, models.StatusPending, now)
+	if err != nil {
+		return 0, errors.WrapError(err, errors.ErrTransactionFailed, "failed to release expired claims", "transaction-service")
+	}
+	
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WrapError(err, errors.ErrTransactionFailed, "failed to check release result", "transaction-service")
+	}
+	
+	return int(released), nil
+}
+
+// MarkProcessingStarted records that a worker has started processing a pending transaction
+func (r *TransactionRepository) MarkProcessingStarted(id uuid.UUID, startedAt time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE transactions SET processing_started_at = $2
+		WHERE id = $1 AND status = $3
+	`, id, startedAt, models.StatusPending)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to mark transaction processing", "transaction-service")
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, "pending transaction not found")
+	}
+	
+	return nil
+}
+
+// ForceFail saves a transaction that has been moved from pending to failed. The stored row
+// is locked and must still be pending, with no claim lease running past now and no processing
+// started after activeSince, so a transaction that a worker is actively processing is never failed
+// underneath it.
+func (r *TransactionRepository) ForceFail(transaction *models.Transaction, now, activeSince time.Time) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		return r.ForceFailInTx(tx, transaction, now, activeSince)
+	})
+}
+
+// ForceFailInTx saves a force-failed transaction within a transaction, with the same checks
+// as ForceFail
+func (r *TransactionRepository) ForceFailInTx(tx *sql.Tx, transaction *models.Transaction, now, activeSince time.Time) error {
+	var status models.TransactionStatus
+	var processingStartedAt, claimedUntil sql.NullTime
+	
+	err := tx.QueryRow(`
+		SELECT status, processing_started_at, claimed_until FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`, transaction.ID).Scan(&status, &processingStartedAt, &claimedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found")
+		}
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to lock transaction", "transaction-service")
+	}
+	
+	if status != models.StatusPending {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only pending transactions can be force-failed", status))
+	}
+	
+	if claimedUntil.Valid && claimedUntil.Time.After(now) {
+		return errors.NewTransactionError(errors.ErrConcurrentModification, "transaction is claimed by a worker")
+	}
+	
+	if processingStartedAt.Valid && processingStartedAt.Time.After(activeSince) {
+		return errors.NewTransactionError(errors.ErrConcurrentModification, "transaction is currently being processed")
+	}
+	
+	return r.updateInTx(tx, transaction)
+}
+
 // GetTransactionStats returns transaction statistics
 func (r *TransactionRepository) GetTransactionStats(walletID uuid.UUID, since time.Time) (*TransactionStats, error) {
 	query := `
@@ -321,6 +598,148 @@ func (r *TransactionRepository) GetTransactionStats(walletID uuid.UUID, since ti
 	return &stats, nil
 }
 
+// RecordFeeInTx records a collected fee in the fee ledger within an existing transaction
+func (r *TransactionRepository) RecordFeeInTx(tx *sql.Tx, transactionID, feeWallet uuid.UUID, currency models.Currency, amount float64) error {
+	query := `
+		INSERT INTO transaction_fees (transaction_id, fee_wallet_id, currency, amount, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`
+
+	_, err := tx.Exec(query, transactionID, feeWallet, currency, amount)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record transaction fee", "transaction-service")
+	}
+
+	return nil
+}
+
+// GetFee retrieves the fee ledger entry for a transaction, including archived transactions
+func (r *TransactionRepository) GetFee(transactionID uuid.UUID) (*FeeEntry, error) {
+	query := `
+		SELECT transaction_id, fee_wallet_id, currency, amount, created_at
+		FROM transaction_fees
+		WHERE transaction_id = $1
+		UNION ALL
+		SELECT transaction_id, fee_wallet_id, currency, amount, created_at
+		FROM transaction_fees_archive
+		WHERE transaction_id = $1
+		LIMIT 1
+	`
+
+	var entry FeeEntry
+	err := r.db.QueryRow(query, transactionID).Scan(
+		&entry.TransactionID,
+		&entry.FeeWallet,
+		&entry.Currency,
+		&entry.Amount,
+		&entry.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewTransactionError(errors.ErrTransactionNotFound, "no fee recorded for transaction")
+		}
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transaction fee", "transaction-service")
+	}
+
+	return &entry, nil
+}
+
+// SetCorrelationIDInTx tags a transaction with the correlation ID of the multi-transfer that created it
+func (r *TransactionRepository) SetCorrelationIDInTx(tx *sql.Tx, transactionID, correlationID uuid.UUID) error {
+	query := `UPDATE transactions SET correlation_id = $2 WHERE id = $1`
+
+	_, err := tx.Exec(query, transactionID, correlationID)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to set transaction correlation ID", "transaction-service")
+	}
+
+	return nil
+}
+
+// GetIDsByCorrelationID retrieves the IDs of transactions sharing a correlation ID
+func (r *TransactionRepository) GetIDsByCorrelationID(correlationID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM transactions
+		WHERE correlation_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, correlationID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transactions by correlation ID", "transaction-service")
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transaction ID", "transaction-service")
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating transaction IDs", "transaction-service")
+	}
+
+	return ids, nil
+}
+
+// encryptMetadata returns a copy of the metadata with sensitive fields encrypted
+func (r *TransactionRepository) encryptMetadata(metadata models.TransactionMetadata) (models.TransactionMetadata, error) {
+	err := r.encryptFields(sensitiveMetadataFields(&metadata))
+	return metadata, err
+}
+
+// decryptMetadata decrypts sensitive metadata fields in place.
+// Plaintext values from before encryption was enabled are left unchanged.
+func (r *TransactionRepository) decryptMetadata(metadata *models.TransactionMetadata) error {
+	return r.decryptFields(sensitiveMetadataFields(metadata))
+}
+
+// encryptFields encrypts non-empty fields in place when an encryptor is configured. Values
+// are always plaintext here, since reads decrypt them, so one that already looks encrypted
+// is encrypted again rather than stored as if it were ciphertext.
+func (r *TransactionRepository) encryptFields(fields []*string) error {
+	if r.encryptor == nil {
+		return nil
+	}
+	
+	for _, field := range fields {
+		if *field == "" {
+			continue
+		}
+		encrypted, err := r.encryptor.Encrypt(*field)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to encrypt transaction metadata", "transaction-service")
+		}
+		*field = encrypted
+	}
+	
+	return nil
+}
+
+// decryptFields decrypts encrypted fields in place, leaving plaintext values unchanged
+func (r *TransactionRepository) decryptFields(fields []*string) error {
+	for _, field := range fields {
+		if !isEncryptedValue(*field) {
+			continue
+		}
+		if r.encryptor == nil {
+			return errors.NewTransactionError(errors.ErrTransactionFailed, "transaction metadata is encrypted but no encryptor is configured")
+		}
+		decrypted, err := r.encryptor.Decrypt(*field)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to decrypt transaction metadata", "transaction-service")
+		}
+		*field = decrypted
+	}
+	
+	return nil
+}
+
 // insertAuditEntry inserts an audit entry within a transaction
 func (r *TransactionRepository) insertAuditEntry(tx *sql.Tx, entry models.AuditEntry) error {
 	query := `
@@ -352,13 +771,18 @@ func (r *TransactionRepository) insertAuditEntry(tx *sql.Tx, entry models.AuditE
 
 // getAuditTrail retrieves the audit trail for a transaction
 func (r *TransactionRepository) getAuditTrail(transactionID uuid.UUID) ([]models.AuditEntry, error) {
-	query := `
+	return r.getAuditTrailFrom(transactionAuditTable, transactionID)
+}
+
+// getAuditTrailFrom retrieves the audit trail for a transaction from the given audit table
+func (r *TransactionRepository) getAuditTrailFrom(auditTable string, transactionID uuid.UUID) ([]models.AuditEntry, error) {
+	query := fmt.Sprintf(`
 		SELECT id, transaction_id, action, previous_state, new_state, 
 			   timestamp, user_id, service_id, details, signature
-		FROM transaction_audit 
+		FROM %s 
 		WHERE transaction_id = $1
 		ORDER BY timestamp ASC
-	`
+	`, auditTable)
 	
 	rows, err := r.db.Query(query, transactionID)
 	if err != nil {
@@ -417,6 +841,15 @@ type TransactionStats struct {
 	AvgFraudScore  float64 `json:"avg_fraud_score"`
 }
 
+// FeeEntry represents a fee collected on a transaction
+type FeeEntry struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	FeeWallet     uuid.UUID       `json:"fee_wallet_id"`
+	Currency      models.Currency `json:"currency"`
+	Amount        float64         `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
 // Migrate creates the necessary database tables
 func (r *TransactionRepository) Migrate() error {
 	migrations := []string{
@@ -456,7 +889,39 @@ func (r *TransactionRepository) Migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_transaction_audit_transaction_id ON transaction_audit(transaction_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_transaction_audit_timestamp ON transaction_audit(timestamp)`,
+		
+		// Create fee ledger table
+		`CREATE TABLE IF NOT EXISTS transaction_fees (
+			transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+			fee_wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaction_fees_fee_wallet ON transaction_fees(fee_wallet_id)`,
+		
+		// Correlate transactions created together by an atomic multi-transfer
+		`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS correlation_id UUID`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_correlation_id ON transactions(correlation_id) WHERE correlation_id IS NOT NULL`,
+		
+		// Support keyset pagination of wallet transaction listings
+		`CREATE INDEX IF NOT EXISTS idx_transactions_from_wallet_created ON transactions(from_wallet_id, created_at DESC, id DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_to_wallet_created ON transactions(to_wallet_id, created_at DESC, id DESC)`,
+		
+		// Track when a worker started processing a pending transaction
+		`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS processing_started_at TIMESTAMP WITH TIME ZONE`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_pending_created ON transactions(created_at) WHERE status = 'pending'`,
+		
+		// Lease pending transactions to a single worker at a time
+		`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS claimed_by VARCHAR(100)`,
+		`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP WITH TIME ZONE`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_claimed_until ON transactions(claimed_until) WHERE status = 'pending' AND claimed_until IS NOT NULL`,
 	}
+	migrations = append(migrations, archiveMigrations()...)
+	migrations = append(migrations, balanceChangeMigrations()...)
+	migrations = append(migrations, transferPolicyMigrations()...)
+	migrations = append(migrations, tagMigrations()...)
+	migrations = append(migrations, integrityCheckMigrations()...)
 	
 	return r.db.Migrate(migrations)
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,6 +47,11 @@ func cleanupTestDB(t *testing.T, db *database.PostgresDB) {
 	if err != nil {
 		t.Logf("Failed to clean transactions table: %v", err)
 	}
+	
+	_, err = db.Exec("DELETE FROM transactions_archive")
+	if err != nil {
+		t.Logf("Failed to clean transactions archive table: %v", err)
+	}
 }
 
 func TestTransactionRepository_Migrate(t *testing.T) {
@@ -287,6 +294,95 @@ func TestTransactionRepository_Update(t *testing.T) {
 	}
 }
 
+func TestTransactionRepository_ArchiveTransactions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	// Create a completed transaction and a pending one
+	completed, err := models.NewTransaction(uuid.New(), uuid.New(), 75.00, models.USDCBDC, models.TransactionMetadata{
+		Description: "Settled payment",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if err := repo.Create(completed); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if err := completed.UpdateStatus(models.StatusCompleted, nil, "transaction-service", nil); err != nil {
+		t.Fatalf("Failed to update transaction status: %v", err)
+	}
+	if err := repo.Update(completed); err != nil {
+		t.Fatalf("Failed to update transaction: %v", err)
+	}
+	
+	pending, err := models.NewTransaction(uuid.New(), uuid.New(), 20.00, models.USDCBDC, models.TransactionMetadata{
+		Description: "Pending payment",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if err := repo.Create(pending); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	
+	archived, err := repo.ArchiveTransactions(context.Background(), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to archive transactions: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("Expected 1 archived transaction, got %d", archived)
+	}
+	
+	// The archived transaction is gone from the hot table
+	var hotCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM transactions WHERE id = $1", completed.ID).Scan(&hotCount); err != nil {
+		t.Fatalf("Failed to count transactions: %v", err)
+	}
+	if hotCount != 0 {
+		t.Errorf("Expected archived transaction to be removed from transactions table")
+	}
+	
+	// GetByID falls back to the archive
+	retrieved, err := repo.GetByID(completed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get archived transaction: %v", err)
+	}
+	if retrieved.Status != models.StatusCompleted {
+		t.Errorf("Expected status %v, got %v", models.StatusCompleted, retrieved.Status)
+	}
+	if retrieved.Amount != completed.Amount {
+		t.Errorf("Expected amount %v, got %v", completed.Amount, retrieved.Amount)
+	}
+	if len(retrieved.AuditTrail) != 2 {
+		t.Errorf("Expected 2 archived audit entries, got %d", len(retrieved.AuditTrail))
+	}
+	
+	// Pending transactions are never archived
+	stillPending, err := repo.GetByID(pending.ID)
+	if err != nil {
+		t.Fatalf("Failed to get pending transaction: %v", err)
+	}
+	if stillPending.Status != models.StatusPending {
+		t.Errorf("Expected status %v, got %v", models.StatusPending, stillPending.Status)
+	}
+	
+	// Running again finds nothing left to archive
+	archived, err = repo.ArchiveTransactions(context.Background(), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to archive transactions: %v", err)
+	}
+	if archived != 0 {
+		t.Errorf("Expected 0 archived transactions on second run, got %d", archived)
+	}
+}
+
 func TestTransactionRepository_GetByWallet(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -369,6 +465,104 @@ func TestTransactionRepository_GetByWallet(t *testing.T) {
 	}
 }
 
+func TestTransactionRepository_GetByWalletCursor(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	walletID := uuid.New()
+	otherWalletID := uuid.New()
+	
+	createTransaction := func(i int) uuid.UUID {
+		transaction, err := models.NewTransaction(
+			walletID,
+			otherWalletID,
+			float64(10+i),
+			models.USDCBDC,
+			models.TransactionMetadata{},
+		)
+		if err != nil {
+			t.Fatalf("Failed to create transaction %d: %v", i, err)
+		}
+		
+		if err := repo.Create(transaction); err != nil {
+			t.Fatalf("Failed to save transaction %d: %v", i, err)
+		}
+		
+		// Add small delay to ensure different timestamps
+		time.Sleep(1 * time.Millisecond)
+		return transaction.ID
+	}
+	
+	expected := make(map[uuid.UUID]bool)
+	for i := 0; i < 7; i++ {
+		expected[createTransaction(i)] = true
+	}
+	
+	// Page through with a small limit, inserting new transactions between fetches
+	seen := make(map[uuid.UUID]bool)
+	var afterCreatedAt time.Time
+	var afterID uuid.UUID
+	pages := 0
+	for {
+		page, next, err := repo.GetByWalletCursor(walletID, afterCreatedAt, afterID, 3)
+		if err != nil {
+			t.Fatalf("Failed to get page %d: %v", pages+1, err)
+		}
+		pages++
+		
+		for _, transaction := range page {
+			if seen[transaction.ID] {
+				t.Errorf("Transaction %s returned more than once", transaction.ID)
+			}
+			seen[transaction.ID] = true
+		}
+		
+		createTransaction(100 + pages)
+		
+		if next == nil {
+			break
+		}
+		
+		// Round-trip the cursor as a client would
+		decoded, err := DecodeWalletCursor(next.Encode())
+		if err != nil {
+			t.Fatalf("Failed to decode cursor: %v", err)
+		}
+		afterCreatedAt, afterID = decoded.CreatedAt, decoded.ID
+	}
+	
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	
+	// Every transaction that existed before paging started must be returned exactly once
+	for id := range expected {
+		if !seen[id] {
+			t.Errorf("Transaction %s was skipped", id)
+		}
+	}
+	
+	// Transactions inserted during paging are newer than the first page and never appear
+	if len(seen) != len(expected) {
+		t.Errorf("Expected %d transactions, got %d", len(expected), len(seen))
+	}
+}
+
+func TestDecodeWalletCursorInvalid(t *testing.T) {
+	for _, cursor := range []string{"not-base64!", "bm8tc2VwYXJhdG9y", "YmFkfGN1cnNvcg"} {
+		if _, err := DecodeWalletCursor(cursor); err == nil {
+			t.Errorf("Expected error decoding cursor %q", cursor)
+		}
+	}
+}
+
 func TestTransactionRepository_GetPendingTransactions(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -449,6 +643,260 @@ func TestTransactionRepository_GetPendingTransactions(t *testing.T) {
 	}
 }
 
+func TestTransactionRepository_GetStuckTransactions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	now := time.Now()
+	cases := []struct {
+		age    time.Duration
+		status models.TransactionStatus
+		stuck  bool
+	}{
+		{2 * time.Hour, models.StatusPending, true},
+		{45 * time.Minute, models.StatusPending, true},
+		{5 * time.Minute, models.StatusPending, false},   // Too recent
+		{2 * time.Hour, models.StatusCompleted, false}, // Not pending
+	}
+	
+	expected := make(map[uuid.UUID]bool)
+	for i, tc := range cases {
+		transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+		if err != nil {
+			t.Fatalf("Failed to create transaction %d: %v", i, err)
+		}
+		transaction.CreatedAt = now.Add(-tc.age)
+		
+		if tc.status != models.StatusPending {
+			if err := transaction.UpdateStatus(tc.status, nil, "test-service", nil); err != nil {
+				t.Fatalf("Failed to update transaction status: %v", err)
+			}
+		}
+		
+		if err := repo.Create(transaction); err != nil {
+			t.Fatalf("Failed to save transaction %d: %v", i, err)
+		}
+		if tc.stuck {
+			expected[transaction.ID] = true
+		}
+	}
+	
+	stuck, err := repo.GetStuckTransactions(now.Add(-30*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("Failed to get stuck transactions: %v", err)
+	}
+	
+	if len(stuck) != len(expected) {
+		t.Fatalf("Expected %d stuck transactions, got %d", len(expected), len(stuck))
+	}
+	for _, transaction := range stuck {
+		if !expected[transaction.ID] {
+			t.Errorf("Unexpected stuck transaction %s", transaction.ID)
+		}
+	}
+	if stuck[0].CreatedAt.After(stuck[1].CreatedAt) {
+		t.Error("Stuck transactions are not ordered by created_at ASC")
+	}
+}
+
+func TestTransactionRepository_ForceFail(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	newStuckTransaction := func() *models.Transaction {
+		transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+		if err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		transaction.CreatedAt = time.Now().Add(-time.Hour)
+		if err := repo.Create(transaction); err != nil {
+			t.Fatalf("Failed to save transaction: %v", err)
+		}
+		if err := transaction.UpdateStatus(models.StatusFailed, nil, "test-service", map[string]interface{}{"reason": "orphaned"}); err != nil {
+			t.Fatalf("Failed to update transaction status: %v", err)
+		}
+		return transaction
+	}
+	
+	t.Run("stuck transaction is failed with an audit entry", func(t *testing.T) {
+		transaction := newStuckTransaction()
+		
+		if err := repo.ForceFail(transaction, time.Now(), time.Now().Add(-5*time.Minute)); err != nil {
+			t.Fatalf("Failed to force-fail transaction: %v", err)
+		}
+		
+		saved, err := repo.GetByID(transaction.ID)
+		if err != nil {
+			t.Fatalf("Failed to get transaction: %v", err)
+		}
+		if saved.Status != models.StatusFailed {
+			t.Errorf("Expected failed status, got %v", saved.Status)
+		}
+		last := saved.AuditTrail[len(saved.AuditTrail)-1]
+		if last.NewState != string(models.StatusFailed) || last.Details["reason"] != "orphaned" {
+			t.Errorf("Expected audit entry recording the reason, got %+v", last)
+		}
+	})
+	
+	t.Run("transaction being processed is refused", func(t *testing.T) {
+		transaction := newStuckTransaction()
+		if err := repo.MarkProcessingStarted(transaction.ID, time.Now()); err != nil {
+			t.Fatalf("Failed to mark processing: %v", err)
+		}
+		
+		err := repo.ForceFail(transaction, time.Now(), time.Now().Add(-5*time.Minute))
+		echoPayErr, ok := err.(*errors.EchoPayError)
+		if !ok || echoPayErr.Code != errors.ErrConcurrentModification {
+			t.Fatalf("Expected concurrent modification error, got %v", err)
+		}
+		
+		saved, err := repo.GetByID(transaction.ID)
+		if err != nil {
+			t.Fatalf("Failed to get transaction: %v", err)
+		}
+		if saved.Status != models.StatusPending {
+			t.Errorf("Expected transaction to stay pending, got %v", saved.Status)
+		}
+	})
+	
+	t.Run("transaction whose processing lapsed can be failed", func(t *testing.T) {
+		transaction := newStuckTransaction()
+		if err := repo.MarkProcessingStarted(transaction.ID, time.Now().Add(-30*time.Minute)); err != nil {
+			t.Fatalf("Failed to mark processing: %v", err)
+		}
+		
+		if err := repo.ForceFail(transaction, time.Now(), time.Now().Add(-5*time.Minute)); err != nil {
+			t.Errorf("Expected lapsed transaction to be force-failed, got %v", err)
+		}
+	})
+}
+
+func TestTransactionRepository_ClaimPendingTransactions_Concurrent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	const pendingCount = 40
+	for i := 0; i < pendingCount; i++ {
+		transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+		if err != nil {
+			t.Fatalf("Failed to create transaction %d: %v", i, err)
+		}
+		if err := repo.Create(transaction); err != nil {
+			t.Fatalf("Failed to save transaction %d: %v", i, err)
+		}
+	}
+	
+	// Two workers claim small batches in parallel until nothing is left
+	workers := []string{"worker-a", "worker-b"}
+	claimed := make([][]uuid.UUID, len(workers))
+	errs := make(chan error, len(workers))
+	var wg sync.WaitGroup
+	for i, workerID := range workers {
+		wg.Add(1)
+		go func(i int, workerID string) {
+			defer wg.Done()
+			for {
+				batch, err := repo.ClaimPendingTransactions(workerID, 3, time.Minute, time.Now())
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(batch) == 0 {
+					return
+				}
+				for _, transaction := range batch {
+					claimed[i] = append(claimed[i], transaction.ID)
+				}
+			}
+		}(i, workerID)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Failed to claim transactions: %v", err)
+	}
+	
+	owners := make(map[uuid.UUID]string)
+	for i, ids := range claimed {
+		for _, id := range ids {
+			if owner, exists := owners[id]; exists {
+				t.Errorf("Transaction %s claimed by both %s and %s", id, owner, workers[i])
+			}
+			owners[id] = workers[i]
+		}
+	}
+	if len(owners) != pendingCount {
+		t.Errorf("Expected all %d transactions to be claimed, got %d", pendingCount, len(owners))
+	}
+}
+
+func TestTransactionRepository_ReleaseExpiredClaims(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupTestDB(t, db)
+	
+	repo := NewTransactionRepository(db)
+	err := repo.Migrate()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	
+	transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if err := repo.Create(transaction); err != nil {
+		t.Fatalf("Failed to save transaction: %v", err)
+	}
+	
+	now := time.Now()
+	claimed, err := repo.ClaimPendingTransactions("worker-a", 10, time.Minute, now)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Expected to claim 1 transaction, got %d (err: %v)", len(claimed), err)
+	}
+	
+	// The lease is still held, so nothing is released and no one else can claim it
+	released, err := repo.ReleaseExpiredClaims(now.Add(30 * time.Second))
+	if err != nil || released != 0 {
+		t.Fatalf("Expected no claims released, got %d (err: %v)", released, err)
+	}
+	claimed, err = repo.ClaimPendingTransactions("worker-b", 10, time.Minute, now.Add(30*time.Second))
+	if err != nil || len(claimed) != 0 {
+		t.Fatalf("Expected no transactions claimable, got %d (err: %v)", len(claimed), err)
+	}
+	
+	// Once the lease expires the sweep returns it to the pool
+	released, err = repo.ReleaseExpiredClaims(now.Add(2 * time.Minute))
+	if err != nil || released != 1 {
+		t.Fatalf("Expected 1 claim released, got %d (err: %v)", released, err)
+	}
+	claimed, err = repo.ClaimPendingTransactions("worker-b", 10, time.Minute, now.Add(2*time.Minute))
+	if err != nil || len(claimed) != 1 || claimed[0].ID != transaction.ID {
+		t.Fatalf("Expected worker-b to claim the released transaction, got %d (err: %v)", len(claimed), err)
+	}
+}
+
 func TestTransactionRepository_GetTransactionStats(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
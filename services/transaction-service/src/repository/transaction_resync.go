@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// BalanceChange is a wallet balance update made by a transaction, kept so that the
+// transaction's balance events can be re-published exactly
+type BalanceChange struct {
+	WalletID   uuid.UUID `json:"wallet_id"`
+	OldBalance float64   `json:"old_balance"`
+	NewBalance float64   `json:"new_balance"`
+}
+
+// RecordBalanceChangesInTx records the balance updates a transaction made, in order, within
+// the database transaction that applied them
+func (r *TransactionRepository) RecordBalanceChangesInTx(tx *sql.Tx, transactionID uuid.UUID, currency models.Currency, changes []BalanceChange) error {
+	query := `
+		INSERT INTO transaction_balance_changes (transaction_id, position, wallet_id, currency, old_balance, new_balance)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for i, change := range changes {
+		_, err := tx.Exec(query, transactionID, i, change.WalletID, currency, change.OldBalance, change.NewBalance)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record balance change", "transaction-service")
+		}
+	}
+
+	return nil
+}
+
+// GetBalanceChanges retrieves the balance updates recorded for a transaction, in the order
+// they were made. Transactions processed before balance changes were recorded have none.
+func (r *TransactionRepository) GetBalanceChanges(transactionID uuid.UUID) ([]BalanceChange, error) {
+	query := `
+		SELECT wallet_id, old_balance, new_balance
+		FROM transaction_balance_changes
+		WHERE transaction_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get balance changes", "transaction-service")
+	}
+	defer rows.Close()
+
+	var changes []BalanceChange
+	for rows.Next() {
+		var change BalanceChange
+		if err := rows.Scan(&change.WalletID, &change.OldBalance, &change.NewBalance); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan balance change", "transaction-service")
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating balance changes", "transaction-service")
+	}
+
+	return changes, nil
+}
+
+// GetCompletedAfter retrieves completed transactions settled after the (settledAt, id)
+// position, oldest first, for keyset pagination through settlements
+func (r *TransactionRepository) GetCompletedAfter(settledAt time.Time, id uuid.UUID, limit int) ([]*models.Transaction, error) {
+	query := `
+		SELECT id, from_wallet_id, to_wallet_id, amount, currency, 
+			   status, fraud_score, created_at, settled_at, metadata
+		FROM transactions 
+		WHERE status = $1 AND (settled_at, id) > ($2, $3)
+		ORDER BY settled_at ASC, id ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, models.StatusCompleted, settledAt, id, limit)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get completed transactions", "transaction-service")
+	}
+	defer rows.Close()
+
+	return r.scanTransactionRows(rows)
+}
+
+// balanceChangeMigrations creates the ledger of balance updates made by each transaction.
+// Rows are kept when a transaction is archived.
+func balanceChangeMigrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS transaction_balance_changes (
+			transaction_id UUID NOT NULL,
+			position INT NOT NULL,
+			wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			old_balance DECIMAL(15,2) NOT NULL,
+			new_balance DECIMAL(15,2) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (transaction_id, position)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_transactions_completed_settled ON transactions(settled_at, id) WHERE status = 'completed'`,
+	}
+}
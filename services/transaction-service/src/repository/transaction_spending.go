@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// UncategorizedCategory is the spending bucket for transactions recorded without a category
+const UncategorizedCategory = "uncategorized"
+
+// CategorySpending is a wallet's completed outgoing spending in one category and currency
+type CategorySpending struct {
+	Category string          `json:"category"`
+	Currency models.Currency `json:"currency"`
+	Count    int             `json:"count"`
+	Total    float64         `json:"total"`
+}
+
+// GetSpendingByCategory totals a wallet's completed outgoing transactions created at or after
+// from and before to, grouped by category and currency. Transactions without a category are
+// grouped under UncategorizedCategory.
+func (r *TransactionRepository) GetSpendingByCategory(walletID uuid.UUID, from, to time.Time) ([]CategorySpending, error) {
+	query := `
+		SELECT COALESCE(NULLIF(metadata->>'category', ''), $4) AS category, currency,
+			COUNT(*), COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE from_wallet_id = $1 AND status = 'completed' AND created_at >= $2 AND created_at < $3
+		GROUP BY 1, currency
+		ORDER BY 1, currency
+	`
+
+	rows, err := r.db.Query(query, walletID, from, to, UncategorizedCategory)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get spending by category", "transaction-service")
+	}
+	defer rows.Close()
+
+	spending := []CategorySpending{}
+	for rows.Next() {
+		var entry CategorySpending
+		if err := rows.Scan(&entry.Category, &entry.Currency, &entry.Count, &entry.Total); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan category spending", "transaction-service")
+		}
+		spending = append(spending, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to iterate category spending", "transaction-service")
+	}
+
+	return spending, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+)
+
+// AddTag tags a transaction. Adding a tag the transaction already has does nothing. Tags are
+// kept when a transaction is archived.
+func (r *TransactionRepository) AddTag(transactionID uuid.UUID, tag string) error {
+	query := `
+		INSERT INTO transaction_tags (transaction_id, tag)
+		VALUES ($1, $2)
+		ON CONFLICT (transaction_id, tag) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, transactionID, tag); err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to tag transaction", "transaction-service")
+	}
+
+	return nil
+}
+
+// RemoveTag removes a tag from a transaction
+func (r *TransactionRepository) RemoveTag(transactionID uuid.UUID, tag string) error {
+	result, err := r.db.Exec(`DELETE FROM transaction_tags WHERE transaction_id = $1 AND tag = $2`, transactionID, tag)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to untag transaction", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check delete result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction does not have tag "+tag)
+	}
+
+	return nil
+}
+
+// GetTags returns a transaction's tags in alphabetical order
+func (r *TransactionRepository) GetTags(transactionID uuid.UUID) ([]string, error) {
+	rows, err := r.db.Query(`SELECT tag FROM transaction_tags WHERE transaction_id = $1 ORDER BY tag`, transactionID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transaction tags", "transaction-service")
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transaction tag", "transaction-service")
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating transaction tags", "transaction-service")
+	}
+
+	return tags, nil
+}
+
+// GetIDsByTag returns the IDs of transactions with a tag, most recently tagged first
+func (r *TransactionRepository) GetIDsByTag(tag string, limit, offset int) ([]uuid.UUID, error) {
+	query := `
+		SELECT transaction_id FROM transaction_tags
+		WHERE tag = $1
+		ORDER BY created_at DESC, transaction_id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, tag, limit, offset)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transactions by tag", "transaction-service")
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transaction ID", "transaction-service")
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating transaction IDs", "transaction-service")
+	}
+
+	return ids, nil
+}
+
+// tagMigrations creates the table of transaction tags. It has no foreign key to transactions
+// so tags survive archival.
+func tagMigrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS transaction_tags (
+			transaction_id UUID NOT NULL,
+			tag VARCHAR(64) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (transaction_id, tag)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag_created ON transaction_tags(tag, created_at DESC, transaction_id DESC)`,
+	}
+}
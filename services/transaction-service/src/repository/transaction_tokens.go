@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+)
+
+// RecordTokenIDsInTx records the tokens a transaction moved under the token_ids key of its
+// metadata, within the database transaction that applied it
+func (r *TransactionRepository) RecordTokenIDsInTx(tx *sql.Tx, transactionID uuid.UUID, tokenIDs []uuid.UUID) error {
+	encoded, err := json.Marshal(tokenIDs)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to encode token IDs", "transaction-service")
+	}
+
+	query := `
+		UPDATE transactions
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{token_ids}', $2::jsonb)
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(query, transactionID, string(encoded))
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record token IDs", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found for token IDs")
+	}
+
+	return nil
+}
+
+// GetTokenIDs retrieves the tokens recorded as moved by a transaction. Transactions settled
+// without tokens have none.
+func (r *TransactionRepository) GetTokenIDs(transactionID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT COALESCE(metadata->'token_ids', '[]'::jsonb)
+		FROM transactions
+		WHERE id = $1
+	`
+
+	var encoded []byte
+	err := r.db.QueryRow(query, transactionID).Scan(&encoded)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewTransactionError(errors.ErrTransactionNotFound, "transaction not found")
+		}
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get token IDs", "transaction-service")
+	}
+
+	var tokenIDs []uuid.UUID
+	if err := json.Unmarshal(encoded, &tokenIDs); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to decode token IDs", "transaction-service")
+	}
+
+	return tokenIDs, nil
+}
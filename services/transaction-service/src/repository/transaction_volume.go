@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// GetCompletedVolume counts and totals completed transactions in a currency created at or
+// after from and before to, including archived transactions. The half-open range lets
+// consecutive periods share a boundary without counting a transaction twice.
+func (r *TransactionRepository) GetCompletedVolume(currency models.Currency, from, to time.Time) (int, float64, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(amount), 0)
+		FROM (
+			SELECT amount FROM transactions
+			WHERE currency = $1 AND status = 'completed' AND created_at >= $2 AND created_at < $3
+			UNION ALL
+			SELECT amount FROM transactions_archive
+			WHERE currency = $1 AND status = 'completed' AND created_at >= $2 AND created_at < $3
+		) completed
+	`
+
+	var count int
+	var total float64
+	if err := r.db.QueryRow(query, currency, from, to).Scan(&count, &total); err != nil {
+		return 0, 0, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transaction volume", "transaction-service")
+	}
+
+	return count, total, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+)
+
+// TransferPolicyMode decides how a wallet's counterparty list restricts its outgoing transfers
+type TransferPolicyMode string
+
+// Transfer policy modes
+const (
+	TransferPolicyUnrestricted TransferPolicyMode = "unrestricted" // Any recipient; the list is ignored
+	TransferPolicyAllow        TransferPolicyMode = "allow"        // Only listed recipients
+	TransferPolicyDeny         TransferPolicyMode = "deny"         // Any recipient except those listed
+)
+
+// TransferPolicy restricts the recipients a wallet may transfer to. Wallets without a stored
+// policy are unrestricted.
+type TransferPolicy struct {
+	WalletID       uuid.UUID          `json:"wallet_id"`
+	Mode           TransferPolicyMode `json:"mode"`
+	Counterparties []uuid.UUID        `json:"counterparties"`
+	UpdatedAt      *time.Time         `json:"updated_at,omitempty"`
+}
+
+// Permits reports whether the policy allows a transfer to the recipient
+func (p *TransferPolicy) Permits(recipient uuid.UUID) bool {
+	listed := false
+	for _, counterparty := range p.Counterparties {
+		if counterparty == recipient {
+			listed = true
+			break
+		}
+	}
+
+	return p.Mode.permits(listed)
+}
+
+// permits reports whether the mode allows a transfer to a recipient that is or isn't listed
+func (m TransferPolicyMode) permits(listed bool) bool {
+	switch m {
+	case TransferPolicyAllow:
+		return listed
+	case TransferPolicyDeny:
+		return !listed
+	default:
+		return true
+	}
+}
+
+// TransferPolicyRepository stores per-wallet transfer allow and deny lists
+type TransferPolicyRepository struct {
+	db *database.PostgresDB
+}
+
+// NewTransferPolicyRepository creates a new transfer policy repository
+func NewTransferPolicyRepository(db *database.PostgresDB) *TransferPolicyRepository {
+	return &TransferPolicyRepository{db: db}
+}
+
+// Get retrieves a wallet's transfer policy, which is unrestricted if none was stored
+func (r *TransferPolicyRepository) Get(walletID uuid.UUID) (*TransferPolicy, error) {
+	policy := &TransferPolicy{WalletID: walletID, Mode: TransferPolicyUnrestricted, Counterparties: []uuid.UUID{}}
+
+	var updatedAt time.Time
+	err := r.db.QueryRow(`SELECT mode, updated_at FROM transfer_policies WHERE wallet_id = $1`, walletID).Scan(&policy.Mode, &updatedAt)
+	if err == sql.ErrNoRows {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transfer policy", "transaction-service")
+	}
+	policy.UpdatedAt = &updatedAt
+
+	rows, err := r.db.Query(`
+		SELECT counterparty_id FROM transfer_policy_entries
+		WHERE wallet_id = $1
+		ORDER BY created_at, counterparty_id
+	`, walletID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get transfer policy entries", "transaction-service")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var counterparty uuid.UUID
+		if err := rows.Scan(&counterparty); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan transfer policy entry", "transaction-service")
+		}
+		policy.Counterparties = append(policy.Counterparties, counterparty)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to iterate transfer policy entries", "transaction-service")
+	}
+
+	return policy, nil
+}
+
+// IsPermitted reports whether the sender's policy allows a transfer to the recipient, without
+// loading the sender's whole list
+func (r *TransferPolicyRepository) IsPermitted(sender, recipient uuid.UUID) (bool, error) {
+	query := `
+		SELECT p.mode, EXISTS (
+			SELECT 1 FROM transfer_policy_entries e
+			WHERE e.wallet_id = p.wallet_id AND e.counterparty_id = $2
+		)
+		FROM transfer_policies p
+		WHERE p.wallet_id = $1
+	`
+
+	var mode TransferPolicyMode
+	var listed bool
+	err := r.db.QueryRow(query, sender, recipient).Scan(&mode, &listed)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.WrapError(err, errors.ErrTransactionFailed, "failed to check transfer policy", "transaction-service")
+	}
+
+	return mode.permits(listed), nil
+}
+
+// Set replaces a wallet's mode and counterparty list. Setting an unrestricted policy removes
+// the stored policy.
+func (r *TransferPolicyRepository) Set(walletID uuid.UUID, mode TransferPolicyMode, counterparties []uuid.UUID) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM transfer_policy_entries WHERE wallet_id = $1`, walletID); err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to clear transfer policy entries", "transaction-service")
+		}
+
+		if mode == TransferPolicyUnrestricted {
+			if _, err := tx.Exec(`DELETE FROM transfer_policies WHERE wallet_id = $1`, walletID); err != nil {
+				return errors.WrapError(err, errors.ErrTransactionFailed, "failed to remove transfer policy", "transaction-service")
+			}
+			return nil
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO transfer_policies (wallet_id, mode, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (wallet_id) DO UPDATE SET mode = EXCLUDED.mode, updated_at = EXCLUDED.updated_at
+		`, walletID, mode)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to save transfer policy", "transaction-service")
+		}
+
+		return r.addEntriesInTx(tx, walletID, counterparties)
+	})
+}
+
+// AddCounterparties adds recipients to a wallet's stored list. Recipients already listed are
+// left as they are.
+func (r *TransferPolicyRepository) AddCounterparties(walletID uuid.UUID, counterparties []uuid.UUID) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		if err := r.touchPolicyInTx(tx, walletID); err != nil {
+			return err
+		}
+		return r.addEntriesInTx(tx, walletID, counterparties)
+	})
+}
+
+// RemoveCounterparty removes a recipient from a wallet's stored list
+func (r *TransferPolicyRepository) RemoveCounterparty(walletID, counterparty uuid.UUID) error {
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		if err := r.touchPolicyInTx(tx, walletID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`DELETE FROM transfer_policy_entries WHERE wallet_id = $1 AND counterparty_id = $2`, walletID, counterparty)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to remove transfer policy entry", "transaction-service")
+		}
+		return nil
+	})
+}
+
+// touchPolicyInTx locks a wallet's stored policy and records that it changed. Lists can only
+// be edited on a wallet with an allow or deny policy.
+func (r *TransferPolicyRepository) touchPolicyInTx(tx *sql.Tx, walletID uuid.UUID) error {
+	result, err := tx.Exec(`UPDATE transfer_policies SET updated_at = NOW() WHERE wallet_id = $1`, walletID)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update transfer policy", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet has no allow or deny list; set a transfer policy first")
+	}
+
+	return nil
+}
+
+// addEntriesInTx lists the counterparties on a wallet's policy
+func (r *TransferPolicyRepository) addEntriesInTx(tx *sql.Tx, walletID uuid.UUID, counterparties []uuid.UUID) error {
+	if len(counterparties) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(counterparties))
+	for i, counterparty := range counterparties {
+		ids[i] = counterparty.String()
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO transfer_policy_entries (wallet_id, counterparty_id)
+		SELECT $1, UNNEST($2::uuid[])
+		ON CONFLICT (wallet_id, counterparty_id) DO NOTHING
+	`, walletID, pq.Array(ids))
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to add transfer policy entries", "transaction-service")
+	}
+
+	return nil
+}
+
+// transferPolicyMigrations creates the transfer policy tables
+func transferPolicyMigrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS transfer_policies (
+			wallet_id UUID PRIMARY KEY,
+			mode VARCHAR(20) NOT NULL CHECK (mode IN ('allow', 'deny')),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS transfer_policy_entries (
+			wallet_id UUID NOT NULL REFERENCES transfer_policies(wallet_id) ON DELETE CASCADE,
+			counterparty_id UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (wallet_id, counterparty_id)
+		)`,
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/errors"
+)
+
+// LinkAccountInTx makes accountID the account holder of a wallet within a transaction,
+// registering the account if it is new. A wallet has one holder, so linking replaces any
+// previous one; the wallet's owner is kept in step.
+func (r *WalletRepository) LinkAccountInTx(tx *sql.Tx, walletID, accountID uuid.UUID) error {
+	_, err := tx.Exec(`INSERT INTO accounts (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`, accountID)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to create account", "transaction-service")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO wallet_accounts (wallet_id, account_id, linked_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (wallet_id) DO UPDATE SET account_id = EXCLUDED.account_id, linked_at = EXCLUDED.linked_at
+		WHERE wallet_accounts.account_id <> EXCLUDED.account_id`,
+		walletID, accountID,
+	)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to link wallet to account", "transaction-service")
+	}
+
+	_, err = tx.Exec(`UPDATE wallets SET owner_id = $2, updated_at = NOW() WHERE id = $1 AND owner_id IS DISTINCT FROM $2`, walletID, accountID)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update wallet owner", "transaction-service")
+	}
+
+	return nil
+}
+
+// GetByAccount retrieves every wallet linked to an account, oldest first
+func (r *WalletRepository) GetByAccount(accountID uuid.UUID) ([]Wallet, error) {
+	query := `
+		SELECT w.id, w.owner_id, w.status, w.status_reason, w.allowed_currencies, w.created_at, w.updated_at
+		FROM wallet_accounts wa
+		JOIN wallets w ON w.id = wa.wallet_id
+		WHERE wa.account_id = $1
+		ORDER BY w.created_at, w.id
+	`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get account wallets", "transaction-service")
+	}
+	defer rows.Close()
+
+	wallets := []Wallet{}
+	for rows.Next() {
+		var wallet Wallet
+		var ownerID uuid.NullUUID
+		err := rows.Scan(
+			&wallet.ID,
+			&ownerID,
+			&wallet.Status,
+			&wallet.StatusReason,
+			pq.Array(&wallet.AllowedCurrencies),
+			&wallet.CreatedAt,
+			&wallet.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan wallet", "transaction-service")
+		}
+		if ownerID.Valid {
+			wallet.OwnerID = &ownerID.UUID
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to iterate account wallets", "transaction-service")
+	}
+
+	return wallets, nil
+}
+
+// GetAccountID retrieves the account holder of a wallet, or nil if the wallet is not linked
+// to an account
+func (r *WalletRepository) GetAccountID(walletID uuid.UUID) (*uuid.UUID, error) {
+	query := `
+		SELECT wa.account_id
+		FROM wallets w
+		LEFT JOIN wallet_accounts wa ON wa.wallet_id = w.id
+		WHERE w.id = $1
+	`
+
+	var accountID uuid.NullUUID
+	err := r.db.QueryRow(query, walletID).Scan(&accountID)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewTransactionError(errors.ErrWalletNotFound, fmt.Sprintf("wallet %s not found", walletID))
+	}
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get wallet account", "transaction-service")
+	}
+
+	if !accountID.Valid {
+		return nil, nil
+	}
+	return &accountID.UUID, nil
+}
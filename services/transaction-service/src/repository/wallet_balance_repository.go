@@ -2,36 +2,50 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
 	"echopay/transaction-service/src/models"
 )
 
-// WalletBalance represents a wallet's current balance
+// WalletBalance represents a wallet's current balance. MinBalance is the reserve an outgoing
+// transfer may not draw the balance below. Held is the part of the balance reserved for
+// transfers awaiting settlement, which cannot be spent again.
 type WalletBalance struct {
 	WalletID uuid.UUID `json:"wallet_id"`
 	Currency models.Currency `json:"currency"`
 	Balance  float64 `json:"balance"`
+	MinBalance float64 `json:"min_balance"`
+	Held     float64 `json:"held"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // WalletBalanceRepository handles wallet balance operations
 type WalletBalanceRepository struct {
-	db *database.PostgresDB
+	db         *database.PostgresDB
+	currencies *currency.Registry
 }
 
 // NewWalletBalanceRepository creates a new wallet balance repository
 func NewWalletBalanceRepository(db *database.PostgresDB) *WalletBalanceRepository {
-	return &WalletBalanceRepository{db: db}
+	return &WalletBalanceRepository{db: db, currencies: currency.NewDefaultRegistry()}
+}
+
+// SetCurrencyRegistry sets the currencies new wallets are created with
+func (r *WalletBalanceRepository) SetCurrencyRegistry(registry *currency.Registry) {
+	r.currencies = registry
 }
 
 // GetBalance retrieves the current balance for a wallet and currency
 func (r *WalletBalanceRepository) GetBalance(walletID uuid.UUID, currency models.Currency) (*WalletBalance, error) {
 	query := `
-		SELECT wallet_id, currency, balance, updated_at
+		SELECT wallet_id, currency, balance, min_balance, held, updated_at
 		FROM wallet_balances 
 		WHERE wallet_id = $1 AND currency = $2
 	`
@@ -41,6 +55,8 @@ func (r *WalletBalanceRepository) GetBalance(walletID uuid.UUID, currency models
 		&balance.WalletID,
 		&balance.Currency,
 		&balance.Balance,
+		&balance.MinBalance,
+		&balance.Held,
 		&balance.UpdatedAt,
 	)
 	
@@ -58,7 +74,7 @@ func (r *WalletBalanceRepository) GetBalance(walletID uuid.UUID, currency models
 // GetBalanceForUpdate retrieves balance with row-level locking for atomic updates
 func (r *WalletBalanceRepository) GetBalanceForUpdate(tx *sql.Tx, walletID uuid.UUID, currency models.Currency) (*WalletBalance, error) {
 	query := `
-		SELECT wallet_id, currency, balance, updated_at
+		SELECT wallet_id, currency, balance, min_balance, held, updated_at
 		FROM wallet_balances 
 		WHERE wallet_id = $1 AND currency = $2
 		FOR UPDATE
@@ -69,6 +85,8 @@ func (r *WalletBalanceRepository) GetBalanceForUpdate(tx *sql.Tx, walletID uuid.
 		&balance.WalletID,
 		&balance.Currency,
 		&balance.Balance,
+		&balance.MinBalance,
+		&balance.Held,
 		&balance.UpdatedAt,
 	)
 	
@@ -108,31 +126,89 @@ func (r *WalletBalanceRepository) UpdateBalance(tx *sql.Tx, walletID uuid.UUID,
 	return nil
 }
 
+// UpdateHeld sets the amount of a wallet's balance held for transfers awaiting settlement
+func (r *WalletBalanceRepository) UpdateHeld(tx *sql.Tx, walletID uuid.UUID, currency models.Currency, held float64) error {
+	result, err := tx.Exec(`
+		UPDATE wallet_balances
+		SET held = $3, updated_at = NOW()
+		WHERE wallet_id = $1 AND currency = $2
+	`, walletID, currency, held)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update held balance", "transaction-service")
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+	
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrTransactionFailed, "wallet balance not found for update")
+	}
+	
+	return nil
+}
+
+// SetMinBalance sets the reserve a wallet must keep in a currency, creating its balance if
+// the wallet has none yet
+func (r *WalletBalanceRepository) SetMinBalance(walletID uuid.UUID, currency models.Currency, minBalance float64) (*WalletBalance, error) {
+	query := `
+		INSERT INTO wallet_balances (wallet_id, currency, balance, min_balance, updated_at)
+		VALUES ($1, $2, 0.0, $3, NOW())
+		ON CONFLICT (wallet_id, currency) DO UPDATE SET min_balance = $3, updated_at = NOW()
+		RETURNING wallet_id, currency, balance, min_balance, held, updated_at
+	`
+	
+	var balance WalletBalance
+	err := r.db.QueryRow(query, walletID, currency, minBalance).Scan(
+		&balance.WalletID,
+		&balance.Currency,
+		&balance.Balance,
+		&balance.MinBalance,
+		&balance.Held,
+		&balance.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to set wallet minimum balance", "transaction-service")
+	}
+	
+	return &balance, nil
+}
+
 // CreateWallet creates a new wallet with zero balances for all supported currencies
 func (r *WalletBalanceRepository) CreateWallet(walletID uuid.UUID) error {
-	currencies := []models.Currency{models.USDCBDC, models.EURCBDC, models.GBPCBDC}
-	
 	return r.db.Transaction(func(tx *sql.Tx) error {
-		for _, currency := range currencies {
-			query := `
-				INSERT INTO wallet_balances (wallet_id, currency, balance, updated_at)
-				VALUES ($1, $2, 0.0, NOW())
-				ON CONFLICT (wallet_id, currency) DO NOTHING
-			`
-			
-			_, err := tx.Exec(query, walletID, currency)
-			if err != nil {
-				return errors.WrapError(err, errors.ErrTransactionFailed, "failed to create wallet balance", "transaction-service")
-			}
-		}
-		return nil
+		return r.CreateWalletInTx(tx, walletID)
 	})
 }
 
+// CreateWalletInTx creates zero balances for all supported currencies within a transaction
+func (r *WalletBalanceRepository) CreateWalletInTx(tx *sql.Tx, walletID uuid.UUID) error {
+	return r.CreateBalancesInTx(tx, walletID, r.currencies.Codes())
+}
+
+// CreateBalancesInTx creates zero balances for the given currencies within a transaction,
+// leaving any existing balance untouched
+func (r *WalletBalanceRepository) CreateBalancesInTx(tx *sql.Tx, walletID uuid.UUID, currencies []string) error {
+	for _, currency := range currencies {
+		query := `
+			INSERT INTO wallet_balances (wallet_id, currency, balance, updated_at)
+			VALUES ($1, $2, 0.0, NOW())
+			ON CONFLICT (wallet_id, currency) DO NOTHING
+		`
+		
+		_, err := tx.Exec(query, walletID, currency)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to create wallet balance", "transaction-service")
+		}
+	}
+	return nil
+}
+
 // GetWalletBalances retrieves all balances for a wallet
 func (r *WalletBalanceRepository) GetWalletBalances(walletID uuid.UUID) ([]*WalletBalance, error) {
 	query := `
-		SELECT wallet_id, currency, balance, updated_at
+		SELECT wallet_id, currency, balance, min_balance, held, updated_at
 		FROM wallet_balances 
 		WHERE wallet_id = $1
 		ORDER BY currency
@@ -152,6 +228,8 @@ func (r *WalletBalanceRepository) GetWalletBalances(walletID uuid.UUID) ([]*Wall
 			&balance.WalletID,
 			&balance.Currency,
 			&balance.Balance,
+			&balance.MinBalance,
+			&balance.Held,
 			&balance.UpdatedAt,
 		)
 		if err != nil {
@@ -178,13 +256,91 @@ func (r *WalletBalanceRepository) GetWalletBalances(walletID uuid.UUID) ([]*Wall
 	return balances, nil
 }
 
+// GetBalancesForWallets retrieves the balances of many wallets in one currency with a single
+// query. Every requested wallet is in the result; wallets with no balance row, including
+// unknown wallets, have a balance of zero. No balance rows are created.
+func (r *WalletBalanceRepository) GetBalancesForWallets(walletIDs []uuid.UUID, currency models.Currency) (map[uuid.UUID]float64, error) {
+	balances := make(map[uuid.UUID]float64, len(walletIDs))
+	if len(walletIDs) == 0 {
+		return balances, nil
+	}
+
+	ids := make([]string, len(walletIDs))
+	for i, walletID := range walletIDs {
+		ids[i] = walletID.String()
+		balances[walletID] = 0
+	}
+
+	query := `
+		SELECT wallet_id, balance
+		FROM wallet_balances
+		WHERE wallet_id = ANY($1) AND currency = $2
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids), currency)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get wallet balances", "transaction-service")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var walletID uuid.UUID
+		var balance float64
+		if err := rows.Scan(&walletID, &balance); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan wallet balance", "transaction-service")
+		}
+		balances[walletID] = balance
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "error iterating wallet balances", "transaction-service")
+	}
+
+	return balances, nil
+}
+
 // AddFunds adds funds to a wallet (for testing and initial funding)
 func (r *WalletBalanceRepository) AddFunds(walletID uuid.UUID, currency models.Currency, amount float64) error {
+	_, err := r.AddFundsWithRef(walletID, currency, amount, "")
+	return err
+}
+
+// AddFundsWithRef adds funds to a wallet once per external reference, such as the ID of the
+// bank transfer or mint instruction behind the funding, so a retried funding does not credit
+// the wallet twice. It reports whether the wallet was credited; false means the reference had
+// already been applied. An empty reference credits the wallet without deduplication.
+func (r *WalletBalanceRepository) AddFundsWithRef(walletID uuid.UUID, currency models.Currency, amount float64, externalRef string) (bool, error) {
 	if amount <= 0 {
-		return errors.NewTransactionError(errors.ErrInvalidTransaction, "amount must be positive")
+		return false, errors.NewTransactionError(errors.ErrInvalidTransaction, "amount must be positive")
+	}
+
+	if !money.IsExact(amount, string(currency)) {
+		return false, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("amount has more than %d decimal places", money.Decimals(string(currency))))
 	}
 	
-	return r.db.Transaction(func(tx *sql.Tx) error {
+	credited := false
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		if externalRef != "" {
+			// A concurrent funding with the same reference waits here for the first to commit
+			result, err := tx.Exec(`
+				INSERT INTO fund_events (wallet_id, currency, external_ref, amount, created_at)
+				VALUES ($1, $2, $3, $4, NOW())
+				ON CONFLICT (wallet_id, currency, external_ref) DO NOTHING
+			`, walletID, currency, externalRef, amount)
+			if err != nil {
+				return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record funding", "transaction-service")
+			}
+			
+			inserted, err := result.RowsAffected()
+			if err != nil {
+				return errors.WrapError(err, errors.ErrTransactionFailed, "failed to record funding", "transaction-service")
+			}
+			if inserted == 0 {
+				return nil
+			}
+		}
+		credited = true
+		
 		// Get current balance with lock
 		var currentBalance float64
 		query := `
@@ -206,8 +362,8 @@ func (r *WalletBalanceRepository) AddFunds(walletID uuid.UUID, currency models.C
 			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get current balance", "transaction-service")
 		}
 		
-		// Update balance
-		newBalance := currentBalance + amount
+		// Update balance in minor units to avoid floating-point drift
+		newBalance := money.FromMinor(money.ToMinor(currentBalance, string(currency))+money.ToMinor(amount, string(currency)), string(currency))
 		_, err = tx.Exec(`
 			UPDATE wallet_balances 
 			SET balance = $3, updated_at = NOW()
@@ -220,6 +376,11 @@ func (r *WalletBalanceRepository) AddFunds(walletID uuid.UUID, currency models.C
 		
 		return nil
 	})
+	if err != nil {
+		return false, err
+	}
+	
+	return credited, nil
 }
 
 // GetTotalBalance returns the total balance across all currencies (converted to USD equivalent)
@@ -241,13 +402,60 @@ func (r *WalletBalanceRepository) GetTotalBalance(walletID uuid.UUID) (float64,
 	return totalBalance, nil
 }
 
+// HasFundsInTx reports whether a wallet holds a non-zero balance in any currency
+func (r *WalletBalanceRepository) HasFundsInTx(tx *sql.Tx, walletID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM wallet_balances
+			WHERE wallet_id = $1 AND balance > 0
+		)
+	`
+	
+	var hasFunds bool
+	if err := tx.QueryRow(query, walletID).Scan(&hasFunds); err != nil {
+		return false, errors.WrapError(err, errors.ErrTransactionFailed, "failed to check wallet balances", "transaction-service")
+	}
+	
+	return hasFunds, nil
+}
+
+// FundedCurrenciesInTx lists the currencies a wallet holds a non-zero balance or hold in
+func (r *WalletBalanceRepository) FundedCurrenciesInTx(tx *sql.Tx, walletID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT currency FROM wallet_balances
+		WHERE wallet_id = $1 AND (balance > 0 OR held > 0)
+		ORDER BY currency
+	`
+	
+	rows, err := tx.Query(query, walletID)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to check wallet balances", "transaction-service")
+	}
+	defer rows.Close()
+	
+	var currencies []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to scan wallet balance", "transaction-service")
+		}
+		currencies = append(currencies, code)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to iterate wallet balances", "transaction-service")
+	}
+	
+	return currencies, nil
+}
+
 // createZeroBalance creates a zero balance entry for a new wallet
 func (r *WalletBalanceRepository) createZeroBalance(walletID uuid.UUID, currency models.Currency) (*WalletBalance, error) {
 	query := `
 		INSERT INTO wallet_balances (wallet_id, currency, balance, updated_at)
 		VALUES ($1, $2, 0.0, NOW())
 		ON CONFLICT (wallet_id, currency) DO NOTHING
-		RETURNING wallet_id, currency, balance, updated_at
+		RETURNING wallet_id, currency, balance, min_balance, held, updated_at
 	`
 	
 	var balance WalletBalance
@@ -255,6 +463,8 @@ func (r *WalletBalanceRepository) createZeroBalance(walletID uuid.UUID, currency
 		&balance.WalletID,
 		&balance.Currency,
 		&balance.Balance,
+		&balance.MinBalance,
+		&balance.Held,
 		&balance.UpdatedAt,
 	)
 	
@@ -275,7 +485,7 @@ func (r *WalletBalanceRepository) createZeroBalanceInTx(tx *sql.Tx, walletID uui
 		INSERT INTO wallet_balances (wallet_id, currency, balance, updated_at)
 		VALUES ($1, $2, 0.0, NOW())
 		ON CONFLICT (wallet_id, currency) DO NOTHING
-		RETURNING wallet_id, currency, balance, updated_at
+		RETURNING wallet_id, currency, balance, min_balance, held, updated_at
 	`
 	
 	var balance WalletBalance
@@ -283,6 +493,8 @@ func (r *WalletBalanceRepository) createZeroBalanceInTx(tx *sql.Tx, walletID uui
 		&balance.WalletID,
 		&balance.Currency,
 		&balance.Balance,
+		&balance.MinBalance,
+		&balance.Held,
 		&balance.UpdatedAt,
 	)
 	
@@ -309,9 +521,51 @@ func (r *WalletBalanceRepository) Migrate() error {
 			PRIMARY KEY (wallet_id, currency)
 		)`,
 		
+		// Reserve outgoing transfers cannot draw the balance below
+		`ALTER TABLE wallet_balances ADD COLUMN IF NOT EXISTS min_balance DECIMAL(15,2) NOT NULL DEFAULT 0.0 CHECK (min_balance >= 0)`,
+		
+		// Funds reserved for transfers awaiting settlement
+		`ALTER TABLE wallet_balances ADD COLUMN IF NOT EXISTS held DECIMAL(15,2) NOT NULL DEFAULT 0.0 CHECK (held >= 0)`,
+		`CREATE TABLE IF NOT EXISTS balance_holds (
+			transaction_id UUID PRIMARY KEY,
+			wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+			fee DECIMAL(15,2) NOT NULL DEFAULT 0.0 CHECK (fee >= 0),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		
+		// Fundings applied by external reference, so retried fundings credit a wallet once
+		`CREATE TABLE IF NOT EXISTS fund_events (
+			wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			external_ref VARCHAR(128) NOT NULL,
+			amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (wallet_id, currency, external_ref)
+		)`,
+		
 		// Create indexes for performance
 		`CREATE INDEX IF NOT EXISTS idx_wallet_balances_wallet_id ON wallet_balances(wallet_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_wallet_balances_updated_at ON wallet_balances(updated_at)`,
+		
+		// Moving averages of each wallet's outgoing transfers, scored against by fraud detection
+		`CREATE TABLE IF NOT EXISTS wallet_baselines (
+			wallet_id UUID NOT NULL,
+			currency VARCHAR(20) NOT NULL,
+			amount_ema DOUBLE PRECISION NOT NULL,
+			interval_ema DOUBLE PRECISION NOT NULL,
+			observations INTEGER NOT NULL DEFAULT 0,
+			last_transaction_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (wallet_id, currency)
+		)`,
+		
+		// Time-decayed average of the fraud scores of each wallet's outgoing transfers
+		`ALTER TABLE wallet_baselines
+			ADD COLUMN IF NOT EXISTS risk_ema DOUBLE PRECISION NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS risk_observations INTEGER NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS risk_updated_at TIMESTAMP WITH TIME ZONE`,
 	}
 	
 	return r.db.Migrate(migrations)
@@ -102,6 +102,65 @@ func TestWalletBalanceRepository_AddFunds_InvalidAmount(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestWalletBalanceRepository_AddFunds_SmallAmountsSumExactly(t *testing.T) {
+	repo, db := setupTestBalanceRepo(t)
+	defer db.Close()
+	
+	walletID := uuid.New()
+	
+	// 1,000 deposits of 0.10 drift away from 100 when summed as float64
+	for i := 0; i < 1000; i++ {
+		err := repo.AddFunds(walletID, models.USDCBDC, 0.1)
+		require.NoError(t, err)
+	}
+	
+	balance, err := repo.GetBalance(walletID, models.USDCBDC)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, balance.Balance)
+}
+
+func TestWalletBalanceRepository_AddFunds_SubCentAmount(t *testing.T) {
+	repo, db := setupTestBalanceRepo(t)
+	defer db.Close()
+	
+	err := repo.AddFunds(uuid.New(), models.USDCBDC, 0.001)
+	assert.Error(t, err)
+}
+
+func TestWalletBalanceRepository_AddFundsWithRef(t *testing.T) {
+	repo, db := setupTestBalanceRepo(t)
+	defer db.Close()
+	
+	walletID := uuid.New()
+	externalRef := "wire-" + uuid.New().String()
+	
+	credited, err := repo.AddFundsWithRef(walletID, models.USDCBDC, 250.0, externalRef)
+	require.NoError(t, err)
+	assert.True(t, credited)
+	
+	// A retried funding with the same reference succeeds without crediting again
+	credited, err = repo.AddFundsWithRef(walletID, models.USDCBDC, 250.0, externalRef)
+	require.NoError(t, err)
+	assert.False(t, credited)
+	
+	balance, err := repo.GetBalance(walletID, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, balance.Balance)
+	
+	// The same reference in another currency is a separate funding
+	credited, err = repo.AddFundsWithRef(walletID, models.EURCBDC, 100.0, externalRef)
+	require.NoError(t, err)
+	assert.True(t, credited)
+	
+	// Without a reference every funding is credited
+	require.NoError(t, repo.AddFunds(walletID, models.USDCBDC, 50.0))
+	require.NoError(t, repo.AddFunds(walletID, models.USDCBDC, 50.0))
+	
+	balance, err = repo.GetBalance(walletID, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 350.0, balance.Balance)
+}
+
 func TestWalletBalanceRepository_UpdateBalance(t *testing.T) {
 	repo, db := setupTestBalanceRepo(t)
 	defer db.Close()
@@ -201,6 +260,42 @@ func TestWalletBalanceRepository_GetWalletBalances(t *testing.T) {
 	assert.Equal(t, 0.0, balanceMap[models.GBPCBDC]) // Should be zero
 }
 
+func TestWalletBalanceRepository_GetBalancesForWallets(t *testing.T) {
+	repo, db := setupTestBalanceRepo(t)
+	defer db.Close()
+	
+	funded := uuid.New()
+	err := repo.AddFunds(funded, models.USDCBDC, 250.0)
+	require.NoError(t, err)
+	
+	// A wallet with balance rows but nothing in them
+	empty := uuid.New()
+	err = repo.CreateWallet(empty)
+	require.NoError(t, err)
+	
+	// Funded in another currency only
+	otherCurrency := uuid.New()
+	err = repo.AddFunds(otherCurrency, models.EURCBDC, 75.0)
+	require.NoError(t, err)
+	
+	unknown := uuid.New()
+	
+	balances, err := repo.GetBalancesForWallets([]uuid.UUID{funded, empty, otherCurrency, unknown}, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]float64{
+		funded:        250.0,
+		empty:         0.0,
+		otherCurrency: 0.0,
+		unknown:       0.0,
+	}, balances)
+	
+	// Unknown wallets are reported without being created
+	var rows int
+	err = db.QueryRow("SELECT COUNT(*) FROM wallet_balances WHERE wallet_id = $1", unknown).Scan(&rows)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rows)
+}
+
 func TestWalletBalanceRepository_GetTotalBalance(t *testing.T) {
 	repo, db := setupTestBalanceRepo(t)
 	defer db.Close()
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// WalletBaseline is a wallet's typical outgoing activity in one currency: exponential moving
+// averages of its transfer amounts and of the time between its transfers. Observations is the
+// number of completed transfers folded in. RiskEMA averages the fraud scores of the wallet's
+// transfers as of RiskUpdatedAt; CurrentRisk is that average decayed to when the baseline was
+// read, and is not stored.
+type WalletBaseline struct {
+	WalletID          uuid.UUID       `json:"wallet_id"`
+	Currency          models.Currency `json:"currency"`
+	AmountEMA         float64         `json:"amount_ema"`
+	IntervalEMA       float64         `json:"interval_ema_seconds"`
+	Observations      int             `json:"observations"`
+	LastTransactionAt *time.Time      `json:"last_transaction_at,omitempty"`
+	RiskEMA           float64         `json:"risk_ema"`
+	RiskObservations  int             `json:"risk_observations"`
+	RiskUpdatedAt     *time.Time      `json:"risk_updated_at,omitempty"`
+	CurrentRisk       float64         `json:"current_risk"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// GetBaseline retrieves a wallet's baseline in a currency, or nil if it has none
+func (r *WalletBalanceRepository) GetBaseline(walletID uuid.UUID, currency models.Currency) (*WalletBaseline, error) {
+	return scanBaseline(r.db.QueryRow(`
+		SELECT wallet_id, currency, amount_ema, interval_ema, observations, last_transaction_at,
+			risk_ema, risk_observations, risk_updated_at, updated_at
+		FROM wallet_baselines
+		WHERE wallet_id = $1 AND currency = $2
+	`, walletID, currency))
+}
+
+// GetBaselineForUpdateInTx retrieves and locks a wallet's baseline in a currency, or returns
+// nil if it has none, so concurrent transfers fold into it one at a time
+func (r *WalletBalanceRepository) GetBaselineForUpdateInTx(tx *sql.Tx, walletID uuid.UUID, currency models.Currency) (*WalletBaseline, error) {
+	return scanBaseline(tx.QueryRow(`
+		SELECT wallet_id, currency, amount_ema, interval_ema, observations, last_transaction_at,
+			risk_ema, risk_observations, risk_updated_at, updated_at
+		FROM wallet_baselines
+		WHERE wallet_id = $1 AND currency = $2
+		FOR UPDATE
+	`, walletID, currency))
+}
+
+// SaveBaselineInTx creates or replaces a wallet's baseline in a currency
+func (r *WalletBalanceRepository) SaveBaselineInTx(tx *sql.Tx, baseline *WalletBaseline) error {
+	_, err := tx.Exec(`
+		INSERT INTO wallet_baselines (wallet_id, currency, amount_ema, interval_ema, observations, last_transaction_at,
+			risk_ema, risk_observations, risk_updated_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (wallet_id, currency) DO UPDATE SET
+			amount_ema = EXCLUDED.amount_ema,
+			interval_ema = EXCLUDED.interval_ema,
+			observations = EXCLUDED.observations,
+			last_transaction_at = EXCLUDED.last_transaction_at,
+			risk_ema = EXCLUDED.risk_ema,
+			risk_observations = EXCLUDED.risk_observations,
+			risk_updated_at = EXCLUDED.risk_updated_at,
+			updated_at = EXCLUDED.updated_at
+	`, baseline.WalletID, baseline.Currency, baseline.AmountEMA, baseline.IntervalEMA, baseline.Observations, baseline.LastTransactionAt,
+		baseline.RiskEMA, baseline.RiskObservations, baseline.RiskUpdatedAt, baseline.UpdatedAt)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to save wallet baseline", "transaction-service")
+	}
+	return nil
+}
+
+func scanBaseline(row *sql.Row) (*WalletBaseline, error) {
+	var baseline WalletBaseline
+	var lastTransactionAt, riskUpdatedAt sql.NullTime
+	err := row.Scan(
+		&baseline.WalletID,
+		&baseline.Currency,
+		&baseline.AmountEMA,
+		&baseline.IntervalEMA,
+		&baseline.Observations,
+		&lastTransactionAt,
+		&baseline.RiskEMA,
+		&baseline.RiskObservations,
+		&riskUpdatedAt,
+		&baseline.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get wallet baseline", "transaction-service")
+	}
+	if lastTransactionAt.Valid {
+		baseline.LastTransactionAt = &lastTransactionAt.Time
+	}
+	if riskUpdatedAt.Valid {
+		baseline.RiskUpdatedAt = &riskUpdatedAt.Time
+	}
+	return &baseline, nil
+}
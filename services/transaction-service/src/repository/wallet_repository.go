@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+)
+
+// WalletStatus is the lifecycle state of a wallet
+type WalletStatus string
+
+// Wallet lifecycle states
+const (
+	WalletStatusActive WalletStatus = "active"
+	WalletStatusFrozen WalletStatus = "frozen"
+	WalletStatusClosed WalletStatus = "closed"
+)
+
+// Wallet is a registered wallet. Balances are kept separately in wallet_balances.
+// AllowedCurrencies restricts the currencies the wallet may hold; nil allows every supported
+// currency.
+type Wallet struct {
+	ID        uuid.UUID    `json:"id"`
+	OwnerID   *uuid.UUID   `json:"owner_id,omitempty"`
+	Status       WalletStatus `json:"status"`
+	StatusReason string       `json:"status_reason,omitempty"`
+	AllowedCurrencies []string `json:"allowed_currencies,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// PermitsCurrency reports whether the wallet may hold a currency
+func (w *Wallet) PermitsCurrency(currency string) bool {
+	if w.AllowedCurrencies == nil {
+		return true
+	}
+	for _, allowed := range w.AllowedCurrencies {
+		if allowed == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// WalletRepository handles wallet registration and lifecycle
+type WalletRepository struct {
+	db *database.PostgresDB
+}
+
+// NewWalletRepository creates a new wallet repository
+func NewWalletRepository(db *database.PostgresDB) *WalletRepository {
+	return &WalletRepository{db: db}
+}
+
+// CreateInTx registers a wallet within a transaction
+func (r *WalletRepository) CreateInTx(tx *sql.Tx, wallet *Wallet) error {
+	query := `
+		INSERT INTO wallets (id, owner_id, status, allowed_currencies, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(query, wallet.ID, wallet.OwnerID, wallet.Status, pq.Array(wallet.AllowedCurrencies), wallet.CreatedAt, wallet.UpdatedAt)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to create wallet", "transaction-service")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a wallet by ID
+func (r *WalletRepository) GetByID(walletID uuid.UUID) (*Wallet, error) {
+	query := `
+		SELECT id, owner_id, status, status_reason, allowed_currencies, created_at, updated_at
+		FROM wallets
+		WHERE id = $1
+	`
+
+	return r.scanWallet(r.db.QueryRow(query, walletID), walletID)
+}
+
+// GetForShareInTx retrieves a wallet and locks it against concurrent status changes until
+// the transaction ends
+func (r *WalletRepository) GetForShareInTx(tx *sql.Tx, walletID uuid.UUID) (*Wallet, error) {
+	query := `
+		SELECT id, owner_id, status, status_reason, allowed_currencies, created_at, updated_at
+		FROM wallets
+		WHERE id = $1
+		FOR SHARE
+	`
+
+	return r.scanWallet(tx.QueryRow(query, walletID), walletID)
+}
+
+// GetForUpdateInTx retrieves a wallet with a row lock for a status change
+func (r *WalletRepository) GetForUpdateInTx(tx *sql.Tx, walletID uuid.UUID) (*Wallet, error) {
+	query := `
+		SELECT id, owner_id, status, status_reason, allowed_currencies, created_at, updated_at
+		FROM wallets
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	return r.scanWallet(tx.QueryRow(query, walletID), walletID)
+}
+
+// UpdateStatusInTx changes a wallet's lifecycle status within a transaction, recording why
+func (r *WalletRepository) UpdateStatusInTx(tx *sql.Tx, walletID uuid.UUID, status WalletStatus, reason string) error {
+	query := `
+		UPDATE wallets
+		SET status = $2, status_reason = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(query, walletID, status, reason)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update wallet status", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrWalletNotFound, fmt.Sprintf("wallet %s not found", walletID))
+	}
+
+	return nil
+}
+
+// SetAllowedCurrenciesInTx replaces the currencies a wallet may hold within a transaction. A
+// nil list allows every supported currency.
+func (r *WalletRepository) SetAllowedCurrenciesInTx(tx *sql.Tx, walletID uuid.UUID, currencies []string) error {
+	query := `
+		UPDATE wallets
+		SET allowed_currencies = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(query, walletID, pq.Array(currencies))
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update wallet currencies", "transaction-service")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to check update result", "transaction-service")
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewTransactionError(errors.ErrWalletNotFound, fmt.Sprintf("wallet %s not found", walletID))
+	}
+
+	return nil
+}
+
+// scanWallet scans a wallet row, reporting a missing wallet as ErrWalletNotFound
+func (r *WalletRepository) scanWallet(row *sql.Row, walletID uuid.UUID) (*Wallet, error) {
+	var wallet Wallet
+	var ownerID uuid.NullUUID
+	err := row.Scan(
+		&wallet.ID,
+		&ownerID,
+		&wallet.Status,
+		&wallet.StatusReason,
+		pq.Array(&wallet.AllowedCurrencies),
+		&wallet.CreatedAt,
+		&wallet.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewTransactionError(errors.ErrWalletNotFound, fmt.Sprintf("wallet %s not found", walletID))
+		}
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get wallet", "transaction-service")
+	}
+
+	if ownerID.Valid {
+		wallet.OwnerID = &ownerID.UUID
+	}
+
+	return &wallet, nil
+}
+
+// Migrate creates the wallets table and registers every wallet that already has balances.
+// Wallets from before explicit creation have no recorded owner.
+func (r *WalletRepository) Migrate() error {
+	migrations := []string{
+		`CREATE TABLE IF NOT EXISTS wallets (
+			id UUID PRIMARY KEY,
+			owner_id UUID,
+			status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'frozen', 'closed')),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_wallets_owner_id ON wallets(owner_id)`,
+
+		// Backfill wallets that were created implicitly by their first balance
+		`INSERT INTO wallets (id, status, created_at, updated_at)
+		SELECT wallet_id, 'active', MIN(updated_at), NOW()
+		FROM wallet_balances
+		GROUP BY wallet_id
+		ON CONFLICT (id) DO NOTHING`,
+
+		// Why a wallet was last frozen or closed
+		`ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT ''`,
+
+		// Account holders and the wallets they hold, one holder per wallet
+		`CREATE TABLE IF NOT EXISTS accounts (
+			id UUID PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS wallet_accounts (
+			wallet_id UUID PRIMARY KEY REFERENCES wallets(id),
+			account_id UUID NOT NULL REFERENCES accounts(id),
+			linked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_wallet_accounts_account_id ON wallet_accounts(account_id)`,
+
+		// Wallets created with an owner are held by that owner's account
+		`INSERT INTO accounts (id, created_at)
+		SELECT owner_id, MIN(created_at)
+		FROM wallets
+		WHERE owner_id IS NOT NULL
+		GROUP BY owner_id
+		ON CONFLICT (id) DO NOTHING`,
+
+		`INSERT INTO wallet_accounts (wallet_id, account_id, linked_at)
+		SELECT id, owner_id, created_at
+		FROM wallets
+		WHERE owner_id IS NOT NULL
+		ON CONFLICT (wallet_id) DO NOTHING`,
+
+		// Currencies a wallet is restricted to; NULL allows every supported currency
+		`ALTER TABLE wallets ADD COLUMN IF NOT EXISTS allowed_currencies TEXT[]`,
+	}
+
+	return r.db.Migrate(migrations)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// GetOutgoingFromWallets retrieves the pending and completed transactions sent from any of
+// the given wallets at or after since, oldest first
+func (r *TransactionRepository) GetOutgoingFromWallets(walletIDs []uuid.UUID, since time.Time) ([]*models.Transaction, error) {
+	if len(walletIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, from_wallet_id, to_wallet_id, amount, currency, 
+			   status, fraud_score, created_at, settled_at, metadata
+		FROM transactions 
+		WHERE from_wallet_id = ANY($1) AND status IN ('pending', 'completed') AND created_at >= $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(query, pq.Array(walletIDs), since)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get outgoing transactions by wallets", "transaction-service")
+	}
+	defer rows.Close()
+
+	return r.scanTransactionRows(rows)
+}
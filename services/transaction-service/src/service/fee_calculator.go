@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/models"
+)
+
+// FeeCalculator computes the fee levied on a transaction
+type FeeCalculator interface {
+	Calculate(tx *models.Transaction) float64
+}
+
+// FeeConfig controls per-transaction fee collection
+type FeeConfig struct {
+	Enabled          bool
+	CollectionWallet uuid.UUID
+	Calculator       FeeCalculator
+}
+
+// DefaultFeeConfig returns a configuration with fee collection disabled
+func DefaultFeeConfig() FeeConfig {
+	return FeeConfig{
+		Enabled:    false,
+		Calculator: NewTieredFeeCalculator(0, 0, 0),
+	}
+}
+
+// NewFeeConfigFromConfig builds fee collection settings from configuration. Fees must be
+// non-negative, the percentage a fraction of at most 1, and enabled fees need a collection
+// wallet.
+func NewFeeConfigFromConfig(cfg config.FeeConfig) (FeeConfig, error) {
+	if cfg.FlatFee < 0 || cfg.MaxFee < 0 || cfg.Percentage < 0 || cfg.Percentage > 1 {
+		return FeeConfig{}, fmt.Errorf("invalid fee amounts: flat %v, percentage %v, max %v", cfg.FlatFee, cfg.Percentage, cfg.MaxFee)
+	}
+
+	feeConfig := FeeConfig{
+		Enabled:    cfg.Enabled,
+		Calculator: NewTieredFeeCalculator(cfg.FlatFee, cfg.Percentage, cfg.MaxFee),
+	}
+	if !cfg.Enabled {
+		return feeConfig, nil
+	}
+
+	wallet, err := uuid.Parse(cfg.CollectionWallet)
+	if err != nil || wallet == uuid.Nil {
+		return FeeConfig{}, fmt.Errorf("invalid fee collection wallet %q", cfg.CollectionWallet)
+	}
+	feeConfig.CollectionWallet = wallet
+	return feeConfig, nil
+}
+
+// TieredFeeCalculator charges a flat fee plus a percentage of the amount, capped at MaxFee
+type TieredFeeCalculator struct {
+	FlatFee    float64
+	Percentage float64 // Fraction of the amount, e.g. 0.001 for 0.1%
+	MaxFee     float64 // Zero means no cap
+}
+
+// NewTieredFeeCalculator creates a new tiered fee calculator
+func NewTieredFeeCalculator(flatFee, percentage, maxFee float64) *TieredFeeCalculator {
+	return &TieredFeeCalculator{
+		FlatFee:    flatFee,
+		Percentage: percentage,
+		MaxFee:     maxFee,
+	}
+}
+
+// Calculate returns the fee for a transaction rounded to the currency's minor unit
+func (c *TieredFeeCalculator) Calculate(tx *models.Transaction) float64 {
+	fee := c.FlatFee + tx.Amount*c.Percentage
+	if c.MaxFee > 0 && fee > c.MaxFee {
+		fee = c.MaxFee
+	}
+	if fee < 0 {
+		fee = 0
+	}
+
+	return money.Round(fee, string(tx.Currency))
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/config"
+	"echopay/transaction-service/src/models"
+)
+
+func TestTieredFeeCalculator_Calculate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		calculator *TieredFeeCalculator
+		amount     float64
+		expected   float64
+	}{
+		{"flat only", NewTieredFeeCalculator(0.25, 0, 0), 100.0, 0.25},
+		{"flat plus percentage", NewTieredFeeCalculator(0.25, 0.01, 0), 100.0, 1.25},
+		{"percentage capped", NewTieredFeeCalculator(0.25, 0.01, 5.0), 10000.0, 5.0},
+		{"rounded to cents", NewTieredFeeCalculator(0, 0.003, 0), 10.0, 0.03},
+		{"zero configuration", NewTieredFeeCalculator(0, 0, 0), 100.0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := &models.Transaction{
+				ID:       uuid.New(),
+				Amount:   tc.amount,
+				Currency: models.USDCBDC,
+			}
+			assert.Equal(t, tc.expected, tc.calculator.Calculate(tx))
+		})
+	}
+}
+
+func TestTieredFeeCalculator_RoundsToCurrencyMinorUnit(t *testing.T) {
+	calculator := NewTieredFeeCalculator(0, 0.003, 0)
+
+	tx := &models.Transaction{ID: uuid.New(), Amount: 2500, Currency: models.Currency("JPY-CBDC")}
+	assert.Equal(t, 8.0, calculator.Calculate(tx), "zero-decimal currencies are charged whole units")
+
+	tx = &models.Transaction{ID: uuid.New(), Amount: 2500, Currency: models.USDCBDC}
+	assert.Equal(t, 7.5, calculator.Calculate(tx))
+}
+
+func TestDefaultFeeConfig_Disabled(t *testing.T) {
+	config := DefaultFeeConfig()
+	assert.False(t, config.Enabled)
+
+	service := &TransactionService{feeConfig: config}
+	tx := &models.Transaction{ID: uuid.New(), FromWallet: uuid.New(), Amount: 100.0}
+	assert.Equal(t, 0.0, service.calculateFee(tx))
+}
+
+func TestNewFeeConfigFromConfig(t *testing.T) {
+	wallet := uuid.New()
+
+	feeConfig, err := NewFeeConfigFromConfig(config.FeeConfig{
+		Enabled:          true,
+		CollectionWallet: wallet.String(),
+		FlatFee:          0.25,
+		Percentage:       0.001,
+		MaxFee:           10,
+	})
+	require.NoError(t, err)
+	assert.True(t, feeConfig.Enabled)
+	assert.Equal(t, wallet, feeConfig.CollectionWallet)
+	assert.Equal(t, NewTieredFeeCalculator(0.25, 0.001, 10), feeConfig.Calculator)
+
+	disabled, err := NewFeeConfigFromConfig(config.FeeConfig{})
+	require.NoError(t, err)
+	assert.False(t, disabled.Enabled)
+
+	_, err = NewFeeConfigFromConfig(config.FeeConfig{Enabled: true})
+	assert.Error(t, err, "enabled fees need a collection wallet")
+
+	_, err = NewFeeConfigFromConfig(config.FeeConfig{Percentage: 1.5})
+	assert.Error(t, err)
+}
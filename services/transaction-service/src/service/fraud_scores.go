@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+)
+
+// MaxFraudScoreBatchSize limits the number of transactions scored by a single bulk update
+const MaxFraudScoreBatchSize = 500
+
+// SetFraudScoresBulk sets the fraud scores of many transactions in one database transaction,
+// appending a fraud score audit entry to each. Every score is validated before any
+// transaction is loaded, and if any update fails none are applied. Tokens moved by a
+// transaction scored above the auto-freeze threshold are frozen as it is loaded. Fraud score
+// events, and alerts for scores above the critical threshold, are published for each
+// transaction once the batch has committed.
+func (s *TransactionService) SetFraudScoresBulk(ctx context.Context, scores map[uuid.UUID]float64, details map[string]interface{}) error {
+	if err := validateFraudScores(scores); err != nil {
+		return err
+	}
+
+	// Apply updates in a deterministic order so concurrent batches lock rows consistently
+	ids := make([]uuid.UUID, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+
+	transactions := make([]*models.Transaction, len(ids))
+	oldScores := make([]*float64, len(ids))
+	recordedDetails := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		transaction, err := s.repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		scoreDetails, err := s.autoFreezeTokens(ctx, transaction, scores[id], details)
+		if err != nil {
+			return err
+		}
+
+		oldScores[i] = transaction.FraudScore
+		recordedDetails[i] = scoreDetails
+		if err := transaction.SetFraudScore(scores[id], "fraud-detection", scoreDetails); err != nil {
+			return err
+		}
+		transactions[i] = transaction
+	}
+
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		for _, transaction := range transactions {
+			if err := s.repo.UpdateInTx(tx, transaction); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, transaction := range transactions {
+		score := scores[transaction.ID]
+		s.observeFraudScore(transaction.Currency, score)
+		s.updateWalletRisk(ctx, transaction, score)
+		s.alertHighRisk(ctx, transaction, score, recordedDetails[i])
+
+		// Publish fraud score update events
+		s.publishTransactionEvent(ctx, transaction, events.EventFraudScoreUpdated)
+		s.statusTracker.PublishFraudScoreUpdate(transaction, oldScores[i], &score)
+	}
+
+	return nil
+}
+
+// validateFraudScores checks a bulk fraud score update before any transaction is touched
+func validateFraudScores(scores map[uuid.UUID]float64) error {
+	if len(scores) == 0 {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, "at least one fraud score is required")
+	}
+	if len(scores) > MaxFraudScoreBatchSize {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("a bulk update can score at most %d transactions", MaxFraudScoreBatchSize))
+	}
+
+	for id, score := range scores {
+		if id == uuid.Nil {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "transaction ID cannot be nil")
+		}
+		if math.IsNaN(score) || score < 0 || score > 1 {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("fraud score for transaction %s must be between 0 and 1", id))
+		}
+	}
+
+	return nil
+}
+
+// autoFreezeTokens freezes the tokens moved by a transaction when its new fraud score exceeds
+// the auto-freeze threshold, and returns the fraud score audit details with the trigger
+// recorded. The freeze note records the trigger in the tokens' audit trails. Otherwise, or
+// when the transaction moved no tokens, details are returned unchanged.
+func (s *TransactionService) autoFreezeTokens(ctx context.Context, transaction *models.Transaction, score float64, details map[string]interface{}) (map[string]interface{}, error) {
+	if s.autoFreezeThreshold == nil || score <= *s.autoFreezeThreshold {
+		return details, nil
+	}
+	threshold := *s.autoFreezeThreshold
+
+	tokenIDs, err := s.repo.GetTokenIDs(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokenIDs) == 0 {
+		return details, nil
+	}
+	if s.tokenFreezer == nil {
+		return nil, errors.NewTransactionError(errors.ErrServiceUnavailable, "token freezing is not configured")
+	}
+
+	note := fmt.Sprintf("fraud score %.2f on transaction %s exceeded the auto-freeze threshold %.2f", score, transaction.ID, threshold)
+	if err := s.tokenFreezer.FreezeTokens(ctx, tokenIDs, note); err != nil {
+		return nil, errors.WrapError(err, errors.ErrServiceUnavailable, "failed to freeze transaction tokens", "transaction-service")
+	}
+
+	recorded := make(map[string]interface{}, len(details)+1)
+	for key, value := range details {
+		recorded[key] = value
+	}
+	recorded["auto_freeze"] = map[string]interface{}{
+		"threshold": threshold,
+		"token_ids": tokenIDs,
+	}
+	return recorded, nil
+}
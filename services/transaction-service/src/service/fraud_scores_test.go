@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+)
+
+func TestTransactionService_SetFraudScoresBulk(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+
+	scores := make(map[uuid.UUID]float64)
+	var ids []uuid.UUID
+	for i, score := range []float64{0.1, 0.5, 0.9} {
+		transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     float64(10 * (i + 1)),
+			Currency:   models.USDCBDC,
+		})
+		require.NoError(t, err)
+		scores[transaction.ID] = score
+		ids = append(ids, transaction.ID)
+	}
+
+	subscriber := service.statusTracker.Subscribe(events.StatusFilter{TransactionIDs: ids})
+	defer service.statusTracker.Unsubscribe(subscriber.ID)
+
+	details := map[string]interface{}{"model": "isolation_forest"}
+	require.NoError(t, service.SetFraudScoresBulk(ctx, scores, details))
+
+	for _, id := range ids {
+		updated, err := service.GetTransaction(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, updated.FraudScore)
+		assert.Equal(t, scores[id], *updated.FraudScore)
+
+		auditTrail := updated.GetAuditTrail()
+		fraudScoreEntry := auditTrail[len(auditTrail)-1]
+		assert.Equal(t, "FRAUD_SCORE_UPDATE", fraudScoreEntry.Action)
+		assert.Equal(t, "fraud-detection", fraudScoreEntry.ServiceID)
+	}
+
+	// One fraud score update is published per transaction
+	published := make(map[uuid.UUID]float64)
+	for range ids {
+		select {
+		case update := <-subscriber.Channel:
+			require.NotNil(t, update.FraudScore)
+			published[update.TransactionID] = *update.FraudScore
+		case <-time.After(time.Second):
+			t.Fatal("Expected a fraud score update for every transaction")
+		}
+	}
+	assert.Equal(t, scores, published)
+}
+
+func TestTransactionService_SetFraudScoresBulk_Validation(t *testing.T) {
+	// Validation fails before the repository is used
+	service := &TransactionService{}
+
+	tooMany := make(map[uuid.UUID]float64)
+	for i := 0; i <= MaxFraudScoreBatchSize; i++ {
+		tooMany[uuid.New()] = 0.5
+	}
+
+	testCases := []struct {
+		name    string
+		scores  map[uuid.UUID]float64
+		message string
+	}{
+		{"no scores", map[uuid.UUID]float64{}, "at least one"},
+		{"too many scores", tooMany, "at most"},
+		{"nil transaction", map[uuid.UUID]float64{uuid.Nil: 0.5}, "cannot be nil"},
+		{"score above one", map[uuid.UUID]float64{uuid.New(): 0.2, uuid.New(): 1.5}, "between 0 and 1"},
+		{"negative score", map[uuid.UUID]float64{uuid.New(): -0.1}, "between 0 and 1"},
+		{"NaN score", map[uuid.UUID]float64{uuid.New(): math.NaN()}, "between 0 and 1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.SetFraudScoresBulk(context.Background(), tc.scores, nil)
+
+			echoPayErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+			assert.Contains(t, echoPayErr.Message, tc.message)
+		})
+	}
+}
+
+func TestTransactionService_SetFraudScore_AutoFreeze(t *testing.T) {
+	testCases := []struct {
+		name       string
+		autoFreeze bool
+		score      float64
+		frozen     bool
+	}{
+		{"below threshold", true, 0.79, false},
+		{"exactly at threshold", true, 0.8, false},
+		{"above threshold", true, 0.81, true},
+		{"auto-freeze disabled", false, 0.99, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, db := setupTestService(t)
+			defer db.Close()
+
+			fromWallet, toWallet := createTestWallets(t, service)
+			ctx := context.Background()
+			tokenIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+			service.SetTokenTransferrer(&fakeTokenTransferrer{
+				owners:  map[uuid.UUID]uuid.UUID{tokenIDs[0]: fromWallet, tokenIDs[1]: fromWallet},
+				failing: map[uuid.UUID]bool{},
+			})
+			freezer := &recordingTokenFreezer{}
+			service.SetTokenFreezer(freezer)
+			if tc.autoFreeze {
+				service.SetAutoFreezeThreshold(0.8)
+			}
+
+			transaction, err := service.SettleTransferWithTokens(ctx, &TokenSettlementRequest{
+				TransactionRequest: TransactionRequest{
+					FromWallet: fromWallet,
+					ToWallet:   toWallet,
+					Amount:     200.0,
+					Currency:   models.USDCBDC,
+				},
+				TokenIDs: tokenIDs,
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, service.SetFraudScore(ctx, transaction.ID, tc.score, map[string]interface{}{"model": "isolation_forest"}))
+
+			updated, err := service.GetTransaction(ctx, transaction.ID)
+			require.NoError(t, err)
+			auditTrail := updated.GetAuditTrail()
+			fraudScoreEntry := auditTrail[len(auditTrail)-1]
+			assert.Equal(t, "FRAUD_SCORE_UPDATE", fraudScoreEntry.Action)
+			assert.Equal(t, "isolation_forest", fraudScoreEntry.Details["model"])
+
+			if !tc.frozen {
+				assert.Empty(t, freezer.frozenTokens)
+				assert.NotContains(t, fraudScoreEntry.Details, "auto_freeze")
+				return
+			}
+
+			assert.Equal(t, tokenIDs, freezer.frozenTokens)
+			require.Len(t, freezer.notes, 1)
+			assert.Contains(t, freezer.notes[0], transaction.ID.String())
+			assert.Contains(t, fraudScoreEntry.Details, "auto_freeze")
+		})
+	}
+}
+
+func TestTransactionService_SetFraudScore_AutoFreezeFails(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	tokenID := uuid.New()
+
+	service.SetTokenTransferrer(&fakeTokenTransferrer{
+		owners:  map[uuid.UUID]uuid.UUID{tokenID: fromWallet},
+		failing: map[uuid.UUID]bool{},
+	})
+	service.SetTokenFreezer(&recordingTokenFreezer{fail: true})
+	service.SetAutoFreezeThreshold(0.8)
+
+	transaction, err := service.SettleTransferWithTokens(ctx, &TokenSettlementRequest{
+		TransactionRequest: TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     100.0,
+			Currency:   models.USDCBDC,
+		},
+		TokenIDs: []uuid.UUID{tokenID},
+	})
+	require.NoError(t, err)
+
+	err = service.SetFraudScore(ctx, transaction.ID, 0.95, nil)
+
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrServiceUnavailable, echoPayErr.Code)
+
+	// The score is not recorded until the tokens are frozen, so the update can be retried
+	updated, err := service.GetTransaction(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Nil(t, updated.FraudScore)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/models"
+)
+
+// DefaultMaxVerifiedAuditEntries is the number of audit entries verified as a transaction is
+// read. It is far above the length of a normal transaction's audit trail.
+const DefaultMaxVerifiedAuditEntries = 10000
+
+// SetMaxVerifiedAuditEntries caps the audit entries verified as a transaction is read. Longer
+// trails are verified in part and flagged for full verification offline. A cap of zero or
+// less restores the default.
+func (s *TransactionService) SetMaxVerifiedAuditEntries(max int) {
+	s.maxAuditEntries = max
+}
+
+// verifyIntegrity verifies a transaction's audit trail as it is read. Trails longer than the
+// cap are verified from their genesis entry and most recent entries only, so one
+// pathologically long trail cannot stall the request, and the transaction is flagged for
+// full verification by VerifyFlaggedTransactions.
+func (s *TransactionService) verifyIntegrity(ctx context.Context, transaction *models.Transaction) error {
+	limit := s.maxAuditEntries
+	if limit <= 0 {
+		limit = DefaultMaxVerifiedAuditEntries
+	}
+
+	if len(transaction.AuditTrail) <= limit {
+		return transaction.VerifyIntegrity()
+	}
+
+	// Flagging is best effort; failing to record it must not fail the read
+	if err := s.repo.FlagForFullVerification(transaction.ID, len(transaction.AuditTrail)); err != nil {
+		logging.WithContext(ctx).Warn("Failed to flag transaction for full audit verification",
+			"transaction_id", transaction.ID,
+			"audit_entries", len(transaction.AuditTrail),
+			"error", err,
+		)
+	}
+
+	window := *transaction
+	window.AuditTrail = auditTrailWindow(transaction.AuditTrail, limit)
+	return window.VerifyIntegrity()
+}
+
+// auditTrailWindow returns the genesis entry of an audit trail followed by its most recent
+// entries, limit entries in all beyond the genesis
+func auditTrailWindow(trail []models.AuditEntry, limit int) []models.AuditEntry {
+	if len(trail) <= limit+1 {
+		return trail
+	}
+
+	window := make([]models.AuditEntry, 0, limit+1)
+	window = append(window, trail[0])
+	return append(window, trail[len(trail)-limit:]...)
+}
+
+// VerifyFlaggedTransactions verifies the full audit trails of up to limit transactions flagged
+// while being read, clearing the flag of each one that verifies. It returns the IDs of the
+// transactions that failed; their flags are kept so they stay visible until investigated.
+func (s *TransactionService) VerifyFlaggedTransactions(ctx context.Context, limit int) (verified int, failed []uuid.UUID, err error) {
+	checks, err := s.repo.GetFlaggedForFullVerification(limit)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, check := range checks {
+		transaction, err := s.repo.GetByID(check.TransactionID)
+		if err != nil {
+			if echoPayErr, ok := err.(*errors.EchoPayError); ok && echoPayErr.Code == errors.ErrTransactionNotFound {
+				// The transaction was removed; there is nothing left to verify
+				if err := s.repo.ClearFullVerificationFlag(check.TransactionID); err != nil {
+					return verified, failed, err
+				}
+				continue
+			}
+			return verified, failed, err
+		}
+
+		if err := transaction.VerifyIntegrity(); err != nil {
+			logging.WithContext(ctx).Error("Transaction failed full audit verification",
+				"transaction_id", transaction.ID,
+				"audit_entries", len(transaction.AuditTrail),
+				"error", err,
+			)
+			failed = append(failed, transaction.ID)
+			continue
+		}
+
+		if err := s.repo.ClearFullVerificationFlag(transaction.ID); err != nil {
+			return verified, failed, err
+		}
+		verified++
+	}
+
+	return verified, failed, nil
+}
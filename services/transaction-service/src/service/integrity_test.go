@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/transaction-service/src/models"
+)
+
+func TestAuditTrailWindow(t *testing.T) {
+	// An oversized synthetic trail is cut to the genesis entry and the most recent entries
+	// without walking it, so verification time does not grow with its length
+	trail := make([]models.AuditEntry, 1000000)
+	for i := range trail {
+		trail[i] = models.AuditEntry{ID: uuid.New(), Action: "FRAUD_SCORE_UPDATE"}
+	}
+	trail[0].Action = "CREATED"
+
+	start := time.Now()
+	window := auditTrailWindow(trail, 100)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	require.Len(t, window, 101)
+	assert.Equal(t, "CREATED", window[0].Action)
+	assert.Equal(t, trail[len(trail)-100].ID, window[1].ID)
+	assert.Equal(t, trail[len(trail)-1].ID, window[100].ID)
+
+	short := trail[:50]
+	assert.Equal(t, short, auditTrailWindow(short, 100))
+}
+
+func TestTransactionService_VerifyIntegrityCap(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	service.SetMaxVerifiedAuditEntries(20)
+	defer service.SetMaxVerifiedAuditEntries(0)
+
+	transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+	require.NoError(t, err)
+	for i := 0; i < 200; i++ {
+		require.NoError(t, transaction.SetFraudScore(float64(i%10)/10, "fraud-detection", nil))
+	}
+	require.NoError(t, service.repo.Create(transaction))
+
+	t.Run("long trails are verified in part and flagged", func(t *testing.T) {
+		retrieved, err := service.GetTransaction(ctx, transaction.ID)
+		require.NoError(t, err)
+		assert.Len(t, retrieved.AuditTrail, len(transaction.AuditTrail))
+
+		checks, err := service.repo.GetFlaggedForFullVerification(1000)
+		require.NoError(t, err)
+		var flagged bool
+		for _, check := range checks {
+			if check.TransactionID == transaction.ID {
+				flagged = true
+				assert.Equal(t, len(transaction.AuditTrail), check.AuditEntries)
+			}
+		}
+		assert.True(t, flagged, "expected the transaction to be flagged for full verification")
+	})
+
+	t.Run("full verification clears the flag", func(t *testing.T) {
+		_, failed, err := service.VerifyFlaggedTransactions(ctx, 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, failed, transaction.ID)
+
+		checks, err := service.repo.GetFlaggedForFullVerification(1000)
+		require.NoError(t, err)
+		for _, check := range checks {
+			assert.NotEqual(t, transaction.ID, check.TransactionID)
+		}
+	})
+
+	t.Run("short trails are not flagged", func(t *testing.T) {
+		fromWallet, toWallet := createTestWallets(t, service)
+		short, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     10.0,
+			Currency:   models.USDCBDC,
+		})
+		require.NoError(t, err)
+
+		_, err = service.GetTransaction(ctx, short.ID)
+		require.NoError(t, err)
+
+		checks, err := service.repo.GetFlaggedForFullVerification(1000)
+		require.NoError(t, err)
+		for _, check := range checks {
+			assert.NotEqual(t, short.ID, check.TransactionID)
+		}
+	})
+}
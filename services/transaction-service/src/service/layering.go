@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+const (
+	// LayeringMinHops is the fewest transfers a chain must have to be reported as layering,
+	// as in A→B→C→external
+	LayeringMinHops = 3
+
+	// LayeringAmountTolerance is how far, as a fraction of the previous amount, each hop of a
+	// chain may differ from the one before it
+	LayeringAmountTolerance = 0.10
+
+	// MaxLayeringWindow bounds how far back layering detection looks
+	MaxLayeringWindow = 7 * 24 * time.Hour
+)
+
+// LayeringAlert describes a chain of near-equal transfers that moved funds through several
+// of a user's wallets before leaving them. Path lists the wallets in the order funds passed
+// through them, ending with the external wallet, and TransactionIDs and Amounts list the
+// transfer for each hop. Fraud scoring raises the score of the terminal transaction, which
+// moved the funds out.
+type LayeringAlert struct {
+	UserID                uuid.UUID       `json:"user_id"`
+	Path                  []uuid.UUID     `json:"path"`
+	TransactionIDs        []uuid.UUID     `json:"transaction_ids"`
+	Amounts               []float64       `json:"amounts"`
+	Currency              models.Currency `json:"currency"`
+	TerminalTransactionID uuid.UUID       `json:"terminal_transaction_id"`
+	StartedAt             time.Time       `json:"started_at"`
+	EndedAt               time.Time       `json:"ended_at"`
+}
+
+// DetectLayering looks for funds layered through a user's wallets within the window ending
+// now: chains of near-equal transfers that pass through at least two of the user's wallets
+// before reaching a wallet the user does not hold. A user with fewer than two wallets cannot
+// layer funds and has no alerts.
+func (s *TransactionService) DetectLayering(ctx context.Context, userID uuid.UUID, window time.Duration) ([]LayeringAlert, error) {
+	if userID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "user ID cannot be nil")
+	}
+	if window <= 0 || window > MaxLayeringWindow {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "layering window must be positive and at most "+MaxLayeringWindow.String())
+	}
+
+	wallets, err := s.walletRepo.GetByAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wallets) < 2 {
+		return []LayeringAlert{}, nil
+	}
+
+	walletIDs := make([]uuid.UUID, len(wallets))
+	for i, wallet := range wallets {
+		walletIDs[i] = wallet.ID
+	}
+
+	transfers, err := s.repo.GetOutgoingFromWallets(walletIDs, s.clock.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := detectLayering(walletIDs, transfers, LayeringMinHops, LayeringAmountTolerance)
+	for i := range alerts {
+		alerts[i].UserID = userID
+	}
+	return alerts, nil
+}
+
+// detectLayering finds chains of transfers that start at one of the own wallets, pass only
+// through own wallets and end at an external one, where each hop follows the previous one in
+// time, moves the same currency and differs in amount from it by at most tolerance. Chains of
+// at least minHops are reported, keeping only the longest chain for each terminal transfer,
+// ordered by when they ended.
+func detectLayering(ownWallets []uuid.UUID, transfers []*models.Transaction, minHops int, tolerance float64) []LayeringAlert {
+	own := make(map[uuid.UUID]bool, len(ownWallets))
+	for _, walletID := range ownWallets {
+		own[walletID] = true
+	}
+
+	outgoing := make(map[uuid.UUID][]*models.Transaction)
+	for _, transfer := range transfers {
+		if own[transfer.FromWallet] {
+			outgoing[transfer.FromWallet] = append(outgoing[transfer.FromWallet], transfer)
+		}
+	}
+
+	longest := make(map[uuid.UUID][]*models.Transaction)
+	var extend func(chain []*models.Transaction)
+	extend = func(chain []*models.Transaction) {
+		last := chain[len(chain)-1]
+		if !own[last.ToWallet] {
+			if len(chain) >= minHops && len(chain) > len(longest[last.ID]) {
+				longest[last.ID] = append([]*models.Transaction(nil), chain...)
+			}
+			return
+		}
+
+		for _, next := range outgoing[last.ToWallet] {
+			if next.CreatedAt.After(last.CreatedAt) && next.Currency == last.Currency &&
+				math.Abs(next.Amount-last.Amount) <= last.Amount*tolerance {
+				extend(append(chain, next))
+			}
+		}
+	}
+
+	for _, transfer := range transfers {
+		if own[transfer.FromWallet] && own[transfer.ToWallet] {
+			extend([]*models.Transaction{transfer})
+		}
+	}
+
+	alerts := make([]LayeringAlert, 0, len(longest))
+	for terminalID, chain := range longest {
+		alert := LayeringAlert{
+			Path:                  []uuid.UUID{chain[0].FromWallet},
+			Currency:              chain[0].Currency,
+			TerminalTransactionID: terminalID,
+			StartedAt:             chain[0].CreatedAt,
+			EndedAt:               chain[len(chain)-1].CreatedAt,
+		}
+		for _, hop := range chain {
+			alert.Path = append(alert.Path, hop.ToWallet)
+			alert.TransactionIDs = append(alert.TransactionIDs, hop.ID)
+			alert.Amounts = append(alert.Amounts, hop.Amount)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].EndedAt.Equal(alerts[j].EndedAt) {
+			return alerts[i].EndedAt.Before(alerts[j].EndedAt)
+		}
+		return alerts[i].TerminalTransactionID.String() < alerts[j].TerminalTransactionID.String()
+	})
+	return alerts
+}
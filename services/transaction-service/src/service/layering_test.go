@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/transaction-service/src/models"
+)
+
+func TestDetectLayering(t *testing.T) {
+	primary, secondary, business, savings := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	external := uuid.New()
+	own := []uuid.UUID{primary, secondary, business, savings}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	transfer := func(from, to uuid.UUID, amount float64, minute int) *models.Transaction {
+		return &models.Transaction{
+			ID:         uuid.New(),
+			FromWallet: from,
+			ToWallet:   to,
+			Amount:     amount,
+			Currency:   models.USDCBDC,
+			Status:     models.StatusCompleted,
+			CreatedAt:  start.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+
+	// Funds move through every wallet a minute apart, shrinking about 5% each hop
+	hops := []*models.Transaction{
+		transfer(primary, secondary, 10000, 0),
+		transfer(secondary, business, 9500, 1),
+		transfer(business, savings, 9000, 2),
+		transfer(savings, external, 8500, 3),
+	}
+
+	t.Run("four hop chain is reported once", func(t *testing.T) {
+		alerts := detectLayering(own, hops, LayeringMinHops, LayeringAmountTolerance)
+		require.Len(t, alerts, 1)
+
+		alert := alerts[0]
+		assert.Equal(t, []uuid.UUID{primary, secondary, business, savings, external}, alert.Path)
+		assert.Equal(t, []float64{10000, 9500, 9000, 8500}, alert.Amounts)
+		assert.Equal(t, []uuid.UUID{hops[0].ID, hops[1].ID, hops[2].ID, hops[3].ID}, alert.TransactionIDs)
+		assert.Equal(t, hops[3].ID, alert.TerminalTransactionID)
+		assert.Equal(t, models.USDCBDC, alert.Currency)
+		assert.Equal(t, hops[0].CreatedAt, alert.StartedAt)
+		assert.Equal(t, hops[3].CreatedAt, alert.EndedAt)
+	})
+
+	t.Run("unrelated transfers are ignored", func(t *testing.T) {
+		transfers := append([]*models.Transaction{
+			transfer(primary, external, 25, 0),
+			transfer(secondary, savings, 120, 2),
+		}, hops...)
+
+		alerts := detectLayering(own, transfers, LayeringMinHops, LayeringAmountTolerance)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, hops[3].ID, alerts[0].TerminalTransactionID)
+	})
+
+	t.Run("short chains are not layering", func(t *testing.T) {
+		alerts := detectLayering(own, hops[2:], LayeringMinHops, LayeringAmountTolerance)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("dissimilar amounts break the chain", func(t *testing.T) {
+		transfers := []*models.Transaction{
+			hops[0],
+			transfer(secondary, business, 4000, 1),
+			transfer(business, savings, 3900, 2),
+			transfer(savings, external, 3800, 3),
+		}
+
+		alerts := detectLayering(own, transfers, LayeringMinHops, LayeringAmountTolerance)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, []uuid.UUID{secondary, business, savings, external}, alerts[0].Path)
+	})
+
+	t.Run("hops must follow each other in time", func(t *testing.T) {
+		transfers := []*models.Transaction{
+			transfer(primary, secondary, 10000, 5),
+			hops[1],
+			hops[2],
+			hops[3],
+		}
+
+		alerts := detectLayering(own, transfers, LayeringMinHops, LayeringAmountTolerance)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, []uuid.UUID{secondary, business, savings, external}, alerts[0].Path)
+	})
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/shared/libraries/monitoring"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// MaxMultiTransferLegs limits the number of legs in a single atomic multi-transfer
+const MaxMultiTransferLegs = 100
+
+// TransferLeg is a single movement of value within an atomic multi-transfer
+type TransferLeg struct {
+	FromWallet uuid.UUID       `json:"from_wallet" binding:"required"`
+	ToWallet   uuid.UUID       `json:"to_wallet" binding:"required"`
+	Amount     float64         `json:"amount" binding:"required,gt=0"`
+	Currency   models.Currency `json:"currency" binding:"required"`
+}
+
+// MultiTransferResult represents the outcome of an atomic multi-transfer
+type MultiTransferResult struct {
+	CorrelationID uuid.UUID             `json:"correlation_id"`
+	Transactions  []*models.Transaction `json:"transactions"`
+}
+
+// balanceKey identifies a wallet balance row
+type balanceKey struct {
+	wallet   uuid.UUID
+	currency models.Currency
+}
+
+// ProcessAtomicMultiTransfer executes all legs in a single database transaction, retried on
+// serialization failures and deadlocks. Balance rows are locked in deterministic wallet-ID
+// order to avoid deadlocks, and if any leg fails the sufficiency check the whole set rolls
+// back. All created transactions share a correlation ID, and each records its own balance
+// changes so it can be resynced or reversed like a single transfer. Legs can spend funds
+// received by earlier legs, so they can't be held for settlement and are rejected in delayed
+// settlement mode.
+func (s *TransactionService) ProcessAtomicMultiTransfer(ctx context.Context, legs []TransferLeg) (*MultiTransferResult, error) {
+	startTime := time.Now()
+	outcome := monitoring.OutcomeFailure
+	defer func() {
+		duration := time.Since(startTime)
+		s.recordProcessingTime(duration)
+		for _, leg := range legs {
+			s.observeTransaction(leg.Currency, outcome, duration)
+		}
+	}()
+
+	if s.settlementMode == SettlementDelayed {
+		s.recordFailure()
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "atomic multi-transfers are not supported in delayed settlement mode")
+	}
+
+	if err := s.validateTransferLegs(legs); err != nil {
+		s.recordFailure()
+		return nil, err
+	}
+
+	correlationID := uuid.New()
+	transactions := make([]*models.Transaction, len(legs))
+	for i, leg := range legs {
+		transaction, err := models.NewTransaction(leg.FromWallet, leg.ToWallet, leg.Amount, leg.Currency, models.TransactionMetadata{})
+		if err != nil {
+			s.recordFailure()
+			return nil, errors.WrapError(err, errors.ErrInvalidTransaction, fmt.Sprintf("failed to create transaction for leg %d", i), "transaction-service")
+		}
+		transactions[i] = transaction
+	}
+
+	// Each attempt starts from the unprocessed transactions
+	originals := make([]models.Transaction, len(transactions))
+	for i, transaction := range transactions {
+		originals[i] = *transaction
+	}
+	var legChanges [][]repository.BalanceChange
+
+	err := database.WithRetry(func() error {
+		for i := range transactions {
+			*transactions[i] = originals[i]
+		}
+		legChanges = make([][]repository.BalanceChange, len(transactions))
+
+		return s.db.Transaction(func(tx *sql.Tx) error {
+			s.balanceMutex.Lock()
+			defer s.balanceMutex.Unlock()
+
+			if err := s.checkWalletsInTx(tx, transactions...); err != nil {
+				return err
+			}
+
+			fees := make([]float64, len(transactions))
+			keys := make(map[balanceKey]bool)
+			for i, transaction := range transactions {
+				keys[balanceKey{transaction.FromWallet, transaction.Currency}] = true
+				keys[balanceKey{transaction.ToWallet, transaction.Currency}] = true
+
+				fees[i] = money.Round(s.calculateFee(transaction), string(transaction.Currency))
+				if fees[i] > 0 {
+					keys[balanceKey{s.feeConfig.CollectionWallet, transaction.Currency}] = true
+				}
+			}
+
+			// Acquire row locks in a deterministic order
+			ordered := make([]balanceKey, 0, len(keys))
+			for key := range keys {
+				ordered = append(ordered, key)
+			}
+			sort.Slice(ordered, func(i, j int) bool {
+				if c := bytes.Compare(ordered[i].wallet[:], ordered[j].wallet[:]); c != 0 {
+					return c < 0
+				}
+				return ordered[i].currency < ordered[j].currency
+			})
+
+			// Balance arithmetic is done in minor units so results match the stored decimal values
+			locked := make(map[balanceKey]*repository.WalletBalance, len(ordered))
+			balances := make(map[balanceKey]int64, len(ordered))
+			for _, key := range ordered {
+				balance, err := s.balanceRepo.GetBalanceForUpdate(tx, key.wallet, key.currency)
+				if err != nil {
+					return errors.WrapError(err, errors.ErrTransactionFailed, "failed to lock wallet balance", "transaction-service")
+				}
+				locked[key] = balance
+				balances[key] = money.ToMinor(balance.Balance, string(key.currency))
+			}
+
+			// Apply legs in order so later legs can spend funds received by earlier ones
+			for i, transaction := range transactions {
+				from := balanceKey{transaction.FromWallet, transaction.Currency}
+				to := balanceKey{transaction.ToWallet, transaction.Currency}
+				currency := string(transaction.Currency)
+
+				// Check the sender's balance as it stands after the earlier legs
+				current := *locked[from]
+				current.Balance = money.FromMinor(balances[from], currency)
+				if err := checkSpendable(&current, transaction.Amount+fees[i]); err != nil {
+					if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+						return errors.NewTransactionError(echoPayErr.Code, fmt.Sprintf("leg %d: %s", i, echoPayErr.Message))
+					}
+					return err
+				}
+
+				move := func(key balanceKey, deltaMinor int64) {
+					oldMinor := balances[key]
+					balances[key] += deltaMinor
+					legChanges[i] = append(legChanges[i], repository.BalanceChange{
+						WalletID:   key.wallet,
+						OldBalance: money.FromMinor(oldMinor, currency),
+						NewBalance: money.FromMinor(balances[key], currency),
+					})
+				}
+				amountMinor := money.ToMinor(transaction.Amount, currency)
+				feeMinor := money.ToMinor(fees[i], currency)
+				move(from, -(amountMinor + feeMinor))
+				move(to, amountMinor)
+				if feeMinor > 0 {
+					move(balanceKey{s.feeConfig.CollectionWallet, transaction.Currency}, feeMinor)
+				}
+			}
+
+			for _, key := range ordered {
+				newBalance := money.FromMinor(balances[key], string(key.currency))
+				if newBalance == locked[key].Balance {
+					continue
+				}
+				if err := s.balanceRepo.UpdateBalance(tx, key.wallet, key.currency, newBalance); err != nil {
+					return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update wallet balance", "transaction-service")
+				}
+			}
+
+			for i, transaction := range transactions {
+				details := map[string]interface{}{
+					"correlation_id": correlationID,
+					"leg":            i,
+					"leg_count":      len(transactions),
+				}
+				if fees[i] > 0 {
+					details["fee"] = fees[i]
+					details["fee_wallet"] = s.feeConfig.CollectionWallet
+				}
+
+				if err := updateTransactionStatus(transaction, models.StatusCompleted, nil, details); err != nil {
+					return err
+				}
+
+				if err := s.repo.CreateInTx(tx, transaction); err != nil {
+					return err
+				}
+
+				if err := s.repo.SetCorrelationIDInTx(tx, transaction.ID, correlationID); err != nil {
+					return err
+				}
+
+				if err := s.recordTransferInTx(tx, transaction, fees[i], legChanges[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}, s.retryPolicy)
+
+	if err != nil {
+		s.recordFailure()
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		logTransactionTransition(ctx, transaction, models.StatusPending, "correlation_id", correlationID.String())
+		s.updateWalletBaseline(ctx, transaction)
+		s.publishTransactionEvent(ctx, transaction, events.EventTransactionCompleted)
+		s.statusTracker.PublishStatusUpdate(transaction, "Transaction completed as part of atomic multi-transfer")
+	}
+	go func() {
+		for i, transaction := range transactions {
+			for _, change := range legChanges[i] {
+				s.publishBalanceUpdateEvent(ctx, change.WalletID, transaction.Currency, change.OldBalance, change.NewBalance, &transaction.ID)
+			}
+		}
+	}()
+
+	outcome = monitoring.OutcomeSuccess
+	s.recordSuccess()
+	for _, transaction := range transactions {
+		s.observeAmount(transaction.Currency, transaction.Amount)
+	}
+	return &MultiTransferResult{
+		CorrelationID: correlationID,
+		Transactions:  transactions,
+	}, nil
+}
+
+// GetTransactionsByCorrelationID retrieves the transactions created by an atomic multi-transfer
+func (s *TransactionService) GetTransactionsByCorrelationID(ctx context.Context, correlationID uuid.UUID) ([]*models.Transaction, error) {
+	ids, err := s.repo.GetIDsByCorrelationID(correlationID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*models.Transaction, 0, len(ids))
+	for _, id := range ids {
+		transaction, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// validateTransferLegs validates every leg of a multi-transfer
+func (s *TransactionService) validateTransferLegs(legs []TransferLeg) error {
+	if len(legs) == 0 {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, "at least one transfer leg is required")
+	}
+
+	if len(legs) > MaxMultiTransferLegs {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("cannot process more than %d legs at once", MaxMultiTransferLegs))
+	}
+
+	for i, leg := range legs {
+		req := &TransactionRequest{
+			FromWallet: leg.FromWallet,
+			ToWallet:   leg.ToWallet,
+			Amount:     leg.Amount,
+			Currency:   leg.Currency,
+		}
+		if err := s.validateTransactionRequest(req); err != nil {
+			if echoPayErr, ok := err.(*errors.EchoPayError); ok {
+				return errors.NewTransactionError(echoPayErr.Code, fmt.Sprintf("leg %d: %s", i, echoPayErr.Message))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// ReceiptSignatureAlgorithm identifies the algorithm used to sign transaction receipts
+const ReceiptSignatureAlgorithm = "ed25519"
+
+// TransactionReceipt is a signed summary of a completed or reversed transaction that a payee
+// can pass on and a third party can check offline with VerifyReceipt
+type TransactionReceipt struct {
+	TransactionID uuid.UUID                `json:"transaction_id"`
+	FromWallet    uuid.UUID                `json:"from_wallet"`
+	ToWallet      uuid.UUID                `json:"to_wallet"`
+	Amount        float64                  `json:"amount"`
+	Currency      models.Currency          `json:"currency"`
+	Status        models.TransactionStatus `json:"status"`
+	CreatedAt     time.Time                `json:"created_at"`
+	StatusAt      time.Time                `json:"status_at"` // When the transaction reached its status
+	IssuedAt      time.Time                `json:"issued_at"`
+	KeyID         string                   `json:"key_id"`
+	Algorithm     string                   `json:"algorithm"`
+	Signature     []byte                   `json:"signature"`
+}
+
+// signingPayload serializes the signed fields of a receipt in a canonical order
+func (r *TransactionReceipt) signingPayload() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
+		r.TransactionID,
+		r.FromWallet,
+		r.ToWallet,
+		strconv.FormatFloat(r.Amount, 'f', -1, 64),
+		r.Currency,
+		r.Status,
+		r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		r.StatusAt.UTC().Format(time.RFC3339Nano),
+		r.IssuedAt.UTC().Format(time.RFC3339Nano),
+		r.KeyID,
+	))
+}
+
+// ReceiptKeys signs receipts with a single active key and holds the public keys of the
+// active and retired keys, so receipts signed before a rotation remain verifiable
+type ReceiptKeys struct {
+	activeKeyID string
+	privateKey  ed25519.PrivateKey
+	publicKeys  map[string]ed25519.PublicKey
+}
+
+// NewReceiptKeys creates receipt keys with the given active signing key
+func NewReceiptKeys(keyID string, privateKey ed25519.PrivateKey) *ReceiptKeys {
+	return &ReceiptKeys{
+		activeKeyID: keyID,
+		privateKey:  privateKey,
+		publicKeys: map[string]ed25519.PublicKey{
+			keyID: privateKey.Public().(ed25519.PublicKey),
+		},
+	}
+}
+
+// NewReceiptKeysFromConfig builds receipt keys from signing configuration. It returns nil
+// when no signing key is configured.
+func NewReceiptKeysFromConfig(cfg config.SigningConfig) (*ReceiptKeys, error) {
+	if cfg.KeyID == "" || cfg.PrivateKey == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt signing key encoding: %w", err)
+	}
+
+	var privateKey ed25519.PrivateKey
+	switch len(raw) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(raw)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(raw)
+	default:
+		return nil, fmt.Errorf("invalid receipt signing key length: %d", len(raw))
+	}
+
+	keys := NewReceiptKeys(cfg.KeyID, privateKey)
+	for keyID, encoded := range cfg.VerificationKeys {
+		publicKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid receipt verification key %s", keyID)
+		}
+		keys.AddVerificationKey(keyID, ed25519.PublicKey(publicKey))
+	}
+
+	return keys, nil
+}
+
+// AddVerificationKey registers a retired public key so older receipts remain verifiable
+func (k *ReceiptKeys) AddVerificationKey(keyID string, publicKey ed25519.PublicKey) {
+	k.publicKeys[keyID] = publicKey
+}
+
+// PublicKey returns the public key registered for a key ID
+func (k *ReceiptKeys) PublicKey(keyID string) (ed25519.PublicKey, error) {
+	publicKey, ok := k.publicKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown receipt signing key: %s", keyID)
+	}
+	return publicKey, nil
+}
+
+// PublicKeys returns every registered public key by key ID, for publishing to third parties
+func (k *ReceiptKeys) PublicKeys() map[string]ed25519.PublicKey {
+	keys := make(map[string]ed25519.PublicKey, len(k.publicKeys))
+	for keyID, publicKey := range k.publicKeys {
+		keys[keyID] = publicKey
+	}
+	return keys
+}
+
+// Sign signs a receipt with the active key
+func (k *ReceiptKeys) Sign(receipt *TransactionReceipt) {
+	receipt.KeyID = k.activeKeyID
+	receipt.Algorithm = ReceiptSignatureAlgorithm
+	receipt.Signature = ed25519.Sign(k.privateKey, receipt.signingPayload())
+}
+
+// VerifyReceipt checks a receipt's signature against the public key of the key that signed
+// it. It needs no access to the service, so third parties can verify receipts offline.
+func VerifyReceipt(receipt *TransactionReceipt, publicKey ed25519.PublicKey) error {
+	if receipt.Algorithm != ReceiptSignatureAlgorithm {
+		return fmt.Errorf("unsupported receipt signature algorithm: %s", receipt.Algorithm)
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid receipt verification key length: %d", len(publicKey))
+	}
+
+	if !ed25519.Verify(publicKey, receipt.signingPayload(), receipt.Signature) {
+		return fmt.Errorf("receipt signature is invalid")
+	}
+
+	return nil
+}
+
+// SetReceiptKeys sets the keys transaction receipts are signed with
+func (s *TransactionService) SetReceiptKeys(keys *ReceiptKeys) {
+	s.receiptKeys = keys
+}
+
+// ReceiptPublicKeys returns the public keys receipts can be verified with, by key ID
+func (s *TransactionService) ReceiptPublicKeys() (map[string]ed25519.PublicKey, error) {
+	if s.receiptKeys == nil {
+		return nil, errors.NewTransactionError(errors.ErrServiceUnavailable, "receipt signing is not configured")
+	}
+	return s.receiptKeys.PublicKeys(), nil
+}
+
+// GenerateReceipt returns a signed receipt for a completed or reversed transaction
+func (s *TransactionService) GenerateReceipt(ctx context.Context, id uuid.UUID) (*TransactionReceipt, error) {
+	if s.receiptKeys == nil {
+		return nil, errors.NewTransactionError(errors.ErrServiceUnavailable, "receipt signing is not configured")
+	}
+
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != models.StatusCompleted && transaction.Status != models.StatusReversed {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only completed or reversed transactions have receipts", transaction.Status))
+	}
+
+	receipt := newTransactionReceipt(transaction, s.clock.Now())
+	s.receiptKeys.Sign(receipt)
+	return receipt, nil
+}
+
+// newTransactionReceipt builds the unsigned receipt of a transaction. The status time is
+// taken from the latest audit entry that moved the transaction to its current status.
+func newTransactionReceipt(transaction *models.Transaction, issuedAt time.Time) *TransactionReceipt {
+	statusAt := transaction.CreatedAt
+	for _, entry := range transaction.AuditTrail {
+		if entry.NewState == string(transaction.Status) && entry.Timestamp.After(statusAt) {
+			statusAt = entry.Timestamp
+		}
+	}
+
+	return &TransactionReceipt{
+		TransactionID: transaction.ID,
+		FromWallet:    transaction.FromWallet,
+		ToWallet:      transaction.ToWallet,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Status:        transaction.Status,
+		CreatedAt:     transaction.CreatedAt.UTC(),
+		StatusAt:      statusAt.UTC(),
+		IssuedAt:      issuedAt.UTC(),
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/transaction-service/src/models"
+)
+
+func newTestReceiptKeys(t *testing.T, keyID string) *ReceiptKeys {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return NewReceiptKeys(keyID, privateKey)
+}
+
+func TestVerifyReceipt(t *testing.T) {
+	keys := newTestReceiptKeys(t, "receipts-1")
+	publicKey, err := keys.PublicKey("receipts-1")
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	transaction := &models.Transaction{
+		ID:         uuid.New(),
+		FromWallet: uuid.New(),
+		ToWallet:   uuid.New(),
+		Amount:     125.5,
+		Currency:   models.USDCBDC,
+		Status:     models.StatusCompleted,
+		CreatedAt:  createdAt,
+		AuditTrail: []models.AuditEntry{
+			{NewState: string(models.StatusPending), Timestamp: createdAt},
+			{NewState: string(models.StatusCompleted), Timestamp: createdAt.Add(2 * time.Second)},
+		},
+	}
+
+	receipt := newTransactionReceipt(transaction, createdAt.Add(time.Hour))
+	keys.Sign(receipt)
+	assert.Equal(t, createdAt.Add(2*time.Second), receipt.StatusAt)
+	assert.NoError(t, VerifyReceipt(receipt, publicKey))
+
+	t.Run("survives a JSON round trip", func(t *testing.T) {
+		encoded, err := json.Marshal(receipt)
+		require.NoError(t, err)
+
+		var decoded TransactionReceipt
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.NoError(t, VerifyReceipt(&decoded, publicKey))
+	})
+
+	t.Run("tampering invalidates the signature", func(t *testing.T) {
+		tamperings := map[string]func(*TransactionReceipt){
+			"amount":    func(r *TransactionReceipt) { r.Amount = 1255 },
+			"payee":     func(r *TransactionReceipt) { r.ToWallet = uuid.New() },
+			"status":    func(r *TransactionReceipt) { r.Status = models.StatusReversed },
+			"timestamp": func(r *TransactionReceipt) { r.StatusAt = r.StatusAt.Add(time.Minute) },
+			"key":       func(r *TransactionReceipt) { r.KeyID = "receipts-2" },
+		}
+
+		for name, tamper := range tamperings {
+			tampered := *receipt
+			tamper(&tampered)
+			assert.Error(t, VerifyReceipt(&tampered, publicKey), name)
+		}
+	})
+
+	t.Run("another key does not verify", func(t *testing.T) {
+		otherKey, err := newTestReceiptKeys(t, "receipts-1").PublicKey("receipts-1")
+		require.NoError(t, err)
+		assert.Error(t, VerifyReceipt(receipt, otherKey))
+	})
+}
+
+func TestTransactionService_GenerateReceipt(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	fromWallet, toWallet := createTestWallets(t, service)
+
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     42.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+
+	// Receipts are unavailable until a signing key is configured
+	_, err = service.GenerateReceipt(ctx, transaction.ID)
+	assert.Error(t, err)
+
+	keys := newTestReceiptKeys(t, "receipts-1")
+	service.SetReceiptKeys(keys)
+
+	receipt, err := service.GenerateReceipt(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID, receipt.TransactionID)
+	assert.Equal(t, toWallet, receipt.ToWallet)
+	assert.Equal(t, 42.0, receipt.Amount)
+	assert.Equal(t, models.StatusCompleted, receipt.Status)
+	assert.Equal(t, "receipts-1", receipt.KeyID)
+
+	publicKeys, err := service.ReceiptPublicKeys()
+	require.NoError(t, err)
+	assert.NoError(t, VerifyReceipt(receipt, publicKeys[receipt.KeyID]))
+
+	receipt.Amount = 4200
+	assert.Error(t, VerifyReceipt(receipt, publicKeys[receipt.KeyID]))
+
+	// Pending transfers have no receipt yet
+	service.SetSettlementMode(SettlementDelayed)
+	defer service.SetSettlementMode(SettlementInstant)
+	pending, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     10.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.StatusPending, pending.Status)
+
+	_, err = service.GenerateReceipt(ctx, pending.ID)
+	assert.Error(t, err)
+
+	_, err = service.GenerateReceipt(ctx, uuid.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// MaxRefundReasonLength caps the reason recorded with a refund
+const MaxRefundReasonLength = 500
+
+// RefundResult is a refund and the refund totals of the transaction it refunds
+type RefundResult struct {
+	Refund         *models.Transaction `json:"refund"`
+	OriginalID     uuid.UUID           `json:"original_transaction_id"`
+	RefundedAmount float64             `json:"refunded_amount"`  // Total refunded so far, including this refund
+	Remaining      float64             `json:"remaining_amount"` // Amount that can still be refunded
+}
+
+// PartialRefund returns part of a completed transaction's amount from its recipient to its
+// sender as a new transaction linked to the original. Refunds of a transaction can never
+// add up to more than its amount. Refunds are not charged a fee.
+func (s *TransactionService) PartialRefund(ctx context.Context, originalID uuid.UUID, amount float64, reason string) (*RefundResult, error) {
+	reason = sanitizeMetadataText(strings.TrimSpace(reason))
+	if reason == "" {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "a reason is required to refund a transaction")
+	}
+	if utf8.RuneCountInString(reason) > MaxRefundReasonLength {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("refund reason cannot be longer than %d characters", MaxRefundReasonLength))
+	}
+	if amount <= 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "refund amount must be positive")
+	}
+
+	original, err := s.repo.GetByID(originalID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := string(original.Currency)
+	if !money.IsExact(amount, currency) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("refund amount has more than %d decimal places", money.Decimals(currency)))
+	}
+
+	refund, err := models.NewTransaction(
+		original.ToWallet,
+		original.FromWallet,
+		amount,
+		original.Currency,
+		models.TransactionMetadata{Description: fmt.Sprintf("Refund of transaction %s", original.ID)},
+	)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrInvalidTransaction, "failed to create refund transaction", "transaction-service")
+	}
+
+	// Each attempt starts from the unprocessed refund
+	unprocessed := *refund
+	var changes []repository.BalanceChange
+	var refundedMinor int64
+	err = database.WithRetry(func() error {
+		*refund = unprocessed
+		return s.db.Transaction(func(tx *sql.Tx) error {
+			status, refunded, err := s.repo.GetRefundStateForUpdateInTx(tx, original.ID)
+			if err != nil {
+				return err
+			}
+			if status != models.StatusCompleted {
+				return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only completed transactions can be refunded", status))
+			}
+
+			// Compared in minor units so refunds can add up to exactly the original amount
+			remainingMinor := money.ToMinor(original.Amount, currency) - money.ToMinor(refunded, currency)
+			amountMinor := money.ToMinor(amount, currency)
+			if amountMinor > remainingMinor {
+				return errors.NewTransactionError(
+					errors.ErrInvalidTransaction,
+					fmt.Sprintf("refund of %s exceeds the refundable amount of %s", money.Format(amount, currency), money.Format(money.FromMinor(remainingMinor, currency), currency)),
+				)
+			}
+
+			changes, err = s.applyRefundInTx(tx, refund)
+			if err != nil {
+				return err
+			}
+			if err := s.repo.RecordRefundInTx(tx, refund.ID, original.ID, amount, reason); err != nil {
+				return err
+			}
+
+			refundedMinor = money.ToMinor(refunded, currency) + amountMinor
+			return nil
+		})
+	}, s.retryPolicy)
+	if err != nil {
+		s.recordFailure()
+		return nil, err
+	}
+
+	go func() {
+		for _, change := range changes {
+			s.publishBalanceUpdateEvent(ctx, change.WalletID, refund.Currency, change.OldBalance, change.NewBalance, &refund.ID)
+		}
+	}()
+
+	logTransactionTransition(ctx, refund, models.StatusPending, "refund_of", original.ID.String(), "reason", reason)
+	s.publishTransactionEvent(ctx, refund, events.EventTransactionCompleted)
+	s.statusTracker.PublishStatusUpdate(refund, "Refund completed")
+	s.recordSuccess()
+
+	return &RefundResult{
+		Refund:         refund,
+		OriginalID:     original.ID,
+		RefundedAmount: money.FromMinor(refundedMinor, currency),
+		Remaining:      money.FromMinor(money.ToMinor(original.Amount, currency)-refundedMinor, currency),
+	}, nil
+}
+
+// applyRefundInTx moves a refund's funds back to the original sender and records it. The
+// original recipient must still have the funds available.
+func (s *TransactionService) applyRefundInTx(tx *sql.Tx, refund *models.Transaction) ([]repository.BalanceChange, error) {
+	s.balanceMutex.Lock()
+	defer s.balanceMutex.Unlock()
+
+	if err := s.checkWalletsInTx(tx, refund); err != nil {
+		return nil, err
+	}
+
+	fromBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, refund.FromWallet, refund.Currency)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get refunding wallet balance", "transaction-service")
+	}
+	if err := checkSpendable(fromBalance, refund.Amount); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.transferFundsInTx(tx, refund, fromBalance, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateInTx(tx, refund); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransferInTx(tx, refund, 0, changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
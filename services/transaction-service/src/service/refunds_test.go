@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+func TestTransactionService_PartialRefund_Validation(t *testing.T) {
+	service := &TransactionService{}
+	ctx := context.Background()
+
+	invalid := map[string]struct {
+		amount float64
+		reason string
+	}{
+		"missing reason":  {10.0, "  "},
+		"long reason":     {10.0, strings.Repeat("x", MaxRefundReasonLength+1)},
+		"zero amount":     {0, "Damaged goods"},
+		"negative amount": {-5.0, "Damaged goods"},
+	}
+
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.PartialRefund(ctx, uuid.New(), tc.amount, tc.reason)
+			require.Error(t, err)
+			txErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrInvalidTransaction, txErr.Code)
+		})
+	}
+}
+
+func TestTransactionService_PartialRefund(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	fromWallet, toWallet := createTestWallets(t, service)
+	original, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+
+	t.Run("refunds part of the amount to the sender", func(t *testing.T) {
+		result, err := service.PartialRefund(ctx, original.ID, 30.0, "Item returned")
+		require.NoError(t, err)
+
+		assert.Equal(t, toWallet, result.Refund.FromWallet)
+		assert.Equal(t, fromWallet, result.Refund.ToWallet)
+		assert.Equal(t, 30.0, result.Refund.Amount)
+		assert.Equal(t, models.StatusCompleted, result.Refund.Status)
+		assert.Equal(t, original.ID, result.OriginalID)
+		assert.Equal(t, 30.0, result.RefundedAmount)
+		assert.Equal(t, 70.0, result.Remaining)
+
+		fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+		require.NoError(t, err)
+		assert.Equal(t, 930.0, fromBalance.Balance)
+		toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+		require.NoError(t, err)
+		assert.Equal(t, 70.0, toBalance.Balance)
+
+		// The refund is linked to the original in its metadata
+		var refundOf string
+		err = db.QueryRow(`SELECT metadata->>'refund_of' FROM transactions WHERE id = $1`, result.Refund.ID).Scan(&refundOf)
+		require.NoError(t, err)
+		assert.Equal(t, original.ID.String(), refundOf)
+	})
+
+	t.Run("rejects refunding more than remains", func(t *testing.T) {
+		_, err := service.PartialRefund(ctx, original.ID, 70.01, "Item returned")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds the refundable amount")
+
+		toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+		require.NoError(t, err)
+		assert.Equal(t, 70.0, toBalance.Balance)
+	})
+
+	t.Run("partial refunds can add up to the full amount", func(t *testing.T) {
+		result, err := service.PartialRefund(ctx, original.ID, 45.5, "Second item returned")
+		require.NoError(t, err)
+		assert.Equal(t, 24.5, result.Remaining)
+
+		result, err = service.PartialRefund(ctx, original.ID, 24.5, "Remaining items returned")
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, result.RefundedAmount)
+		assert.Equal(t, 0.0, result.Remaining)
+
+		fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+		require.NoError(t, err)
+		assert.Equal(t, 1000.0, fromBalance.Balance)
+
+		_, err = service.PartialRefund(ctx, original.ID, 0.01, "Nothing left")
+		assert.Error(t, err)
+	})
+
+	t.Run("only completed transactions can be refunded", func(t *testing.T) {
+		service.SetSettlementMode(SettlementDelayed)
+		defer service.SetSettlementMode(SettlementInstant)
+
+		pending, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     50.0,
+			Currency:   models.USDCBDC,
+		})
+		require.NoError(t, err)
+		require.Equal(t, models.StatusPending, pending.Status)
+
+		_, err = service.PartialRefund(ctx, pending.ID, 10.0, "Not settled yet")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only completed transactions can be refunded")
+
+		_, err = service.PartialRefund(ctx, uuid.New(), 10.0, "Unknown transaction")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/models"
+)
+
+// RiskAlert describes a transaction whose fraud score exceeded the critical alert threshold
+type RiskAlert struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	FromWallet    uuid.UUID       `json:"from_wallet"`
+	ToWallet      uuid.UUID       `json:"to_wallet"`
+	Amount        float64         `json:"amount"`
+	Currency      models.Currency `json:"currency"`
+	Score         float64         `json:"score"`
+	Threshold     float64         `json:"threshold"`
+	Reasons       []string        `json:"reasons,omitempty"` // Risk factors reported with the score
+	RaisedAt      time.Time       `json:"raised_at"`
+}
+
+// Alerter notifies operations of high-risk transactions, for example through a queue or email
+type Alerter interface {
+	Alert(ctx context.Context, alert RiskAlert) error
+}
+
+// LoggingAlerter reports high-risk transactions as warnings in the service log
+type LoggingAlerter struct{}
+
+// Alert logs the alert
+func (LoggingAlerter) Alert(ctx context.Context, alert RiskAlert) error {
+	logging.WithContext(ctx).Warn("High-risk transaction",
+		"transaction_id", alert.TransactionID,
+		"from_wallet", alert.FromWallet,
+		"to_wallet", alert.ToWallet,
+		"amount", alert.Amount,
+		"currency", alert.Currency,
+		"score", alert.Score,
+		"threshold", alert.Threshold,
+		"reasons", alert.Reasons,
+	)
+	return nil
+}
+
+// ChannelAlerter delivers alerts on a channel, for consumers within the process and tests
+type ChannelAlerter struct {
+	Alerts chan RiskAlert
+}
+
+// NewChannelAlerter creates an alerter whose channel buffers up to size alerts
+func NewChannelAlerter(size int) *ChannelAlerter {
+	return &ChannelAlerter{Alerts: make(chan RiskAlert, size)}
+}
+
+// Alert sends the alert on the channel, waiting for room until ctx is done
+func (a *ChannelAlerter) Alert(ctx context.Context, alert RiskAlert) error {
+	select {
+	case a.Alerts <- alert:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetRiskAlerter sets the alerter notified when a transaction's fraud score is set above the
+// critical threshold
+func (s *TransactionService) SetRiskAlerter(alerter Alerter, threshold float64) {
+	s.alerter = alerter
+	s.criticalScore = threshold
+}
+
+// alertHighRisk raises an alert for a transaction whose new fraud score exceeds the critical
+// threshold. The score has already been recorded, so a failed alert is logged rather than
+// returned.
+func (s *TransactionService) alertHighRisk(ctx context.Context, transaction *models.Transaction, score float64, details map[string]interface{}) {
+	if s.alerter == nil || score <= s.criticalScore {
+		return
+	}
+
+	alert := RiskAlert{
+		TransactionID: transaction.ID,
+		FromWallet:    transaction.FromWallet,
+		ToWallet:      transaction.ToWallet,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Score:         score,
+		Threshold:     s.criticalScore,
+		Reasons:       riskReasons(details),
+		RaisedAt:      s.clock.Now().UTC(),
+	}
+	if err := s.alerter.Alert(ctx, alert); err != nil {
+		logging.WithContext(ctx).Error("Failed to raise high-risk transaction alert", "transaction_id", transaction.ID, "error", err.Error())
+	}
+}
+
+// riskReasons collects the risk factors fraud detection reported with a score, and notes an
+// automatic token freeze
+func riskReasons(details map[string]interface{}) []string {
+	var reasons []string
+	switch factors := details["risk_factors"].(type) {
+	case []string:
+		reasons = append(reasons, factors...)
+	case []interface{}:
+		for _, factor := range factors {
+			reasons = append(reasons, fmt.Sprint(factor))
+		}
+	}
+	if _, frozen := details["auto_freeze"]; frozen {
+		reasons = append(reasons, "tokens auto-frozen")
+	}
+	return reasons
+}
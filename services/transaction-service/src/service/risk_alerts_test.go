@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/clock"
+	"echopay/transaction-service/src/models"
+)
+
+func TestTransactionService_AlertHighRisk_OnlyAboveCriticalThreshold(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	alerter := NewChannelAlerter(10)
+	service := &TransactionService{clock: clock.NewFake(now)}
+	service.SetRiskAlerter(alerter, 0.9)
+
+	transaction := &models.Transaction{
+		ID:         uuid.New(),
+		FromWallet: uuid.New(),
+		ToWallet:   uuid.New(),
+		Amount:     2500.0,
+		Currency:   models.USDCBDC,
+	}
+	ctx := context.Background()
+
+	service.alertHighRisk(ctx, transaction, 0.5, nil)
+	service.alertHighRisk(ctx, transaction, 0.9, nil)
+	assert.Empty(t, alerter.Alerts, "Expected no alert at or below the critical threshold")
+
+	details := map[string]interface{}{
+		"risk_factors": []interface{}{"velocity", "new_recipient"},
+		"auto_freeze":  map[string]interface{}{"threshold": 0.8},
+	}
+	service.alertHighRisk(ctx, transaction, 0.97, details)
+
+	require.Len(t, alerter.Alerts, 1)
+	assert.Equal(t, RiskAlert{
+		TransactionID: transaction.ID,
+		FromWallet:    transaction.FromWallet,
+		ToWallet:      transaction.ToWallet,
+		Amount:        2500.0,
+		Currency:      models.USDCBDC,
+		Score:         0.97,
+		Threshold:     0.9,
+		Reasons:       []string{"velocity", "new_recipient", "tokens auto-frozen"},
+		RaisedAt:      now,
+	}, <-alerter.Alerts)
+}
+
+func TestTransactionService_SetFraudScoresBulk_AlertsCriticalScores(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	alerter := NewChannelAlerter(10)
+	service.SetRiskAlerter(alerter, 0.9)
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+
+	scores := make(map[uuid.UUID]float64)
+	var critical uuid.UUID
+	for _, score := range []float64{0.2, 0.9, 0.99} {
+		transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     25.0,
+			Currency:   models.USDCBDC,
+		})
+		require.NoError(t, err)
+		scores[transaction.ID] = score
+		if score > 0.9 {
+			critical = transaction.ID
+		}
+	}
+
+	require.NoError(t, service.SetFraudScoresBulk(ctx, scores, map[string]interface{}{"risk_factors": []string{"amount_spike"}}))
+
+	require.Len(t, alerter.Alerts, 1)
+	alert := <-alerter.Alerts
+	assert.Equal(t, critical, alert.TransactionID)
+	assert.Equal(t, 0.99, alert.Score)
+	assert.Equal(t, []string{"amount_spike"}, alert.Reasons)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// SettlementMode controls when a transfer's funds move between wallets
+type SettlementMode string
+
+const (
+	// SettlementInstant moves funds and completes transfers as they are processed
+	SettlementInstant SettlementMode = "instant"
+	// SettlementDelayed holds the sender's funds and leaves transfers pending until they are
+	// settled, so flows that depend on non-instant settlement can be exercised
+	SettlementDelayed SettlementMode = "delayed"
+)
+
+// ParseSettlementMode parses a settlement mode name
+func ParseSettlementMode(mode string) (SettlementMode, error) {
+	switch SettlementMode(mode) {
+	case SettlementInstant, SettlementDelayed:
+		return SettlementMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown settlement mode %q, expected %q or %q", mode, SettlementInstant, SettlementDelayed)
+	}
+}
+
+// SetSettlementMode sets when transfers settle. Token-backed and multi-recipient transfers
+// always settle instantly, and atomic multi-transfers are rejected in delayed mode.
+func (s *TransactionService) SetSettlementMode(mode SettlementMode) {
+	s.settlementMode = mode
+}
+
+// holdTransactionInTx reserves the amount and fee of a transfer on the sender's balance and
+// records the transaction as pending, leaving the funds to be moved by Settle
+func (s *TransactionService) holdTransactionInTx(tx *sql.Tx, transaction *models.Transaction) error {
+	s.balanceMutex.Lock()
+	defer s.balanceMutex.Unlock()
+
+	if err := s.checkWalletsInTx(tx, transaction); err != nil {
+		return err
+	}
+
+	fromBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.FromWallet, transaction.Currency)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get sender balance", "transaction-service")
+	}
+
+	currency := string(transaction.Currency)
+	fee := money.Round(s.calculateFee(transaction), currency)
+	if err := checkSpendable(fromBalance, transaction.Amount+fee); err != nil {
+		return err
+	}
+
+	newHeld := money.FromMinor(money.ToMinor(fromBalance.Held, currency)+money.ToMinor(transaction.Amount+fee, currency), currency)
+	if err := s.balanceRepo.UpdateHeld(tx, transaction.FromWallet, transaction.Currency, newHeld); err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateInTx(tx, transaction); err != nil {
+		return err
+	}
+
+	return s.balanceRepo.CreateHoldInTx(tx, &repository.BalanceHold{
+		TransactionID: transaction.ID,
+		WalletID:      transaction.FromWallet,
+		Currency:      transaction.Currency,
+		Amount:        transaction.Amount,
+		Fee:           fee,
+		CreatedAt:     s.clock.Now(),
+	})
+}
+
+// releaseHoldInTx returns the funds held for a transaction to the sender's available
+// balance. It does nothing if the transaction has no hold.
+func (s *TransactionService) releaseHoldInTx(tx *sql.Tx, transactionID uuid.UUID) error {
+	hold, err := s.balanceRepo.GetHoldForUpdateInTx(tx, transactionID)
+	if err != nil || hold == nil {
+		return err
+	}
+
+	balance, err := s.balanceRepo.GetBalanceForUpdate(tx, hold.WalletID, hold.Currency)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get sender balance", "transaction-service")
+	}
+
+	currency := string(hold.Currency)
+	newHeld := money.FromMinor(money.ToMinor(balance.Held, currency)-money.ToMinor(hold.Amount+hold.Fee, currency), currency)
+	if err := s.balanceRepo.UpdateHeld(tx, hold.WalletID, hold.Currency, newHeld); err != nil {
+		return err
+	}
+
+	return s.balanceRepo.DeleteHoldInTx(tx, transactionID)
+}
+
+// Settle moves the funds held for a pending transfer to the recipient and completes it.
+// Transfers processed in instant settlement mode have nothing to settle.
+func (s *TransactionService) Settle(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != models.StatusPending {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only pending transactions can be settled", transaction.Status))
+	}
+
+	// Each attempt starts from the unsettled transaction
+	original := *transaction
+	var changes []repository.BalanceChange
+
+	err = database.WithRetry(func() error {
+		*transaction = original
+		return s.db.Transaction(func(tx *sql.Tx) error {
+			s.balanceMutex.Lock()
+			defer s.balanceMutex.Unlock()
+
+			// Locking the hold stops the transfer being settled or force-failed twice
+			hold, err := s.balanceRepo.GetHoldForUpdateInTx(tx, transaction.ID)
+			if err != nil {
+				return err
+			}
+			if hold == nil {
+				return errors.NewTransactionError(errors.ErrInvalidTransaction, "transaction has no funds held for settlement")
+			}
+
+			if err := s.checkWalletsInTx(tx, transaction); err != nil {
+				return err
+			}
+
+			fromBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.FromWallet, transaction.Currency)
+			if err != nil {
+				return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get sender balance", "transaction-service")
+			}
+
+			changes, err = s.transferFundsInTx(tx, transaction, fromBalance, hold.Fee, true)
+			if err != nil {
+				return err
+			}
+
+			if err := s.repo.UpdateInTx(tx, transaction); err != nil {
+				return err
+			}
+
+			if err := s.balanceRepo.DeleteHoldInTx(tx, transaction.ID); err != nil {
+				return err
+			}
+
+			return s.recordTransferInTx(tx, transaction, hold.Fee, changes)
+		})
+	}, s.retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	logTransactionTransition(ctx, transaction, models.StatusPending, "amount", transaction.Amount, "currency", transaction.Currency, "settled", true)
+
+	go func() {
+		for _, change := range changes {
+			s.publishBalanceUpdateEvent(ctx, change.WalletID, transaction.Currency, change.OldBalance, change.NewBalance, &transaction.ID)
+		}
+	}()
+
+	s.updateWalletBaseline(ctx, transaction)
+	s.publishTransactionEvent(ctx, transaction, events.EventTransactionCompleted)
+	s.statusTracker.PublishStatusUpdate(transaction, "Transaction settled")
+	s.observeAmount(transaction.Currency, transaction.Amount)
+
+	return transaction, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+func TestParseSettlementMode(t *testing.T) {
+	mode, err := ParseSettlementMode("instant")
+	require.NoError(t, err)
+	assert.Equal(t, SettlementInstant, mode)
+
+	mode, err = ParseSettlementMode("delayed")
+	require.NoError(t, err)
+	assert.Equal(t, SettlementDelayed, mode)
+
+	_, err = ParseSettlementMode("")
+	assert.Error(t, err)
+	_, err = ParseSettlementMode("eventual")
+	assert.Error(t, err)
+}
+
+func TestTransactionService_DelayedSettlement(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	feeWallet := uuid.New()
+	require.NoError(t, service.balanceRepo.CreateWallet(feeWallet))
+
+	service.SetFeeConfig(FeeConfig{
+		Enabled:          true,
+		CollectionWallet: feeWallet,
+		Calculator:       NewTieredFeeCalculator(0.50, 0.01, 10.0),
+	})
+	service.SetSettlementMode(SettlementDelayed)
+
+	ctx := context.Background()
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     600.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, transaction.Status)
+
+	// The amount and fee (0.50 flat + 1% of 600 = 6.50) are held, not moved
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+	assert.Equal(t, 606.50, fromBalance.Held)
+
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, toBalance.Balance)
+
+	// Held funds can't be spent again
+	_, err = service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     500.0,
+		Currency:   models.USDCBDC,
+	})
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInsufficientFunds, transactionErr.Code)
+
+	// Nor can the held transfer be completed without moving its funds
+	err = service.UpdateTransactionStatus(ctx, transaction.ID, models.StatusCompleted, nil, nil)
+	transactionErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+
+	settled, err := service.Settle(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, settled.Status)
+
+	fromBalance, err = service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0-606.50, fromBalance.Balance)
+	assert.Equal(t, 0.0, fromBalance.Held)
+
+	toBalance, err = service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 600.0, toBalance.Balance)
+
+	feeBalance, err := service.GetWalletBalance(ctx, feeWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 6.50, feeBalance.Balance)
+
+	fee, err := service.GetTransactionFee(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 6.50, fee.Amount)
+
+	saved, err := service.GetTransaction(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, saved.Status)
+
+	// A settled transfer can't be settled again
+	_, err = service.Settle(ctx, transaction.ID)
+	transactionErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+
+	// Instant mode moves funds as the transfer is processed
+	service.SetSettlementMode(SettlementInstant)
+	instant, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, instant.Status)
+
+	_, err = service.Settle(ctx, instant.ID)
+	assert.Error(t, err)
+}
+
+func TestTransactionService_DelayedSettlement_RejectsMultiTransfer(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	service.SetSettlementMode(SettlementDelayed)
+
+	ctx := context.Background()
+	_, err := service.ProcessAtomicMultiTransfer(ctx, []TransferLeg{
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 100.0, Currency: models.USDCBDC},
+	})
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+
+	// Nothing is moved or held
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+	assert.Equal(t, 0.0, fromBalance.Held)
+}
+
+func TestTransactionService_DelayedSettlement_ForceFailReleasesHold(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	service.SetSettlementMode(SettlementDelayed)
+
+	ctx := context.Background()
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     300.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+
+	_, err = service.ForceFailTransaction(ctx, transaction.ID, "settlement abandoned", nil)
+	require.NoError(t, err)
+
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+	assert.Equal(t, 0.0, fromBalance.Held)
+
+	// The released funds are not moved by a later settlement
+	_, err = service.Settle(ctx, transaction.ID)
+	assert.Error(t, err)
+
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, toBalance.Balance)
+}
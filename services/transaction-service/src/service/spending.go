@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/repository"
+)
+
+// SpendingSummary is a wallet's completed outgoing spending over a period, by category
+type SpendingSummary struct {
+	WalletID   uuid.UUID                     `json:"wallet_id"`
+	From       time.Time                     `json:"from"`
+	To         time.Time                     `json:"to"`
+	Categories []repository.CategorySpending `json:"categories"`
+}
+
+// SetAllowedCategories restricts the categories transactions may be recorded with. An empty
+// list accepts any category.
+func (s *TransactionService) SetAllowedCategories(categories []string) {
+	if len(categories) == 0 {
+		s.allowedCategories = nil
+		return
+	}
+
+	s.allowedCategories = make(map[string]bool, len(categories))
+	for _, category := range categories {
+		s.allowedCategories[category] = true
+	}
+}
+
+// categoryAllowed reports whether a transaction may be recorded with the given category.
+// Uncategorized transactions are always allowed.
+func (s *TransactionService) categoryAllowed(category string) bool {
+	return category == "" || s.allowedCategories == nil || s.allowedCategories[category]
+}
+
+// GetSpendingByCategory summarizes a wallet's completed outgoing transactions created at or
+// after from and before to, by category and currency
+func (s *TransactionService) GetSpendingByCategory(ctx context.Context, walletID uuid.UUID, from, to time.Time) (*SpendingSummary, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+
+	if !from.Before(to) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "spending period start must be before its end")
+	}
+
+	categories, err := s.repo.GetSpendingByCategory(walletID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpendingSummary{
+		WalletID:   walletID,
+		From:       from,
+		To:         to,
+		Categories: categories,
+	}, nil
+}
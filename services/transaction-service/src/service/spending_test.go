@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/currency"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+func TestTransactionService_GetSpendingByCategory(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+
+	pay := func(amount float64, category string) *models.Transaction {
+		transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     amount,
+			Currency:   models.USDCBDC,
+			Metadata:   models.TransactionMetadata{Category: category},
+		})
+		require.NoError(t, err)
+		return transaction
+	}
+
+	pay(40.0, "groceries")
+	pay(60.0, "groceries")
+	pay(500.0, "rent")
+	pay(15.0, "")
+	old := pay(100.0, "groceries")
+
+	// Incoming transfers are not the wallet's spending
+	require.NoError(t, service.balanceRepo.AddFunds(toWallet, models.USDCBDC, 50.0))
+	_, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: toWallet,
+		ToWallet:   fromWallet,
+		Amount:     50.0,
+		Currency:   models.USDCBDC,
+		Metadata:   models.TransactionMetadata{Category: "groceries"},
+	})
+	require.NoError(t, err)
+
+	// Move one transaction out of the period
+	now := time.Now()
+	_, err = db.Exec(`UPDATE transactions SET created_at = $2 WHERE id = $1`, old.ID, now.AddDate(0, -2, 0))
+	require.NoError(t, err)
+
+	summary, err := service.GetSpendingByCategory(ctx, fromWallet, now.AddDate(0, -1, 0), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, []repository.CategorySpending{
+		{Category: "groceries", Currency: models.USDCBDC, Count: 2, Total: 100.0},
+		{Category: "rent", Currency: models.USDCBDC, Count: 1, Total: 500.0},
+		{Category: repository.UncategorizedCategory, Currency: models.USDCBDC, Count: 1, Total: 15.0},
+	}, summary.Categories)
+
+	// A period covering only the older transaction
+	summary, err = service.GetSpendingByCategory(ctx, fromWallet, now.AddDate(0, -3, 0), now.AddDate(0, -1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []repository.CategorySpending{
+		{Category: "groceries", Currency: models.USDCBDC, Count: 1, Total: 100.0},
+	}, summary.Categories)
+}
+
+func TestTransactionService_GetSpendingByCategory_Validation(t *testing.T) {
+	// Validation fails before the repository is used
+	service := &TransactionService{}
+	now := time.Now()
+
+	_, err := service.GetSpendingByCategory(context.Background(), uuid.Nil, now.Add(-time.Hour), now)
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+
+	_, err = service.GetSpendingByCategory(context.Background(), uuid.New(), now, now.Add(-time.Hour))
+	transactionErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
+
+func TestTransactionService_ValidateTransactionRequest_AllowedCategories(t *testing.T) {
+	service := &TransactionService{currencies: currency.NewDefaultRegistry()}
+	newRequest := func(category string) *TransactionRequest {
+		return &TransactionRequest{
+			FromWallet: uuid.New(),
+			ToWallet:   uuid.New(),
+			Amount:     10.0,
+			Currency:   models.USDCBDC,
+			Metadata:   models.TransactionMetadata{Category: category},
+		}
+	}
+
+	// Without an allow-list any category is accepted
+	require.NoError(t, service.validateTransactionRequest(newRequest("anything")))
+
+	service.SetAllowedCategories([]string{"groceries", "rent"})
+	assert.NoError(t, service.validateTransactionRequest(newRequest("groceries")))
+	assert.NoError(t, service.validateTransactionRequest(newRequest("")))
+
+	err := service.validateTransactionRequest(newRequest("gambling"))
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+	assert.Contains(t, transactionErr.Message, "category")
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// allowedStatusTransitions lists the statuses a transaction may move to from each status.
+// Failed and reversed transactions are final.
+var allowedStatusTransitions = map[models.TransactionStatus][]models.TransactionStatus{
+	models.StatusPending:   {models.StatusCompleted, models.StatusFailed},
+	models.StatusCompleted: {models.StatusReversed},
+}
+
+// CanTransitionStatus reports whether a transaction in status from may move to status to
+func CanTransitionStatus(from, to models.TransactionStatus) bool {
+	for _, allowed := range allowedStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// updateTransactionStatus moves a transaction to status and records it in the audit trail.
+// An illegal transition is rejected before the transaction or its audit trail is changed.
+func updateTransactionStatus(transaction *models.Transaction, status models.TransactionStatus, userID *uuid.UUID, details map[string]interface{}) error {
+	if !CanTransitionStatus(transaction.Status, status) {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction cannot move from %s to %s", transaction.Status, status))
+	}
+
+	return transaction.UpdateStatus(status, userID, "transaction-service", details)
+}
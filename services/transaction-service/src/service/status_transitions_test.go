@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+func TestCanTransitionStatus(t *testing.T) {
+	statuses := []models.TransactionStatus{models.StatusPending, models.StatusCompleted, models.StatusFailed, models.StatusReversed}
+	legal := map[[2]models.TransactionStatus]bool{
+		{models.StatusPending, models.StatusCompleted}:  true,
+		{models.StatusPending, models.StatusFailed}:     true,
+		{models.StatusCompleted, models.StatusReversed}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			expected := legal[[2]models.TransactionStatus{from, to}]
+			assert.Equal(t, expected, CanTransitionStatus(from, to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestUpdateTransactionStatus_RejectsIllegalTransition(t *testing.T) {
+	transaction, err := models.NewTransaction(uuid.New(), uuid.New(), 100.0, models.USDCBDC, models.TransactionMetadata{})
+	require.NoError(t, err)
+
+	require.NoError(t, updateTransactionStatus(transaction, models.StatusCompleted, nil, nil))
+	require.NoError(t, updateTransactionStatus(transaction, models.StatusReversed, nil, nil))
+	auditEntries := len(transaction.AuditTrail)
+
+	for _, status := range []models.TransactionStatus{models.StatusPending, models.StatusCompleted, models.StatusReversed} {
+		err := updateTransactionStatus(transaction, status, nil, nil)
+		transactionErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+		assert.Equal(t, models.StatusReversed, transaction.Status)
+		assert.Len(t, transaction.AuditTrail, auditEntries)
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenFreezer freezes and unfreezes tokens in the token management service, either all those
+// held by a wallet or a specific set
+type TokenFreezer interface {
+	FreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error
+	UnfreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error
+	FreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, note string) error
+}
+
+// HTTPTokenFreezer freezes wallet tokens through the token management REST API
+type HTTPTokenFreezer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPTokenFreezer creates a freezer against the token management service at baseURL
+func NewHTTPTokenFreezer(baseURL string, timeout time.Duration) *HTTPTokenFreezer {
+	return &HTTPTokenFreezer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// FreezeWalletTokens freezes every active token held by the wallet
+func (f *HTTPTokenFreezer) FreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error {
+	return f.post(ctx, fmt.Sprintf("%s/api/v1/wallets/%s/freeze", f.baseURL, walletID), map[string]string{"note": note})
+}
+
+// UnfreezeWalletTokens unfreezes the tokens frozen by FreezeWalletTokens
+func (f *HTTPTokenFreezer) UnfreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error {
+	return f.post(ctx, fmt.Sprintf("%s/api/v1/wallets/%s/unfreeze", f.baseURL, walletID), map[string]string{"note": note})
+}
+
+// FreezeTokens freezes the given tokens for fraud investigation
+func (f *HTTPTokenFreezer) FreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, note string) error {
+	return f.post(ctx, fmt.Sprintf("%s/api/v1/tokens/bulk/status", f.baseURL), map[string]interface{}{
+		"token_ids":  tokenIDs,
+		"new_status": "frozen",
+		"reason":     "fraud_investigation",
+		"note":       note,
+	})
+}
+
+func (f *HTTPTokenFreezer) post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach token management service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token management service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTokenFreezer records the wallets whose tokens were frozen and unfrozen, and the
+// tokens frozen individually, failing every call while fail is set
+type recordingTokenFreezer struct {
+	frozen       []uuid.UUID
+	unfrozen     []uuid.UUID
+	frozenTokens []uuid.UUID
+	notes        []string
+	fail         bool
+}
+
+func (f *recordingTokenFreezer) FreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error {
+	if f.fail {
+		return fmt.Errorf("token management service unavailable")
+	}
+	f.frozen = append(f.frozen, walletID)
+	return nil
+}
+
+func (f *recordingTokenFreezer) UnfreezeWalletTokens(ctx context.Context, walletID uuid.UUID, note string) error {
+	if f.fail {
+		return fmt.Errorf("token management service unavailable")
+	}
+	f.unfrozen = append(f.unfrozen, walletID)
+	return nil
+}
+
+func (f *recordingTokenFreezer) FreezeTokens(ctx context.Context, tokenIDs []uuid.UUID, note string) error {
+	if f.fail {
+		return fmt.Errorf("token management service unavailable")
+	}
+	f.frozenTokens = append(f.frozenTokens, tokenIDs...)
+	f.notes = append(f.notes, note)
+	return nil
+}
+
+func TestHTTPTokenFreezer(t *testing.T) {
+	walletID := uuid.New()
+	var paths []string
+	var notes []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Note string `json:"note"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		notes = append(notes, body.Note)
+
+		if r.URL.Path == "/api/v1/wallets/"+walletID.String()+"/freeze" || r.URL.Path == "/api/v1/wallets/"+walletID.String()+"/unfreeze" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	freezer := NewHTTPTokenFreezer(server.URL+"/", time.Second)
+
+	require.NoError(t, freezer.FreezeWalletTokens(context.Background(), walletID, "device compromised"))
+	require.NoError(t, freezer.UnfreezeWalletTokens(context.Background(), walletID, "owner verified"))
+	assert.Equal(t, []string{
+		"POST /api/v1/wallets/" + walletID.String() + "/freeze",
+		"POST /api/v1/wallets/" + walletID.String() + "/unfreeze",
+	}, paths)
+	assert.Equal(t, []string{"device compromised", "owner verified"}, notes)
+
+	assert.Error(t, freezer.FreezeWalletTokens(context.Background(), uuid.New(), ""))
+}
+
+func TestHTTPTokenFreezer_FreezeTokens(t *testing.T) {
+	tokenIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	var body struct {
+		TokenIDs  []uuid.UUID `json:"token_ids"`
+		NewStatus string      `json:"new_status"`
+		Reason    string      `json:"reason"`
+		Note      string      `json:"note"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/tokens/bulk/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	freezer := NewHTTPTokenFreezer(server.URL, time.Second)
+
+	require.NoError(t, freezer.FreezeTokens(context.Background(), tokenIDs, "fraud score exceeded"))
+	assert.Equal(t, tokenIDs, body.TokenIDs)
+	assert.Equal(t, "frozen", body.NewStatus)
+	assert.Equal(t, "fraud_investigation", body.Reason)
+	assert.Equal(t, "fraud score exceeded", body.Note)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/database"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/shared/libraries/webhooks"
+	"echopay/transaction-service/src/events"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// MaxSettlementTokens limits the number of tokens moved by a single token-backed transfer
+const MaxSettlementTokens = 100
+
+// TokenSettlementRequest is a transfer whose funds are backed by specific tokens that move
+// from the sender to the recipient along with the balance
+type TokenSettlementRequest struct {
+	TransactionRequest
+	TokenIDs []uuid.UUID `json:"token_ids" binding:"required"`
+}
+
+// SetTokenTransferrer sets how token ownership is moved for token-backed transfers
+func (s *TransactionService) SetTokenTransferrer(transferrer TokenTransferrer) {
+	s.tokens = transferrer
+}
+
+// SettleTransferWithTokens moves funds between wallets and then moves the tokens backing them
+// in the token management service. Balances and token ownership live in separate databases,
+// so the token leg runs once the balance transfer has committed. If any token fails to move,
+// the tokens already moved are returned to the sender and the balance transfer is reversed,
+// and an ErrTokenTransferFailed error describes what was undone.
+func (s *TransactionService) SettleTransferWithTokens(ctx context.Context, req *TokenSettlementRequest) (*models.Transaction, error) {
+	if s.tokens == nil {
+		return nil, errors.NewTransactionError(errors.ErrServiceUnavailable, "token settlement is not configured")
+	}
+	if err := validateSettlementTokens(req.TokenIDs); err != nil {
+		return nil, err
+	}
+
+	// Refuse before any funds move if the sender does not hold every token
+	for _, tokenID := range req.TokenIDs {
+		owned, err := s.tokens.VerifyOwnership(ctx, tokenID, req.FromWallet)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrServiceUnavailable, "failed to verify token ownership", "transaction-service")
+		}
+		if !owned {
+			return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("token %s is not held by wallet %s", tokenID, req.FromWallet))
+		}
+	}
+
+	transaction, err := s.processTransaction(ctx, &req.TransactionRequest, req.TokenIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tokenID := range req.TokenIDs {
+		if err := s.tokens.TransferToken(ctx, tokenID, req.ToWallet, transaction.ID); err != nil {
+			return nil, s.compensateTokenSettlement(ctx, transaction, req.TokenIDs[:i], tokenID, err)
+		}
+	}
+
+	return transaction, nil
+}
+
+// compensateTokenSettlement undoes a token-backed transfer whose token leg failed at
+// failedToken. Tokens already moved are returned on a best-effort basis; any that cannot be
+// are listed in the returned error for manual reconciliation.
+func (s *TransactionService) compensateTokenSettlement(ctx context.Context, transaction *models.Transaction, moved []uuid.UUID, failedToken uuid.UUID, cause error) error {
+	var unreturned []uuid.UUID
+	for _, tokenID := range moved {
+		if err := s.tokens.TransferToken(ctx, tokenID, transaction.FromWallet, transaction.ID); err != nil {
+			unreturned = append(unreturned, tokenID)
+		}
+	}
+
+	details := map[string]interface{}{
+		"transaction_id":  transaction.ID,
+		"failed_token_id": failedToken,
+		"cause":           cause.Error(),
+	}
+	if len(unreturned) > 0 {
+		details["unreturned_token_ids"] = unreturned
+	}
+
+	if err := s.reverseBalanceTransfer(ctx, transaction, details); err != nil {
+		details["reversal_error"] = err.Error()
+		return errors.NewTransactionError(
+			errors.ErrTokenTransferFailed,
+			fmt.Sprintf("token %s could not be transferred and the balance transfer could not be reversed; manual reconciliation is required", failedToken),
+		).WithDetails(details)
+	}
+
+	message := fmt.Sprintf("token %s could not be transferred; the balance transfer was reversed", failedToken)
+	if len(unreturned) > 0 {
+		message += fmt.Sprintf(", but %d transferred tokens could not be returned", len(unreturned))
+	}
+	return errors.NewTransactionError(errors.ErrTokenTransferFailed, message).WithDetails(details)
+}
+
+// reverseBalanceTransfer undoes the balance changes recorded for a completed transaction and
+// marks it reversed. A wallet that has since spent the funds it received cannot be reversed.
+func (s *TransactionService) reverseBalanceTransfer(ctx context.Context, transaction *models.Transaction, details map[string]interface{}) error {
+	changes, err := s.repo.GetBalanceChanges(transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	currency := string(transaction.Currency)
+	original := *transaction
+	var reversed []repository.BalanceChange
+
+	err = database.WithRetry(func() error {
+		*transaction = original
+		reversed = nil
+		return s.db.Transaction(func(tx *sql.Tx) error {
+			s.balanceMutex.Lock()
+			defer s.balanceMutex.Unlock()
+
+			for _, change := range changes {
+				balance, err := s.balanceRepo.GetBalanceForUpdate(tx, change.WalletID, transaction.Currency)
+				if err != nil {
+					return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get wallet balance", "transaction-service")
+				}
+
+				deltaMinor := money.ToMinor(change.NewBalance, currency) - money.ToMinor(change.OldBalance, currency)
+				newMinor := money.ToMinor(balance.Balance, currency) - deltaMinor
+				// Funds held for transfers awaiting settlement cannot be taken back
+				if newMinor < money.ToMinor(balance.Held, currency) {
+					return errors.NewTransactionError(
+						errors.ErrInsufficientFunds,
+						fmt.Sprintf("wallet %s no longer holds the %s needed to reverse the transfer", change.WalletID, money.Format(money.FromMinor(deltaMinor, currency), currency)),
+					)
+				}
+
+				newBalance := money.FromMinor(newMinor, currency)
+				if err := s.balanceRepo.UpdateBalance(tx, change.WalletID, transaction.Currency, newBalance); err != nil {
+					return errors.WrapError(err, errors.ErrTransactionFailed, "failed to restore wallet balance", "transaction-service")
+				}
+				reversed = append(reversed, repository.BalanceChange{WalletID: change.WalletID, OldBalance: balance.Balance, NewBalance: newBalance})
+			}
+
+			err := updateTransactionStatus(transaction, models.StatusReversed, nil, map[string]interface{}{
+				"reason":          "token transfer failed",
+				"failed_token_id": details["failed_token_id"],
+			})
+			if err != nil {
+				return err
+			}
+			return s.repo.UpdateInTx(tx, transaction)
+		})
+	}, s.retryPolicy)
+	if err != nil {
+		return err
+	}
+
+	logTransactionTransition(ctx, transaction, original.Status, "reason", "token transfer failed", "failed_token_id", details["failed_token_id"])
+
+	go func() {
+		for _, change := range reversed {
+			s.publishBalanceUpdateEvent(ctx, change.WalletID, transaction.Currency, change.OldBalance, change.NewBalance, &transaction.ID)
+		}
+	}()
+	s.publishTransactionEvent(ctx, transaction, events.EventTransactionReversed)
+	s.statusTracker.PublishStatusUpdate(transaction, "Transaction reversed after its token transfer failed")
+	s.webhooks.Dispatch(webhooks.EventTransactionReversed, map[string]interface{}{
+		"transaction_id": transaction.ID,
+		"from_wallet":    transaction.FromWallet,
+		"to_wallet":      transaction.ToWallet,
+		"amount":         transaction.Amount,
+		"currency":       transaction.Currency,
+		"details":        details,
+	})
+
+	return nil
+}
+
+// validateSettlementTokens checks the tokens named by a token-backed transfer
+func validateSettlementTokens(tokenIDs []uuid.UUID) error {
+	if len(tokenIDs) == 0 {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, "at least one token ID is required")
+	}
+	if len(tokenIDs) > MaxSettlementTokens {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("a transfer can move at most %d tokens", MaxSettlementTokens))
+	}
+
+	seen := make(map[uuid.UUID]bool, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		if tokenID == uuid.Nil {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "token ID cannot be nil")
+		}
+		if seen[tokenID] {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("token %s is listed more than once", tokenID))
+		}
+		seen[tokenID] = true
+	}
+
+	return nil
+}
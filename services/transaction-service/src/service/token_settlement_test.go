@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// fakeTokenTransferrer tracks token owners in memory and fails transfers of tokens in failing
+type fakeTokenTransferrer struct {
+	owners  map[uuid.UUID]uuid.UUID
+	failing map[uuid.UUID]bool
+}
+
+func (f *fakeTokenTransferrer) VerifyOwnership(ctx context.Context, tokenID, ownerID uuid.UUID) (bool, error) {
+	return f.owners[tokenID] == ownerID, nil
+}
+
+func (f *fakeTokenTransferrer) TransferToken(ctx context.Context, tokenID, newOwner, transactionID uuid.UUID) error {
+	if f.failing[tokenID] {
+		return fmt.Errorf("token management service returned status 409")
+	}
+	f.owners[tokenID] = newOwner
+	return nil
+}
+
+func TestTransactionService_SettleTransferWithTokens(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+
+	transferrer := &fakeTokenTransferrer{
+		owners:  map[uuid.UUID]uuid.UUID{first: fromWallet, second: fromWallet},
+		failing: map[uuid.UUID]bool{},
+	}
+	service.SetTokenTransferrer(transferrer)
+
+	transaction, err := service.SettleTransferWithTokens(ctx, &TokenSettlementRequest{
+		TransactionRequest: TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     200.0,
+			Currency:   models.USDCBDC,
+		},
+		TokenIDs: []uuid.UUID{first, second},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, transaction.Status)
+	assert.Equal(t, toWallet, transferrer.owners[first])
+	assert.Equal(t, toWallet, transferrer.owners[second])
+
+	tokenIDs, err := service.repo.GetTokenIDs(transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, tokenIDs)
+
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, toBalance.Balance)
+}
+
+func TestTransactionService_SettleTransferWithTokens_TokenLegFails(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+
+	transferrer := &fakeTokenTransferrer{
+		owners:  map[uuid.UUID]uuid.UUID{first: fromWallet, second: fromWallet},
+		failing: map[uuid.UUID]bool{second: true},
+	}
+	service.SetTokenTransferrer(transferrer)
+
+	transaction, err := service.SettleTransferWithTokens(ctx, &TokenSettlementRequest{
+		TransactionRequest: TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     200.0,
+			Currency:   models.USDCBDC,
+		},
+		TokenIDs: []uuid.UUID{first, second},
+	})
+
+	assert.Nil(t, transaction)
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrTokenTransferFailed, echoPayErr.Code)
+	assert.Contains(t, echoPayErr.Message, "reversed")
+	assert.Equal(t, second, echoPayErr.Details["failed_token_id"])
+	assert.NotContains(t, echoPayErr.Details, "unreturned_token_ids")
+
+	// The token that did move was returned to the sender
+	assert.Equal(t, fromWallet, transferrer.owners[first])
+	assert.Equal(t, fromWallet, transferrer.owners[second])
+
+	// Balances were rolled back
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, toBalance.Balance)
+
+	// The transaction is kept as reversed with its tokens recorded
+	transactionID := echoPayErr.Details["transaction_id"].(uuid.UUID)
+	reversed, err := service.GetTransaction(ctx, transactionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusReversed, reversed.Status)
+
+	tokenIDs, err := service.repo.GetTokenIDs(transactionID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, tokenIDs)
+}
+
+func TestTransactionService_SettleTransferWithTokens_Validation(t *testing.T) {
+	fromWallet := uuid.New()
+	owned := uuid.New()
+	newRequest := func(tokenIDs ...uuid.UUID) *TokenSettlementRequest {
+		return &TokenSettlementRequest{
+			TransactionRequest: TransactionRequest{
+				FromWallet: fromWallet,
+				ToWallet:   uuid.New(),
+				Amount:     10.0,
+				Currency:   models.USDCBDC,
+			},
+			TokenIDs: tokenIDs,
+		}
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		service := &TransactionService{}
+		_, err := service.SettleTransferWithTokens(context.Background(), newRequest(owned))
+
+		echoPayErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrServiceUnavailable, echoPayErr.Code)
+	})
+
+	service := &TransactionService{}
+	service.SetTokenTransferrer(&fakeTokenTransferrer{
+		owners:  map[uuid.UUID]uuid.UUID{owned: fromWallet},
+		failing: map[uuid.UUID]bool{},
+	})
+
+	testCases := []struct {
+		name    string
+		req     *TokenSettlementRequest
+		message string
+	}{
+		{"no tokens", newRequest(), "at least one"},
+		{"nil token", newRequest(uuid.Nil), "cannot be nil"},
+		{"duplicate token", newRequest(owned, owned), "more than once"},
+		{"token held by another wallet", newRequest(owned, uuid.New()), "is not held by"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.SettleTransferWithTokens(context.Background(), tc.req)
+
+			echoPayErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+			assert.Contains(t, echoPayErr.Message, tc.message)
+		})
+	}
+}
+
+func TestHTTPTokenTransferrer(t *testing.T) {
+	tokenID, owner, pendingToken := uuid.New(), uuid.New(), uuid.New()
+	newOwner, transactionID := uuid.New(), uuid.New()
+	var transferred struct {
+		TokenID       uuid.UUID `json:"token_id"`
+		NewOwner      uuid.UUID `json:"new_owner"`
+		TransactionID uuid.UUID `json:"transaction_id"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tokens/" + tokenID.String() + "/verify/" + owner.String():
+			json.NewEncoder(w).Encode(map[string]interface{}{"is_owner": true})
+		case "/api/v1/tokens/" + tokenID.String() + "/transfer":
+			json.NewDecoder(r.Body).Decode(&transferred)
+			w.WriteHeader(http.StatusOK)
+		case "/api/v1/tokens/" + pendingToken.String() + "/transfer":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	transferrer := NewHTTPTokenTransferrer(server.URL+"/", time.Second)
+
+	owned, err := transferrer.VerifyOwnership(context.Background(), tokenID, owner)
+	require.NoError(t, err)
+	assert.True(t, owned)
+
+	owned, err = transferrer.VerifyOwnership(context.Background(), uuid.New(), owner)
+	require.NoError(t, err)
+	assert.False(t, owned)
+
+	require.NoError(t, transferrer.TransferToken(context.Background(), tokenID, newOwner, transactionID))
+	assert.Equal(t, tokenID, transferred.TokenID)
+	assert.Equal(t, newOwner, transferred.NewOwner)
+	assert.Equal(t, transactionID, transferred.TransactionID)
+
+	// A transfer awaiting co-signers has not moved the token
+	assert.Error(t, transferrer.TransferToken(context.Background(), pendingToken, newOwner, transactionID))
+}
+
+func TestHTTPTokenTransferrer_RequiredNonce(t *testing.T) {
+	tokenID := uuid.New()
+	used := make(map[string]bool)
+
+	// Like the token management service with TRANSFER_NONCE_REQUIRED set, reject transfers
+	// without a nonce and timestamp, with a stale timestamp or with a nonce already used
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Nonce     string    `json:"nonce"`
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Nonce == "" || body.Timestamp.IsZero() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if time.Since(body.Timestamp) > 5*time.Minute || used[body.Nonce] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		used[body.Nonce] = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transferrer := NewHTTPTokenTransferrer(server.URL, time.Second)
+
+	// Every transfer carries its own nonce
+	require.NoError(t, transferrer.TransferToken(context.Background(), tokenID, uuid.New(), uuid.New()))
+	require.NoError(t, transferrer.TransferToken(context.Background(), tokenID, uuid.New(), uuid.New()))
+	assert.Len(t, used, 2)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenTransferrer moves token ownership in the token management service
+type TokenTransferrer interface {
+	VerifyOwnership(ctx context.Context, tokenID, ownerID uuid.UUID) (bool, error)
+	TransferToken(ctx context.Context, tokenID, newOwner, transactionID uuid.UUID) error
+}
+
+// HTTPTokenTransferrer moves tokens through the token management REST API
+type HTTPTokenTransferrer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPTokenTransferrer creates a transferrer against the token management service at baseURL
+func NewHTTPTokenTransferrer(baseURL string, timeout time.Duration) *HTTPTokenTransferrer {
+	return &HTTPTokenTransferrer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// VerifyOwnership reports whether ownerID currently holds the token
+func (t *HTTPTokenTransferrer) VerifyOwnership(ctx context.Context, tokenID, ownerID uuid.UUID) (bool, error) {
+	url := fmt.Sprintf("%s/api/v1/tokens/%s/verify/%s", t.baseURL, tokenID, ownerID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to reach token management service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("token management service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		IsOwner bool `json:"is_owner"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to decode ownership result: %w", err)
+	}
+	return result.IsOwner, nil
+}
+
+// TransferToken transfers a token to newOwner as part of a transaction. Each request carries
+// a fresh nonce and timestamp, so it passes the service's replay checks when nonces are
+// required. A transfer held for co-signer approval has not moved the token and is reported as
+// an error.
+func (t *HTTPTokenTransferrer) TransferToken(ctx context.Context, tokenID, newOwner, transactionID uuid.UUID) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"token_id":       tokenID,
+		"new_owner":      newOwner,
+		"transaction_id": transactionID,
+		"nonce":          uuid.NewString(),
+		"timestamp":      time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/tokens/%s/transfer", t.baseURL, tokenID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach token management service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return fmt.Errorf("transfer of token %s is awaiting co-signer approval", tokenID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token management service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/models"
+)
+
+// MaxTransactionChainLength caps the transactions returned in a chain, so corrupt links
+// cannot make a chain lookup walk an unbounded set
+const MaxTransactionChainLength = 1000
+
+// Relations of a transaction to the rest of its chain
+const (
+	ChainRelationOriginal = "original"
+	ChainRelationRefund   = "refund"
+)
+
+// TransactionChainMember is one transaction in a chain. Reversals undo a transaction in
+// place, so a reversed member is one whose status is reversed.
+type TransactionChainMember struct {
+	Transaction *models.Transaction `json:"transaction"`
+	Relation    string              `json:"relation"`
+	RefundOf    *uuid.UUID          `json:"refund_of,omitempty"`
+	Reversed    bool                `json:"reversed"`
+	Archived    bool                `json:"archived"`
+}
+
+// TransactionChain is the set of transactions linked to a transaction by refunds, oldest first
+type TransactionChain struct {
+	TransactionID uuid.UUID                `json:"transaction_id"`
+	Members       []TransactionChainMember `json:"members"`
+	Truncated     bool                     `json:"truncated,omitempty"`
+}
+
+// GetTransactionChain returns every transaction connected to a transaction through refund
+// links, in both directions: the original it refunds, the original's other refunds, and any
+// refunds of those. Each transaction appears once even if the links form a cycle, and
+// archived transactions are included and marked as such.
+func (s *TransactionService) GetTransactionChain(ctx context.Context, id uuid.UUID) (*TransactionChain, error) {
+	chain := &TransactionChain{TransactionID: id}
+
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		if len(chain.Members) >= MaxTransactionChainLength {
+			chain.Truncated = true
+			logging.WithContext(ctx).Warn("Transaction chain truncated",
+				"transaction_id", id,
+				"members", len(chain.Members),
+			)
+			break
+		}
+
+		memberID := queue[0]
+		queue = queue[1:]
+
+		links, err := s.repo.GetRefundLinks(memberID)
+		if err != nil {
+			if echoPayErr, ok := err.(*errors.EchoPayError); ok && echoPayErr.Code == errors.ErrTransactionNotFound && memberID != id {
+				// A dangling link is skipped rather than failing the whole chain
+				logging.WithContext(ctx).Warn("Transaction chain links to a missing transaction",
+					"transaction_id", id,
+					"missing_id", memberID,
+				)
+				continue
+			}
+			return nil, err
+		}
+
+		transaction, err := s.repo.GetByID(memberID)
+		if err != nil {
+			return nil, err
+		}
+
+		member := TransactionChainMember{
+			Transaction: transaction,
+			Relation:    ChainRelationOriginal,
+			RefundOf:    links.RefundOf,
+			Reversed:    transaction.Status == models.StatusReversed,
+			Archived:    links.Archived,
+		}
+		if links.RefundOf != nil {
+			member.Relation = ChainRelationRefund
+		}
+		chain.Members = append(chain.Members, member)
+
+		neighbours := links.Refunds
+		if links.RefundOf != nil {
+			neighbours = append([]uuid.UUID{*links.RefundOf}, neighbours...)
+		}
+		for _, neighbour := range neighbours {
+			if !visited[neighbour] {
+				visited[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	sort.SliceStable(chain.Members, func(i, j int) bool {
+		a, b := chain.Members[i].Transaction, chain.Members[j].Transaction
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	return chain, nil
+}
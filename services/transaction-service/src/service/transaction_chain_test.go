@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/transaction-service/src/models"
+)
+
+func TestTransactionService_GetTransactionChain(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	fromWallet, toWallet := createTestWallets(t, service)
+	original, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+
+	first, err := service.PartialRefund(ctx, original.ID, 30.0, "Item returned")
+	require.NoError(t, err)
+	second, err := service.PartialRefund(ctx, original.ID, 20.0, "Second item returned")
+	require.NoError(t, err)
+
+	// The second refund is itself reversed
+	require.NoError(t, service.UpdateTransactionStatus(ctx, second.Refund.ID, models.StatusReversed, nil, nil))
+
+	assertChain := func(t *testing.T, chain *TransactionChain) {
+		require.Len(t, chain.Members, 3)
+
+		seen := map[uuid.UUID]TransactionChainMember{}
+		for _, member := range chain.Members {
+			_, duplicate := seen[member.Transaction.ID]
+			assert.False(t, duplicate, "transaction %s appears more than once", member.Transaction.ID)
+			seen[member.Transaction.ID] = member
+		}
+
+		assert.Equal(t, original.ID, chain.Members[0].Transaction.ID, "members are ordered by time")
+		assert.Contains(t, seen, first.Refund.ID)
+		assert.Contains(t, seen, second.Refund.ID)
+		assert.Equal(t, ChainRelationRefund, seen[first.Refund.ID].Relation)
+		assert.Equal(t, original.ID, *seen[first.Refund.ID].RefundOf)
+		assert.True(t, seen[second.Refund.ID].Reversed)
+		assert.False(t, seen[first.Refund.ID].Reversed)
+	}
+
+	t.Run("the whole chain is returned from any member", func(t *testing.T) {
+		for _, id := range []uuid.UUID{original.ID, first.Refund.ID, second.Refund.ID} {
+			chain, err := service.GetTransactionChain(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, id, chain.TransactionID)
+			assertChain(t, chain)
+
+			assert.Equal(t, ChainRelationOriginal, chain.Members[0].Relation)
+			assert.False(t, chain.Members[0].Archived)
+		}
+	})
+
+	t.Run("cyclic links are followed once", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE transactions SET metadata = metadata || jsonb_build_object('refund_of', $2::text) WHERE id = $1`, original.ID, second.Refund.ID)
+		require.NoError(t, err)
+
+		chain, err := service.GetTransactionChain(ctx, first.Refund.ID)
+		require.NoError(t, err)
+		assertChain(t, chain)
+	})
+
+	t.Run("unknown transaction is not found", func(t *testing.T) {
+		_, err := service.GetTransactionChain(ctx, uuid.New())
+		assert.Error(t, err)
+	})
+}
@@ -4,12 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/logging"
+	"echopay/shared/libraries/money"
+	"echopay/shared/libraries/monitoring"
+	"echopay/shared/libraries/webhooks"
 	"echopay/transaction-service/src/events"
 	"echopay/transaction-service/src/models"
 	"echopay/transaction-service/src/repository"
@@ -22,24 +32,67 @@ type TransactionRequest struct {
 	Amount     float64   `json:"amount" binding:"required,gt=0"`
 	Currency   models.Currency `json:"currency" binding:"required"`
 	Metadata   models.TransactionMetadata `json:"metadata"`
+
+	// Private memos shown only to the sending or receiving wallet; the description is shared
+	SenderNote    string `json:"sender_note,omitempty"`
+	RecipientNote string `json:"recipient_note,omitempty"`
 }
 
 // TransactionService handles core transaction processing
 type TransactionService struct {
 	repo           *repository.TransactionRepository
 	balanceRepo    *repository.WalletBalanceRepository
+	walletRepo     *repository.WalletRepository
+	policyRepo     *repository.TransferPolicyRepository
 	db             *database.PostgresDB
 	eventPublisher *events.EventPublisher
 	statusTracker  *events.StatusTracker
 	balanceMutex   sync.RWMutex // Protects balance operations
 	metrics        *TransactionMetrics
+	promMetrics    *monitoring.Metrics
+	webhooks       *webhooks.Dispatcher
+	feeConfig      FeeConfig
+	retryPolicy    database.RetryPolicy
+	currencies     *currency.Registry
+	clock          clock.Clock
+	tokenFreezer   TokenFreezer
+	tokens         TokenTransferrer
+	alerter        Alerter
+	receiptKeys    *ReceiptKeys
+
+	autoFreezeThreshold *float64        // Fraud score above which a transaction's tokens are frozen; nil disables auto-freeze
+	criticalScore       float64         // Fraud score above which the alerter is notified
+	fraudHalfLife       time.Duration   // Age at which a fraud score counts half towards wallet risk; zero disables decay
+	allowedCategories   map[string]bool // Categories transactions may be recorded with; nil accepts any
+	settlementMode      SettlementMode  // Whether transfers settle immediately or are held until Settle
+	reportingLocation   *time.Location  // Timezone of volume report days; nil reports in UTC
+	maxAuditEntries     int             // Audit entries verified as a transaction is read; see verifyIntegrity
+
+	eventHealthMutex sync.RWMutex
+	eventHealth      *EventStreamingStatus // Result of the last event streaming health check
+}
+
+// EventStreamingStatus is the result of an event streaming health check
+type EventStreamingStatus struct {
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
 }
 
-// TransactionMetrics tracks service performance metrics
+// maxProcessingSamples is the number of recent processing times kept for metrics
+const maxProcessingSamples = 1000
+
+// TransactionMetrics tracks service performance metrics. ProcessingTimes holds the
+// last maxProcessingSamples (1000) processing times; the percentile fields are
+// computed over that window by GetServiceMetrics and are zero when it is empty.
 type TransactionMetrics struct {
 	ProcessingTimes []time.Duration
 	SuccessCount    int64
 	FailureCount    int64
+	P50             time.Duration
+	P95             time.Duration
+	P99             time.Duration
+	Max             time.Duration
 	mutex           sync.RWMutex
 }
 
@@ -55,10 +108,17 @@ func NewTransactionService(db *database.PostgresDB) *TransactionService {
 	return &TransactionService{
 		repo:           repository.NewTransactionRepository(db),
 		balanceRepo:    repository.NewWalletBalanceRepository(db),
+		walletRepo:     repository.NewWalletRepository(db),
+		policyRepo:     repository.NewTransferPolicyRepository(db),
 		db:             db,
 		eventPublisher: eventPublisher,
 		statusTracker:  statusTracker,
 		metrics:        &TransactionMetrics{},
+		feeConfig:      DefaultFeeConfig(),
+		retryPolicy:    database.DefaultRetryPolicy(),
+		currencies:     currency.NewDefaultRegistry(),
+		clock:          clock.Real(),
+		settlementMode: SettlementInstant,
 	}
 }
 
@@ -67,18 +127,130 @@ func NewTransactionServiceWithEvents(db *database.PostgresDB, eventPublisher *ev
 	return &TransactionService{
 		repo:           repository.NewTransactionRepository(db),
 		balanceRepo:    repository.NewWalletBalanceRepository(db),
+		walletRepo:     repository.NewWalletRepository(db),
+		policyRepo:     repository.NewTransferPolicyRepository(db),
 		db:             db,
 		eventPublisher: eventPublisher,
 		statusTracker:  statusTracker,
 		metrics:        &TransactionMetrics{},
+		feeConfig:      DefaultFeeConfig(),
+		retryPolicy:    database.DefaultRetryPolicy(),
+		currencies:     currency.NewDefaultRegistry(),
+		clock:          clock.Real(),
+		settlementMode: SettlementInstant,
+	}
+}
+
+// CheckEventPublisher verifies that the event publisher can reach its brokers
+func (s *TransactionService) CheckEventPublisher(ctx context.Context) error {
+	return s.eventPublisher.CheckConnection(ctx)
+}
+
+// FlushEvents waits until published events have been delivered to Kafka and returns any
+// delivery failures since the last flush
+func (s *TransactionService) FlushEvents(ctx context.Context) error {
+	if s.eventPublisher == nil {
+		return nil
 	}
+	return s.eventPublisher.Flush(ctx)
+}
+
+// CheckEventStreaming runs the event publisher health check and records the result.
+// Transactions are still processed while event streaming is unhealthy; the service
+// only reports itself as degraded.
+func (s *TransactionService) CheckEventStreaming(ctx context.Context) *EventStreamingStatus {
+	status := &EventStreamingStatus{Healthy: true, CheckedAt: s.clock.Now().UTC()}
+	if err := s.eventPublisher.Healthy(ctx); err != nil {
+		status.Healthy = false
+		status.Error = err.Error()
+	}
+
+	s.eventHealthMutex.Lock()
+	s.eventHealth = status
+	s.eventHealthMutex.Unlock()
+
+	if s.promMetrics != nil {
+		s.promMetrics.SetEventStreamingHealthy(status.Healthy)
+	}
+
+	return status
+}
+
+// EventStreamingDegraded reports whether the last event streaming health check failed
+func (s *TransactionService) EventStreamingDegraded() bool {
+	s.eventHealthMutex.RLock()
+	defer s.eventHealthMutex.RUnlock()
+	return s.eventHealth != nil && !s.eventHealth.Healthy
+}
+
+// SetPrometheusMetrics enables export of per-transaction latency and outcome metrics
+func (s *TransactionService) SetPrometheusMetrics(metrics *monitoring.Metrics) {
+	s.promMetrics = metrics
+}
+
+// SetWebhookDispatcher enables webhook notifications for transaction reversals
+func (s *TransactionService) SetWebhookDispatcher(dispatcher *webhooks.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// SetRetryPolicy configures how transactions retry serialization failures and deadlocks
+func (s *TransactionService) SetRetryPolicy(policy database.RetryPolicy) {
+	s.retryPolicy = policy
+}
+
+// SetCurrencyRegistry sets the currencies accepted for transfers and seeded into new wallets
+func (s *TransactionService) SetCurrencyRegistry(registry *currency.Registry) {
+	s.currencies = registry
+	s.balanceRepo.SetCurrencyRegistry(registry)
+}
+
+// SetClock sets the clock used for claim leases, stuck and archive cutoffs and the
+// force-fail processing window
+func (s *TransactionService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SupportsCurrency reports whether a currency is registered as supported
+func (s *TransactionService) SupportsCurrency(currency models.Currency) bool {
+	return s.currencies.Supported(string(currency))
+}
+
+// SetFeeConfig configures per-transaction fee collection
+func (s *TransactionService) SetFeeConfig(config FeeConfig) {
+	s.feeConfig = config
+}
+
+// SetTokenFreezer sets how a wallet's tokens are frozen along with the wallet. Without one,
+// an emergency freeze only blocks the wallet's transfers.
+func (s *TransactionService) SetTokenFreezer(freezer TokenFreezer) {
+	s.tokenFreezer = freezer
+}
+
+// SetAutoFreezeThreshold enables freezing the tokens moved by a transaction whose fraud score
+// is set above threshold. Tokens are frozen through the token freezer.
+func (s *TransactionService) SetAutoFreezeThreshold(threshold float64) {
+	s.autoFreezeThreshold = &threshold
+}
+
+// SetMetadataEncryptor enables encryption of sensitive transaction metadata at rest
+func (s *TransactionService) SetMetadataEncryptor(encryptor repository.Encryptor) {
+	s.repo.SetEncryptor(encryptor)
 }
 
 // ProcessTransaction processes a transaction with sub-second performance
 func (s *TransactionService) ProcessTransaction(ctx context.Context, req *TransactionRequest) (*models.Transaction, error) {
+	return s.processTransaction(ctx, req, nil)
+}
+
+// processTransaction processes a transaction, recording the given token IDs and the request's
+// transfer notes in its metadata when there are any
+func (s *TransactionService) processTransaction(ctx context.Context, req *TransactionRequest, tokenIDs []uuid.UUID) (*models.Transaction, error) {
 	startTime := time.Now()
+	outcome := monitoring.OutcomeFailure
 	defer func() {
-		s.recordProcessingTime(time.Since(startTime))
+		duration := time.Since(startTime)
+		s.recordProcessingTime(duration)
+		s.observeTransaction(req.Currency, outcome, duration)
 	}()
 
 	// Validate transaction request
@@ -100,90 +272,261 @@ func (s *TransactionService) ProcessTransaction(ctx context.Context, req *Transa
 		return nil, errors.WrapError(err, errors.ErrInvalidTransaction, "failed to create transaction", "transaction-service")
 	}
 
-	// Publish transaction created event
-	s.publishTransactionEvent(ctx, transaction, events.EventTransactionCreated)
+	// Publish transaction created event, scored against the sender's baseline for fraud detection
+	s.publishTransactionCreatedEvent(ctx, transaction, s.scoreAgainstBaseline(ctx, transaction))
 	s.statusTracker.PublishStatusUpdate(transaction, "Transaction created and processing")
 
+	// Token-backed transfers move their tokens immediately, so they always settle instantly
+	delayed := s.settlementMode == SettlementDelayed && len(tokenIDs) == 0
+
 	// Process transaction with atomic balance updates
-	err = s.processTransactionAtomic(ctx, transaction)
+	notes := repository.TransferNotes{SenderNote: req.SenderNote, RecipientNote: req.RecipientNote}
+	err = s.processTransactionAtomic(ctx, transaction, tokenIDs, notes, delayed)
 	if err != nil {
 		s.recordFailure()
+		logging.WithContext(logging.ContextWithFields(ctx, "transaction_id", transaction.ID.String())).Warn("Transaction failed",
+			"from_status", transaction.Status,
+			"to_status", models.StatusFailed,
+			"error", err.Error(),
+		)
 		// Publish failure event
 		s.publishTransactionEvent(ctx, transaction, events.EventTransactionFailed)
 		return nil, err
 	}
 
+	if delayed {
+		// The transfer completes when it is settled
+		s.statusTracker.PublishStatusUpdate(transaction, "Transaction authorized, funds held until settlement")
+		outcome = monitoring.OutcomeSuccess
+		s.recordSuccess()
+		return transaction, nil
+	}
+	logTransactionTransition(ctx, transaction, models.StatusPending, "amount", transaction.Amount, "currency", transaction.Currency)
+
+	s.updateWalletBaseline(ctx, transaction)
+	
 	// Publish success events
 	s.publishTransactionEvent(ctx, transaction, events.EventTransactionCompleted)
 	s.statusTracker.PublishStatusUpdate(transaction, "Transaction completed successfully")
 
+	outcome = monitoring.OutcomeSuccess
 	s.recordSuccess()
+	s.observeAmount(transaction.Currency, transaction.Amount)
 	return transaction, nil
 }
 
-// processTransactionAtomic handles the atomic transaction processing
-func (s *TransactionService) processTransactionAtomic(ctx context.Context, transaction *models.Transaction) error {
-	return s.db.Transaction(func(tx *sql.Tx) error {
-		// Lock wallet balances to prevent race conditions
-		s.balanceMutex.Lock()
-		defer s.balanceMutex.Unlock()
+// processTransactionAtomic handles the atomic transaction processing. When delayed, the
+// funds are held and the transaction is left pending until it is settled. Serialization
+// failures and deadlocks are retried according to the service's retry policy.
+func (s *TransactionService) processTransactionAtomic(ctx context.Context, transaction *models.Transaction, tokenIDs []uuid.UUID, notes repository.TransferNotes, delayed bool) error {
+	// Each attempt starts from the unprocessed transaction
+	original := *transaction
+	var changes []repository.BalanceChange
+
+	err := database.WithRetry(func() error {
+		*transaction = original
+		return s.db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			if delayed {
+				err = s.holdTransactionInTx(tx, transaction)
+			} else {
+				changes, err = s.applyTransactionInTx(tx, transaction)
+			}
+			if err != nil {
+				return err
+			}
+			if len(tokenIDs) > 0 {
+				if err := s.repo.RecordTokenIDsInTx(tx, transaction.ID, tokenIDs); err != nil {
+					return err
+				}
+			}
+			if !notes.IsEmpty() {
+				return s.repo.RecordTransferNotesInTx(tx, transaction.ID, notes)
+			}
+			return nil
+		})
+	}, s.retryPolicy)
+	if err != nil {
+		return err
+	}
 
-		// Verify sufficient funds
-		fromBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.FromWallet, transaction.Currency)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get sender balance", "transaction-service")
+	// Publish balance update events now that the transaction has committed
+	go func() {
+		for _, change := range changes {
+			s.publishBalanceUpdateEvent(ctx, change.WalletID, transaction.Currency, change.OldBalance, change.NewBalance, &transaction.ID)
 		}
+	}()
 
-		if fromBalance.Balance < transaction.Amount {
-			return errors.NewTransactionError(
-				errors.ErrInsufficientFunds,
-				fmt.Sprintf("insufficient funds: available %.2f, required %.2f", fromBalance.Balance, transaction.Amount),
-			)
-		}
+	return nil
+}
 
-		// Verify recipient wallet exists
-		toBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.ToWallet, transaction.Currency)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to get recipient balance", "transaction-service")
-		}
+// applyTransactionInTx moves funds for a transaction and records it, returning the
+// resulting balance changes
+func (s *TransactionService) applyTransactionInTx(tx *sql.Tx, transaction *models.Transaction) ([]repository.BalanceChange, error) {
+	// Lock wallet balances to prevent race conditions
+	s.balanceMutex.Lock()
+	defer s.balanceMutex.Unlock()
+
+	if err := s.checkWalletsInTx(tx, transaction); err != nil {
+		return nil, err
+	}
 
-		// Update balances atomically
-		newFromBalance := fromBalance.Balance - transaction.Amount
-		newToBalance := toBalance.Balance + transaction.Amount
+	// Verify sufficient funds
+	fromBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.FromWallet, transaction.Currency)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get sender balance", "transaction-service")
+	}
 
-		err = s.balanceRepo.UpdateBalance(tx, transaction.FromWallet, transaction.Currency, newFromBalance)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update sender balance", "transaction-service")
+	fee := money.Round(s.calculateFee(transaction), string(transaction.Currency))
+	if err := checkSpendable(fromBalance, transaction.Amount+fee); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.transferFundsInTx(tx, transaction, fromBalance, fee, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save transaction to database
+	err = s.repo.CreateInTx(tx, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransferInTx(tx, transaction, fee, changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// checkSpendable rejects a debit that the sender's balance, less any funds held for transfers
+// awaiting settlement, cannot cover or that would take it below the wallet's reserve
+func checkSpendable(balance *repository.WalletBalance, debit float64) error {
+	// Balance arithmetic is done in minor units so results match the stored decimal values
+	currency := string(balance.Currency)
+	availableMinor := money.ToMinor(balance.Balance, currency) - money.ToMinor(balance.Held, currency)
+	debitMinor := money.ToMinor(debit, currency)
+	available := money.Format(money.FromMinor(availableMinor, currency), currency)
+	required := money.Format(money.FromMinor(debitMinor, currency), currency)
+
+	if availableMinor < debitMinor {
+		return errors.NewTransactionError(
+			errors.ErrInsufficientFunds,
+			fmt.Sprintf("insufficient funds: available %s, required %s", available, required),
+		)
+	}
+
+	// The reserve is checked separately so a sender can tell which limit the transfer hit
+	if availableMinor-debitMinor < money.ToMinor(balance.MinBalance, currency) {
+		return errors.NewTransactionError(
+			errors.ErrInsufficientFunds,
+			fmt.Sprintf("transfer would take the balance below the required reserve of %s: available %s, required %s", money.Format(balance.MinBalance, currency), available, required),
+		)
+	}
+
+	return nil
+}
+
+// transferFundsInTx debits the sender's locked balance by the amount and fee, credits the
+// recipient and fee collection wallet, and marks the transaction completed. When the funds
+// were held for the transaction, the hold on the sender's balance is released as they move.
+func (s *TransactionService) transferFundsInTx(tx *sql.Tx, transaction *models.Transaction, fromBalance *repository.WalletBalance, fee float64, held bool) ([]repository.BalanceChange, error) {
+	currency := string(transaction.Currency)
+	amountMinor := money.ToMinor(transaction.Amount, currency)
+	feeMinor := money.ToMinor(fee, currency)
+	totalDebitMinor := amountMinor + feeMinor
+
+	// Lock the recipient balance
+	toBalance, err := s.balanceRepo.GetBalanceForUpdate(tx, transaction.ToWallet, transaction.Currency)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get recipient balance", "transaction-service")
+	}
+
+	// Update balances atomically
+	newFromMinor := money.ToMinor(fromBalance.Balance, currency) - totalDebitMinor
+	newToMinor := money.ToMinor(toBalance.Balance, currency) + amountMinor
+
+	// Credit the fee to the collection wallet (folded into the recipient update if they match)
+	feeWallet := s.feeConfig.CollectionWallet
+	var feeBalance *repository.WalletBalance
+	var newFeeBalance float64
+	if fee > 0 {
+		if feeWallet == transaction.ToWallet {
+			newToMinor += feeMinor
+		} else {
+			feeBalance, err = s.balanceRepo.GetBalanceForUpdate(tx, feeWallet, transaction.Currency)
+			if err != nil {
+				return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to get fee wallet balance", "transaction-service")
+			}
+			newFeeBalance = money.FromMinor(money.ToMinor(feeBalance.Balance, currency)+feeMinor, currency)
 		}
+	}
 
-		err = s.balanceRepo.UpdateBalance(tx, transaction.ToWallet, transaction.Currency, newToBalance)
-		if err != nil {
-			return errors.WrapError(err, errors.ErrTransactionFailed, "failed to update recipient balance", "transaction-service")
+	newFromBalance := money.FromMinor(newFromMinor, currency)
+	newToBalance := money.FromMinor(newToMinor, currency)
+
+	err = s.balanceRepo.UpdateBalance(tx, transaction.FromWallet, transaction.Currency, newFromBalance)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to update sender balance", "transaction-service")
+	}
+
+	if held {
+		newHeld := money.FromMinor(money.ToMinor(fromBalance.Held, currency)-totalDebitMinor, currency)
+		if err := s.balanceRepo.UpdateHeld(tx, transaction.FromWallet, transaction.Currency, newHeld); err != nil {
+			return nil, err
 		}
+	}
 
-		// Publish balance update events (will be sent after transaction commits)
-		go func() {
-			s.publishBalanceUpdateEvent(ctx, transaction.FromWallet, transaction.Currency, fromBalance.Balance, newFromBalance, &transaction.ID)
-			s.publishBalanceUpdateEvent(ctx, transaction.ToWallet, transaction.Currency, toBalance.Balance, newToBalance, &transaction.ID)
-		}()
+	err = s.balanceRepo.UpdateBalance(tx, transaction.ToWallet, transaction.Currency, newToBalance)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to update recipient balance", "transaction-service")
+	}
 
-		// Mark transaction as completed
-		err = transaction.UpdateStatus(models.StatusCompleted, nil, "transaction-service", map[string]interface{}{
-			"from_balance": newFromBalance,
-			"to_balance":   newToBalance,
-		})
+	if feeBalance != nil {
+		err = s.balanceRepo.UpdateBalance(tx, feeWallet, transaction.Currency, newFeeBalance)
 		if err != nil {
-			return err
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, "failed to update fee wallet balance", "transaction-service")
 		}
+	}
 
-		// Save transaction to database
-		err = s.repo.CreateInTx(tx, transaction)
-		if err != nil {
+	// Balance update events are published after the transaction commits
+	changes := []repository.BalanceChange{
+		{WalletID: transaction.FromWallet, OldBalance: fromBalance.Balance, NewBalance: newFromBalance},
+		{WalletID: transaction.ToWallet, OldBalance: toBalance.Balance, NewBalance: newToBalance},
+	}
+	if feeBalance != nil {
+		changes = append(changes, repository.BalanceChange{WalletID: feeWallet, OldBalance: feeBalance.Balance, NewBalance: newFeeBalance})
+	}
+
+	// Mark transaction as completed
+	details := map[string]interface{}{
+		"from_balance": newFromBalance,
+		"to_balance":   newToBalance,
+	}
+	if fee > 0 {
+		details["fee"] = fee
+		details["fee_wallet"] = feeWallet
+	}
+
+	if err := updateTransactionStatus(transaction, models.StatusCompleted, nil, details); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// recordTransferInTx records the fee and balance changes of a saved, completed transaction
+func (s *TransactionService) recordTransferInTx(tx *sql.Tx, transaction *models.Transaction, fee float64, changes []repository.BalanceChange) error {
+	// Record the fee in the ledger
+	if fee > 0 {
+		if err := s.repo.RecordFeeInTx(tx, transaction.ID, s.feeConfig.CollectionWallet, transaction.Currency, fee); err != nil {
 			return err
 		}
+	}
 
-		return nil
-	})
+	// Keep the balance changes so the transaction's events can be re-published by a resync
+	return s.repo.RecordBalanceChangesInTx(tx, transaction.ID, transaction.Currency, changes)
 }
 
 // GetTransaction retrieves a transaction by ID
@@ -194,7 +537,7 @@ func (s *TransactionService) GetTransaction(ctx context.Context, id uuid.UUID) (
 	}
 
 	// Verify audit trail integrity
-	if err := transaction.VerifyIntegrity(); err != nil {
+	if err := s.verifyIntegrity(ctx, transaction); err != nil {
 		return nil, errors.WrapError(err, errors.ErrTransactionFailed, "transaction integrity verification failed", "transaction-service")
 	}
 
@@ -202,7 +545,7 @@ func (s *TransactionService) GetTransaction(ctx context.Context, id uuid.UUID) (
 }
 
 // GetTransactionsByWallet retrieves transactions for a wallet with pagination
-func (s *TransactionService) GetTransactionsByWallet(ctx context.Context, walletID uuid.UUID, limit, offset int) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsByWallet(ctx context.Context, walletID, requester uuid.UUID, limit, offset int) ([]*WalletTransaction, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50 // Default limit
 	}
@@ -217,13 +560,61 @@ func (s *TransactionService) GetTransactionsByWallet(ctx context.Context, wallet
 
 	// Verify integrity of all transactions
 	for _, transaction := range transactions {
-		if err := transaction.VerifyIntegrity(); err != nil {
+		if err := s.verifyIntegrity(ctx, transaction); err != nil {
 			return nil, errors.WrapError(err, errors.ErrTransactionFailed, 
 				fmt.Sprintf("transaction %s integrity verification failed", transaction.ID), "transaction-service")
 		}
 	}
 
-	return transactions, nil
+	return s.walletTransactionViews(transactions, requester)
+}
+
+// WalletTransactionPage is a cursor-paginated page of a wallet's transactions.
+// NextCursor is empty on the last page.
+type WalletTransactionPage struct {
+	Transactions []*WalletTransaction `json:"transactions"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+}
+
+// GetTransactionsByWalletCursor retrieves transactions for a wallet using an opaque cursor
+// returned by a previous page. An empty cursor starts from the newest transaction.
+func (s *TransactionService) GetTransactionsByWalletCursor(ctx context.Context, walletID, requester uuid.UUID, cursor string, limit int) (*WalletTransactionPage, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50 // Default limit
+	}
+
+	var after repository.WalletCursor
+	if cursor != "" {
+		decoded, err := repository.DecodeWalletCursor(cursor)
+		if err != nil {
+			return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "invalid pagination cursor")
+		}
+		after = decoded
+	}
+
+	transactions, next, err := s.repo.GetByWalletCursor(walletID, after.CreatedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify integrity of all transactions
+	for _, transaction := range transactions {
+		if err := s.verifyIntegrity(ctx, transaction); err != nil {
+			return nil, errors.WrapError(err, errors.ErrTransactionFailed, 
+				fmt.Sprintf("transaction %s integrity verification failed", transaction.ID), "transaction-service")
+		}
+	}
+
+	views, err := s.walletTransactionViews(transactions, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WalletTransactionPage{Transactions: views}
+	if next != nil {
+		page.NextCursor = next.Encode()
+	}
+	return page, nil
 }
 
 // UpdateTransactionStatus updates a transaction status (for external services)
@@ -232,8 +623,20 @@ func (s *TransactionService) UpdateTransactionStatus(ctx context.Context, id uui
 	if err != nil {
 		return err
 	}
+	previousStatus := transaction.Status
 
-	err = transaction.UpdateStatus(status, userID, "transaction-service", details)
+	// Held funds must be moved or released along with the status change
+	if previousStatus == models.StatusPending {
+		hold, err := s.balanceRepo.GetHold(id)
+		if err != nil {
+			return err
+		}
+		if hold != nil {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "transaction has funds held for settlement, settle or force-fail it instead")
+		}
+	}
+
+	err = updateTransactionStatus(transaction, status, userID, details)
 	if err != nil {
 		return err
 	}
@@ -242,6 +645,7 @@ func (s *TransactionService) UpdateTransactionStatus(ctx context.Context, id uui
 	if err != nil {
 		return err
 	}
+	logTransactionTransition(ctx, transaction, previousStatus)
 
 	// Publish status update events
 	var eventType events.EventType
@@ -265,16 +669,35 @@ func (s *TransactionService) UpdateTransactionStatus(ctx context.Context, id uui
 	s.publishTransactionEvent(ctx, transaction, eventType)
 	s.statusTracker.PublishStatusUpdate(transaction, message)
 
+	if status == models.StatusReversed {
+		s.webhooks.Dispatch(webhooks.EventTransactionReversed, map[string]interface{}{
+			"transaction_id": transaction.ID,
+			"from_wallet":    transaction.FromWallet,
+			"to_wallet":      transaction.ToWallet,
+			"amount":         transaction.Amount,
+			"currency":       transaction.Currency,
+			"reversed_by":    userID,
+			"details":        details,
+		})
+	}
+
 	return nil
 }
 
-// SetFraudScore sets the fraud score for a transaction
+// SetFraudScore sets the fraud score for a transaction. A score above the auto-freeze
+// threshold first freezes the tokens the transaction moved, and a score above the critical
+// threshold raises an alert once recorded.
 func (s *TransactionService) SetFraudScore(ctx context.Context, id uuid.UUID, score float64, details map[string]interface{}) error {
 	transaction, err := s.repo.GetByID(id)
 	if err != nil {
 		return err
 	}
 
+	details, err = s.autoFreezeTokens(ctx, transaction, score, details)
+	if err != nil {
+		return err
+	}
+
 	oldScore := transaction.FraudScore
 	err = transaction.SetFraudScore(score, "fraud-detection", details)
 	if err != nil {
@@ -286,6 +709,10 @@ func (s *TransactionService) SetFraudScore(ctx context.Context, id uuid.UUID, sc
 		return err
 	}
 
+	s.observeFraudScore(transaction.Currency, score)
+	s.updateWalletRisk(ctx, transaction, score)
+	s.alertHighRisk(ctx, transaction, score, details)
+
 	// Publish fraud score update events
 	s.publishTransactionEvent(ctx, transaction, events.EventFraudScoreUpdated)
 	s.statusTracker.PublishFraudScoreUpdate(transaction, oldScore, &score)
@@ -315,24 +742,235 @@ func (s *TransactionService) GetPendingTransactions(ctx context.Context, limit i
 	return s.repo.GetPendingTransactions(limit)
 }
 
+// ClaimPendingTransactions leases up to limit pending transactions to a worker for
+// leaseDuration. Concurrent workers never receive the same transaction while its lease holds.
+func (s *TransactionService) ClaimPendingTransactions(ctx context.Context, workerID string, limit int, leaseDuration time.Duration) ([]*models.Transaction, error) {
+	if workerID == "" {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "worker ID is required")
+	}
+	if leaseDuration <= 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "lease duration must be positive")
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100 // Default limit
+	}
+
+	return s.repo.ClaimPendingTransactions(workerID, limit, leaseDuration, s.clock.Now())
+}
+
+// ReleaseExpiredClaims makes pending transactions whose lease expired before now claimable again
+func (s *TransactionService) ReleaseExpiredClaims(ctx context.Context, now time.Time) (int, error) {
+	return s.repo.ReleaseExpiredClaims(now)
+}
+
+// processingActiveWindow is how long after a worker starts processing a pending transaction
+// it is considered in progress and protected from being force-failed
+const processingActiveWindow = 5 * time.Minute
+
+// maxForceFailReasonLength caps the reason recorded when a transaction is force-failed
+const maxForceFailReasonLength = 500
+
+// GetStuckTransactions retrieves pending transactions created more than olderThan ago
+func (s *TransactionService) GetStuckTransactions(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Transaction, error) {
+	if olderThan <= 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "stuck threshold must be positive")
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100 // Default limit
+	}
+
+	return s.repo.GetStuckTransactions(s.clock.Now().Add(-olderThan), limit)
+}
+
+// ForceFailTransaction moves a stuck pending transaction to failed, recording the reason in
+// its audit trail. Transactions claimed by a worker, or that a worker started processing
+// within processingActiveWindow, are refused.
+func (s *TransactionService) ForceFailTransaction(ctx context.Context, id uuid.UUID, reason string, userID *uuid.UUID) (*models.Transaction, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "a reason is required to force-fail a transaction")
+	}
+	if len(reason) > maxForceFailReasonLength {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("reason cannot be longer than %d characters", maxForceFailReasonLength))
+	}
+
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != models.StatusPending {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only pending transactions can be force-failed", transaction.Status))
+	}
+
+	err = updateTransactionStatus(transaction, models.StatusFailed, userID, map[string]interface{}{
+		"reason": reason,
+		"forced": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Funds held for the transfer are returned to the sender
+	now := s.clock.Now()
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.repo.ForceFailInTx(tx, transaction, now, now.Add(-processingActiveWindow)); err != nil {
+			return err
+		}
+		return s.releaseHoldInTx(tx, transaction.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logTransactionTransition(ctx, transaction, models.StatusPending, "reason", reason, "forced", true)
+
+	s.publishTransactionEvent(ctx, transaction, events.EventTransactionFailed)
+	s.statusTracker.PublishStatusUpdate(transaction, "Transaction force-failed by administrator")
+
+	return transaction, nil
+}
+
+// resyncBatchSize is the number of completed transactions loaded per page by ResyncSince
+const resyncBatchSize = 100
+
+// ResyncTransaction re-publishes the created, balance update and completed events of a
+// completed transaction, for recovery when the service stopped between committing the
+// transaction and publishing its events. Replayed events are flagged as replays and have
+// stable IDs, so resyncing a transaction more than once is safe for consumers.
+func (s *TransactionService) ResyncTransaction(ctx context.Context, id uuid.UUID) error {
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Status != models.StatusCompleted {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction is %s, only completed transactions can be resynced", transaction.Status))
+	}
+
+	return s.replayTransaction(ctx, transaction)
+}
+
+// ResyncSince re-publishes the events of every completed transaction settled at or after
+// since, oldest first, and returns how many were resynced
+func (s *TransactionService) ResyncSince(ctx context.Context, since time.Time) (int, error) {
+	if since.After(s.clock.Now()) {
+		return 0, errors.NewTransactionError(errors.ErrInvalidTransaction, "resync start cannot be in the future")
+	}
+
+	// uuid.Nil sorts before every transaction ID, so transactions settled exactly at since are included
+	afterSettledAt, afterID := since, uuid.Nil
+	resynced := 0
+	for {
+		transactions, err := s.repo.GetCompletedAfter(afterSettledAt, afterID, resyncBatchSize)
+		if err != nil {
+			return resynced, err
+		}
+
+		for _, transaction := range transactions {
+			if err := ctx.Err(); err != nil {
+				return resynced, err
+			}
+			if err := s.replayTransaction(ctx, transaction); err != nil {
+				return resynced, err
+			}
+			resynced++
+		}
+
+		if len(transactions) < resyncBatchSize {
+			return resynced, nil
+		}
+		last := transactions[len(transactions)-1]
+		afterSettledAt, afterID = *last.SettledAt, last.ID
+	}
+}
+
+// replayTransaction re-publishes a completed transaction's events with its recorded balance changes
+func (s *TransactionService) replayTransaction(ctx context.Context, transaction *models.Transaction) error {
+	if s.eventPublisher == nil {
+		return errors.NewTransactionError(errors.ErrServiceUnavailable, "event publishing is not configured")
+	}
+
+	recorded, err := s.repo.GetBalanceChanges(transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]events.BalanceChange, len(recorded))
+	for i, change := range recorded {
+		changes[i] = events.BalanceChange{
+			WalletID:   change.WalletID,
+			OldBalance: change.OldBalance,
+			NewBalance: change.NewBalance,
+		}
+	}
+
+	return s.eventPublisher.ReplayTransactionEvents(ctx, transaction, changes)
+}
+
 // GetTransactionStats returns transaction statistics for a wallet
 func (s *TransactionService) GetTransactionStats(ctx context.Context, walletID uuid.UUID, since time.Time) (*repository.TransactionStats, error) {
 	return s.repo.GetTransactionStats(walletID, since)
 }
 
+// archiveTimeout bounds an archival run, and so the statement timeout of each of its batches
+const archiveTimeout = 30 * time.Minute
+
+// ArchiveTransactions moves settled transactions older than before into the archive tables.
+// Archived transactions remain retrievable through GetTransaction.
+func (s *TransactionService) ArchiveTransactions(ctx context.Context, before time.Time) (int, error) {
+	if before.After(s.clock.Now()) {
+		return 0, errors.NewTransactionError(errors.ErrInvalidTransaction, "archive cutoff cannot be in the future")
+	}
+
+	// Archival moves large batches, so it runs with a longer statement timeout than requests
+	ctx, cancel := context.WithTimeout(ctx, archiveTimeout)
+	defer cancel()
+
+	return s.repo.ArchiveTransactions(ctx, before)
+}
+
 // GetServiceMetrics returns service performance metrics
 func (s *TransactionService) GetServiceMetrics() *TransactionMetrics {
 	s.metrics.mutex.RLock()
 	defer s.metrics.mutex.RUnlock()
 
-	return &TransactionMetrics{
+	result := &TransactionMetrics{
 		ProcessingTimes: append([]time.Duration{}, s.metrics.ProcessingTimes...), // Copy slice
 		SuccessCount:    s.metrics.SuccessCount,
 		FailureCount:    s.metrics.FailureCount,
 	}
+
+	// Sort a separate copy so the returned samples keep their recorded order
+	if len(result.ProcessingTimes) > 0 {
+		sorted := append([]time.Duration{}, result.ProcessingTimes...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		result.P50 = percentile(sorted, 50)
+		result.P95 = percentile(sorted, 95)
+		result.P99 = percentile(sorted, 99)
+		result.Max = sorted[len(sorted)-1]
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile (1-100) of an ascending, non-empty slice
+func percentile(sorted []time.Duration, pct int) time.Duration {
+	rank := (pct*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
 }
 
-// validateTransactionRequest validates the transaction request
+// Limits on the free-text transaction metadata persisted with each transaction
+const (
+	MaxDescriptionLength  = 256
+	MaxCategoryLength     = 64
+	MaxTransferNoteLength = 256
+)
+
+// validateTransactionRequest validates the transaction request, stripping control characters
+// from its metadata, and checks that the sender's transfer policy permits the recipient
 func (s *TransactionService) validateTransactionRequest(req *TransactionRequest) error {
 	if req.FromWallet == req.ToWallet {
 		return errors.NewTransactionError(errors.ErrInvalidTransaction, "cannot transfer to the same wallet")
@@ -351,17 +989,73 @@ func (s *TransactionService) validateTransactionRequest(req *TransactionRequest)
 	}
 
 	// Validate currency
-	validCurrencies := map[models.Currency]bool{
-		models.USDCBDC: true,
-		models.EURCBDC: true,
-		models.GBPCBDC: true,
+	if !s.currencies.Supported(string(req.Currency)) {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", req.Currency))
 	}
 
-	if !validCurrencies[req.Currency] {
-		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", req.Currency))
+	if !money.IsExact(req.Amount, string(req.Currency)) {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transaction amount has more than %d decimal places", money.Decimals(string(req.Currency))))
 	}
 
-	return nil
+	req.Metadata.Description = sanitizeMetadataText(req.Metadata.Description)
+	req.Metadata.Category = sanitizeMetadataText(req.Metadata.Category)
+
+	if utf8.RuneCountInString(req.Metadata.Description) > MaxDescriptionLength {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("description cannot be longer than %d characters", MaxDescriptionLength))
+	}
+
+	if utf8.RuneCountInString(req.Metadata.Category) > MaxCategoryLength {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("category cannot be longer than %d characters", MaxCategoryLength))
+	}
+
+	if !s.categoryAllowed(req.Metadata.Category) {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unknown transaction category: %s", req.Metadata.Category))
+	}
+
+	req.SenderNote = sanitizeMetadataText(req.SenderNote)
+	req.RecipientNote = sanitizeMetadataText(req.RecipientNote)
+
+	if utf8.RuneCountInString(req.SenderNote) > MaxTransferNoteLength || utf8.RuneCountInString(req.RecipientNote) > MaxTransferNoteLength {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("transfer notes cannot be longer than %d characters", MaxTransferNoteLength))
+	}
+
+	// Stored values with the encryption prefix are read back as ciphertext
+	for _, value := range []string{req.Metadata.Description, req.SenderNote, req.RecipientNote} {
+		if repository.IsReservedValue(value) {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "description and transfer notes cannot start with the reserved prefix \"enc:\"")
+		}
+	}
+
+	return s.checkTransferPolicy(req.FromWallet, req.ToWallet)
+}
+
+// sanitizeMetadataText strips control characters from a free-text metadata value
+func sanitizeMetadataText(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// calculateFee returns the fee for a transaction, or zero when fees are disabled
+func (s *TransactionService) calculateFee(transaction *models.Transaction) float64 {
+	if !s.feeConfig.Enabled || s.feeConfig.Calculator == nil || s.feeConfig.CollectionWallet == uuid.Nil {
+		return 0
+	}
+
+	// The fee wallet doesn't pay fees to itself
+	if transaction.FromWallet == s.feeConfig.CollectionWallet {
+		return 0
+	}
+
+	return s.feeConfig.Calculator.Calculate(transaction)
+}
+
+// GetTransactionFee retrieves the fee ledger entry for a transaction
+func (s *TransactionService) GetTransactionFee(ctx context.Context, transactionID uuid.UUID) (*repository.FeeEntry, error) {
+	return s.repo.GetFee(transactionID)
 }
 
 // recordProcessingTime records the processing time for metrics
@@ -371,12 +1065,63 @@ func (s *TransactionService) recordProcessingTime(duration time.Duration) {
 
 	s.metrics.ProcessingTimes = append(s.metrics.ProcessingTimes, duration)
 	
-	// Keep only the last 1000 measurements
-	if len(s.metrics.ProcessingTimes) > 1000 {
+	// Keep only the last maxProcessingSamples measurements
+	if len(s.metrics.ProcessingTimes) > maxProcessingSamples {
 		s.metrics.ProcessingTimes = s.metrics.ProcessingTimes[1:]
 	}
 }
 
+// observeTransaction exports a processed transaction's latency and outcome to Prometheus
+func (s *TransactionService) observeTransaction(currency models.Currency, outcome string, duration time.Duration) {
+	if s.promMetrics == nil {
+		return
+	}
+
+	s.promMetrics.RecordTransactionOutcome(s.currencyLabel(currency), outcome, duration)
+}
+
+// observeAmount exports a completed transaction's amount to Prometheus
+func (s *TransactionService) observeAmount(currency models.Currency, amount float64) {
+	if s.promMetrics == nil {
+		return
+	}
+
+	s.promMetrics.RecordTransactionAmount(s.currencyLabel(currency), amount)
+}
+
+// observeFraudScore exports a transaction's fraud score to Prometheus
+func (s *TransactionService) observeFraudScore(currency models.Currency, score float64) {
+	if s.promMetrics == nil {
+		return
+	}
+
+	s.promMetrics.RecordTransactionFraudScore(s.currencyLabel(currency), score)
+}
+
+// currencyLabel returns the Prometheus label for a currency. Unsupported currencies share one
+// label value to keep label cardinality bounded to the configured currency set.
+func (s *TransactionService) currencyLabel(currency models.Currency) string {
+	label := string(currency)
+	if !s.currencies.Supported(label) {
+		return "unknown"
+	}
+	return label
+}
+
+// GetLatencyPercentiles returns p50, p95 and p99 processing latency estimated from the
+// Prometheus histogram, or nil when Prometheus metrics are not enabled
+func (s *TransactionService) GetLatencyPercentiles() map[string]time.Duration {
+	if s.promMetrics == nil {
+		return nil
+	}
+
+	return map[string]time.Duration{
+		"p50": s.promMetrics.TransactionLatencyQuantile(0.50),
+		"p95": s.promMetrics.TransactionLatencyQuantile(0.95),
+		"p99": s.promMetrics.TransactionLatencyQuantile(0.99),
+	}
+}
+
 // recordSuccess increments the success counter
 func (s *TransactionService) recordSuccess() {
 	s.metrics.mutex.Lock()
@@ -401,6 +1146,17 @@ func (s *TransactionService) publishTransactionEvent(ctx context.Context, transa
 	}
 }
 
+// publishTransactionCreatedEvent publishes a transaction created event with the transfer's
+// deviation from its sender's baseline
+func (s *TransactionService) publishTransactionCreatedEvent(ctx context.Context, transaction *models.Transaction, baselineDeviation *float64) {
+	if s.eventPublisher != nil {
+		if err := s.eventPublisher.PublishTransactionCreatedEvent(ctx, transaction, baselineDeviation); err != nil {
+			// Log error but don't fail the transaction
+			// TODO: Add proper logging
+		}
+	}
+}
+
 // publishBalanceUpdateEvent publishes a balance update event
 func (s *TransactionService) publishBalanceUpdateEvent(ctx context.Context, walletID uuid.UUID, currency models.Currency, oldBalance, newBalance float64, transactionID *uuid.UUID) {
 	if s.eventPublisher != nil {
@@ -431,5 +1187,11 @@ func (s *TransactionService) Migrate() error {
 	if err := s.repo.Migrate(); err != nil {
 		return err
 	}
-	return s.balanceRepo.Migrate()
+	if err := s.balanceRepo.Migrate(); err != nil {
+		return err
+	}
+	if err := s.walletRepo.Migrate(); err != nil {
+		return err
+	}
+	return s.repo.MigrateAmountScale()
 }
\ No newline at end of file
@@ -2,16 +2,26 @@ package service
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	
+	"echopay/shared/libraries/clock"
+	"echopay/shared/libraries/currency"
 	"echopay/shared/libraries/database"
 	"echopay/shared/libraries/errors"
+	sharedhttp "echopay/shared/libraries/http"
+	"echopay/shared/libraries/monitoring"
+	"echopay/transaction-service/src/events"
 	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
 )
 
 func setupTestDB(t *testing.T) *database.PostgresDB {
@@ -35,19 +45,19 @@ func setupTestService(t *testing.T) (*TransactionService, *database.PostgresDB)
 	return service, db
 }
 
-func createTestWallets(t *testing.T, service *TransactionService) (uuid.UUID, uuid.UUID) {
-	fromWallet := uuid.New()
-	toWallet := uuid.New()
-	
-	// Create wallets with initial balances
-	err := service.balanceRepo.CreateWallet(fromWallet)
+func createTestWallet(t *testing.T, service *TransactionService) uuid.UUID {
+	wallet, err := service.CreateWallet(context.Background(), &CreateWalletRequest{OwnerID: uuid.New()})
 	require.NoError(t, err)
 	
-	err = service.balanceRepo.CreateWallet(toWallet)
-	require.NoError(t, err)
+	return wallet.ID
+}
+
+func createTestWallets(t *testing.T, service *TransactionService) (uuid.UUID, uuid.UUID) {
+	fromWallet := createTestWallet(t, service)
+	toWallet := createTestWallet(t, service)
 	
 	// Add funds to sender wallet
-	err = service.balanceRepo.AddFunds(fromWallet, models.USDCBDC, 1000.0)
+	err := service.balanceRepo.AddFunds(fromWallet, models.USDCBDC, 1000.0)
 	require.NoError(t, err)
 	
 	return fromWallet, toWallet
@@ -113,6 +123,7 @@ func TestTransactionService_ProcessTransaction_InsufficientFunds(t *testing.T) {
 	echoPayErr, ok := err.(*errors.EchoPayError)
 	assert.True(t, ok)
 	assert.Equal(t, errors.ErrInsufficientFunds, echoPayErr.Code)
+	assert.NotContains(t, echoPayErr.Message, "reserve")
 	
 	// Verify balances were not changed
 	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
@@ -120,6 +131,205 @@ func TestTransactionService_ProcessTransaction_InsufficientFunds(t *testing.T) {
 	assert.Equal(t, 1000.0, fromBalance.Balance)
 }
 
+func TestTransactionService_ProcessTransaction_UnknownWallet(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, _ := createTestWallets(t, service)
+	
+	// Transfers to a wallet that was never created are rejected instead of creating it
+	ctx := context.Background()
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   uuid.New(),
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	
+	assert.Nil(t, transaction)
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrWalletNotFound, echoPayErr.Code)
+	
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+}
+
+func TestTransactionService_ProcessTransaction_ClosedWallet(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	
+	// A wallet holding funds cannot be closed
+	_, err := service.CloseWallet(ctx, fromWallet)
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+	
+	closed, err := service.CloseWallet(ctx, toWallet)
+	require.NoError(t, err)
+	assert.Equal(t, repository.WalletStatusClosed, closed.Status)
+	
+	wallet, err := service.GetWallet(ctx, toWallet)
+	require.NoError(t, err)
+	assert.Equal(t, repository.WalletStatusClosed, wallet.Status)
+	
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	
+	assert.Nil(t, transaction)
+	echoPayErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+	assert.Contains(t, echoPayErr.Message, "closed")
+	
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+}
+
+func TestTransactionService_FundWallet(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	walletID := createTestWallet(t, service)
+	ctx := context.Background()
+	externalRef := "mint-" + uuid.New().String()
+	
+	result, err := service.FundWallet(ctx, walletID, models.USDCBDC, 400.0, externalRef)
+	require.NoError(t, err)
+	assert.True(t, result.Credited)
+	assert.Equal(t, 400.0, result.Balance.Balance)
+	
+	// Retrying the funding reports the balance without crediting it twice
+	result, err = service.FundWallet(ctx, walletID, models.USDCBDC, 400.0, externalRef)
+	require.NoError(t, err)
+	assert.False(t, result.Credited)
+	assert.Equal(t, 400.0, result.Balance.Balance)
+	
+	for _, tc := range []struct {
+		name        string
+		walletID    uuid.UUID
+		amount      float64
+		externalRef string
+	}{
+		{"missing reference", walletID, 10.0, "  "},
+		{"reference too long", walletID, 10.0, strings.Repeat("x", MaxFundingRefLength+1)},
+		{"non-positive amount", walletID, 0, "ref-1"},
+		{"nil wallet", uuid.Nil, 10.0, "ref-1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.FundWallet(ctx, tc.walletID, models.USDCBDC, tc.amount, tc.externalRef)
+			echoPayErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+		})
+	}
+}
+
+func TestTransactionService_EmergencyFreezeWallet(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	freezer := &recordingTokenFreezer{}
+	service.SetTokenFreezer(freezer)
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	req := &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	}
+	
+	// A reason is required
+	_, err := service.EmergencyFreezeWallet(ctx, toWallet, "  ")
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+	
+	frozen, err := service.EmergencyFreezeWallet(ctx, toWallet, "device compromised")
+	require.NoError(t, err)
+	assert.Equal(t, repository.WalletStatusFrozen, frozen.Status)
+	assert.Equal(t, "device compromised", frozen.StatusReason)
+	assert.Equal(t, []uuid.UUID{toWallet}, freezer.frozen)
+	
+	// Transfers to and from the frozen wallet are blocked
+	transaction, err := service.ProcessTransaction(ctx, req)
+	assert.Nil(t, transaction)
+	echoPayErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrWalletFrozen, echoPayErr.Code)
+	
+	_, err = service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: toWallet,
+		ToWallet:   fromWallet,
+		Amount:     1.0,
+		Currency:   models.USDCBDC,
+	})
+	echoPayErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrWalletFrozen, echoPayErr.Code)
+	
+	_, err = service.EmergencyFreezeWallet(ctx, toWallet, "again")
+	echoPayErr, ok = err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrWalletFrozen, echoPayErr.Code)
+	
+	// Recovery needs a verifier
+	_, err = service.RecoverWallet(ctx, toWallet, uuid.Nil)
+	assert.Error(t, err)
+	
+	recovered, err := service.RecoverWallet(ctx, toWallet, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, repository.WalletStatusActive, recovered.Status)
+	assert.Equal(t, []uuid.UUID{toWallet}, freezer.unfrozen)
+	
+	transaction, err = service.ProcessTransaction(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, transaction.Status)
+	
+	// Recovering a wallet that is not frozen fails
+	_, err = service.RecoverWallet(ctx, toWallet, uuid.New())
+	assert.Error(t, err)
+}
+
+func TestTransactionService_EmergencyFreezeWallet_TokenFreezeFails(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	service.SetTokenFreezer(&recordingTokenFreezer{fail: true})
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+	
+	_, err := service.EmergencyFreezeWallet(ctx, fromWallet, "device compromised")
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrServiceUnavailable, echoPayErr.Code)
+	
+	// The wallet freeze was rolled back with the failed token freeze
+	wallet, err := service.GetWallet(ctx, fromWallet)
+	require.NoError(t, err)
+	assert.Equal(t, repository.WalletStatusActive, wallet.Status)
+	
+	_, err = service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	assert.NoError(t, err)
+}
+
 func TestTransactionService_ProcessTransaction_InvalidRequest(t *testing.T) {
 	service, db := setupTestService(t)
 	defer db.Close()
@@ -275,6 +485,17 @@ func TestTransactionService_UpdateTransactionStatus(t *testing.T) {
 	assert.Equal(t, string(models.StatusCompleted), lastEntry.PreviousState)
 	assert.Equal(t, string(models.StatusReversed), lastEntry.NewState)
 	assert.Equal(t, &userID, lastEntry.UserID)
+	
+	// A reversed transaction is final
+	err = service.UpdateTransactionStatus(ctx, transaction.ID, models.StatusPending, &userID, nil)
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+	
+	unchanged, err := service.GetTransaction(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusReversed, unchanged.Status)
+	assert.Len(t, unchanged.GetAuditTrail(), len(auditTrail))
 }
 
 func TestTransactionService_SetFraudScore(t *testing.T) {
@@ -318,6 +539,49 @@ func TestTransactionService_SetFraudScore(t *testing.T) {
 	assert.Equal(t, "fraud-detection", fraudScoreEntry.ServiceID)
 }
 
+func TestTransactionService_ProcessTransaction_BelowReserve(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	
+	ctx := context.Background()
+	_, err := service.SetWalletMinBalance(ctx, fromWallet, models.USDCBDC, 200.0)
+	require.NoError(t, err)
+	
+	// The sender holds enough to cover the transfer, but not while keeping its reserve
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     900.0,
+		Currency:   models.USDCBDC,
+	})
+	
+	assert.Nil(t, transaction)
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInsufficientFunds, echoPayErr.Code)
+	assert.Contains(t, echoPayErr.Message, "reserve")
+	
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+	assert.Equal(t, 200.0, fromBalance.MinBalance)
+	
+	// A transfer leaving exactly the reserve is allowed
+	_, err = service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     800.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+	
+	fromBalance, err = service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, fromBalance.Balance)
+}
+
 func TestTransactionService_GetTransactionsByWallet(t *testing.T) {
 	service, db := setupTestService(t)
 	defer db.Close()
@@ -340,7 +604,7 @@ func TestTransactionService_GetTransactionsByWallet(t *testing.T) {
 	}
 	
 	// Get transactions for sender wallet
-	transactions, err := service.GetTransactionsByWallet(ctx, fromWallet, 10, 0)
+	transactions, err := service.GetTransactionsByWallet(ctx, fromWallet, fromWallet, 10, 0)
 	
 	assert.NoError(t, err)
 	assert.Len(t, transactions, 5)
@@ -381,6 +645,226 @@ func TestTransactionService_GetPendingTransactions(t *testing.T) {
 	assert.Equal(t, models.StatusPending, pendingTransactions[0].Status)
 }
 
+func TestTransactionService_ForceFailTransaction(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	// Simulate a transaction orphaned in pending by a crashed worker
+	transaction, err := models.NewTransaction(
+		uuid.New(),
+		uuid.New(),
+		100.0,
+		models.USDCBDC,
+		models.TransactionMetadata{},
+	)
+	require.NoError(t, err)
+	transaction.CreatedAt = time.Now().Add(-time.Hour)
+	
+	err = service.repo.Create(transaction)
+	require.NoError(t, err)
+	
+	ctx := context.Background()
+	stuck, err := service.GetStuckTransactions(ctx, 30*time.Minute, 1000)
+	require.NoError(t, err)
+	assert.Contains(t, transactionIDs(stuck), transaction.ID)
+	
+	// A reason is required
+	_, err = service.ForceFailTransaction(ctx, transaction.ID, " ", nil)
+	assert.Error(t, err)
+	_, err = service.ForceFailTransaction(ctx, transaction.ID, strings.Repeat("x", maxForceFailReasonLength+1), nil)
+	assert.Error(t, err)
+	
+	adminID := uuid.New()
+	failed, err := service.ForceFailTransaction(ctx, transaction.ID, "orphaned by worker crash", &adminID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+	
+	saved, err := service.GetTransaction(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, saved.Status)
+	
+	lastEntry := saved.AuditTrail[len(saved.AuditTrail)-1]
+	assert.Equal(t, string(models.StatusPending), lastEntry.PreviousState)
+	assert.Equal(t, string(models.StatusFailed), lastEntry.NewState)
+	assert.Equal(t, "orphaned by worker crash", lastEntry.Details["reason"])
+	assert.Equal(t, &adminID, lastEntry.UserID)
+	
+	// Already failed transactions can't be force-failed again
+	_, err = service.ForceFailTransaction(ctx, transaction.ID, "orphaned by worker crash", &adminID)
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
+
+func TestTransactionService_ForceFailTransaction_ProcessingWindow(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	transaction, err := models.NewTransaction(
+		uuid.New(),
+		uuid.New(),
+		100.0,
+		models.USDCBDC,
+		models.TransactionMetadata{},
+	)
+	require.NoError(t, err)
+	
+	err = service.repo.Create(transaction)
+	require.NoError(t, err)
+	
+	// Postgres stores microseconds, so the window edges are one microsecond apart
+	startedAt := time.Now().UTC().Truncate(time.Microsecond)
+	err = service.repo.MarkProcessingStarted(transaction.ID, startedAt)
+	require.NoError(t, err)
+	
+	fakeClock := clock.NewFake(startedAt.Add(processingActiveWindow - time.Microsecond))
+	service.SetClock(fakeClock)
+	ctx := context.Background()
+	
+	_, err = service.ForceFailTransaction(ctx, transaction.ID, "worker stopped responding", nil)
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrConcurrentModification, transactionErr.Code)
+	
+	fakeClock.Advance(time.Microsecond)
+	failed, err := service.ForceFailTransaction(ctx, transaction.ID, "worker stopped responding", nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, failed.Status)
+}
+
+func TestTransactionService_ArchiveTransactions_FutureCutoff(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	service := &TransactionService{clock: clock.NewFake(now)}
+	
+	_, err := service.ArchiveTransactions(context.Background(), now.Add(time.Nanosecond))
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
+
+func TestTransactionService_GetBalancesForWallets_Validation(t *testing.T) {
+	// Validation fails before the repository is used
+	service := &TransactionService{currencies: currency.NewDefaultRegistry()}
+	
+	tooMany := make([]uuid.UUID, MaxBalanceLookupWallets+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+	
+	invalid := map[string][]uuid.UUID{
+		"no wallets":    {},
+		"too many":      tooMany,
+		"nil wallet ID": {uuid.New(), uuid.Nil},
+	}
+	for name, walletIDs := range invalid {
+		_, err := service.GetBalancesForWallets(context.Background(), walletIDs, models.USDCBDC)
+		transactionErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok, name)
+		assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code, name)
+	}
+	
+	_, err := service.GetBalancesForWallets(context.Background(), []uuid.UUID{uuid.New()}, "XYZ-CBDC")
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
+
+func TestTransactionService_ValidateTransactionRequest_Metadata(t *testing.T) {
+	service := &TransactionService{currencies: currency.NewDefaultRegistry()}
+	newRequest := func(metadata models.TransactionMetadata) *TransactionRequest {
+		return &TransactionRequest{
+			FromWallet: uuid.New(),
+			ToWallet:   uuid.New(),
+			Amount:     10.0,
+			Currency:   models.USDCBDC,
+			Metadata:   metadata,
+		}
+	}
+	
+	t.Run("over-long description is rejected", func(t *testing.T) {
+		err := service.validateTransactionRequest(newRequest(models.TransactionMetadata{
+			Description: strings.Repeat("x", MaxDescriptionLength+1),
+		}))
+		transactionErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+		assert.Contains(t, transactionErr.Message, "description")
+	})
+	
+	t.Run("control characters are stripped", func(t *testing.T) {
+		req := newRequest(models.TransactionMetadata{
+			Description: "rent\x00 for\r\n march\x1b[31m",
+			Category:    "\x07housing\u009b",
+		})
+		require.NoError(t, service.validateTransactionRequest(req))
+		assert.Equal(t, "rent for march[31m", req.Metadata.Description)
+		assert.Equal(t, "housing", req.Metadata.Category)
+	})
+	
+	t.Run("reserved encryption prefix is rejected", func(t *testing.T) {
+		for _, req := range []*TransactionRequest{
+			newRequest(models.TransactionMetadata{Description: "enc:v1:meta-1:AAAA"}),
+			{FromWallet: uuid.New(), ToWallet: uuid.New(), Amount: 10.0, Currency: models.USDCBDC, SenderNote: "enc:x"},
+			{FromWallet: uuid.New(), ToWallet: uuid.New(), Amount: 10.0, Currency: models.USDCBDC, RecipientNote: "enc:x"},
+		} {
+			err := service.validateTransactionRequest(req)
+			transactionErr, ok := err.(*errors.EchoPayError)
+			require.True(t, ok)
+			assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+			assert.Contains(t, transactionErr.Message, "reserved prefix")
+		}
+		
+		require.NoError(t, service.validateTransactionRequest(newRequest(models.TransactionMetadata{Description: "encore tickets"})))
+	})
+}
+
+func TestTransactionService_ResyncTransaction(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	
+	ctx := context.Background()
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	})
+	require.NoError(t, err)
+	
+	// The balance changes replayed by a resync match the original update
+	changes, err := service.repo.GetBalanceChanges(transaction.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, repository.BalanceChange{WalletID: fromWallet, OldBalance: 1000.0, NewBalance: 900.0}, changes[0])
+	assert.Equal(t, repository.BalanceChange{WalletID: toWallet, OldBalance: 0.0, NewBalance: 100.0}, changes[1])
+	
+	require.NoError(t, service.ResyncTransaction(ctx, transaction.ID))
+	
+	resynced, err := service.ResyncSince(ctx, *transaction.SettledAt)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, resynced, 1)
+	
+	// Only completed transactions can be resynced
+	pending, err := models.NewTransaction(fromWallet, toWallet, 10.0, models.USDCBDC, models.TransactionMetadata{})
+	require.NoError(t, err)
+	require.NoError(t, service.repo.Create(pending))
+	
+	err = service.ResyncTransaction(ctx, pending.ID)
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
+
+func transactionIDs(transactions []*models.Transaction) []uuid.UUID {
+	ids := make([]uuid.UUID, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+	return ids
+}
+
 func TestTransactionService_PerformanceMetrics(t *testing.T) {
 	service, db := setupTestService(t)
 	defer db.Close()
@@ -416,6 +900,93 @@ func TestTransactionService_PerformanceMetrics(t *testing.T) {
 	}
 }
 
+func TestTransactionService_GetServiceMetricsPercentiles(t *testing.T) {
+	t.Run("known durations", func(t *testing.T) {
+		// Record 100ms down to 1ms so the buffer is not already sorted
+		var processingTimes []time.Duration
+		for i := 100; i >= 1; i-- {
+			processingTimes = append(processingTimes, time.Duration(i)*time.Millisecond)
+		}
+		service := &TransactionService{metrics: &TransactionMetrics{ProcessingTimes: processingTimes}}
+
+		metrics := service.GetServiceMetrics()
+
+		assert.Equal(t, 50*time.Millisecond, metrics.P50)
+		assert.Equal(t, 95*time.Millisecond, metrics.P95)
+		assert.Equal(t, 99*time.Millisecond, metrics.P99)
+		assert.Equal(t, 100*time.Millisecond, metrics.Max)
+
+		// The live buffer must keep its recorded order
+		assert.Equal(t, 100*time.Millisecond, service.metrics.ProcessingTimes[0])
+		assert.Equal(t, 1*time.Millisecond, service.metrics.ProcessingTimes[99])
+	})
+
+	t.Run("empty buffer", func(t *testing.T) {
+		service := &TransactionService{metrics: &TransactionMetrics{}}
+
+		metrics := service.GetServiceMetrics()
+
+		assert.Zero(t, metrics.P50)
+		assert.Zero(t, metrics.P95)
+		assert.Zero(t, metrics.P99)
+		assert.Zero(t, metrics.Max)
+	})
+
+	t.Run("window keeps last 1000 samples", func(t *testing.T) {
+		service := &TransactionService{metrics: &TransactionMetrics{}}
+		for i := 1; i <= maxProcessingSamples+10; i++ {
+			service.recordProcessingTime(time.Duration(i) * time.Millisecond)
+		}
+
+		metrics := service.GetServiceMetrics()
+
+		assert.Len(t, metrics.ProcessingTimes, maxProcessingSamples)
+		assert.Equal(t, time.Duration(maxProcessingSamples+10)*time.Millisecond, metrics.Max)
+	})
+}
+
+func TestTransactionService_CurrencyMetrics(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	// Collectors register globally, so this is the only test that creates them
+	service.SetPrometheusMetrics(monitoring.NewMetrics("transaction-service-test"))
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	err := service.balanceRepo.AddFunds(fromWallet, models.EURCBDC, 1000.0)
+	require.NoError(t, err)
+	
+	ctx := context.Background()
+	for _, req := range []*TransactionRequest{
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 100.0, Currency: models.USDCBDC},
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 50.0, Currency: models.USDCBDC},
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 75.0, Currency: models.EURCBDC},
+	} {
+		transaction, err := service.ProcessTransaction(ctx, req)
+		require.NoError(t, err)
+		require.NoError(t, service.SetFraudScore(ctx, transaction.ID, 0.25, nil))
+	}
+	
+	// Multi-transfer legs are recorded like single transfers
+	_, err = service.ProcessAtomicMultiTransfer(ctx, []TransferLeg{
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 25.0, Currency: models.USDCBDC},
+		{FromWallet: fromWallet, ToWallet: toWallet, Amount: 10.0, Currency: models.EURCBDC},
+	})
+	require.NoError(t, err)
+	
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/metrics", sharedhttp.MetricsHandler())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	
+	body := w.Body.String()
+	assert.Contains(t, body, `echopay_transaction_amount_sum{currency="USD-CBDC",service="transaction-service-test"} 175`)
+	assert.Contains(t, body, `echopay_transaction_amount_sum{currency="EUR-CBDC",service="transaction-service-test"} 85`)
+	assert.Contains(t, body, `echopay_transaction_fraud_score_count{currency="USD-CBDC",service="transaction-service-test"} 2`)
+	assert.Contains(t, body, `echopay_transaction_fraud_score_count{currency="EUR-CBDC",service="transaction-service-test"} 1`)
+}
+
 func TestTransactionService_ConcurrentTransactions(t *testing.T) {
 	service, db := setupTestService(t)
 	defer db.Close()
@@ -503,4 +1074,252 @@ func TestTransactionService_AuditTrailIntegrity(t *testing.T) {
 	assert.Equal(t, "STATUS_CHANGE", lastEntry.Action)
 	assert.Equal(t, string(models.StatusPending), lastEntry.PreviousState)
 	assert.Equal(t, string(models.StatusCompleted), lastEntry.NewState)
-}
\ No newline at end of file
+}
+func TestTransactionService_ProcessTransaction_WithFees(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	feeWallet := uuid.New()
+	require.NoError(t, service.balanceRepo.CreateWallet(feeWallet))
+	
+	service.SetFeeConfig(FeeConfig{
+		Enabled:          true,
+		CollectionWallet: feeWallet,
+		Calculator:       NewTieredFeeCalculator(0.50, 0.01, 10.0),
+	})
+	
+	req := &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     100.0,
+		Currency:   models.USDCBDC,
+	}
+	
+	ctx := context.Background()
+	transaction, err := service.ProcessTransaction(ctx, req)
+	require.NoError(t, err)
+	
+	// Fee is 0.50 flat + 1% of 100 = 1.50
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0-100.0-1.50, fromBalance.Balance)
+	
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, toBalance.Balance)
+	
+	feeBalance, err := service.GetWalletBalance(ctx, feeWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1.50, feeBalance.Balance)
+	
+	fee, err := service.GetTransactionFee(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1.50, fee.Amount)
+	assert.Equal(t, feeWallet, fee.FeeWallet)
+}
+
+func TestTransactionService_ProcessTransaction_FeeCausesInsufficientFunds(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	fromWallet, toWallet := createTestWallets(t, service)
+	feeWallet := uuid.New()
+	require.NoError(t, service.balanceRepo.CreateWallet(feeWallet))
+	
+	service.SetFeeConfig(FeeConfig{
+		Enabled:          true,
+		CollectionWallet: feeWallet,
+		Calculator:       NewTieredFeeCalculator(1.0, 0, 0),
+	})
+	
+	// Entire balance leaves nothing for the fee
+	req := &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     1000.0,
+		Currency:   models.USDCBDC,
+	}
+	
+	ctx := context.Background()
+	_, err := service.ProcessTransaction(ctx, req)
+	require.Error(t, err)
+	
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInsufficientFunds, echoPayErr.Code)
+	
+	fromBalance, err := service.GetWalletBalance(ctx, fromWallet, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, fromBalance.Balance)
+}
+
+func TestTransactionService_ProcessAtomicMultiTransfer_Success(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	walletA, walletB := createTestWallets(t, service)
+	walletC := createTestWallet(t, service)
+	
+	legs := []TransferLeg{
+		{FromWallet: walletA, ToWallet: walletB, Amount: 300.0, Currency: models.USDCBDC},
+		{FromWallet: walletA, ToWallet: walletC, Amount: 200.0, Currency: models.USDCBDC},
+		{FromWallet: walletB, ToWallet: walletC, Amount: 100.0, Currency: models.USDCBDC},
+	}
+	
+	ctx := context.Background()
+	result, err := service.ProcessAtomicMultiTransfer(ctx, legs)
+	require.NoError(t, err)
+	assert.Len(t, result.Transactions, 3)
+	
+	for _, transaction := range result.Transactions {
+		assert.Equal(t, models.StatusCompleted, transaction.Status)
+	}
+	
+	balanceA, err := service.GetWalletBalance(ctx, walletA, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, balanceA.Balance)
+	
+	balanceB, err := service.GetWalletBalance(ctx, walletB, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, balanceB.Balance)
+	
+	balanceC, err := service.GetWalletBalance(ctx, walletC, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 300.0, balanceC.Balance)
+	
+	// All legs share the correlation ID
+	correlated, err := service.GetTransactionsByCorrelationID(ctx, result.CorrelationID)
+	require.NoError(t, err)
+	assert.Len(t, correlated, 3)
+	
+	// Each leg records its own balance changes, as applied in order, so it can be resynced
+	changes, err := service.repo.GetBalanceChanges(result.Transactions[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []repository.BalanceChange{
+		{WalletID: walletB, OldBalance: 300.0, NewBalance: 200.0},
+		{WalletID: walletC, OldBalance: 200.0, NewBalance: 300.0},
+	}, changes)
+	assert.NoError(t, service.ResyncTransaction(ctx, result.Transactions[2].ID))
+}
+
+func TestTransactionService_ProcessAtomicMultiTransfer_LastLegFails(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	walletA, walletB := createTestWallets(t, service)
+	walletC := createTestWallet(t, service)
+	
+	legs := []TransferLeg{
+		{FromWallet: walletA, ToWallet: walletB, Amount: 300.0, Currency: models.USDCBDC},
+		{FromWallet: walletA, ToWallet: walletC, Amount: 400.0, Currency: models.USDCBDC},
+		{FromWallet: walletB, ToWallet: walletC, Amount: 500.0, Currency: models.USDCBDC}, // B only holds 300
+	}
+	
+	ctx := context.Background()
+	result, err := service.ProcessAtomicMultiTransfer(ctx, legs)
+	require.Error(t, err)
+	assert.Nil(t, result)
+	
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInsufficientFunds, echoPayErr.Code)
+	
+	// Every balance is unchanged
+	balanceA, err := service.GetWalletBalance(ctx, walletA, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, balanceA.Balance)
+	
+	balanceB, err := service.GetWalletBalance(ctx, walletB, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, balanceB.Balance)
+	
+	balanceC, err := service.GetWalletBalance(ctx, walletC, models.USDCBDC)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, balanceC.Balance)
+}
+
+func TestTransactionService_ProcessAtomicMultiTransfer_InvalidLeg(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	walletA, walletB := createTestWallets(t, service)
+	
+	legs := []TransferLeg{
+		{FromWallet: walletA, ToWallet: walletB, Amount: 100.0, Currency: models.USDCBDC},
+		{FromWallet: walletB, ToWallet: walletB, Amount: 50.0, Currency: models.USDCBDC},
+	}
+	
+	_, err := service.ProcessAtomicMultiTransfer(context.Background(), legs)
+	require.Error(t, err)
+	
+	echoPayErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, echoPayErr.Code)
+}
+
+func TestTransactionService_RegisteredCurrency(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+	
+	jpy := models.Currency("JPY-CBDC")
+	ctx := context.Background()
+	
+	// Transfers in an unregistered currency are rejected
+	_, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: uuid.New(),
+		ToWallet:   uuid.New(),
+		Amount:     500,
+		Currency:   jpy,
+	})
+	require.Error(t, err)
+	
+	registry := currency.NewDefaultRegistry()
+	registry.Register(string(jpy))
+	service.SetCurrencyRegistry(registry)
+	
+	// New wallets are seeded with the registered currency
+	fromWallet, toWallet := createTestWallets(t, service)
+	balances, err := service.balanceRepo.GetWalletBalances(fromWallet)
+	require.NoError(t, err)
+	assert.Len(t, balances, 4)
+	
+	err = service.balanceRepo.AddFunds(fromWallet, jpy, 10000)
+	require.NoError(t, err)
+	
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet: fromWallet,
+		ToWallet:   toWallet,
+		Amount:     500,
+		Currency:   jpy,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, transaction.Status)
+	
+	toBalance, err := service.GetWalletBalance(ctx, toWallet, jpy)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, toBalance.Balance)
+}
+
+func TestTransactionService_EventStreamingDegraded(t *testing.T) {
+	// A publisher whose broker refuses connections
+	publisher := events.NewEventPublisher(events.EventPublisherConfig{
+		KafkaBrokers: []string{"127.0.0.1:1"},
+		Topic:        "test.transactions",
+	})
+	defer publisher.Close()
+
+	checkedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	service := &TransactionService{eventPublisher: publisher, metrics: &TransactionMetrics{}, clock: clock.NewFake(checkedAt)}
+	assert.False(t, service.EventStreamingDegraded(), "Expected no degradation before the first check")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	status := service.CheckEventStreaming(ctx)
+
+	assert.False(t, status.Healthy)
+	assert.NotEmpty(t, status.Error)
+	assert.Equal(t, checkedAt, status.CheckedAt)
+	assert.True(t, service.EventStreamingDegraded())
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+// MaxTagLength caps the length of a transaction tag
+const MaxTagLength = 64
+
+// tagPattern matches a normalized tag: lowercase letters and digits, optionally separated by
+// dashes, underscores, dots or colons
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+([-_.:][a-z0-9]+)*$`)
+
+// normalizeTag lowercases and trims a tag so variants of the same tag are stored once
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", errors.NewTransactionError(errors.ErrInvalidTransaction, "tag cannot be empty")
+	}
+	if len(tag) > MaxTagLength {
+		return "", errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("tag cannot be longer than %d characters", MaxTagLength))
+	}
+	if !tagPattern.MatchString(tag) {
+		return "", errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("invalid tag %q: use letters and digits separated by '-', '_', '.' or ':'", tag))
+	}
+	return tag, nil
+}
+
+// AddTag tags a transaction and returns its tags
+func (s *TransactionService) AddTag(ctx context.Context, transactionID uuid.UUID, tag string) ([]string, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(transactionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddTag(transactionID, tag); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTags(transactionID)
+}
+
+// RemoveTag removes a tag from a transaction and returns its remaining tags
+func (s *TransactionService) RemoveTag(ctx context.Context, transactionID uuid.UUID, tag string) ([]string, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RemoveTag(transactionID, tag); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTags(transactionID)
+}
+
+// GetByTag retrieves transactions with a tag, most recently tagged first
+func (s *TransactionService) GetByTag(ctx context.Context, tag string, limit, offset int) ([]*models.Transaction, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ids, err := s.repo.GetIDsByTag(tag, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*models.Transaction, 0, len(ids))
+	for _, id := range ids {
+		transaction, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/transaction-service/src/models"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tag, err := normalizeTag("  Manual-Review ")
+	require.NoError(t, err)
+	assert.Equal(t, "manual-review", tag)
+
+	tag, err = normalizeTag("VIP")
+	require.NoError(t, err)
+	assert.Equal(t, "vip", tag)
+
+	for _, invalid := range []string{"", "   ", "two words", "-leading", "trailing-", "a/b", strings.Repeat("x", MaxTagLength+1)} {
+		_, err := normalizeTag(invalid)
+		assert.Error(t, err, "expected %q to be rejected", invalid)
+	}
+}
+
+func TestTransactionService_Tags(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	fromWallet, toWallet := createTestWallets(t, service)
+	tag := "review-" + uuid.New().String()[:8]
+
+	var transactions []*models.Transaction
+	for i := 0; i < 3; i++ {
+		transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     10.0,
+			Currency:   models.USDCBDC,
+		})
+		require.NoError(t, err)
+		transactions = append(transactions, transaction)
+	}
+
+	t.Run("tags are normalized and de-duplicated", func(t *testing.T) {
+		tags, err := service.AddTag(ctx, transactions[0].ID, tag)
+		require.NoError(t, err)
+		assert.Equal(t, []string{tag}, tags)
+
+		tags, err = service.AddTag(ctx, transactions[0].ID, strings.ToUpper(tag))
+		require.NoError(t, err)
+		assert.Equal(t, []string{tag}, tags)
+
+		tags, err = service.AddTag(ctx, transactions[0].ID, "VIP")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{tag, "vip"}, tags)
+
+		_, err = service.AddTag(ctx, uuid.New(), tag)
+		assert.Error(t, err)
+	})
+
+	t.Run("untagging removes only that tag", func(t *testing.T) {
+		tags, err := service.RemoveTag(ctx, transactions[0].ID, "vip")
+		require.NoError(t, err)
+		assert.Equal(t, []string{tag}, tags)
+
+		_, err = service.RemoveTag(ctx, transactions[0].ID, "vip")
+		assert.Error(t, err)
+	})
+
+	t.Run("retrieves tagged transactions a page at a time", func(t *testing.T) {
+		for _, transaction := range transactions[1:] {
+			_, err := service.AddTag(ctx, transaction.ID, tag)
+			require.NoError(t, err)
+		}
+
+		firstPage, err := service.GetByTag(ctx, tag, 2, 0)
+		require.NoError(t, err)
+		require.Len(t, firstPage, 2)
+
+		secondPage, err := service.GetByTag(ctx, tag, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, secondPage, 1)
+
+		var ids []uuid.UUID
+		for _, transaction := range append(firstPage, secondPage...) {
+			ids = append(ids, transaction.ID)
+		}
+		assert.ElementsMatch(t, []uuid.UUID{transactions[0].ID, transactions[1].ID, transactions[2].ID}, ids)
+
+		untagged, err := service.GetByTag(ctx, "no-such-tag", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, untagged)
+	})
+}
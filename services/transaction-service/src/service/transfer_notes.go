@@ -0,0 +1,59 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// WalletTransaction is a transaction as seen from one wallet's history. The description is
+// shared by both parties, but each transfer note is only shown to the wallet that wrote it.
+type WalletTransaction struct {
+	*models.Transaction
+	SenderNote    string `json:"sender_note,omitempty"`
+	RecipientNote string `json:"recipient_note,omitempty"`
+}
+
+// walletTransactionViews wraps transactions for the wallet requesting them, attaching the
+// transfer note that wallet may see. A requester that is neither party sees no notes.
+func (s *TransactionService) walletTransactionViews(transactions []*models.Transaction, requester uuid.UUID) ([]*WalletTransaction, error) {
+	views := make([]*WalletTransaction, len(transactions))
+
+	var ids []uuid.UUID
+	for i, transaction := range transactions {
+		views[i] = &WalletTransaction{Transaction: transaction}
+		if requester != uuid.Nil && (transaction.FromWallet == requester || transaction.ToWallet == requester) {
+			ids = append(ids, transaction.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return views, nil
+	}
+
+	notes, err := s.repo.GetTransferNotes(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		note, ok := notes[view.ID]
+		if !ok {
+			continue
+		}
+		view.SenderNote, view.RecipientNote = visibleTransferNotes(view.Transaction, note, requester)
+	}
+
+	return views, nil
+}
+
+// visibleTransferNotes returns the notes on a transaction that the requesting wallet may see
+func visibleTransferNotes(transaction *models.Transaction, notes repository.TransferNotes, requester uuid.UUID) (senderNote, recipientNote string) {
+	if transaction.FromWallet == requester {
+		senderNote = notes.SenderNote
+	}
+	if transaction.ToWallet == requester {
+		recipientNote = notes.RecipientNote
+	}
+	return senderNote, recipientNote
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/currency"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+func TestTransactionService_GetTransactionsByWallet_TransferNotes(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+
+	transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+		FromWallet:    fromWallet,
+		ToWallet:      toWallet,
+		Amount:        25.0,
+		Currency:      models.USDCBDC,
+		Metadata:      models.TransactionMetadata{Description: "dinner"},
+		SenderNote:    "split with Sam later",
+		RecipientNote: "paid back for dinner",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		wallet        uuid.UUID
+		requester     uuid.UUID
+		senderNote    string
+		recipientNote string
+	}{
+		{"sender", fromWallet, fromWallet, "split with Sam later", ""},
+		{"recipient", toWallet, toWallet, "", "paid back for dinner"},
+		{"third party", fromWallet, uuid.New(), "", ""},
+		{"anonymous", toWallet, uuid.Nil, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactions, err := service.GetTransactionsByWallet(ctx, tc.wallet, tc.requester, 10, 0)
+			require.NoError(t, err)
+			require.Len(t, transactions, 1)
+
+			view := transactions[0]
+			assert.Equal(t, transaction.ID, view.ID)
+			assert.Equal(t, "dinner", view.Metadata.Description)
+			assert.Equal(t, tc.senderNote, view.SenderNote)
+			assert.Equal(t, tc.recipientNote, view.RecipientNote)
+
+			page, err := service.GetTransactionsByWalletCursor(ctx, tc.wallet, tc.requester, "", 10)
+			require.NoError(t, err)
+			require.Len(t, page.Transactions, 1)
+			assert.Equal(t, tc.senderNote, page.Transactions[0].SenderNote)
+			assert.Equal(t, tc.recipientNote, page.Transactions[0].RecipientNote)
+		})
+	}
+}
+
+func TestTransactionService_ValidateTransactionRequest_TransferNotes(t *testing.T) {
+	service := &TransactionService{currencies: currency.NewDefaultRegistry()}
+	newRequest := func(senderNote, recipientNote string) *TransactionRequest {
+		return &TransactionRequest{
+			FromWallet:    uuid.New(),
+			ToWallet:      uuid.New(),
+			Amount:        10.0,
+			Currency:      models.USDCBDC,
+			SenderNote:    senderNote,
+			RecipientNote: recipientNote,
+		}
+	}
+
+	for _, req := range []*TransactionRequest{
+		newRequest(strings.Repeat("x", MaxTransferNoteLength+1), ""),
+		newRequest("", strings.Repeat("x", MaxTransferNoteLength+1)),
+	} {
+		err := service.validateTransactionRequest(req)
+		transactionErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+		assert.Contains(t, transactionErr.Message, "transfer notes")
+	}
+
+	req := newRequest("rent\x00 share\x1b", "\x07thanks")
+	require.NoError(t, service.validateTransactionRequest(req))
+	assert.Equal(t, "rent share", req.SenderNote)
+	assert.Equal(t, "thanks", req.RecipientNote)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/repository"
+)
+
+// MaxTransferPolicyEntries caps the number of counterparties on a wallet's allow or deny list
+const MaxTransferPolicyEntries = 1000
+
+// ParseTransferPolicyMode parses a transfer policy mode name
+func ParseTransferPolicyMode(mode string) (repository.TransferPolicyMode, error) {
+	switch repository.TransferPolicyMode(mode) {
+	case repository.TransferPolicyUnrestricted, repository.TransferPolicyAllow, repository.TransferPolicyDeny:
+		return repository.TransferPolicyMode(mode), nil
+	default:
+		return "", errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unknown transfer policy mode %q: must be unrestricted, allow or deny", mode))
+	}
+}
+
+// GetTransferPolicy retrieves the policy restricting which wallets a wallet may transfer to
+func (s *TransactionService) GetTransferPolicy(ctx context.Context, walletID uuid.UUID) (*repository.TransferPolicy, error) {
+	if _, err := s.walletRepo.GetByID(walletID); err != nil {
+		return nil, err
+	}
+	return s.policyRepo.Get(walletID)
+}
+
+// SetTransferPolicy replaces a wallet's transfer policy. In allow mode the wallet may only
+// transfer to the listed counterparties; in deny mode it may transfer to any wallet except
+// them. An unrestricted policy has no list.
+func (s *TransactionService) SetTransferPolicy(ctx context.Context, walletID uuid.UUID, mode repository.TransferPolicyMode, counterparties []uuid.UUID) (*repository.TransferPolicy, error) {
+	if _, err := ParseTransferPolicyMode(string(mode)); err != nil {
+		return nil, err
+	}
+	if mode == repository.TransferPolicyUnrestricted && len(counterparties) > 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "an unrestricted transfer policy cannot list counterparties")
+	}
+	if err := validateCounterparties(walletID, counterparties, 0); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.walletRepo.GetByID(walletID); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Set(walletID, mode, counterparties); err != nil {
+		return nil, err
+	}
+	return s.policyRepo.Get(walletID)
+}
+
+// AddTransferPolicyCounterparties adds counterparties to a wallet's allow or deny list
+func (s *TransactionService) AddTransferPolicyCounterparties(ctx context.Context, walletID uuid.UUID, counterparties []uuid.UUID) (*repository.TransferPolicy, error) {
+	if len(counterparties) == 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "at least one counterparty is required")
+	}
+
+	policy, err := s.GetTransferPolicy(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCounterparties(walletID, counterparties, len(policy.Counterparties)); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.AddCounterparties(walletID, counterparties); err != nil {
+		return nil, err
+	}
+	return s.policyRepo.Get(walletID)
+}
+
+// RemoveTransferPolicyCounterparty removes a counterparty from a wallet's allow or deny list
+func (s *TransactionService) RemoveTransferPolicyCounterparty(ctx context.Context, walletID, counterparty uuid.UUID) (*repository.TransferPolicy, error) {
+	if _, err := s.walletRepo.GetByID(walletID); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.RemoveCounterparty(walletID, counterparty); err != nil {
+		return nil, err
+	}
+	return s.policyRepo.Get(walletID)
+}
+
+// validateCounterparties validates counterparties being listed on a wallet's policy alongside
+// the existing number already on it
+func validateCounterparties(walletID uuid.UUID, counterparties []uuid.UUID, existing int) error {
+	if existing+len(counterparties) > MaxTransferPolicyEntries {
+		return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("a transfer policy cannot list more than %d counterparties", MaxTransferPolicyEntries))
+	}
+
+	for _, counterparty := range counterparties {
+		if counterparty == uuid.Nil {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "counterparty wallet IDs cannot be nil")
+		}
+		if counterparty == walletID {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "a wallet cannot list itself as a counterparty")
+		}
+	}
+
+	return nil
+}
+
+// checkTransferPolicy rejects a transfer the sender's policy does not permit. Services built
+// without a policy repository leave transfers unrestricted.
+func (s *TransactionService) checkTransferPolicy(from, to uuid.UUID) error {
+	if s.policyRepo == nil {
+		return nil
+	}
+
+	permitted, err := s.policyRepo.IsPermitted(from, to)
+	if err != nil {
+		return err
+	}
+	if !permitted {
+		return errors.NewTransactionError(errors.ErrTransferNotPermitted, fmt.Sprintf("wallet %s is not permitted to transfer to wallet %s", from, to))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+func TestTransferPolicy_Permits(t *testing.T) {
+	listed := uuid.New()
+	other := uuid.New()
+
+	allow := &repository.TransferPolicy{Mode: repository.TransferPolicyAllow, Counterparties: []uuid.UUID{listed}}
+	assert.True(t, allow.Permits(listed))
+	assert.False(t, allow.Permits(other))
+
+	deny := &repository.TransferPolicy{Mode: repository.TransferPolicyDeny, Counterparties: []uuid.UUID{listed}}
+	assert.False(t, deny.Permits(listed))
+	assert.True(t, deny.Permits(other))
+
+	unrestricted := &repository.TransferPolicy{Mode: repository.TransferPolicyUnrestricted}
+	assert.True(t, unrestricted.Permits(other))
+
+	_, err := ParseTransferPolicyMode("block")
+	assert.Error(t, err)
+}
+
+func TestTransactionService_TransferPolicy(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	transfer := func(from, to uuid.UUID) error {
+		_, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: from,
+			ToWallet:   to,
+			Amount:     10.0,
+			Currency:   models.USDCBDC,
+		})
+		return err
+	}
+	assertNotPermitted := func(t *testing.T, err error) {
+		require.Error(t, err)
+		txErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrTransferNotPermitted, txErr.Code)
+	}
+
+	t.Run("unrestricted wallets can transfer to anyone", func(t *testing.T) {
+		fromWallet, toWallet := createTestWallets(t, service)
+
+		policy, err := service.GetTransferPolicy(ctx, fromWallet)
+		require.NoError(t, err)
+		assert.Equal(t, repository.TransferPolicyUnrestricted, policy.Mode)
+		assert.Empty(t, policy.Counterparties)
+
+		assert.NoError(t, transfer(fromWallet, toWallet))
+	})
+
+	t.Run("allow mode only permits listed recipients", func(t *testing.T) {
+		fromWallet, approved := createTestWallets(t, service)
+		unlisted := createTestWallet(t, service)
+
+		policy, err := service.SetTransferPolicy(ctx, fromWallet, repository.TransferPolicyAllow, []uuid.UUID{approved})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{approved}, policy.Counterparties)
+
+		assert.NoError(t, transfer(fromWallet, approved))
+		assertNotPermitted(t, transfer(fromWallet, unlisted))
+
+		// Listing the recipient permits it
+		_, err = service.AddTransferPolicyCounterparties(ctx, fromWallet, []uuid.UUID{unlisted})
+		require.NoError(t, err)
+		assert.NoError(t, transfer(fromWallet, unlisted))
+
+		// The policy restricts the wallet's outgoing transfers only
+		assert.NoError(t, transfer(unlisted, createTestWallet(t, service)))
+	})
+
+	t.Run("deny mode rejects listed recipients", func(t *testing.T) {
+		fromWallet, denied := createTestWallets(t, service)
+		other := createTestWallet(t, service)
+
+		_, err := service.SetTransferPolicy(ctx, fromWallet, repository.TransferPolicyDeny, []uuid.UUID{denied})
+		require.NoError(t, err)
+
+		assertNotPermitted(t, transfer(fromWallet, denied))
+		assert.NoError(t, transfer(fromWallet, other))
+
+		// Removing the entry lifts the restriction
+		policy, err := service.RemoveTransferPolicyCounterparty(ctx, fromWallet, denied)
+		require.NoError(t, err)
+		assert.Empty(t, policy.Counterparties)
+		assert.NoError(t, transfer(fromWallet, denied))
+
+		// Resetting to unrestricted removes the policy
+		_, err = service.SetTransferPolicy(ctx, fromWallet, repository.TransferPolicyDeny, []uuid.UUID{denied})
+		require.NoError(t, err)
+		policy, err = service.SetTransferPolicy(ctx, fromWallet, repository.TransferPolicyUnrestricted, nil)
+		require.NoError(t, err)
+		assert.Equal(t, repository.TransferPolicyUnrestricted, policy.Mode)
+		assert.NoError(t, transfer(fromWallet, denied))
+	})
+
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		wallet := createTestWallet(t, service)
+
+		_, err := service.SetTransferPolicy(ctx, wallet, repository.TransferPolicyAllow, []uuid.UUID{wallet})
+		assert.Error(t, err)
+		_, err = service.SetTransferPolicy(ctx, wallet, repository.TransferPolicyUnrestricted, []uuid.UUID{uuid.New()})
+		assert.Error(t, err)
+		_, err = service.SetTransferPolicy(ctx, uuid.New(), repository.TransferPolicyDeny, nil)
+		assert.Error(t, err)
+		// Lists can only be edited once a mode is set
+		_, err = service.AddTransferPolicyCounterparties(ctx, wallet, []uuid.UUID{uuid.New()})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// logTransactionTransition logs a committed transaction status change. The line carries the
+// request's correlation ID from ctx along with the transaction ID.
+func logTransactionTransition(ctx context.Context, transaction *models.Transaction, from models.TransactionStatus, args ...interface{}) {
+	ctx = logging.ContextWithFields(ctx, "transaction_id", transaction.ID.String())
+	fields := []interface{}{
+		"from_status", from,
+		"to_status", transaction.Status,
+		"from_wallet", transaction.FromWallet.String(),
+		"to_wallet", transaction.ToWallet.String(),
+	}
+	logging.WithContext(ctx).Info("Transaction state transition", append(fields, args...)...)
+}
+
+// logWalletTransition logs a committed wallet status change with the request's correlation ID
+func logWalletTransition(ctx context.Context, walletID uuid.UUID, from, to repository.WalletStatus, reason string) {
+	ctx = logging.ContextWithFields(ctx, "wallet_id", walletID.String())
+	logging.WithContext(ctx).Info("Wallet state transition",
+		"from_status", from,
+		"to_status", to,
+		"reason", reason,
+	)
+}
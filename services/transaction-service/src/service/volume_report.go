@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/models"
+)
+
+// DailyVolume is the number and total value of completed transactions in a currency over one
+// calendar day in the reporting timezone
+type DailyVolume struct {
+	Currency models.Currency `json:"currency"`
+	Day      string          `json:"day"` // YYYY-MM-DD
+	Timezone string          `json:"timezone"`
+	From     time.Time       `json:"from"` // Start of the day, inclusive
+	To       time.Time       `json:"to"`   // Start of the next day, exclusive
+	Count    int             `json:"count"`
+	Total    float64         `json:"total"`
+}
+
+// SetReportingLocation sets the timezone whose calendar days volume reports cover
+func (s *TransactionService) SetReportingLocation(location *time.Location) {
+	s.reportingLocation = location
+}
+
+// ReportingLocation returns the timezone whose calendar days volume reports cover, UTC unless
+// configured otherwise
+func (s *TransactionService) ReportingLocation() *time.Location {
+	if s.reportingLocation == nil {
+		return time.UTC
+	}
+	return s.reportingLocation
+}
+
+// dayBounds returns the start of the calendar day containing t in location and the start of
+// the following day. Days across a daylight saving change are 23 or 25 hours long.
+func dayBounds(t time.Time, location *time.Location) (time.Time, time.Time) {
+	t = t.In(location)
+	from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// GetDailyVolume reports the completed transactions in a currency created on the calendar day
+// containing day, in the reporting timezone. A day with no activity reports zero.
+func (s *TransactionService) GetDailyVolume(ctx context.Context, currency models.Currency, day time.Time) (*DailyVolume, error) {
+	if !s.currencies.Supported(string(currency)) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", currency))
+	}
+
+	location := s.ReportingLocation()
+	from, to := dayBounds(day, location)
+
+	count, total, err := s.repo.GetCompletedVolume(currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DailyVolume{
+		Currency: currency,
+		Day:      from.Format("2006-01-02"),
+		Timezone: location.String(),
+		From:     from,
+		To:       to,
+		Count:    count,
+		Total:    money.Round(total, string(currency)),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/currency"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+)
+
+func TestDayBounds(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Late evening UTC is already the next day in Berlin
+	instant := time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC)
+
+	from, to := dayBounds(instant, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = dayBounds(instant, berlin)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, berlin), from)
+	assert.True(t, from.Equal(time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24*time.Hour, to.Sub(from))
+
+	// Midnight starts a day rather than ending one
+	from, _ = dayBounds(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), from)
+
+	// The day clocks go forward is an hour short
+	from, to = dayBounds(time.Date(2025, 3, 30, 12, 0, 0, 0, berlin), berlin)
+	assert.Equal(t, 23*time.Hour, to.Sub(from))
+}
+
+func TestTransactionService_GetDailyVolume(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	service.SetReportingLocation(berlin)
+
+	fromWallet, toWallet := createTestWallets(t, service)
+	ctx := context.Background()
+
+	// A distinct currency keeps other tests' transactions out of the totals
+	registry := currency.NewDefaultRegistry()
+	testCurrency := models.Currency("VOL-" + fromWallet.String()[:8])
+	registry.Register(string(testCurrency))
+	service.SetCurrencyRegistry(registry)
+	require.NoError(t, service.balanceRepo.AddFunds(fromWallet, testCurrency, 1000.0))
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, berlin)
+	next := day.AddDate(0, 0, 1)
+
+	pay := func(amount float64, createdAt time.Time) {
+		transaction, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: fromWallet,
+			ToWallet:   toWallet,
+			Amount:     amount,
+			Currency:   testCurrency,
+		})
+		require.NoError(t, err)
+		_, err = db.Exec(`UPDATE transactions SET created_at = $2 WHERE id = $1`, transaction.ID, createdAt)
+		require.NoError(t, err)
+	}
+
+	pay(1.0, day.Add(-time.Millisecond))   // Last moment of the previous day
+	pay(10.0, day)                         // First moment of the day
+	pay(20.0, day.Add(12*time.Hour))       // Midday
+	pay(40.0, next.Add(-time.Millisecond)) // Last moment of the day
+	pay(80.0, next)                        // First moment of the next day
+
+	volume, err := service.GetDailyVolume(ctx, testCurrency, day.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-06-02", volume.Day)
+	assert.Equal(t, "Europe/Berlin", volume.Timezone)
+	assert.Equal(t, 3, volume.Count)
+	assert.Equal(t, 70.0, volume.Total)
+
+	// Adjacent days share a boundary without counting either transaction twice
+	previous, err := service.GetDailyVolume(ctx, testCurrency, day.Add(-time.Hour))
+	require.NoError(t, err)
+	following, err := service.GetDailyVolume(ctx, testCurrency, next)
+	require.NoError(t, err)
+	assert.Equal(t, 1, previous.Count)
+	assert.Equal(t, 1.0, previous.Total)
+	assert.Equal(t, 1, following.Count)
+	assert.Equal(t, 80.0, following.Total)
+
+	// A day with no activity reports zero
+	quiet, err := service.GetDailyVolume(ctx, testCurrency, day.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Equal(t, 0, quiet.Count)
+	assert.Equal(t, 0.0, quiet.Total)
+}
+
+func TestTransactionService_GetDailyVolume_UnsupportedCurrency(t *testing.T) {
+	// Validation fails before the repository is used
+	service := &TransactionService{currencies: currency.NewDefaultRegistry()}
+
+	_, err := service.GetDailyVolume(context.Background(), "XYZ-CBDC", time.Now())
+	transactionErr, ok := err.(*errors.EchoPayError)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrInvalidTransaction, transactionErr.Code)
+}
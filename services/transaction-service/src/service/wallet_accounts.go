@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/repository"
+)
+
+// GetWalletsForUser retrieves every wallet held by a user's account, oldest first. A user
+// with no linked wallets has an empty list.
+func (s *TransactionService) GetWalletsForUser(ctx context.Context, userID uuid.UUID) ([]repository.Wallet, error) {
+	if userID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "user ID cannot be nil")
+	}
+	return s.walletRepo.GetByAccount(userID)
+}
+
+// GetUserForWallet retrieves the user holding a wallet, or nil if the wallet is not linked
+// to an account, as with wallets that predate explicit creation
+func (s *TransactionService) GetUserForWallet(ctx context.Context, walletID uuid.UUID) (*uuid.UUID, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+	return s.walletRepo.GetAccountID(walletID)
+}
+
+// LinkWalletToUser makes a user the account holder of a wallet, replacing any previous
+// holder. It lets wallets that were never linked, or that changed hands, take part in
+// cross-wallet fraud analysis.
+func (s *TransactionService) LinkWalletToUser(ctx context.Context, walletID, userID uuid.UUID) (*repository.Wallet, error) {
+	if walletID == uuid.Nil || userID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID and user ID are required")
+	}
+
+	var wallet *repository.Wallet
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		wallet, err = s.walletRepo.GetForUpdateInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.walletRepo.LinkAccountInTx(tx, walletID, userID); err != nil {
+			return err
+		}
+		wallet.OwnerID = &userID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
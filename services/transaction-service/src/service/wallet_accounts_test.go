@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/repository"
+)
+
+func TestTransactionService_WalletsForUser(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	var walletIDs []uuid.UUID
+	for i := 0; i < 3; i++ {
+		wallet, err := service.CreateWallet(ctx, &CreateWalletRequest{OwnerID: userID})
+		require.NoError(t, err)
+		walletIDs = append(walletIDs, wallet.ID)
+	}
+	otherWallet := createTestWallet(t, service)
+
+	wallets, err := service.GetWalletsForUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, wallets, 3)
+	for i, wallet := range wallets {
+		assert.Equal(t, walletIDs[i], wallet.ID)
+		assert.Equal(t, &userID, wallet.OwnerID)
+	}
+
+	for _, walletID := range walletIDs {
+		holder, err := service.GetUserForWallet(ctx, walletID)
+		require.NoError(t, err)
+		assert.Equal(t, &userID, holder)
+	}
+
+	holder, err := service.GetUserForWallet(ctx, otherWallet)
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.NotEqual(t, userID, *holder)
+
+	wallets, err = service.GetWalletsForUser(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, wallets)
+
+	t.Run("unlinked wallet has no user until linked", func(t *testing.T) {
+		// Wallets registered before explicit creation have no owner or account
+		now := time.Now().UTC()
+		unlinked := &repository.Wallet{ID: uuid.New(), Status: repository.WalletStatusActive, CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, service.db.Transaction(func(tx *sql.Tx) error {
+			return service.walletRepo.CreateInTx(tx, unlinked)
+		}))
+
+		holder, err := service.GetUserForWallet(ctx, unlinked.ID)
+		require.NoError(t, err)
+		assert.Nil(t, holder)
+
+		wallet, err := service.LinkWalletToUser(ctx, unlinked.ID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, &userID, wallet.OwnerID)
+
+		holder, err = service.GetUserForWallet(ctx, unlinked.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &userID, holder)
+
+		wallets, err := service.GetWalletsForUser(ctx, userID)
+		require.NoError(t, err)
+		assert.Len(t, wallets, 4)
+	})
+
+	t.Run("unknown wallet is not found", func(t *testing.T) {
+		_, err := service.GetUserForWallet(ctx, uuid.New())
+		echoPayErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrWalletNotFound, echoPayErr.Code)
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/logging"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+const (
+	// baselineSmoothing is the weight a completed transfer carries in an established baseline
+	baselineSmoothing = 0.1
+	// baselinePriorAmount and baselinePriorInterval are the conservative prior a new wallet's
+	// baseline starts from: small, infrequent transfers, so large or rapid early transfers
+	// deviate until the wallet establishes its own pattern
+	baselinePriorAmount   = 100.0
+	baselinePriorInterval = 24 * time.Hour
+	// baselineTolerance is how many times its baseline a transfer's amount or frequency may be
+	// before it deviates, leaving room for ordinary variation and a still-converging average
+	baselineTolerance = 2.0
+)
+
+// SetFraudScoreHalfLife sets how quickly fraud scores fade from wallet risk: a score counts
+// half as much once it is halfLife old, and a quarter at twice that. Zero or less disables
+// decay. Stored transaction fraud scores are never changed.
+func (s *TransactionService) SetFraudScoreHalfLife(halfLife time.Duration) {
+	if halfLife < 0 {
+		halfLife = 0
+	}
+	s.fraudHalfLife = halfLife
+}
+
+// newWalletBaseline returns the prior baseline of a wallet with no completed transfers
+func newWalletBaseline(walletID uuid.UUID, currency models.Currency) *repository.WalletBaseline {
+	return &repository.WalletBaseline{
+		WalletID:    walletID,
+		Currency:    currency,
+		AmountEMA:   baselinePriorAmount,
+		IntervalEMA: baselinePriorInterval.Seconds(),
+	}
+}
+
+// observeBaseline folds a completed transfer into a baseline. The prior counts as a single
+// observation, so early transfers move the averages quickly until each carries only
+// baselineSmoothing of the weight.
+func observeBaseline(baseline *repository.WalletBaseline, amount float64, at time.Time) {
+	weight := math.Max(baselineSmoothing, 1/float64(baseline.Observations+2))
+
+	baseline.AmountEMA += weight * (amount - baseline.AmountEMA)
+	if baseline.LastTransactionAt != nil {
+		interval := math.Max(at.Sub(*baseline.LastTransactionAt).Seconds(), 0)
+		baseline.IntervalEMA += weight * (interval - baseline.IntervalEMA)
+	}
+
+	baseline.Observations++
+	baseline.LastTransactionAt = &at
+	baseline.UpdatedAt = at
+}
+
+// scoreDecay returns the weight a fraud score keeps after age, halving every halfLife
+func scoreDecay(age, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, age.Seconds()/halfLife.Seconds())
+}
+
+// riskAt returns a baseline's fraud risk as of at: its average of fraud scores, decayed by the
+// time since the last score was folded in, so a wallet's old scores fade unless new ones
+// renew them
+func riskAt(baseline *repository.WalletBaseline, at time.Time, halfLife time.Duration) float64 {
+	if baseline.RiskUpdatedAt == nil {
+		return 0
+	}
+	return baseline.RiskEMA * scoreDecay(at.Sub(*baseline.RiskUpdatedAt), halfLife)
+}
+
+// observeRisk folds a transfer's fraud score into a baseline's risk. The existing risk decays
+// to at first, so a recent score outweighs an older score of the same value. As with amounts,
+// early scores move the average quickly until each carries baselineSmoothing of the weight.
+func observeRisk(baseline *repository.WalletBaseline, score float64, at time.Time, halfLife time.Duration) {
+	risk := riskAt(baseline, at, halfLife)
+	weight := math.Max(baselineSmoothing, 1/float64(baseline.RiskObservations+1))
+
+	baseline.RiskEMA = risk + weight*(score-risk)
+	baseline.RiskObservations++
+	baseline.RiskUpdatedAt = &at
+	baseline.UpdatedAt = at
+}
+
+// baselineDeviation scores how far a transfer deviates from its sender's baseline, from 0 for
+// a typical transfer towards 1 for an amount many times the wallet's average or a transfer
+// sent much sooner than its usual interval. Either deviation alone raises the score, and
+// together they compound.
+func baselineDeviation(baseline *repository.WalletBaseline, amount float64, at time.Time) float64 {
+	amountScore := excessScore(amount / baseline.AmountEMA / baselineTolerance)
+
+	frequencyScore := 0.0
+	if baseline.LastTransactionAt != nil {
+		elapsed := math.Max(at.Sub(*baseline.LastTransactionAt).Seconds(), 1)
+		frequencyScore = excessScore(baseline.IntervalEMA / elapsed / baselineTolerance)
+	}
+
+	return 1 - (1-amountScore)*(1-frequencyScore)
+}
+
+// excessScore maps how many times a value exceeds its tolerated baseline to a score in
+// [0, 1): 0 up to the tolerance, 0.5 at twice it and 0.9 at ten times it
+func excessScore(ratio float64) float64 {
+	if math.IsNaN(ratio) || ratio <= 1 {
+		return 0
+	}
+	return 1 - 1/ratio
+}
+
+// GetWalletBaseline returns a wallet's baseline in a currency, or the prior baseline if the
+// wallet has completed no transfers in it. Its current risk is decayed to now.
+func (s *TransactionService) GetWalletBaseline(ctx context.Context, walletID uuid.UUID, currency models.Currency) (*repository.WalletBaseline, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+
+	baseline, err := s.balanceRepo.GetBaseline(walletID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if baseline == nil {
+		baseline = newWalletBaseline(walletID, currency)
+	}
+	baseline.CurrentRisk = riskAt(baseline, s.clock.Now(), s.fraudHalfLife)
+	return baseline, nil
+}
+
+// scoreAgainstBaseline returns how far a new transfer deviates from its sender's baseline, or
+// nil if the baseline cannot be loaded. Fraud detection receives it with the transaction
+// created event.
+func (s *TransactionService) scoreAgainstBaseline(ctx context.Context, transaction *models.Transaction) *float64 {
+	baseline, err := s.GetWalletBaseline(ctx, transaction.FromWallet, transaction.Currency)
+	if err != nil {
+		logging.WithContext(ctx).Warn("Failed to load wallet baseline", "wallet_id", transaction.FromWallet, "error", err.Error())
+		return nil
+	}
+
+	deviation := baselineDeviation(baseline, transaction.Amount, s.clock.Now())
+	return &deviation
+}
+
+// updateWalletBaseline folds a completed transfer into its sender's baseline. The transfer
+// has already completed, so a failure is logged rather than returned.
+func (s *TransactionService) updateWalletBaseline(ctx context.Context, transaction *models.Transaction) {
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		baseline, err := s.balanceRepo.GetBaselineForUpdateInTx(tx, transaction.FromWallet, transaction.Currency)
+		if err != nil {
+			return err
+		}
+		if baseline == nil {
+			baseline = newWalletBaseline(transaction.FromWallet, transaction.Currency)
+		}
+
+		observeBaseline(baseline, transaction.Amount, s.clock.Now().UTC())
+		return s.balanceRepo.SaveBaselineInTx(tx, baseline)
+	})
+	if err != nil {
+		logging.WithContext(ctx).Warn("Failed to update wallet baseline", "wallet_id", transaction.FromWallet, "transaction_id", transaction.ID, "error", err.Error())
+	}
+}
+
+// updateWalletRisk folds a transfer's new fraud score into its sender's baseline risk. The
+// score has already been stored, so a failure is logged rather than returned.
+func (s *TransactionService) updateWalletRisk(ctx context.Context, transaction *models.Transaction, score float64) {
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		baseline, err := s.balanceRepo.GetBaselineForUpdateInTx(tx, transaction.FromWallet, transaction.Currency)
+		if err != nil {
+			return err
+		}
+		if baseline == nil {
+			baseline = newWalletBaseline(transaction.FromWallet, transaction.Currency)
+		}
+
+		observeRisk(baseline, score, s.clock.Now().UTC(), s.fraudHalfLife)
+		return s.balanceRepo.SaveBaselineInTx(tx, baseline)
+	})
+	if err != nil {
+		logging.WithContext(ctx).Warn("Failed to update wallet risk", "wallet_id", transaction.FromWallet, "transaction_id", transaction.ID, "error", err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// establishBaseline folds count transfers of amount, interval apart, into a new wallet's
+// baseline and returns it with the time of the last transfer
+func establishBaseline(amount float64, interval time.Duration, count int) (*repository.WalletBaseline, time.Time) {
+	baseline := newWalletBaseline(uuid.New(), models.USDCBDC)
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		at = at.Add(interval)
+		observeBaseline(baseline, amount, at)
+	}
+	return baseline, at
+}
+
+func TestBaselineDeviation_ScoresAgainstWalletHistory(t *testing.T) {
+	smallSpender, lastSmall := establishBaseline(20, 24*time.Hour, 30)
+	highVolume, lastHigh := establishBaseline(5000, time.Hour, 30)
+
+	// The averages have converged on each wallet's own pattern, away from the prior
+	assert.InEpsilon(t, 20, smallSpender.AmountEMA, 0.1)
+	assert.InEpsilon(t, 5000, highVolume.AmountEMA, 0.1)
+	assert.Less(t, highVolume.IntervalEMA, baselineTolerance*time.Hour.Seconds())
+
+	// The same transfer, sent at each wallet's usual interval
+	smallScore := baselineDeviation(smallSpender, 2000, lastSmall.Add(24*time.Hour))
+	highScore := baselineDeviation(highVolume, 2000, lastHigh.Add(time.Hour))
+
+	assert.Greater(t, smallScore, 0.95)
+	assert.Equal(t, 0.0, highScore)
+	assert.Greater(t, smallScore, highScore)
+
+	// Typical transfers do not deviate
+	assert.Equal(t, 0.0, baselineDeviation(smallSpender, 20, lastSmall.Add(24*time.Hour)))
+}
+
+func TestBaselineDeviation_RapidTransfersDeviate(t *testing.T) {
+	baseline, last := establishBaseline(50, 24*time.Hour, 30)
+
+	usual := baselineDeviation(baseline, 50, last.Add(24*time.Hour))
+	rapid := baselineDeviation(baseline, 50, last.Add(time.Minute))
+
+	assert.Equal(t, 0.0, usual)
+	assert.Greater(t, rapid, 0.98)
+}
+
+func TestBaselineDeviation_NewWalletsStartFromConservativePrior(t *testing.T) {
+	baseline := newWalletBaseline(uuid.New(), models.USDCBDC)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, baseline.Observations)
+	assert.Equal(t, 0.0, baselineDeviation(baseline, baselinePriorAmount, now))
+	assert.Equal(t, 0.0, baselineDeviation(baseline, baselineTolerance*baselinePriorAmount, now))
+	assert.InDelta(t, 0.8, baselineDeviation(baseline, 10*baselinePriorAmount, now), 1e-9)
+
+	// The prior counts as one observation, so a first transfer moves the average halfway
+	observeBaseline(baseline, 300, now)
+	assert.Equal(t, 200.0, baseline.AmountEMA)
+	assert.Equal(t, 1, baseline.Observations)
+	assert.Equal(t, now, *baseline.LastTransactionAt)
+}
+
+func TestWalletRisk_OldScoresDecay(t *testing.T) {
+	halfLife := 7 * 24 * time.Hour
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Two wallets each have one equally high score, one of them four half-lives ago
+	stale := newWalletBaseline(uuid.New(), models.USDCBDC)
+	observeRisk(stale, 0.9, now.Add(-4*halfLife), halfLife)
+	recent := newWalletBaseline(uuid.New(), models.USDCBDC)
+	observeRisk(recent, 0.9, now, halfLife)
+
+	assert.InDelta(t, 0.9, riskAt(recent, now, halfLife), 1e-9)
+	assert.InDelta(t, 0.9/16, riskAt(stale, now, halfLife), 1e-9)
+	assert.Less(t, riskAt(stale, now, halfLife), riskAt(recent, now, halfLife))
+
+	// A new low score outweighs the faded high one, but not a fresh one
+	observeRisk(stale, 0.1, now, halfLife)
+	observeRisk(recent, 0.1, now, halfLife)
+	assert.Less(t, stale.RiskEMA, recent.RiskEMA)
+	assert.Equal(t, 2, stale.RiskObservations)
+
+	// Without a half-life scores never fade, and the baseline's amounts are untouched
+	undecayed := newWalletBaseline(uuid.New(), models.USDCBDC)
+	observeRisk(undecayed, 0.9, now.Add(-4*halfLife), 0)
+	assert.InDelta(t, 0.9, riskAt(undecayed, now, 0), 1e-9)
+	assert.Equal(t, baselinePriorAmount, undecayed.AmountEMA)
+	assert.Equal(t, 0, undecayed.Observations)
+
+	// A wallet that was never scored carries no risk
+	assert.Equal(t, 0.0, riskAt(newWalletBaseline(uuid.New(), models.USDCBDC), now, halfLife))
+}
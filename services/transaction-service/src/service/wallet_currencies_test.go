@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"echopay/shared/libraries/errors"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+func TestWallet_PermitsCurrency(t *testing.T) {
+	unrestricted := &repository.Wallet{}
+	assert.True(t, unrestricted.PermitsCurrency(string(models.EURCBDC)))
+
+	restricted := &repository.Wallet{AllowedCurrencies: []string{string(models.USDCBDC)}}
+	assert.True(t, restricted.PermitsCurrency(string(models.USDCBDC)))
+	assert.False(t, restricted.PermitsCurrency(string(models.EURCBDC)))
+}
+
+func TestTransactionService_WalletAllowedCurrencies(t *testing.T) {
+	service, db := setupTestService(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	transfer := func(from, to uuid.UUID, currency models.Currency) error {
+		_, err := service.ProcessTransaction(ctx, &TransactionRequest{
+			FromWallet: from,
+			ToWallet:   to,
+			Amount:     10.0,
+			Currency:   currency,
+		})
+		return err
+	}
+	assertNotPermitted := func(t *testing.T, err error) {
+		require.Error(t, err)
+		txErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrCurrencyNotPermitted, txErr.Code)
+		assert.Equal(t, 403, txErr.GetHTTPStatus())
+	}
+
+	usdOnly, err := service.CreateWallet(ctx, &CreateWalletRequest{
+		OwnerID:           uuid.New(),
+		AllowedCurrencies: []models.Currency{models.USDCBDC, models.USDCBDC},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{string(models.USDCBDC)}, usdOnly.AllowedCurrencies)
+
+	t.Run("only allowed currencies are seeded", func(t *testing.T) {
+		balances, err := service.balanceRepo.GetWalletBalances(usdOnly.ID)
+		require.NoError(t, err)
+		require.Len(t, balances, 1)
+		assert.Equal(t, models.USDCBDC, balances[0].Currency)
+
+		stored, err := service.GetWallet(ctx, usdOnly.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{string(models.USDCBDC)}, stored.AllowedCurrencies)
+	})
+
+	t.Run("funding in a disallowed currency is rejected", func(t *testing.T) {
+		_, err := service.FundWallet(ctx, usdOnly.ID, models.EURCBDC, 50.0, "bank-transfer-eur-"+uuid.NewString())
+		assertNotPermitted(t, err)
+
+		result, err := service.FundWallet(ctx, usdOnly.ID, models.USDCBDC, 50.0, "bank-transfer-usd-"+uuid.NewString())
+		require.NoError(t, err)
+		assert.True(t, result.Credited)
+	})
+
+	t.Run("transfers in a disallowed currency are rejected both ways", func(t *testing.T) {
+		other := createTestWallet(t, service)
+		require.NoError(t, service.balanceRepo.AddFunds(other, models.EURCBDC, 100.0))
+		require.NoError(t, service.balanceRepo.AddFunds(other, models.USDCBDC, 100.0))
+
+		assertNotPermitted(t, transfer(other, usdOnly.ID, models.EURCBDC))
+		assertNotPermitted(t, transfer(usdOnly.ID, other, models.EURCBDC))
+
+		assert.NoError(t, transfer(other, usdOnly.ID, models.USDCBDC))
+		assert.NoError(t, transfer(usdOnly.ID, other, models.USDCBDC))
+	})
+
+	t.Run("unsupported currencies cannot be allowed", func(t *testing.T) {
+		_, err := service.CreateWallet(ctx, &CreateWalletRequest{
+			OwnerID:           uuid.New(),
+			AllowedCurrencies: []models.Currency{"JPY-CBDC"},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("a funded currency cannot be disallowed", func(t *testing.T) {
+		_, err := service.SetWalletAllowedCurrencies(ctx, usdOnly.ID, []models.Currency{models.EURCBDC})
+		require.Error(t, err)
+		txErr, ok := err.(*errors.EchoPayError)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrInvalidTransaction, txErr.Code)
+	})
+
+	t.Run("admin can lift the restriction", func(t *testing.T) {
+		wallet, err := service.SetWalletAllowedCurrencies(ctx, usdOnly.ID, nil)
+		require.NoError(t, err)
+		assert.Nil(t, wallet.AllowedCurrencies)
+
+		balances, err := service.balanceRepo.GetWalletBalances(usdOnly.ID)
+		require.NoError(t, err)
+		assert.Len(t, balances, len(service.currencies.Codes()))
+
+		_, err = service.FundWallet(ctx, usdOnly.ID, models.EURCBDC, 50.0, "bank-transfer-eur-"+uuid.NewString())
+		assert.NoError(t, err)
+	})
+
+	t.Run("new wallets default to every supported currency", func(t *testing.T) {
+		wallet, err := service.GetWallet(ctx, createTestWallet(t, service))
+		require.NoError(t, err)
+		assert.Nil(t, wallet.AllowedCurrencies)
+	})
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"echopay/shared/libraries/errors"
+	"echopay/shared/libraries/money"
+	"echopay/transaction-service/src/models"
+	"echopay/transaction-service/src/repository"
+)
+
+// MaxBalanceLookupWallets limits the number of wallets in a single bulk balance fetch
+const MaxBalanceLookupWallets = 200
+
+// MaxFreezeReasonLength caps the reason recorded for an emergency wallet freeze
+const MaxFreezeReasonLength = 500
+
+// MaxFundingRefLength caps the external reference a wallet funding is deduplicated by
+const MaxFundingRefLength = 128
+
+// CreateWalletRequest represents a wallet creation request. AllowedCurrencies restricts the
+// wallet to a subset of the supported currencies; when empty it may hold any of them.
+type CreateWalletRequest struct {
+	OwnerID           uuid.UUID         `json:"owner_id" binding:"required"`
+	AllowedCurrencies []models.Currency `json:"allowed_currencies,omitempty"`
+}
+
+// CreateWallet registers a new active wallet held by the owner's account and seeds it with
+// zero balances for every currency it may hold
+func (s *TransactionService) CreateWallet(ctx context.Context, req *CreateWalletRequest) (*repository.Wallet, error) {
+	if req.OwnerID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "owner ID cannot be nil")
+	}
+
+	allowed, err := s.allowedCurrencies(req.AllowedCurrencies)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	ownerID := req.OwnerID
+	wallet := &repository.Wallet{
+		ID:        uuid.New(),
+		OwnerID:   &ownerID,
+		Status:    repository.WalletStatusActive,
+		AllowedCurrencies: allowed,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		if err := s.walletRepo.CreateInTx(tx, wallet); err != nil {
+			return err
+		}
+		if err := s.walletRepo.LinkAccountInTx(tx, wallet.ID, ownerID); err != nil {
+			return err
+		}
+		if allowed == nil {
+			return s.balanceRepo.CreateWalletInTx(tx, wallet.ID)
+		}
+		return s.balanceRepo.CreateBalancesInTx(tx, wallet.ID, allowed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// GetWallet retrieves a registered wallet
+func (s *TransactionService) GetWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error) {
+	return s.walletRepo.GetByID(walletID)
+}
+
+// SetWalletAllowedCurrencies replaces the currencies a wallet may hold, seeding zero balances
+// for any it could not hold before. An empty list lifts the restriction. A currency the
+// wallet still holds funds in cannot be disallowed.
+func (s *TransactionService) SetWalletAllowedCurrencies(ctx context.Context, walletID uuid.UUID, currencies []models.Currency) (*repository.Wallet, error) {
+	allowed, err := s.allowedCurrencies(currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet *repository.Wallet
+	err = s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		wallet, err = s.walletRepo.GetForUpdateInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		restricted := &repository.Wallet{AllowedCurrencies: allowed}
+		funded, err := s.balanceRepo.FundedCurrenciesInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+		for _, currency := range funded {
+			if !restricted.PermitsCurrency(currency) {
+				return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("cannot disallow %s while the wallet holds funds in it", currency))
+			}
+		}
+
+		if err := s.walletRepo.SetAllowedCurrenciesInTx(tx, walletID, allowed); err != nil {
+			return err
+		}
+		if allowed == nil {
+			if err := s.balanceRepo.CreateWalletInTx(tx, walletID); err != nil {
+				return err
+			}
+		} else if err := s.balanceRepo.CreateBalancesInTx(tx, walletID, allowed); err != nil {
+			return err
+		}
+
+		wallet.AllowedCurrencies = allowed
+		wallet.UpdatedAt = s.clock.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// allowedCurrencies validates the currencies a wallet is restricted to, dropping duplicates.
+// An empty list allows every supported currency and is returned as nil.
+func (s *TransactionService) allowedCurrencies(currencies []models.Currency) ([]string, error) {
+	if len(currencies) == 0 {
+		return nil, nil
+	}
+
+	allowed := make([]string, 0, len(currencies))
+	seen := make(map[models.Currency]bool, len(currencies))
+	for _, currency := range currencies {
+		if !s.currencies.Supported(string(currency)) {
+			return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", currency))
+		}
+		if !seen[currency] {
+			seen[currency] = true
+			allowed = append(allowed, string(currency))
+		}
+	}
+	return allowed, nil
+}
+
+// CloseWallet closes a wallet so it can no longer send or receive transfers. Only an empty
+// wallet can be closed.
+func (s *TransactionService) CloseWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error) {
+	var wallet *repository.Wallet
+	var previousStatus repository.WalletStatus
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		wallet, err = s.walletRepo.GetForUpdateInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		if wallet.Status == repository.WalletStatusClosed {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet is already closed")
+		}
+
+		hasFunds, err := s.balanceRepo.HasFundsInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+		if hasFunds {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "cannot close a wallet that still holds funds")
+		}
+
+		if err := s.walletRepo.UpdateStatusInTx(tx, walletID, repository.WalletStatusClosed, ""); err != nil {
+			return err
+		}
+		previousStatus = wallet.Status
+		wallet.Status = repository.WalletStatusClosed
+		wallet.UpdatedAt = s.clock.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logWalletTransition(ctx, walletID, previousStatus, repository.WalletStatusClosed, "")
+
+	return wallet, nil
+}
+
+// EmergencyFreezeWallet freezes a wallet so it can neither send nor receive transfers, and
+// freezes every active token it holds. The token freeze runs inside the wallet's database
+// transaction, so the wallet is left unchanged if the tokens cannot be frozen.
+func (s *TransactionService) EmergencyFreezeWallet(ctx context.Context, walletID uuid.UUID, reason string) (*repository.Wallet, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+
+	reason = strings.TrimSpace(sanitizeMetadataText(reason))
+	if reason == "" {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "a reason is required to freeze a wallet")
+	}
+	if len([]rune(reason)) > MaxFreezeReasonLength {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("freeze reason exceeds maximum length of %d characters", MaxFreezeReasonLength))
+	}
+
+	var wallet *repository.Wallet
+	tokensFrozen := false
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		wallet, err = s.walletRepo.GetForUpdateInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		switch wallet.Status {
+		case repository.WalletStatusClosed:
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, "cannot freeze a closed wallet")
+		case repository.WalletStatusFrozen:
+			return errors.NewTransactionError(errors.ErrWalletFrozen, "wallet is already frozen")
+		}
+
+		if err := s.walletRepo.UpdateStatusInTx(tx, walletID, repository.WalletStatusFrozen, reason); err != nil {
+			return err
+		}
+
+		if s.tokenFreezer != nil {
+			if err := s.tokenFreezer.FreezeWalletTokens(ctx, walletID, reason); err != nil {
+				return errors.WrapError(err, errors.ErrServiceUnavailable, "failed to freeze wallet tokens", "transaction-service")
+			}
+			tokensFrozen = true
+		}
+
+		wallet.Status = repository.WalletStatusFrozen
+		wallet.StatusReason = reason
+		wallet.UpdatedAt = s.clock.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		// The commit failed after the tokens were frozen; release them again on a best-effort basis
+		if tokensFrozen {
+			s.tokenFreezer.UnfreezeWalletTokens(ctx, walletID, "wallet freeze rolled back")
+		}
+		return nil, err
+	}
+	logWalletTransition(ctx, walletID, repository.WalletStatusActive, repository.WalletStatusFrozen, reason)
+
+	return wallet, nil
+}
+
+// RecoverWallet lifts an emergency freeze once the wallet owner has been verified, making the
+// wallet active again and unfreezing the tokens frozen with it
+func (s *TransactionService) RecoverWallet(ctx context.Context, walletID, verifiedBy uuid.UUID) (*repository.Wallet, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+	if verifiedBy == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "recovery must be verified")
+	}
+
+	reason := fmt.Sprintf("recovered after verification by %s", verifiedBy)
+
+	var wallet *repository.Wallet
+	err := s.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		wallet, err = s.walletRepo.GetForUpdateInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+
+		if wallet.Status != repository.WalletStatusFrozen {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("wallet is %s, not frozen", wallet.Status))
+		}
+
+		if err := s.walletRepo.UpdateStatusInTx(tx, walletID, repository.WalletStatusActive, reason); err != nil {
+			return err
+		}
+
+		if s.tokenFreezer != nil {
+			if err := s.tokenFreezer.UnfreezeWalletTokens(ctx, walletID, reason); err != nil {
+				return errors.WrapError(err, errors.ErrServiceUnavailable, "failed to unfreeze wallet tokens", "transaction-service")
+			}
+		}
+
+		wallet.Status = repository.WalletStatusActive
+		wallet.StatusReason = reason
+		wallet.UpdatedAt = s.clock.Now().UTC()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logWalletTransition(ctx, walletID, repository.WalletStatusFrozen, repository.WalletStatusActive, reason)
+
+	return wallet, nil
+}
+
+// SetWalletMinBalance sets the reserve a wallet must keep in a currency. Outgoing transfers
+// that would take the balance below it are rejected; a reserve of zero removes the limit.
+// A balance already below a new reserve is left as it is, but cannot be spent from.
+func (s *TransactionService) SetWalletMinBalance(ctx context.Context, walletID uuid.UUID, currency models.Currency, minBalance float64) (*repository.WalletBalance, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+
+	if !s.currencies.Supported(string(currency)) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", currency))
+	}
+
+	if math.IsNaN(minBalance) || minBalance < 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "minimum balance cannot be negative")
+	}
+
+	if !money.IsExact(minBalance, string(currency)) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("minimum balance has more than %d decimal places", money.Decimals(string(currency))))
+	}
+
+	if _, err := s.walletRepo.GetByID(walletID); err != nil {
+		return nil, err
+	}
+
+	s.balanceMutex.Lock()
+	defer s.balanceMutex.Unlock()
+
+	return s.balanceRepo.SetMinBalance(walletID, currency, minBalance)
+}
+
+// GetBalancesForWallets returns the balances of many wallets in one currency, keyed by
+// wallet. Wallets with no balance in the currency, including unknown wallets, report zero.
+func (s *TransactionService) GetBalancesForWallets(ctx context.Context, walletIDs []uuid.UUID, currency models.Currency) (map[uuid.UUID]float64, error) {
+	if len(walletIDs) == 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "at least one wallet ID is required")
+	}
+	if len(walletIDs) > MaxBalanceLookupWallets {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("cannot fetch balances for more than %d wallets at once", MaxBalanceLookupWallets))
+	}
+
+	for _, walletID := range walletIDs {
+		if walletID == uuid.Nil {
+			return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+		}
+	}
+
+	if !s.currencies.Supported(string(currency)) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", currency))
+	}
+
+	s.balanceMutex.RLock()
+	defer s.balanceMutex.RUnlock()
+
+	return s.balanceRepo.GetBalancesForWallets(walletIDs, currency)
+}
+
+// FundingResult is the outcome of funding a wallet
+type FundingResult struct {
+	Balance  *repository.WalletBalance `json:"balance"`
+	Credited bool                      `json:"credited"` // False when the reference had already been applied
+}
+
+// FundWallet credits a wallet with funds from outside the system, such as a bank transfer or
+// central bank issuance, identified by an external reference. Funding again with the same
+// reference succeeds without crediting the wallet a second time, so callers can safely retry.
+func (s *TransactionService) FundWallet(ctx context.Context, walletID uuid.UUID, currency models.Currency, amount float64, externalRef string) (*FundingResult, error) {
+	if walletID == uuid.Nil {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "wallet ID cannot be nil")
+	}
+
+	externalRef = strings.TrimSpace(externalRef)
+	if externalRef == "" {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "funding requires an external reference")
+	}
+	if len(externalRef) > MaxFundingRefLength {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("external reference cannot exceed %d characters", MaxFundingRefLength))
+	}
+
+	if !s.currencies.Supported(string(currency)) {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("unsupported currency: %s", currency))
+	}
+
+	if math.IsNaN(amount) || amount <= 0 {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "amount must be positive")
+	}
+
+	wallet, err := s.walletRepo.GetByID(walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Status == repository.WalletStatusClosed {
+		return nil, errors.NewTransactionError(errors.ErrInvalidTransaction, "cannot fund a closed wallet")
+	}
+	if !wallet.PermitsCurrency(string(currency)) {
+		return nil, currencyNotPermitted(walletID, currency)
+	}
+
+	s.balanceMutex.Lock()
+	defer s.balanceMutex.Unlock()
+
+	credited, err := s.balanceRepo.AddFundsWithRef(walletID, currency, amount, externalRef)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.balanceRepo.GetBalance(walletID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FundingResult{Balance: balance, Credited: credited}, nil
+}
+
+// checkWalletsInTx rejects transfers involving a wallet that was never created, has been
+// closed, is frozen, or may not hold the transfer's currency. The wallets stay share-locked
+// until the transaction ends so they cannot be closed or restricted while funds move.
+func (s *TransactionService) checkWalletsInTx(tx *sql.Tx, transactions ...*models.Transaction) error {
+	// Lock in a deterministic order, once per wallet
+	currencies := make(map[uuid.UUID][]models.Currency, 2*len(transactions))
+	ordered := make([]uuid.UUID, 0, 2*len(transactions))
+	for _, transaction := range transactions {
+		for _, walletID := range []uuid.UUID{transaction.FromWallet, transaction.ToWallet} {
+			if _, seen := currencies[walletID]; !seen {
+				ordered = append(ordered, walletID)
+			}
+			currencies[walletID] = append(currencies[walletID], transaction.Currency)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return bytes.Compare(ordered[i][:], ordered[j][:]) < 0
+	})
+
+	for _, walletID := range ordered {
+		wallet, err := s.walletRepo.GetForShareInTx(tx, walletID)
+		if err != nil {
+			return err
+		}
+		if wallet.Status == repository.WalletStatusClosed {
+			return errors.NewTransactionError(errors.ErrInvalidTransaction, fmt.Sprintf("wallet %s is closed", walletID))
+		}
+		if wallet.Status == repository.WalletStatusFrozen {
+			return errors.NewTransactionError(errors.ErrWalletFrozen, fmt.Sprintf("wallet %s is frozen", walletID))
+		}
+		for _, currency := range currencies[walletID] {
+			if !wallet.PermitsCurrency(string(currency)) {
+				return currencyNotPermitted(walletID, currency)
+			}
+		}
+	}
+
+	return nil
+}
+
+// currencyNotPermitted reports that a wallet may not hold a currency
+func currencyNotPermitted(walletID uuid.UUID, currency models.Currency) error {
+	return errors.NewTransactionError(errors.ErrCurrencyNotPermitted, fmt.Sprintf("wallet %s is not permitted to hold %s", walletID, currency))
+}
@@ -0,0 +1,36 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealClockFollowsSystemTime(t *testing.T) {
+	before := time.Now()
+	now := Real().Now()
+	after := time.Now()
+
+	if now.Before(before) || now.After(after) {
+		t.Errorf("Real().Now() = %v, expected between %v and %v", now, before, after)
+	}
+}
+
+func TestFakeClockOnlyMovesWhenTold(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	if got := fake.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, expected %v", got, start)
+	}
+
+	fake.Advance(90 * time.Second)
+	if got, expected := fake.Now(), start.Add(90*time.Second); !got.Equal(expected) {
+		t.Errorf("Now() after Advance = %v, expected %v", got, expected)
+	}
+
+	later := start.Add(24 * time.Hour)
+	fake.Set(later)
+	if got := fake.Now(); !got.Equal(later) {
+		t.Errorf("Now() after Set = %v, expected %v", got, later)
+	}
+}
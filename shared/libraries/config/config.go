@@ -3,17 +3,21 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"echopay/shared/libraries/currency"
 )
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	Database string
-	User     string
-	Password string
-	SSLMode  string
+	Host             string
+	Port             int
+	Database         string
+	User             string
+	Password         string
+	SSLMode          string
+	StatementTimeout time.Duration // Server-side limit on each statement; zero leaves PostgreSQL's default
 }
 
 // KafkaConfig holds Kafka connection configuration
@@ -41,15 +45,209 @@ type ServiceConfig struct {
 	WriteTimeout    time.Duration
 }
 
+// SigningConfig holds issuer signing key configuration
+type SigningConfig struct {
+	KeyID            string
+	PrivateKey       string            // Base64-encoded Ed25519 seed or private key
+	VerificationKeys map[string]string // Retired key IDs mapped to base64-encoded public keys
+}
+
+// EncryptionConfig holds field-level encryption key configuration
+type EncryptionConfig struct {
+	Enabled        bool
+	KeyID          string
+	Key            string            // Base64-encoded 256-bit AES key
+	DecryptionKeys map[string]string // Retired key IDs mapped to base64-encoded AES keys
+}
+
+// WebhookConfig holds outbound webhook delivery configuration. Endpoint registrations are
+// kept in memory by each service instance, so they are lost on restart and are not shared
+// between replicas.
+type WebhookConfig struct {
+	Timeout             time.Duration // Per-attempt HTTP timeout
+	MaxRetries          int           // Retries after the first failed attempt
+	RetryBackoff        time.Duration // Base delay between attempts, multiplied by the attempt number
+	AllowedHosts        []string      // Hosts endpoints may target; empty allows any host that resolves to a public address
+	AllowPrivateTargets bool          // Permit loopback, private and link-local targets; for local development only
+}
+
+// ReasonCodeConfig holds validation settings for freeze and unfreeze reason codes
+type ReasonCodeConfig struct {
+	AllowFreeText bool // Accept reasons that are not known codes as legacy free text
+}
+
+// TokenIntegrityConfig holds settings for verifying stored token checksums
+type TokenIntegrityConfig struct {
+	StrictChecksums bool // Fail reads of tokens whose stored checksum does not match instead of logging a warning
+}
+
+// BulkUpdateConfig holds settings for bulk token status updates
+type BulkUpdateConfig struct {
+	BatchSize    int // Token IDs updated per statement; all batches share one transaction
+	AuditWorkers int // Workers preparing audit entries concurrently for each batch
+}
+
+// TokenCacheConfig holds the in-memory cache of token reads
+type TokenCacheConfig struct {
+	Size int           // Tokens cached; zero or less disables the cache
+	TTL  time.Duration // How long a cached token is served before it is read again
+}
+
+// CurrencyConfig holds the currency codes and CBDC types the services accept
+type CurrencyConfig struct {
+	Supported []string       // Currency codes, e.g. USD-CBDC
+	Decimals  map[string]int // Minor-unit decimal places by currency code, for currencies that do not use 2
+}
+
+// IssuanceConfig holds token issuance limits
+type IssuanceConfig struct {
+	MaxDenomination   float64 // Default maximum denomination for CBDC types without a rule
+	DenominationRules string  // JSON object of per-CBDC-type rules, e.g. {"USD-CBDC":{"allowed":[1,5,10]}}
+}
+
+// TransactionServiceConfig holds how other services reach the transaction service
+type TransactionServiceConfig struct {
+	URL     string        // Base URL, e.g. http://transaction-service:8001
+	Timeout time.Duration // Per-request HTTP timeout
+}
+
+// TokenServiceConfig holds how other services reach the token management service
+type TokenServiceConfig struct {
+	URL     string        // Base URL, e.g. http://token-management:8003
+	Timeout time.Duration // Per-request HTTP timeout
+}
+
+// FraudConfig holds the transaction service's responses to fraud scores
+type FraudConfig struct {
+	AutoFreezeEnabled   bool          // Freeze the tokens moved by a transaction scored above the threshold; off unless explicitly enabled
+	AutoFreezeThreshold float64       // Fraud score, between 0 and 1, that a transaction must exceed to be auto-frozen
+	CriticalThreshold   float64       // Fraud score that raises an immediate high-risk alert when exceeded
+	ScoreHalfLife       time.Duration // Age at which a fraud score counts half as much towards wallet risk; zero disables decay
+}
+
+// AuditVerificationConfig holds limits on verifying transaction audit trails as they are read
+type AuditVerificationConfig struct {
+	MaxEntries         int           // Audit entries verified on read; longer trails are verified in full offline
+	FullVerifyInterval time.Duration // How often transactions flagged for full verification are checked
+}
+
+// AuditBatchConfig holds limits on fetching the audit trails of several tokens at once
+type AuditBatchConfig struct {
+	MaxTokens         int // Tokens one request may fetch
+	RequestsPerMinute int // Batch fetches allowed per client each minute
+}
+
+// CategoryConfig holds the transaction categories the transaction service accepts
+type CategoryConfig struct {
+	Allowed []string // Category names; empty accepts any category
+}
+
+// DatabaseMetricsConfig holds the connection pool metrics and slow query log configuration
+type DatabaseMetricsConfig struct {
+	StatsInterval      time.Duration // How often pool statistics are copied into the metrics
+	SlowQueryThreshold time.Duration // Statements at least this slow are logged; zero disables the log
+}
+
+// HTTPMetricsConfig holds how HTTP request metrics are labeled
+type HTTPMetricsConfig struct {
+	RouteLabels    bool     // Label by route template, e.g. /tokens/:id; off records every request under one endpoint
+	ExcludedRoutes []string // Route templates not recorded, such as probes and the metrics endpoint
+}
+
+// LoadSheddingConfig holds when low-priority routes are shed and for how long clients back off
+type LoadSheddingConfig struct {
+	CheckInterval time.Duration // How often the database is checked for overload
+	RetryAfter    time.Duration // Sent to shed clients in the Retry-After header
+}
+
+// SchemaCheckConfig holds how often the deep health check verifies the schema version
+type SchemaCheckConfig struct {
+	Interval time.Duration // How often the applied schema version is compared with the binary's
+}
+
+// RequestTimeoutConfig holds how long API requests may run before they are cancelled
+type RequestTimeoutConfig struct {
+	Default time.Duration // Most routes; zero disables the timeout
+	Long    time.Duration // Bulk operations and reports that touch many rows
+}
+
+// SettlementConfig holds when the transaction service settles transfers
+type SettlementConfig struct {
+	Mode string // "instant" or "delayed"; delayed holds funds until a transfer is settled
+}
+
+// FeeConfig holds per-transaction fee collection settings
+type FeeConfig struct {
+	Enabled          bool    // Charge fees; off unless explicitly enabled
+	CollectionWallet string  // Wallet ID credited with collected fees; required when enabled
+	FlatFee          float64 // Charged on every transaction
+	Percentage       float64 // Fraction of the amount added to the flat fee, e.g. 0.001 for 0.1%
+	MaxFee           float64 // Cap on the fee for one transaction; zero means no cap
+}
+
+// ReadOnlyConfig holds whether a service starts in read-only mode, rejecting writes
+type ReadOnlyConfig struct {
+	Enabled bool
+	Reason  string // Reported to clients whose writes are rejected
+}
+
+// GRPCConfig holds configuration for a service's internal gRPC listener
+type GRPCConfig struct {
+	Port int
+}
+
+// CORSConfig holds which browser origins may call a service. No origins are allowed by default.
+type CORSConfig struct {
+	AllowedOrigins   []string // Exact origins, e.g. https://wallet.example.com
+	AllowedMethods   []string // Empty means the middleware defaults
+	AllowedHeaders   []string // Empty means the middleware defaults
+	AllowCredentials bool
+}
+
+// ProfilingConfig holds the opt-in runtime profiling listener configuration
+type ProfilingConfig struct {
+	Enabled bool   // Serve pprof endpoints; off unless explicitly enabled
+	Address string // Internal bind address, e.g. 127.0.0.1:6060
+}
+
+// ReportingConfig holds configuration for aggregate reports
+type ReportingConfig struct {
+	Timezone string // IANA zone whose calendar days reports cover, e.g. Europe/Berlin
+}
+
+// ReplayProtectionConfig holds the anti-replay checks applied to token transfer requests
+type ReplayProtectionConfig struct {
+	RequireNonce bool          // Reject transfers that carry no nonce
+	MaxClockSkew time.Duration // How far a request timestamp may differ from the server clock
+}
+
+// AuditRetentionConfig holds how long token audit entries stay in the live audit trail
+type AuditRetentionConfig struct {
+	Retention time.Duration // Entries older than this are archived; zero disables archival
+	Interval  time.Duration // How often archival runs
+}
+
+// TransferVelocityConfig holds how often a single token may change hands
+type TransferVelocityConfig struct {
+	MaxTransfers int           // Ownership transfers allowed per token within Window; zero or less is unlimited
+	Window       time.Duration // Sliding window the transfers are counted over
+}
+
+// SettlementFinalityConfig holds how long a transferred token must rest before it can be destroyed
+type SettlementFinalityConfig struct {
+	FinalityWindow time.Duration // Time after a token's latest transfer before it is final; zero makes transfers final at once
+}
+
 // GetDatabaseConfig returns database configuration from environment variables
 func GetDatabaseConfig() DatabaseConfig {
 	return DatabaseConfig{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnvAsInt("DB_PORT", 5432),
-		Database: getEnv("DB_NAME", "echopay"),
-		User:     getEnv("DB_USER", "echopay"),
-		Password: getEnv("DB_PASSWORD", "echopay_dev"),
-		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+		Host:             getEnv("DB_HOST", "localhost"),
+		Port:             getEnvAsInt("DB_PORT", 5432),
+		Database:         getEnv("DB_NAME", "echopay"),
+		User:             getEnv("DB_USER", "echopay"),
+		Password:         getEnv("DB_PASSWORD", "echopay_dev"),
+		SSLMode:          getEnv("DB_SSL_MODE", "disable"),
+		StatementTimeout: getEnvAsDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
 	}
 }
 
@@ -85,6 +283,287 @@ func GetServiceConfig(defaultPort int) ServiceConfig {
 	}
 }
 
+// GetSigningConfig returns signing key configuration from environment variables.
+// TOKEN_VERIFICATION_KEYS is a comma-separated list of keyID=publicKey pairs.
+func GetSigningConfig() SigningConfig {
+	return SigningConfig{
+		KeyID:            getEnv("TOKEN_SIGNING_KEY_ID", ""),
+		PrivateKey:       getEnv("TOKEN_SIGNING_PRIVATE_KEY", ""),
+		VerificationKeys: getEnvAsKeyMap("TOKEN_VERIFICATION_KEYS"),
+	}
+}
+
+// GetReceiptSigningConfig returns the transaction receipt signing key configuration from
+// environment variables. RECEIPT_VERIFICATION_KEYS is a comma-separated list of
+// keyID=publicKey pairs for retired keys.
+func GetReceiptSigningConfig() SigningConfig {
+	return SigningConfig{
+		KeyID:            getEnv("RECEIPT_SIGNING_KEY_ID", ""),
+		PrivateKey:       getEnv("RECEIPT_SIGNING_PRIVATE_KEY", ""),
+		VerificationKeys: getEnvAsKeyMap("RECEIPT_VERIFICATION_KEYS"),
+	}
+}
+
+// GetEncryptionConfig returns metadata encryption configuration from environment variables.
+// Encryption is disabled unless METADATA_ENCRYPTION_ENABLED is set.
+func GetEncryptionConfig() EncryptionConfig {
+	return EncryptionConfig{
+		Enabled:        getEnvAsBool("METADATA_ENCRYPTION_ENABLED", false),
+		KeyID:          getEnv("METADATA_ENCRYPTION_KEY_ID", ""),
+		Key:            getEnv("METADATA_ENCRYPTION_KEY", ""),
+		DecryptionKeys: getEnvAsKeyMap("METADATA_DECRYPTION_KEYS"),
+	}
+}
+
+// GetWebhookConfig returns webhook delivery configuration from environment variables
+func GetWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		Timeout:             getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		MaxRetries:          getEnvAsInt("WEBHOOK_MAX_RETRIES", 3),
+		RetryBackoff:        getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", time.Second),
+		AllowedHosts:        getEnvAsList("WEBHOOK_ALLOWED_HOSTS", nil),
+		AllowPrivateTargets: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+	}
+}
+
+// GetReasonCodeConfig returns reason code validation configuration from environment variables
+func GetReasonCodeConfig() ReasonCodeConfig {
+	return ReasonCodeConfig{
+		AllowFreeText: getEnvAsBool("REASON_CODES_ALLOW_FREE_TEXT", false),
+	}
+}
+
+// GetTokenIntegrityConfig returns token checksum verification configuration from environment
+// variables
+func GetTokenIntegrityConfig() TokenIntegrityConfig {
+	return TokenIntegrityConfig{
+		StrictChecksums: getEnvAsBool("TOKEN_CHECKSUM_STRICT", false),
+	}
+}
+
+// GetBulkUpdateConfig returns bulk status update configuration from environment variables
+func GetBulkUpdateConfig() BulkUpdateConfig {
+	return BulkUpdateConfig{
+		BatchSize:    getEnvAsInt("BULK_UPDATE_BATCH_SIZE", 500),
+		AuditWorkers: getEnvAsInt("BULK_UPDATE_AUDIT_WORKERS", 4),
+	}
+}
+
+// GetTokenCacheConfig returns token read cache configuration from environment variables. The
+// cache is off unless TOKEN_CACHE_SIZE is set.
+func GetTokenCacheConfig() TokenCacheConfig {
+	return TokenCacheConfig{
+		Size: getEnvAsInt("TOKEN_CACHE_SIZE", 0),
+		TTL:  getEnvAsDuration("TOKEN_CACHE_TTL", 2*time.Second),
+	}
+}
+
+// GetCurrencyConfig returns supported currency configuration from environment variables.
+// SUPPORTED_CURRENCIES is a comma-separated list of currency codes, and CURRENCY_DECIMALS a
+// comma-separated list of code=places pairs, e.g. JPY-CBDC=0,BHD-CBDC=3.
+func GetCurrencyConfig() CurrencyConfig {
+	decimals := make(map[string]int)
+	for code, value := range getEnvAsKeyMap("CURRENCY_DECIMALS") {
+		if places, err := strconv.Atoi(value); err == nil {
+			decimals[code] = places
+		}
+	}
+
+	return CurrencyConfig{
+		Supported: getEnvAsList("SUPPORTED_CURRENCIES", currency.DefaultCurrencies),
+		Decimals:  decimals,
+	}
+}
+
+// GetGRPCConfig returns gRPC listener configuration from environment variables
+func GetGRPCConfig(defaultPort int) GRPCConfig {
+	return GRPCConfig{
+		Port: getEnvAsInt("GRPC_PORT", defaultPort),
+	}
+}
+
+// GetIssuanceConfig returns token issuance configuration from environment variables
+func GetIssuanceConfig() IssuanceConfig {
+	return IssuanceConfig{
+		MaxDenomination:   getEnvAsFloat("MAX_DENOMINATION", 1000000),
+		DenominationRules: getEnv("DENOMINATION_RULES", ""),
+	}
+}
+
+// GetTransactionServiceConfig returns transaction service client configuration from environment variables
+func GetTransactionServiceConfig() TransactionServiceConfig {
+	return TransactionServiceConfig{
+		URL:     getEnv("TRANSACTION_SERVICE_URL", "http://localhost:8001"),
+		Timeout: getEnvAsDuration("TRANSACTION_SERVICE_TIMEOUT", 5*time.Second),
+	}
+}
+
+// GetTokenServiceConfig returns token management service client configuration from environment variables
+func GetTokenServiceConfig() TokenServiceConfig {
+	return TokenServiceConfig{
+		URL:     getEnv("TOKEN_SERVICE_URL", "http://localhost:8003"),
+		Timeout: getEnvAsDuration("TOKEN_SERVICE_TIMEOUT", 5*time.Second),
+	}
+}
+
+// GetFraudConfig returns fraud score response configuration from environment variables
+func GetFraudConfig() FraudConfig {
+	return FraudConfig{
+		AutoFreezeEnabled:   getEnvAsBool("FRAUD_AUTO_FREEZE_ENABLED", false),
+		AutoFreezeThreshold: getEnvAsFloat("FRAUD_AUTO_FREEZE_THRESHOLD", 0.9),
+		CriticalThreshold:   getEnvAsFloat("FRAUD_CRITICAL_THRESHOLD", 0.95),
+		ScoreHalfLife:       getEnvAsDuration("FRAUD_SCORE_HALF_LIFE", 7*24*time.Hour),
+	}
+}
+
+// GetAuditVerificationConfig returns audit trail verification configuration from environment
+// variables
+func GetAuditVerificationConfig() AuditVerificationConfig {
+	return AuditVerificationConfig{
+		MaxEntries:         getEnvAsInt("AUDIT_VERIFY_MAX_ENTRIES", 10000),
+		FullVerifyInterval: getEnvAsDuration("AUDIT_FULL_VERIFY_INTERVAL", 5*time.Minute),
+	}
+}
+
+// GetAuditBatchConfig returns audit trail batch fetch configuration from environment variables
+func GetAuditBatchConfig() AuditBatchConfig {
+	return AuditBatchConfig{
+		MaxTokens:         getEnvAsInt("AUDIT_BATCH_MAX_TOKENS", 100),
+		RequestsPerMinute: getEnvAsInt("AUDIT_BATCH_RATE_LIMIT", 30),
+	}
+}
+
+// GetCategoryConfig returns transaction category configuration from environment variables.
+// TRANSACTION_CATEGORIES is a comma-separated list of category names.
+func GetCategoryConfig() CategoryConfig {
+	return CategoryConfig{
+		Allowed: getEnvAsList("TRANSACTION_CATEGORIES", nil),
+	}
+}
+
+// GetDatabaseMetricsConfig returns database metrics configuration from environment variables
+func GetDatabaseMetricsConfig() DatabaseMetricsConfig {
+	return DatabaseMetricsConfig{
+		StatsInterval:      getEnvAsDuration("DB_STATS_INTERVAL", 15*time.Second),
+		SlowQueryThreshold: getEnvAsDuration("DB_SLOW_QUERY_THRESHOLD", 0),
+	}
+}
+
+// GetHTTPMetricsConfig returns HTTP request metrics configuration from environment variables.
+// HTTP_METRICS_EXCLUDED_ROUTES is a comma-separated list of route templates.
+func GetHTTPMetricsConfig() HTTPMetricsConfig {
+	return HTTPMetricsConfig{
+		RouteLabels:    getEnvAsBool("HTTP_METRICS_ROUTE_LABELS", true),
+		ExcludedRoutes: getEnvAsList("HTTP_METRICS_EXCLUDED_ROUTES", []string{"/metrics", "/health", "/readyz"}),
+	}
+}
+
+// GetLoadSheddingConfig returns load shedding configuration from environment variables
+func GetLoadSheddingConfig() LoadSheddingConfig {
+	return LoadSheddingConfig{
+		CheckInterval: getEnvAsDuration("LOAD_SHED_CHECK_INTERVAL", time.Second),
+		RetryAfter:    getEnvAsDuration("LOAD_SHED_RETRY_AFTER", 5*time.Second),
+	}
+}
+
+// GetSchemaCheckConfig returns schema version check configuration from environment variables
+func GetSchemaCheckConfig() SchemaCheckConfig {
+	return SchemaCheckConfig{
+		Interval: getEnvAsDuration("SCHEMA_CHECK_INTERVAL", 30*time.Second),
+	}
+}
+
+// GetRequestTimeoutConfig returns API request timeout configuration from environment variables
+func GetRequestTimeoutConfig() RequestTimeoutConfig {
+	return RequestTimeoutConfig{
+		Default: getEnvAsDuration("REQUEST_TIMEOUT", 10*time.Second),
+		Long:    getEnvAsDuration("REQUEST_TIMEOUT_LONG", 60*time.Second),
+	}
+}
+
+// GetSettlementConfig returns settlement configuration from environment variables
+func GetSettlementConfig() SettlementConfig {
+	return SettlementConfig{
+		Mode: getEnv("SETTLEMENT_MODE", "instant"),
+	}
+}
+
+// GetFeeConfig returns transaction fee configuration from environment variables
+func GetFeeConfig() FeeConfig {
+	return FeeConfig{
+		Enabled:          getEnvAsBool("FEES_ENABLED", false),
+		CollectionWallet: getEnv("FEE_COLLECTION_WALLET", ""),
+		FlatFee:          getEnvAsFloat("FEE_FLAT", 0),
+		Percentage:       getEnvAsFloat("FEE_PERCENTAGE", 0),
+		MaxFee:           getEnvAsFloat("FEE_MAX", 0),
+	}
+}
+
+// GetReadOnlyConfig returns read-only mode configuration from environment variables
+func GetReadOnlyConfig() ReadOnlyConfig {
+	return ReadOnlyConfig{
+		Enabled: getEnvAsBool("READ_ONLY_MODE", false),
+		Reason:  getEnv("READ_ONLY_REASON", "maintenance"),
+	}
+}
+
+// GetCORSConfig returns CORS configuration from environment variables. CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS are comma-separated lists.
+func GetCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   getEnvAsList("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods:   getEnvAsList("CORS_ALLOWED_METHODS", nil),
+		AllowedHeaders:   getEnvAsList("CORS_ALLOWED_HEADERS", nil),
+		AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
+	}
+}
+
+// GetProfilingConfig returns profiling listener configuration from environment variables
+func GetProfilingConfig() ProfilingConfig {
+	return ProfilingConfig{
+		Enabled: getEnvAsBool("PROFILING_ENABLED", false),
+		Address: getEnv("PROFILING_ADDR", "127.0.0.1:6060"),
+	}
+}
+
+// GetReportingConfig returns reporting configuration from environment variables
+func GetReportingConfig() ReportingConfig {
+	return ReportingConfig{
+		Timezone: getEnv("REPORTING_TIMEZONE", "UTC"),
+	}
+}
+
+// GetReplayProtectionConfig returns transfer anti-replay configuration from environment variables
+func GetReplayProtectionConfig() ReplayProtectionConfig {
+	return ReplayProtectionConfig{
+		RequireNonce: getEnvAsBool("TRANSFER_NONCE_REQUIRED", false),
+		MaxClockSkew: getEnvAsDuration("TRANSFER_MAX_CLOCK_SKEW", 5*time.Minute),
+	}
+}
+
+// GetAuditRetentionConfig returns audit trail retention configuration from environment variables
+func GetAuditRetentionConfig() AuditRetentionConfig {
+	return AuditRetentionConfig{
+		Retention: getEnvAsDuration("AUDIT_RETENTION", 0),
+		Interval:  getEnvAsDuration("AUDIT_ARCHIVE_INTERVAL", time.Hour),
+	}
+}
+
+// GetTransferVelocityConfig returns per-token transfer velocity limits from environment variables
+func GetTransferVelocityConfig() TransferVelocityConfig {
+	return TransferVelocityConfig{
+		MaxTransfers: getEnvAsInt("TRANSFER_VELOCITY_MAX", 0),
+		Window:       getEnvAsDuration("TRANSFER_VELOCITY_WINDOW", time.Hour),
+	}
+}
+
+// GetSettlementFinalityConfig returns the token settlement finality window from environment variables
+func GetSettlementFinalityConfig() SettlementFinalityConfig {
+	return SettlementFinalityConfig{
+		FinalityWindow: getEnvAsDuration("TOKEN_FINALITY_WINDOW", 0),
+	}
+}
+
 // Helper functions to get environment variables with defaults
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -102,6 +581,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -111,6 +599,32 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsKeyMap parses a comma-separated list of keyID=value pairs
+func getEnvAsKeyMap(key string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(getEnv(key, ""), ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			keys[parts[0]] = parts[1]
+		}
+	}
+	return keys
+}
+
+// getEnvAsList parses a comma-separated list, ignoring empty entries
+func getEnvAsList(key string, defaultValue []string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -21,6 +21,10 @@ func TestGetDatabaseConfig(t *testing.T) {
 	if config.Database != "echopay" {
 		t.Errorf("Expected default database 'echopay', got %s", config.Database)
 	}
+	
+	if config.StatementTimeout != 30*time.Second {
+		t.Errorf("Expected default statement timeout 30s, got %v", config.StatementTimeout)
+	}
 }
 
 func TestGetDatabaseConfigWithEnvVars(t *testing.T) {
@@ -30,6 +34,7 @@ func TestGetDatabaseConfigWithEnvVars(t *testing.T) {
 	os.Setenv("DB_NAME", "testdb")
 	os.Setenv("DB_USER", "testuser")
 	os.Setenv("DB_PASSWORD", "testpass")
+	os.Setenv("DB_STATEMENT_TIMEOUT", "5s")
 	
 	defer func() {
 		// Clean up
@@ -38,6 +43,7 @@ func TestGetDatabaseConfigWithEnvVars(t *testing.T) {
 		os.Unsetenv("DB_NAME")
 		os.Unsetenv("DB_USER")
 		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("DB_STATEMENT_TIMEOUT")
 	}()
 	
 	config := GetDatabaseConfig()
@@ -61,6 +67,10 @@ func TestGetDatabaseConfigWithEnvVars(t *testing.T) {
 	if config.Password != "testpass" {
 		t.Errorf("Expected password 'testpass', got %s", config.Password)
 	}
+	
+	if config.StatementTimeout != 5*time.Second {
+		t.Errorf("Expected statement timeout 5s, got %v", config.StatementTimeout)
+	}
 }
 
 func TestGetServiceConfig(t *testing.T) {
@@ -133,6 +143,658 @@ func TestGetConnectionString(t *testing.T) {
 	}
 }
 
+func TestGetSigningConfigWithEnvVars(t *testing.T) {
+	os.Setenv("TOKEN_SIGNING_KEY_ID", "fed-2025-02")
+	os.Setenv("TOKEN_SIGNING_PRIVATE_KEY", "c2VjcmV0")
+	os.Setenv("TOKEN_VERIFICATION_KEYS", "fed-2025-01=cHViMQ==, malformed ,fed-2024-12=cHViMg==")
+	
+	defer func() {
+		os.Unsetenv("TOKEN_SIGNING_KEY_ID")
+		os.Unsetenv("TOKEN_SIGNING_PRIVATE_KEY")
+		os.Unsetenv("TOKEN_VERIFICATION_KEYS")
+	}()
+	
+	config := GetSigningConfig()
+	
+	if config.KeyID != "fed-2025-02" {
+		t.Errorf("Expected key ID 'fed-2025-02', got %s", config.KeyID)
+	}
+	
+	if config.PrivateKey != "c2VjcmV0" {
+		t.Errorf("Expected private key 'c2VjcmV0', got %s", config.PrivateKey)
+	}
+	
+	if len(config.VerificationKeys) != 2 {
+		t.Errorf("Expected 2 verification keys, got %d", len(config.VerificationKeys))
+	}
+	
+	if config.VerificationKeys["fed-2025-01"] != "cHViMQ==" {
+		t.Errorf("Expected verification key 'cHViMQ==', got %s", config.VerificationKeys["fed-2025-01"])
+	}
+}
+
+func TestGetReceiptSigningConfig(t *testing.T) {
+	if config := GetReceiptSigningConfig(); config.KeyID != "" || config.PrivateKey != "" {
+		t.Errorf("Expected receipt signing to be unconfigured by default, got %+v", config)
+	}
+	
+	os.Setenv("RECEIPT_SIGNING_KEY_ID", "receipts-2025-02")
+	os.Setenv("RECEIPT_SIGNING_PRIVATE_KEY", "c2VjcmV0")
+	os.Setenv("RECEIPT_VERIFICATION_KEYS", "receipts-2025-01=cHViMQ==")
+	os.Setenv("TOKEN_SIGNING_KEY_ID", "fed-2025-02")
+	
+	defer func() {
+		os.Unsetenv("RECEIPT_SIGNING_KEY_ID")
+		os.Unsetenv("RECEIPT_SIGNING_PRIVATE_KEY")
+		os.Unsetenv("RECEIPT_VERIFICATION_KEYS")
+		os.Unsetenv("TOKEN_SIGNING_KEY_ID")
+	}()
+	
+	config := GetReceiptSigningConfig()
+	
+	if config.KeyID != "receipts-2025-02" || config.PrivateKey != "c2VjcmV0" {
+		t.Errorf("Expected the receipt signing key, got %+v", config)
+	}
+	
+	if config.VerificationKeys["receipts-2025-01"] != "cHViMQ==" {
+		t.Errorf("Expected verification key 'cHViMQ==', got %s", config.VerificationKeys["receipts-2025-01"])
+	}
+}
+
+func TestGetEncryptionConfigDefaults(t *testing.T) {
+	config := GetEncryptionConfig()
+	
+	if config.Enabled {
+		t.Error("Expected metadata encryption to be disabled by default")
+	}
+	
+	if len(config.DecryptionKeys) != 0 {
+		t.Errorf("Expected no decryption keys, got %d", len(config.DecryptionKeys))
+	}
+}
+
+func TestGetEncryptionConfigWithEnvVars(t *testing.T) {
+	os.Setenv("METADATA_ENCRYPTION_ENABLED", "true")
+	os.Setenv("METADATA_ENCRYPTION_KEY_ID", "meta-2")
+	os.Setenv("METADATA_ENCRYPTION_KEY", "a2V5Mg==")
+	os.Setenv("METADATA_DECRYPTION_KEYS", "meta-1=a2V5MQ==")
+	
+	defer func() {
+		os.Unsetenv("METADATA_ENCRYPTION_ENABLED")
+		os.Unsetenv("METADATA_ENCRYPTION_KEY_ID")
+		os.Unsetenv("METADATA_ENCRYPTION_KEY")
+		os.Unsetenv("METADATA_DECRYPTION_KEYS")
+	}()
+	
+	config := GetEncryptionConfig()
+	
+	if !config.Enabled {
+		t.Error("Expected metadata encryption to be enabled")
+	}
+	
+	if config.KeyID != "meta-2" {
+		t.Errorf("Expected key ID 'meta-2', got %s", config.KeyID)
+	}
+	
+	if config.DecryptionKeys["meta-1"] != "a2V5MQ==" {
+		t.Errorf("Expected decryption key 'a2V5MQ==', got %s", config.DecryptionKeys["meta-1"])
+	}
+}
+
+func TestGetWebhookConfigWithEnvVars(t *testing.T) {
+	os.Setenv("WEBHOOK_TIMEOUT", "2s")
+	os.Setenv("WEBHOOK_MAX_RETRIES", "5")
+	
+	defer func() {
+		os.Unsetenv("WEBHOOK_TIMEOUT")
+		os.Unsetenv("WEBHOOK_MAX_RETRIES")
+	}()
+	
+	config := GetWebhookConfig()
+	
+	if config.Timeout != 2*time.Second {
+		t.Errorf("Expected timeout 2s, got %v", config.Timeout)
+	}
+	
+	if config.MaxRetries != 5 {
+		t.Errorf("Expected 5 retries, got %d", config.MaxRetries)
+	}
+	
+	if config.RetryBackoff != time.Second {
+		t.Errorf("Expected default retry backoff 1s, got %v", config.RetryBackoff)
+	}
+}
+
+func TestGetReasonCodeConfig(t *testing.T) {
+	if GetReasonCodeConfig().AllowFreeText {
+		t.Error("Expected free-text reasons to be rejected by default")
+	}
+	
+	os.Setenv("REASON_CODES_ALLOW_FREE_TEXT", "true")
+	defer os.Unsetenv("REASON_CODES_ALLOW_FREE_TEXT")
+	
+	if !GetReasonCodeConfig().AllowFreeText {
+		t.Error("Expected free-text reasons to be allowed when enabled")
+	}
+}
+
+func TestGetTokenIntegrityConfig(t *testing.T) {
+	if GetTokenIntegrityConfig().StrictChecksums {
+		t.Error("Expected checksum mismatches to only be logged by default")
+	}
+	
+	os.Setenv("TOKEN_CHECKSUM_STRICT", "true")
+	defer os.Unsetenv("TOKEN_CHECKSUM_STRICT")
+	
+	if !GetTokenIntegrityConfig().StrictChecksums {
+		t.Error("Expected strict checksums when enabled")
+	}
+}
+
+func TestGetBulkUpdateConfig(t *testing.T) {
+	cfg := GetBulkUpdateConfig()
+	if cfg.BatchSize != 500 || cfg.AuditWorkers != 4 {
+		t.Errorf("Expected batches of 500 with 4 audit workers by default, got %+v", cfg)
+	}
+	
+	os.Setenv("BULK_UPDATE_BATCH_SIZE", "2000")
+	os.Setenv("BULK_UPDATE_AUDIT_WORKERS", "8")
+	defer os.Unsetenv("BULK_UPDATE_BATCH_SIZE")
+	defer os.Unsetenv("BULK_UPDATE_AUDIT_WORKERS")
+	
+	cfg = GetBulkUpdateConfig()
+	if cfg.BatchSize != 2000 || cfg.AuditWorkers != 8 {
+		t.Errorf("Expected batches of 2000 with 8 audit workers, got %+v", cfg)
+	}
+}
+
+func TestGetTokenCacheConfig(t *testing.T) {
+	cfg := GetTokenCacheConfig()
+	if cfg.Size != 0 || cfg.TTL != 2*time.Second {
+		t.Errorf("Expected the token cache to be off by default, got %+v", cfg)
+	}
+	
+	os.Setenv("TOKEN_CACHE_SIZE", "10000")
+	os.Setenv("TOKEN_CACHE_TTL", "500ms")
+	defer os.Unsetenv("TOKEN_CACHE_SIZE")
+	defer os.Unsetenv("TOKEN_CACHE_TTL")
+	
+	cfg = GetTokenCacheConfig()
+	if cfg.Size != 10000 || cfg.TTL != 500*time.Millisecond {
+		t.Errorf("Expected 10000 tokens cached for 500ms, got %+v", cfg)
+	}
+}
+
+func TestGetCurrencyConfig(t *testing.T) {
+	supported := GetCurrencyConfig().Supported
+	if len(supported) != 3 || supported[0] != "USD-CBDC" {
+		t.Errorf("Expected default USD, EUR and GBP CBDCs, got %v", supported)
+	}
+	
+	os.Setenv("SUPPORTED_CURRENCIES", "USD-CBDC, JPY-CBDC,,")
+	defer os.Unsetenv("SUPPORTED_CURRENCIES")
+	
+	supported = GetCurrencyConfig().Supported
+	if len(supported) != 2 || supported[1] != "JPY-CBDC" {
+		t.Errorf("Expected [USD-CBDC JPY-CBDC], got %v", supported)
+	}
+	
+	if decimals := GetCurrencyConfig().Decimals; len(decimals) != 0 {
+		t.Errorf("Expected no decimal overrides by default, got %v", decimals)
+	}
+	
+	os.Setenv("CURRENCY_DECIMALS", "JPY-CBDC=0, BHD-CBDC=3,XYZ-CBDC=two")
+	defer os.Unsetenv("CURRENCY_DECIMALS")
+	
+	decimals := GetCurrencyConfig().Decimals
+	if len(decimals) != 2 || decimals["JPY-CBDC"] != 0 || decimals["BHD-CBDC"] != 3 {
+		t.Errorf("Expected JPY-CBDC=0 and BHD-CBDC=3, got %v", decimals)
+	}
+}
+
+func TestGetGRPCConfig(t *testing.T) {
+	if port := GetGRPCConfig(9003).Port; port != 9003 {
+		t.Errorf("Expected default port 9003, got %d", port)
+	}
+	
+	os.Setenv("GRPC_PORT", "9500")
+	defer os.Unsetenv("GRPC_PORT")
+	
+	if port := GetGRPCConfig(9003).Port; port != 9500 {
+		t.Errorf("Expected port 9500, got %d", port)
+	}
+}
+
+func TestGetIssuanceConfig(t *testing.T) {
+	if cfg := GetIssuanceConfig(); cfg.MaxDenomination != 1000000 || cfg.DenominationRules != "" {
+		t.Errorf("Expected default max denomination and no rules, got %+v", cfg)
+	}
+	
+	os.Setenv("MAX_DENOMINATION", "5000")
+	os.Setenv("DENOMINATION_RULES", `{"USD-CBDC":{"allowed":[1,5,10]}}`)
+	defer os.Unsetenv("MAX_DENOMINATION")
+	defer os.Unsetenv("DENOMINATION_RULES")
+	
+	cfg := GetIssuanceConfig()
+	if cfg.MaxDenomination != 5000 {
+		t.Errorf("Expected max denomination 5000, got %v", cfg.MaxDenomination)
+	}
+	if cfg.DenominationRules != `{"USD-CBDC":{"allowed":[1,5,10]}}` {
+		t.Errorf("Unexpected denomination rules %q", cfg.DenominationRules)
+	}
+}
+
+func TestGetTransactionServiceConfig(t *testing.T) {
+	os.Setenv("TRANSACTION_SERVICE_URL", "http://transaction-service:8001")
+	os.Setenv("TRANSACTION_SERVICE_TIMEOUT", "2s")
+	defer os.Unsetenv("TRANSACTION_SERVICE_URL")
+	defer os.Unsetenv("TRANSACTION_SERVICE_TIMEOUT")
+	
+	cfg := GetTransactionServiceConfig()
+	if cfg.URL != "http://transaction-service:8001" {
+		t.Errorf("Expected URL http://transaction-service:8001, got %s", cfg.URL)
+	}
+	if cfg.Timeout != 2*time.Second {
+		t.Errorf("Expected timeout 2s, got %v", cfg.Timeout)
+	}
+}
+
+func TestGetTokenServiceConfig(t *testing.T) {
+	cfg := GetTokenServiceConfig()
+	if cfg.URL != "http://localhost:8003" {
+		t.Errorf("Expected default URL http://localhost:8003, got %s", cfg.URL)
+	}
+	
+	os.Setenv("TOKEN_SERVICE_TIMEOUT", "3s")
+	defer os.Unsetenv("TOKEN_SERVICE_TIMEOUT")
+	
+	cfg = GetTokenServiceConfig()
+	if cfg.Timeout != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", cfg.Timeout)
+	}
+}
+
+func TestGetFraudConfig(t *testing.T) {
+	cfg := GetFraudConfig()
+	if cfg.AutoFreezeEnabled {
+		t.Error("Expected auto-freeze to be disabled by default")
+	}
+	if cfg.AutoFreezeThreshold != 0.9 {
+		t.Errorf("Expected default threshold 0.9, got %v", cfg.AutoFreezeThreshold)
+	}
+	if cfg.CriticalThreshold != 0.95 {
+		t.Errorf("Expected default critical threshold 0.95, got %v", cfg.CriticalThreshold)
+	}
+	if cfg.ScoreHalfLife != 7*24*time.Hour {
+		t.Errorf("Expected default score half-life 168h, got %v", cfg.ScoreHalfLife)
+	}
+	
+	os.Setenv("FRAUD_AUTO_FREEZE_ENABLED", "true")
+	os.Setenv("FRAUD_AUTO_FREEZE_THRESHOLD", "0.75")
+	os.Setenv("FRAUD_CRITICAL_THRESHOLD", "0.8")
+	os.Setenv("FRAUD_SCORE_HALF_LIFE", "24h")
+	defer os.Unsetenv("FRAUD_AUTO_FREEZE_ENABLED")
+	defer os.Unsetenv("FRAUD_AUTO_FREEZE_THRESHOLD")
+	defer os.Unsetenv("FRAUD_CRITICAL_THRESHOLD")
+	defer os.Unsetenv("FRAUD_SCORE_HALF_LIFE")
+	
+	cfg = GetFraudConfig()
+	if !cfg.AutoFreezeEnabled {
+		t.Error("Expected auto-freeze to be enabled")
+	}
+	if cfg.AutoFreezeThreshold != 0.75 {
+		t.Errorf("Expected threshold 0.75, got %v", cfg.AutoFreezeThreshold)
+	}
+	if cfg.CriticalThreshold != 0.8 {
+		t.Errorf("Expected critical threshold 0.8, got %v", cfg.CriticalThreshold)
+	}
+	if cfg.ScoreHalfLife != 24*time.Hour {
+		t.Errorf("Expected score half-life 24h, got %v", cfg.ScoreHalfLife)
+	}
+}
+
+func TestGetAuditVerificationConfig(t *testing.T) {
+	cfg := GetAuditVerificationConfig()
+	if cfg.MaxEntries != 10000 {
+		t.Errorf("Expected default max entries 10000, got %d", cfg.MaxEntries)
+	}
+	if cfg.FullVerifyInterval != 5*time.Minute {
+		t.Errorf("Expected default full verification interval 5m, got %v", cfg.FullVerifyInterval)
+	}
+	
+	os.Setenv("AUDIT_VERIFY_MAX_ENTRIES", "500")
+	os.Setenv("AUDIT_FULL_VERIFY_INTERVAL", "1h")
+	defer os.Unsetenv("AUDIT_VERIFY_MAX_ENTRIES")
+	defer os.Unsetenv("AUDIT_FULL_VERIFY_INTERVAL")
+	
+	cfg = GetAuditVerificationConfig()
+	if cfg.MaxEntries != 500 {
+		t.Errorf("Expected max entries 500, got %d", cfg.MaxEntries)
+	}
+	if cfg.FullVerifyInterval != time.Hour {
+		t.Errorf("Expected full verification interval 1h, got %v", cfg.FullVerifyInterval)
+	}
+}
+
+func TestGetCategoryConfig(t *testing.T) {
+	cfg := GetCategoryConfig()
+	if len(cfg.Allowed) != 0 {
+		t.Errorf("Expected no category allow-list by default, got %v", cfg.Allowed)
+	}
+	
+	os.Setenv("TRANSACTION_CATEGORIES", "groceries, rent,,travel")
+	defer os.Unsetenv("TRANSACTION_CATEGORIES")
+	
+	cfg = GetCategoryConfig()
+	if len(cfg.Allowed) != 3 || cfg.Allowed[0] != "groceries" || cfg.Allowed[1] != "rent" || cfg.Allowed[2] != "travel" {
+		t.Errorf("Expected [groceries rent travel], got %v", cfg.Allowed)
+	}
+}
+
+func TestGetDatabaseMetricsConfig(t *testing.T) {
+	cfg := GetDatabaseMetricsConfig()
+	if cfg.StatsInterval != 15*time.Second {
+		t.Errorf("Expected default stats interval 15s, got %v", cfg.StatsInterval)
+	}
+	if cfg.SlowQueryThreshold != 0 {
+		t.Errorf("Expected slow query log disabled by default, got %v", cfg.SlowQueryThreshold)
+	}
+	
+	os.Setenv("DB_STATS_INTERVAL", "5s")
+	os.Setenv("DB_SLOW_QUERY_THRESHOLD", "250ms")
+	defer os.Unsetenv("DB_STATS_INTERVAL")
+	defer os.Unsetenv("DB_SLOW_QUERY_THRESHOLD")
+	
+	cfg = GetDatabaseMetricsConfig()
+	if cfg.StatsInterval != 5*time.Second {
+		t.Errorf("Expected stats interval 5s, got %v", cfg.StatsInterval)
+	}
+	if cfg.SlowQueryThreshold != 250*time.Millisecond {
+		t.Errorf("Expected slow query threshold 250ms, got %v", cfg.SlowQueryThreshold)
+	}
+}
+
+func TestGetHTTPMetricsConfig(t *testing.T) {
+	cfg := GetHTTPMetricsConfig()
+	if !cfg.RouteLabels {
+		t.Error("Expected route labels to be enabled by default")
+	}
+	if len(cfg.ExcludedRoutes) != 3 || cfg.ExcludedRoutes[0] != "/metrics" {
+		t.Errorf("Expected probes and /metrics excluded by default, got %v", cfg.ExcludedRoutes)
+	}
+	
+	os.Setenv("HTTP_METRICS_ROUTE_LABELS", "false")
+	os.Setenv("HTTP_METRICS_EXCLUDED_ROUTES", "/metrics, /ws/tokens")
+	defer os.Unsetenv("HTTP_METRICS_ROUTE_LABELS")
+	defer os.Unsetenv("HTTP_METRICS_EXCLUDED_ROUTES")
+	
+	cfg = GetHTTPMetricsConfig()
+	if cfg.RouteLabels {
+		t.Error("Expected route labels to be disabled")
+	}
+	if len(cfg.ExcludedRoutes) != 2 || cfg.ExcludedRoutes[1] != "/ws/tokens" {
+		t.Errorf("Expected excluded routes [/metrics /ws/tokens], got %v", cfg.ExcludedRoutes)
+	}
+}
+
+func TestGetLoadSheddingConfig(t *testing.T) {
+	cfg := GetLoadSheddingConfig()
+	if cfg.CheckInterval != time.Second {
+		t.Errorf("Expected default check interval 1s, got %v", cfg.CheckInterval)
+	}
+	if cfg.RetryAfter != 5*time.Second {
+		t.Errorf("Expected default retry after 5s, got %v", cfg.RetryAfter)
+	}
+	
+	os.Setenv("LOAD_SHED_CHECK_INTERVAL", "500ms")
+	os.Setenv("LOAD_SHED_RETRY_AFTER", "30s")
+	defer os.Unsetenv("LOAD_SHED_CHECK_INTERVAL")
+	defer os.Unsetenv("LOAD_SHED_RETRY_AFTER")
+	
+	cfg = GetLoadSheddingConfig()
+	if cfg.CheckInterval != 500*time.Millisecond {
+		t.Errorf("Expected check interval 500ms, got %v", cfg.CheckInterval)
+	}
+	if cfg.RetryAfter != 30*time.Second {
+		t.Errorf("Expected retry after 30s, got %v", cfg.RetryAfter)
+	}
+}
+
+func TestGetAuditBatchConfig(t *testing.T) {
+	cfg := GetAuditBatchConfig()
+	if cfg.MaxTokens != 100 {
+		t.Errorf("Expected default max tokens 100, got %d", cfg.MaxTokens)
+	}
+	if cfg.RequestsPerMinute != 30 {
+		t.Errorf("Expected default rate limit 30, got %d", cfg.RequestsPerMinute)
+	}
+	
+	os.Setenv("AUDIT_BATCH_MAX_TOKENS", "25")
+	os.Setenv("AUDIT_BATCH_RATE_LIMIT", "5")
+	defer os.Unsetenv("AUDIT_BATCH_MAX_TOKENS")
+	defer os.Unsetenv("AUDIT_BATCH_RATE_LIMIT")
+	
+	cfg = GetAuditBatchConfig()
+	if cfg.MaxTokens != 25 {
+		t.Errorf("Expected max tokens 25, got %d", cfg.MaxTokens)
+	}
+	if cfg.RequestsPerMinute != 5 {
+		t.Errorf("Expected rate limit 5, got %d", cfg.RequestsPerMinute)
+	}
+}
+
+func TestGetSchemaCheckConfig(t *testing.T) {
+	cfg := GetSchemaCheckConfig()
+	if cfg.Interval != 30*time.Second {
+		t.Errorf("Expected default schema check interval 30s, got %v", cfg.Interval)
+	}
+	
+	os.Setenv("SCHEMA_CHECK_INTERVAL", "1m")
+	defer os.Unsetenv("SCHEMA_CHECK_INTERVAL")
+	
+	cfg = GetSchemaCheckConfig()
+	if cfg.Interval != time.Minute {
+		t.Errorf("Expected schema check interval 1m, got %v", cfg.Interval)
+	}
+}
+
+func TestGetRequestTimeoutConfig(t *testing.T) {
+	cfg := GetRequestTimeoutConfig()
+	if cfg.Default != 10*time.Second || cfg.Long != 60*time.Second {
+		t.Errorf("Expected default timeouts of 10s and 60s, got %+v", cfg)
+	}
+	
+	os.Setenv("REQUEST_TIMEOUT", "2s")
+	os.Setenv("REQUEST_TIMEOUT_LONG", "5m")
+	defer os.Unsetenv("REQUEST_TIMEOUT")
+	defer os.Unsetenv("REQUEST_TIMEOUT_LONG")
+	
+	cfg = GetRequestTimeoutConfig()
+	if cfg.Default != 2*time.Second || cfg.Long != 5*time.Minute {
+		t.Errorf("Expected timeouts of 2s and 5m, got %+v", cfg)
+	}
+}
+
+func TestGetFeeConfig(t *testing.T) {
+	cfg := GetFeeConfig()
+	if cfg.Enabled {
+		t.Error("Expected fees to be disabled by default")
+	}
+	if cfg.FlatFee != 0 || cfg.Percentage != 0 || cfg.MaxFee != 0 {
+		t.Errorf("Expected zero fees by default, got %+v", cfg)
+	}
+	
+	os.Setenv("FEES_ENABLED", "true")
+	os.Setenv("FEE_COLLECTION_WALLET", "7f1c2d8e-4b5a-4c3d-9e8f-0a1b2c3d4e5f")
+	os.Setenv("FEE_FLAT", "0.25")
+	os.Setenv("FEE_PERCENTAGE", "0.001")
+	os.Setenv("FEE_MAX", "10")
+	defer os.Unsetenv("FEES_ENABLED")
+	defer os.Unsetenv("FEE_COLLECTION_WALLET")
+	defer os.Unsetenv("FEE_FLAT")
+	defer os.Unsetenv("FEE_PERCENTAGE")
+	defer os.Unsetenv("FEE_MAX")
+	
+	cfg = GetFeeConfig()
+	if !cfg.Enabled {
+		t.Error("Expected fees to be enabled")
+	}
+	if cfg.CollectionWallet != "7f1c2d8e-4b5a-4c3d-9e8f-0a1b2c3d4e5f" {
+		t.Errorf("Expected collection wallet from env, got %s", cfg.CollectionWallet)
+	}
+	if cfg.FlatFee != 0.25 || cfg.Percentage != 0.001 || cfg.MaxFee != 10 {
+		t.Errorf("Expected fees from env, got %+v", cfg)
+	}
+}
+
+func TestGetSettlementConfig(t *testing.T) {
+	cfg := GetSettlementConfig()
+	if cfg.Mode != "instant" {
+		t.Errorf("Expected default settlement mode instant, got %s", cfg.Mode)
+	}
+	
+	os.Setenv("SETTLEMENT_MODE", "delayed")
+	defer os.Unsetenv("SETTLEMENT_MODE")
+	
+	cfg = GetSettlementConfig()
+	if cfg.Mode != "delayed" {
+		t.Errorf("Expected settlement mode delayed, got %s", cfg.Mode)
+	}
+}
+
+func TestGetProfilingConfig(t *testing.T) {
+	cfg := GetProfilingConfig()
+	if cfg.Enabled {
+		t.Error("Expected profiling to be disabled by default")
+	}
+	if cfg.Address != "127.0.0.1:6060" {
+		t.Errorf("Expected default address 127.0.0.1:6060, got %s", cfg.Address)
+	}
+	
+	os.Setenv("PROFILING_ENABLED", "true")
+	os.Setenv("PROFILING_ADDR", "10.0.0.5:6061")
+	defer os.Unsetenv("PROFILING_ENABLED")
+	defer os.Unsetenv("PROFILING_ADDR")
+	
+	cfg = GetProfilingConfig()
+	if !cfg.Enabled {
+		t.Error("Expected profiling to be enabled")
+	}
+	if cfg.Address != "10.0.0.5:6061" {
+		t.Errorf("Expected address 10.0.0.5:6061, got %s", cfg.Address)
+	}
+}
+
+func TestGetCORSConfig(t *testing.T) {
+	cfg := GetCORSConfig()
+	if len(cfg.AllowedOrigins) != 0 || cfg.AllowCredentials {
+		t.Errorf("Expected no allowed origins or credentials by default, got %+v", cfg)
+	}
+	
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://wallet.example.com, https://admin.example.com")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	defer os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	defer os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+	
+	cfg = GetCORSConfig()
+	if len(cfg.AllowedOrigins) != 2 || cfg.AllowedOrigins[1] != "https://admin.example.com" {
+		t.Errorf("Expected two allowed origins, got %v", cfg.AllowedOrigins)
+	}
+	if !cfg.AllowCredentials {
+		t.Error("Expected credentials to be allowed")
+	}
+}
+
+func TestGetReadOnlyConfig(t *testing.T) {
+	if cfg := GetReadOnlyConfig(); cfg.Enabled {
+		t.Error("Expected read-only mode to be off by default")
+	}
+	
+	os.Setenv("READ_ONLY_MODE", "true")
+	os.Setenv("READ_ONLY_REASON", "ledger migration")
+	defer os.Unsetenv("READ_ONLY_MODE")
+	defer os.Unsetenv("READ_ONLY_REASON")
+	
+	cfg := GetReadOnlyConfig()
+	if !cfg.Enabled || cfg.Reason != "ledger migration" {
+		t.Errorf("Expected read-only mode for ledger migration, got %+v", cfg)
+	}
+}
+
+func TestGetReportingConfig(t *testing.T) {
+	if cfg := GetReportingConfig(); cfg.Timezone != "UTC" {
+		t.Errorf("Expected reports in UTC by default, got %s", cfg.Timezone)
+	}
+	
+	os.Setenv("REPORTING_TIMEZONE", "Europe/Berlin")
+	defer os.Unsetenv("REPORTING_TIMEZONE")
+	
+	if cfg := GetReportingConfig(); cfg.Timezone != "Europe/Berlin" {
+		t.Errorf("Expected reports in Europe/Berlin, got %s", cfg.Timezone)
+	}
+}
+
+func TestGetReplayProtectionConfig(t *testing.T) {
+	if cfg := GetReplayProtectionConfig(); cfg.RequireNonce || cfg.MaxClockSkew != 5*time.Minute {
+		t.Errorf("Expected optional nonces with a 5m skew by default, got %+v", cfg)
+	}
+	
+	os.Setenv("TRANSFER_NONCE_REQUIRED", "true")
+	os.Setenv("TRANSFER_MAX_CLOCK_SKEW", "30s")
+	defer os.Unsetenv("TRANSFER_NONCE_REQUIRED")
+	defer os.Unsetenv("TRANSFER_MAX_CLOCK_SKEW")
+	
+	cfg := GetReplayProtectionConfig()
+	if !cfg.RequireNonce || cfg.MaxClockSkew != 30*time.Second {
+		t.Errorf("Expected required nonces with a 30s skew, got %+v", cfg)
+	}
+}
+
+func TestGetAuditRetentionConfig(t *testing.T) {
+	if cfg := GetAuditRetentionConfig(); cfg.Retention != 0 || cfg.Interval != time.Hour {
+		t.Errorf("Expected archival disabled with an hourly interval by default, got %+v", cfg)
+	}
+	
+	os.Setenv("AUDIT_RETENTION", "2160h")
+	os.Setenv("AUDIT_ARCHIVE_INTERVAL", "15m")
+	defer os.Unsetenv("AUDIT_RETENTION")
+	defer os.Unsetenv("AUDIT_ARCHIVE_INTERVAL")
+	
+	cfg := GetAuditRetentionConfig()
+	if cfg.Retention != 2160*time.Hour || cfg.Interval != 15*time.Minute {
+		t.Errorf("Expected 90 day retention archived every 15m, got %+v", cfg)
+	}
+}
+
+func TestGetTransferVelocityConfig(t *testing.T) {
+	if cfg := GetTransferVelocityConfig(); cfg.MaxTransfers != 0 || cfg.Window != time.Hour {
+		t.Errorf("Expected unlimited transfers over an hourly window by default, got %+v", cfg)
+	}
+	
+	os.Setenv("TRANSFER_VELOCITY_MAX", "5")
+	os.Setenv("TRANSFER_VELOCITY_WINDOW", "10m")
+	defer os.Unsetenv("TRANSFER_VELOCITY_MAX")
+	defer os.Unsetenv("TRANSFER_VELOCITY_WINDOW")
+	
+	cfg := GetTransferVelocityConfig()
+	if cfg.MaxTransfers != 5 || cfg.Window != 10*time.Minute {
+		t.Errorf("Expected 5 transfers per 10m, got %+v", cfg)
+	}
+}
+
+func TestGetSettlementFinalityConfig(t *testing.T) {
+	if cfg := GetSettlementFinalityConfig(); cfg.FinalityWindow != 0 {
+		t.Errorf("Expected transfers to be final at once by default, got %+v", cfg)
+	}
+	
+	os.Setenv("TOKEN_FINALITY_WINDOW", "30m")
+	defer os.Unsetenv("TOKEN_FINALITY_WINDOW")
+	
+	if cfg := GetSettlementFinalityConfig(); cfg.FinalityWindow != 30*time.Minute {
+		t.Errorf("Expected a 30m finality window, got %+v", cfg)
+	}
+}
+
 func TestGetEnvAsDuration(t *testing.T) {
 	os.Setenv("TEST_DURATION", "5m")
 	defer os.Unsetenv("TEST_DURATION")
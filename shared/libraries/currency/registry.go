@@ -0,0 +1,75 @@
+// Package currency holds the set of currency codes and CBDC types the services accept, so
+// supporting a new CBDC is a configuration change rather than edits across validators.
+package currency
+
+import (
+	"sync"
+
+	"echopay/shared/libraries/money"
+)
+
+// DefaultCurrencies are the CBDCs supported when no configuration is given
+var DefaultCurrencies = []string{"USD-CBDC", "EUR-CBDC", "GBP-CBDC"}
+
+// Registry is a concurrency-safe set of supported currency codes that keeps registration order
+type Registry struct {
+	mutex sync.RWMutex
+	codes []string
+	known map[string]bool
+}
+
+// NewRegistry creates a registry supporting the given currency codes
+func NewRegistry(codes ...string) *Registry {
+	r := &Registry{known: make(map[string]bool)}
+	for _, code := range codes {
+		r.Register(code)
+	}
+	return r
+}
+
+// NewDefaultRegistry creates a registry supporting DefaultCurrencies
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(DefaultCurrencies...)
+}
+
+// Register adds a currency code. Registering an empty or already known code is a no-op.
+func (r *Registry) Register(code string) {
+	if code == "" {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.known[code] {
+		return
+	}
+	r.known[code] = true
+	r.codes = append(r.codes, code)
+}
+
+// Supported reports whether a currency code is registered
+func (r *Registry) Supported(code string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.known[code]
+}
+
+// Codes returns the registered currency codes in registration order
+func (r *Registry) Codes() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return append([]string{}, r.codes...)
+}
+
+// SetDecimals sets the number of minor-unit decimal places for a currency code, such as 0 for
+// a currency without fractional units. Amounts are rounded and formatted through package
+// money, so the precision applies process-wide rather than only to this registry.
+func (r *Registry) SetDecimals(code string, decimals int) error {
+	return money.SetDecimals(code, decimals)
+}
+
+// Decimals returns the number of minor-unit decimal places for a currency code
+func (r *Registry) Decimals(code string) int {
+	return money.Decimals(code)
+}
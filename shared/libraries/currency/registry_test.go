@@ -0,0 +1,58 @@
+package currency
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	for _, code := range []string{"USD-CBDC", "EUR-CBDC", "GBP-CBDC"} {
+		if !registry.Supported(code) {
+			t.Errorf("Expected %s to be supported by default", code)
+		}
+	}
+	if registry.Supported("JPY-CBDC") {
+		t.Error("Expected JPY-CBDC to be unsupported until registered")
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry("USD-CBDC")
+	registry.Register("JPY-CBDC")
+	registry.Register("USD-CBDC")
+	registry.Register("")
+
+	if !registry.Supported("JPY-CBDC") {
+		t.Error("Expected registered currency to be supported")
+	}
+
+	expected := []string{"USD-CBDC", "JPY-CBDC"}
+	if codes := registry.Codes(); !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Expected codes %v, got %v", expected, codes)
+	}
+}
+
+func TestRegistryDecimals(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	if got := registry.Decimals("USD-CBDC"); got != 2 {
+		t.Errorf("Expected USD-CBDC to use 2 decimal places, got %d", got)
+	}
+	if got := registry.Decimals("JPY-CBDC"); got != 0 {
+		t.Errorf("Expected JPY-CBDC to use 0 decimal places, got %d", got)
+	}
+
+	if err := registry.SetDecimals("TND-CBDC", 3); err != nil {
+		t.Fatalf("Expected 3 decimal places to be accepted, got %v", err)
+	}
+	defer registry.SetDecimals("TND-CBDC", 2)
+	if got := registry.Decimals("TND-CBDC"); got != 3 {
+		t.Errorf("Expected TND-CBDC to use 3 decimal places, got %d", got)
+	}
+
+	if err := registry.SetDecimals("TND-CBDC", -1); err == nil {
+		t.Error("Expected negative decimal places to be rejected")
+	}
+}
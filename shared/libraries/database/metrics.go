@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"echopay/shared/libraries/logging"
+)
+
+// PoolMetrics exposes a connection pool's sql.DBStats as Prometheus gauges
+type PoolMetrics struct {
+	OpenConnections  prometheus.Gauge
+	InUseConnections prometheus.Gauge
+	IdleConnections  prometheus.Gauge
+	WaitCount        prometheus.Gauge // Total connections waited for since the pool was opened
+	WaitDuration     prometheus.Gauge // Total time spent waiting for connections, in seconds
+}
+
+// NewPoolMetrics registers the connection pool gauges for a service. Collectors register
+// globally, so it must be called once per service.
+func NewPoolMetrics(serviceName string) *PoolMetrics {
+	return &PoolMetrics{
+		OpenConnections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_database_open_connections",
+			Help: "Number of established database connections, in use and idle",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+
+		InUseConnections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_database_in_use_connections",
+			Help: "Number of database connections currently in use",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+
+		IdleConnections: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_database_idle_connections",
+			Help: "Number of idle database connections",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+
+		WaitCount: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_database_wait_count",
+			Help: "Total number of connections waited for because the pool was exhausted",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+
+		WaitDuration: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_database_wait_duration_seconds",
+			Help: "Total time spent waiting for a database connection",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+	}
+}
+
+// Record sets the gauges from a snapshot of the pool's statistics
+func (m *PoolMetrics) Record(stats sql.DBStats) {
+	m.OpenConnections.Set(float64(stats.OpenConnections))
+	m.InUseConnections.Set(float64(stats.InUse))
+	m.IdleConnections.Set(float64(stats.Idle))
+	m.WaitCount.Set(float64(stats.WaitCount))
+	m.WaitDuration.Set(stats.WaitDuration.Seconds())
+}
+
+// ReportStats records the pool's statistics into metrics immediately and then every
+// interval until ctx is cancelled. It blocks, so callers run it in its own goroutine.
+func (db *PostgresDB) ReportStats(ctx context.Context, metrics *PoolMetrics, interval time.Duration) {
+	metrics.Record(db.Stats())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.Record(db.Stats())
+		}
+	}
+}
+
+// PoolSaturated reports whether every connection the pool may open is in use, so new
+// statements have to wait for one to be released
+func (db *PostgresDB) PoolSaturated() bool {
+	stats := db.Stats()
+	return stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections
+}
+
+// LoadReason reports why the database cannot take more work, either because its pool is
+// saturated or because it cannot be reached, or returns an empty string if it can
+func (db *PostgresDB) LoadReason() string {
+	if db.PoolSaturated() {
+		return "connection pool saturated"
+	}
+	if err := db.HealthCheck(); err != nil {
+		return "database unreachable"
+	}
+	return ""
+}
+
+// SetSlowQueryLog logs every statement run through db that takes at least threshold. Only
+// the query template is logged, never its arguments, which may hold personal data. A zero
+// threshold disables the log. Call it before the database is shared between goroutines.
+//
+// Statements run on a *sql.Tx from Transaction go straight to database/sql and are not
+// timed.
+func (db *PostgresDB) SetSlowQueryLog(threshold time.Duration, logger *logging.Logger) {
+	if threshold <= 0 || logger == nil {
+		db.slowQueryThreshold = 0
+		db.slowQueryLogger = nil
+		return
+	}
+
+	db.slowQueryThreshold = threshold
+	db.slowQueryLogger = logger
+}
+
+// logIfSlow logs query when it has run for longer than the slow query threshold
+func (db *PostgresDB) logIfSlow(ctx context.Context, query string, start time.Time) {
+	if db.slowQueryLogger == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < db.slowQueryThreshold {
+		return
+	}
+
+	db.slowQueryLogger.WithContext(ctx).Warn("Slow database query",
+		"query", query,
+		"duration_ms", elapsed.Milliseconds(),
+		"threshold_ms", db.slowQueryThreshold.Milliseconds(),
+	)
+}
+
+// ExecContext runs sql.DB.ExecContext, logging the statement if it is slow
+func (db *PostgresDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer db.logIfSlow(ctx, query, time.Now())
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+// Exec runs sql.DB.Exec, logging the statement if it is slow
+func (db *PostgresDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// QueryContext runs sql.DB.QueryContext, logging the query if it is slow
+func (db *PostgresDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer db.logIfSlow(ctx, query, time.Now())
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// Query runs sql.DB.Query, logging the query if it is slow
+func (db *PostgresDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRowContext runs sql.DB.QueryRowContext, logging the query if it is slow
+func (db *PostgresDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer db.logIfSlow(ctx, query, time.Now())
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// QueryRow runs sql.DB.QueryRow, logging the query if it is slow
+func (db *PostgresDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"echopay/shared/libraries/logging"
+)
+
+// sleepDriver is a database/sql driver whose statements take as long as the duration named
+// by their query, e.g. "SELECT 1 -- sleep 20ms", so slow queries can be tested without
+// PostgreSQL
+type sleepDriver struct{}
+
+type sleepConn struct{}
+
+type sleepStmt struct{ query string }
+
+type emptyRows struct{}
+
+func init() {
+	sql.Register("database-test-sleep", sleepDriver{})
+}
+
+func (sleepDriver) Open(string) (driver.Conn, error) { return sleepConn{}, nil }
+
+func (sleepConn) Prepare(query string) (driver.Stmt, error) { return sleepStmt{query: query}, nil }
+func (sleepConn) Close() error                              { return nil }
+func (sleepConn) Begin() (driver.Tx, error)                 { return nil, errors.New("transactions not supported") }
+
+func (s sleepStmt) Close() error  { return nil }
+func (s sleepStmt) NumInput() int { return -1 }
+
+func (s sleepStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.sleep()
+	return driver.RowsAffected(0), nil
+}
+
+func (s sleepStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.sleep()
+	return emptyRows{}, nil
+}
+
+func (s sleepStmt) sleep() {
+	if i := strings.Index(s.query, "-- sleep "); i >= 0 {
+		if d, err := time.ParseDuration(s.query[i+len("-- sleep "):]); err == nil {
+			time.Sleep(d)
+		}
+	}
+}
+
+func (emptyRows) Columns() []string         { return []string{"value"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func openSleepDB(t *testing.T) *PostgresDB {
+	db, err := sql.Open("database-test-sleep", "")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &PostgresDB{DB: db}
+}
+
+func TestSlowQueryLogged(t *testing.T) {
+	db := openSleepDB(t)
+	var buf bytes.Buffer
+	db.SetSlowQueryLog(10*time.Millisecond, logging.NewLoggerWithWriter("database-test", &buf))
+
+	if _, err := db.Exec("UPDATE wallets SET owner = $1 -- sleep 1ms", "alice@example.com"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected fast statement not to be logged, got %s", buf.String())
+	}
+
+	slowQuery := "SELECT value FROM wallets WHERE owner = $1 -- sleep 20ms"
+	rows, err := db.QueryContext(context.Background(), slowQuery, "alice@example.com")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	rows.Close()
+
+	logged := buf.String()
+	if !strings.Contains(logged, "Slow database query") || !strings.Contains(logged, "SELECT value FROM wallets WHERE owner = $1") {
+		t.Errorf("Expected slow query to be logged, got %s", logged)
+	}
+	if strings.Contains(logged, "alice@example.com") {
+		t.Errorf("Expected query arguments not to be logged, got %s", logged)
+	}
+
+	// A zero threshold disables the log
+	buf.Reset()
+	db.SetSlowQueryLog(0, logging.NewLoggerWithWriter("database-test", &buf))
+	db.QueryRow(slowQuery, "alice@example.com").Scan(new(string))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log with slow query logging disabled, got %s", buf.String())
+	}
+}
+
+func TestPoolMetricsRegistered(t *testing.T) {
+	db := openSleepDB(t)
+	metrics := NewPoolMetrics("database-test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		db.ReportStats(ctx, metrics, time.Hour)
+		close(done)
+	}()
+
+	// Hold a connection so the pool reports it in use
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	metrics.Record(db.Stats())
+
+	cancel()
+	<-done
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "service" && label.GetValue() == "database-test" {
+					values[family.GetName()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"echopay_database_open_connections",
+		"echopay_database_in_use_connections",
+		"echopay_database_idle_connections",
+		"echopay_database_wait_count",
+		"echopay_database_wait_duration_seconds",
+	} {
+		if _, ok := values[name]; !ok {
+			t.Errorf("Expected gauge %s to be registered", name)
+		}
+	}
+
+	if values["echopay_database_in_use_connections"] != 1 {
+		t.Errorf("Expected 1 connection in use, got %v", values["echopay_database_in_use_connections"])
+	}
+}
+
+func TestPoolSaturated(t *testing.T) {
+	db := openSleepDB(t)
+	if db.PoolSaturated() {
+		t.Error("Expected an unlimited pool never to be saturated")
+	}
+
+	db.SetMaxOpenConns(1)
+	if db.PoolSaturated() {
+		t.Error("Expected an idle pool not to be saturated")
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	if !db.PoolSaturated() {
+		t.Error("Expected pool to be saturated with its only connection in use")
+	}
+	if reason := db.LoadReason(); reason != "connection pool saturated" {
+		t.Errorf("Expected saturated pool to be reported as load, got %q", reason)
+	}
+
+	conn.Close()
+	if db.PoolSaturated() {
+		t.Error("Expected pool not to be saturated once the connection is released")
+	}
+	if reason := db.LoadReason(); reason != "" {
+		t.Errorf("Expected no load reason, got %q", reason)
+	}
+}
@@ -7,25 +7,31 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+
+	"echopay/shared/libraries/logging"
 )
 
 // PostgresDB wraps sql.DB with additional functionality
 type PostgresDB struct {
 	*sql.DB
 	config DatabaseConfig
+
+	slowQueryThreshold time.Duration   // Statements at least this slow are logged; see SetSlowQueryLog
+	slowQueryLogger    *logging.Logger // Nil when slow query logging is disabled
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	Database        string
-	User            string
-	Password        string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host             string
+	Port             int
+	Database         string
+	User             string
+	Password         string
+	SSLMode          string
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	StatementTimeout time.Duration // Server-side limit on each statement; zero leaves PostgreSQL's default
 }
 
 // NewPostgresDB creates a new PostgreSQL database connection
@@ -35,6 +41,12 @@ func NewPostgresDB(config DatabaseConfig) (*PostgresDB, error) {
 		config.Host, config.Port, config.User, config.Password, config.Database, config.SSLMode,
 	)
 	
+	// lib/pq sends unrecognized settings as run-time parameters, so every connection in
+	// the pool starts with the statement timeout applied
+	if config.StatementTimeout > 0 {
+		connStr += fmt.Sprintf(" statement_timeout=%d", config.StatementTimeout.Milliseconds())
+	}
+	
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
@@ -69,13 +81,31 @@ func (db *PostgresDB) GetStats() sql.DBStats {
 	return db.Stats()
 }
 
-// Transaction executes a function within a database transaction
+// Transaction executes a function within a database transaction. Commit errors,
+// including serialization failures reported at commit, are returned to the caller.
 func (db *PostgresDB) Transaction(fn func(*sql.Tx) error) error {
-	tx, err := db.Begin()
+	return db.TransactionContext(context.Background(), fn)
+}
+
+// TransactionContext executes a function within a database transaction bound to ctx. When
+// ctx has a deadline further away than the statement timeout, the timeout is raised to that
+// deadline for this transaction only, so maintenance jobs such as archival can run long
+// statements by passing a longer-deadline context.
+func (db *PostgresDB) TransactionContext(ctx context.Context, fn func(*sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	
+	if deadline, ok := ctx.Deadline(); ok && db.config.StatementTimeout > 0 {
+		if remaining := time.Until(deadline); remaining > db.config.StatementTimeout {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", remaining.Milliseconds())); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to extend statement timeout: %w", err)
+			}
+		}
+	}
+	
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
@@ -138,14 +168,15 @@ func (db *PostgresDB) Migrate(migrations []string) error {
 // DefaultConfig returns a default database configuration
 func DefaultConfig() DatabaseConfig {
 	return DatabaseConfig{
-		Host:            "localhost",
-		Port:            5432,
-		Database:        "echopay",
-		User:            "echopay",
-		Password:        "echopay_dev",
-		SSLMode:         "disable",
-		MaxOpenConns:    25,
-		MaxIdleConns:    5,
-		ConnMaxLifetime: 5 * time.Minute,
+		Host:             "localhost",
+		Port:             5432,
+		Database:         "echopay",
+		User:             "echopay",
+		Password:         "echopay_dev",
+		SSLMode:          "disable",
+		MaxOpenConns:     25,
+		MaxIdleConns:     5,
+		ConnMaxLifetime:  5 * time.Minute,
+		StatementTimeout: 30 * time.Second,
 	}
 }
\ No newline at end of file
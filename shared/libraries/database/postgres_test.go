@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// setupTestDB connects to the test database with a short statement timeout
+func setupTestDB(t *testing.T) *PostgresDB {
+	config := DefaultConfig()
+	config.Database = "echopay_test"
+	config.StatementTimeout = 200 * time.Millisecond
+
+	db, err := NewPostgresDB(config)
+	if err != nil {
+		t.Skipf("Skipping database tests: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStatementTimeoutAbortsSlowQuery(t *testing.T) {
+	db := setupTestDB(t)
+
+	start := time.Now()
+	_, err := db.Exec("SELECT pg_sleep(5)")
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected slow statement to be aborted")
+	}
+	if !IsStatementTimeout(err) {
+		t.Errorf("Expected statement timeout error, got %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Expected statement to abort near the 200ms timeout, took %v", elapsed)
+	}
+
+	// Statements inside a transaction are bound by the same timeout
+	err = db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("SELECT pg_sleep(5)")
+		return err
+	})
+	if !IsStatementTimeout(err) {
+		t.Errorf("Expected statement timeout error in transaction, got %v", err)
+	}
+}
+
+func TestTransactionContextExtendsStatementTimeout(t *testing.T) {
+	db := setupTestDB(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := db.TransactionContext(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec("SELECT pg_sleep(0.5)")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected a longer-deadline context to extend the statement timeout, got %v", err)
+	}
+
+	// The extension is local to the transaction
+	if _, err := db.Exec("SELECT pg_sleep(0.5)"); !IsStatementTimeout(err) {
+		t.Errorf("Expected statement timeout error after the transaction, got %v", err)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	if err := checkSchemaVersion(12, 12, 12); err != nil {
+		t.Errorf("Expected a schema at the expected version to pass, got %v", err)
+	}
+
+	tests := map[string][3]int{
+		"behind":          {11, 11, 12},
+		"ahead":           {13, 13, 12},
+		"never migrated":  {0, 0, 12},
+		"missing version": {12, 11, 12},
+	}
+
+	for name, tc := range tests {
+		if err := checkSchemaVersion(tc[0], tc[1], tc[2]); err == nil {
+			t.Errorf("Expected %s schema to fail the check", name)
+		}
+	}
+}
+
+func TestHealthCheckDeep(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Creates schema_migrations without applying anything
+	if err := db.Migrate(nil); err != nil {
+		t.Fatalf("Failed to create migrations table: %v", err)
+	}
+
+	version, _, err := db.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	if err := db.HealthCheckDeep(version + 1); err == nil {
+		t.Error("Expected a schema behind the binary's version to fail the deep health check")
+	}
+}
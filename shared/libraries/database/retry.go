@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes for transaction failures that are safe to retry
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// sqlStateQueryCanceled is reported for statements cancelled by statement_timeout or by
+// lib/pq when the statement's context ends
+const sqlStateQueryCanceled = "57014"
+
+// sqlStateUniqueViolation is reported when an insert or update breaks a unique constraint
+const sqlStateUniqueViolation = "23505"
+
+// RetryPolicy controls how WithRetry retries transient transaction failures
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; values below 1 mean a single attempt
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each further retry
+	MaxDelay    time.Duration // Upper bound on the delay between attempts
+}
+
+// DefaultRetryPolicy returns the retry policy used for contended transactions
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    200 * time.Millisecond,
+	}
+}
+
+// IsRetryable reports whether err, or any error it wraps, is a PostgreSQL
+// serialization failure or deadlock
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == sqlStateSerializationFailure || pqErr.Code == sqlStateDeadlockDetected
+}
+
+// IsStatementTimeout reports whether err, or any error it wraps, is a statement that was
+// cancelled for running past the statement timeout or its context's deadline. Such failures
+// mean the database is too slow to serve the request, not that the request is invalid.
+func IsStatementTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == sqlStateQueryCanceled
+}
+
+// IsUniqueViolation reports whether err, or any error it wraps, is a PostgreSQL unique
+// constraint violation, such as inserting a row whose key already exists
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == sqlStateUniqueViolation
+}
+
+// WithRetry calls fn until it succeeds, returns a non-retryable error, or the policy's
+// attempts are used up. Retries wait for a jittered exponential backoff so that
+// contending transactions do not collide again in lockstep. fn must be safe to run
+// more than once, which holds for a function that runs a complete database transaction.
+func WithRetry(fn func() error, policy RetryPolicy) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt < attempts {
+			time.Sleep(policy.backoff(attempt))
+		}
+	}
+	return err
+}
+
+// backoff returns a random delay in [d/2, d] where d doubles with each retry up to MaxDelay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
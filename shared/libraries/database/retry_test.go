@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func testRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Millisecond,
+	}
+}
+
+func TestWithRetryRecoversFromTransientError(t *testing.T) {
+	calls := 0
+	err := WithRetry(func() error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("failed to update balance: %w", &pq.Error{Code: sqlStateSerializationFailure})
+		}
+		return nil
+	}, testRetryPolicy(3))
+
+	if err != nil {
+		t.Fatalf("Expected success after retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestWithRetryPropagatesNonRetryableError(t *testing.T) {
+	permanent := errors.New("insufficient funds")
+
+	calls := 0
+	err := WithRetry(func() error {
+		calls++
+		return permanent
+	}, testRetryPolicy(3))
+
+	if err != permanent {
+		t.Errorf("Expected the original error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected non-retryable error to stop after 1 call, got %d", calls)
+	}
+}
+
+func TestWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	err := WithRetry(func() error {
+		calls++
+		return &pq.Error{Code: sqlStateDeadlockDetected}
+	}, testRetryPolicy(3))
+
+	if !IsRetryable(err) {
+		t.Errorf("Expected the last deadlock error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{&pq.Error{Code: sqlStateSerializationFailure}, true},
+		{&pq.Error{Code: sqlStateDeadlockDetected}, true},
+		{fmt.Errorf("wrapped: %w", &pq.Error{Code: sqlStateDeadlockDetected}), true},
+		{&pq.Error{Code: "23505"}, false}, // unique_violation
+		{errors.New("connection refused"), false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("IsRetryable(%v) = %v, expected %v", tt.err, got, tt.retryable)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+
+	for attempt, max := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 6: 40 * time.Millisecond} {
+		delay := policy.backoff(attempt)
+		if delay < max/2 || delay > max {
+			t.Errorf("Attempt %d: expected delay within [%v, %v], got %v", attempt, max/2, max, delay)
+		}
+	}
+}
+
+func TestIsStatementTimeout(t *testing.T) {
+	if !IsStatementTimeout(fmt.Errorf("failed to get token: %w", &pq.Error{Code: sqlStateQueryCanceled})) {
+		t.Error("Expected a cancelled statement to be a timeout")
+	}
+	if !IsStatementTimeout(fmt.Errorf("failed to get token: %w", context.DeadlineExceeded)) {
+		t.Error("Expected an exceeded deadline to be a timeout")
+	}
+	if IsStatementTimeout(&pq.Error{Code: sqlStateSerializationFailure}) {
+		t.Error("Expected a serialization failure not to be a timeout")
+	}
+	if IsStatementTimeout(errors.New("insufficient funds")) {
+		t.Error("Expected a plain error not to be a timeout")
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	if !IsUniqueViolation(fmt.Errorf("failed to create token: %w", &pq.Error{Code: sqlStateUniqueViolation})) {
+		t.Error("Expected a wrapped unique violation to be detected")
+	}
+	if IsUniqueViolation(&pq.Error{Code: sqlStateSerializationFailure}) {
+		t.Error("Expected a serialization failure not to be a unique violation")
+	}
+	if IsUniqueViolation(errors.New("duplicate key")) {
+		t.Error("Expected a plain error not to be a unique violation")
+	}
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion returns the highest migration version recorded by Migrate, along with the
+// number of versions recorded. Both are zero when no migration has been applied.
+func (db *PostgresDB) SchemaVersion(ctx context.Context) (version int, applied int, err error) {
+	query := `
+		SELECT COALESCE(MAX(version::int), 0), COUNT(*)
+		FROM schema_migrations
+		WHERE version ~ '^[0-9]+$'
+	`
+
+	if err := db.QueryRowContext(ctx, query).Scan(&version, &applied); err != nil {
+		return 0, 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, applied, nil
+}
+
+// HealthCheckDeep pings the database and verifies that its schema is at expectedVersion,
+// the number of migrations the running binary applies. A schema that is behind, such as one
+// left half-migrated, or ahead, migrated by a newer binary, fails the check.
+func (db *PostgresDB) HealthCheckDeep(expectedVersion int) error {
+	if err := db.HealthCheck(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, applied, err := db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checkSchemaVersion(version, applied, expectedVersion)
+}
+
+// checkSchemaVersion compares the applied schema version with the expected one. Versions are
+// numbered from 1 without gaps, so fewer applied versions than the highest one means some
+// migrations were skipped.
+func checkSchemaVersion(version, applied, expectedVersion int) error {
+	if version != expectedVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version, expectedVersion)
+	}
+
+	if applied < version {
+		return fmt.Errorf("schema version %d is missing %d earlier migrations", version, version-applied)
+	}
+
+	return nil
+}
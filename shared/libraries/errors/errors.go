@@ -24,14 +24,29 @@ func (e *EchoPayError) Error() string {
 	return fmt.Sprintf("[%s] %s: %s", e.Service, e.Code, e.Message)
 }
 
+// Unwrap returns the underlying cause so errors.Is and errors.As can inspect it
+func (e *EchoPayError) Unwrap() error {
+	return e.Cause
+}
+
 // Error codes for different services and scenarios
 const (
+	// Request Errors
+	ErrValidation           = "VALIDATION_ERROR"
+	ErrReplayDetected       = "REPLAY_DETECTED"
+	
 	// Transaction Service Errors
 	ErrInsufficientFunds    = "INSUFFICIENT_FUNDS"
 	ErrInvalidTransaction   = "INVALID_TRANSACTION"
 	ErrTransactionFailed    = "TRANSACTION_FAILED"
 	ErrTransactionNotFound  = "TRANSACTION_NOT_FOUND"
 	ErrDuplicateTransaction = "DUPLICATE_TRANSACTION"
+	ErrTransferNotPermitted = "TRANSFER_NOT_PERMITTED"
+	ErrCurrencyNotPermitted = "CURRENCY_NOT_PERMITTED"
+	
+	// Wallet Errors
+	ErrWalletNotFound       = "WALLET_NOT_FOUND"
+	ErrWalletFrozen         = "WALLET_FROZEN"
 	
 	// Fraud Detection Errors
 	ErrFraudDetectionFailed = "FRAUD_DETECTION_FAILED"
@@ -44,6 +59,10 @@ const (
 	ErrTokenFrozen          = "TOKEN_FROZEN"
 	ErrInvalidTokenState    = "INVALID_TOKEN_STATE"
 	ErrTokenTransferFailed  = "TOKEN_TRANSFER_FAILED"
+	ErrQuotaExceeded        = "QUOTA_EXCEEDED"
+	ErrVelocityExceeded     = "VELOCITY_EXCEEDED"
+	ErrDuplicateToken       = "DUPLICATE_TOKEN"
+	ErrNotYetFinal          = "NOT_YET_FINAL"
 	
 	// Reversibility Errors
 	ErrCaseNotFound         = "CASE_NOT_FOUND"
@@ -56,13 +75,16 @@ const (
 	ErrAMLViolation         = "AML_VIOLATION"
 	ErrComplianceCheck      = "COMPLIANCE_CHECK_FAILED"
 	ErrRegulatoryReporting  = "REGULATORY_REPORTING_FAILED"
+	ErrSanctionsBlocked     = "SANCTIONS_BLOCKED"
 	
 	// System Errors
 	ErrDatabaseConnection   = "DATABASE_CONNECTION_ERROR"
 	ErrServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	ErrRequestTimeout       = "REQUEST_TIMEOUT"
 	ErrRateLimitExceeded    = "RATE_LIMIT_EXCEEDED"
 	ErrAuthenticationFailed = "AUTHENTICATION_FAILED"
 	ErrAuthorizationFailed  = "AUTHORIZATION_FAILED"
+	ErrConcurrentModification = "CONCURRENT_MODIFICATION"
 )
 
 // NewError creates a new EchoPayError with stack trace
@@ -107,9 +129,11 @@ func (e *EchoPayError) IsRetryable() bool {
 	retryableCodes := map[string]bool{
 		ErrServiceUnavailable:   true,
 		ErrDatabaseConnection:   true,
+		ErrRequestTimeout:       true,
 		ErrAnalysisTimeout:      true,
 		ErrModelUnavailable:     true,
 		ErrRegulatoryReporting:  true,
+		ErrConcurrentModification: true,
 	}
 	
 	return retryableCodes[e.Code]
@@ -118,12 +142,17 @@ func (e *EchoPayError) IsRetryable() bool {
 // IsUserError determines if an error is caused by user input
 func (e *EchoPayError) IsUserError() bool {
 	userErrorCodes := map[string]bool{
+		ErrValidation:           true,
 		ErrInsufficientFunds:    true,
 		ErrInvalidTransaction:   true,
 		ErrDuplicateTransaction: true,
+		ErrWalletFrozen:         true,
 		ErrTokenFrozen:          true,
 		ErrInvalidTokenState:    true,
 		ErrInvalidCaseState:     true,
+		ErrReplayDetected:       true,
+		ErrTransferNotPermitted: true,
+		ErrCurrencyNotPermitted: true,
 		ErrKYCFailed:           true,
 		ErrAuthenticationFailed: true,
 		ErrAuthorizationFailed:  true,
@@ -135,17 +164,32 @@ func (e *EchoPayError) IsUserError() bool {
 // GetHTTPStatus returns appropriate HTTP status code for the error
 func (e *EchoPayError) GetHTTPStatus() int {
 	statusMap := map[string]int{
+		ErrValidation:           400, // Bad Request
 		ErrInsufficientFunds:    402, // Payment Required
 		ErrInvalidTransaction:   400, // Bad Request
 		ErrTransactionNotFound:  404, // Not Found
+		ErrWalletNotFound:       404, // Not Found
 		ErrDuplicateTransaction: 409, // Conflict
+		ErrDuplicateToken:       409, // Conflict
+		ErrConcurrentModification: 409, // Conflict
+		ErrReplayDetected:       409, // Conflict
 		ErrHighRiskTransaction:  403, // Forbidden
+		ErrTransferNotPermitted: 403, // Forbidden
+		ErrCurrencyNotPermitted: 403, // Forbidden
 		ErrTokenFrozen:          423, // Locked
+		ErrWalletFrozen:         423, // Locked
+		ErrInvalidTokenState:    409, // Conflict
+		ErrNotYetFinal:          409, // Conflict
+		ErrTokenTransferFailed:  502, // Bad Gateway
+		ErrQuotaExceeded:        422, // Unprocessable Entity
 		ErrRateLimitExceeded:    429, // Too Many Requests
+		ErrVelocityExceeded:     429, // Too Many Requests
 		ErrAuthenticationFailed: 401, // Unauthorized
 		ErrAuthorizationFailed:  403, // Forbidden
+		ErrSanctionsBlocked:     451, // Unavailable For Legal Reasons
 		ErrServiceUnavailable:   503, // Service Unavailable
 		ErrDatabaseConnection:   503, // Service Unavailable
+		ErrRequestTimeout:       504, // Gateway Timeout
 	}
 	
 	if status, exists := statusMap[e.Code]; exists {
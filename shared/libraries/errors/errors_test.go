@@ -36,6 +36,10 @@ func TestWrapError(t *testing.T) {
 	if wrappedErr.Cause != originalErr {
 		t.Error("Expected cause to be set to original error")
 	}
+	
+	if !errors.Is(wrappedErr, originalErr) {
+		t.Error("Expected wrapped error to unwrap to original error")
+	}
 }
 
 func TestWithContext(t *testing.T) {
@@ -61,6 +65,11 @@ func TestIsRetryable(t *testing.T) {
 		t.Error("Expected service unavailable error to be retryable")
 	}
 	
+	conflictErr := NewError(ErrConcurrentModification, "Stale write", "test-service")
+	if !conflictErr.IsRetryable() {
+		t.Error("Expected concurrent modification error to be retryable")
+	}
+	
 	nonRetryableErr := NewError(ErrInvalidTransaction, "Bad request", "test-service")
 	if nonRetryableErr.IsRetryable() {
 		t.Error("Expected invalid transaction error to not be retryable")
@@ -87,8 +96,23 @@ func TestGetHTTPStatus(t *testing.T) {
 		{ErrInsufficientFunds, 402},
 		{ErrInvalidTransaction, 400},
 		{ErrTransactionNotFound, 404},
+		{ErrWalletNotFound, 404},
+		{ErrWalletFrozen, 423},
 		{ErrAuthenticationFailed, 401},
 		{ErrServiceUnavailable, 503},
+		{ErrRequestTimeout, 504},
+		{ErrSanctionsBlocked, 451},
+		{ErrQuotaExceeded, 422},
+		{ErrVelocityExceeded, 429},
+		{ErrDuplicateToken, 409},
+		{ErrNotYetFinal, 409},
+		{ErrConcurrentModification, 409},
+		{ErrReplayDetected, 409},
+		{ErrTransferNotPermitted, 403},
+		{ErrCurrencyNotPermitted, 403},
+		{ErrValidation, 400},
+		{ErrInvalidTokenState, 409},
+		{ErrTokenTransferFailed, 502},
 		{"UNKNOWN_ERROR", 500},
 	}
 	
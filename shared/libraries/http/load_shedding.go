@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutePriority declares how important a route is when the service is overloaded
+type RoutePriority int
+
+const (
+	// PriorityLow routes, such as stats and history, are shed while the service is overloaded
+	PriorityLow RoutePriority = iota
+	// PriorityCritical routes, such as payments and reversals, are always served
+	PriorityCritical
+)
+
+// LoadState records whether a service is overloaded and why, so low-priority work can be
+// shed instead of queued behind the work that matters. It is shared by every route.
+type LoadState struct {
+	mu         sync.RWMutex
+	reasons    map[string]string // Source name to overload reason
+	retryAfter time.Duration     // Sent to shed clients as Retry-After
+}
+
+// NewLoadState creates a load state that starts out not overloaded. Shed requests are told
+// to retry after retryAfter.
+func NewLoadState(retryAfter time.Duration) *LoadState {
+	return &LoadState{
+		reasons:    make(map[string]string),
+		retryAfter: retryAfter,
+	}
+}
+
+// MarkOverloaded reports that a source, such as the database, is overloaded. An empty
+// reason clears it.
+func (s *LoadState) MarkOverloaded(name, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if reason == "" {
+		delete(s.reasons, name)
+		return
+	}
+	s.reasons[name] = reason
+}
+
+// Overloaded reports whether any source is overloaded, along with a sorted list of reasons
+func (s *LoadState) Overloaded() (bool, []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reasons := []string{}
+	for name, reason := range s.reasons {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+	}
+	sort.Strings(reasons)
+
+	return len(reasons) > 0, reasons
+}
+
+// Watch runs check immediately and then every interval until ctx is cancelled, marking the
+// named source overloaded with the reason check returns, or clearing it when check returns
+// an empty reason. It blocks, so callers run it in its own goroutine.
+func (s *LoadState) Watch(ctx context.Context, name string, interval time.Duration, check func() string) {
+	s.MarkOverloaded(name, check())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.MarkOverloaded(name, check())
+		}
+	}
+}
+
+// Priority declares a route's priority when it is registered. While the service is
+// overloaded, PriorityLow routes are rejected with 503 and a Retry-After header; routes
+// registered without a priority are treated as critical.
+func (s *LoadState) Priority(priority RoutePriority) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if priority >= PriorityCritical {
+			c.Next()
+			return
+		}
+
+		if overloaded, reasons := s.Overloaded(); overloaded {
+			retryAfter := int(math.Ceil(s.retryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":      "Service overloaded, try again later",
+				"reasons":    reasons,
+				"request_id": c.GetString("request_id"),
+				"timestamp":  time.Now().UTC(),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoadStateShedsLowPriorityRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	state := NewLoadState(5 * time.Second)
+	
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/health", ok)
+	r.GET("/stats", state.Priority(PriorityLow), ok)
+	r.POST("/reversal", state.Priority(PriorityCritical), ok)
+	
+	perform := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	
+	if w := perform(http.MethodGet, "/stats"); w.Code != http.StatusOK {
+		t.Errorf("Expected low priority route to be served without load, got %d", w.Code)
+	}
+	
+	state.MarkOverloaded("database", "connection pool saturated")
+	
+	w := perform(http.MethodGet, "/stats")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected low priority route to be shed under load, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "5" {
+		t.Errorf("Expected Retry-After 5, got %q", w.Header().Get("Retry-After"))
+	}
+	
+	if w := perform(http.MethodPost, "/reversal"); w.Code != http.StatusOK {
+		t.Errorf("Expected critical route to be served under load, got %d", w.Code)
+	}
+	if w := perform(http.MethodGet, "/health"); w.Code != http.StatusOK {
+		t.Errorf("Expected route without a priority to be served under load, got %d", w.Code)
+	}
+	
+	state.MarkOverloaded("database", "")
+	
+	if w := perform(http.MethodGet, "/stats"); w.Code != http.StatusOK {
+		t.Errorf("Expected low priority route to be served once load clears, got %d", w.Code)
+	}
+}
+
+func TestLoadStateWatch(t *testing.T) {
+	state := NewLoadState(time.Second)
+	checks := make(chan string, 1)
+	checks <- "connection pool saturated"
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		state.Watch(ctx, "database", time.Millisecond, func() string {
+			select {
+			case reason := <-checks:
+				return reason
+			default:
+				return ""
+			}
+		})
+		close(done)
+	}()
+	
+	deadline := time.Now().Add(time.Second)
+	for {
+		overloaded, _ := state.Overloaded()
+		if !overloaded && len(checks) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected overload to clear once the check passes")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	
+	cancel()
+	<-done
+}
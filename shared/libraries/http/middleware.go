@@ -1,14 +1,20 @@
 package http
 
 import (
-	"context"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"echopay/shared/libraries/config"
+	"echopay/shared/libraries/logging"
 )
 
 // RequestIDMiddleware adds a unique request ID to each request
@@ -22,21 +28,75 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		c.Header("X-Request-ID", requestID)
 		c.Set("request_id", requestID)
 		
-		// Add to context for logging
-		ctx := context.WithValue(c.Request.Context(), "request_id", requestID)
+		// Add to context so request-scoped loggers attach it to every line
+		ctx := logging.ContextWithRequestID(c.Request.Context(), requestID)
 		c.Request = c.Request.WithContext(ctx)
 		
 		c.Next()
 	}
 }
 
-// CORSMiddleware handles Cross-Origin Resource Sharing
-func CORSMiddleware() gin.HandlerFunc {
+// DefaultCORSMethods and DefaultCORSHeaders are allowed when CORSOptions leaves them empty
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-Request-ID"}
+)
+
+// CORSOptions controls which browser origins may call a service. An origin of "*" allows
+// any origin, which should only be used for public, unauthenticated APIs.
+type CORSOptions struct {
+	AllowedOrigins   []string // Exact origins, e.g. https://wallet.example.com
+	AllowedMethods   []string // Defaults to DefaultCORSMethods
+	AllowedHeaders   []string // Defaults to DefaultCORSHeaders
+	AllowCredentials bool     // Let browsers send cookies and authorization headers
+}
+
+// CORSOptionsFromConfig builds CORS options from the service configuration
+func CORSOptionsFromConfig(cfg config.CORSConfig) CORSOptions {
+	return CORSOptions{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+	}
+}
+
+// CORSMiddleware handles Cross-Origin Resource Sharing. A request's origin is echoed back only
+// when it is on the allow-list; requests from other origins get no CORS headers, so browsers
+// refuse to expose the response. Preflight requests are answered without reaching handlers.
+func CORSMiddleware(opts CORSOptions) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(opts.AllowedOrigins))
+	for _, origin := range opts.AllowedOrigins {
+		allowed[origin] = true
+	}
+
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+		origin := c.GetHeader("Origin")
+		if origin != "" {
+			// Responses differ by origin, so caches must not share them across origins
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		if origin != "" && (allowed[origin] || allowed["*"]) {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+			if opts.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -47,8 +107,42 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-// MetricsMiddleware records HTTP metrics
-func MetricsMiddleware(serviceName string) gin.HandlerFunc {
+// PermissiveCORSMiddleware allows every origin, as CORSMiddleware did before it took an
+// allow-list.
+//
+// Deprecated: use CORSMiddleware with the origins that should be allowed.
+func PermissiveCORSMiddleware() gin.HandlerFunc {
+	logging.NewLogger("http").Warn("PermissiveCORSMiddleware allows every origin; configure a CORS allow-list instead")
+	return CORSMiddleware(CORSOptions{AllowedOrigins: []string{"*"}})
+}
+
+// unmatchedRoute labels requests that matched no route, such as 404s, so arbitrary request
+// paths never become label values
+const unmatchedRoute = "unmatched"
+
+// allRoutes labels every request when route labels are disabled
+const allRoutes = "all"
+
+// MetricsOptions controls how MetricsMiddleware labels requests. Endpoints are labeled by route
+// template, e.g. /tokens/:id, never by the concrete path, so label cardinality is bounded by
+// the number of registered routes.
+type MetricsOptions struct {
+	RouteLabels    bool                  // Label by route template; false records every request under one endpoint
+	ExcludedRoutes []string              // Route templates not recorded, such as probes and the metrics endpoint
+	Registerer     prometheus.Registerer // Defaults to the default Prometheus registry
+}
+
+// MetricsOptionsFromConfig builds metrics options from the service configuration
+func MetricsOptionsFromConfig(cfg config.HTTPMetricsConfig) MetricsOptions {
+	return MetricsOptions{
+		RouteLabels:    cfg.RouteLabels,
+		ExcludedRoutes: cfg.ExcludedRoutes,
+	}
+}
+
+// MetricsMiddleware records the duration and count of HTTP requests, and a count of
+// responses by status class (2xx, 4xx, ...), for each route
+func MetricsMiddleware(serviceName string, opts MetricsOptions) gin.HandlerFunc {
 	httpDuration := prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name: "http_request_duration_seconds",
@@ -67,27 +161,75 @@ func MetricsMiddleware(serviceName string) gin.HandlerFunc {
 		[]string{"method", "endpoint", "status_code"},
 	)
 	
-	prometheus.MustRegister(httpDuration, httpRequests)
+	httpResponses := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_responses_total",
+			Help: "Total number of HTTP responses by status class",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		},
+		[]string{"method", "endpoint", "status_class"},
+	)
+	
+	registerer := opts.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	registerer.MustRegister(httpDuration, httpRequests, httpResponses)
+	
+	excluded := make(map[string]bool, len(opts.ExcludedRoutes))
+	for _, route := range opts.ExcludedRoutes {
+		excluded[route] = true
+	}
 	
 	return func(c *gin.Context) {
 		start := time.Now()
 		
 		c.Next()
 		
+		route := c.FullPath()
+		if excluded[route] {
+			return
+		}
+		
+		endpoint := allRoutes
+		if opts.RouteLabels {
+			endpoint = route
+			if endpoint == "" {
+				endpoint = unmatchedRoute
+			}
+		}
+		
 		duration := time.Since(start)
 		statusCode := c.Writer.Status()
 		
+		method := methodLabel(c.Request.Method)
 		labels := prometheus.Labels{
-			"method":      c.Request.Method,
-			"endpoint":    c.FullPath(),
+			"method":      method,
+			"endpoint":    endpoint,
 			"status_code": http.StatusText(statusCode),
 		}
 		
 		httpDuration.With(labels).Observe(duration.Seconds())
 		httpRequests.With(labels).Inc()
+		httpResponses.With(prometheus.Labels{
+			"method":       method,
+			"endpoint":     endpoint,
+			"status_class": fmt.Sprintf("%dxx", statusCode/100),
+		}).Inc()
 	}
 }
 
+// methodLabel returns the metrics label for a request method. Clients can send any method
+// string, so nonstandard methods share one label.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
 // HealthCheckHandler provides a standard health check endpoint
 func HealthCheckHandler(serviceName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -155,4 +297,106 @@ func RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
 		
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// Request body limits for MaxBodyBytes
+const (
+	DefaultMaxBodyBytes int64 = 1 << 20   // 1 MiB for regular JSON requests
+	BulkMaxBodyBytes    int64 = 256 << 10 // 256 KiB for bulk endpoints, enough for 1000 UUIDs
+)
+
+// MaxBodyBytes rejects requests whose body is larger than limit with 413. The body is read up
+// to the limit before the handler runs, so bodies without a Content-Length are bounded too.
+func MaxBodyBytes(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		
+		if c.Request.ContentLength <= limit {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+			c.Request.Body.Close()
+			if err == nil && int64(len(body)) <= limit {
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+				c.Next()
+				return
+			}
+		}
+		
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":      "Request body too large",
+			"limit":      limit,
+			"request_id": c.GetString("request_id"),
+			"timestamp":  time.Now().UTC(),
+		})
+		c.Abort()
+	}
+}
+
+// UserHeader carries the authenticated caller's ID, set by the API gateway
+const UserHeader = "X-User-ID"
+
+// RolesHeader carries the authenticated caller's roles as a JSON array, set by the API gateway
+const RolesHeader = "X-User-Roles"
+
+// WalletHeader carries the wallet the authenticated caller acts for, set by the API gateway
+const WalletHeader = "X-Wallet-ID"
+
+// IssuerHeader carries the issuer the authenticated caller acts for, set by the API gateway
+const IssuerHeader = "X-User-Issuer"
+
+// HasRole reports whether the request's caller has the given role
+func HasRole(c *gin.Context, role string) bool {
+	var roles []string
+	if header := c.GetHeader(RolesHeader); header != "" {
+		if err := json.Unmarshal([]byte(header), &roles); err != nil {
+			return false
+		}
+	}
+	
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireAnyRole rejects requests whose caller has none of the given roles
+func RequireAnyRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range roles {
+			if HasRole(c, role) {
+				c.Next()
+				return
+			}
+		}
+		
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":      "Insufficient permissions",
+			"required":   roles,
+			"request_id": c.GetString("request_id"),
+			"timestamp":  time.Now().UTC(),
+		})
+		c.Abort()
+	}
+}
+
+// RequireRole rejects requests whose caller does not have the given role
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if HasRole(c, role) {
+			c.Next()
+			return
+		}
+		
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":      "Insufficient permissions",
+			"required":   role,
+			"request_id": c.GetString("request_id"),
+			"timestamp":  time.Now().UTC(),
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,332 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"echopay/shared/libraries/logging"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", RequireRole("admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		roles    string
+		expected int
+	}{
+		{`["user","admin"]`, http.StatusOK},
+		{`["user"]`, http.StatusForbidden},
+		{"", http.StatusForbidden},
+		{"admin", http.StatusForbidden}, // Not a JSON array
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if tt.roles != "" {
+			req.Header.Set(RolesHeader, tt.roles)
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("Roles %q: expected status %d, got %d", tt.roles, tt.expected, w.Code)
+		}
+	}
+}
+
+func TestRequireAnyRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/freeze", RequireAnyRole("compliance", "admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		roles    string
+		expected int
+	}{
+		{`["compliance"]`, http.StatusOK},
+		{`["user","admin"]`, http.StatusOK},
+		{`["user"]`, http.StatusForbidden},
+		{"", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/freeze", nil)
+		if tt.roles != "" {
+			req.Header.Set(RolesHeader, tt.roles)
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("Roles %q: expected status %d, got %d", tt.roles, tt.expected, w.Code)
+		}
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/bulk", MaxBodyBytes(16), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	tests := []struct {
+		body     string
+		chunked  bool
+		expected int
+	}{
+		{`{"a":"b"}`, false, http.StatusOK},
+		{strings.Repeat("x", 16), false, http.StatusOK},
+		{strings.Repeat("x", 17), false, http.StatusRequestEntityTooLarge},
+		{strings.Repeat("x", 1024), true, http.StatusRequestEntityTooLarge}, // No Content-Length
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(tt.body))
+		if tt.chunked {
+			req.ContentLength = -1
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("Body of %d bytes: expected status %d, got %d", len(tt.body), tt.expected, w.Code)
+		}
+		if tt.expected == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("Expected handler to read the full body, got %q", w.Body.String())
+		}
+	}
+}
+
+func TestRequestIDMiddleware_LogsCorrelationID(t *testing.T) {
+	var output bytes.Buffer
+	logging.SetDefault(logging.NewLoggerWithWriter("test-service", &output))
+	defer logging.SetDefault(logging.NewLogger("echopay"))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/transactions/:id", func(c *gin.Context) {
+		ctx := logging.ContextWithFields(c.Request.Context(), "transaction_id", c.Param("id"))
+		logging.WithContext(ctx).Info("Transaction retrieved")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/transactions/tx-1", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	r.ServeHTTP(w, req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", output.String(), err)
+	}
+	if entry["request_id"] != "req-123" {
+		t.Errorf("Expected request_id req-123, got %v", entry["request_id"])
+	}
+	if entry["transaction_id"] != "tx-1" {
+		t.Errorf("Expected transaction_id tx-1, got %v", entry["transaction_id"])
+	}
+	if entry["service"] != "test-service" {
+		t.Errorf("Expected service test-service, got %v", entry["service"])
+	}
+
+	// A generated request ID is returned to the caller and logged
+	output.Reset()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transactions/tx-2", nil))
+
+	requestID := w.Header().Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("Expected a generated X-Request-ID header")
+	}
+	if !strings.Contains(output.String(), `"request_id":"`+requestID+`"`) {
+		t.Errorf("Expected log output to include request ID %s, got %q", requestID, output.String())
+	}
+}
+
+func newCORSRouter(opts CORSOptions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORSMiddleware(opts))
+	r.GET("/tokens", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestCORSMiddleware_AllowedOrigin(t *testing.T) {
+	r := newCORSRouter(CORSOptions{
+		AllowedOrigins:   []string{"https://wallet.example.com"},
+		AllowCredentials: true,
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+	req.Header.Set("Origin", "https://wallet.example.com")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://wallet.example.com" {
+		t.Errorf("Expected the origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_DisallowedOrigin(t *testing.T) {
+	r := newCORSRouter(CORSOptions{AllowedOrigins: []string{"https://wallet.example.com"}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the request itself to be served, got %d", w.Code)
+	}
+	for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers", "Access-Control-Allow-Credentials"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("Expected no %s header, got %q", header, got)
+		}
+	}
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	r := newCORSRouter(CORSOptions{
+		AllowedOrigins: []string{"https://wallet.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/tokens", nil)
+	req.Header.Set("Origin", "https://wallet.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://wallet.example.com" {
+		t.Errorf("Expected the origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Errorf("Expected the configured methods, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+		t.Errorf("Expected the default headers, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected credentials to be disallowed by default, got %q", got)
+	}
+}
+
+// gatherCounter returns the values of a counter in a registry keyed by the given labels,
+// joined with spaces
+func gatherCounter(t *testing.T, registry *prometheus.Registry, name string, labels ...string) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			byName := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				byName[pair.GetName()] = pair.GetValue()
+			}
+			key := make([]string, len(labels))
+			for i, label := range labels {
+				key[i] = byName[label]
+			}
+			values[strings.Join(key, " ")] += metric.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func TestMetricsMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(MetricsMiddleware("test-service", MetricsOptions{
+		RouteLabels:    true,
+		ExcludedRoutes: []string{"/health"},
+		Registerer:     registry,
+	}))
+	r.GET("/tokens/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	r.GET("/wallets/:id/tokens", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/tokens/a", "/tokens/b", "/tokens/missing", "/wallets/w1/tokens", "/wallets/w2/tokens", "/health", "/no/such/route/123"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	requests := gatherCounter(t, registry, "http_requests_total", "endpoint")
+	expected := map[string]float64{"/tokens/:id": 3, "/wallets/:id/tokens": 2, "unmatched": 1}
+	if len(requests) != len(expected) {
+		t.Fatalf("Expected one series per route template, got %v", requests)
+	}
+	for endpoint, count := range expected {
+		if requests[endpoint] != count {
+			t.Errorf("Expected %v requests for %s, got %v", count, endpoint, requests[endpoint])
+		}
+	}
+
+	classes := gatherCounter(t, registry, "http_responses_total", "endpoint", "status_class")
+	if classes["/tokens/:id 2xx"] != 2 || classes["/tokens/:id 4xx"] != 1 || classes["/wallets/:id/tokens 2xx"] != 2 {
+		t.Errorf("Unexpected status class counts: %v", classes)
+	}
+	if classes["unmatched 4xx"] != 1 {
+		t.Errorf("Expected the unmatched request counted as 4xx, got %v", classes)
+	}
+}
+
+func TestMetricsMiddleware_WithoutRouteLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(MetricsMiddleware("test-service", MetricsOptions{Registerer: registry}))
+	r.GET("/tokens/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tokens/a", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/tokens/a", nil))
+
+	requests := gatherCounter(t, registry, "http_requests_total", "method", "endpoint")
+	if len(requests) != 2 || requests["GET all"] != 1 || requests["OTHER all"] != 1 {
+		t.Errorf("Expected requests under one endpoint with bounded methods, got %v", requests)
+	}
+}
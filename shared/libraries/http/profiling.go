@@ -0,0 +1,62 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"echopay/shared/libraries/config"
+)
+
+// ProfilingHandler serves the net/http/pprof endpoints under /debug/pprof/. It is only meant
+// for the internal listener created by NewProfilingServer and must never be mounted on a
+// service's public router.
+func ProfilingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// NewProfilingServer returns an HTTP server exposing ProfilingHandler on the configured
+// address, or nil when profiling is disabled. The address must bind a loopback or private
+// interface so the profiles are never reachable from outside the internal network.
+func NewProfilingServer(cfg config.ProfilingConfig) (*http.Server, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if err := validateInternalAddress(cfg.Address); err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:              cfg.Address,
+		Handler:           ProfilingHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}, nil
+}
+
+// validateInternalAddress rejects bind addresses that would listen on all interfaces or on a
+// public one
+func validateInternalAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid profiling address %q: %w", address, err)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+		return fmt.Errorf("profiling address %q must bind a loopback or private interface", address)
+	}
+	return nil
+}
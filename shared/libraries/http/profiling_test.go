@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"echopay/shared/libraries/config"
+)
+
+func TestNewProfilingServer(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		server, err := NewProfilingServer(config.ProfilingConfig{Enabled: false, Address: "127.0.0.1:6060"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if server != nil {
+			t.Fatal("Expected no profiling server when profiling is disabled")
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		server, err := NewProfilingServer(config.ProfilingConfig{Enabled: true, Address: "127.0.0.1:6060"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if server == nil {
+			t.Fatal("Expected a profiling server when profiling is enabled")
+		}
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap"} {
+			w := httptest.NewRecorder()
+			server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("GET %s: expected status %d, got %d", path, http.StatusOK, w.Code)
+			}
+		}
+	})
+
+	t.Run("public bind address is rejected", func(t *testing.T) {
+		for _, address := range []string{":6060", "0.0.0.0:6060", "[::]:6060", "8.8.8.8:6060", "profiler.example.com:6060", "127.0.0.1"} {
+			if _, err := NewProfilingServer(config.ProfilingConfig{Enabled: true, Address: address}); err == nil {
+				t.Errorf("Expected address %q to be rejected", address)
+			}
+		}
+
+		for _, address := range []string{"localhost:6060", "[::1]:6060", "10.1.2.3:6060", "192.168.0.10:6060"} {
+			if _, err := NewProfilingServer(config.ProfilingConfig{Enabled: true, Address: address}); err != nil {
+				t.Errorf("Expected address %q to be accepted, got %v", address, err)
+			}
+		}
+	})
+}
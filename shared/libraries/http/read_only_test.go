@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadOnlyModeRejectsWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mode := NewReadOnlyMode()
+
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api := r.Group("/api", mode.RejectWrites())
+	api.GET("/transactions/:id", ok)
+	api.POST("/transactions", ok)
+	api.PATCH("/transactions/:id/status", ok)
+	api.DELETE("/transactions/:id", ok)
+	r.PUT("/admin/read-only", SetReadOnlyHandler(mode))
+	r.GET("/admin/read-only", ReadOnlyStatusHandler(mode))
+
+	perform := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	if w := perform(http.MethodPost, "/api/transactions", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected write to be served before read-only mode, got %d", w.Code)
+	}
+
+	if w := perform(http.MethodPut, "/admin/read-only", `{"enabled": true, "reason": "schema migration"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected read-only mode to be enabled, got %d", w.Code)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		path := "/api/transactions"
+		if method == http.MethodPatch {
+			path = "/api/transactions/1/status"
+		} else if method == http.MethodDelete {
+			path = "/api/transactions/1"
+		}
+
+		w := perform(method, path, "")
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected %s to be rejected in read-only mode, got %d", method, w.Code)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode rejection: %v", err)
+		}
+		if body["reason"] != "schema migration" {
+			t.Errorf("Expected rejection to carry the reason, got %v", body["reason"])
+		}
+	}
+
+	if w := perform(http.MethodGet, "/api/transactions/1", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected read to be served in read-only mode, got %d", w.Code)
+	}
+
+	w := perform(http.MethodGet, "/admin/read-only", "")
+	var status ReadOnlyStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if !status.Enabled || status.Since == nil {
+		t.Errorf("Expected status to report read-only mode since it was enabled, got %+v", status)
+	}
+
+	if w := perform(http.MethodPut, "/admin/read-only", `{"enabled": false}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected read-only mode to be disabled, got %d", w.Code)
+	}
+
+	if w := perform(http.MethodPost, "/api/transactions", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected write to be served once read-only mode is lifted, got %d", w.Code)
+	}
+}
+
+func TestSetReadOnlyHandlerRequiresEnabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mode := NewReadOnlyMode()
+
+	r := gin.New()
+	r.PUT("/admin/read-only", SetReadOnlyHandler(mode))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/read-only", strings.NewReader(`{"reason": "oops"}`)))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected missing enabled flag to be rejected, got %d", w.Code)
+	}
+	if mode.Status().Enabled {
+		t.Error("Expected read-only mode to stay disabled")
+	}
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessTracker records whether each startup subsystem of a service is ready
+// to receive traffic. Subsystems are registered up front and start out not ready.
+// A ready service can also be degraded when a non-critical dependency is failing.
+type ReadinessTracker struct {
+	mu         sync.RWMutex
+	subsystems map[string]string // Subsystem name to not-ready reason; empty when ready
+	degraded   map[string]string // Dependency name to degradation reason
+}
+
+// NewReadinessTracker creates a tracker for the given subsystems, all initially not ready
+func NewReadinessTracker(subsystems ...string) *ReadinessTracker {
+	t := &ReadinessTracker{
+		subsystems: make(map[string]string, len(subsystems)),
+		degraded:   make(map[string]string),
+	}
+	for _, name := range subsystems {
+		t.subsystems[name] = "not started"
+	}
+	return t
+}
+
+// MarkReady reports a subsystem as ready
+func (t *ReadinessTracker) MarkReady(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subsystems[name] = ""
+}
+
+// MarkNotReady reports a subsystem as not ready with the given reason
+func (t *ReadinessTracker) MarkNotReady(name, reason string) {
+	if reason == "" {
+		reason = "not ready"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subsystems[name] = reason
+}
+
+// MarkDegraded reports that a dependency is failing without making the service unready.
+// An empty reason clears the degradation.
+func (t *ReadinessTracker) MarkDegraded(name, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if reason == "" {
+		delete(t.degraded, name)
+		return
+	}
+	t.degraded[name] = reason
+}
+
+// Degraded returns a sorted list of reasons for degraded dependencies
+func (t *ReadinessTracker) Degraded() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	reasons := []string{}
+	for name, reason := range t.degraded {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+	}
+	sort.Strings(reasons)
+
+	return reasons
+}
+
+// Ready reports whether every subsystem is ready, along with a sorted list of
+// reasons for those that are not
+func (t *ReadinessTracker) Ready() (bool, []string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	reasons := []string{}
+	for name, reason := range t.subsystems {
+		if reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+		}
+	}
+	sort.Strings(reasons)
+
+	return len(reasons) == 0, reasons
+}
+
+// Watch runs check immediately and then every interval until ctx is cancelled, marking the
+// named subsystem not ready with the error check returns, or ready when it returns nil. It
+// blocks, so callers run it in its own goroutine.
+func (t *ReadinessTracker) Watch(ctx context.Context, name string, interval time.Duration, check func() error) {
+	t.apply(name, check())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.apply(name, check())
+		}
+	}
+}
+
+// apply marks a subsystem ready or not ready from the result of a check
+func (t *ReadinessTracker) apply(name string, err error) {
+	if err != nil {
+		t.MarkNotReady(name, err.Error())
+		return
+	}
+	t.MarkReady(name)
+}
+
+// ReadinessHandler provides a readiness probe endpoint that returns 503 until
+// every subsystem tracked by the tracker is ready. A ready but degraded service
+// still returns 200 with status "degraded" so it keeps receiving traffic.
+func ReadinessHandler(serviceName string, tracker *ReadinessTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ready, reasons := tracker.Ready()
+		if !ready {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"service":   serviceName,
+				"status":    "not_ready",
+				"reasons":   reasons,
+				"timestamp": time.Now().UTC(),
+			})
+			return
+		}
+
+		if degraded := tracker.Degraded(); len(degraded) > 0 {
+			c.JSON(http.StatusOK, gin.H{
+				"service":   serviceName,
+				"status":    "degraded",
+				"degraded":  degraded,
+				"timestamp": time.Now().UTC(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":   serviceName,
+			"status":    "ready",
+			"timestamp": time.Now().UTC(),
+		})
+	}
+}
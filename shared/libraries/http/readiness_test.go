@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func performReadinessRequest(t *testing.T, tracker *ReadinessTracker) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", ReadinessHandler("test-service", tracker))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	r.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestReadinessTrackerNotReadyUntilAllSubsystemsReady(t *testing.T) {
+	tracker := NewReadinessTracker("migrations", "event_publisher")
+	
+	ready, reasons := tracker.Ready()
+	if ready {
+		t.Error("Expected tracker to start not ready")
+	}
+	if len(reasons) != 2 {
+		t.Errorf("Expected 2 reasons, got %d", len(reasons))
+	}
+	
+	tracker.MarkReady("migrations")
+	ready, reasons = tracker.Ready()
+	if ready {
+		t.Error("Expected tracker to be not ready while event publisher is pending")
+	}
+	if len(reasons) != 1 || reasons[0] != "event_publisher: not started" {
+		t.Errorf("Unexpected reasons: %v", reasons)
+	}
+	
+	tracker.MarkReady("event_publisher")
+	ready, reasons = tracker.Ready()
+	if !ready {
+		t.Errorf("Expected tracker to be ready, got reasons %v", reasons)
+	}
+	
+	tracker.MarkNotReady("event_publisher", "broker unreachable")
+	ready, reasons = tracker.Ready()
+	if ready {
+		t.Error("Expected tracker to be not ready after a subsystem regressed")
+	}
+	if len(reasons) != 1 || reasons[0] != "event_publisher: broker unreachable" {
+		t.Errorf("Unexpected reasons: %v", reasons)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tracker := NewReadinessTracker("migrations")
+	
+	status, body := performReadinessRequest(t, tracker)
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", status)
+	}
+	if body["status"] != "not_ready" {
+		t.Errorf("Expected status 'not_ready', got %v", body["status"])
+	}
+	reasons, ok := body["reasons"].([]interface{})
+	if !ok || len(reasons) != 1 || reasons[0] != "migrations: not started" {
+		t.Errorf("Unexpected reasons: %v", body["reasons"])
+	}
+	
+	tracker.MarkReady("migrations")
+	
+	status, body = performReadinessRequest(t, tracker)
+	if status != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", status)
+	}
+	if body["status"] != "ready" {
+		t.Errorf("Expected status 'ready', got %v", body["status"])
+	}
+}
+
+func TestReadinessHandlerDegraded(t *testing.T) {
+	tracker := NewReadinessTracker("migrations")
+	tracker.MarkReady("migrations")
+	tracker.MarkDegraded("event_streaming", "broker unreachable")
+	
+	status, body := performReadinessRequest(t, tracker)
+	if status != http.StatusOK {
+		t.Errorf("Expected degraded service to stay ready with status 200, got %d", status)
+	}
+	if body["status"] != "degraded" {
+		t.Errorf("Expected status 'degraded', got %v", body["status"])
+	}
+	degraded, ok := body["degraded"].([]interface{})
+	if !ok || len(degraded) != 1 || degraded[0] != "event_streaming: broker unreachable" {
+		t.Errorf("Unexpected degraded reasons: %v", body["degraded"])
+	}
+	
+	tracker.MarkDegraded("event_streaming", "")
+	
+	status, body = performReadinessRequest(t, tracker)
+	if status != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("Expected ready after degradation cleared, got %d %v", status, body["status"])
+	}
+}
+
+func TestReadinessTrackerWatch(t *testing.T) {
+	tracker := NewReadinessTracker("migrations", "schema")
+	tracker.MarkReady("migrations")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A schema migrated by a newer binary fails the deep check until it is fixed
+	var mismatch atomic.Bool
+	mismatch.Store(true)
+	go tracker.Watch(ctx, "schema", 10*time.Millisecond, func() error {
+		if mismatch.Load() {
+			return errors.New("schema version is 13, expected 12")
+		}
+		return nil
+	})
+
+	waitFor := func(reason string) {
+		deadline := time.Now().Add(time.Second)
+		for {
+			_, reasons := tracker.Ready()
+			if (reason == "" && len(reasons) == 0) || (len(reasons) == 1 && reasons[0] == reason) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected readiness reasons %q, got %v", reason, reasons)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitFor("schema: schema version is 13, expected 12")
+	status, body := performReadinessRequest(t, tracker)
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 on a schema mismatch, got %d", status)
+	}
+	if body["status"] != "not_ready" {
+		t.Errorf("Expected status 'not_ready', got %v", body["status"])
+	}
+
+	mismatch.Store(false)
+	waitFor("")
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"echopay/shared/libraries/errors"
+)
+
+// bufferedResponseWriter holds a handler's response until RequestTimeout decides whether to
+// send it or replace it with a timeout
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	header  http.Header
+	body    bytes.Buffer
+	status  int
+	written bool
+}
+
+func newBufferedResponseWriter(w gin.ResponseWriter) *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		ResponseWriter: w,
+		header:         make(http.Header),
+		status:         http.StatusOK,
+	}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedResponseWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedResponseWriter) Written() bool {
+	return w.written
+}
+
+// Flush does nothing; the response is sent once the handler has finished
+func (w *bufferedResponseWriter) Flush() {}
+
+// send writes the held response to the underlying writer
+func (w *bufferedResponseWriter) send() {
+	for key, values := range w.header {
+		w.ResponseWriter.Header()[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// RequestTimeout bounds how long a route's handler may run. The request context gets a
+// deadline, so database work and other calls made with it are cancelled once it passes. A
+// handler that fails after the deadline has its response replaced with a 504 carrying a
+// REQUEST_TIMEOUT error; one that succeeds despite the deadline is answered as usual, since
+// its work completed. Responses are held until the handler returns, so streaming routes
+// should not use it. A timeout of zero or less leaves the route unbounded.
+func RequestTimeout(timeout time.Duration, service string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		original := c.Writer
+		buffered := newBufferedResponseWriter(original)
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = buffered
+		// Restored on panic too, so an outer recovery's response reaches the client
+		defer func() { c.Writer = original }()
+
+		c.Next()
+
+		c.Writer = original
+		if ctx.Err() == context.DeadlineExceeded && buffered.status >= http.StatusBadRequest {
+			err := errors.NewError(errors.ErrRequestTimeout, fmt.Sprintf("request did not complete within %s", timeout), service)
+			c.JSON(err.GetHTTPStatus(), gin.H{
+				"error":      err.Code,
+				"message":    err.Message,
+				"service":    err.Service,
+				"request_id": c.GetString("request_id"),
+				"timestamp":  err.Timestamp,
+			})
+			return
+		}
+
+		if buffered.written || buffered.status != http.StatusOK {
+			buffered.send()
+		}
+	}
+}
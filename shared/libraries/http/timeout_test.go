@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.GET("/work", RequestTimeout(20*time.Millisecond, "test-service"), handler)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/work", nil))
+		return w
+	}
+
+	t.Run("slow handler is cancelled and answered with 504", func(t *testing.T) {
+		var handlerErr error
+		w := serve(func(c *gin.Context) {
+			select {
+			case <-c.Request.Context().Done():
+				handlerErr = c.Request.Context().Err()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "query cancelled"})
+			case <-time.After(time.Second):
+				c.JSON(http.StatusOK, gin.H{})
+			}
+		})
+
+		if handlerErr != context.DeadlineExceeded {
+			t.Errorf("Expected the handler's context to be cancelled by the deadline, got %v", handlerErr)
+		}
+		if w.Code != http.StatusGatewayTimeout {
+			t.Fatalf("Expected 504, got %d", w.Code)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body, got %q", w.Body.String())
+		}
+		if body["error"] != "REQUEST_TIMEOUT" || body["service"] != "test-service" {
+			t.Errorf("Expected a REQUEST_TIMEOUT error from test-service, got %v", body)
+		}
+	})
+
+	t.Run("fast handler response is passed through", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Header("X-Custom", "kept")
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		if w.Code != http.StatusCreated || w.Header().Get("X-Custom") != "kept" {
+			t.Errorf("Expected 201 with the handler's header, got %d %v", w.Code, w.Header())
+		}
+		if w.Body.String() != `{"ok":true}` {
+			t.Errorf("Expected the handler's body, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("status without a body is passed through", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Expected 204, got %d", w.Code)
+		}
+	})
+
+	t.Run("work that completes after the deadline is reported", func(t *testing.T) {
+		w := serve(func(c *gin.Context) {
+			time.Sleep(40 * time.Millisecond)
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected 200 for completed work, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// FieldError is one invalid field of a request body. Field is the field's JSON path, such as
+// "amount" or "metadata.category".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldCheck validates a bound request beyond its binding tags, such as checking a currency
+// is supported, and returns the fields it rejects
+type FieldCheck func() []FieldError
+
+// BindJSON binds the JSON request body into obj, which must be a pointer to a struct. Unlike
+// gin's ShouldBindJSON, which stops at the first problem, every field that cannot be decoded
+// or fails its binding tags or one of the checks is reported, so clients can fix them all at
+// once. On failure a 400 listing the errors under "errors" is sent and false is returned.
+// Checks run whenever the body is a JSON object, so they must tolerate zero-valued fields;
+// their errors for fields that were already rejected are dropped.
+func BindJSON(c *gin.Context, obj interface{}, checks ...FieldCheck) bool {
+	fieldErrors := bindJSON(c.Request, obj, checks)
+	if len(fieldErrors) == 0 {
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":  "Invalid request format",
+		"errors": fieldErrors,
+	})
+	return false
+}
+
+// bindJSON decodes and validates a request body, returning every field error found
+func bindJSON(req *http.Request, obj interface{}, checks []FieldCheck) []FieldError {
+	if req.Body == nil {
+		return []FieldError{{Field: "body", Message: "is required"}}
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return []FieldError{{Field: "body", Message: "could not be read"}}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	fieldErrors, decoded := decodeFields(body, obj)
+	if !decoded {
+		return fieldErrors
+	}
+
+	// Fields that could not be decoded are left zero, so later failures of a field already
+	// reported are dropped
+	rejected := make(map[string]bool, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		rejected[fieldErr.Field] = true
+	}
+	report := func(fieldErr FieldError) {
+		if rejected[strings.SplitN(fieldErr.Field, ".", 2)[0]] || rejected[fieldErr.Field] {
+			return
+		}
+		rejected[fieldErr.Field] = true
+		fieldErrors = append(fieldErrors, fieldErr)
+	}
+
+	var validationErrors validator.ValidationErrors
+	if err := binding.Validator.ValidateStruct(obj); errors.As(err, &validationErrors) {
+		structType := reflect.TypeOf(obj).Elem()
+		for _, fieldErr := range validationErrors {
+			report(FieldError{Field: jsonFieldPath(structType, fieldErr.StructNamespace()), Message: validationMessage(fieldErr)})
+		}
+	} else if err != nil {
+		report(FieldError{Field: "body", Message: err.Error()})
+	}
+
+	for _, check := range checks {
+		for _, fieldErr := range check() {
+			report(fieldErr)
+		}
+	}
+
+	return fieldErrors
+}
+
+// decodeFields decodes a JSON object into obj one top-level field at a time, so one bad value
+// does not hide the others. It reports false if the body is not a JSON object at all.
+func decodeFields(body []byte, obj interface{}) ([]FieldError, bool) {
+	if err := json.Unmarshal(body, obj); err == nil {
+		return nil, true
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return []FieldError{{Field: "body", Message: "must be a JSON object"}}, false
+	}
+
+	var fieldErrors []FieldError
+	value := reflect.ValueOf(obj).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		data, present := lookupJSONField(raw, name)
+		if !present {
+			continue
+		}
+
+		target := reflect.New(field.Type)
+		if err := json.Unmarshal(data, target.Interface()); err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: name, Message: "must be " + typeDescription(field.Type)})
+			value.Field(i).Set(reflect.Zero(field.Type))
+			continue
+		}
+		value.Field(i).Set(target.Elem())
+	}
+	return fieldErrors, true
+}
+
+// lookupJSONField finds a field's raw value, matching names case-insensitively as
+// encoding/json does when an exact match is missing
+func lookupJSONField(raw map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if data, ok := raw[name]; ok {
+		return data, true
+	}
+	for key, data := range raw {
+		if strings.EqualFold(key, name) {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// jsonName returns the name a struct field is encoded under, or false if it is not encoded
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return "", false
+	}
+	if tag == "" {
+		return field.Name, true
+	}
+	return tag, true
+}
+
+// jsonFieldPath converts a validator struct namespace, such as
+// "TransactionRequest.Metadata.Category", into the JSON path "metadata.category"
+func jsonFieldPath(structType reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+	path := make([]string, 0, len(parts))
+	current := structType
+	for _, part := range parts {
+		// Slice and map elements are namespaced as Field[0]
+		fieldName, index, _ := strings.Cut(part, "[")
+		name := fieldName
+		if current != nil && current.Kind() == reflect.Struct {
+			if field, ok := current.FieldByName(fieldName); ok {
+				if encoded, ok := jsonName(field); ok {
+					name = encoded
+				}
+				current = indirectType(field.Type)
+			} else {
+				current = nil
+			}
+		}
+		if index != "" {
+			name += "[" + index
+		}
+		path = append(path, name)
+	}
+	return strings.Join(path, ".")
+}
+
+// indirectType returns the struct type reached through pointers, slices and maps, or nil
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// validationMessage describes a failed binding tag
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldErr.Param())
+	case "gte":
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fieldErr.Param())
+	case "lte":
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "min":
+		return fmt.Sprintf("must have at least %s", fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("must have at most %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", fieldErr.Param())
+	default:
+		return fmt.Sprintf("failed the %s check", fieldErr.Tag())
+	}
+}
+
+// typeDescription names the kind of JSON value a field type accepts
+func typeDescription(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(uuid.UUID{}):
+		return "a valid UUID"
+	case reflect.TypeOf(time.Time{}):
+		return "an RFC 3339 timestamp"
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeDescription(t.Elem())
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type testTransferRequest struct {
+	FromWallet uuid.UUID `json:"from_wallet" binding:"required"`
+	ToWallet   uuid.UUID `json:"to_wallet" binding:"required"`
+	Amount     float64   `json:"amount" binding:"required,gt=0"`
+	Currency   string    `json:"currency" binding:"required"`
+	Metadata   struct {
+		Category string `json:"category" binding:"max=8"`
+	} `json:"metadata"`
+}
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	supported := func(req *testTransferRequest) FieldCheck {
+		return func() []FieldError {
+			if req.Currency != "" && req.Currency != "USD-CBDC" {
+				return []FieldError{{Field: "currency", Message: "is not a supported currency"}}
+			}
+			return nil
+		}
+	}
+
+	perform := func(body string) (*httptest.ResponseRecorder, *testTransferRequest, bool) {
+		var req testTransferRequest
+		var bound bool
+		r := gin.New()
+		r.POST("/transfers", func(c *gin.Context) {
+			if bound = BindJSON(c, &req, supported(&req)); bound {
+				c.Status(http.StatusCreated)
+			}
+		})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body)))
+		return w, &req, bound
+	}
+
+	fieldErrors := func(t *testing.T, w *httptest.ResponseRecorder) []FieldError {
+		t.Helper()
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %d", w.Code)
+		}
+		var response struct {
+			Error  string       `json:"error"`
+			Errors []FieldError `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error != "Invalid request format" {
+			t.Errorf("Expected the generic error message to be kept, got %q", response.Error)
+		}
+		return response.Errors
+	}
+
+	t.Run("every invalid field is reported", func(t *testing.T) {
+		w, _, bound := perform(`{"to_wallet": "not-a-uuid", "amount": -5, "currency": "XYZ", "metadata": {"category": "entertainment"}}`)
+		if bound {
+			t.Fatal("Expected binding to fail")
+		}
+
+		expected := []FieldError{
+			{Field: "to_wallet", Message: "must be a valid UUID"},
+			{Field: "from_wallet", Message: "is required"},
+			{Field: "amount", Message: "must be greater than 0"},
+			{Field: "metadata.category", Message: "must have at most 8"},
+			{Field: "currency", Message: "is not a supported currency"},
+		}
+		if got := fieldErrors(t, w); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected errors %+v, got %+v", expected, got)
+		}
+	})
+
+	t.Run("wrongly typed values are reported once", func(t *testing.T) {
+		w, _, _ := perform(`{"from_wallet": "` + uuid.New().String() + `", "to_wallet": "` + uuid.New().String() + `", "amount": "ten", "currency": 840}`)
+
+		expected := []FieldError{
+			{Field: "amount", Message: "must be a number"},
+			{Field: "currency", Message: "must be a string"},
+		}
+		if got := fieldErrors(t, w); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected errors %+v, got %+v", expected, got)
+		}
+	})
+
+	t.Run("body that is not an object", func(t *testing.T) {
+		w, _, _ := perform(`[1, 2`)
+
+		expected := []FieldError{{Field: "body", Message: "must be a JSON object"}}
+		if got := fieldErrors(t, w); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected errors %+v, got %+v", expected, got)
+		}
+	})
+
+	t.Run("valid request is bound", func(t *testing.T) {
+		from, to := uuid.New(), uuid.New()
+		w, req, bound := perform(`{"from_wallet": "` + from.String() + `", "to_wallet": "` + to.String() + `", "amount": 12.5, "currency": "USD-CBDC"}`)
+
+		if !bound || w.Code != http.StatusCreated {
+			t.Fatalf("Expected request to bind, got %d: %s", w.Code, w.Body.String())
+		}
+		if req.FromWallet != from || req.ToWallet != to || req.Amount != 12.5 || req.Currency != "USD-CBDC" {
+			t.Errorf("Expected request fields to be bound, got %+v", req)
+		}
+	})
+}
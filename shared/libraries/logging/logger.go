@@ -2,8 +2,10 @@ package logging
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -23,12 +25,30 @@ type LogEntry struct {
 	Fields      map[string]interface{} `json:"fields,omitempty"`
 }
 
+// contextKey keys the correlation values a request carries in its context
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	fieldsKey
+)
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = NewLogger("echopay")
+)
+
 func NewLogger(serviceName string) *Logger {
+	return NewLoggerWithWriter(serviceName, os.Stdout)
+}
+
+// NewLoggerWithWriter creates a JSON logger that writes to w
+func NewLoggerWithWriter(serviceName string, w io.Writer) *Logger {
 	opts := &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}
 	
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	handler := slog.NewJSONHandler(w, opts)
 	logger := slog.New(handler)
 	
 	return &Logger{
@@ -37,21 +57,72 @@ func NewLogger(serviceName string) *Logger {
 	}
 }
 
+// SetDefault sets the logger that WithContext derives request-scoped loggers from. Services
+// call it once at startup with their own logger.
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+// Default returns the logger set by SetDefault
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// WithContext derives a child of the default logger carrying the correlation ID and any
+// fields attached to ctx, so every line logged while handling a request can be traced
+func WithContext(ctx context.Context) *Logger {
+	return Default().WithContext(ctx)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request's correlation ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the correlation ID carried by ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	return getRequestID(ctx)
+}
+
+// ContextWithFields returns a copy of ctx whose loggers also attach the given key-value
+// pairs, such as the ID of the transaction or token being processed
+func ContextWithFields(ctx context.Context, args ...interface{}) context.Context {
+	existing, _ := ctx.Value(fieldsKey).([]interface{})
+	fields := make([]interface{}, 0, len(existing)+len(args))
+	fields = append(fields, existing...)
+	fields = append(fields, args...)
+	return context.WithValue(ctx, fieldsKey, fields)
+}
+
+// WithContext derives a child logger carrying the service name, the correlation values in
+// ctx and any fields attached with ContextWithFields. Empty values are left out.
 func (l *Logger) WithContext(ctx context.Context) *Logger {
+	args := []interface{}{"service", l.serviceName}
+	
 	// Extract trace ID, user ID, request ID from context
-	traceID := getTraceID(ctx)
-	userID := getUserID(ctx)
-	requestID := getRequestID(ctx)
+	for _, field := range []struct {
+		key   string
+		value string
+	}{
+		{"trace_id", getTraceID(ctx)},
+		{"user_id", getUserID(ctx)},
+		{"request_id", getRequestID(ctx)},
+	} {
+		if field.value != "" {
+			args = append(args, field.key, field.value)
+		}
+	}
 	
-	logger := l.Logger.With(
-		"service", l.serviceName,
-		"trace_id", traceID,
-		"user_id", userID,
-		"request_id", requestID,
-	)
+	if fields, ok := ctx.Value(fieldsKey).([]interface{}); ok {
+		args = append(args, fields...)
+	}
 	
 	return &Logger{
-		Logger:      logger,
+		Logger:      l.Logger.With(args...),
 		serviceName: l.serviceName,
 	}
 }
@@ -95,8 +166,8 @@ func getUserID(ctx context.Context) string {
 }
 
 func getRequestID(ctx context.Context) string {
-	if requestID := ctx.Value("request_id"); requestID != nil {
-		return requestID.(string)
+	if requestID, ok := ctx.Value(requestIDKey).(string); ok {
+		return requestID
 	}
 	return ""
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestLogger_WithContext(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLoggerWithWriter("token-management", &output)
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithFields(ctx, "token_id", "tok-1")
+	ctx = ContextWithFields(ctx, "wallet_id", "wallet-1")
+	logger.WithContext(ctx).Info("Token frozen")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", output.String(), err)
+	}
+	
+	expected := map[string]string{
+		"service":    "token-management",
+		"request_id": "req-1",
+		"token_id":   "tok-1",
+		"wallet_id":  "wallet-1",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s %s, got %v", key, value, entry[key])
+		}
+	}
+	
+	// Missing correlation values are left out rather than logged empty
+	if _, ok := entry["trace_id"]; ok {
+		t.Error("Expected no trace_id without one in the context")
+	}
+	
+	if RequestIDFromContext(ctx) != "req-1" {
+		t.Errorf("Expected request ID req-1, got %s", RequestIDFromContext(ctx))
+	}
+}
+
+func TestContextWithFields_DoesNotAffectParent(t *testing.T) {
+	parent := ContextWithFields(context.Background(), "transaction_id", "tx-1")
+	ContextWithFields(parent, "token_id", "tok-1")
+	
+	var output bytes.Buffer
+	NewLoggerWithWriter("transaction-service", &output).WithContext(parent).Info("Transaction completed")
+	
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", output.String(), err)
+	}
+	if _, ok := entry["token_id"]; ok {
+		t.Error("Expected a child context's fields not to leak into its parent")
+	}
+}
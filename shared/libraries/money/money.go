@@ -0,0 +1,132 @@
+// Package money converts float64 amounts to and from integer minor units so that balance
+// arithmetic is exact and matches the fixed-point values stored in the database.
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDecimals is the number of minor-unit decimal places used by currencies without an
+// override, matching the DECIMAL(15,2) amount and balance columns
+const DefaultDecimals = 2
+
+// MaxSupportedDecimals is the largest number of minor-unit decimal places a currency may use
+const MaxSupportedDecimals = 8
+
+var (
+	decimalsMutex sync.RWMutex
+
+	// currencyDecimals overrides DefaultDecimals by currency code, or by ISO 4217 code for
+	// every CBDC of that currency
+	currencyDecimals = map[string]int{
+		"JPY": 0,
+		"KRW": 0,
+	}
+)
+
+// SetDecimals sets the number of minor-unit decimal places for a currency, either a full code
+// such as "BHD-CBDC" or an ISO 4217 code. Amounts are rounded and formatted to this precision
+// everywhere in the process, so it should be set at startup before any amounts are handled.
+func SetDecimals(currency string, decimals int) error {
+	if currency == "" {
+		return fmt.Errorf("currency code cannot be empty")
+	}
+	if decimals < 0 || decimals > MaxSupportedDecimals {
+		return fmt.Errorf("decimal places for %s must be between 0 and %d, got %d", currency, MaxSupportedDecimals, decimals)
+	}
+
+	decimalsMutex.Lock()
+	defer decimalsMutex.Unlock()
+	currencyDecimals[strings.ToUpper(currency)] = decimals
+	return nil
+}
+
+// Decimals returns the number of minor-unit decimal places for a currency. An override for
+// the full code wins; otherwise CBDC codes such as "USD-CBDC" are matched by their ISO 4217
+// prefix.
+func Decimals(currency string) int {
+	code := strings.ToUpper(currency)
+
+	decimalsMutex.RLock()
+	defer decimalsMutex.RUnlock()
+
+	if decimals, ok := currencyDecimals[code]; ok {
+		return decimals
+	}
+	if i := strings.IndexByte(code, '-'); i >= 0 {
+		code = code[:i]
+	}
+	if decimals, ok := currencyDecimals[code]; ok {
+		return decimals
+	}
+	return DefaultDecimals
+}
+
+// MaxDecimals returns the largest number of decimal places any currency uses, and so the
+// scale amount columns need to store every currency exactly
+func MaxDecimals() int {
+	decimalsMutex.RLock()
+	defer decimalsMutex.RUnlock()
+
+	max := DefaultDecimals
+	for _, decimals := range currencyDecimals {
+		if decimals > max {
+			max = decimals
+		}
+	}
+	return max
+}
+
+// scale returns the number of minor units in one major unit of a currency
+func scale(currency string) float64 {
+	return math.Pow10(Decimals(currency))
+}
+
+// ToMinor converts an amount to integer minor units. The amount's shortest decimal form is
+// rounded half away from zero, which is how the database rounds the same value into a
+// DECIMAL column, so 1.005 becomes 101 cents even though its float64 value is just below it.
+func ToMinor(amount float64, currency string) int64 {
+	decimals := Decimals(currency)
+
+	digits := strconv.FormatFloat(math.Abs(amount), 'f', -1, 64)
+	whole, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, fraction = digits[:i], digits[i+1:]
+	}
+	fraction += strings.Repeat("0", decimals+1)
+
+	minor, _ := strconv.ParseInt(whole+fraction[:decimals], 10, 64)
+	if fraction[decimals] >= '5' {
+		minor++
+	}
+
+	if amount < 0 {
+		return -minor
+	}
+	return minor
+}
+
+// FromMinor converts integer minor units back to an amount
+func FromMinor(minor int64, currency string) float64 {
+	return float64(minor) / scale(currency)
+}
+
+// Round rounds an amount to the currency's minor unit
+func Round(amount float64, currency string) float64 {
+	return FromMinor(ToMinor(amount, currency), currency)
+}
+
+// IsExact reports whether an amount has no precision finer than the currency's minor unit
+func IsExact(amount float64, currency string) bool {
+	return Round(amount, currency) == amount
+}
+
+// Format renders an amount with exactly the currency's number of decimal places, so a
+// zero-decimal currency shows "1235" and a three-decimal one "12.346"
+func Format(amount float64, currency string) string {
+	return strconv.FormatFloat(Round(amount, currency), 'f', Decimals(currency), 64)
+}
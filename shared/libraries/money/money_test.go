@@ -0,0 +1,175 @@
+package money
+
+import (
+	"testing"
+)
+
+func TestDecimals(t *testing.T) {
+	tests := map[string]int{
+		"USD-CBDC": 2,
+		"EUR-CBDC": 2,
+		"GBP-CBDC": 2,
+		"JPY-CBDC": 0,
+		"jpy":      0,
+		"":         DefaultDecimals,
+	}
+
+	for currency, expected := range tests {
+		if got := Decimals(currency); got != expected {
+			t.Errorf("Decimals(%q) = %d, expected %d", currency, got, expected)
+		}
+	}
+}
+
+func TestToMinorRoundsToNearestUnit(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		expected int64
+	}{
+		{0.1, "USD-CBDC", 10},
+		{19.99, "USD-CBDC", 1999},
+		{1.005, "USD-CBDC", 101}, // The float64 is 1.00499999..., but the database rounds "1.005" up
+		{2.675, "EUR-CBDC", 268},
+		{0.125, "USD-CBDC", 13},
+		{-0.125, "USD-CBDC", -13},
+		{1234.5, "JPY-CBDC", 1235},
+	}
+
+	for _, tt := range tests {
+		if got := ToMinor(tt.amount, tt.currency); got != tt.expected {
+			t.Errorf("ToMinor(%v, %q) = %d, expected %d", tt.amount, tt.currency, got, tt.expected)
+		}
+	}
+}
+
+func TestSummingSmallAmountsIsExact(t *testing.T) {
+	const currency = "USD-CBDC"
+
+	// Float addition drifts: 0.1+0.2 != 0.3 and 10,000 additions of 0.01 miss 100
+	floatTotal := 0.0
+	var minorTotal int64
+	for i := 0; i < 10000; i++ {
+		floatTotal += 0.01
+		minorTotal += ToMinor(0.01, currency)
+	}
+
+	if floatTotal == 100 {
+		t.Fatal("Expected float addition to drift, test no longer demonstrates the problem")
+	}
+	if total := FromMinor(minorTotal, currency); total != 100 {
+		t.Errorf("Expected exact total 100, got %v", total)
+	}
+
+	balance := ToMinor(0.1, currency) + ToMinor(0.2, currency)
+	if FromMinor(balance, currency) != 0.3 {
+		t.Errorf("Expected 0.1 + 0.2 to equal 0.3, got %v", FromMinor(balance, currency))
+	}
+}
+
+func TestRoundAndIsExact(t *testing.T) {
+	if got := Round(10.006, "USD-CBDC"); got != 10.01 {
+		t.Errorf("Expected 10.01, got %v", got)
+	}
+	if got := Round(0.1+0.2, "USD-CBDC"); got != 0.3 {
+		t.Errorf("Expected 0.3, got %v", got)
+	}
+
+	if !IsExact(19.99, "USD-CBDC") {
+		t.Error("Expected 19.99 to be exact in cents")
+	}
+	if IsExact(19.999, "USD-CBDC") {
+		t.Error("Expected 19.999 to be finer than a cent")
+	}
+	if IsExact(0.5, "JPY-CBDC") {
+		t.Error("Expected 0.5 to be finer than a yen")
+	}
+}
+
+// setDecimals configures a currency's precision for the duration of a test
+func setDecimals(t *testing.T, currency string, decimals int) {
+	if err := SetDecimals(currency, decimals); err != nil {
+		t.Fatalf("SetDecimals(%q, %d) failed: %v", currency, decimals, err)
+	}
+	t.Cleanup(func() {
+		decimalsMutex.Lock()
+		defer decimalsMutex.Unlock()
+		delete(currencyDecimals, currency)
+	})
+}
+
+func TestZeroDecimalCurrency(t *testing.T) {
+	setDecimals(t, "XOF-CBDC", 0)
+
+	if got := Decimals("XOF-CBDC"); got != 0 {
+		t.Fatalf("Expected 0 decimal places, got %d", got)
+	}
+	if got := ToMinor(1234.5, "XOF-CBDC"); got != 1235 {
+		t.Errorf("Expected 1234.5 to round to 1235 minor units, got %d", got)
+	}
+	if got := Round(99.49, "XOF-CBDC"); got != 99 {
+		t.Errorf("Expected 99.49 to round to 99, got %v", got)
+	}
+	if IsExact(10.5, "XOF-CBDC") {
+		t.Error("Expected 10.5 to be finer than the minor unit")
+	}
+	if got := Format(1234.5, "XOF-CBDC"); got != "1235" {
+		t.Errorf("Expected \"1235\", got %q", got)
+	}
+}
+
+func TestThreeDecimalCurrency(t *testing.T) {
+	setDecimals(t, "BHD-CBDC", 3)
+
+	if got := ToMinor(12.3456, "BHD-CBDC"); got != 12346 {
+		t.Errorf("Expected 12.3456 to round to 12346 minor units, got %d", got)
+	}
+	if got := ToMinor(1.0005, "BHD-CBDC"); got != 1001 {
+		t.Errorf("Expected 1.0005 to round half up to 1001 minor units, got %d", got)
+	}
+	if !IsExact(0.125, "BHD-CBDC") {
+		t.Error("Expected 0.125 to be exact in fils")
+	}
+	if got := FromMinor(ToMinor(0.1, "BHD-CBDC")+ToMinor(0.002, "BHD-CBDC"), "BHD-CBDC"); got != 0.102 {
+		t.Errorf("Expected 0.1 + 0.002 to equal 0.102, got %v", got)
+	}
+	if got := Format(7.5, "BHD-CBDC"); got != "7.500" {
+		t.Errorf("Expected \"7.500\", got %q", got)
+	}
+	if got := MaxDecimals(); got != 3 {
+		t.Errorf("Expected columns to need 3 decimal places, got %d", got)
+	}
+}
+
+func TestSetDecimals(t *testing.T) {
+	setDecimals(t, "JPY-CBDC", 2)
+
+	// A full-code override wins over the ISO 4217 code
+	if got := Decimals("JPY-CBDC"); got != 2 {
+		t.Errorf("Expected JPY-CBDC override of 2, got %d", got)
+	}
+	if got := Decimals("JPY"); got != 0 {
+		t.Errorf("Expected JPY to keep 0 decimal places, got %d", got)
+	}
+
+	for _, decimals := range []int{-1, MaxSupportedDecimals + 1} {
+		if err := SetDecimals("USD-CBDC", decimals); err == nil {
+			t.Errorf("Expected %d decimal places to be rejected", decimals)
+		}
+	}
+	if err := SetDecimals("", 2); err == nil {
+		t.Error("Expected an empty currency code to be rejected")
+	}
+	if got := MaxDecimals(); got != DefaultDecimals {
+		t.Errorf("Expected default columns to need %d decimal places, got %d", DefaultDecimals, got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format(10, "USD-CBDC"); got != "10.00" {
+		t.Errorf("Expected \"10.00\", got %q", got)
+	}
+	if got := Format(0.1+0.2, "EUR-CBDC"); got != "0.30" {
+		t.Errorf("Expected \"0.30\", got %q", got)
+	}
+}
@@ -1,10 +1,18 @@
 package monitoring
 
 import (
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Transaction outcome label values
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
 )
 
 type Metrics struct {
@@ -12,11 +20,15 @@ type Metrics struct {
 	TransactionCounter    prometheus.Counter
 	TransactionDuration   prometheus.Histogram
 	TransactionErrors     prometheus.Counter
+	TransactionLatency    *prometheus.HistogramVec // Labeled by currency and outcome
+	TransactionOutcomes   *prometheus.CounterVec   // Labeled by currency and outcome
+	TransactionAmounts    *prometheus.SummaryVec   // Completed transaction amounts, labeled by currency
 	
 	// Fraud detection metrics
 	FraudDetectionLatency prometheus.Histogram
 	FraudScoreDistribution prometheus.Histogram
 	FalsePositiveRate     prometheus.Gauge
+	FraudScoresByCurrency *prometheus.SummaryVec // Labeled by currency
 	
 	// Reversibility metrics
 	ReversalCounter       prometheus.Counter
@@ -27,6 +39,7 @@ type Metrics struct {
 	ActiveConnections     prometheus.Gauge
 	DatabaseConnections   prometheus.Gauge
 	QueueDepth           prometheus.Gauge
+	EventStreamingHealthy prometheus.Gauge // 1 when events are being published, 0 when degraded
 }
 
 func NewMetrics(serviceName string) *Metrics {
@@ -50,6 +63,26 @@ func NewMetrics(serviceName string) *Metrics {
 			ConstLabels: prometheus.Labels{"service": serviceName},
 		}),
 		
+		TransactionLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "echopay_transaction_processing_seconds",
+			Help: "Transaction processing latency by currency and outcome",
+			Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5},
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"currency", "outcome"}),
+		
+		TransactionOutcomes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "echopay_transaction_outcomes_total",
+			Help: "Total number of processed transactions by currency and outcome",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"currency", "outcome"}),
+		
+		TransactionAmounts: promauto.NewSummaryVec(prometheus.SummaryOpts{
+			Name: "echopay_transaction_amount",
+			Help: "Amounts of completed transactions by currency",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"currency"}),
+		
 		FraudDetectionLatency: promauto.NewHistogram(prometheus.HistogramOpts{
 			Name: "echopay_fraud_detection_duration_seconds",
 			Help: "Fraud detection processing duration",
@@ -70,6 +103,13 @@ func NewMetrics(serviceName string) *Metrics {
 			ConstLabels: prometheus.Labels{"service": serviceName},
 		}),
 		
+		FraudScoresByCurrency: promauto.NewSummaryVec(prometheus.SummaryOpts{
+			Name: "echopay_transaction_fraud_score",
+			Help: "Fraud scores assigned to transactions by currency",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"currency"}),
+		
 		ReversalCounter: promauto.NewCounter(prometheus.CounterOpts{
 			Name: "echopay_reversals_total",
 			Help: "Total number of transaction reversals",
@@ -107,6 +147,12 @@ func NewMetrics(serviceName string) *Metrics {
 			Help: "Current message queue depth",
 			ConstLabels: prometheus.Labels{"service": serviceName},
 		}),
+		
+		EventStreamingHealthy: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "echopay_event_streaming_healthy",
+			Help: "Whether the event publisher passed its last health check (1) or is degraded (0)",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
 	}
 }
 
@@ -120,6 +166,71 @@ func (m *Metrics) RecordTransactionError() {
 	m.TransactionErrors.Inc()
 }
 
+// RecordTransactionOutcome records the latency and outcome of a processed transaction
+func (m *Metrics) RecordTransactionOutcome(currency, outcome string, duration time.Duration) {
+	m.TransactionLatency.WithLabelValues(currency, outcome).Observe(duration.Seconds())
+	m.TransactionOutcomes.WithLabelValues(currency, outcome).Inc()
+}
+
+// RecordTransactionAmount records the amount of a completed transaction
+func (m *Metrics) RecordTransactionAmount(currency string, amount float64) {
+	m.TransactionAmounts.WithLabelValues(currency).Observe(amount)
+}
+
+// RecordTransactionFraudScore records the fraud score assigned to a transaction
+func (m *Metrics) RecordTransactionFraudScore(currency string, score float64) {
+	m.FraudScoresByCurrency.WithLabelValues(currency).Observe(score)
+}
+
+// TransactionLatencyQuantile estimates the q-quantile of transaction latency across all
+// currencies and outcomes from the histogram buckets, interpolating linearly within a bucket
+// as PromQL's histogram_quantile does. It returns zero when nothing has been observed.
+func (m *Metrics) TransactionLatencyQuantile(q float64) time.Duration {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		m.TransactionLatency.Collect(ch)
+		close(ch)
+	}()
+
+	cumulative := make(map[float64]uint64)
+	var total uint64
+	for metric := range ch {
+		var pb dto.Metric
+		if err := metric.Write(&pb); err != nil {
+			continue
+		}
+		histogram := pb.GetHistogram()
+		total += histogram.GetSampleCount()
+		for _, bucket := range histogram.GetBucket() {
+			cumulative[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	bounds := make([]float64, 0, len(cumulative))
+	for bound := range cumulative {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+
+	rank := q * float64(total)
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, bound := range bounds {
+		count := float64(cumulative[bound])
+		if count >= rank && count > lowerCount {
+			seconds := lowerBound + (bound-lowerBound)*(rank-lowerCount)/(count-lowerCount)
+			return time.Duration(seconds * float64(time.Second))
+		}
+		lowerBound, lowerCount = bound, count
+	}
+
+	// Observations above the largest bucket are reported at that bucket's bound
+	return time.Duration(lowerBound * float64(time.Second))
+}
+
 func (m *Metrics) RecordFraudDetection(duration time.Duration, riskScore float64) {
 	m.FraudDetectionLatency.Observe(duration.Seconds())
 	m.FraudScoreDistribution.Observe(riskScore)
@@ -144,4 +255,12 @@ func (m *Metrics) UpdateDatabaseConnections(count int) {
 
 func (m *Metrics) UpdateQueueDepth(depth int) {
 	m.QueueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) SetEventStreamingHealthy(healthy bool) {
+	if healthy {
+		m.EventStreamingHealthy.Set(1)
+		return
+	}
+	m.EventStreamingHealthy.Set(0)
 }
\ No newline at end of file
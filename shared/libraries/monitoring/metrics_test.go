@@ -0,0 +1,142 @@
+package monitoring
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+
+	sharedhttp "echopay/shared/libraries/http"
+)
+
+var (
+	testMetrics     *Metrics
+	testMetricsOnce sync.Once
+)
+
+// getTestMetrics returns a single Metrics instance, since collectors register globally
+func getTestMetrics() *Metrics {
+	testMetricsOnce.Do(func() {
+		testMetrics = NewMetrics("monitoring-test")
+	})
+	return testMetrics
+}
+
+func TestTransactionLatencyExposedOnMetricsEndpoint(t *testing.T) {
+	metrics := getTestMetrics()
+	metrics.RecordTransactionOutcome("USD-CBDC", OutcomeSuccess, 20*time.Millisecond)
+	metrics.RecordTransactionOutcome("EUR-CBDC", OutcomeFailure, 3*time.Millisecond)
+	
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/metrics", sharedhttp.MetricsHandler())
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	
+	body := w.Body.String()
+	expected := []string{
+		"# TYPE echopay_transaction_processing_seconds histogram",
+		`echopay_transaction_processing_seconds_bucket{currency="USD-CBDC",outcome="success",service="monitoring-test",le="0.025"}`,
+		`echopay_transaction_processing_seconds_count{currency="EUR-CBDC",outcome="failure",service="monitoring-test"} 1`,
+		`echopay_transaction_outcomes_total{currency="USD-CBDC",outcome="success",service="monitoring-test"}`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected /metrics output to contain %q", want)
+		}
+	}
+}
+
+func TestTransactionLatencyQuantile(t *testing.T) {
+	metrics := getTestMetrics()
+	metrics.TransactionLatency.Reset()
+	
+	if q := metrics.TransactionLatencyQuantile(0.95); q != 0 {
+		t.Errorf("Expected zero quantile with no observations, got %v", q)
+	}
+	
+	// 90 fast transactions in the (1ms, 2.5ms] bucket and 10 slow ones in (50ms, 100ms]
+	for i := 0; i < 90; i++ {
+		metrics.RecordTransactionOutcome("USD-CBDC", OutcomeSuccess, 2*time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		metrics.RecordTransactionOutcome("GBP-CBDC", OutcomeFailure, 80*time.Millisecond)
+	}
+	
+	p50 := metrics.TransactionLatencyQuantile(0.5)
+	if p50 <= time.Millisecond || p50 > 2500*time.Microsecond {
+		t.Errorf("Expected p50 within (1ms, 2.5ms], got %v", p50)
+	}
+	
+	p95 := metrics.TransactionLatencyQuantile(0.95)
+	if p95 <= 50*time.Millisecond || p95 > 100*time.Millisecond {
+		t.Errorf("Expected p95 within (50ms, 100ms], got %v", p95)
+	}
+	
+	// Observations beyond the largest bucket report that bucket's bound
+	metrics.TransactionLatency.Reset()
+	metrics.RecordTransactionOutcome("USD-CBDC", OutcomeSuccess, 10*time.Second)
+	if q := metrics.TransactionLatencyQuantile(0.99); q != 2500*time.Millisecond {
+		t.Errorf("Expected p99 capped at 2.5s, got %v", q)
+	}
+}
+
+func TestSetEventStreamingHealthy(t *testing.T) {
+	metrics := getTestMetrics()
+	
+	metrics.SetEventStreamingHealthy(false)
+	var gauge dto.Metric
+	if err := metrics.EventStreamingHealthy.Write(&gauge); err != nil {
+		t.Fatalf("Failed to read gauge: %v", err)
+	}
+	if gauge.GetGauge().GetValue() != 0 {
+		t.Errorf("Expected degraded gauge value 0, got %v", gauge.GetGauge().GetValue())
+	}
+	
+	metrics.SetEventStreamingHealthy(true)
+	if err := metrics.EventStreamingHealthy.Write(&gauge); err != nil {
+		t.Fatalf("Failed to read gauge: %v", err)
+	}
+	if gauge.GetGauge().GetValue() != 1 {
+		t.Errorf("Expected healthy gauge value 1, got %v", gauge.GetGauge().GetValue())
+	}
+}
+
+func TestTransactionAmountAndFraudScoreByCurrency(t *testing.T) {
+	metrics := getTestMetrics()
+	metrics.RecordTransactionAmount("USD-CBDC", 120.0)
+	metrics.RecordTransactionAmount("USD-CBDC", 80.0)
+	metrics.RecordTransactionAmount("JPY-CBDC", 5000.0)
+	metrics.RecordTransactionFraudScore("USD-CBDC", 0.2)
+	
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/metrics", sharedhttp.MetricsHandler())
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	
+	body := w.Body.String()
+	expected := []string{
+		"# TYPE echopay_transaction_amount summary",
+		`echopay_transaction_amount_sum{currency="USD-CBDC",service="monitoring-test"} 200`,
+		`echopay_transaction_amount_count{currency="JPY-CBDC",service="monitoring-test"} 1`,
+		`echopay_transaction_amount{currency="USD-CBDC",service="monitoring-test",quantile="0.5"}`,
+		`echopay_transaction_fraud_score_count{currency="USD-CBDC",service="monitoring-test"} 1`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected /metrics output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"echopay/shared/libraries/config"
+)
+
+// Event types delivered to webhook endpoints
+const (
+	EventTokenFrozen         = "token.frozen"
+	EventTokenUnfrozen       = "token.unfrozen"
+	EventTokensBulkFrozen    = "token.bulk_frozen"
+	EventTransactionReversed = "transaction.reversed"
+)
+
+// Headers set on every webhook request
+const (
+	SignatureHeader = "X-EchoPay-Signature"
+	EventHeader     = "X-EchoPay-Event"
+	DeliveryHeader  = "X-EchoPay-Delivery"
+)
+
+// maxRecordedDeliveries bounds the number of delivery records kept in memory
+const maxRecordedDeliveries = 1000
+
+// DeliveryStatus represents the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Endpoint is a registered webhook receiver. An empty EventTypes filter receives every event.
+type Endpoint struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Delivery records the outcome of sending one event to one endpoint
+type Delivery struct {
+	ID           uuid.UUID      `json:"id"`
+	EndpointID   uuid.UUID      `json:"endpoint_id"`
+	EventID      uuid.UUID      `json:"event_id"`
+	EventType    string         `json:"event_type"`
+	Status       DeliveryStatus `json:"status"`
+	Attempts     int            `json:"attempts"`
+	ResponseCode int            `json:"response_code,omitempty"`
+	LastError    string         `json:"last_error,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+}
+
+// Event is the JSON payload posted to webhook endpoints
+type Event struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Dispatcher delivers events to registered endpoints asynchronously, signing each payload
+// with the endpoint's secret and retrying failed attempts. Registrations live only in the
+// dispatcher's memory: they do not survive a restart and each service instance has its own.
+type Dispatcher struct {
+	client       *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+
+	allowedHosts map[string]bool
+	allowPrivate bool
+	lookupIP     func(host string) ([]net.IP, error)
+
+	mu            sync.RWMutex
+	endpoints     map[uuid.UUID]*Endpoint
+	deliveries    map[uuid.UUID]*Delivery
+	deliveryOrder []uuid.UUID
+
+	inFlight sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher with the given delivery configuration
+func NewDispatcher(cfg config.WebhookConfig) *Dispatcher {
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	allowedHosts := make(map[string]bool, len(cfg.AllowedHosts))
+	for _, host := range cfg.AllowedHosts {
+		allowedHosts[strings.ToLower(host)] = true
+	}
+
+	// Re-check the address actually dialled so a host that resolved to a public address at
+	// registration cannot later be pointed at an internal one
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateTargets {
+		dialer := &net.Dialer{
+			Timeout: cfg.Timeout,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || restrictedIP(ip) {
+					return fmt.Errorf("webhook target %s is not a public address", host)
+				}
+				return nil
+			},
+		}
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &Dispatcher{
+		client:       &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		allowedHosts: allowedHosts,
+		allowPrivate: cfg.AllowPrivateTargets,
+		lookupIP:     net.LookupIP,
+		endpoints:    make(map[uuid.UUID]*Endpoint),
+		deliveries:   make(map[uuid.UUID]*Delivery),
+	}
+}
+
+// Register adds an endpoint that receives events matching eventTypes
+func (d *Dispatcher) Register(endpointURL, secret string, eventTypes []string) (*Endpoint, error) {
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", endpointURL)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if err := d.checkTarget(parsed.Hostname()); err != nil {
+		return nil, err
+	}
+
+	endpoint := &Endpoint{
+		ID:         uuid.New(),
+		URL:        endpointURL,
+		Secret:     secret,
+		EventTypes: append([]string{}, eventTypes...),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	d.mu.Lock()
+	d.endpoints[endpoint.ID] = endpoint
+	d.mu.Unlock()
+
+	return endpoint, nil
+}
+
+// Dispatch sends an event to every endpoint subscribed to eventType without blocking the caller.
+// It is safe to call on a nil dispatcher, in which case no webhooks are sent.
+func (d *Dispatcher) Dispatch(eventType string, data interface{}) {
+	if d == nil {
+		return
+	}
+
+	event := Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, endpoint := range d.endpoints {
+		if !endpoint.subscribes(eventType) {
+			continue
+		}
+
+		delivery := &Delivery{
+			ID:         uuid.New(),
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  eventType,
+			Status:     DeliveryStatusPending,
+			CreatedAt:  event.OccurredAt,
+		}
+		d.recordDelivery(delivery)
+
+		d.inFlight.Add(1)
+		go d.deliver(*endpoint, delivery.ID, eventType, body)
+	}
+}
+
+// Deliveries returns a snapshot of recorded deliveries, oldest first
+func (d *Dispatcher) Deliveries() []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	deliveries := make([]Delivery, 0, len(d.deliveryOrder))
+	for _, id := range d.deliveryOrder {
+		deliveries = append(deliveries, *d.deliveries[id])
+	}
+	return deliveries
+}
+
+// Wait blocks until every in-flight delivery has finished
+func (d *Dispatcher) Wait() {
+	d.inFlight.Wait()
+}
+
+// deliver posts the payload, retrying with a linear backoff until it succeeds or retries run out
+func (d *Dispatcher) deliver(endpoint Endpoint, deliveryID uuid.UUID, eventType string, body []byte) {
+	defer d.inFlight.Done()
+
+	signature := Sign(endpoint.Secret, body)
+
+	for attempt := 1; attempt <= d.maxRetries+1; attempt++ {
+		statusCode, err := d.post(endpoint.URL, deliveryID, eventType, signature, body)
+
+		d.mu.Lock()
+		delivery := d.deliveries[deliveryID]
+		if delivery != nil {
+			delivery.Attempts = attempt
+			delivery.ResponseCode = statusCode
+			delivery.LastError = ""
+			if err != nil {
+				delivery.LastError = err.Error()
+			}
+			if err == nil || attempt == d.maxRetries+1 {
+				now := time.Now().UTC()
+				delivery.CompletedAt = &now
+				delivery.Status = DeliveryStatusDelivered
+				if err != nil {
+					delivery.Status = DeliveryStatusFailed
+				}
+			}
+		}
+		d.mu.Unlock()
+
+		if err == nil {
+			return
+		}
+		if attempt <= d.maxRetries {
+			time.Sleep(d.retryBackoff * time.Duration(attempt))
+		}
+	}
+}
+
+// post sends a single delivery attempt and treats any non-2xx response as a failure
+func (d *Dispatcher) post(endpointURL string, deliveryID uuid.UUID, eventType, signature string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, deliveryID.String())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordDelivery stores a delivery record, evicting the oldest once the limit is reached.
+// The caller must hold d.mu.
+func (d *Dispatcher) recordDelivery(delivery *Delivery) {
+	if len(d.deliveryOrder) >= maxRecordedDeliveries {
+		delete(d.deliveries, d.deliveryOrder[0])
+		d.deliveryOrder = d.deliveryOrder[1:]
+	}
+	d.deliveries[delivery.ID] = delivery
+	d.deliveryOrder = append(d.deliveryOrder, delivery.ID)
+}
+
+// checkTarget rejects endpoint hosts outside the configured allowlist or, without one, hosts
+// that resolve to loopback, private or link-local addresses
+func (d *Dispatcher) checkTarget(host string) error {
+	if len(d.allowedHosts) > 0 {
+		if !d.allowedHosts[strings.ToLower(host)] {
+			return fmt.Errorf("webhook host %q is not in the allowed hosts", host)
+		}
+		return nil
+	}
+	if d.allowPrivate {
+		return nil
+	}
+
+	ips, err := d.lookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return fmt.Errorf("webhook host %q could not be resolved", host)
+	}
+	for _, ip := range ips {
+		if restrictedIP(ip) {
+			return fmt.Errorf("webhook host %q resolves to a non-public address", host)
+		}
+	}
+	return nil
+}
+
+// restrictedIP reports whether ip is an address webhooks must not reach, such as loopback,
+// private networks, link-local ranges (including cloud metadata services) or multicast
+func restrictedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
+
+// subscribes reports whether the endpoint's event filter includes eventType
+func (e *Endpoint) subscribes(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature header value for a payload: "sha256=" followed by the
+// hex-encoded HMAC-SHA256 of the body keyed with the endpoint secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is valid for body under secret. Receivers can use
+// it to authenticate deliveries.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,246 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echopay/shared/libraries/config"
+)
+
+// newTestDispatcher allows private targets so deliveries can reach httptest servers
+func newTestDispatcher(maxRetries int) *Dispatcher {
+	return NewDispatcher(config.WebhookConfig{
+		Timeout:             time.Second,
+		MaxRetries:          maxRetries,
+		RetryBackoff:        time.Millisecond,
+		AllowPrivateTargets: true,
+	})
+}
+
+func TestDispatcherSignsPayload(t *testing.T) {
+	const secret = "fraud-ops-secret"
+
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if !VerifySignature(secret, body, r.Header.Get(SignatureHeader)) {
+			t.Errorf("Signature header %q does not match payload", r.Header.Get(SignatureHeader))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(EventHeader) != EventTokenFrozen {
+			t.Errorf("Expected event header %s, got %s", EventTokenFrozen, r.Header.Get(EventHeader))
+		}
+
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher := newTestDispatcher(0)
+	if _, err := dispatcher.Register(server.URL, secret, []string{EventTokenFrozen}); err != nil {
+		t.Fatalf("Failed to register endpoint: %v", err)
+	}
+
+	dispatcher.Dispatch(EventTokenFrozen, map[string]string{"token_id": "abc"})
+	dispatcher.Wait()
+
+	select {
+	case event := <-received:
+		if event.Type != EventTokenFrozen {
+			t.Errorf("Expected event type %s, got %s", EventTokenFrozen, event.Type)
+		}
+	default:
+		t.Fatal("Expected webhook to be delivered")
+	}
+
+	deliveries := dispatcher.Deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+	}
+	if deliveries[0].Status != DeliveryStatusDelivered {
+		t.Errorf("Expected delivered status, got %s", deliveries[0].Status)
+	}
+	if deliveries[0].ResponseCode != http.StatusNoContent {
+		t.Errorf("Expected response code 204, got %d", deliveries[0].ResponseCode)
+	}
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher := newTestDispatcher(3)
+	dispatcher.Register(server.URL, "secret", nil)
+
+	dispatcher.Dispatch(EventTransactionReversed, map[string]string{"transaction_id": "abc"})
+	dispatcher.Wait()
+
+	deliveries := dispatcher.Deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+	}
+	if deliveries[0].Status != DeliveryStatusDelivered {
+		t.Errorf("Expected delivered status, got %s", deliveries[0].Status)
+	}
+	if deliveries[0].Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", deliveries[0].Attempts)
+	}
+}
+
+func TestDispatcherRecordsFailureAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	dispatcher := newTestDispatcher(2)
+	dispatcher.Register(server.URL, "secret", nil)
+
+	dispatcher.Dispatch(EventTokenUnfrozen, nil)
+	dispatcher.Wait()
+
+	deliveries := dispatcher.Deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+	}
+	if deliveries[0].Status != DeliveryStatusFailed {
+		t.Errorf("Expected failed status, got %s", deliveries[0].Status)
+	}
+	if deliveries[0].Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", deliveries[0].Attempts)
+	}
+	if deliveries[0].LastError == "" {
+		t.Error("Expected last error to be recorded")
+	}
+}
+
+func TestDispatcherEventFilter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher := newTestDispatcher(0)
+	dispatcher.Register(server.URL, "secret", []string{EventTransactionReversed})
+
+	dispatcher.Dispatch(EventTokenFrozen, nil)
+	dispatcher.Wait()
+
+	if calls != 0 {
+		t.Errorf("Expected no deliveries for unsubscribed event, got %d", calls)
+	}
+	if len(dispatcher.Deliveries()) != 0 {
+		t.Errorf("Expected no delivery records, got %d", len(dispatcher.Deliveries()))
+	}
+}
+
+func TestDispatcherRegisterValidation(t *testing.T) {
+	dispatcher := newTestDispatcher(0)
+
+	if _, err := dispatcher.Register("ftp://example.com/hook", "secret", nil); err == nil {
+		t.Error("Expected error for non-HTTP URL")
+	}
+	if _, err := dispatcher.Register("https://example.com/hook", "", nil); err == nil {
+		t.Error("Expected error for missing secret")
+	}
+}
+
+func TestDispatcherRejectsInternalTargets(t *testing.T) {
+	dispatcher := NewDispatcher(config.WebhookConfig{Timeout: time.Second})
+	dispatcher.lookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "hooks.example.com":
+			return []net.IP{net.ParseIP("203.0.113.10")}, nil
+		case "internal.example.com":
+			return []net.IP{net.ParseIP("203.0.113.10"), net.ParseIP("10.1.2.3")}, nil
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IP{ip}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host}
+	}
+
+	rejected := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.20/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://internal.example.com/hook",
+		"https://unknown.example.com/hook",
+	}
+	for _, target := range rejected {
+		if _, err := dispatcher.Register(target, "secret", nil); err == nil {
+			t.Errorf("Expected %s to be rejected", target)
+		}
+	}
+
+	if _, err := dispatcher.Register("https://hooks.example.com/hook", "secret", nil); err != nil {
+		t.Errorf("Expected public host to be accepted, got %v", err)
+	}
+}
+
+func TestDispatcherAllowedHosts(t *testing.T) {
+	dispatcher := NewDispatcher(config.WebhookConfig{
+		Timeout:      time.Second,
+		AllowedHosts: []string{"Hooks.Example.com"},
+	})
+
+	if _, err := dispatcher.Register("https://hooks.example.com/fraud", "secret", nil); err != nil {
+		t.Errorf("Expected allowed host to be accepted, got %v", err)
+	}
+	if _, err := dispatcher.Register("https://other.example.com/fraud", "secret", nil); err == nil {
+		t.Error("Expected host outside the allowlist to be rejected")
+	}
+}
+
+func TestDispatcherRefusesToDialInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// An allowlisted host skips resolution at registration, but delivery still checks the
+	// address it connects to
+	dispatcher := NewDispatcher(config.WebhookConfig{
+		Timeout:      time.Second,
+		AllowedHosts: []string{"127.0.0.1"},
+	})
+	if _, err := dispatcher.Register(server.URL, "secret", nil); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	dispatcher.Dispatch(EventTokenFrozen, map[string]string{"token_id": "abc"})
+	dispatcher.Wait()
+
+	deliveries := dispatcher.Deliveries()
+	if len(deliveries) != 1 || deliveries[0].Status != DeliveryStatusFailed {
+		t.Fatalf("Expected one failed delivery, got %+v", deliveries)
+	}
+}
+
+func TestNilDispatcherDispatch(t *testing.T) {
+	var dispatcher *Dispatcher
+	dispatcher.Dispatch(EventTokenFrozen, nil) // Must not panic
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRequest represents a webhook endpoint registration request
+type RegisterRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret" binding:"required"`
+	EventTypes []string `json:"event_types"`
+}
+
+// RegisterHandler handles POST /api/v1/webhooks. The secret is never echoed back.
+func RegisterHandler(dispatcher *Dispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RegisterRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request format",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		endpoint, err := dispatcher.Register(req.URL, req.Secret, req.EventTypes)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusCreated, endpoint)
+	}
+}
+
+// DeliveriesHandler handles GET /api/v1/webhooks/deliveries
+func DeliveriesHandler(dispatcher *Dispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deliveries := dispatcher.Deliveries()
+		c.JSON(http.StatusOK, gin.H{
+			"deliveries": deliveries,
+			"count":      len(deliveries),
+		})
+	}
+}